@@ -2,23 +2,39 @@ package main
 
 import (
 	"bufio"
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
 	"net/http"
 	"net/url"
 	"os"
+	"os/signal"
+	"real-estate-portal/internal/batch"
 	"real-estate-portal/internal/config"
+	"real-estate-portal/internal/csrf"
 	"real-estate-portal/internal/database"
+	"real-estate-portal/internal/errtext"
+	"real-estate-portal/internal/events"
+	"real-estate-portal/internal/feed"
 	"real-estate-portal/internal/handlers"
+	"real-estate-portal/internal/maintenance"
+	"real-estate-portal/internal/meta"
 	"real-estate-portal/internal/models"
+	"real-estate-portal/internal/queue"
 	"real-estate-portal/internal/ratelimit"
+	"real-estate-portal/internal/redisstore"
 	"real-estate-portal/internal/scheduler"
 	"real-estate-portal/internal/scraper"
 	"real-estate-portal/internal/search"
+	"real-estate-portal/internal/share"
 	"real-estate-portal/internal/snapshot"
+	"real-estate-portal/internal/tracing"
 	"strconv"
 	"strings"
+	"sync"
+	"syscall"
 	"time"
 
 	"github.com/gin-contrib/cors"
@@ -35,19 +51,62 @@ var (
 	appScheduler    *scheduler.Scheduler
 	queueWorker     *scheduler.QueueWorker
 	snapshotService *snapshot.Service
+	viewCounter     *database.ViewCounter
+	shareService    *share.Service
+	queueService    *queue.Service
+
+	// Yahoo's shared list limiter and circuit breaker, passed to every Yahoo scraper
+	yahooLimiter *ratelimit.YahooLimiter
+	yahooBreaker *scraper.CircuitBreaker
 )
 
 func main() {
 	// Load configuration
 	configPath := getEnv("CONFIG_PATH", "/app/config/scraper_config.yaml")
 	var err error
+	// A missing file means defaults; a file that exists but cannot be read or parsed stops
+	// startup instead of silently running with defaults (e.g. without the configured limits)
 	appConfig, err = config.LoadConfig(configPath)
 	if err != nil {
-		log.Printf("Warning: Failed to load config from %s: %v. Using defaults.", configPath, err)
-		appConfig = config.DefaultConfig()
-	} else {
-		log.Printf("Loaded configuration from %s", configPath)
+		log.Fatalf("Failed to load config from %s: %v", configPath, err)
+	}
+	log.Printf("Loaded configuration from %s", configPath)
+	if origins := os.Getenv("CORS_ALLOW_ORIGINS"); origins != "" {
+		appConfig.CORS.AllowOrigins = strings.Split(origins, ",")
+	}
+	if err := appConfig.Validate(); err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
+
+	// Shared limiter windows and caches across replicas (optional)
+	if redisAddr := getEnvOrConfig(appConfig.Redis.Addr, "REDIS_ADDR", ""); redisAddr != "" {
+		store, err := redisstore.New(redisAddr, appConfig.Redis.Password, appConfig.Redis.DB)
+		if err != nil {
+			log.Printf("Warning: Redis unavailable (%v). Using in-memory limiters and caches.", err)
+		} else {
+			defer store.Close()
+			ratelimit.SetSharedStore(store)
+			database.SetSharedListingCache(store)
+			log.Printf("Using Redis at %s for shared limiter windows and listing cache", redisAddr)
+		}
+	}
+
+	// Apply per-source limiter overrides before any scraping starts
+	for _, name := range []string{"yahoo", "suumo"} {
+		if source := appConfig.ResolveSource(name); source.Configured {
+			scraper.ConfigureSourceLimits(name, source.BaseDelay, source.Jitter, source.DetailPerHour)
+		}
 	}
+	yahooLimiter, yahooBreaker = scraper.SharedLimits("yahoo", scraper.YahooHost)
+	for _, source := range createSources().Sources() {
+		source.Limiter().SetPreventiveCooldown(appConfig.Scraper.PreventiveCooldown.Policy())
+	}
+	scraper.ConfigureAliveLimit(appConfig.Scraper.AliveCheck.PerHour)
+	if parse := appConfig.Scraper.Parse; parse != (config.ParseConfig{}) {
+		scraper.ConfigureParseBudget(parse.MaxConcurrent, parse.MaxDocumentBytes, parse.MaxDocumentNodes)
+	}
+	errtext.SetMaxLength(appConfig.ErrorHandling.MaxErrorLength)
+	scraper.SetDebugLogging(strings.EqualFold(appConfig.Logging.Level, "debug"))
 
 	// Initialize database based on configuration
 	dbType := appConfig.Database.Type
@@ -109,6 +168,19 @@ func main() {
 		}
 	}
 
+	// Read-only maintenance mode: restore the persisted flag; config can force it on
+	if gormDB != nil {
+		if err := maintenance.Load(gormDB.DB()); err != nil {
+			log.Printf("Warning: %v", err)
+		}
+	}
+	if appConfig.Maintenance.ReadOnly {
+		maintenance.Override(appConfig.Maintenance.Message)
+	}
+	if state := maintenance.Current(); state.ReadOnly {
+		log.Printf("⚠️  Read-only maintenance mode is ON (writes return 503): %s", state.Message)
+	}
+
 	// Initialize Meilisearch using config
 	meilisearchHost := appConfig.Search.Meilisearch.Host
 	if meilisearchHost == "" {
@@ -143,58 +215,127 @@ func main() {
 	)
 
 	// Initialize snapshot service (MySQL only)
+	snapshot.SetInterestPolicy(appConfig.Snapshot.InterestPolicy())
 	if gormDB != nil {
 		sqlDB, _ := gormDB.GetDB()
 		snapshotService = snapshot.NewService(sqlDB)
 		log.Println("Snapshot service initialized")
+
+		// Detail-page views feed interest-weighted snapshotting
+		viewCounter = database.NewViewCounter(gormDB)
+		viewCounter.Start(appConfig.Snapshot.ViewFlushInterval())
+		defer viewCounter.Stop()
+
+		// Single enqueue path (upsert on active_key) for list pages
+		queueService = queue.NewService(sqlDB)
+		queueService.SetPriorityAging(appConfig.QueueWorker.PriorityAging())
+
+		// Share links for filtered result sets; expired links are purged daily
+		shareService = share.NewService(sqlDB)
+		shareService.StartPurge(24 * time.Hour)
+		defer shareService.Stop()
 	}
 
 	// Initialize and start scheduler (MySQL only)
 	if gormDB != nil {
 		sqlDB, _ := gormDB.GetDB()
-		appScheduler = scheduler.NewScheduler(sqlDB, appConfig)
+
+		// A WAF block persisted before the restart keeps Yahoo's breaker open until it ends
+		scheduler.RestoreBreakerState(sqlDB, yahooBreaker)
+
+		appScheduler = scheduler.NewSchedulerWithSources(sqlDB, appConfig, createSources())
 		if err := appScheduler.Start(); err != nil {
 			log.Printf("Warning: Failed to start scheduler: %v", err)
 		}
 		defer appScheduler.Stop()
 
 		// Initialize and start queue worker
-		queueWorker = scheduler.NewQueueWorker(sqlDB)
-		queueWorker.Start()
+		yahoo := createScraper()
+		queueWorker = scheduler.NewQueueWorkerWithConfig(sqlDB, yahoo, scraper.NewRegistry(yahoo, createSuumoSource()), appConfig.QueueWorker)
+		queueWorker.SetMinRefetchInterval(appConfig.Scraper.MinRefetchInterval())
+		queueWorker.SetPropertySaver(gormDB)
+		if adaptive := appConfig.Scraper.AdaptiveLimiter; adaptive.Enabled {
+			// Yahoo detail pages follow the time of day and slow down on failures
+			limiter := ratelimit.NewAdaptiveDetailLimiter(adaptive.Rates(), adaptive.Adaptive())
+			limiter.SetPreventiveCooldown(appConfig.Scraper.PreventiveCooldown.Policy())
+			queueWorker.SetAdaptiveLimiter("yahoo", limiter)
+			log.Printf("Queue worker: adaptive Yahoo detail limiter (%+v)", limiter.Status())
+		}
+		if err := queueWorker.Start(); err != nil {
+			log.Printf("Warning: Failed to start queue worker: %v", err)
+		}
+		// Stopped on shutdown unless stopped already through /api/admin/worker/stop
 		defer queueWorker.Stop()
 		log.Println("Queue worker started")
 	}
 
+	// Optional OpenTelemetry tracing (no-op tracer unless otel.endpoint is set)
+	otelEndpoint := getEnvOrConfig(appConfig.OTel.Endpoint, "OTEL_ENDPOINT", "")
+	shutdownTracing, err := tracing.Init(otelEndpoint, appConfig.OTel.ServiceName)
+	if err != nil {
+		log.Printf("Warning: Failed to initialize tracing: %v", err)
+	}
+	defer shutdownTracing(context.Background())
+
 	// Setup Gin router
 	r := gin.Default()
-
-	// CORS configuration
-	r.Use(cors.New(cors.Config{
-		AllowOrigins:     []string{"http://localhost:5176"},
-		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE"},
-		AllowHeaders:     []string{"Origin", "Content-Type"},
-		AllowCredentials: true,
-	}))
+	if otelEndpoint != "" {
+		r.Use(tracing.Middleware())
+	}
+
+	// CORS configuration (cors.allow_origins). Mutating requests are additionally checked by
+	// the csrf middleware: a foreign Origin, or a browser request without X-Requested-With /
+	// X-API-Key / X-Client-Token, gets 403 (see internal/csrf)
+	corsOrigins := appConfig.CORS.Origins()
+	corsConfig := cors.Config{
+		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE"},
+		AllowHeaders:     append([]string{"Origin", "Content-Type"}, csrf.CustomHeaders...),
+		AllowCredentials: appConfig.CORS.Credentials(),
+	}
+	switch {
+	case len(corsOrigins) == 1 && corsOrigins[0] == "*":
+		corsConfig.AllowAllOrigins = true
+		r.Use(cors.New(corsConfig))
+	case len(corsOrigins) > 0:
+		corsConfig.AllowOrigins = corsOrigins
+		r.Use(cors.New(corsConfig))
+	default:
+		log.Println("CORS: no allowed origins, cross-origin browser requests are refused")
+	}
+	r.Use(csrf.Middleware(corsOrigins))
+
+	// Read-only maintenance mode refuses every write except read-only POST searches
+	// and the toggle itself
+	r.Use(maintenance.Middleware("/api/search/advanced", "/api/admin/maintenance/read-only"))
 
 	// Routes
 	r.GET("/health", healthCheck)
+	r.GET("/health/ready", readinessCheck)
 	r.GET("/api/properties", getProperties)
 	r.GET("/api/properties/:id", getProperty)
 
-	// Scraping routes with rate limiting
-	r.POST("/api/scrape", rateLimitMiddleware(), scrapeURL)
-	r.POST("/api/scrape/batch", rateLimitMiddleware(), scrapeBatch)
-	r.POST("/api/scrape/list", rateLimitMiddleware(), scrapeListPage)
-	r.POST("/api/scrape/update", rateLimitMiddleware(), scrapeAndUpdate)
+	registerScrapeRoutes(r)
 
 	// Rate limiter stats endpoint
 	r.GET("/api/ratelimit/stats", getRateLimitStats)
 
+	// Listing cache stats endpoint
+	r.GET("/api/cache/stats", getListingCacheStats)
+
 	// Scheduler and snapshot endpoints
 	r.POST("/api/scheduler/run", triggerScheduledScraping)
 	r.GET("/api/properties/:id/history", getPropertyHistory)
+	r.GET("/api/properties/:id/diff", getPropertyDiff)
+
+	// Anonymous favorite counter (interest signal for snapshot retention)
+	r.POST("/api/properties/:id/favorite", favoriteProperty)
+	r.DELETE("/api/properties/:id/favorite", unfavoriteProperty)
 	r.GET("/api/changes/recent", getRecentChanges)
 
+	// Atom feeds (new listings / changes) for feed readers
+	r.GET("/api/feeds/changes.atom", getChangesFeed)
+	r.GET("/api/feeds/new-listings.atom", getNewListingsFeed)
+
 	// Queue worker stats endpoint
 	r.GET("/api/queue/stats", getQueueStats)
 
@@ -204,6 +345,15 @@ func main() {
 	r.POST("/api/search/reindex", reindexAllProperties)
 	r.GET("/api/filter", filterProperties)
 
+	// Time-limited share links for /api/filter result sets
+	r.POST("/api/share", rateLimitMiddleware(), createShare)
+	r.GET("/api/share/:token", getShare)
+	r.GET("/api/lines", getLines)
+	r.GET("/api/stats/walk-buckets", getWalkBucketStats)
+
+	// Canonical option lists (floor plans, sort keys, change types...) for the frontend
+	r.GET("/api/meta/options", getMetaOptions)
+
 	// Admin API routes (requires authentication in production)
 	if gormDB != nil {
 		sqlDB, _ := gormDB.GetDB()
@@ -220,23 +370,90 @@ func main() {
 			// Scraping control
 			admin.POST("/scraping/trigger", adminHandler.TriggerScraping)
 			admin.GET("/scraping/status", adminHandler.GetScrapingStatus)
+			admin.GET("/scraping/state", adminHandler.GetScrapingState)
+			admin.GET("/scraping/robots", getRobotsRules)
+			admin.GET("/scraping/breakers", getCircuitBreakers)
+			admin.POST("/scraping/breakers/reset", resetCircuitBreakers)
 
 			// Cleanup operations
 			admin.POST("/cleanup/run", adminHandler.RunCleanup)
 			admin.GET("/cleanup/logs", adminHandler.GetDeleteLogs)
 
+			// Maintenance
+			admin.POST("/maintenance/read-only", adminHandler.SetReadOnly)
+
+			// Queue worker control
+			admin.POST("/worker/start", startQueueWorker)
+			admin.POST("/worker/stop", stopQueueWorker)
+			admin.POST("/worker/pause", pauseQueueWorker)
+			admin.POST("/worker/resume", resumeQueueWorker)
+			admin.GET("/events", adminHandler.StreamEvents)
+			admin.GET("/worker/status", getQueueStats)
+
+			// Queue inspection and bulk operations
+			admin.GET("/queue", listQueueItems)
+			admin.GET("/queue/:id", getQueueItem)
+			admin.POST("/queue/:id/retry", retryQueueItem)
+			admin.POST("/queue/:id/cancel", cancelQueueItem)
+			admin.POST("/queue/reprioritize", adminHandler.ReprioritizeQueue)
+			admin.GET("/queue/dead", adminHandler.GetDeadQueueItems)
+			admin.POST("/queue/requeue", adminHandler.RequeueDeadItems)
+
+			// Data repair
+			admin.POST("/backfill/list-fields", repairListFields)
+
+			// Manual corrections (locked against re-scrapes)
+			admin.PATCH("/properties/:id", correctProperty)
+			admin.GET("/properties/:id/corrections", getPropertyCorrections)
+			admin.DELETE("/properties/:id/corrections/:field", unlockPropertyCorrection)
+
 			// Property history
 			admin.GET("/properties/:id/history", adminHandler.GetPropertyHistory)
 			admin.GET("/changes/recent", adminHandler.GetRecentChanges)
+
+			// Support lookup across active/removed properties and delete logs
+			admin.GET("/lookup", adminHandler.Lookup)
+
+			// Reports
+			admin.GET("/reports/snapshot-gaps", adminHandler.GetSnapshotGaps)
 		}
 
 		log.Println("Admin API routes registered at /api/admin/*")
+
+		// Dev-only seed data (refuses to run unless dev.seed_enabled)
+		devHandler := handlers.NewDevHandler(gormDB, searchClient, appConfig.Dev.SeedEnabled)
+		r.POST("/api/dev/seed", devHandler.Seed)
+		r.POST("/api/dev/reset", devHandler.Reset)
+		if appConfig.Dev.SeedEnabled {
+			log.Println("⚠️  Dev seed endpoints enabled at /api/dev/*")
+		}
 	}
 
 	port := getEnv("PORT", "8084")
 	log.Printf("Server starting on port %s", port)
-	if err := r.Run(":" + port); err != nil {
-		log.Fatalf("Failed to start server: %v", err)
+
+	// SIGINT/SIGTERM shut down gracefully: in-flight requests finish, then the deferred Stops
+	// run (the queue worker finishes or returns its item to pending)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	server := &http.Server{Addr: ":" + port, Handler: r}
+	// Open event streams end on shutdown instead of holding it for the whole timeout
+	server.RegisterOnShutdown(events.Default.CloseAll)
+	serverErr := make(chan error, 1)
+	go func() { serverErr <- server.ListenAndServe() }()
+
+	select {
+	case err := <-serverErr:
+		if !errors.Is(err, http.ErrServerClosed) {
+			log.Fatalf("Failed to start server: %v", err)
+		}
+	case <-ctx.Done():
+		log.Println("Shutting down...")
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			log.Printf("Warning: HTTP server shutdown: %v", err)
+		}
 	}
 }
 
@@ -247,7 +464,34 @@ func healthCheck(c *gin.Context) {
 	})
 }
 
-func getProperties(c *gin.Context) {
+// readinessCheck reports whether the API can serve traffic. Read-only maintenance mode
+// still counts as ready (reads are served) but is reported so callers can tell.
+func readinessCheck(c *gin.Context) {
+	status := http.StatusOK
+	dbStatus := "ok"
+	if gormDB != nil {
+		sqlDB, err := gormDB.DB().DB()
+		if err == nil {
+			err = sqlDB.PingContext(c.Request.Context())
+		}
+		if err != nil {
+			status = http.StatusServiceUnavailable
+			dbStatus = "unreachable"
+		}
+	}
+
+	state := maintenance.Current()
+	c.JSON(status, gin.H{
+		"ready":       status == http.StatusOK,
+		"database":    dbStatus,
+		"read_only":   state.ReadOnly,
+		"maintenance": state,
+		"time":        time.Now(),
+	})
+}
+
+// parsePropertyFilters builds listing filters from query parameters (shared by the list API and feeds)
+func parsePropertyFilters(c *gin.Context) database.PropertyFilters {
 	// Build filters from query parameters
 	filters := database.PropertyFilters{
 		Station:  c.Query("station"),
@@ -312,6 +556,18 @@ func getProperties(c *gin.Context) {
 		}
 	}
 
+	// Building floors range (e.g. min_building_floors=20 for tower buildings)
+	if minStr := c.Query("min_building_floors"); minStr != "" {
+		if minFloors, parseErr := strconv.Atoi(minStr); parseErr == nil {
+			filters.MinBuildingFloors = &minFloors
+		}
+	}
+	if maxStr := c.Query("max_building_floors"); maxStr != "" {
+		if maxFloors, parseErr := strconv.Atoi(maxStr); parseErr == nil {
+			filters.MaxBuildingFloors = &maxFloors
+		}
+	}
+
 	// Multi-select filters (comma-separated)
 	if floorPlansStr := c.Query("floor_plans"); floorPlansStr != "" {
 		filters.FloorPlans = strings.Split(floorPlansStr, ",")
@@ -344,6 +600,12 @@ func getProperties(c *gin.Context) {
 		filters.Cursor = cursor
 	}
 
+	return filters
+}
+
+func getProperties(c *gin.Context) {
+	filters := parsePropertyFilters(c)
+
 	// Always use paginated endpoint with GORM
 	if gormDB != nil {
 		start := time.Now()
@@ -359,7 +621,12 @@ func getProperties(c *gin.Context) {
 		log.Printf("[Search API] duration_ms=%d total=%d limit=%d has_cursor=%v sort=%s",
 			duration.Milliseconds(), result.Total, result.Limit, filters.Cursor != "", filters.SortBy)
 
-		c.JSON(http.StatusOK, result)
+		// The result may be shared with the listing cache; decorate a copy
+		page := *result
+		page.Properties = append([]models.Property(nil), result.Properties...)
+		attachFreshness(page.Properties)
+
+		c.JSON(http.StatusOK, page)
 		return
 	}
 
@@ -396,6 +663,14 @@ func getProperty(c *gin.Context) {
 		images, _ = gormDB.GetPropertyImages(id)
 	}
 
+	if viewCounter != nil {
+		viewCounter.Record(property.ID)
+	}
+
+	props := []models.Property{*property}
+	attachFreshness(props)
+	property = &props[0]
+
 	// Create response with stations and images
 	response := gin.H{
 		"property": property,
@@ -403,26 +678,119 @@ func getProperty(c *gin.Context) {
 		"images":   images,
 	}
 
+	// Relist linkage: days on market continues across delist/relist
+	if gormDB != nil {
+		if firstSeen, err := gormDB.GetListingFirstSeen(property); err == nil {
+			response["relisted_from"] = property.RelistedFrom
+			response["first_listed_at"] = firstSeen
+			response["days_on_market"] = int(time.Since(firstSeen).Hours() / 24)
+		}
+	}
+
+	// When the listing will be refreshed; internal callers (X-API-Key) see the queue details
+	if queueService != nil {
+		refresh, err := queueService.RefreshStatus(property.Source, property.SourcePropertyID, scraper.DetailLimiter.Status())
+		if err != nil {
+			log.Printf("Warning: Failed to get refresh status for %s: %v", property.ID, err)
+		} else if _, internal := appConfig.FindAPIKey(c.GetHeader("X-API-Key")); internal {
+			response["refresh_status"] = refresh
+		} else {
+			response["refresh_status"] = refresh.Public()
+		}
+	}
+
 	c.JSON(http.StatusOK, response)
 }
 
 // createScraper creates a new scraper instance with configuration
 func createScraper() *scraper.Scraper {
 	if appConfig == nil {
-		return scraper.NewScraper()
+		return scraper.NewScraperWithLimiter(scraper.DefaultScraperConfig(), yahooLimiter, yahooBreaker)
+	}
+
+	cfg := scraper.ScraperConfig{
+		Timeout:       appConfig.Scraper.GetTimeout(),
+		MaxRetries:    appConfig.Scraper.MaxRetries,
+		RetryDelay:    appConfig.Scraper.GetRetryDelay(),
+		RequestDelay:  appConfig.Scraper.GetRequestDelay(),
+		UserAgents:    appConfig.UserAgentPool(),
+		RespectRobots: appConfig.Scraper.RespectRobots,
+		VerifyImages:  appConfig.Scraper.VerifyImages,
+		SessionWarmup: appConfig.Scraper.SessionWarmup,
+		CookieFile:    appConfig.Scraper.SessionCookieFile,
+
+		AttemptTimeout: appConfig.Scraper.GetAttemptTimeout(),
+		MaxTotal:       appConfig.Scraper.GetMaxTotal(),
+		MaxRetryAfter:  appConfig.Scraper.GetMaxRetryAfter(),
+
+		DebugHTMLDir:       appConfig.Scraper.DebugHTML.Dir,
+		DebugHTMLMaxBytes:  appConfig.Scraper.DebugHTML.MaxFileBytes,
+		DebugHTMLRetention: time.Duration(appConfig.Scraper.DebugHTML.RetentionDays) * 24 * time.Hour,
+		DebugHTMLMaxFiles:  appConfig.Scraper.DebugHTML.MaxFiles,
+	}
+
+	// Field selectors are written against Yahoo's markup (SUUMO has its own parser)
+	cfg.Selectors = appConfig.Scraper.Selectors
+
+	// Per-source header profile (only when a sources.yahoo block is configured)
+	source := appConfig.ResolveSource("yahoo")
+	cfg.Proxies = source.Proxies
+	if source.Configured {
+		cfg.Profile = &scraper.HeaderProfile{
+			UserAgents: source.UserAgents,
+			Headers:    source.Headers,
+		}
 	}
 
-	return scraper.NewScraperWithConfig(scraper.ScraperConfig{
-		Timeout:      appConfig.Scraper.GetTimeout(),
-		MaxRetries:   appConfig.Scraper.MaxRetries,
-		RetryDelay:   appConfig.Scraper.GetRetryDelay(),
-		RequestDelay: appConfig.Scraper.GetRequestDelay(),
-	})
+	return scraper.NewScraperWithLimiter(cfg, yahooLimiter, yahooBreaker)
+}
+
+// createSuumoSource creates the SUUMO source with the global scraper settings and the
+// sources.suumo header profile
+func createSuumoSource() *scraper.SuumoSource {
+	if appConfig == nil {
+		return scraper.NewSuumoSource(scraper.ScraperConfig{Timeout: 30 * time.Second, MaxRetries: 3, RetryDelay: 2 * time.Second, MaxTotal: 2 * time.Minute, RespectRobots: true})
+	}
+
+	cfg := scraper.ScraperConfig{
+		Timeout:       appConfig.Scraper.GetTimeout(),
+		MaxRetries:    appConfig.Scraper.MaxRetries,
+		RetryDelay:    appConfig.Scraper.GetRetryDelay(),
+		UserAgents:    appConfig.UserAgentPool(),
+		RespectRobots: appConfig.Scraper.RespectRobots,
+		VerifyImages:  appConfig.Scraper.VerifyImages,
+		CookieFile:    appConfig.Scraper.SessionCookieFile,
+
+		AttemptTimeout: appConfig.Scraper.GetAttemptTimeout(),
+		MaxTotal:       appConfig.Scraper.GetMaxTotal(),
+		MaxRetryAfter:  appConfig.Scraper.GetMaxRetryAfter(),
+
+		DebugHTMLDir:       appConfig.Scraper.DebugHTML.Dir,
+		DebugHTMLMaxBytes:  appConfig.Scraper.DebugHTML.MaxFileBytes,
+		DebugHTMLRetention: time.Duration(appConfig.Scraper.DebugHTML.RetentionDays) * 24 * time.Hour,
+		DebugHTMLMaxFiles:  appConfig.Scraper.DebugHTML.MaxFiles,
+	}
+	source := appConfig.ResolveSource("suumo")
+	cfg.Proxies = source.Proxies
+	if source.Configured {
+		cfg.Profile = &scraper.HeaderProfile{
+			UserAgents: source.UserAgents,
+			Headers:    source.Headers,
+		}
+	}
+	return scraper.NewSuumoSource(cfg)
+}
+
+// createSources registers every supported site by host. Sources keep per-scrape state
+// (stations/images), so each request builds its own; limiters are shared per site.
+func createSources() *scraper.Registry {
+	return scraper.NewRegistry(createScraper(), createSuumoSource())
 }
 
 func scrapeURL(c *gin.Context) {
 	var req struct {
-		URL string `json:"url" binding:"required"`
+		URL   string `json:"url" binding:"required"`
+		Force bool   `json:"force"` // scrape even if fetched within min_refetch_interval
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -430,23 +798,69 @@ func scrapeURL(c *gin.Context) {
 		return
 	}
 
-	// Apply DetailLimiter for single property scraping (5 per hour max)
-	scraper.DetailLimiter.Acquire("single")
+	ctx := c.Request.Context()
+
+	// The site the URL belongs to (unknown hosts are a 400 before any limiter wait)
+	source, err := createSources().ForURL(req.URL)
+	if err != nil {
+		respondScrapeError(c, "", err)
+		return
+	}
+
+	// Fetched moments ago (e.g. by the queue worker): answer with the stored listing, no request
+	if !req.Force {
+		if fresh := freshProperty(source, req.URL); fresh != nil {
+			log.Printf("[scrape] property_id=%s fetched %s ago - skipped_fresh", fresh.ID, time.Since(fresh.FetchedAt).Round(time.Second))
+			c.JSON(http.StatusOK, withResponseField(fresh, "skipped_fresh", true))
+			return
+		}
+	}
+
+	// Each site has its own breaker: fail fast while this one is open
+	if bs, ok := source.(scraper.BreakerSource); ok {
+		if isOpen, retryAt := bs.CircuitBreaker().State(); isOpen {
+			respondBreakerOpen(c, blockReasonBreakerOpen, retryAt)
+			return
+		}
+	}
+
+	// Apply the source's DetailLimiter for single property scraping (N per hour max)
+	// (a client that disconnects while waiting releases the handler instead of holding it for up to an hour)
+	_, waitSpan := tracing.Start(ctx, "ratelimit.DetailLimiter.Acquire")
+	waitStart := time.Now()
+	err = source.Limiter().AcquireContext(ctx, "single")
+	detailWait := time.Since(waitStart)
+	waitSpan.End()
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": fmt.Sprintf("canceled while waiting for the detail rate limit: %v", err)})
+		return
+	}
 
 	// Scrape the property
-	s := createScraper()
-	property, err := s.ScrapeProperty(req.URL)
+	result, err := source.ScrapeDetail(ctx, req.URL, scraper.Validators{})
+	var building *scraper.BuildingPageError
+	if errors.As(err, &building) {
+		respondBuildingPage(c, source, building, result.Stats)
+		return
+	}
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondScrapeError(c, "", err)
 		return
 	}
+	property, stations, images := result.Property, result.Stations, result.Images
+	stats := result.Stats
+	stats.LimiterWait += detailWait
+	log.Printf("[scrape] property_id=%s %s", property.ID, stats)
 
 	// Save to database with stations and images (if using GORM)
 	if gormDB != nil {
-		// Get stations and images from scraper and convert to models
-		stations := s.GetLastStationsAsModels(property.ID)
-		images := s.GetLastImagesAsModels(property.ID)
-		err = gormDB.SavePropertyWithStationsAndImages(property, stations, images)
+		// A redirect to a re-published listing moves the stored row first, so the save updates it
+		if redirect := result.Redirect; redirect != nil {
+			if err := gormDB.RecordPropertyRedirect(ctx, property.Source, redirect.FromID, redirect.ToID, redirect.ToURL); err != nil {
+				log.Printf("Warning: Failed to record redirect %s -> %s: %v", redirect.FromID, redirect.ToID, err)
+			}
+		}
+		err = gormDB.SavePropertyWithStationsAndImagesContext(ctx, property, stations, images)
 
 		// Log station and image save operation
 		if err == nil {
@@ -469,17 +883,94 @@ func scrapeURL(c *gin.Context) {
 		return
 	}
 
-	// Index in Meilisearch
-	if err := searchClient.IndexProperty(property); err != nil {
+	// Index in Meilisearch (with line names for the lines filter)
+	for _, st := range stations {
+		if st.LineName != "" {
+			property.Lines = append(property.Lines, st.LineName)
+		}
+	}
+	if err := searchClient.IndexPropertyContext(ctx, property); err != nil {
 		log.Printf("Warning: Failed to index property: %v", err)
 	}
 
-	c.JSON(http.StatusOK, property)
+	// The property's fields plus scrape_stats (requests, retries, statuses, waits)
+	c.JSON(http.StatusOK, withScrapeStats(property, stats))
+}
+
+// respondBuildingPage answers a scrape of a building page: nothing is saved for the building,
+// its units are queued like list page entries (with the building page as Referer) and the
+// response reports how many units the URL expanded into
+func respondBuildingPage(c *gin.Context, source scraper.PropertySource, building *scraper.BuildingPageError, stats scraper.ScrapeStats) {
+	results := make([]batch.Result, 0, len(building.Units))
+	for _, unit := range building.Units {
+		results = append(results, enqueueListURL(source, unit.DetailURL, building.URL))
+	}
+	log.Printf("[scrape] %s is a building page: expanded into %d units", building.URL, len(building.Units))
+
+	c.JSON(http.StatusOK, gin.H{
+		"building":     true,
+		"building_url": building.URL,
+		"units":        len(building.Units),
+		"results":      results,
+		"scrape_stats": stats,
+	})
+}
+
+// withScrapeStats renders property (through its own MarshalJSON) with a scrape_stats field added
+func withScrapeStats(property *models.Property, stats scraper.ScrapeStats) interface{} {
+	return withResponseField(property, "scrape_stats", stats)
+}
+
+// withResponseField renders property (through its own MarshalJSON) with one extra field added
+func withResponseField(property *models.Property, name string, value interface{}) interface{} {
+	fields := map[string]json.RawMessage{}
+	data, err := json.Marshal(property)
+	if err == nil {
+		err = json.Unmarshal(data, &fields)
+	}
+	if err != nil {
+		log.Printf("Warning: Failed to add %s to the response: %v", name, err)
+		return property
+	}
+	if fields[name], err = json.Marshal(value); err != nil {
+		return property
+	}
+	return fields
+}
+
+// freshProperty returns the active listing stored for url when it was fetched within
+// scraper.min_refetch_interval_minutes, so a re-scrape can be skipped; nil otherwise
+func freshProperty(source scraper.PropertySource, url string) *models.Property {
+	interval := appConfig.Scraper.MinRefetchInterval()
+	if gormDB == nil || interval <= 0 {
+		return nil
+	}
+	sourcePropertyID, err := source.SourcePropertyID(normalizeURLForCheck(url))
+	if err != nil {
+		return nil
+	}
+	var stored []models.Property
+	if err := gormDB.DB().
+		Where("source = ? AND source_property_id = ? AND status = ?", source.Name(), sourcePropertyID, models.PropertyStatusActive).
+		Where("fetched_at > ?", time.Now().Add(-interval)).
+		Limit(1).Find(&stored).Error; err != nil || len(stored) == 0 {
+		return nil
+	}
+	return &stored[0]
+}
+
+// lastScrapeStats returns the stats of source's last list call (zero if it keeps none)
+func lastScrapeStats(source scraper.PropertySource) scraper.ScrapeStats {
+	if st, ok := source.(scraper.StatsSource); ok {
+		return st.LastStats()
+	}
+	return scraper.ScrapeStats{}
 }
 
 func scrapeBatch(c *gin.Context) {
 	var req struct {
-		URLs []string `json:"urls" binding:"required"`
+		URLs        []string `json:"urls" binding:"required"`
+		Concurrency int      `json:"concurrency"` // Optional: parallel scrapers (default: 1, max: 5)
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -487,46 +978,73 @@ func scrapeBatch(c *gin.Context) {
 		return
 	}
 
-	s := createScraper()
-	var properties []models.Property
-	var errors []string
+	if req.Concurrency <= 0 {
+		req.Concurrency = 1
+	}
+	if req.Concurrency > 5 {
+		req.Concurrency = 5
+	}
 
-	for _, url := range req.URLs {
+	// Results and properties are collected by input index so the response follows request order
+	scraped := make([]*models.Property, len(req.URLs))
+	var statsMu sync.Mutex
+	var stats scraper.ScrapeStats
+	start := time.Now()
+	results := batch.Run(len(req.URLs), req.Concurrency, func(i int) batch.Result {
+		url := req.URLs[i]
+
+		// Scrapers keep per-scrape state (stations/images), so each call gets its own
+		s := createScraper()
 		property, err := s.ScrapeProperty(url)
+		statsMu.Lock()
+		stats.Add(s.LastStats())
+		statsMu.Unlock()
 		if err != nil {
-			errors = append(errors, fmt.Sprintf("%s: %v", url, err))
-			continue
+			return batch.Failed(url, err)
 		}
 
 		if gormDB != nil {
-			err = gormDB.SaveProperty(property)
+			err = gormDB.SavePropertyWithStationsAndImages(property,
+				s.GetLastStationsAsModels(property.ID), s.GetLastImagesAsModels(property.ID))
 		} else {
 			err = db.SaveProperty(property)
 		}
-
 		if err != nil {
-			errors = append(errors, fmt.Sprintf("%s: %v", url, err))
-			continue
+			return batch.Failed(url, err)
 		}
 
-		properties = append(properties, *property)
+		scraped[i] = property
 
 		// Small delay to be respectful
 		time.Sleep(1 * time.Second)
-	}
+		statsMu.Lock()
+		stats.PacingWait += time.Second
+		statsMu.Unlock()
+		return batch.OK(url, property.ID)
+	})
+	stats.WallTime = time.Since(start)
+	log.Printf("[scrape/batch] urls=%d %s", len(req.URLs), stats)
 
 	// Index all properties
+	var properties []models.Property
+	for _, p := range scraped {
+		if p != nil {
+			properties = append(properties, *p)
+		}
+	}
 	if len(properties) > 0 {
 		if err := searchClient.IndexProperties(properties); err != nil {
 			log.Printf("Warning: Failed to index properties: %v", err)
 		}
 	}
 
+	summary := batch.Summarize(results)
 	c.JSON(http.StatusOK, gin.H{
-		"success": len(properties),
-		"failed":  len(errors),
-		"errors":  errors,
-		"properties": properties,
+		"total":        summary.Total,
+		"success":      summary.OK,
+		"failed":       summary.Failed,
+		"results":      results,
+		"scrape_stats": stats,
 	})
 }
 
@@ -535,6 +1053,7 @@ func scrapeListPage(c *gin.Context) {
 		URL         string `json:"url" binding:"required"`
 		Limit       int    `json:"limit"`       // Optional: max number of properties to scrape
 		Concurrency int    `json:"concurrency"` // Optional: number of concurrent scrapers (default: 5)
+		MaxPages    int    `json:"max_pages"`   // Optional: list pages to follow via pagination (default: 1)
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -546,121 +1065,84 @@ func scrapeListPage(c *gin.Context) {
 	if req.Limit == 0 {
 		req.Limit = 20
 	}
+	// Never more URLs than the caller's enqueue quota has left today
+	if left, limited := quotaLeft(c); limited && req.Limit > left {
+		req.Limit = left
+	}
+
+	// Default to the first page only
+	if req.MaxPages == 0 {
+		req.MaxPages = 1
+	}
+	if req.MaxPages < 0 || req.MaxPages > scraper.MaxListPages {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("max_pages must be between 1 and %d", scraper.MaxListPages)})
+		return
+	}
 
 	// Default concurrency to 5
 	if req.Concurrency == 0 {
 		req.Concurrency = 5
 	}
 
-	s := createScraper()
-
-	// Step 1: Extract property URLs from list page
-	log.Printf("Scraping list page: %s", req.URL)
-	propertyURLs, err := s.ScrapeListPage(req.URL)
+	source, err := createSources().ForURL(req.URL)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to scrape list page: %v", err)})
+		respondScrapeError(c, "", err)
 		return
 	}
 
-	log.Printf("Found %d property URLs", len(propertyURLs))
+	// Step 1: Extract property URLs from the list page (and following pages, up to max_pages)
+	log.Printf("Scraping %s list page: %s (max_pages=%d)", source.Name(), req.URL, req.MaxPages)
+	// total_on_site / pages are what the site reports for the whole search (-1 = not parsed)
+	listPage := &scraper.ListPage{TotalOnSite: -1, Pages: -1, PagesVisited: 1}
+	if paged, ok := source.(scraper.PagedSource); ok {
+		listPage, err = paged.ScrapeListPagesWithMetaContext(c.Request.Context(), req.URL, req.MaxPages)
+	} else {
+		listPage.URLs, err = source.ScrapeList(c.Request.Context(), req.URL)
+	}
+	propertyURLs, pagesVisited := listPage.URLs, listPage.PagesVisited
+	found := len(propertyURLs)
+	listStats := lastScrapeStats(source)
+	log.Printf("[scrape/list] pages=%d total_on_site=%d site_pages=%d %s", pagesVisited, listPage.TotalOnSite, listPage.Pages, listStats)
+	if err != nil && len(propertyURLs) == 0 {
+		respondScrapeError(c, "Failed to scrape list page", err)
+		return
+	}
+	// A later page failing keeps the URLs already collected
+	paginationError := ""
+	if err != nil {
+		paginationError = errtext.Clean(err.Error())
+		log.Printf("Pagination stopped early: %v", err)
+	}
+
+	log.Printf("Found %d property URLs across %d page(s)", len(propertyURLs), pagesVisited)
 
 	// Apply limit
 	if len(propertyURLs) > req.Limit {
 		propertyURLs = propertyURLs[:req.Limit]
 	}
 
-	// Step 2: Check which properties already exist (differential scraping)
+	// Step 2: Per URL, refresh last_seen_at for existing properties or add new ones to
+	// the detail_scrape_queue (differential scraping). Results follow list page order.
 	log.Printf("Checking for existing properties...")
-	existingURLs := make(map[string]bool)
-	newURLs := []string{}
-
-	for _, url := range propertyURLs {
-		// Extract Yahoo property ID from URL for efficient lookup
-		normalizedURL := normalizeURLForCheck(url)
-		parts := strings.Split(normalizedURL, "/detail/")
-		if len(parts) == 2 {
-			propertyID := strings.Split(parts[1], "?")[0]
-			propertyID = strings.TrimSuffix(propertyID, "/")
-
-			// Check if property exists with this source_property_id
-			var count int64
-			if gormDB != nil {
-				gormDB.DB().Model(&models.Property{}).Where("source = ? AND source_property_id = ?", "yahoo", propertyID).Count(&count)
-			}
-			if count > 0 {
-				existingURLs[url] = true
-				// Update last_seen_at for existing property
-				if gormDB != nil {
-					gormDB.DB().Model(&models.Property{}).
-						Where("source = ? AND source_property_id = ?", "yahoo", propertyID).
-						Update("last_seen_at", time.Now())
-				}
-			} else {
-				newURLs = append(newURLs, url)
-			}
-		} else {
-			// If URL doesn't match expected format, treat as new
-			newURLs = append(newURLs, url)
-		}
-	}
-
-	log.Printf("Found %d existing properties (last_seen_at updated), %d new properties to scrape", len(existingURLs), len(newURLs))
-
-	// Step 2.5: Add new properties to the detail_scrape_queue
-	if gormDB != nil && len(newURLs) > 0 {
-		for _, url := range newURLs {
-			// Extract property ID from URL
-			normalizedURL := normalizeURLForCheck(url)
-			parts := strings.Split(normalizedURL, "/detail/")
-			if len(parts) == 2 {
-				propertyID := strings.Split(parts[1], "?")[0]
-				propertyID = strings.TrimSuffix(propertyID, "/")
-
-				// Add to queue with proper failed handling
-				var existing models.DetailScrapeQueue
-				err := gormDB.DB().Where("source = ? AND source_property_id = ?", "yahoo", propertyID).
-					First(&existing).Error
-
-				if errors.Is(err, gorm.ErrRecordNotFound) {
-					// No existing record - create new
-					queue := models.DetailScrapeQueue{
-						Source:           "yahoo",
-						SourcePropertyID: propertyID,
-						DetailURL:        normalizedURL,
-						Status:           models.QueueStatusPending,
-						Priority:         0,
-						Attempts:         0,
-					}
-					if createErr := gormDB.DB().Create(&queue).Error; createErr != nil {
-						log.Printf("Warning: Failed to create queue for %s: %v", propertyID, createErr)
-					}
-				} else if err != nil {
-					log.Printf("Warning: Failed to check queue for %s: %v", propertyID, err)
-				} else {
-					// Record exists - handle based on status
-					switch existing.Status {
-					case models.QueueStatusPending, models.QueueStatusProcessing:
-						// Already in queue, do nothing
-					case models.QueueStatusFailed:
-						// Retry failed items by resetting to pending
-						updates := map[string]interface{}{
-							"status":        models.QueueStatusPending,
-							"attempts":      0,
-							"last_error":    "",
-							"next_retry_at": nil,
-						}
-						if updateErr := gormDB.DB().Model(&existing).Updates(updates).Error; updateErr != nil {
-							log.Printf("Warning: Failed to reset failed queue for %s: %v", propertyID, updateErr)
-						}
-					case models.QueueStatusPermanentFail:
-						// Don't retry permanent failures (404, etc)
-					case models.QueueStatusDone:
-						// Already successfully scraped, do nothing
-					}
-				}
-			}
+	results := make([]batch.Result, len(propertyURLs))
+	existingCount, newCount, enqueued := 0, 0, 0
+	for i, url := range propertyURLs {
+		results[i] = enqueueListURL(source, url, listPage.Referer(url, req.URL))
+		switch results[i].Action {
+		case listActionExisting:
+			existingCount++
+		case listActionQueued, listActionRequeued, listActionAlreadyQueued, listActionPermanentFail, listActionAlreadyDone:
+			newCount++
 		}
-		log.Printf("✅ Added %d new properties to detail_scrape_queue", len(newURLs))
+		if results[i].Action == listActionQueued || results[i].Action == listActionRequeued {
+			enqueued++
+		}
+	}
+	consumeEnqueueQuota(c, enqueued)
+
+	log.Printf("Found %d existing properties (last_seen_at updated), %d new properties to scrape", existingCount, newCount)
+	if gormDB != nil && newCount > 0 {
+		log.Printf("✅ Added %d new properties to detail_scrape_queue", newCount)
 	}
 
 	// Step 3: QUEUE-ONLY MODE - Do NOT process immediately
@@ -688,11 +1170,21 @@ func scrapeListPage(c *gin.Context) {
 	// - Scheduler/worker (processes queue with rate limits)
 
 	// Return queue-only response
+	summary := batch.Summarize(results)
 	c.JSON(http.StatusOK, gin.H{
-		"message":         "List page scraped successfully. URLs added to queue.",
-		"urls_found":      len(propertyURLs),
-		"existing":        len(existingURLs),
-		"new_to_queue":    len(newURLs),
+		"message":          "List page scraped successfully. URLs added to queue.",
+		"urls_found":       len(propertyURLs),
+		"found":            found,
+		"total_on_site":    listPage.TotalOnSite,
+		"pages":            listPage.Pages,
+		"pages_visited":    pagesVisited,
+		"pagination_error": paginationError,
+		"existing":         existingCount,
+		"new_to_queue":     newCount,
+		"success":          summary.OK,
+		"failed":           summary.Failed,
+		"results":          results,
+		"scrape_stats":     listStats,
 		"queue_status": gin.H{
 			"pending":    queueStats.Pending,
 			"processing": queueStats.Processing,
@@ -702,6 +1194,50 @@ func scrapeListPage(c *gin.Context) {
 	})
 }
 
+// Per-URL actions reported in scrapeListPage results
+const (
+	listActionExisting      = string(queue.ListedExisting)
+	listActionQueued        = string(queue.ListedQueued)
+	listActionRequeued      = string(queue.ListedRequeued)
+	listActionAlreadyQueued = string(queue.ListedAlreadyQueued)
+	listActionAlreadyDone   = string(queue.ListedAlreadyDone)
+	listActionPermanentFail = string(queue.ListedPermanentFail)
+)
+
+// enqueueListURL handles one URL from source's list page: refresh last_seen_at if the property
+// exists, otherwise add it to the detail_scrape_queue with the list page as its referer
+func enqueueListURL(source scraper.PropertySource, url, listURL string) batch.Result {
+	// Extract the site's property ID from the URL for efficient lookup
+	normalizedURL := normalizeURLForCheck(url)
+	sourcePropertyID, err := source.SourcePropertyID(normalizedURL)
+	if err != nil {
+		return batch.Result{URL: url, Status: batch.StatusError, Error: "could not extract property ID from URL"}
+	}
+	sourceName := source.Name()
+
+	if gormDB == nil {
+		return batch.Result{URL: url, Status: batch.StatusOK, Action: listActionQueued}
+	}
+
+	// Known properties get last_seen_at refreshed; done/permanently failed ones are not queued again
+	outcome, propertyID, err := queueService.EnqueueListed(sourceName, sourcePropertyID, normalizedURL, listURL)
+	if err != nil {
+		log.Printf("Warning: Failed to enqueue %s: %v", sourcePropertyID, err)
+		return batch.Failed(url, err)
+	}
+	switch outcome {
+	case queue.ListedExisting:
+		result := batch.OK(url, propertyID)
+		result.Action = listActionExisting
+		return result
+	case queue.ListedPermanentFail:
+		// Don't retry permanent failures (404, etc)
+		return batch.Result{URL: url, Status: batch.StatusError, Action: listActionPermanentFail, Error: "permanently failed earlier; not retried"}
+	default:
+		return batch.Result{URL: url, Status: batch.StatusOK, Action: string(outcome)}
+	}
+}
+
 // REMOVED: Immediate detail scraping logic
 // All detail scraping moved to queue worker/scheduler only
 
@@ -726,9 +1262,9 @@ func scrapeAndUpdate(c *gin.Context) {
 	s := createScraper()
 
 	// Step 1: Extract property URLs from list page
-	propertyURLs, err := s.ScrapeListPage(req.URL)
+	propertyURLs, err := s.ScrapeListPageContext(c.Request.Context(), req.URL)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to scrape list page: %v", err)})
+		respondScrapeError(c, "Failed to scrape list page", err)
 		return
 	}
 
@@ -749,17 +1285,22 @@ func scrapeAndUpdate(c *gin.Context) {
 
 		property, err := s.ScrapeProperty(url)
 		if err != nil {
-			errMsg := err.Error()
+			code, errMsg := errtext.FromError(err)
 
 			// Check for permanent failure (404)
-			if strings.Contains(errMsg, "permanent_fail") || strings.Contains(errMsg, "404") {
+			if errors.Is(err, scraper.ErrNotFound) {
 				log.Printf("Permanent failure (404) for %s - not retrying", url)
 				permanentFailures = append(permanentFailures, fmt.Sprintf("%s: 404 Not Found (permanent)", url))
 				continue
 			}
+			if errors.Is(err, scraper.ErrDelisted) {
+				log.Printf("Ended listing page for %s - not retrying", url)
+				permanentFailures = append(permanentFailures, fmt.Sprintf("%s: listing ended (permanent)", url))
+				continue
+			}
 
 			// Other errors (WAF, timeout, etc.)
-			scrapeErrors = append(scrapeErrors, fmt.Sprintf("%s: %v", url, err))
+			scrapeErrors = append(scrapeErrors, fmt.Sprintf("%s: [%s] %s", url, code, errMsg))
 			continue
 		}
 
@@ -789,7 +1330,7 @@ func scrapeAndUpdate(c *gin.Context) {
 	// Mark removed properties
 	if len(removedIDs) > 0 {
 		if err := gormDB.MarkPropertiesAsRemoved(removedIDs); err != nil {
-			saveErrors = append(saveErrors, fmt.Sprintf("Failed to mark properties as removed: %v", err))
+			saveErrors = append(saveErrors, errtext.Clean(fmt.Sprintf("Failed to mark properties as removed: %v", err)))
 		} else {
 			log.Printf("Marked %d properties as removed", len(removedIDs))
 		}
@@ -798,7 +1339,7 @@ func scrapeAndUpdate(c *gin.Context) {
 	// Save new and updated properties
 	for _, property := range scrapedProperties {
 		if err := gormDB.SaveProperty(&property); err != nil {
-			saveErrors = append(saveErrors, fmt.Sprintf("%s: %v", property.ID, err))
+			saveErrors = append(saveErrors, errtext.Clean(fmt.Sprintf("%s: %v", property.ID, err)))
 			continue
 		}
 	}
@@ -855,6 +1396,7 @@ func searchProperties(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	attachFreshness(properties)
 
 	c.JSON(http.StatusOK, properties)
 }
@@ -891,6 +1433,11 @@ func filterProperties(c *gin.Context) {
 		params.FloorPlans = floorPlans
 	}
 
+	// Building types (repeated param, OR semantics)
+	if buildingTypes := c.QueryArray("building_type"); len(buildingTypes) > 0 {
+		params.BuildingTypes = buildingTypes
+	}
+
 	// Max walk time
 	if maxWalkStr := c.Query("max_walk_time"); maxWalkStr != "" {
 		if maxWalk, err := strconv.Atoi(maxWalkStr); err == nil {
@@ -898,33 +1445,38 @@ func filterProperties(c *gin.Context) {
 		}
 	}
 
+	// Lines (repeated param, OR semantics)
+	if lines := c.QueryArray("lines"); len(lines) > 0 {
+		params.Lines = lines
+	}
+
+	// Minimum building floors
+	if minFloorsStr := c.Query("min_building_floors"); minFloorsStr != "" {
+		if minFloors, err := strconv.Atoi(minFloorsStr); err == nil {
+			params.MinBuildingFloors = &minFloors
+		}
+	}
+
+	// Campaign flags
+	params.FreeRent = c.Query("free_rent") == "true"
+	params.NoBrokerageFee = c.Query("no_brokerage_fee") == "true"
+
+	// Lease type (fixed_term_lease=false excludes 定期借家, true limits to 定期借家)
+	if fixedTerm, err := strconv.ParseBool(c.Query("fixed_term_lease")); err == nil {
+		params.FixedTermLease = &fixedTerm
+	}
+
 	// Sort by
 	if sortBy := c.Query("sort_by"); sortBy != "" {
 		params.SortBy = sortBy
 	}
 
-	// If no query and no filters, get all from database
-	if query == "" && params.MinRent == nil && params.MaxRent == nil &&
-		len(params.FloorPlans) == 0 && params.MaxWalkTime == nil {
-		var properties []models.Property
-		var err error
-
-		if gormDB != nil {
-			properties, err = gormDB.GetAllProperties()
-		} else {
-			properties, err = db.GetAllProperties()
-		}
-
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-			return
-		}
-		c.JSON(http.StatusOK, properties)
+	if err := params.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "code": "invalid_filter"})
 		return
 	}
 
-	// Search with filters using Meilisearch
-	properties, err := searchClient.FilterSearch(params)
+	properties, err := runFilterSearch(params)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -933,42 +1485,512 @@ func filterProperties(c *gin.Context) {
 	c.JSON(http.StatusOK, properties)
 }
 
-func getEnv(key, defaultValue string) string {
-	if value := os.Getenv(key); value != "" {
-		return value
-	}
-	return defaultValue
-}
-
-// getEnvOrConfig returns config value if set, otherwise falls back to environment variable, then default
-func getEnvOrConfig(configValue, envKey, defaultValue string) string {
-	if configValue != "" {
-		return configValue
+// runFilterSearch executes /api/filter parameters: everything from the database when no
+// query or filter is set, otherwise a Meilisearch filter search
+func runFilterSearch(params search.FilterParams) ([]models.Property, error) {
+	if !params.HasFilters() {
+		if gormDB != nil {
+			return gormDB.GetAllProperties()
+		}
+		return db.GetAllProperties()
 	}
-	return getEnv(envKey, defaultValue)
-}
 
-// Utility function to load URLs from file
-func loadURLsFromFile(filename string) ([]string, error) {
-	file, err := os.Open(filename)
+	properties, err := searchClient.FilterSearch(params)
 	if err != nil {
 		return nil, err
 	}
-	defer file.Close()
+	attachFreshness(properties)
+	return properties, nil
+}
 
-	var urls []string
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		line := strings.TrimSpace(scanner.Text())
-		if line != "" && !strings.HasPrefix(line, "#") {
-			urls = append(urls, line)
-		}
+// createShare stores filter parameters under a random token
+// Body: {"filter_params": {...same names as /api/filter query params...}, "expires_in_days": 30}
+func createShare(c *gin.Context) {
+	if shareService == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Share links are not available (requires MySQL/GORM)"})
+		return
 	}
 
-	return urls, scanner.Err()
-}
+	var req struct {
+		FilterParams  search.FilterParams `json:"filter_params"`
+		ExpiresInDays int                 `json:"expires_in_days"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.ExpiresInDays < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "expires_in_days must be positive"})
+		return
+	}
+	if err := req.FilterParams.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "code": "invalid_filter"})
+		return
+	}
 
-// rateLimitMiddleware returns a Gin middleware that enforces rate limiting
+	params, err := json.Marshal(req.FilterParams)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	shared, err := shareService.Create(string(params), time.Duration(req.ExpiresInDays)*24*time.Hour)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"token":         shared.Token,
+		"url":           "/api/share/" + shared.Token,
+		"expires_at":    shared.ExpiresAt,
+		"filter_params": req.FilterParams,
+	})
+}
+
+// getShare re-executes a shared search and returns the results with the original parameters
+func getShare(c *gin.Context) {
+	if shareService == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Share links are not available (requires MySQL/GORM)"})
+		return
+	}
+
+	shared, err := shareService.Get(c.Param("token"))
+	switch {
+	case errors.Is(err, share.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error(), "code": "share_not_found"})
+		return
+	case errors.Is(err, share.ErrExpired):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error(), "code": "share_expired"})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	var params search.FilterParams
+	if err := json.Unmarshal([]byte(shared.Params), &params); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("invalid stored parameters: %v", err)})
+		return
+	}
+	if params.Limit == 0 {
+		params.Limit = 20
+	}
+
+	properties, err := runFilterSearch(params)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"properties":    properties,
+		"count":         len(properties),
+		"filter_params": params,
+		"expires_at":    shared.ExpiresAt,
+	})
+}
+
+// repairListFields rewrites malformed facilities/features JSON into canonical arrays
+func repairListFields(c *gin.Context) {
+	start := time.Now()
+	repaired, err := gormDB.RepairListFields()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "repaired": repaired})
+		return
+	}
+
+	log.Printf("[Backfill] Repaired facilities/features on %d properties in %v", repaired, time.Since(start))
+	c.JSON(http.StatusOK, gin.H{
+		"message":  "List field backfill complete",
+		"repaired": repaired,
+	})
+}
+
+// correctProperty applies manual field corrections and locks them against re-scrapes
+// Body: {"fields": {"area": 25.3}, "author": "...", "reason": "..."}
+func correctProperty(c *gin.Context) {
+	var req struct {
+		Fields map[string]json.RawMessage `json:"fields" binding:"required"`
+		Author string                     `json:"author"`
+		Reason string                     `json:"reason"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	property, corrections, err := gormDB.CorrectProperty(c.Param("id"), req.Fields, req.Author, req.Reason)
+	if err != nil {
+		c.JSON(correctionErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := searchClient.IndexProperty(property); err != nil {
+		log.Printf("[Correction] Failed to reindex %s: %v", property.ID, err)
+	}
+	log.Printf("[Correction] property=%s fields=%d author=%q", property.ID, len(corrections), req.Author)
+
+	c.JSON(http.StatusOK, gin.H{
+		"property":    property,
+		"corrections": corrections,
+	})
+}
+
+// getPropertyCorrections returns the manual correction audit trail for a property
+func getPropertyCorrections(c *gin.Context) {
+	corrections, err := gormDB.GetManualCorrections(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"count":       len(corrections),
+		"corrections": corrections,
+	})
+}
+
+// unlockPropertyCorrection unlocks a corrected field so scrapes may update it again
+func unlockPropertyCorrection(c *gin.Context) {
+	property, err := gormDB.UnlockCorrection(c.Param("id"), c.Param("field"), c.Query("author"))
+	if err != nil {
+		c.JSON(correctionErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":       "Field unlocked",
+		"field":         c.Param("field"),
+		"locked_fields": property.GetLockedFields(),
+	})
+}
+
+// scrapeErrorStatus maps the scraper's typed errors to HTTP status codes
+// (delisted → 404, ended-listing page → 410, robots.txt → 403, throttled → 429, WAF / open breaker → 503, unparsable page
+// or every proxy down → 502, attempt timeout or retry budget used up → 504)
+func scrapeErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, scraper.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, scraper.ErrDelisted):
+		return http.StatusGone
+	case errors.Is(err, scraper.ErrBuildingPage):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, scraper.ErrRobotsDisallowed):
+		return http.StatusForbidden
+	case errors.Is(err, scraper.ErrUnknownSource):
+		return http.StatusBadRequest
+	case errors.Is(err, scraper.ErrRateLimited):
+		return http.StatusTooManyRequests
+	case errors.Is(err, scraper.ErrWAFBlocked), errors.Is(err, scraper.ErrCircuitOpen):
+		return http.StatusServiceUnavailable
+	case errors.Is(err, scraper.ErrParse), errors.Is(err, scraper.ErrProxy):
+		return http.StatusBadGateway
+	case errors.Is(err, scraper.ErrTimeout):
+		return http.StatusGatewayTimeout
+	}
+	return http.StatusInternalServerError
+}
+
+// respondScrapeError writes a scrape failure with its mapped status and errtext code;
+// an open circuit breaker also sets Retry-After
+func respondScrapeError(c *gin.Context, prefix string, err error) {
+	code, msg := errtext.FromError(err)
+	if errors.Is(err, scraper.ErrCircuitOpen) {
+		if _, retryAt := yahooBreaker.State(); !retryAt.IsZero() {
+			c.Header("Retry-After", strconv.Itoa(int(time.Until(retryAt).Seconds())+1))
+		}
+	}
+	if prefix != "" {
+		msg = prefix + ": " + msg
+	}
+	c.JSON(scrapeErrorStatus(err), gin.H{"error": msg, "code": code})
+}
+
+// getRobotsRules returns the cached robots.txt rules per origin (fetched lazily on first scrape)
+func getRobotsRules(c *gin.Context) {
+	respectRobots := appConfig == nil || appConfig.Scraper.RespectRobots
+	c.JSON(http.StatusOK, gin.H{
+		"respect_robots": respectRobots,
+		"origins":        scraper.RobotsCacheSnapshot(),
+	})
+}
+
+// getCircuitBreakers returns each source's circuit breaker state and the hosts with shared limits
+func getCircuitBreakers(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"sources": createSources().BreakerStatus(),
+		"hosts":   scraper.SharedHosts(),
+	})
+}
+
+// resetCircuitBreakers closes the named source's circuit breaker, or every source's without
+// a name, so scraping resumes without a restart once a block is known to be lifted
+func resetCircuitBreakers(c *gin.Context) {
+	var req struct {
+		Source string `json:"source"`
+	}
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	sources := createSources()
+	var reset []string
+	for _, src := range sources.Sources() {
+		bs, ok := src.(scraper.BreakerSource)
+		if !ok || (req.Source != "" && src.Name() != req.Source) {
+			continue
+		}
+		bs.CircuitBreaker().Reset()
+		reset = append(reset, src.Name())
+	}
+	if req.Source != "" && len(reset) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("unknown source %q", req.Source)})
+		return
+	}
+
+	log.Printf("[admin] Circuit breakers reset: %v", reset)
+	c.JSON(http.StatusOK, gin.H{
+		"reset":   reset,
+		"sources": sources.BreakerStatus(),
+	})
+}
+
+// correctionErrorStatus maps correction errors to HTTP status codes
+func correctionErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, models.ErrFieldNotCorrectable), errors.Is(err, models.ErrFieldNotLocked):
+		return http.StatusBadRequest
+	}
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &syntaxErr) || errors.As(err, &typeErr) {
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+// listQueueItems lists detail_scrape_queue items (?status=, ?source=, ?min_attempts=, ?limit=, ?offset=)
+// in processing order, with last_error shortened
+func listQueueItems(c *gin.Context) {
+	filter := database.QueueItemFilter{
+		Status: c.Query("status"),
+		Source: c.Query("source"),
+	}
+	var page database.QueuePage
+	for name, dest := range map[string]*int{"min_attempts": &filter.MinAttempts, "limit": &page.Limit, "offset": &page.Offset} {
+		if raw := c.Query(name); raw != "" {
+			n, err := strconv.Atoi(raw)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": name + " must be an integer"})
+				return
+			}
+			*dest = n
+		}
+	}
+
+	list, err := gormDB.ListQueueItems(filter, page)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, database.ErrInvalidQueueFilter) {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, list)
+}
+
+// getQueueItem returns one detail_scrape_queue item with its full last_error
+func getQueueItem(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "id must be an integer"})
+		return
+	}
+
+	item, err := gormDB.GetQueueItem(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "queue item not found"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, item)
+}
+
+// retryQueueItem puts a failed/permanent_fail/done/cancelled queue item back to pending
+// ({"reset_attempts": true} also clears its attempt count)
+func retryQueueItem(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "id must be an integer"})
+		return
+	}
+	var req struct {
+		ResetAttempts bool `json:"reset_attempts"`
+	}
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	item, err := gormDB.RetryQueueItem(id, req.ResetAttempts)
+	if err != nil {
+		c.JSON(queueItemErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	log.Printf("[admin] Queue item %d retried (reset_attempts=%v)", id, req.ResetAttempts)
+	c.JSON(http.StatusOK, item)
+}
+
+// cancelQueueItem marks a pending/failed queue item cancelled; a processing one is refused with 409
+func cancelQueueItem(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "id must be an integer"})
+		return
+	}
+
+	item, err := gormDB.CancelQueueItem(id)
+	if err != nil {
+		c.JSON(queueItemErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	log.Printf("[admin] Queue item %d cancelled", id)
+	c.JSON(http.StatusOK, item)
+}
+
+// queueItemErrorStatus maps retry/cancel errors to HTTP statuses
+func queueItemErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, database.ErrQueueItemConflict):
+		return http.StatusConflict
+	}
+	return http.StatusInternalServerError
+}
+
+// attachFreshness sets the freshness badge fields on properties about to be returned.
+// Without GORM only the fetched_at-based fields are set.
+func attachFreshness(properties []models.Property) {
+	thresholds := models.DefaultFreshnessThresholds
+	if appConfig != nil {
+		thresholds = appConfig.Freshness.Thresholds()
+	}
+
+	if gormDB == nil {
+		now := time.Now()
+		for i := range properties {
+			properties[i].ApplyFreshness(now, thresholds)
+		}
+		return
+	}
+	if err := gormDB.AttachFreshness(properties, thresholds); err != nil {
+		log.Printf("[Freshness] Failed to look up recent price changes: %v", err)
+	}
+}
+
+// getLines returns distinct line names with active-property counts for the line picker
+func getLines(c *gin.Context) {
+	if gormDB == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error": "Line listing is not available (requires MySQL/GORM)",
+		})
+		return
+	}
+
+	lines, err := gormDB.GetLineCounts()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"count": len(lines),
+		"lines": lines,
+	})
+}
+
+// getWalkBucketStats returns active-property counts per walk-time bucket (1-5, 6-10, 11-15,
+// 16+ minutes, unknown), overall or for one station (?station=渋谷)
+func getWalkBucketStats(c *gin.Context) {
+	if gormDB == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error": "Walk-time stats are not available (requires MySQL/GORM)",
+		})
+		return
+	}
+
+	station := strings.TrimSpace(c.Query("station"))
+	buckets, err := gormDB.GetWalkBucketCounts(station)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	var total int64
+	for _, b := range buckets {
+		total += b.Count
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"station": station,
+		"total":   total,
+		"buckets": buckets,
+	})
+}
+
+// getMetaOptions returns the option lists the backend validates against (no DB access)
+func getMetaOptions(c *gin.Context) {
+	c.JSON(http.StatusOK, meta.CurrentOptions())
+}
+
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+// getEnvOrConfig returns config value if set, otherwise falls back to environment variable, then default
+func getEnvOrConfig(configValue, envKey, defaultValue string) string {
+	if configValue != "" {
+		return configValue
+	}
+	return getEnv(envKey, defaultValue)
+}
+
+// Utility function to load URLs from file
+func loadURLsFromFile(filename string) ([]string, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var urls []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line != "" && !strings.HasPrefix(line, "#") {
+			urls = append(urls, line)
+		}
+	}
+
+	return urls, scanner.Err()
+}
+
+// rateLimitMiddleware returns a Gin middleware that enforces rate limiting
 func rateLimitMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		if !rateLimiter.AllowRequest() {
@@ -985,13 +2007,248 @@ func rateLimitMiddleware() gin.HandlerFunc {
 	}
 }
 
-// getRateLimitStats returns current rate limiter statistics
-func getRateLimitStats(c *gin.Context) {
-	stats := rateLimiter.GetStats()
-	c.JSON(http.StatusOK, stats)
+// registerScrapeRoutes adds the scraping and manual enqueue routes.
+// Synchronous scrape endpoints fail fast while the circuit breaker is open or scraping is
+// frozen; the queue-based endpoints keep accepting work since the worker waits it out.
+// Per-API-key daily quotas run first so a rejected request doesn't touch the shared budget.
+func registerScrapeRoutes(r gin.IRouter) {
+	scrapeQuota := apiKeyQuotaMiddleware(database.QuotaKindScrape)
+	enqueueQuota := apiKeyQuotaMiddleware(database.QuotaKindEnqueue)
+	r.POST("/api/scrape", scrapeQuota, rateLimitMiddleware(), scrapeURL) // checks the URL's own site breaker
+	r.POST("/api/scrape/batch", scrapeQuota, rateLimitMiddleware(), breakerBackpressureMiddleware(), scrapeBatch)
+	r.POST("/api/scrape/list", enqueueQuota, rateLimitMiddleware(), scrapeListPage)
+	r.POST("/api/scrape/update", scrapeQuota, rateLimitMiddleware(), breakerBackpressureMiddleware(), scrapeAndUpdate)
+
+	// Manual enqueue: the worker scrapes the URLs under the queue's limiter and WAF protections
+	r.POST("/api/queue/enqueue", enqueueQuota, rateLimitMiddleware(), enqueueURL)
+	r.POST("/api/queue/enqueue/batch", enqueueQuota, rateLimitMiddleware(), enqueueURLs)
+}
+
+// Reasons reported by respondBreakerOpen
+const (
+	blockReasonBreakerOpen = "breaker_open" // this process's circuit breaker is open
+	blockReasonFrozen      = "frozen"       // scraping_state holds a block (another replica, or before a restart)
+)
+
+// breakerBackpressureMiddleware rejects synchronous scrape requests with 503 while the
+// circuit breaker is open or scraping is frozen, instead of failing every URL after a long wait
+func breakerBackpressureMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if reason, retryAt, blocked := scrapingBlocked(); blocked {
+			respondBreakerOpen(c, reason, retryAt)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// scrapingBlocked reports why Yahoo scrapes are refused right now and until when: the
+// in-process breaker first, then the block persisted in scraping_state. A frozen state
+// without blocked_until has a zero retryAt.
+func scrapingBlocked() (reason string, retryAt time.Time, blocked bool) {
+	if isOpen, retryAt := yahooBreaker.State(); isOpen {
+		return blockReasonBreakerOpen, retryAt, true
+	}
+	if gormDB == nil {
+		return "", time.Time{}, false
+	}
+
+	state, err := scheduler.LoadScrapingState(gormDB.DB())
+	if err != nil {
+		// The breaker above still protects this process; don't refuse on a read failure
+		log.Printf("[scrape] Failed to load scraping state: %v", err)
+		return "", time.Time{}, false
+	}
+	if state == nil || state.CanScrape() {
+		return "", time.Time{}, false
+	}
+	if state.BlockedUntil != nil {
+		retryAt = *state.BlockedUntil
+	}
+	return blockReasonFrozen, retryAt, true
+}
+
+// respondBreakerOpen writes the 503 returned while scraping is blocked (reason: breaker_open
+// or frozen); Retry-After is omitted when the block has no end time
+func respondBreakerOpen(c *gin.Context, reason string, retryAt time.Time) {
+	body := gin.H{
+		"error":  "Scraping temporarily unavailable",
+		"code":   "scraping_unavailable",
+		"reason": reason,
+	}
+	if !retryAt.IsZero() {
+		retryAfter := int(time.Until(retryAt).Seconds()) + 1
+		c.Header("Retry-After", strconv.Itoa(retryAfter))
+		body["retry_at"] = retryAt
+		body["retry_after_seconds"] = retryAfter
+	}
+	c.JSON(http.StatusServiceUnavailable, body)
+}
+
+// getRateLimitStats returns current rate limiter statistics, plus the caller's own
+// quota usage when the request carries a known API key
+func getRateLimitStats(c *gin.Context) {
+	stats := rateLimiter.GetStats()
+	detail := scraper.DetailLimiter.Status()
+	stats.DetailLimiter = &detail
+	parse := scraper.ParseLimiter.Status()
+	stats.ParseLimiter = &parse
+
+	apiKey, ok := appConfig.FindAPIKey(c.GetHeader("X-API-Key"))
+	if !ok || gormDB == nil {
+		c.JSON(http.StatusOK, stats)
+		return
+	}
+
+	usage, err := gormDB.GetAPIKeyUsage(apiKey.Name, quotaDay())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, struct {
+		ratelimit.Stats
+		APIKey gin.H `json:"api_key"`
+	}{
+		Stats: stats,
+		APIKey: gin.H{
+			"name":          apiKey.Name,
+			"day":           usage.Day,
+			"scrape_used":   usage.ScrapeCount,
+			"scrape_limit":  apiKey.ScrapePerDay,
+			"enqueue_used":  usage.EnqueueCount,
+			"enqueue_limit": apiKey.EnqueuePerDay,
+		},
+	})
+}
+
+// quotaDay returns the current quota day (YYYY-MM-DD in the configured timezone)
+func quotaDay() string {
+	return quotaNow().In(appConfig.Location()).Format("2006-01-02")
+}
+
+// quotaNow is the clock quota days are taken from (replaced in tests)
+var quotaNow = time.Now
+
+// Context keys the quota middleware shares with the handlers behind it
+const (
+	quotaLeftKey     = "quota_left"     // int: units the caller's key has left today (unset = no limit)
+	quotaConsumedKey = "quota_consumed" // int: queue items an enqueue handler actually added
+)
+
+// quotaLeft returns how many units the caller's key has left today (false = no limit applies)
+func quotaLeft(c *gin.Context) (int, bool) {
+	v, ok := c.Get(quotaLeftKey)
+	n, _ := v.(int)
+	return n, ok
+}
+
+// consumeEnqueueQuota records that the handler added n items to the queue; the enqueue quota
+// counts those, not requests
+func consumeEnqueueQuota(c *gin.Context, n int) {
+	c.Set(quotaConsumedKey, n)
+}
+
+// apiKeyQuotaMiddleware enforces the caller's daily quota for the given kind. Once API keys are
+// configured every request needs a known one (401 otherwise); without keys only the global
+// limiter applies. The scrape quota counts requests that end in a non-error response; the
+// enqueue quota counts the queue items the handler reports with consumeEnqueueQuota.
+func apiKeyQuotaMiddleware(kind string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if len(appConfig.APIKeys) == 0 {
+			c.Next()
+			return
+		}
+
+		header := c.GetHeader("X-API-Key")
+		if header == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "API key required (X-API-Key header)"})
+			c.Abort()
+			return
+		}
+		apiKey, ok := appConfig.FindAPIKey(header)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Unknown API key"})
+			c.Abort()
+			return
+		}
+
+		// Counters live in MySQL; without it quotas can't be tracked across restarts
+		if gormDB == nil {
+			c.Next()
+			return
+		}
+
+		limit := apiKey.ScrapePerDay
+		if kind == database.QuotaKindEnqueue {
+			limit = apiKey.EnqueuePerDay
+		}
+
+		day := quotaDay()
+		if limit > 0 {
+			usage, err := gormDB.GetAPIKeyUsage(apiKey.Name, day)
+			if err != nil {
+				// Soft quota: don't block callers on a counter read failure
+				log.Printf("[Quota] Failed to read usage for %s: %v", apiKey.Name, err)
+			} else {
+				used := usage.ScrapeCount
+				if kind == database.QuotaKindEnqueue {
+					used = usage.EnqueueCount
+				}
+				if used >= limit {
+					c.JSON(http.StatusTooManyRequests, gin.H{
+						"error":   "API key quota exceeded",
+						"message": fmt.Sprintf("Daily %s quota for %s is used up.", kind, apiKey.Name),
+						"quota": gin.H{
+							"key_name": apiKey.Name,
+							"kind":     kind,
+							"day":      day,
+							"used":     used,
+							"limit":    limit,
+						},
+					})
+					c.Abort()
+					return
+				}
+				c.Set(quotaLeftKey, limit-used)
+			}
+		}
+
+		c.Next()
+
+		n := 0
+		switch kind {
+		case database.QuotaKindEnqueue:
+			// Items already created count even if the request failed part way
+			n = c.GetInt(quotaConsumedKey)
+		default:
+			if c.Writer.Status() < http.StatusBadRequest {
+				n = 1
+			}
+		}
+		if n > 0 {
+			if err := gormDB.IncrementAPIKeyUsage(apiKey.Name, day, kind, n); err != nil {
+				log.Printf("[Quota] Failed to record usage for %s: %v", apiKey.Name, err)
+			}
+		}
+	}
+}
+
+// getListingCacheStats returns hit/miss counters for the landing-page listing cache
+func getListingCacheStats(c *gin.Context) {
+	if gormDB == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error": "Listing cache is not available (requires MySQL/GORM)",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gormDB.GetListingCacheStats())
 }
 
-// getQueueStats returns current queue worker statistics
+// getQueueStats returns current queue worker statistics with the backlog figures (oldest pending
+// age, done in the last 24h, average attempts)
 func getQueueStats(c *gin.Context) {
 	if queueWorker == nil {
 		c.JSON(http.StatusServiceUnavailable, gin.H{
@@ -1001,9 +2258,190 @@ func getQueueStats(c *gin.Context) {
 	}
 
 	stats := queueWorker.GetQueueStats()
+	if gormDB != nil {
+		backlog, err := gormDB.GetQueueBacklog(time.Now())
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		stats["oldest_pending_at"] = backlog.OldestPendingAt
+		stats["oldest_pending_age_seconds"] = backlog.OldestPendingAgeSeconds
+		stats["done_last_24h"] = backlog.DoneLast24h
+		stats["avg_attempts_done"] = backlog.AvgAttemptsDone
+	}
+	c.JSON(http.StatusOK, stats)
+}
+
+// startQueueWorker starts the queue worker (409 if it is running or its last run is still
+// unwinding) and returns its stats
+func startQueueWorker(c *gin.Context) {
+	if queueWorker == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error": "Queue worker is not available (requires MySQL/GORM)",
+		})
+		return
+	}
+
+	if err := queueWorker.Start(); err != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "is_running": queueWorker.IsRunning()})
+		return
+	}
+	log.Println("Admin: Queue worker started")
+
+	c.JSON(http.StatusOK, queueWorker.GetQueueStats())
+}
+
+// stopQueueWorker stops the queue worker (409 if it is not running) and returns its stats.
+// Items in progress get the shutdown grace period, then go back to pending.
+func stopQueueWorker(c *gin.Context) {
+	if queueWorker == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error": "Queue worker is not available (requires MySQL/GORM)",
+		})
+		return
+	}
+
+	if err := queueWorker.Stop(); err != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "is_running": queueWorker.IsRunning()})
+		return
+	}
+	log.Println("Admin: Queue worker stopped")
+
+	c.JSON(http.StatusOK, queueWorker.GetQueueStats())
+}
+
+// pauseQueueWorker stops the queue worker from claiming items ({"reason": ...} optional) while
+// the API keeps serving; items in progress finish. The pause survives a restart. Returns the
+// stats, with persisted false (and a warning) if the pause only holds for this process.
+func pauseQueueWorker(c *gin.Context) {
+	if queueWorker == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error": "Queue worker is not available (requires MySQL/GORM)",
+		})
+		return
+	}
+	var req struct {
+		Reason string `json:"reason"`
+	}
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	_, err := queueWorker.Pause(req.Reason)
+	log.Printf("Admin: Queue worker paused (reason: %q)", req.Reason)
+	respondPauseChange(c, err)
+}
+
+// resumeQueueWorker lets a paused queue worker claim items again and returns its stats
+func resumeQueueWorker(c *gin.Context) {
+	if queueWorker == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error": "Queue worker is not available (requires MySQL/GORM)",
+		})
+		return
+	}
+
+	_, err := queueWorker.Resume()
+	log.Println("Admin: Queue worker resumed")
+	respondPauseChange(c, err)
+}
+
+// respondPauseChange returns the queue stats after a pause or resume; err is a failed write
+// of the pause, which is already in effect for this process but won't survive a restart
+func respondPauseChange(c *gin.Context, err error) {
+	stats := queueWorker.GetQueueStats()
+	stats["persisted"] = err == nil
+	if err != nil {
+		log.Printf("Admin: %v", err)
+		stats["warning"] = err.Error()
+	}
 	c.JSON(http.StatusOK, stats)
 }
 
+// enqueueURL queues one detail URL for the worker instead of scraping it inline
+// ({"url": ..., "priority": 2}); a listing already pending/processing is reported as a duplicate
+func enqueueURL(c *gin.Context) {
+	var req struct {
+		URL      string `json:"url" binding:"required"`
+		Priority *int   `json:"priority"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if _, _, _, err := queue.ResolveDetailURL(createSources(), req.URL); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	runManualEnqueue(c, []string{req.URL}, req.Priority)
+}
+
+// enqueueURLs is enqueueURL for up to queue.MaxManualBatch URLs ({"urls": [...], "priority": 2});
+// invalid URLs are reported per URL instead of failing the batch
+func enqueueURLs(c *gin.Context) {
+	var req struct {
+		URLs     []string `json:"urls" binding:"required"`
+		Priority *int     `json:"priority"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if len(req.URLs) == 0 || len(req.URLs) > queue.MaxManualBatch {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("urls must contain 1 to %d URLs", queue.MaxManualBatch)})
+		return
+	}
+	runManualEnqueue(c, req.URLs, req.Priority)
+}
+
+// runManualEnqueue inserts the queue rows for enqueueURL / enqueueURLs and writes the response
+func runManualEnqueue(c *gin.Context, urls []string, priority *int) {
+	if queueService == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error": "Queue is not available (requires MySQL/GORM)",
+		})
+		return
+	}
+	p := queue.PriorityManual
+	if priority != nil {
+		p = *priority
+	}
+	if p < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "priority must not be negative"})
+		return
+	}
+
+	if left, limited := quotaLeft(c); limited && len(urls) > left {
+		c.JSON(http.StatusTooManyRequests, gin.H{
+			"error":   "API key quota exceeded",
+			"message": fmt.Sprintf("%d URLs exceed the %d enqueues left today.", len(urls), left),
+		})
+		return
+	}
+
+	result, err := queueService.EnqueueURLs(createSources(), urls, p)
+	consumeEnqueueQuota(c, len(result.Created))
+	if err != nil {
+		log.Printf("[Enqueue] Failed after %d created: %v", len(result.Created), err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "result": result})
+		return
+	}
+	log.Printf("[Enqueue] priority=%d created=%d duplicates=%d invalid=%d",
+		p, len(result.Created), len(result.Duplicates), len(result.Invalid))
+
+	// 202: the worker scrapes them later (also while the breaker is open)
+	c.JSON(http.StatusAccepted, gin.H{
+		"priority":    p,
+		"created_ids": result.CreatedIDs(),
+		"created":     result.Created,
+		"duplicates":  result.Duplicates,
+		"invalid":     result.Invalid,
+	})
+}
+
 // triggerScheduledScraping manually triggers the scheduled scraping job
 func triggerScheduledScraping(c *gin.Context) {
 	if appScheduler == nil {
@@ -1013,6 +2451,11 @@ func triggerScheduledScraping(c *gin.Context) {
 		return
 	}
 
+	if appScheduler.RunInProgress() {
+		c.JSON(http.StatusConflict, gin.H{"error": scheduler.ErrRunInProgress.Error()})
+		return
+	}
+
 	// Run in background to avoid timeout
 	go func() {
 		if err := appScheduler.RunNow(); err != nil {
@@ -1052,6 +2495,86 @@ func getPropertyHistory(c *gin.Context) {
 	})
 }
 
+// getPropertyDiff diffs the snapshots nearest to two dates (from/to, YYYY-MM-DD) using
+// the same comparison as change detection, plus the change events recorded in between
+func getPropertyDiff(c *gin.Context) {
+	if snapshotService == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error": "Snapshot service is not available (requires MySQL/GORM)",
+		})
+		return
+	}
+
+	from, fromErr := time.Parse("2006-01-02", c.Query("from"))
+	to, toErr := time.Parse("2006-01-02", c.Query("to"))
+	if fromErr != nil || toErr != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "from and to are required (YYYY-MM-DD)", "code": "invalid_date"})
+		return
+	}
+	if to.Before(from) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "to must not be before from", "code": "invalid_range"})
+		return
+	}
+
+	tolerance := snapshot.DefaultDiffTolerance
+	if daysStr := c.Query("tolerance_days"); daysStr != "" {
+		if days, err := strconv.Atoi(daysStr); err == nil && days >= 0 {
+			tolerance = time.Duration(days) * 24 * time.Hour
+		}
+	}
+
+	diff, err := snapshotService.DiffBetween(c.Param("id"), from, to, tolerance)
+	switch {
+	case errors.Is(err, snapshot.ErrFromSnapshotMissing):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error(), "code": "from_snapshot_missing"})
+		return
+	case errors.Is(err, snapshot.ErrToSnapshotMissing):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error(), "code": "to_snapshot_missing"})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, diff)
+}
+
+// favoriteProperty increments a property's favorite count
+func favoriteProperty(c *gin.Context) {
+	adjustFavorite(c, 1)
+}
+
+// unfavoriteProperty decrements a property's favorite count
+func unfavoriteProperty(c *gin.Context) {
+	adjustFavorite(c, -1)
+}
+
+func adjustFavorite(c *gin.Context, delta int) {
+	if gormDB == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error": "Favorites are not available (requires MySQL/GORM)",
+		})
+		return
+	}
+
+	id := c.Param("id")
+	if _, err := gormDB.GetPropertyByID(id); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Property not found"})
+		return
+	}
+
+	count, err := gormDB.AdjustFavoriteCount(id, delta)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"property_id":    id,
+		"favorite_count": count,
+	})
+}
+
 // getRecentChanges retrieves recent property changes
 func getRecentChanges(c *gin.Context) {
 	if snapshotService == nil {
@@ -1076,20 +2599,147 @@ func getRecentChanges(c *gin.Context) {
 	})
 }
 
+// feedCache holds rendered Atom documents for a minute
+var feedCache = feed.NewCache(feed.CacheTTL)
+
+// maxFeedEntries caps the number of entries per feed
+const maxFeedEntries = 200
+
+// feedSelfURL returns the absolute URL of the current feed request
+func feedSelfURL(c *gin.Context) string {
+	scheme := "http"
+	if c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	return scheme + "://" + c.Request.Host + c.Request.URL.RequestURI()
+}
+
+// writeFeed renders a feed, caches it and writes it as application/atom+xml
+func writeFeed(c *gin.Context, f *feed.Feed) {
+	body, err := f.Render()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	feedCache.Set(c.Request.URL.RequestURI(), body)
+	c.Data(http.StatusOK, "application/atom+xml; charset=utf-8", body)
+}
+
+// serveCachedFeed writes a cached feed for this URL if present
+func serveCachedFeed(c *gin.Context) bool {
+	body, ok := feedCache.Get(c.Request.URL.RequestURI())
+	if ok {
+		c.Data(http.StatusOK, "application/atom+xml; charset=utf-8", body)
+	}
+	return ok
+}
+
+// getChangesFeed returns recent property changes as Atom (?limit=, ?type=rent_changed,relisted)
+func getChangesFeed(c *gin.Context) {
+	if snapshotService == nil || gormDB == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error": "Change feed is not available (requires MySQL/GORM)",
+		})
+		return
+	}
+	if serveCachedFeed(c) {
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if limit <= 0 || limit > maxFeedEntries {
+		limit = maxFeedEntries
+	}
+	var changeTypes []string
+	if typeStr := c.Query("type"); typeStr != "" {
+		changeTypes = strings.Split(typeStr, ",")
+	}
+
+	changes, err := snapshotService.GetRecentChangesByType(limit, changeTypes)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Load the referenced properties in one query
+	ids := make([]string, 0, len(changes))
+	for _, change := range changes {
+		ids = append(ids, change.PropertyID)
+	}
+	properties, err := gormDB.GetPropertiesByIDs(ids)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	byID := make(map[string]*models.Property, len(properties))
+	for i := range properties {
+		byID[properties[i].ID] = &properties[i]
+	}
+
+	updated := time.Now()
+	if len(changes) > 0 {
+		updated = changes[0].DetectedAt
+	}
+	f := feed.NewFeed("urn:shiboroom:feed:changes", "しぼるーむ 物件の変更", feedSelfURL(c), updated)
+	for _, change := range changes {
+		f.Entries = append(f.Entries, feed.ChangeEntry(change, byID[change.PropertyID]))
+	}
+
+	writeFeed(c, f)
+}
+
+// getNewListingsFeed returns newly listed properties as Atom (accepts the /api/properties filters)
+func getNewListingsFeed(c *gin.Context) {
+	if gormDB == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error": "New listings feed is not available (requires MySQL/GORM)",
+		})
+		return
+	}
+	if serveCachedFeed(c) {
+		return
+	}
+
+	filters := parsePropertyFilters(c)
+	filters.SortBy = "created_at_desc"
+	filters.Cursor = ""
+	if filters.Limit <= 0 || filters.Limit > maxFeedEntries {
+		filters.Limit = 50
+	}
+
+	result, err := gormDB.GetPropertiesWithFiltersPaginated(filters)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	updated := time.Now()
+	if len(result.Properties) > 0 {
+		updated = result.Properties[0].CreatedAt
+	}
+	f := feed.NewFeed("urn:shiboroom:feed:new-listings", "しぼるーむ 新着物件", feedSelfURL(c), updated)
+	for _, property := range result.Properties {
+		f.Entries = append(f.Entries, feed.NewListingEntry(property))
+	}
+
+	writeFeed(c, f)
+}
+
 // advancedSearchProperties performs advanced search with filters and facets
 func advancedSearchProperties(c *gin.Context) {
 	var reqBody struct {
-		Query       string   `json:"query"`
-		Limit       int64    `json:"limit"`
-		Offset      int64    `json:"offset"`
-		MinRent     *int     `json:"min_rent"`
-		MaxRent     *int     `json:"max_rent"`
-		FloorPlans  []string `json:"floor_plans"`
-		MinArea     *float64 `json:"min_area"`
-		MaxArea     *float64 `json:"max_area"`
-		MaxWalkTime *int     `json:"max_walk_time"`
-		Sort        string   `json:"sort"` // "rent_asc", "rent_desc", "area_desc", etc.
-		Facets      []string `json:"facets"`
+		Query             string   `json:"query"`
+		Limit             int64    `json:"limit"`
+		Offset            int64    `json:"offset"`
+		MinRent           *int     `json:"min_rent"`
+		MaxRent           *int     `json:"max_rent"`
+		FloorPlans        []string `json:"floor_plans"`
+		MinArea           *float64 `json:"min_area"`
+		MaxArea           *float64 `json:"max_area"`
+		MaxWalkTime       *int     `json:"max_walk_time"`
+		MinBuildingFloors *int     `json:"min_building_floors"`
+		Sort              string   `json:"sort"` // "rent_asc", "rent_desc", "area_desc", etc.
+		Facets            []string `json:"facets"`
 	}
 
 	if err := c.ShouldBindJSON(&reqBody); err != nil {
@@ -1115,9 +2765,16 @@ func advancedSearchProperties(c *gin.Context) {
 	if reqBody.MaxWalkTime != nil {
 		filters = append(filters, fmt.Sprintf("walk_time <= %d", *reqBody.MaxWalkTime))
 	}
+	if reqBody.MinBuildingFloors != nil {
+		filters = append(filters, fmt.Sprintf("building_floors >= %d", *reqBody.MinBuildingFloors))
+	}
 	if len(reqBody.FloorPlans) > 0 {
 		planFilters := make([]string, len(reqBody.FloorPlans))
 		for i, plan := range reqBody.FloorPlans {
+			if !models.IsFloorPlan(plan) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid floor plan: " + plan, "code": "invalid_filter"})
+				return
+			}
 			planFilters[i] = fmt.Sprintf("floor_plan = '%s'", plan)
 		}
 		filters = append(filters, "("+strings.Join(planFilters, " OR ")+")")
@@ -1126,20 +2783,12 @@ func advancedSearchProperties(c *gin.Context) {
 	// Build sort conditions
 	sortConditions := []string{}
 	if reqBody.Sort != "" {
-		switch reqBody.Sort {
-		case "rent_asc":
-			sortConditions = append(sortConditions, "rent:asc")
-		case "rent_desc":
-			sortConditions = append(sortConditions, "rent:desc")
-		case "area_desc":
-			sortConditions = append(sortConditions, "area:desc")
-		case "walk_time_asc":
-			sortConditions = append(sortConditions, "walk_time:asc")
-		case "building_age_asc":
-			sortConditions = append(sortConditions, "building_age:asc")
-		case "newest":
-			sortConditions = append(sortConditions, "created_at:desc")
+		sort, ok := search.SearchSort(reqBody.Sort)
+		if !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid sort: " + reqBody.Sort, "code": "invalid_sort"})
+			return
 		}
+		sortConditions = append(sortConditions, sort)
 	}
 
 	// Default facets
@@ -1167,6 +2816,7 @@ func advancedSearchProperties(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	attachFreshness(result.Hits)
 
 	c.JSON(http.StatusOK, gin.H{
 		"hits":            result.Hits,
@@ -1180,7 +2830,7 @@ func advancedSearchProperties(c *gin.Context) {
 
 // getSearchFacets retrieves facet distributions
 func getSearchFacets(c *gin.Context) {
-	facetsParam := c.DefaultQuery("facets", "floor_plan,station")
+	facetsParam := c.DefaultQuery("facets", "floor_plan,station,walk_time_bucket")
 	facets := strings.Split(facetsParam, ",")
 
 	facetDist, err := searchClient.GetFacets(facets)
@@ -1218,6 +2868,13 @@ func reindexAllProperties(c *gin.Context) {
 
 	log.Printf("[Reindex] Found %d properties in database", len(properties))
 
+	// Attach line names from property_stations for the lines filter
+	if gormDB != nil {
+		if err := gormDB.AttachLines(properties); err != nil {
+			log.Printf("[Reindex] Warning: Failed to attach lines: %v", err)
+		}
+	}
+
 	// Index all properties to Meilisearch
 	successCount := 0
 	failCount := 0
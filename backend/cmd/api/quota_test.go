@@ -0,0 +1,85 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"real-estate-portal/internal/config"
+	"real-estate-portal/internal/sqlitetest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+var quotaConfig = &config.Config{
+	Timezone: "Asia/Tokyo",
+	APIKeys: []config.APIKeyConfig{
+		{Name: "team-a", Key: "key-a", EnqueuePerDay: 2},
+		{Name: "team-b", Key: "key-b", EnqueuePerDay: 2},
+	},
+}
+
+// enqueueAs queues the n-th test listing with an API key and returns the status
+func enqueueAs(r *gin.Engine, key string, n int) int {
+	body := fmt.Sprintf(`{"url": "https://realestate.yahoo.co.jp/rent/detail/%013d/"}`, n)
+	req, _ := http.NewRequest(http.MethodPost, "/api/queue/enqueue", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-API-Key", key)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w.Code
+}
+
+// setQuotaClock pins the quota clock to t for the rest of the test
+func setQuotaClock(t *testing.T, at time.Time) {
+	old := quotaNow
+	quotaNow = func() time.Time { return at }
+	t.Cleanup(func() { quotaNow = old })
+}
+
+func TestAPIKeyQuota(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Skipf("no tz data: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "quota.db")
+	r := routerOn(t, sqlitetest.OpenFile(t, path), quotaConfig)
+	setQuotaClock(t, time.Date(2026, 10, 16, 23, 59, 30, 0, tokyo))
+
+	// Team A spends its two enqueues; team B has its own counter
+	for i, want := range []int{http.StatusAccepted, http.StatusAccepted, http.StatusTooManyRequests} {
+		if got := enqueueAs(r, "key-a", i+1); got != want {
+			t.Fatalf("team-a enqueue %d: status %d (want %d)", i+1, got, want)
+		}
+	}
+	if got := enqueueAs(r, "key-b", 10); got != http.StatusAccepted {
+		t.Errorf("team-b enqueue: status %d (want 202 while team-a is exhausted)", got)
+	}
+	if got := enqueueAs(r, "unknown", 11); got != http.StatusUnauthorized {
+		t.Errorf("unknown key: status %d (want 401)", got)
+	}
+
+	// A restarted process reads the same counters
+	r = routerOn(t, sqlitetest.OpenFile(t, path), quotaConfig)
+	if got := enqueueAs(r, "key-a", 3); got != http.StatusTooManyRequests {
+		t.Errorf("team-a after restart: status %d (want 429)", got)
+	}
+	if got := enqueueAs(r, "key-b", 12); got != http.StatusAccepted {
+		t.Errorf("team-b after restart: status %d (want 202, one left)", got)
+	}
+	if got := enqueueAs(r, "key-b", 13); got != http.StatusTooManyRequests {
+		t.Errorf("team-b third enqueue: status %d (want 429)", got)
+	}
+
+	// Midnight in the configured timezone starts a new day
+	setQuotaClock(t, time.Date(2026, 10, 17, 0, 0, 30, 0, tokyo))
+	if got := enqueueAs(r, "key-a", 4); got != http.StatusAccepted {
+		t.Errorf("team-a after midnight: status %d (want 202)", got)
+	}
+	if got := enqueueAs(r, "key-b", 14); got != http.StatusAccepted {
+		t.Errorf("team-b after midnight: status %d (want 202)", got)
+	}
+}
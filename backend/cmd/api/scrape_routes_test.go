@@ -0,0 +1,141 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"real-estate-portal/internal/config"
+	"real-estate-portal/internal/database"
+	"real-estate-portal/internal/models"
+	"real-estate-portal/internal/queue"
+	"real-estate-portal/internal/ratelimit"
+	"real-estate-portal/internal/scraper"
+	"real-estate-portal/internal/sqlitetest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// scrapeRouter wires the scrape and enqueue routes to a fresh SQLite database and a closed
+// Yahoo breaker, restoring the globals afterwards
+func scrapeRouter(t *testing.T) (*gin.Engine, *gorm.DB) {
+	t.Helper()
+	db := sqlitetest.Open(t)
+	return routerOn(t, db, &config.Config{}), db
+}
+
+// routerOn is scrapeRouter on db with cfg (a new process on the same database)
+func routerOn(t *testing.T, db *gorm.DB, cfg *config.Config) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	oldConfig, oldLimiter, oldBreaker, oldGorm, oldQueue := appConfig, rateLimiter, yahooBreaker, gormDB, queueService
+	t.Cleanup(func() {
+		appConfig, rateLimiter, yahooBreaker, gormDB, queueService = oldConfig, oldLimiter, oldBreaker, oldGorm, oldQueue
+	})
+	appConfig = cfg
+	rateLimiter = ratelimit.NewRateLimiter(0, 0, 0, false)
+	yahooBreaker = scraper.NewCircuitBreaker(2, time.Minute)
+	gormDB = database.NewGormDBFromDB(db)
+	queueService = queue.NewService(db)
+
+	r := gin.New()
+	registerScrapeRoutes(r)
+	return r
+}
+
+func post(r *gin.Engine, path, body string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+	return w
+}
+
+const testDetailURL = "https://realestate.yahoo.co.jp/rent/detail/0000012345678/"
+
+func TestScrapeBackpressure(t *testing.T) {
+	tests := []struct {
+		name       string
+		block      func(db *gorm.DB)
+		wantReason string
+	}{
+		{
+			name:       "breaker open",
+			block:      func(*gorm.DB) { yahooBreaker.Restore(time.Now().Add(10 * time.Minute)) },
+			wantReason: blockReasonBreakerOpen,
+		},
+		{
+			name: "frozen by another replica",
+			block: func(db *gorm.DB) {
+				state := models.ScrapingState{ID: 1, LastAttempt: time.Now()}
+				state.SetBlocked("waf_blocked", 10*time.Minute)
+				if err := db.Create(&state).Error; err != nil {
+					t.Fatalf("scraping state: %v", err)
+				}
+			},
+			wantReason: blockReasonFrozen,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, db := scrapeRouter(t)
+			tt.block(db)
+
+			for _, path := range []string{"/api/scrape/batch", "/api/scrape/update"} {
+				start := time.Now()
+				w := post(r, path, `{"urls": ["`+testDetailURL+`"]}`)
+				if took := time.Since(start); took > time.Second {
+					t.Errorf("%s: answered after %v (want fail fast)", path, took)
+				}
+				if w.Code != http.StatusServiceUnavailable {
+					t.Fatalf("%s: status %d (want 503): %s", path, w.Code, w.Body)
+				}
+				retryAfter, err := strconv.Atoi(w.Header().Get("Retry-After"))
+				if err != nil || retryAfter < 590 || retryAfter > 601 {
+					t.Errorf("%s: Retry-After %q (want about 600s)", path, w.Header().Get("Retry-After"))
+				}
+				var body struct {
+					Code   string `json:"code"`
+					Reason string `json:"reason"`
+				}
+				if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+					t.Fatalf("%s: body %s: %v", path, w.Body, err)
+				}
+				if body.Code != "scraping_unavailable" || body.Reason != tt.wantReason {
+					t.Errorf("%s: code %q reason %q (want scraping_unavailable, %s)", path, body.Code, body.Reason, tt.wantReason)
+				}
+			}
+
+			// The queue waits the block out, so enqueueing is still accepted
+			w := post(r, "/api/queue/enqueue", `{"url": "`+testDetailURL+`"}`)
+			if w.Code != http.StatusAccepted {
+				t.Fatalf("enqueue: status %d (want 202): %s", w.Code, w.Body)
+			}
+			var pending int64
+			db.Model(&models.DetailScrapeQueue{}).Where("status = ?", models.QueueStatusPending).Count(&pending)
+			if pending != 1 {
+				t.Errorf("%d pending queue rows (want 1)", pending)
+			}
+		})
+	}
+}
+
+// An expired block lets the request through to the handler
+func TestScrapeBackpressureExpired(t *testing.T) {
+	r, db := scrapeRouter(t)
+	until := time.Now().Add(-time.Minute)
+	state := models.ScrapingState{ID: 1, IsBlocked: true, BlockedUntil: &until, LastAttempt: time.Now()}
+	if err := db.Create(&state).Error; err != nil {
+		t.Fatalf("scraping state: %v", err)
+	}
+
+	// An empty batch is rejected by the handler itself
+	if w := post(r, "/api/scrape/batch", `{"urls": []}`); w.Code == http.StatusServiceUnavailable {
+		t.Errorf("status 503 after the block ended: %s", w.Body)
+	}
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"real-estate-portal/internal/config"
+	"real-estate-portal/internal/scraper"
+	"sync"
+	"testing"
+)
+
+// Each source's requests carry its sources.<name> profile, with the global pool for unset fields
+func TestSourceProfilesOnRequests(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	yamlText := `
+user_agents: ["Global/1.0"]
+scraper:
+  respect_robots: false
+sources:
+  yahoo:
+    user_agents: ["YahooAgent/1.0"]
+    headers:
+      Accept-Language: "ja-JP"
+      Sec-Fetch-User: ""
+  suumo:
+    headers:
+      X-Profile: "suumo"
+`
+	if err := os.WriteFile(path, []byte(yamlText), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	cfg, err := config.LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+
+	var mu sync.Mutex
+	sent := map[string]http.Header{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		sent[r.URL.Path] = r.Header.Clone()
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	oldConfig, oldLimiter, oldBreaker := appConfig, yahooLimiter, yahooBreaker
+	defer func() { appConfig, yahooLimiter, yahooBreaker = oldConfig, oldLimiter, oldBreaker }()
+	appConfig = cfg
+	yahooLimiter, yahooBreaker = scraper.SharedLimits("yahoo", scraper.YahooHost)
+
+	sources := map[string]scraper.AliveChecker{"/yahoo": createScraper(), "/suumo": createSuumoSource()}
+	for path, source := range sources {
+		if _, _, err := source.CheckAliveContext(context.Background(), server.URL+path); err != nil {
+			t.Fatalf("%s: %v", path, err)
+		}
+	}
+
+	tests := []struct {
+		path, header, want string
+	}{
+		{"/yahoo", "User-Agent", "YahooAgent/1.0"},
+		{"/yahoo", "Accept-Language", "ja-JP"},
+		{"/yahoo", "Sec-Fetch-User", ""}, // an empty profile value removes the header
+		{"/yahoo", "X-Profile", ""},
+		{"/suumo", "User-Agent", "Global/1.0"},
+		{"/suumo", "Accept-Language", "ja-JP,ja;q=0.9,en-US;q=0.8,en;q=0.7"},
+		{"/suumo", "Sec-Fetch-User", "?1"},
+		{"/suumo", "X-Profile", "suumo"},
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	for _, tt := range tests {
+		h, ok := sent[tt.path]
+		if !ok {
+			t.Fatalf("no request to %s", tt.path)
+		}
+		if got := h.Get(tt.header); got != tt.want {
+			t.Errorf("%s %s: %q (want %q)", tt.path, tt.header, got, tt.want)
+		}
+	}
+}
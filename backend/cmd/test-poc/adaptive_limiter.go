@@ -0,0 +1,142 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"real-estate-portal/internal/models"
+	"real-estate-portal/internal/ratelimit"
+	"real-estate-portal/internal/scheduler"
+	"real-estate-portal/internal/scraper"
+	"time"
+)
+
+// Test 82: 適応型の詳細リミッター（オフライン）
+// AdaptiveDetailLimiter が失敗の連続で slow モード（slow_per_hour）に入り、クールダウン後は
+// RampMinInterval ごとにしか上げず、基準レートに戻ること（成功だけでは上限を下げないこと）、
+// キューワーカーが設定された適応型リミッターで取得して試行ごとに Observe し、404 は数えないことを確認する
+func testAdaptiveLimiter() TestResult {
+	result := TestResult{
+		TestName:  "適応型の詳細リミッター",
+		Timestamp: time.Now(),
+	}
+
+	log.Println("\n[Test 82] 適応型詳細リミッターテスト...")
+
+	const step = 100 * time.Millisecond
+	rates := ratelimit.DetailRateConfig{NightPerHour: 30, DayPerHour: 30, DefaultPerHour: 30}
+	newLimiter := func() *ratelimit.AdaptiveDetailLimiter {
+		return ratelimit.NewAdaptiveDetailLimiter(rates, ratelimit.AdaptiveConfig{
+			Window: 10, SlowThreshold: 0.3, RecoverThreshold: 0.1, SlowPerHour: 5,
+			Cooldown: step, RampStep: 10, RampMinInterval: step,
+		})
+	}
+
+	var problems []string
+	var modes []string
+	expect := func(l *ratelimit.AdaptiveDetailLimiter, name, mode string, perHour int) {
+		status := l.Status()
+		modes = append(modes, fmt.Sprintf("%s=%s/%d", name, status.Mode, status.PerHour))
+		if status.Mode != mode || status.PerHour != perHour {
+			problems = append(problems, fmt.Sprintf("%s: mode=%s per_hour=%d (want %s, %d)", name, status.Mode, status.PerHour, mode, perHour))
+		}
+	}
+	observe := func(l *ratelimit.AdaptiveDetailLimiter, success bool, n int) {
+		for i := 0; i < n; i++ {
+			l.Observe(success)
+		}
+	}
+
+	// 1. Successes alone keep the base rate
+	l := newLimiter()
+	observe(l, true, 10)
+	expect(l, "healthy", ratelimit.AdaptiveModeNormal, 30)
+
+	// 2. A burst of failures (3 of the last 10) enters slow mode; successes do not end it early
+	observe(l, false, 3)
+	expect(l, "burst", ratelimit.AdaptiveModeSlow, 5)
+	if status := l.Status(); status.SlowUntil == nil || status.NextRampAt == nil || !status.NextRampAt.After(*status.SlowUntil) {
+		problems = append(problems, fmt.Sprintf("burst: slow_until=%v next_ramp_at=%v", status.SlowUntil, status.NextRampAt))
+	}
+	observe(l, true, 10)
+	expect(l, "slow, recovering", ratelimit.AdaptiveModeSlow, 5)
+
+	// 3. Cooldown over: capped until RampMinInterval after it, then one step per interval
+	time.Sleep(step + 20*time.Millisecond)
+	l.Observe(true)
+	expect(l, "cooldown over", ratelimit.AdaptiveModeRamping, 5)
+	time.Sleep(step)
+	l.Observe(true)
+	expect(l, "first ramp", ratelimit.AdaptiveModeRamping, 15)
+	observe(l, true, 5)
+	expect(l, "no ramp within the interval", ratelimit.AdaptiveModeRamping, 15)
+	time.Sleep(step + 20*time.Millisecond)
+	l.Observe(true)
+	expect(l, "second ramp", ratelimit.AdaptiveModeRamping, 25)
+	time.Sleep(step + 20*time.Millisecond)
+	l.Observe(true)
+	expect(l, "back to base", ratelimit.AdaptiveModeNormal, 30)
+
+	// 4. The worker acquires through the adaptive limiter and observes every attempt but a 404
+	runWorker := func(name string, scrapeErr error) *ratelimit.AdaptiveDetailLimiter {
+		q := &fakeWorkerQueue{rows: []models.DetailScrapeQueue{{
+			ID: 1, Source: "stub", SourcePropertyID: name, DetailURL: "https://stub.example/detail/" + name + "/",
+			Status: models.QueueStatusPending,
+		}}}
+		db, err := openFakeWorkerDB(q)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: dry-run DB: %v", name, err))
+			return nil
+		}
+		source := newStubSource(func(context.Context, string) (*models.Property, error) { return nil, scrapeErr })
+		adaptive := newLimiter()
+		w := scheduler.NewQueueWorkerWithSources(db, scraper.NewScraper(), scraper.NewRegistry(source))
+		w.SetPollInterval(10 * time.Millisecond)
+		w.SetHealthCheck(func(context.Context) bool { return true })
+		w.SetAdaptiveLimiter("stub", adaptive)
+		if err := w.Start(); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: Start: %v", name, err))
+			return nil
+		}
+		for deadline := time.Now().Add(3 * time.Second); time.Now().Before(deadline) && q.row(1).LastError == ""; time.Sleep(5 * time.Millisecond) {
+		}
+		if err := w.Stop(); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: Stop: %v", name, err))
+		}
+		if q.row(1).LastError == "" {
+			problems = append(problems, fmt.Sprintf("%s: item not processed (%s)", name, q.row(1).Status))
+		}
+		if used := source.Limiter().GetUsage(); used != 0 {
+			problems = append(problems, fmt.Sprintf("%s: fixed limiter used %d times (want the adaptive one)", name, used))
+		}
+		if _, ok := w.GetQueueStats()["adaptive_limiters"].(map[string]ratelimit.AdaptiveStatus)["stub"]; !ok {
+			problems = append(problems, fmt.Sprintf("%s: no adaptive_limiters.stub in the queue stats", name))
+		}
+		return adaptive
+	}
+	if failed := runWorker("adapt503", fmt.Errorf("unexpected status code 503")); failed != nil {
+		if status := failed.Status(); status.Observed != 1 || status.Mode != ratelimit.AdaptiveModeSlow {
+			problems = append(problems, fmt.Sprintf("503: observed=%d mode=%s (want 1, slow)", status.Observed, status.Mode))
+		}
+	}
+	if gone := runWorker("adapt404", fmt.Errorf("%w: status code 404", scraper.ErrNotFound)); gone != nil {
+		if status := gone.Status(); status.Observed != 0 || status.Mode != ratelimit.AdaptiveModeNormal {
+			problems = append(problems, fmt.Sprintf("404: observed=%d mode=%s (want 0, normal)", status.Observed, status.Mode))
+		}
+	}
+
+	result.Details = map[string]interface{}{
+		"modes":    modes,
+		"problems": problems,
+	}
+	if len(problems) > 0 {
+		result.Message = fmt.Sprintf("適応型リミッターが不正: %v", problems)
+		log.Printf("  ❌ %v", problems)
+		return result
+	}
+
+	result.Success = true
+	result.Message = "失敗の連続で slow モードに入り、間隔ごとに段階的に基準レートへ戻り、ワーカーの試行結果が反映されることを確認"
+	log.Printf("  ✅ %s", result.Message)
+	return result
+}
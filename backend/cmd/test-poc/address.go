@@ -0,0 +1,83 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"real-estate-portal/internal/models"
+	"real-estate-portal/internal/scraper"
+	"strings"
+	"time"
+)
+
+// Test 42: 住所の抽出（フィクスチャモードのみ）
+// 47都道府県の住所を本文から取り出せること（「東京都内」「東京都知事」は住所ではない）と、
+// __SERVER_SIDE_CONTEXT__ の無いページでパンくずやフッターではなく所在地欄の住所を取ることを確認する
+func testAddressExtraction(s *scraper.Scraper, propertyURL string) TestResult {
+	result := TestResult{
+		TestName:  "住所の抽出",
+		Timestamp: time.Now(),
+	}
+
+	log.Println("\n[Test 42] 住所の抽出テスト...")
+
+	cases := []struct {
+		input string
+		want  string
+	}{
+		{"所在地：北海道札幌市中央区北1条西2丁目", "北海道札幌市中央区北1条西2丁目"},
+		{"京都府京都市中京区烏丸通二条下ル二条殿町538", "京都府京都市中京区烏丸通二条下ル二条殿町538"},
+		{"京都府相楽郡精華町光台1丁目", "京都府相楽郡精華町光台1丁目"},
+		{"沖縄県那覇市おもろまち4丁目", "沖縄県那覇市おもろまち4丁目"},
+		{"鹿児島県鹿児島市中央町 (地図)", "鹿児島県鹿児島市中央町"},
+		{"東京都新宿区西新宿1丁目", "東京都新宿区西新宿1丁目"},
+		// Not addresses: no 市区郡町村 right after the prefecture
+		{"東京都内の人気エリア", ""},
+		{"東京都知事(3)第12345号 大阪府大阪市北区梅田1-1", "大阪府大阪市北区梅田1-1"},
+		{"北海道の賃貸", ""},
+		{"", ""},
+	}
+
+	var problems []string
+	for _, tc := range cases {
+		if got := models.FindAddress(tc.input); got != tc.want {
+			problems = append(problems, fmt.Sprintf("FindAddress(%q)=%q (want %q)", tc.input, got, tc.want))
+		}
+	}
+
+	// Pages without the structured JSON: the 所在地 row, else the first address in the text
+	detailBase := propertyURL[:strings.Index(propertyURL, "/rent/detail/")+len("/rent/detail/")]
+	pages := []struct {
+		id   string
+		want string
+	}{
+		{"address00hokkaido", "北海道札幌市中央区北1条西2丁目"},     // 東京都の賃貸 breadcrumb, 東京都 footer
+		{"address01kyoto", "京都府京都市中京区烏丸通二条下ル二条殿町538"}, // 東京都内 / 東京都知事 lines first
+		{"context02htmlonly", "東京都新宿区西新宿1丁目"},         // plain 所在地 row
+	}
+	for _, tc := range pages {
+		property, err := s.ScrapeProperty(detailBase + tc.id + "/")
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: 取得失敗: %v", tc.id, err))
+			continue
+		}
+		if property.Address != tc.want {
+			problems = append(problems, fmt.Sprintf("%s: address=%q (want %q)", tc.id, property.Address, tc.want))
+		}
+	}
+
+	result.Details = map[string]interface{}{
+		"cases":    len(cases),
+		"pages":    len(pages),
+		"problems": problems,
+	}
+	if len(problems) > 0 {
+		result.Message = fmt.Sprintf("住所の抽出が不正: %v", problems)
+		log.Printf("  ❌ %v", problems)
+		return result
+	}
+
+	result.Success = true
+	result.Message = fmt.Sprintf("%d通りの表記と%dページで住所を正しく抽出", len(cases), len(pages))
+	log.Printf("  ✅ %s", result.Message)
+	return result
+}
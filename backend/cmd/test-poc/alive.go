@@ -0,0 +1,117 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"real-estate-portal/internal/scraper"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Test 47: HEADによる掲載終了チェック（フィクスチャモードのみ）
+// CheckAlive が 404/410 を掲載終了、200 を掲載中と判定し、HEAD が拒否されたら
+// 1バイトの Range GET に切り替えること、詳細ページの枠（DetailLimiter）を使わないことを確認する
+func testCheckAlive() TestResult {
+	result := TestResult{
+		TestName:  "HEADによる掲載終了チェック",
+		Timestamp: time.Now(),
+	}
+
+	log.Println("\n[Test 47] HEADによる掲載終了チェックテスト...")
+
+	var mu sync.Mutex
+	var requests []string // "HEAD /rent/detail/live00/" or "GET /rent/detail/nohead00/ bytes=0-0"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests = append(requests, strings.TrimSpace(r.Method+" "+r.URL.Path+" "+r.Header.Get("Range")))
+		mu.Unlock()
+		switch {
+		case strings.Contains(r.URL.Path, "gone404"):
+			http.NotFound(w, r)
+		case strings.Contains(r.URL.Path, "gone410"):
+			w.WriteHeader(http.StatusGone)
+		case strings.Contains(r.URL.Path, "nohead") && r.Method == http.MethodHead:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		case strings.Contains(r.URL.Path, "busy"):
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case r.Header.Get("Range") != "":
+			w.Header().Set("Content-Range", "bytes 0-0/4096")
+			w.WriteHeader(http.StatusPartialContent)
+			fmt.Fprint(w, "<")
+		default:
+			fmt.Fprint(w, `<html><head><title>ok</title></head><body><h1>ok</h1></body></html>`)
+		}
+	}))
+	defer server.Close()
+
+	s := scraper.NewScraperWithConfig(scraper.ScraperConfig{
+		Timeout:     5 * time.Second,
+		BaseURL:     server.URL,
+		FixtureMode: true,
+	})
+
+	cases := []struct {
+		id        string
+		alive     bool
+		status    int
+		wantErr   bool
+		wantCalls []string
+	}{
+		{"live00", true, http.StatusOK, false, []string{"HEAD /rent/detail/live00/"}},
+		{"gone404", false, http.StatusNotFound, false, []string{"HEAD /rent/detail/gone404/"}},
+		{"gone410", false, http.StatusGone, false, []string{"HEAD /rent/detail/gone410/"}},
+		// HEAD refused: one-byte ranged GET
+		{"nohead00", true, http.StatusPartialContent, false, []string{"HEAD /rent/detail/nohead00/", "GET /rent/detail/nohead00/ bytes=0-0"}},
+		// 503 says nothing about the listing
+		{"busy00", false, http.StatusServiceUnavailable, true, []string{"HEAD /rent/detail/busy00/"}},
+	}
+
+	detailBefore, aliveBefore := scraper.DetailLimiter.GetUsage(), scraper.AliveLimiter.GetUsage()
+	var problems []string
+	for _, tc := range cases {
+		mu.Lock()
+		requests = nil
+		mu.Unlock()
+
+		alive, status, err := s.CheckAlive(server.URL + "/rent/detail/" + tc.id + "/")
+		if alive != tc.alive || status != tc.status || (err != nil) != tc.wantErr {
+			problems = append(problems, fmt.Sprintf("%s: alive=%v status=%d err=%v (want alive=%v status=%d err=%v)",
+				tc.id, alive, status, err, tc.alive, tc.status, tc.wantErr))
+		}
+		mu.Lock()
+		got := append([]string(nil), requests...)
+		mu.Unlock()
+		if strings.Join(got, ", ") != strings.Join(tc.wantCalls, ", ") {
+			problems = append(problems, fmt.Sprintf("%s: requests %v (want %v)", tc.id, got, tc.wantCalls))
+		}
+	}
+	// The 503 counted one breaker failure; a success resets the consecutive run for later tests
+	if _, _, err := s.CheckAlive(server.URL + "/rent/detail/live01reset/"); err != nil {
+		log.Printf("  reset request failed: %v", err)
+	}
+
+	if used := scraper.DetailLimiter.GetUsage() - detailBefore; used != 0 {
+		problems = append(problems, fmt.Sprintf("detail budget used by %d alive checks", used))
+	}
+	if used := scraper.AliveLimiter.GetUsage() - aliveBefore; used != len(cases)+1 {
+		problems = append(problems, fmt.Sprintf("alive limiter counted %d checks (want %d)", used, len(cases)+1))
+	}
+
+	result.Details = map[string]interface{}{
+		"cases":    len(cases),
+		"problems": problems,
+	}
+	if len(problems) > 0 {
+		result.Message = fmt.Sprintf("掲載終了チェックが不正: %v", problems)
+		log.Printf("  ❌ %v", problems)
+		return result
+	}
+
+	result.Success = true
+	result.Message = fmt.Sprintf("%d件のHEADチェックを正しく判定（404/410は掲載終了、HEAD拒否はRange GET、詳細枠は未使用）", len(cases))
+	log.Printf("  ✅ %s", result.Message)
+	return result
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"real-estate-portal/internal/batch"
+	"time"
+)
+
+// Test 9: バッチ結果の順序保持（オフライン）
+// 並列実行で完了順がばらばらでも、成功・失敗が混在した結果が入力順に並ぶことを確認する
+func testBatchOrdering() TestResult {
+	result := TestResult{
+		TestName:  "バッチ結果の順序保持",
+		Timestamp: time.Now(),
+	}
+
+	log.Println("\n[Test 9] バッチ結果の順序保持テスト...")
+
+	urls := make([]string, 8)
+	for i := range urls {
+		urls[i] = fmt.Sprintf("https://example.com/rent/detail/%02d/", i)
+	}
+
+	// Earlier inputs finish later; every third input fails
+	results := batch.Run(len(urls), 4, func(i int) batch.Result {
+		time.Sleep(time.Duration(len(urls)-i) * 5 * time.Millisecond)
+		if i%3 == 1 {
+			return batch.Failed(urls[i], fmt.Errorf("status code 404"))
+		}
+		return batch.OK(urls[i], fmt.Sprintf("id-%02d", i))
+	})
+
+	var problems []string
+	for i, r := range results {
+		if r.URL != urls[i] {
+			problems = append(problems, fmt.Sprintf("index %d: url=%s", i, r.URL))
+			continue
+		}
+		wantStatus := batch.StatusOK
+		if i%3 == 1 {
+			wantStatus = batch.StatusError
+		}
+		if r.Status != wantStatus {
+			problems = append(problems, fmt.Sprintf("index %d: status=%s", i, r.Status))
+		}
+		if r.Status == batch.StatusOK && r.PropertyID != fmt.Sprintf("id-%02d", i) {
+			problems = append(problems, fmt.Sprintf("index %d: property_id=%s", i, r.PropertyID))
+		}
+	}
+
+	summary := batch.Summarize(results)
+	if summary.OK != 5 || summary.Failed != 3 {
+		problems = append(problems, fmt.Sprintf("summary ok=%d failed=%d", summary.OK, summary.Failed))
+	}
+
+	result.Details = map[string]interface{}{
+		"results":  results,
+		"problems": problems,
+	}
+	if len(problems) > 0 {
+		result.Message = fmt.Sprintf("順序または件数が不正: %v", problems)
+		log.Printf("  ❌ %v", problems)
+		return result
+	}
+
+	result.Success = true
+	result.Message = fmt.Sprintf("%d件（成功%d/失敗%d）が入力順で返却", summary.Total, summary.OK, summary.Failed)
+	log.Printf("  ✅ 入力順を保持")
+	return result
+}
@@ -0,0 +1,192 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"real-estate-portal/internal/models"
+	"real-estate-portal/internal/ratelimit"
+	"real-estate-portal/internal/scheduler"
+	"real-estate-portal/internal/scraper"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Test 87: バッチ取得の排他と時間枠（オフライン）
+// ワーカーが項目を「読んだときのステータスのままなら processing にする」条件付き UPDATE で取り、
+// 先に別のワーカーに取られた項目は飛ばして次の項目を取ること、ソースの DetailLimiter の1時間の枠が
+// 残り1件ならバッチの途中でも1件だけ取って残りを pending のままにすること（枠を使い切ったソースしか
+// 無ければティックを飛ばす）、max_concurrency=1 でも batch_size 件を1ティックで取って順に処理することを
+// 確認する
+func testBatchClaim() TestResult {
+	result := TestResult{
+		TestName:  "バッチ取得の排他と時間枠",
+		Timestamp: time.Now(),
+	}
+
+	log.Println("\n[Test 87] バッチ取得の排他と時間枠テスト...")
+
+	var problems []string
+	pending := func(ids ...int64) []models.DetailScrapeQueue {
+		rows := make([]models.DetailScrapeQueue, 0, len(ids))
+		for _, id := range ids {
+			pid := fmt.Sprintf("batch%02d", id)
+			rows = append(rows, models.DetailScrapeQueue{ID: id, Source: "stub", SourcePropertyID: pid,
+				DetailURL: "https://stub.example/detail/" + pid + "/", Status: models.QueueStatusPending})
+		}
+		return rows
+	}
+	var mu sync.Mutex
+	var scraped []string
+	scrape := func(_ context.Context, url string) (*models.Property, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		scraped = append(scraped, strings.TrimSuffix(url[strings.LastIndex(strings.TrimSuffix(url, "/"), "/")+1:], "/"))
+		return nil, fmt.Errorf("%w: status code 404", scraper.ErrNotFound)
+	}
+	runWorker := func(db *gorm.DB, source *stubSource, slots int, done func() bool) {
+		w := scheduler.NewQueueWorkerWithSources(db, scraper.NewScraper(), scraper.NewRegistry(source))
+		w.SetPollInterval(10 * time.Millisecond)
+		w.SetConcurrency(slots, 3)
+		w.SetHealthCheck(func(context.Context) bool { return true })
+		if err := w.Start(); err != nil {
+			problems = append(problems, fmt.Sprintf("Start: %v", err))
+			return
+		}
+		for deadline := time.Now().Add(3 * time.Second); time.Now().Before(deadline) && !done(); time.Sleep(5 * time.Millisecond) {
+		}
+		time.Sleep(50 * time.Millisecond) // a few more ticks
+		if err := w.Stop(); err != nil {
+			problems = append(problems, fmt.Sprintf("Stop: %v", err))
+		}
+	}
+
+	capture := &logCapture{}
+	out := log.Writer()
+	log.SetOutput(io.MultiWriter(out, capture))
+	defer log.SetOutput(out)
+
+	// 1. Another worker takes item 1 between the lookup and the claim: this one moves on to item 2
+	q := &fakeWorkerQueue{rows: pending(1, 2)}
+	db, err := openFakeWorkerDB(q)
+	var claimSQL []string
+	if err == nil {
+		err = db.Callback().Update().Before("gorm:update").Register("poc:other_worker", func(tx *gorm.DB) {
+			if set, ok := tx.Statement.Dest.(map[string]interface{}); !ok || set["attempts"] == nil {
+				return // not a claim
+			}
+			q.mu.Lock()
+			defer q.mu.Unlock()
+			if q.rows[0].Status == models.QueueStatusPending {
+				q.rows[0].Status = models.QueueStatusProcessing // the other worker's claim
+			}
+		})
+	}
+	if err == nil {
+		err = db.Callback().Update().After("gorm:update").Register("poc:claim_sql", func(tx *gorm.DB) {
+			if sql := tx.Dialector.Explain(tx.Statement.SQL.String(), tx.Statement.Vars...); strings.Contains(sql, "attempts + 1") {
+				claimSQL = append(claimSQL, sql)
+			}
+		})
+	}
+	if err != nil {
+		result.Message = fmt.Sprintf("dry-run DB: %v", err)
+		log.Printf("  ❌ %s", result.Message)
+		return result
+	}
+	runWorker(db, newStubSource(scrape), 3, func() bool {
+		return q.row(2).Status != models.QueueStatusPending && q.row(2).Status != models.QueueStatusProcessing
+	})
+	if fmt.Sprint(scraped) != "[batch02]" {
+		problems = append(problems, fmt.Sprintf("scraped %v (want [batch02]: batch01 belongs to the other worker)", scraped))
+	}
+	if row := q.row(1); row.Status != models.QueueStatusProcessing || row.Attempts != 0 || len(q.history[1]) != 0 {
+		problems = append(problems, fmt.Sprintf("the other worker's item: %s, %d attempts, history %v", row.Status, row.Attempts, q.history[1]))
+	}
+	if row := q.row(2); row.Status != models.QueueStatusPermanentFail || row.Attempts != 1 {
+		problems = append(problems, fmt.Sprintf("item 2: %s after %d attempts (want permanent_fail, 1)", row.Status, row.Attempts))
+	}
+	if capture.count("id=1 was claimed by another worker") == 0 {
+		problems = append(problems, "the lost claim was not logged")
+	}
+	if len(claimSQL) == 0 || !strings.Contains(claimSQL[0], "`status`='processing'") || !strings.Contains(claimSQL[0], "WHERE id = 1 AND status = 'pending'") {
+		problems = append(problems, fmt.Sprintf("claim SQL %v (want a conditional UPDATE)", claimSQL))
+	}
+
+	// 2. One request left in the hour: one item of a batch of three is taken, two stay pending
+	mu.Lock()
+	scraped = nil
+	mu.Unlock()
+	q = &fakeWorkerQueue{rows: pending(11, 12, 13)}
+	db, err = openFakeWorkerDB(q)
+	if err != nil {
+		result.Message = fmt.Sprintf("dry-run DB: %v", err)
+		log.Printf("  ❌ %s", result.Message)
+		return result
+	}
+	limited := &stubSource{limiter: ratelimit.NewSourceDetailLimiter("stub-batch", 2), scrape: scrape}
+	limited.limiter.Acquire("poc")
+	runWorker(db, limited, 3, func() bool { return len(q.history[11]) >= 2 })
+	if fmt.Sprint(scraped) != "[batch11]" {
+		problems = append(problems, fmt.Sprintf("scraped %v with one request left (want [batch11])", scraped))
+	}
+	for _, id := range []int64{12, 13} {
+		if row := q.row(id); row.Status != models.QueueStatusPending || row.Attempts != 0 {
+			problems = append(problems, fmt.Sprintf("item %d: %s, %d attempts (want left pending)", id, row.Status, row.Attempts))
+		}
+	}
+	if capture.count("Hourly detail budget used up or cooling down for all sources") == 0 {
+		problems = append(problems, "ticks with the budget used up were not skipped")
+	}
+
+	// 3. One slot, a batch of three: all three are claimed on the first tick, then scraped in order
+	mu.Lock()
+	scraped = nil
+	mu.Unlock()
+	q = &fakeWorkerQueue{rows: pending(21, 22, 23)}
+	db, err = openFakeWorkerDB(q)
+	if err != nil {
+		result.Message = fmt.Sprintf("dry-run DB: %v", err)
+		log.Printf("  ❌ %s", result.Message)
+		return result
+	}
+	var claimedWithFirst []int64
+	serial := newStubSource(func(ctx context.Context, url string) (*models.Property, error) {
+		if strings.Contains(url, "batch21") {
+			for _, id := range []int64{22, 23} {
+				if q.row(id).Status == models.QueueStatusProcessing {
+					mu.Lock()
+					claimedWithFirst = append(claimedWithFirst, id)
+					mu.Unlock()
+				}
+			}
+		}
+		return scrape(ctx, url)
+	})
+	runWorker(db, serial, 1, func() bool { return q.row(23).Status == models.QueueStatusPermanentFail })
+	if fmt.Sprint(scraped) != "[batch21 batch22 batch23]" {
+		problems = append(problems, fmt.Sprintf("one slot: scraped %v (want [batch21 batch22 batch23])", scraped))
+	}
+	if fmt.Sprint(claimedWithFirst) != "[22 23]" {
+		problems = append(problems, fmt.Sprintf("one slot: claimed with the first item %v (want [22 23]: the whole batch)", claimedWithFirst))
+	}
+
+	result.Details = map[string]interface{}{
+		"claim_sql": claimSQL,
+		"problems":  problems,
+	}
+	if len(problems) > 0 {
+		result.Message = fmt.Sprintf("バッチ取得が不正: %v", problems)
+		log.Printf("  ❌ %v", problems)
+		return result
+	}
+
+	result.Success = true
+	result.Message = "条件付き UPDATE で取るため他のワーカーの項目を二重に取らず、1時間の枠が尽きたら残りを pending のままにし、1スロットでもバッチ全件を取って順に処理することを確認"
+	log.Printf("  ✅ %s", result.Message)
+	return result
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"real-estate-portal/internal/database"
+	"real-estate-portal/internal/models"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// アクティブ物件取得のベンチマーク（要MySQL、-bench-active で実行）
+// 合成データ（既定10万件、うち3割がactive）で、全件取得・差分用の列限定取得・
+// スケジューラ用のストリーミング取得の時間とメモリを比較する。
+// 専用DB（BENCH_DB_NAME、既定 realestate_bench）を使うこと。本番DBには絶対に向けない。
+
+const (
+	benchRows        = 100000
+	benchActiveRatio = 0.3
+)
+
+func runActivePropertiesBenchmark() {
+	dbName := getEnvDefault("BENCH_DB_NAME", "realestate_bench")
+	gdb, err := database.NewGormDB(
+		getEnvDefault("DB_HOST", "127.0.0.1"),
+		getEnvDefault("DB_PORT", "3306"),
+		getEnvDefault("DB_USER", "realestate_user"),
+		getEnvDefault("DB_PASSWORD", "realestate_pass"),
+		dbName,
+	)
+	if err != nil {
+		log.Fatalf("Benchmark: failed to connect to %s: %v", dbName, err)
+	}
+	defer gdb.Close()
+
+	if err := gdb.InitSchema(); err != nil {
+		log.Fatalf("Benchmark: failed to init schema: %v", err)
+	}
+
+	quiet := gdb.DB().Session(&gorm.Session{Logger: logger.Default.LogMode(logger.Silent)})
+	if err := seedBenchProperties(quiet, benchRows); err != nil {
+		log.Fatalf("Benchmark: failed to seed: %v", err)
+	}
+	db := database.NewGormDBFromDB(quiet)
+
+	cases := []struct {
+		name string
+		fn   func() (int, error)
+	}{
+		{"GetActiveProperties (all columns, ordered)", func() (int, error) {
+			props, err := db.GetActiveProperties()
+			return len(props), err
+		}},
+		{"GetActivePropertiesForDiff (5 columns)", func() (int, error) {
+			props, err := db.GetActivePropertiesForDiff()
+			return len(props), err
+		}},
+		{"StreamActiveProperties (scheduler, stop at 100)", func() (int, error) {
+			n := 0
+			err := db.StreamActiveProperties(500, []string{"id", "source", "source_property_id", "detail_url"}, func(batch []models.Property) error {
+				n += len(batch)
+				if n >= 100 {
+					return database.ErrStopStream
+				}
+				return nil
+			})
+			return n, err
+		}},
+		{"StreamActiveProperties (full scan, 5 columns)", func() (int, error) {
+			n := 0
+			err := db.StreamActiveProperties(1000, []string{"id", "title", "rent", "image_url", "detail_url"}, func(batch []models.Property) error {
+				n += len(batch)
+				return nil
+			})
+			return n, err
+		}},
+	}
+
+	log.Printf("Benchmark: %d rows (%.0f%% active) in %s", benchRows, benchActiveRatio*100, dbName)
+	log.Printf("%-50s %8s %14s %14s %12s", "case", "rows", "ms/op", "MB/op", "allocs/op")
+	for _, c := range cases {
+		rows := 0
+		var runErr error
+		r := testing.Benchmark(func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				rows, runErr = c.fn()
+			}
+		})
+		if runErr != nil {
+			log.Printf("%-50s error: %v", c.name, runErr)
+			continue
+		}
+		log.Printf("%-50s %8d %14.1f %14.1f %12d", c.name, rows,
+			float64(r.NsPerOp())/float64(time.Millisecond),
+			float64(r.AllocedBytesPerOp())/(1<<20),
+			r.AllocsPerOp())
+	}
+}
+
+// seedBenchProperties fills properties up to n synthetic rows (existing rows are reused)
+func seedBenchProperties(db *gorm.DB, n int) error {
+	var existing int64
+	if err := db.Model(&models.Property{}).Where("source = ?", "bench").Count(&existing).Error; err != nil {
+		return err
+	}
+	if int(existing) >= n {
+		return nil
+	}
+
+	log.Printf("Benchmark: seeding %d synthetic properties...", n-int(existing))
+	notes := strings.Repeat("初期費用詳細 ", 60) // realistic row width
+	now := time.Now()
+	batch := make([]models.Property, 0, 1000)
+	for i := int(existing); i < n; i++ {
+		rent := 50000 + i%150000
+		status := models.PropertyStatusRemoved
+		if float64(i%100) < benchActiveRatio*100 {
+			status = models.PropertyStatusActive
+		}
+		batch = append(batch, models.Property{
+			ID:               fmt.Sprintf("bench%027d", i),
+			Source:           "bench",
+			SourcePropertyID: fmt.Sprintf("bench-%d", i),
+			DetailURL:        fmt.Sprintf("https://example.com/rent/detail/bench-%d/", i),
+			Title:            fmt.Sprintf("ベンチマーク物件 %d", i),
+			ImageURL:         fmt.Sprintf("https://example.com/img/%d.jpg", i),
+			Rent:             &rent,
+			Address:          "東京都渋谷区",
+			Notes:            notes,
+			Status:           status,
+			FetchedAt:        now,
+		})
+		if len(batch) == cap(batch) {
+			if err := db.Create(&batch).Error; err != nil {
+				return err
+			}
+			batch = batch[:0]
+		}
+	}
+	if len(batch) > 0 {
+		return db.Create(&batch).Error
+	}
+	return nil
+}
+
+func getEnvDefault(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"real-estate-portal/internal/scraper"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// 住所抽出のベンチマーク（ネットワーク不要、-bench-address で実行）
+// おすすめ物件一覧やフッターで要素数の多い合成詳細ページ（__SERVER_SIDE_CONTEXT__ なし）で、
+// 旧方式（全要素の Text() を走査）と現在の ParsePropertyHTML 全体の時間とメモリを比較する。
+// 旧方式の住所抽出だけのコストは「legacy − parse only」で、以前の ParsePropertyHTML にはこれが上乗せされていた。
+
+const benchAddressCards = 1500
+
+func runAddressBenchmark() {
+	withRow := benchAddressPage(true)
+	textOnly := benchAddressPage(false)
+	pageURL := "https://realestate.yahoo.co.jp/rent/detail/benchaddress/"
+	s := scraper.NewScraper()
+
+	cases := []struct {
+		name string
+		fn   func() (string, error)
+	}{
+		{"goquery parse only (baseline)", func() (string, error) {
+			_, err := goquery.NewDocumentFromReader(strings.NewReader(withRow))
+			return "", err
+		}},
+		{"legacy: parse + scan every element (5 prefectures)", func() (string, error) {
+			doc, err := goquery.NewDocumentFromReader(strings.NewReader(withRow))
+			if err != nil {
+				return "", err
+			}
+			return legacyExtractAddress(doc), nil
+		}},
+		{"ParsePropertyHTML (所在地 row)", func() (string, error) {
+			p, err := s.ParsePropertyHTML(context.Background(), withRow, pageURL)
+			if err != nil {
+				return "", err
+			}
+			return p.Address, nil
+		}},
+		{"ParsePropertyHTML (text only)", func() (string, error) {
+			p, err := s.ParsePropertyHTML(context.Background(), textOnly, pageURL)
+			if err != nil {
+				return "", err
+			}
+			return p.Address, nil
+		}},
+	}
+
+	log.Printf("Benchmark: synthetic detail page, %d cards, %d KB", benchAddressCards, len(withRow)/1024)
+	log.Printf("%-52s %14s %14s %12s  %s", "case", "ms/op", "MB/op", "allocs/op", "address")
+	for _, c := range cases {
+		address := ""
+		var runErr error
+		// The parser logs every field it extracts; keep the table readable
+		log.SetOutput(io.Discard)
+		r := testing.Benchmark(func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				address, runErr = c.fn()
+			}
+		})
+		log.SetOutput(os.Stderr)
+		if runErr != nil {
+			log.Printf("%-52s error: %v", c.name, runErr)
+			continue
+		}
+		log.Printf("%-52s %14.2f %14.2f %12d  %q", c.name,
+			float64(r.NsPerOp())/float64(time.Millisecond),
+			float64(r.AllocedBytesPerOp())/(1<<20),
+			r.AllocsPerOp(), address)
+	}
+}
+
+// benchAddressPage builds a detail page without the embedded JSON: a long list of recommended
+// properties, the detail table (with or without the 所在地 row) and a footer with the agency licence
+func benchAddressPage(withRow bool) string {
+	var b strings.Builder
+	b.WriteString(`<!DOCTYPE html><html lang="ja"><head><meta charset="UTF-8"><title>ベンチ物件 101</title></head><body>`)
+	b.WriteString(`<header><nav><a href="/">東京都の賃貸</a> &gt; <a href="/hokkaido/">北海道の賃貸</a></nav></header>`)
+	b.WriteString(`<h1>ベンチ物件 101</h1><ul class="recommend">`)
+	for i := 0; i < benchAddressCards; i++ {
+		fmt.Fprintf(&b, "\n"+`<li><div class="card"><div class="card__body"><p class="card__title"><span>おすすめ物件 %d</span></p>`+
+			`<p class="card__price"><span>%d.5万円</span> <span>1LDK</span></p></div></div></li>`, i, 5+i%20)
+	}
+	b.WriteString("\n" + `</ul><table class="DetailTable">` + "\n")
+	b.WriteString(`<tr><th>賃料</th><td>8.2万円</td></tr><tr><th>間取り</th><td>1LDK</td></tr>`)
+	if withRow {
+		b.WriteString(`<tr><th>所在地</th><td>北海道札幌市中央区北1条西2丁目<br><a href="#map">地図を見る</a></td></tr>`)
+	} else {
+		b.WriteString(`</table>` + "\n" + `<p>所在地：北海道札幌市中央区北1条西2丁目</p>` + "\n" + `<table>`)
+	}
+	b.WriteString("\n" + `</table><footer><p>株式会社サンプル不動産 東京都知事(3)第12345号</p></footer></body></html>`)
+	return b.String()
+}
+
+// legacyExtractAddress is the previous extractAddress, kept only as the benchmark baseline:
+// Text() of every element in the page, five hard-coded prefectures
+func legacyExtractAddress(doc *goquery.Document) string {
+	address := ""
+	doc.Find("*").Each(func(i int, s *goquery.Selection) {
+		text := s.Text()
+		if strings.Contains(text, "東京都") || strings.Contains(text, "大阪府") ||
+			strings.Contains(text, "神奈川県") || strings.Contains(text, "千葉県") ||
+			strings.Contains(text, "埼玉県") {
+			re := regexp.MustCompile(`(東京都|大阪府|神奈川県|千葉県|埼玉県)[^\n]+`)
+			matches := re.FindStringSubmatch(text)
+			if len(matches) > 0 && len(address) == 0 {
+				address = strings.TrimSpace(matches[0])
+				runes := []rune(address)
+				if len(runes) > 50 {
+					address = string(runes[:50])
+				}
+			}
+		}
+	})
+	return address
+}
@@ -0,0 +1,219 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"real-estate-portal/internal/models"
+	"real-estate-portal/internal/ratelimit"
+	"real-estate-portal/internal/scheduler"
+	"real-estate-portal/internal/scraper"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// fakeScrapingState is the scraping_state table: one row (or none) read by First and written by Save
+type fakeScrapingState struct {
+	mu  sync.Mutex
+	row *models.ScrapingState
+}
+
+func (f *fakeScrapingState) get() *models.ScrapingState {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.row == nil {
+		return nil
+	}
+	row := *f.row
+	return &row
+}
+
+func (f *fakeScrapingState) set(row *models.ScrapingState) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.row = row
+}
+
+func (f *fakeScrapingState) register(db *gorm.DB) error {
+	if err := db.Callback().Query().After("gorm:query").Register("poc:state_query", func(tx *gorm.DB) {
+		if !f.answer(tx) && tx.Statement.RaiseErrorOnNotFound && tx.Error == nil {
+			tx.AddError(gorm.ErrRecordNotFound)
+		}
+	}); err != nil {
+		return err
+	}
+	return f.registerWrites(db)
+}
+
+// answer fills a First on scraping_state from the row; false for any other query
+func (f *fakeScrapingState) answer(tx *gorm.DB) bool {
+	dest, ok := tx.Statement.Dest.(*models.ScrapingState)
+	if !ok {
+		return false
+	}
+	if row := f.get(); row != nil {
+		*dest = *row
+		tx.RowsAffected = 1
+		return true
+	}
+	tx.AddError(gorm.ErrRecordNotFound)
+	return true
+}
+
+// registerWrites keeps the row saved through db
+func (f *fakeScrapingState) registerWrites(db *gorm.DB) error {
+	save := func(tx *gorm.DB) {
+		if dest, ok := tx.Statement.Dest.(*models.ScrapingState); ok {
+			row := *dest
+			f.set(&row)
+			tx.RowsAffected = 1
+		}
+	}
+	if err := db.Callback().Update().After("gorm:update").Register("poc:state_save", save); err != nil {
+		return err
+	}
+	return db.Callback().Create().After("gorm:create").Register("poc:state_insert", save)
+}
+
+// Test 81: WAFブロック状態の永続化（オフライン）
+// サーキットブレーカーが開くと scraping_state にブロック（理由・解除時刻）が保存され、回復（成功・管理者リセット）で
+// 消えること、再起動を模した新しいブレーカーとワーカーが保存済みのブロックを読み込んで解除時刻まで CanProceed を
+// 拒否・処理を止めること、期限切れのブロックは無視されることを確認する
+func testBreakerStatePersist() TestResult {
+	result := TestResult{
+		TestName:  "WAFブロック状態の永続化",
+		Timestamp: time.Now(),
+	}
+
+	log.Println("\n[Test 81] WAFブロック状態の永続化テスト...")
+
+	state := &fakeScrapingState{}
+	db, err := openDryRunDB()
+	if err == nil {
+		err = state.register(db)
+	}
+	if err != nil {
+		result.Message = fmt.Sprintf("dry-run DB: %v", err)
+		log.Printf("  ❌ %s", result.Message)
+		return result
+	}
+
+	var problems []string
+	newBreaker := func() *scraper.CircuitBreaker { return scraper.NewCircuitBreaker(8, time.Hour) }
+	expectBlocked := func(name string, want bool) *models.ScrapingState {
+		row := state.get()
+		if blocked := row != nil && row.IsBlocked; blocked != want {
+			problems = append(problems, fmt.Sprintf("%s: scraping_state %+v (want blocked=%v)", name, row, want))
+		}
+		return row
+	}
+
+	// 1. No row yet: the restored breaker lets requests through
+	breaker := newBreaker()
+	scheduler.RestoreBreakerState(db, breaker)
+	if !breaker.CanProceed() {
+		problems = append(problems, "breaker closed without a persisted block refuses requests")
+	}
+
+	// 2. Two block pages in a row open it: the block is written until the retry time
+	breaker.RecordBlock(200)
+	breaker.RecordBlock(200)
+	if row := expectBlocked("opened", true); row != nil && row.IsBlocked {
+		if row.BlockedUntil == nil || row.BlockedUntil.Sub(breaker.RetryAt()).Abs() > time.Second ||
+			!strings.Contains(row.BlockedReason, "consecutive") {
+			problems = append(problems, fmt.Sprintf("opened: until=%v reason=%q (want the retry time %v)", row.BlockedUntil, row.BlockedReason, breaker.RetryAt()))
+		}
+	}
+
+	// 3. Restart: a new breaker stays open until the persisted time
+	restarted := newBreaker()
+	scheduler.RestoreBreakerState(db, restarted)
+	if restarted.CanProceed() {
+		problems = append(problems, "restarted breaker lets requests through during the persisted block")
+	}
+	if row := state.get(); row != nil && row.BlockedUntil != nil && !restarted.RetryAt().Equal(*row.BlockedUntil) {
+		problems = append(problems, fmt.Sprintf("restarted breaker retries at %v (want %v)", restarted.RetryAt(), *row.BlockedUntil))
+	}
+
+	// 4. An admin reset clears the persisted block
+	restarted.Reset()
+	expectBlocked("reset", false)
+
+	// 5. A short persisted block: refused until it ends, the half-open success clears the row
+	until := time.Now().Add(200 * time.Millisecond)
+	state.set(&models.ScrapingState{ID: 1, IsBlocked: true, BlockedUntil: &until, BlockedReason: "waf"})
+	short := newBreaker()
+	scheduler.RestoreBreakerState(db, short)
+	if short.CanProceed() {
+		problems = append(problems, "short block: refused nothing before it ended")
+	}
+	time.Sleep(250 * time.Millisecond)
+	if !short.CanProceed() {
+		problems = append(problems, "short block: still refused after it ended")
+	}
+	short.RecordSuccess()
+	expectBlocked("recovered", false)
+
+	// 6. An expired block is ignored
+	past := time.Now().Add(-time.Minute)
+	state.set(&models.ScrapingState{ID: 1, IsBlocked: true, BlockedUntil: &past})
+	expired := newBreaker()
+	scheduler.RestoreBreakerState(db, expired)
+	if !expired.CanProceed() {
+		problems = append(problems, "expired block still refuses requests")
+	}
+
+	// 7. Worker Start: the persisted block pauses processing and reopens the worker's breaker
+	blockedUntil := time.Now().Add(time.Hour)
+	state.set(&models.ScrapingState{ID: 1, IsBlocked: true, BlockedUntil: &blockedUntil, BlockedReason: "waf", FailureCount: 1})
+	workerBreaker := newBreaker()
+	yahoo := scraper.NewScraperWithLimiter(scraper.DefaultScraperConfig(), ratelimit.NewYahooLimiter(1, time.Millisecond, 0), workerBreaker)
+	w := scheduler.NewQueueWorkerWithScraper(db, yahoo)
+	w.SetPollInterval(10 * time.Millisecond)
+	var checks int
+	var checksMu sync.Mutex
+	w.SetHealthCheck(func(context.Context) bool {
+		checksMu.Lock()
+		defer checksMu.Unlock()
+		checks++
+		return true
+	})
+	if err := w.Start(); err != nil {
+		problems = append(problems, fmt.Sprintf("worker Start: %v", err))
+	}
+	var cooldown scheduler.HealthCooldownStatus
+	for deadline := time.Now().Add(time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		if cooldown = w.HealthCooldown(); cooldown.Active {
+			break
+		}
+	}
+	time.Sleep(50 * time.Millisecond)
+	if err := w.Stop(); err != nil {
+		problems = append(problems, fmt.Sprintf("worker Stop: %v", err))
+	}
+	checksMu.Lock()
+	if !cooldown.Active || cooldown.Until == nil || !cooldown.Until.Equal(blockedUntil) || checks != 0 {
+		problems = append(problems, fmt.Sprintf("worker: cooldown %+v, %d health checks (want paused until %v, none)", cooldown, checks, blockedUntil))
+	}
+	checksMu.Unlock()
+	if workerBreaker.CanProceed() {
+		problems = append(problems, "worker breaker lets requests through during the persisted block")
+	}
+
+	result.Details = map[string]interface{}{
+		"problems": problems,
+	}
+	if len(problems) > 0 {
+		result.Message = fmt.Sprintf("ブロック状態の永続化が不正: %v", problems)
+		log.Printf("  ❌ %v", problems)
+		return result
+	}
+
+	result.Success = true
+	result.Message = "ブレーカーのブロックが scraping_state に保存・回復で解除され、再起動後も解除時刻まで止まることを確認"
+	log.Printf("  ✅ %s", result.Message)
+	return result
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"real-estate-portal/internal/errtext"
+	"real-estate-portal/internal/scraper"
+	"strings"
+	"time"
+)
+
+// Test 66: 建物ページ（フィクスチャモードのみ）
+// 複数の空室を並べた建物ページ（/rent/building/ と、リスティングを持たず部屋表だけの詳細 URL）が部屋ごとの
+// 物件に展開されること、ScrapeProperty は ErrBuildingPage（コード building_page）で部屋の URL を返すこと、
+// 通常の詳細ページは建物ページと判定されないことを確認する
+func testBuildingPages(propertyURL string) TestResult {
+	result := TestResult{
+		TestName:  "建物ページ",
+		Timestamp: time.Now(),
+	}
+
+	log.Println("\n[Test 66] 建物ページテスト...")
+
+	baseURL := propertyURL[:strings.Index(propertyURL, "/rent/detail/")]
+	s := newFixtureScraper(baseURL)
+
+	var problems []string
+
+	// 1. /rent/building/ page: three units with their own IDs, rent, plan, area and floor
+	buildingURL := baseURL + "/rent/building/maison01/"
+	if !scraper.IsBuildingURL(buildingURL) || scraper.IsBuildingURL(propertyURL) {
+		problems = append(problems, "IsBuildingURL does not tell building and detail URLs apart")
+	}
+	units, err := s.ScrapeBuilding(buildingURL)
+	if err != nil {
+		problems = append(problems, fmt.Sprintf("building: %v", err))
+	}
+	want := []struct {
+		id    string
+		rent  int
+		plan  string
+		area  float64
+		floor int
+	}{
+		{"0000bb01c2d3e4f5a6b7c8d9e0f1a2b3c4d5e6f7a8b9", 98000, "1K", 25.3, 2},
+		{"0000bb02c2d3e4f5a6b7c8d9e0f1a2b3c4d5e6f7a8b9", 124000, "1LDK", 40.12, 5},
+		{"0000bb03c2d3e4f5a6b7c8d9e0f1a2b3c4d5e6f7a8b9", 150000, "2LDK", 55.5, 7},
+	}
+	if err == nil && len(units) != len(want) {
+		problems = append(problems, fmt.Sprintf("building: %d units (want %d)", len(units), len(want)))
+	} else if err == nil {
+		ids := make(map[string]bool)
+		for i, unit := range units {
+			w := want[i]
+			if unit.SourcePropertyID != w.id || unit.DetailURL != baseURL+"/rent/detail/"+w.id+"/" {
+				problems = append(problems, fmt.Sprintf("unit %d: id=%s url=%s", i, unit.SourcePropertyID, unit.DetailURL))
+			}
+			if !intPtrEq(unit.Rent, intp(w.rent)) {
+				problems = append(problems, fmt.Sprintf("unit %d: rent=%s (want %d)", i, fmtIntPtr(unit.Rent), w.rent))
+			}
+			if unit.FloorPlan != w.plan {
+				problems = append(problems, fmt.Sprintf("unit %d: plan=%q (want %q)", i, unit.FloorPlan, w.plan))
+			}
+			if unit.Area == nil || *unit.Area != w.area {
+				problems = append(problems, fmt.Sprintf("unit %d: area=%v (want %v)", i, unit.Area, w.area))
+			}
+			if !intPtrEq(unit.Floor, intp(w.floor)) {
+				problems = append(problems, fmt.Sprintf("unit %d: floor=%s (want %d)", i, fmtIntPtr(unit.Floor), w.floor))
+			}
+			if unit.BuildingName != "メゾン代々木" || !strings.Contains(unit.Address, "渋谷区代々木") {
+				problems = append(problems, fmt.Sprintf("unit %d: building=%q address=%q", i, unit.BuildingName, unit.Address))
+			}
+			if unit.ID == "" || ids[unit.ID] {
+				problems = append(problems, fmt.Sprintf("unit %d: internal id %q missing or repeated", i, unit.ID))
+			}
+			ids[unit.ID] = true
+		}
+	}
+
+	// 2. ScrapeProperty reports the building page instead of saving it as one listing
+	_, err = s.ScrapeProperty(buildingURL)
+	var building *scraper.BuildingPageError
+	if !errors.Is(err, scraper.ErrBuildingPage) || !errors.As(err, &building) {
+		problems = append(problems, fmt.Sprintf("ScrapeProperty(building): err=%v (want ErrBuildingPage)", err))
+	} else if len(building.UnitURLs()) != len(want) {
+		problems = append(problems, fmt.Sprintf("ScrapeProperty(building): %d unit URLs", len(building.UnitURLs())))
+	}
+	if code, _ := errtext.FromError(err); code != errtext.CodeBuilding {
+		problems = append(problems, fmt.Sprintf("building: code=%s (want %s)", code, errtext.CodeBuilding))
+	}
+
+	// 3. A detail URL that only shows a unit table is a building page too
+	units, err = s.ScrapeBuilding(baseURL + "/rent/detail/building04units/")
+	if err != nil || len(units) != 2 {
+		problems = append(problems, fmt.Sprintf("unit table on a detail URL: %d units, err=%v (want 2)", len(units), err))
+	}
+
+	// 4. Ordinary detail pages (with or without a listing object) stay single listings
+	for _, url := range []string{propertyURL, baseURL + "/rent/detail/context00json/", baseURL + "/rent/detail/station01one/"} {
+		units, err := s.ScrapeBuilding(url)
+		if err != nil || len(units) != 1 {
+			problems = append(problems, fmt.Sprintf("%s: %d units, err=%v (want 1)", url, len(units), err))
+		}
+	}
+
+	result.Details = map[string]interface{}{
+		"problems": problems,
+	}
+	if len(problems) > 0 {
+		result.Message = fmt.Sprintf("建物ページの展開が不正: %v", problems)
+		log.Printf("  ❌ %v", problems)
+		return result
+	}
+
+	result.Success = true
+	result.Message = "建物ページが部屋ごとの物件に展開され、ScrapeProperty は ErrBuildingPage で部屋の URL を返すことを確認"
+	log.Printf("  ✅ %s", result.Message)
+	return result
+}
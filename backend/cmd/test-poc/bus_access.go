@@ -0,0 +1,93 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"real-estate-portal/internal/models"
+	"real-estate-portal/internal/scraper"
+	"real-estate-portal/internal/snapshot"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// Test 57: バス便の交通の取得（フィクスチャモードのみ）
+// 交通行が徒歩のみ / バス+徒歩 / バスのみ のとき、walk_time は駅までの徒歩だけを持ち、最寄り駅がバス便なら
+// バス停からの徒歩ではなく bus_minutes にバスの乗車分が入ること、その変化が access_changed になることを確認する
+func testBusAccess() TestResult {
+	result := TestResult{
+		TestName:  "バス便の交通の取得",
+		Timestamp: time.Now(),
+	}
+
+	log.Println("\n[Test 57] バス便の交通の取得テスト...")
+
+	s := scraper.NewScraperWithConfig(scraper.ScraperConfig{FixtureMode: true})
+
+	cases := []struct {
+		name   string
+		access []string
+		walk   *int
+		bus    *int
+	}{
+		{"walk_only", []string{"JR中央線/三鷹駅 徒歩7分"}, intp(7), nil},
+		{"bus_walk", []string{"JR中央線/三鷹駅 バス10分 新川停 徒歩3分"}, nil, intp(10)},
+		{"bus_only", []string{"JR中央線/三鷹駅 バス12分"}, nil, intp(12)},
+		// A station on foot is the nearest even when the bus line is listed first
+		{"bus_then_walk", []string{"JR中央線/三鷹駅 バス10分 新川停 徒歩3分", "京王線/調布駅 徒歩18分"}, intp(18), nil},
+	}
+
+	var problems []string
+	scraped := map[string]*models.Property{}
+	for _, tc := range cases {
+		page := `<html><head><title>` + tc.name + `</title></head><body><table>` +
+			`<tr><th>賃料</th><td>8.5万円</td></tr>` +
+			`<tr><th>交通</th><td>` + strings.Join(tc.access, "<br>") + `</td></tr>` +
+			`</table></body></html>`
+		doc, err := goquery.NewDocumentFromReader(strings.NewReader(page))
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: パース失敗: %v", tc.name, err))
+			continue
+		}
+		property, _, err := s.ParsePropertyDocument(doc, "https://realestate.yahoo.co.jp/rent/detail/bus"+tc.name+"/")
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: 抽出失敗: %v", tc.name, err))
+			continue
+		}
+		scraped[tc.name] = property
+		if !intPtrEq(property.WalkTime, tc.walk) || !intPtrEq(property.BusMinutes, tc.bus) {
+			problems = append(problems, fmt.Sprintf("%s: walk_time=%s bus_minutes=%s (want %s/%s)", tc.name,
+				fmtIntPtr(property.WalkTime), fmtIntPtr(property.BusMinutes), fmtIntPtr(tc.walk), fmtIntPtr(tc.bus)))
+		}
+	}
+
+	// Change detection: walk -> bus is access_changed; an old snapshot without any access is not compared
+	if walk, bus := scraped["walk_only"], scraped["bus_walk"]; walk != nil && bus != nil {
+		old := &models.PropertySnapshot{Status: "active", WalkTime: walk.WalkTime, BusMinutes: walk.BusMinutes}
+		cur := &models.PropertySnapshot{Status: "active", WalkTime: bus.WalkTime, BusMinutes: bus.BusMinutes}
+		changes := snapshot.CompareSnapshots("bus", old, cur, time.Now())
+		if len(changes) != 1 || changes[0].ChangeType != models.ChangeTypeAccess ||
+			changes[0].OldValue != "walk=7m" || changes[0].NewValue != "bus=10m" {
+			problems = append(problems, fmt.Sprintf("CompareSnapshots: %+v", changes))
+		}
+		if changes := snapshot.CompareSnapshots("bus", &models.PropertySnapshot{Status: "active"}, cur, time.Now()); len(changes) != 0 {
+			problems = append(problems, fmt.Sprintf("CompareSnapshots (no old access): %+v", changes))
+		}
+	}
+
+	result.Details = map[string]interface{}{
+		"cases":    len(cases),
+		"problems": problems,
+	}
+	if len(problems) > 0 {
+		result.Message = fmt.Sprintf("バス便の交通が不正: %v", problems)
+		log.Printf("  ❌ %v", problems)
+		return result
+	}
+
+	result.Success = true
+	result.Message = fmt.Sprintf("%d件の交通行で walk_time / bus_minutes を正しく取得し、徒歩→バスの変化を access_changed として検出", len(cases))
+	log.Printf("  ✅ %s", result.Message)
+	return result
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"real-estate-portal/internal/ratelimit"
+	"real-estate-portal/internal/scraper"
+	"sync/atomic"
+	"time"
+)
+
+// キャンセル後に呼び出しが戻るまでの許容時間
+const cancelReturnBudget = 2 * time.Second
+
+// Test 30: スクレイピングのキャンセル（フィクスチャモードのみ）
+// 詳細リミッターの待機・応答待ちの取得・キャンセル済みの一覧取得が ctx の終了で速やかに戻ることを確認する
+func testScrapeCancellation() TestResult {
+	result := TestResult{
+		TestName:  "スクレイピングのキャンセル",
+		Timestamp: time.Now(),
+	}
+
+	log.Println("\n[Test 30] スクレイピングのキャンセルテスト...")
+
+	var problems []string
+	timings := map[string]int64{}
+
+	// 1) DetailLimiter: the second acquire in a 1/hour window would wait ~1 hour
+	limiter := ratelimit.NewDetailLimiter(1)
+	limiter.Acquire("poc")
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	start := time.Now()
+	err := limiter.AcquireContext(ctx, "poc")
+	cancel()
+	timings["detail_limiter_ms"] = time.Since(start).Milliseconds()
+	if !errors.Is(err, context.DeadlineExceeded) || time.Since(start) > cancelReturnBudget {
+		problems = append(problems, fmt.Sprintf("DetailLimiter.AcquireContext: err=%v after %v", err, time.Since(start)))
+	}
+
+	// 2) A detail fetch against a server that never answers, with retries and 5s backoff configured
+	var hits atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		select {
+		case <-r.Context().Done():
+		case <-time.After(30 * time.Second):
+		}
+	}))
+	defer server.Close()
+
+	s := scraper.NewScraperWithConfig(scraper.ScraperConfig{
+		Timeout:     time.Minute,
+		MaxRetries:  3,
+		RetryDelay:  5 * time.Second,
+		BaseURL:     server.URL,
+		FixtureMode: true,
+	})
+	ctx, cancel = context.WithTimeout(context.Background(), 300*time.Millisecond)
+	start = time.Now()
+	_, err = s.ScrapePropertyContext(ctx, server.URL+"/rent/detail/cancel00hang/")
+	cancel()
+	timings["scrape_property_ms"] = time.Since(start).Milliseconds()
+	if !errors.Is(err, context.DeadlineExceeded) || time.Since(start) > cancelReturnBudget {
+		problems = append(problems, fmt.Sprintf("ScrapePropertyContext: err=%v after %v", err, time.Since(start)))
+	}
+	// Cancellation is not a site failure, so the site's circuit breaker stays closed
+	if isOpen, _ := s.CircuitBreaker().State(); isOpen {
+		problems = append(problems, "circuit breaker opened by a canceled request")
+	}
+
+	// 3) An already-canceled list scrape never reaches the server
+	before := hits.Load()
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	if _, err := s.ScrapeListPageContext(ctx, server.URL+"/list/"); !errors.Is(err, context.Canceled) {
+		problems = append(problems, fmt.Sprintf("ScrapeListPageContext: err=%v", err))
+	}
+	if n := hits.Load() - before; n != 0 {
+		problems = append(problems, fmt.Sprintf("canceled list scrape sent %d request(s)", n))
+	}
+
+	result.Details = map[string]interface{}{
+		"timings":  timings,
+		"problems": problems,
+	}
+	if len(problems) > 0 {
+		result.Message = fmt.Sprintf("キャンセルが効かない: %v", problems)
+		log.Printf("  ❌ %v", problems)
+		return result
+	}
+
+	result.Success = true
+	result.Message = fmt.Sprintf("リミッター待機%dms・取得%dmsでキャンセル、ブレーカーは閉じたまま",
+		timings["detail_limiter_ms"], timings["scrape_property_ms"])
+	log.Printf("  ✅ %s", result.Message)
+	return result
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Test 36: Shift_JIS ページの文字コード変換（フィクスチャモードのみ）
+// detail_sjis00meta.html（Shift_JIS）を <meta charset>・Content-Type・宣言なしの3通りで配信し、
+// タイトル・住所が文字化けせず取得でき、UTF-8 のページはそのまま扱われることを確認する
+func testShiftJISCharset(fixtureDir string) TestResult {
+	result := TestResult{
+		TestName:  "Shift_JISページの文字コード変換",
+		Timestamp: time.Now(),
+	}
+
+	log.Println("\n[Test 36] 文字コード変換テスト...")
+
+	sjis, err := os.ReadFile(filepath.Join(fixtureDir, "detail_sjis00meta.html"))
+	if err != nil {
+		result.Message = fmt.Sprintf("フィクスチャ読み込み失敗: %v", err)
+		return result
+	}
+	utf8Page, err := os.ReadFile(filepath.Join(fixtureDir, "detail.html"))
+	if err != nil {
+		result.Message = fmt.Sprintf("フィクスチャ読み込み失敗: %v", err)
+		return result
+	}
+	noMeta := bytes.Replace(sjis, []byte(`<meta charset="Shift_JIS">`), nil, 1)
+
+	// variant → body and Content-Type
+	variants := map[string]struct {
+		body        []byte
+		contentType string
+	}{
+		"meta":       {sjis, "text/html"},
+		"header":     {noMeta, "text/html; charset=Shift_JIS"},
+		"undeclared": {noMeta, "text/html"},
+		"utf8":       {utf8Page, "text/html"},
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		v, ok := variants[strings.Trim(strings.TrimPrefix(r.URL.Path, "/rent/detail/sjis"), "/")]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", v.contentType)
+		w.Write(v.body)
+	}))
+	defer server.Close()
+
+	s := newFixtureScraper(server.URL)
+	var problems []string
+
+	const wantTitle, wantAddress = "コーポ表参道 102", "東京都港区北青山3丁目"
+	for _, name := range []string{"meta", "header", "undeclared"} {
+		property, err := s.ScrapeProperty(server.URL + "/rent/detail/sjis" + name + "/")
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", name, err))
+			continue
+		}
+		if !utf8.ValidString(property.Title) || strings.ContainsRune(property.Title, utf8.RuneError) ||
+			!strings.Contains(property.Title, wantTitle) {
+			problems = append(problems, fmt.Sprintf("%s: title=%q (want %q)", name, property.Title, wantTitle))
+		}
+		if !strings.Contains(property.Address, wantAddress) {
+			problems = append(problems, fmt.Sprintf("%s: address=%q (want %q)", name, property.Address, wantAddress))
+		}
+	}
+
+	// UTF-8 pages are passed through untouched
+	want, err := newFixtureScraper(server.URL).ParsePropertyHTML(context.Background(), string(utf8Page), server.URL+"/rent/detail/sjisutf8/")
+	if err != nil {
+		problems = append(problems, fmt.Sprintf("utf8 parse: %v", err))
+	} else if got, err := s.ScrapeProperty(server.URL + "/rent/detail/sjisutf8/"); err != nil {
+		problems = append(problems, fmt.Sprintf("utf8: %v", err))
+	} else if got.Title != want.Title || got.Address != want.Address {
+		problems = append(problems, fmt.Sprintf("utf8: title=%q address=%q (want %q / %q)", got.Title, got.Address, want.Title, want.Address))
+	}
+
+	result.Details = map[string]interface{}{
+		"variants": len(variants),
+		"problems": problems,
+	}
+	if len(problems) > 0 {
+		result.Message = fmt.Sprintf("文字コード変換が不正: %v", problems)
+		log.Printf("  ❌ %v", problems)
+		return result
+	}
+
+	result.Success = true
+	result.Message = "Shift_JIS（meta / Content-Type / 宣言なし）を文字化けなく取得、UTF-8 はそのまま"
+	log.Printf("  ✅ %s", result.Message)
+	return result
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"real-estate-portal/internal/scraper"
+	"sync"
+	"time"
+)
+
+// Test 34: ETag / Last-Modified による条件付き再取得（フィクスチャモードのみ）
+// 初回取得で検証子が物件に記録され、再取得では If-None-Match / If-Modified-Since が送られ、
+// 304 ならパースせず ErrNotModified、ページが変われば新しい検証子で全件取得されることを確認する
+func testConditionalRescrape(fixtureDir string) TestResult {
+	result := TestResult{
+		TestName:  "条件付きリクエストによる再取得の省略",
+		Timestamp: time.Now(),
+	}
+
+	log.Println("\n[Test 34] 条件付き再取得テスト...")
+
+	body, err := os.ReadFile(filepath.Join(fixtureDir, "detail.html"))
+	if err != nil {
+		result.Message = fmt.Sprintf("フィクスチャ読み込み失敗: %v", err)
+		return result
+	}
+
+	const lastModified = "Wed, 14 Oct 2026 03:00:00 GMT"
+	var mu sync.Mutex
+	etag := `"v1"`
+	var sent []string // conditional headers per request ("-" = unconditional)
+	full, notModified := 0, 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rent/detail/cond00etag/" {
+			http.NotFound(w, r)
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		inm, ims := r.Header.Get("If-None-Match"), r.Header.Get("If-Modified-Since")
+		switch {
+		case inm != "":
+			sent = append(sent, "If-None-Match: "+inm)
+		case ims != "":
+			sent = append(sent, "If-Modified-Since: "+ims)
+		default:
+			sent = append(sent, "-")
+		}
+		if (inm != "" && inm == etag) || (inm == "" && ims == lastModified && etag == `"v1"`) {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		full++
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Last-Modified", lastModified)
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write(body)
+	}))
+	defer server.Close()
+
+	s := newFixtureScraper(server.URL)
+	pageURL := server.URL + "/rent/detail/cond00etag/"
+	var problems []string
+
+	// First fetch: unconditional, validators recorded on the property
+	first, err := s.ScrapePropertyIfModified(context.Background(), pageURL, scraper.Validators{})
+	if err != nil {
+		result.Message = fmt.Sprintf("初回取得失敗: %v", err)
+		return result
+	}
+	if first.ETag != `"v1"` || first.LastModified != lastModified {
+		problems = append(problems, fmt.Sprintf("first: validators=%q/%q", first.ETag, first.LastModified))
+	}
+
+	// Same ETag: 304, not parsed
+	if _, err := s.ScrapePropertyIfModified(context.Background(), pageURL, scraper.ValidatorsOf(first)); !errors.Is(err, scraper.ErrNotModified) {
+		problems = append(problems, fmt.Sprintf("etag match: err=%v (want ErrNotModified)", err))
+	}
+
+	// Last-Modified only
+	if _, err := s.ScrapePropertyIfModified(context.Background(), pageURL, scraper.Validators{LastModified: lastModified}); !errors.Is(err, scraper.ErrNotModified) {
+		problems = append(problems, fmt.Sprintf("last-modified match: err=%v (want ErrNotModified)", err))
+	}
+
+	// Page changed: full fetch with the new ETag
+	mu.Lock()
+	etag = `"v2"`
+	mu.Unlock()
+	changed, err := s.ScrapePropertyIfModified(context.Background(), pageURL, scraper.ValidatorsOf(first))
+	switch {
+	case err != nil:
+		problems = append(problems, fmt.Sprintf("changed: err=%v", err))
+	case changed.ETag != `"v2"`:
+		problems = append(problems, fmt.Sprintf("changed: etag=%q (want \"v2\")", changed.ETag))
+	case changed.Title != first.Title:
+		problems = append(problems, fmt.Sprintf("changed: title=%q (want %q)", changed.Title, first.Title))
+	}
+
+	mu.Lock()
+	wantSent := []string{"-", `If-None-Match: "v1"`, "If-Modified-Since: " + lastModified, `If-None-Match: "v1"`}
+	if fmt.Sprint(sent) != fmt.Sprint(wantSent) {
+		problems = append(problems, fmt.Sprintf("headers sent=%q (want %q)", sent, wantSent))
+	}
+	if full != 2 || notModified != 2 {
+		problems = append(problems, fmt.Sprintf("responses: full=%d not_modified=%d (want 2/2)", full, notModified))
+	}
+	result.Details = map[string]interface{}{
+		"requests":     sent,
+		"full":         full,
+		"not_modified": notModified,
+		"problems":     problems,
+	}
+	mu.Unlock()
+
+	if len(problems) > 0 {
+		result.Message = fmt.Sprintf("条件付き再取得が不正: %v", problems)
+		log.Printf("  ❌ %v", problems)
+		return result
+	}
+
+	result.Success = true
+	result.Message = "検証子を記録し、未変更は304でパース省略、変更時は新しいETagで再取得"
+	log.Printf("  ✅ %s", result.Message)
+	return result
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"real-estate-portal/internal/ratelimit"
+	"time"
+)
+
+// Test 11: 予防クールダウンの間隔（オフライン）
+// ワーカーのポーリングを模擬し、有効時は閾値到達後に間隔が空き、無効時は空かないことを確認する
+func testPreventiveCooldownSpacing() TestResult {
+	result := TestResult{
+		TestName:  "予防クールダウンの間隔",
+		Timestamp: time.Now(),
+	}
+
+	log.Println("\n[Test 11] 予防クールダウン間隔テスト...")
+
+	const (
+		tick     = 5 * time.Millisecond
+		cooldown = 80 * time.Millisecond
+		items    = 4
+	)
+
+	enabled := ratelimit.PreventiveCooldown{Enabled: true, AfterSuccesses: 2, Duration: cooldown}
+	disabled := ratelimit.PreventiveCooldown{Enabled: false, AfterSuccesses: 2, Duration: cooldown}
+
+	gapsOn := simulateWorkerGaps(enabled, tick, items)
+	gapsOff := simulateWorkerGaps(disabled, tick, items)
+
+	var problems []string
+	// Enabled: items 1-2 back to back, item 3 waits out the cooldown, item 4 back to back again
+	if gapsOn[1] < cooldown {
+		problems = append(problems, fmt.Sprintf("enabled: gap before item 3 = %v (want >= %v)", gapsOn[1], cooldown))
+	}
+	if gapsOn[0] >= cooldown || gapsOn[2] >= cooldown {
+		problems = append(problems, fmt.Sprintf("enabled: unexpected pause %v", gapsOn))
+	}
+	for i, g := range gapsOff {
+		if g >= cooldown {
+			problems = append(problems, fmt.Sprintf("disabled: gap %d = %v", i, g))
+		}
+	}
+
+	// The adaptive limiter keeps the cooldown off unless it was enabled explicitly
+	adaptive := ratelimit.NewAdaptiveDetailLimiter(ratelimit.DetailRateConfig{}, ratelimit.AdaptiveConfig{})
+	adaptive.SetPreventiveCooldown(enabled)
+	adaptive.Observe(true)
+	adaptive.Observe(true)
+	if adaptive.CooldownRemaining() > 0 {
+		problems = append(problems, "adaptive: cooldown applied without explicit config")
+	}
+	explicit := enabled
+	explicit.Explicit = true
+	adaptive.SetPreventiveCooldown(explicit)
+	adaptive.Observe(true)
+	adaptive.Observe(true)
+	if adaptive.CooldownRemaining() <= 0 {
+		problems = append(problems, "adaptive: explicit cooldown not applied")
+	}
+
+	result.Details = map[string]interface{}{
+		"gaps_enabled_ms":  durationsMs(gapsOn),
+		"gaps_disabled_ms": durationsMs(gapsOff),
+		"problems":         problems,
+	}
+	if len(problems) > 0 {
+		result.Message = fmt.Sprintf("間隔が不正: %v", problems)
+		log.Printf("  ❌ %v", problems)
+		return result
+	}
+
+	result.Success = true
+	result.Message = fmt.Sprintf("有効時 %v / 無効時 %v", durationsMs(gapsOn), durationsMs(gapsOff))
+	log.Printf("  ✅ 有効時のみ閾値後に間隔が空く")
+	return result
+}
+
+// simulateWorkerGaps polls like QueueWorker.run and returns the spacing between processed items
+func simulateWorkerGaps(policy ratelimit.PreventiveCooldown, tick time.Duration, items int) []time.Duration {
+	dl := ratelimit.NewDetailLimiter(1000)
+	dl.SetPreventiveCooldown(policy)
+
+	ticker := time.NewTicker(tick)
+	defer ticker.Stop()
+
+	var processed []time.Time
+	for len(processed) < items {
+		<-ticker.C
+		if dl.CooldownRemaining() > 0 {
+			continue
+		}
+		processed = append(processed, time.Now())
+		dl.RecordOutcome(true)
+	}
+
+	gaps := make([]time.Duration, 0, items-1)
+	for i := 1; i < len(processed); i++ {
+		gaps = append(gaps, processed[i].Sub(processed[i-1]))
+	}
+	return gaps
+}
+
+func durationsMs(ds []time.Duration) []int64 {
+	out := make([]int64, len(ds))
+	for i, d := range ds {
+		out[i] = d.Milliseconds()
+	}
+	return out
+}
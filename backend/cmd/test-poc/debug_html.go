@@ -0,0 +1,101 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"real-estate-portal/internal/scraper"
+	"strings"
+	"time"
+)
+
+// Test 37: 抽出失敗ページの HTML 保存（フィクスチャモードのみ）
+// タイトル・賃料が取れないページ（アクセス集中の中間ページ）だけが <dir>/<物件ID>-<時刻>.html に保存され、
+// サイズ上限で切り詰められ、保持期間を過ぎたファイルは削除されることを確認する
+func testDebugHTMLCapture(propertyURL string) TestResult {
+	result := TestResult{
+		TestName:  "抽出失敗ページのHTML保存",
+		Timestamp: time.Now(),
+	}
+
+	log.Println("\n[Test 37] デバッグ用HTML保存テスト...")
+
+	dir, err := os.MkdirTemp("", "poc-debug-html")
+	if err != nil {
+		result.Message = fmt.Sprintf("一時ディレクトリ作成失敗: %v", err)
+		return result
+	}
+	defer os.RemoveAll(dir)
+
+	// A capture past the retention is pruned when the scraper starts
+	stale := filepath.Join(dir, "stale-20000101-000000.000.html")
+	os.WriteFile(stale, []byte("<html></html>"), 0o644)
+	old := time.Now().Add(-30 * 24 * time.Hour)
+	os.Chtimes(stale, old, old)
+
+	detailBase := propertyURL[:strings.Index(propertyURL, "/rent/detail/")+len("/rent/detail/")]
+	const maxBytes = 200
+	s := scraper.NewScraperWithConfig(scraper.ScraperConfig{
+		Timeout:            10 * time.Second,
+		BaseURL:            propertyURL[:strings.Index(propertyURL, "/rent/detail/")],
+		FixtureMode:        true,
+		DebugHTMLDir:       dir,
+		DebugHTMLMaxBytes:  maxBytes,
+		DebugHTMLRetention: 7 * 24 * time.Hour,
+	})
+
+	var problems []string
+	listCaptures := func() []string {
+		matches, _ := filepath.Glob(filepath.Join(dir, "*.html"))
+		return matches
+	}
+
+	if _, err := os.Stat(stale); !os.IsNotExist(err) {
+		problems = append(problems, "stale capture was not pruned")
+	}
+
+	// Good page: nothing written
+	if _, err := s.ScrapeProperty(propertyURL); err != nil {
+		problems = append(problems, fmt.Sprintf("good page: %v", err))
+	}
+	if files := listCaptures(); len(files) != 0 {
+		problems = append(problems, fmt.Sprintf("good page wrote %d files", len(files)))
+	}
+
+	// Interstitial: saved as <property_id>-<timestamp>.html, truncated to the cap
+	p, err := s.ScrapeProperty(detailBase + "debug00blocked/")
+	if err != nil {
+		problems = append(problems, fmt.Sprintf("bad page: %v", err))
+	} else if p.Title != "No Title" || p.Rent != nil {
+		problems = append(problems, fmt.Sprintf("bad page extracted title=%q rent=%s", p.Title, fmtIntPtr(p.Rent)))
+	}
+	files := listCaptures()
+	switch {
+	case len(files) != 1:
+		problems = append(problems, fmt.Sprintf("bad page wrote %d files (want 1)", len(files)))
+	case p != nil && !strings.HasPrefix(filepath.Base(files[0]), p.SourcePropertyID+"-"):
+		problems = append(problems, fmt.Sprintf("file name %s (want %s-<timestamp>.html)", filepath.Base(files[0]), p.SourcePropertyID))
+	default:
+		data, _ := os.ReadFile(files[0])
+		if !strings.HasPrefix(string(data), "<!DOCTYPE html>") || !strings.Contains(string(data), "truncated") ||
+			len(data) > maxBytes+100 {
+			problems = append(problems, fmt.Sprintf("capture content: %d bytes, truncated marker=%v", len(data), strings.Contains(string(data), "truncated")))
+		}
+	}
+
+	result.Details = map[string]interface{}{
+		"files":    len(files),
+		"problems": problems,
+	}
+	if len(problems) > 0 {
+		result.Message = fmt.Sprintf("HTML保存が不正: %v", problems)
+		log.Printf("  ❌ %v", problems)
+		return result
+	}
+
+	result.Success = true
+	result.Message = "抽出失敗ページのみ物件ID付きで保存（上限で切り詰め）、古いファイルは削除"
+	log.Printf("  ✅ %s", result.Message)
+	return result
+}
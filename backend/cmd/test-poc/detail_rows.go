@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"real-estate-portal/internal/scraper"
+	"strings"
+	"time"
+)
+
+// Test 45: 方位・階数・駐車場・契約期間・入居時期・条件等の抽出（フィクスチャモードのみ）
+// 詳細表の各行から取り出し、埋め込みJSONの値があればそちらを優先すること、
+// "地上3階建て/2階部分" は FloorLabel に全体を残して Floor=2 になることを確認する
+func testDetailRows(s *scraper.Scraper, propertyURL string) TestResult {
+	result := TestResult{
+		TestName:  "方位・階数・駐車場・契約期間・入居時期・条件等の抽出",
+		Timestamp: time.Now(),
+	}
+
+	log.Println("\n[Test 45] 方位・階数・駐車場・契約期間・入居時期・条件等の抽出テスト...")
+
+	type want struct {
+		direction, floorLabel, parking, contractPeriod, moveInDate, conditions string
+		floor, buildingFloors                                                  *int
+	}
+	detailBase := propertyURL[:strings.Index(propertyURL, "/rent/detail/")+len("/rent/detail/")]
+	pages := []struct {
+		id   string
+		want want
+	}{
+		// Detail table only
+		{"terms00table", want{"南東", "地上3階建て/2階部分", "空有 15,000円/月", "2年", "即入居可", "二人入居可 ペット相談", intp(2), intp(3)}},
+		// JSON values win; the table adds 入居可能時期 / 条件等 ("-" 駐車場 is skipped)
+		{"terms01json", want{"南", "地上10階建て/7階部分", "なし", "2年", "2026年11月上旬", "事務所利用不可", intp(7), intp(10)}},
+	}
+
+	var problems []string
+	for _, tc := range pages {
+		property, err := s.ScrapeProperty(detailBase + tc.id + "/")
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: 取得失敗: %v", tc.id, err))
+			continue
+		}
+		got := want{property.Direction, property.FloorLabel, property.Parking, property.ContractPeriod,
+			property.MoveInDate, property.Conditions, property.Floor, property.BuildingFloors}
+		for _, f := range []struct{ name, got, want string }{
+			{"direction", got.direction, tc.want.direction},
+			{"floor_label", got.floorLabel, tc.want.floorLabel},
+			{"parking", got.parking, tc.want.parking},
+			{"contract_period", got.contractPeriod, tc.want.contractPeriod},
+			{"move_in_date", got.moveInDate, tc.want.moveInDate},
+			{"conditions", got.conditions, tc.want.conditions},
+		} {
+			if f.got != f.want {
+				problems = append(problems, fmt.Sprintf("%s: %s=%q (want %q)", tc.id, f.name, f.got, f.want))
+			}
+		}
+		if !intPtrEq(got.floor, tc.want.floor) || !intPtrEq(property.UnitFloor, tc.want.floor) ||
+			!intPtrEq(got.buildingFloors, tc.want.buildingFloors) {
+			problems = append(problems, fmt.Sprintf("%s: floor=%s unit_floor=%s building_floors=%s (want %s / %s)", tc.id,
+				fmtIntPtr(got.floor), fmtIntPtr(property.UnitFloor), fmtIntPtr(got.buildingFloors),
+				fmtIntPtr(tc.want.floor), fmtIntPtr(tc.want.buildingFloors)))
+		}
+	}
+
+	result.Details = map[string]interface{}{
+		"pages":    len(pages),
+		"problems": problems,
+	}
+	if len(problems) > 0 {
+		result.Message = fmt.Sprintf("詳細表の項目の抽出が不正: %v", problems)
+		log.Printf("  ❌ %v", problems)
+		return result
+	}
+
+	result.Success = true
+	result.Message = fmt.Sprintf("%dページで方位・階数・駐車場・契約期間・入居時期・条件等を正しく抽出", len(pages))
+	log.Printf("  ✅ %s", result.Message)
+	return result
+}
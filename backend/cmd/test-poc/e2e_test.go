@@ -0,0 +1,165 @@
+//go:build e2e
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"real-estate-portal/internal/models"
+	"real-estate-portal/internal/queue"
+	"real-estate-portal/internal/scheduler"
+	"real-estate-portal/internal/scraper"
+	"real-estate-portal/internal/search"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeMeilisearch answers document additions like Meilisearch (202 with an enqueued task) and
+// keeps the documents it was sent, by ID
+type fakeMeilisearch struct {
+	mu        sync.Mutex
+	documents map[string]map[string]interface{}
+}
+
+func startFakeMeilisearch(t *testing.T) (*fakeMeilisearch, *httptest.Server) {
+	t.Helper()
+	m := &fakeMeilisearch{documents: make(map[string]map[string]interface{})}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/indexes/properties/documents" {
+			http.NotFound(w, r)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		var docs []map[string]interface{}
+		if err := json.Unmarshal(body, &docs); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		m.mu.Lock()
+		for _, doc := range docs {
+			id, _ := doc["id"].(string)
+			m.documents[id] = doc
+		}
+		m.mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusAccepted)
+		io.WriteString(w, `{"taskUid":1,"indexUid":"properties","status":"enqueued","type":"documentAdditionOrUpdate","enqueuedAt":"2026-01-01T00:00:00Z"}`)
+	}))
+	t.Cleanup(srv.Close)
+	return m, srv
+}
+
+func (m *fakeMeilisearch) document(id string) map[string]interface{} {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.documents[id]
+}
+
+// TestPipelineE2E runs the offline pipeline the way production does: the list fixture is
+// crawled, its listings queued, the queue worker scrapes each detail fixture and saves it to
+// SQLite (property, stations, snapshot, queue row done), and the saved listings are indexed
+// into a mocked Meilisearch.
+func TestPipelineE2E(t *testing.T) {
+	fixtures := startFixtureServer("testdata/fixtures")
+	t.Cleanup(fixtures.Close)
+	s := newFixtureScraper(fixtures.URL)
+	sources := scraper.NewRegistry(s)
+	db := openSQLiteDB(t)
+
+	// 1. Crawl the list page and queue what it links to (inserted as EnqueueListed would: its
+	// upsert is MySQL's ON DUPLICATE KEY UPDATE)
+	listURL := fixtures.URL + "/rent/list/"
+	urls, err := s.ScrapeList(context.Background(), listURL)
+	if err != nil || len(urls) == 0 {
+		t.Fatalf("ScrapeList: %d URLs, %v", len(urls), err)
+	}
+	for _, u := range urls {
+		source, id, detailURL, err := queue.ResolveDetailURL(sources, u)
+		if err != nil {
+			t.Fatalf("ResolveDetailURL(%s): %v", u, err)
+		}
+		item := models.DetailScrapeQueue{Source: source, SourcePropertyID: id, DetailURL: detailURL,
+			RefererURL: listURL, Status: models.QueueStatusPending, Priority: queue.PriorityList}
+		if err := db.Create(&item).Error; err != nil {
+			t.Fatalf("queue %s: %v", id, err)
+		}
+	}
+
+	// 2. The worker drains the queue
+	w := scheduler.NewQueueWorkerWithSources(db, s, sources)
+	w.SetPollInterval(20 * time.Millisecond)
+	w.SetHealthCheck(func(context.Context) bool { return true })
+	w.SetPriorityAging(0) // the aged ORDER BY is MySQL's TIMESTAMPDIFF
+	if err := w.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	var items []models.DetailScrapeQueue
+	for deadline := time.Now().Add(30 * time.Second); ; time.Sleep(50 * time.Millisecond) {
+		items = nil
+		if err := db.Order("id").Find(&items).Error; err != nil {
+			t.Fatalf("read queue: %v", err)
+		}
+		done := 0
+		for _, item := range items {
+			if item.Status == models.QueueStatusDone {
+				done++
+			}
+		}
+		if done == len(urls) {
+			break
+		}
+		if time.Now().After(deadline) {
+			w.Stop()
+			t.Fatalf("queue not drained: %+v", items)
+		}
+	}
+	if err := w.Stop(); err != nil {
+		t.Fatalf("Stop: %v", err)
+	}
+
+	// 3. Every listing was saved with its stations and a snapshot
+	var properties []models.Property
+	if err := db.Order("id").Find(&properties).Error; err != nil {
+		t.Fatalf("read properties: %v", err)
+	}
+	if len(properties) != len(urls) {
+		t.Fatalf("%d properties saved for %d listings", len(properties), len(urls))
+	}
+	for _, item := range items {
+		if item.CompletedAt == nil || item.Attempts != 1 || item.RefererURL != listURL {
+			t.Errorf("queue item %s: completed_at=%v attempts=%d referer=%q", item.SourcePropertyID, item.CompletedAt, item.Attempts, item.RefererURL)
+		}
+	}
+	for _, p := range properties {
+		if p.Title == "" || p.Rent == nil || p.DetailURL == "" || p.Status != models.PropertyStatusActive {
+			t.Errorf("property %s: title=%q rent=%v url=%q status=%s", p.ID, p.Title, p.Rent, p.DetailURL, p.Status)
+		}
+		var stations, snapshots int64
+		db.Model(&models.PropertyStation{}).Where("property_id = ?", p.ID).Count(&stations)
+		db.Model(&models.PropertySnapshot{}).Where("property_id = ?", p.ID).Count(&snapshots)
+		if stations == 0 || snapshots != 1 {
+			t.Errorf("property %s: %d stations, %d snapshots (want some, 1)", p.ID, stations, snapshots)
+		}
+	}
+
+	// 4. The saved listings index into Meilisearch
+	meili, srv := startFakeMeilisearch(t)
+	client := search.NewSearchClient(srv.URL, "e2e-key")
+	if err := client.IndexProperties(properties); err != nil {
+		t.Fatalf("IndexProperties: %v", err)
+	}
+	for _, p := range properties {
+		doc := meili.document(p.ID)
+		if doc == nil {
+			t.Errorf("property %s not indexed", p.ID)
+			continue
+		}
+		if doc["title"] != p.Title || doc["detail_url"] != p.DetailURL {
+			t.Errorf("indexed %s: title=%v detail_url=%v (want %q, %q)", p.ID, doc["title"], doc["detail_url"], p.Title, p.DetailURL)
+		}
+	}
+}
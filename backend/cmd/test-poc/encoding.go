@@ -0,0 +1,160 @@
+package main
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"real-estate-portal/internal/scraper"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/andybalholm/brotli"
+)
+
+// pocEncodings are the compressed variants served by Test 35 (name → Content-Encoding, encoder)
+var pocEncodings = []struct {
+	name, header string
+	encode       func(w io.Writer) io.WriteCloser
+}{
+	{"gzip", "gzip", func(w io.Writer) io.WriteCloser { return gzip.NewWriter(w) }},
+	{"deflate", "deflate", func(w io.Writer) io.WriteCloser { return zlib.NewWriter(w) }},
+	{"rawdeflate", "deflate", func(w io.Writer) io.WriteCloser {
+		fw, _ := flate.NewWriter(w, flate.DefaultCompression)
+		return fw
+	}},
+	{"br", "br", func(w io.Writer) io.WriteCloser { return brotli.NewWriter(w) }},
+}
+
+// Test 35: 圧縮レスポンスの展開（フィクスチャモードのみ）
+// Accept-Encoding を送り、gzip / deflate（zlib・生）/ br で返された一覧・詳細ページが
+// 非圧縮と同じ結果になり、未対応の Content-Encoding はパースエラーになることを確認する
+func testCompressedResponses(fixtureDir string) TestResult {
+	result := TestResult{
+		TestName:  "圧縮レスポンスの展開",
+		Timestamp: time.Now(),
+	}
+
+	log.Println("\n[Test 35] 圧縮レスポンステスト...")
+
+	listHTML, err := os.ReadFile(filepath.Join(fixtureDir, "list.html"))
+	if err != nil {
+		result.Message = fmt.Sprintf("フィクスチャ読み込み失敗: %v", err)
+		return result
+	}
+	detailHTML, err := os.ReadFile(filepath.Join(fixtureDir, "detail.html"))
+	if err != nil {
+		result.Message = fmt.Sprintf("フィクスチャ読み込み失敗: %v", err)
+		return result
+	}
+
+	var mu sync.Mutex
+	var acceptEncodings []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// /list/<encoding> or /rent/detail/enc<encoding>/
+		var body []byte
+		var name string
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/list/"):
+			body, name = listHTML, strings.TrimPrefix(r.URL.Path, "/list/")
+		case strings.HasPrefix(r.URL.Path, "/rent/detail/enc"):
+			body, name = detailHTML, strings.Trim(strings.TrimPrefix(r.URL.Path, "/rent/detail/enc"), "/")
+		default:
+			http.NotFound(w, r)
+			return
+		}
+		mu.Lock()
+		acceptEncodings = append(acceptEncodings, r.Header.Get("Accept-Encoding"))
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		if name == "compress" { // an encoding the scraper does not decode
+			w.Header().Set("Content-Encoding", "compress")
+			w.Write(body)
+			return
+		}
+		for _, enc := range pocEncodings {
+			if enc.name == name {
+				var buf bytes.Buffer
+				ew := enc.encode(&buf)
+				ew.Write(body)
+				ew.Close()
+				w.Header().Set("Content-Encoding", enc.header)
+				w.Write(buf.Bytes())
+				return
+			}
+		}
+		w.Write(body) // identity
+	}))
+	defer server.Close()
+
+	s := newFixtureScraper(server.URL)
+	var problems []string
+
+	wantURLs, err := s.ScrapeListPage(server.URL + "/list/identity")
+	if err != nil {
+		result.Message = fmt.Sprintf("非圧縮の一覧取得失敗: %v", err)
+		return result
+	}
+	want, err := s.ScrapeProperty(server.URL + "/rent/detail/encidentity/")
+	if err != nil {
+		result.Message = fmt.Sprintf("非圧縮の詳細取得失敗: %v", err)
+		return result
+	}
+
+	for _, enc := range pocEncodings {
+		urls, err := s.ScrapeListPage(server.URL + "/list/" + enc.name)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s list: %v", enc.name, err))
+		} else if len(urls) != len(wantURLs) {
+			problems = append(problems, fmt.Sprintf("%s list: %d URLs (want %d)", enc.name, len(urls), len(wantURLs)))
+		}
+
+		got, err := s.ScrapeProperty(server.URL + "/rent/detail/enc" + enc.name + "/")
+		switch {
+		case err != nil:
+			problems = append(problems, fmt.Sprintf("%s detail: %v", enc.name, err))
+		case got.Title == "" || got.Title != want.Title || !intPtrEq(got.Rent, want.Rent):
+			problems = append(problems, fmt.Sprintf("%s detail: title=%q rent=%s (want %q / %s)",
+				enc.name, got.Title, fmtIntPtr(got.Rent), want.Title, fmtIntPtr(want.Rent)))
+		}
+	}
+
+	if _, err := s.ScrapeListPage(server.URL + "/list/compress"); !errors.Is(err, scraper.ErrParse) {
+		problems = append(problems, fmt.Sprintf("unsupported encoding: err=%v (want ErrParse)", err))
+	}
+
+	mu.Lock()
+	for _, ae := range acceptEncodings {
+		if !strings.Contains(ae, "gzip") || strings.Contains(ae, "zstd") {
+			problems = append(problems, fmt.Sprintf("Accept-Encoding=%q (want gzip, no undecodable encodings)", ae))
+			break
+		}
+	}
+	result.Details = map[string]interface{}{
+		"encodings":       len(pocEncodings),
+		"accept_encoding": acceptEncodings,
+		"problems":        problems,
+	}
+	mu.Unlock()
+
+	if len(problems) > 0 {
+		result.Message = fmt.Sprintf("圧縮レスポンスの展開が不正: %v", problems)
+		log.Printf("  ❌ %v", problems)
+		return result
+	}
+
+	result.Success = true
+	result.Message = fmt.Sprintf("%d種類の圧縮で一覧・詳細とも非圧縮と同じ結果、未対応の圧縮はパースエラー", len(pocEncodings))
+	log.Printf("  ✅ %s", result.Message)
+	return result
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"real-estate-portal/internal/errtext"
+	"real-estate-portal/internal/scheduler"
+	"real-estate-portal/internal/scraper"
+	"strings"
+	"time"
+)
+
+// Test 56: 掲載終了ページの判定（フィクスチャモードのみ）
+// 200 で返る掲載終了ページ（detail_ended00notice.html）が物件として返らず ErrDelisted（delisted）になり、
+// ワーカーが再試行しない分類になること、閲覧履歴に「掲載が終了しました」がある通常の物件ページは
+// そのまま取得できることを確認する
+func testEndedListing(propertyURL string) TestResult {
+	result := TestResult{
+		TestName:  "掲載終了ページの判定",
+		Timestamp: time.Now(),
+	}
+
+	log.Println("\n[Test 56] 掲載終了ページの判定テスト...")
+
+	baseURL := propertyURL[:strings.Index(propertyURL, "/rent/detail/")]
+	s := newFixtureScraper(baseURL)
+
+	var problems []string
+
+	// 1. The notice page: no property to save, typed error, not retried
+	property, err := s.ScrapeProperty(baseURL + "/rent/detail/ended00notice/")
+	if property != nil {
+		problems = append(problems, fmt.Sprintf("notice: property returned (title=%q), would be saved", property.Title))
+	}
+	if !errors.Is(err, scraper.ErrDelisted) {
+		problems = append(problems, fmt.Sprintf("notice: err=%v (want ErrDelisted)", err))
+	} else {
+		if code, _ := errtext.FromError(err); code != errtext.CodeDelisted {
+			problems = append(problems, fmt.Sprintf("notice: code=%s (want %s)", code, errtext.CodeDelisted))
+		}
+		if failure := scheduler.ClassifyScrapeFailure(err); failure != scheduler.FailurePermanent {
+			problems = append(problems, fmt.Sprintf("notice: classified %s (want %s)", failure, scheduler.FailurePermanent))
+		}
+	}
+
+	// 2. A live listing whose history block mentions an ended one is parsed as usual
+	live, err := s.ScrapeProperty(baseURL + "/rent/detail/ended01recommend/")
+	if err != nil {
+		problems = append(problems, fmt.Sprintf("recommend: 取得失敗: %v", err))
+	} else if !intPtrEq(live.Rent, intp(90000)) {
+		problems = append(problems, fmt.Sprintf("recommend: rent=%s (want 90000)", fmtIntPtr(live.Rent)))
+	}
+
+	result.Details = map[string]interface{}{
+		"problems": problems,
+	}
+	if len(problems) > 0 {
+		result.Message = fmt.Sprintf("掲載終了ページの判定が不正: %v", problems)
+		log.Printf("  ❌ %v", problems)
+		return result
+	}
+
+	result.Success = true
+	result.Message = "掲載終了ページは物件を返さず ErrDelisted（再試行なし）、閲覧履歴の終了表示は通常ページとして取得されることを確認"
+	log.Printf("  ✅ %s", result.Message)
+	return result
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"real-estate-portal/internal/models"
+	"real-estate-portal/internal/queue"
+	"sync"
+	"time"
+)
+
+// Test 77: 同時キュー登録の一意性（オフライン）
+// スケジューラーと手動登録が同じ物件を同時に queue.Service.Enqueue しても、active_key の一意制約と
+// ON DUPLICATE KEY の upsert で pending 行が物件ごとに1つだけになり、エラーにならないこと、
+// failed 行の復活が同時に入った行と衝突した場合（重複キー）も黙ってその行の優先度上げになることを確認する
+func testEnqueueRace() TestResult {
+	result := TestResult{
+		TestName:  "同時キュー登録の一意性",
+		Timestamp: time.Now(),
+	}
+
+	log.Println("\n[Test 77] 同時キュー登録の一意性テスト...")
+
+	const listings = 50
+	fake := &fakeActiveQueue{}
+	for i := 0; i < listings; i++ {
+		// Every other listing failed earlier, so both enqueuers race to revive it
+		if i%2 == 1 {
+			fake.rows = append(fake.rows, models.DetailScrapeQueue{
+				ID: int64(len(fake.rows) + 1), Source: "yahoo", SourcePropertyID: fmt.Sprintf("race%02d", i),
+				Status: models.QueueStatusFailed, Attempts: 5,
+			})
+		}
+	}
+	// A failed row whose listing a concurrent enqueue already made active again
+	fake.rows = append(fake.rows,
+		models.DetailScrapeQueue{ID: int64(len(fake.rows) + 1), Source: "yahoo", SourcePropertyID: "revive", Status: models.QueueStatusFailed, Attempts: 5},
+		models.DetailScrapeQueue{ID: int64(len(fake.rows) + 2), Source: "yahoo", SourcePropertyID: "revive", Status: models.QueueStatusPending},
+	)
+	db, err := openDryRunDB()
+	if err == nil {
+		err = fake.register(db)
+	}
+	if err != nil {
+		result.Message = fmt.Sprintf("dry-run DB: %v", err)
+		log.Printf("  ❌ %s", result.Message)
+		return result
+	}
+	service := queue.NewService(db)
+
+	var problems []string
+	var mu sync.Mutex
+	outcomes := map[queue.EnqueueOutcome]int{}
+
+	// 1. Scheduler (priority 1) and a manual trigger (priority 2) enqueue each listing at once
+	start := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < listings; i++ {
+		spid := fmt.Sprintf("race%02d", i)
+		for _, priority := range []int{queue.PriorityScheduled, queue.PriorityManual} {
+			wg.Add(1)
+			go func(spid string, priority int) {
+				defer wg.Done()
+				<-start
+				got, err := service.Enqueue("yahoo", spid, "https://realestate.yahoo.co.jp/rent/detail/"+spid, priority)
+				mu.Lock()
+				defer mu.Unlock()
+				if err != nil {
+					problems = append(problems, fmt.Sprintf("%s@%d: %v", spid, priority, err))
+				}
+				outcomes[got]++
+			}(spid, priority)
+		}
+	}
+	close(start)
+	wg.Wait()
+
+	// 2. The lookup misses the active row, the revive hits active_key: a silent bump, no error
+	fake.hideActive = true
+	got, err := service.Enqueue("yahoo", "revive", "https://realestate.yahoo.co.jp/rent/detail/revive", queue.PriorityManual)
+	if err != nil || got != queue.EnqueueBumped {
+		problems = append(problems, fmt.Sprintf("revive collision: got %q (%v), want %q", got, err, queue.EnqueueBumped))
+	}
+
+	// Exactly one pending row per listing, at the higher of the two priorities
+	active := map[string][]models.DetailScrapeQueue{}
+	for _, row := range fake.rows {
+		if row.Status == models.QueueStatusPending || row.Status == models.QueueStatusProcessing {
+			active[row.SourcePropertyID] = append(active[row.SourcePropertyID], row)
+		}
+	}
+	for i := 0; i < listings; i++ {
+		spid := fmt.Sprintf("race%02d", i)
+		if rows := active[spid]; len(rows) != 1 || rows[0].Priority != queue.PriorityManual {
+			problems = append(problems, fmt.Sprintf("%s: %d active rows %+v (want one at priority %d)", spid, len(rows), rows, queue.PriorityManual))
+		}
+	}
+	if rows := active["revive"]; len(rows) != 1 || rows[0].Priority != queue.PriorityManual {
+		problems = append(problems, fmt.Sprintf("revive: %d active rows %+v (want one at priority %d)", len(rows), rows, queue.PriorityManual))
+	}
+	if created := outcomes[queue.EnqueueInserted] + outcomes[queue.EnqueueRevived]; created != listings {
+		problems = append(problems, fmt.Sprintf("%d enqueues created a row (want %d): %v", created, listings, outcomes))
+	}
+	problems = append(problems, fake.problems...)
+
+	result.Details = map[string]interface{}{
+		"outcomes": outcomes,
+		"rows":     len(fake.rows),
+		"problems": problems,
+	}
+	if len(problems) > 0 {
+		result.Message = fmt.Sprintf("同時登録で重複または失敗: %v", problems)
+		log.Printf("  ❌ %v", problems)
+		return result
+	}
+
+	result.Success = true
+	result.Message = fmt.Sprintf("%d物件を2経路から同時に登録して pending 行は物件ごとに1つ、重複キーは黙って優先度上げになることを確認", listings)
+	log.Printf("  ✅ %s (%v)", result.Message, outcomes)
+	return result
+}
@@ -0,0 +1,239 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"real-estate-portal/internal/config"
+	"real-estate-portal/internal/events"
+	"real-estate-portal/internal/handlers"
+	"real-estate-portal/internal/models"
+	"real-estate-portal/internal/scheduler"
+	"real-estate-portal/internal/scraper"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Test 85: 進捗イベントのストリーム（オフライン）
+// イベントバスが種類で絞った購読者にだけ配り、読まない購読者でも Publish がブロックせず古いものから捨てて
+// 件数を数えること、GET /api/admin/events が SSE（id / event / data）で絞った種類だけを流し、不明な種類は
+// 400 になること、キューワーカーが item_started / item_failed（error_class・failure）/ cooldown_entered を、
+// スケジューラーが run_finished を発行することを確認する
+func testEventStream() TestResult {
+	result := TestResult{
+		TestName:  "進捗イベントのストリーム",
+		Timestamp: time.Now(),
+	}
+
+	log.Println("\n[Test 85] 進捗イベントのストリームテスト...")
+
+	var problems []string
+
+	// 1. Bus: filtered fan-out, drop-oldest without blocking
+	bus := events.NewBus()
+	all := bus.Subscribe(10)
+	failures := bus.Subscribe(10, events.ItemFailed)
+	slow := bus.Subscribe(2)
+	start := time.Now()
+	for i := 1; i <= 5; i++ {
+		bus.Publish(events.Event{Type: events.ItemStarted, ItemID: int64(i)})
+	}
+	bus.Publish(events.Event{Type: events.ItemFailed, ItemID: 6})
+	if elapsed := time.Since(start); elapsed > 100*time.Millisecond {
+		problems = append(problems, fmt.Sprintf("publishing to a full subscriber took %v", elapsed))
+	}
+	drain := func(sub *events.Subscription) []int64 {
+		var ids []int64
+		for {
+			select {
+			case e, ok := <-sub.Events():
+				if !ok {
+					return ids
+				}
+				ids = append(ids, e.ItemID)
+			default:
+				return ids
+			}
+		}
+	}
+	if ids := drain(all); len(ids) != 6 {
+		problems = append(problems, fmt.Sprintf("unfiltered subscriber got %v (want 6 events)", ids))
+	}
+	if ids := drain(failures); len(ids) != 1 || ids[0] != 6 {
+		problems = append(problems, fmt.Sprintf("item_failed subscriber got %v (want [6])", ids))
+	}
+	if ids := drain(slow); fmt.Sprint(ids) != "[5 6]" || slow.Dropped() != 4 {
+		problems = append(problems, fmt.Sprintf("slow subscriber got %v, dropped %d (want [5 6], 4)", ids, slow.Dropped()))
+	}
+	slow.Close()
+	slow.Close()
+	bus.Publish(events.Event{Type: events.ItemDone})
+	if _, ok := <-slow.Events(); ok {
+		problems = append(problems, "closed subscription still receives events")
+	}
+	bus.CloseAll()
+	if ids := drain(all); len(ids) != 1 {
+		problems = append(problems, fmt.Sprintf("unfiltered subscriber got %v after the close (want the last event)", ids))
+	}
+	if _, ok := <-all.Events(); ok {
+		problems = append(problems, "CloseAll left a subscription open")
+	}
+	if _, err := events.ParseTypes([]string{"item_done,item_failed", "run_finished"}); err != nil {
+		problems = append(problems, fmt.Sprintf("ParseTypes: %v", err))
+	}
+
+	// 2. The SSE endpoint streams only the requested types
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	db, err := openDryRunDB()
+	if err != nil {
+		result.Message = fmt.Sprintf("dry-run DB: %v", err)
+		log.Printf("  ❌ %s", result.Message)
+		return result
+	}
+	r.GET("/api/admin/events", handlers.NewAdminHandler(db, nil).StreamEvents)
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	if resp, err := http.Get(server.URL + "/api/admin/events?type=item_done,bogus"); err != nil || resp.StatusCode != http.StatusBadRequest {
+		problems = append(problems, fmt.Sprintf("unknown type: %v %v (want 400)", resp, err))
+	} else {
+		resp.Body.Close()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/api/admin/events?type=item_done&type=run_finished", nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		problems = append(problems, fmt.Sprintf("stream: %v", err))
+	} else {
+		if ct := resp.Header.Get("Content-Type"); !strings.HasPrefix(ct, "text/event-stream") {
+			problems = append(problems, fmt.Sprintf("stream Content-Type %q", ct))
+		}
+		reader := bufio.NewReader(resp.Body)
+		reader.ReadString('\n') // ": connected" arrives once the subscription exists
+		events.Publish(events.Event{Type: events.ItemStarted, ItemID: 1})
+		events.Publish(events.Event{Type: events.ItemDone, ItemID: 1, PropertyID: "prop-1"})
+		var frame []string
+		for len(frame) < 3 {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				problems = append(problems, fmt.Sprintf("stream read: %v (got %q)", err, frame))
+				break
+			}
+			if line = strings.TrimSpace(line); line != "" && !strings.HasPrefix(line, ":") {
+				frame = append(frame, line)
+			}
+		}
+		if len(frame) == 3 {
+			var e events.Event
+			if !strings.HasPrefix(frame[0], "id: ") || frame[1] != "event: item_done" ||
+				json.Unmarshal([]byte(strings.TrimPrefix(frame[2], "data: ")), &e) != nil || e.PropertyID != "prop-1" {
+				problems = append(problems, fmt.Sprintf("stream frame %q (want the item_done event only)", frame))
+			}
+		}
+		cancel()
+		resp.Body.Close()
+	}
+
+	// 3. The worker reports items and cooldowns: a 404 fails permanently, a WAF block pauses it
+	sub := events.Default.Subscribe(64)
+	defer sub.Close()
+	q := &fakeWorkerQueue{rows: []models.DetailScrapeQueue{
+		{ID: 1, Source: "stub", SourcePropertyID: "gone01", DetailURL: "https://stub.example/detail/gone01/", Status: models.QueueStatusPending},
+		{ID: 2, Source: "stub", SourcePropertyID: "waf01", DetailURL: "https://stub.example/detail/waf01/", Status: models.QueueStatusPending},
+	}}
+	workerDB, err := openFakeWorkerDB(q)
+	if err != nil {
+		result.Message = fmt.Sprintf("dry-run DB: %v", err)
+		log.Printf("  ❌ %s", result.Message)
+		return result
+	}
+	source := newStubSource(func(_ context.Context, url string) (*models.Property, error) {
+		if strings.Contains(url, "waf01") {
+			return nil, fmt.Errorf("%w: block page", scraper.ErrWAFBlocked)
+		}
+		return nil, fmt.Errorf("%w: status code 404", scraper.ErrNotFound)
+	})
+	w := scheduler.NewQueueWorkerWithSources(workerDB, scraper.NewScraper(), scraper.NewRegistry(source))
+	w.SetPollInterval(10 * time.Millisecond)
+	w.SetHealthCheck(func(context.Context) bool { return true })
+	if err := w.Start(); err != nil {
+		problems = append(problems, fmt.Sprintf("worker Start: %v", err))
+	}
+	var got []string
+	byType := make(map[string][]events.Event)
+	for deadline := time.After(3 * time.Second); len(byType[events.CooldownEntered]) == 0 || len(byType[events.ItemFailed]) < 2; {
+		select {
+		case e := <-sub.Events():
+			got = append(got, fmt.Sprintf("%s#%d", e.Type, e.ItemID))
+			byType[e.Type] = append(byType[e.Type], e)
+			continue
+		case <-deadline:
+		}
+		break
+	}
+	if err := w.Stop(); err != nil {
+		problems = append(problems, fmt.Sprintf("worker Stop: %v", err))
+	}
+	if len(byType[events.ItemStarted]) != 2 || len(byType[events.ItemFailed]) != 2 || len(byType[events.CooldownEntered]) != 1 {
+		problems = append(problems, fmt.Sprintf("worker events %v (want 2 started, 2 failed, 1 cooldown)", got))
+	} else {
+		if gone := byType[events.ItemFailed][0]; gone.ItemID != 1 || gone.ErrorClass != "not_found" || gone.Failure != string(scheduler.FailurePermanent) ||
+			gone.Status != models.QueueStatusPermanentFail {
+			problems = append(problems, fmt.Sprintf("404 event: %+v", gone))
+		}
+		if waf := byType[events.ItemFailed][1]; waf.ItemID != 2 || waf.Failure != string(scheduler.FailureCooldown) || waf.Until == nil {
+			problems = append(problems, fmt.Sprintf("WAF event: %+v", waf))
+		}
+		if cooldown := byType[events.CooldownEntered][0]; cooldown.Until == nil || time.Until(*cooldown.Until) < 4*time.Minute {
+			problems = append(problems, fmt.Sprintf("cooldown event: %+v (want about 5 minutes)", cooldown))
+		}
+	}
+
+	// 4. A scheduler run reports its end
+	aliveCheck := false
+	sched := scheduler.NewSchedulerWithSources(db, &config.Config{
+		Scraper: config.ScraperConfig{AliveCheck: config.AliveCheckConfig{Enabled: &aliveCheck}},
+	}, scraper.NewRegistry(source))
+	if err := sched.RunNow(); err != nil {
+		problems = append(problems, fmt.Sprintf("RunNow: %v", err))
+	}
+	var finished *events.Event
+	for deadline := time.After(time.Second); finished == nil; {
+		select {
+		case e := <-sub.Events():
+			if e.Type == events.RunFinished {
+				finished = &e
+			}
+			continue
+		case <-deadline:
+		}
+		break
+	}
+	if finished == nil || finished.Trigger != "manual" || finished.Status != models.SchedulerRunDone {
+		problems = append(problems, fmt.Sprintf("run_finished: %+v", finished))
+	}
+
+	result.Details = map[string]interface{}{
+		"worker_events": got,
+		"problems":      problems,
+	}
+	if len(problems) > 0 {
+		result.Message = fmt.Sprintf("進捗イベントが不正: %v", problems)
+		log.Printf("  ❌ %v", problems)
+		return result
+	}
+
+	result.Success = true
+	result.Message = "イベントが種類ごとに配られて遅い購読者は古いものから捨てられ、SSE で流れ、ワーカーとスケジューラーが発行することを確認"
+	log.Printf("  ✅ %s", result.Message)
+	return result
+}
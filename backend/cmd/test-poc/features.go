@@ -0,0 +1,78 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"real-estate-portal/internal/scraper"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Test 27: 設備・特徴タグの抽出（フィクスチャモードのみ）
+// 人気の特徴・設備のタグが Features（表示ラベル）と Facilities（英語キー）に JSON 配列で入り、
+// タグのないページや既存の空文字行も [] として返ることを確認する
+func testFeatureTags(s *scraper.Scraper, propertyURL string) TestResult {
+	result := TestResult{
+		TestName:  "設備・特徴タグの抽出",
+		Timestamp: time.Now(),
+	}
+
+	log.Println("\n[Test 27] 設備・特徴タグの抽出テスト...")
+
+	// Fixture detail pages share the list page's host; only the property ID differs
+	detailBase := propertyURL[:strings.Index(propertyURL, "/rent/detail/")+len("/rent/detail/")]
+
+	cases := []struct {
+		id         string
+		features   []string
+		facilities []string // must be present
+		absent     []string // must not be present (disabled tag)
+	}{
+		{"features00tags", []string{"オートロック", "バス・トイレ別"}, []string{"auto_lock", "bath_toilet_separate"}, []string{"pet_friendly"}},
+		{"features01nocontext", []string{"オートロック", "バス・トイレ別"}, []string{"auto_lock", "bath_toilet_separate"}, []string{"pet_friendly"}},
+		{"lease03norow", []string{}, nil, nil},
+	}
+
+	var problems []string
+	for _, tc := range cases {
+		p, err := s.ScrapeProperty(detailBase + tc.id + "/")
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: 取得失敗: %v", tc.id, err))
+			continue
+		}
+		if !json.Valid([]byte(p.Facilities)) || !json.Valid([]byte(p.Features)) {
+			problems = append(problems, fmt.Sprintf("%s: stored values are not JSON (facilities=%q features=%q)", tc.id, p.Facilities, p.Features))
+		}
+		if got := p.GetFeatures(); !slices.Equal(got, tc.features) {
+			problems = append(problems, fmt.Sprintf("%s: features=%v, want %v", tc.id, got, tc.features))
+		}
+		facilities := p.GetFacilities()
+		for _, key := range tc.facilities {
+			if !slices.Contains(facilities, key) {
+				problems = append(problems, fmt.Sprintf("%s: facilities missing %s (%v)", tc.id, key, facilities))
+			}
+		}
+		for _, key := range tc.absent {
+			if slices.Contains(facilities, key) {
+				problems = append(problems, fmt.Sprintf("%s: disabled tag %s in facilities", tc.id, key))
+			}
+		}
+	}
+
+	result.Details = map[string]interface{}{
+		"cases":    len(cases),
+		"problems": problems,
+	}
+	if len(problems) > 0 {
+		result.Message = fmt.Sprintf("設備・特徴タグの抽出が不正: %v", problems)
+		log.Printf("  ❌ %v", problems)
+		return result
+	}
+
+	result.Success = true
+	result.Message = fmt.Sprintf("%d件のページで設備・特徴をJSON配列として抽出", len(cases))
+	log.Printf("  ✅ %s", result.Message)
+	return result
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"real-estate-portal/internal/scraper"
+	"strings"
+	"time"
+)
+
+// オフラインのフィクスチャモード
+// 保存済みの一覧/詳細ページを httptest サーバーで配信し、実サイトにアクセスせずに検証する
+
+// startFixtureServer serves saved list/detail pages from dir
+func startFixtureServer(dir string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var file string
+		switch {
+		case strings.Contains(r.URL.Path, "/rent/detail/"):
+			id := strings.Trim(strings.TrimPrefix(r.URL.Path, "/rent/detail/"), "/")
+			file = filepath.Join(dir, "detail_"+id+".html")
+			if _, err := os.Stat(file); err != nil {
+				file = filepath.Join(dir, "detail.html")
+			}
+		case strings.Contains(r.URL.Path, "/rent/building/"):
+			id := strings.Trim(strings.TrimPrefix(r.URL.Path, "/rent/building/"), "/")
+			file = filepath.Join(dir, "building_"+filepath.Base(id)+".html")
+		case strings.Contains(r.URL.Path, "/list"):
+			file = filepath.Join(dir, "list.html")
+			if page := r.URL.Query().Get("page"); page != "" {
+				paged := filepath.Join(dir, "list_page"+filepath.Base(page)+".html")
+				if _, err := os.Stat(paged); err == nil {
+					file = paged
+				}
+			}
+		case r.URL.Path == "/":
+			w.WriteHeader(http.StatusOK)
+			return
+		case strings.HasPrefix(r.URL.Path, "/images/"):
+			// Placeholder for images the scraper checks with a HEAD request (verifyImageURL)
+			w.Header().Set("Content-Type", "image/gif")
+			w.WriteHeader(http.StatusOK)
+			return
+		default:
+			http.NotFound(w, r)
+			return
+		}
+
+		data, err := os.ReadFile(file)
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write(data)
+	}))
+}
+
+// newFixtureScraper creates a scraper pointed at the fixture server with pacing disabled
+// (image checks on, as on the PoC path)
+func newFixtureScraper(baseURL string) *scraper.Scraper {
+	// Keep the shared limiters from sleeping 8-12s between fixture requests
+	scraper.ConfigureSourceLimits("yahoo", 10*time.Millisecond, 0, 1000)
+
+	return scraper.NewScraperWithConfig(scraper.ScraperConfig{
+		Timeout:      10 * time.Second,
+		MaxRetries:   0,
+		RetryDelay:   100 * time.Millisecond,
+		RequestDelay: 0,
+		VerifyImages: true,
+		BaseURL:      baseURL,
+		FixtureMode:  true,
+	})
+}
+
+// Test 5: 抽出フィールドのカバレッジ（フィクスチャモードのみ）
+// 保存済みページから主要フィールドがすべて抽出できることを確認する
+func testFieldCoverage(s *scraper.Scraper, propertyURL string) TestResult {
+	result := TestResult{
+		TestName:  "抽出フィールドのカバレッジ",
+		Timestamp: time.Now(),
+	}
+
+	log.Println("\n[Test 5] 抽出フィールドのカバレッジテスト...")
+
+	property, err := s.ScrapeProperty(propertyURL)
+	if err != nil {
+		result.Message = fmt.Sprintf("物件詳細の取得失敗: %v", err)
+		return result
+	}
+
+	checks := map[string]bool{
+		"title":        property.Title != "" && property.Title != "No Title",
+		"image_url":    property.ImageURL != "",
+		"rent":         property.Rent != nil && *property.Rent > 0,
+		"floor_plan":   property.FloorPlan != "",
+		"area":         property.Area != nil && *property.Area > 0,
+		"walk_time":    property.WalkTime != nil && *property.WalkTime > 0,
+		"station":      property.Station != "",
+		"address":      property.Address != "",
+		"building_age": property.BuildingAge != nil,
+		"floor":        property.Floor != nil,
+		"stations":     len(s.GetLastStations()) > 0,
+		"free_rent":    property.FreeRent && property.FreeRentMonths != nil && *property.FreeRentMonths == 1,
+		"no_brokerage": property.NoBrokerageFee,
+	}
+
+	var missing []string
+	for field, ok := range checks {
+		if !ok {
+			missing = append(missing, field)
+		}
+	}
+
+	result.Details = map[string]interface{}{
+		"covered": len(checks) - len(missing),
+		"total":   len(checks),
+		"missing": missing,
+	}
+	if len(missing) == 0 {
+		result.Success = true
+		result.Message = fmt.Sprintf("全%d項目を抽出", len(checks))
+		log.Printf("  ✅ 全フィールド抽出成功")
+	} else {
+		result.Message = fmt.Sprintf("未抽出フィールドあり: %s", strings.Join(missing, ", "))
+		log.Printf("  ❌ 未抽出: %v", missing)
+	}
+
+	return result
+}
+
+// Test 6: キャンペーン終了の判定（フィクスチャモードのみ）
+// 「フリーレントキャンペーン終了」の物件でフラグが立たないことを確認する
+func testEndedCampaign(s *scraper.Scraper, propertyURL string) TestResult {
+	result := TestResult{
+		TestName:  "キャンペーン終了の判定",
+		Timestamp: time.Now(),
+	}
+
+	log.Println("\n[Test 6] キャンペーン終了の判定テスト...")
+
+	property, err := s.ScrapeProperty(propertyURL)
+	if err != nil {
+		result.Message = fmt.Sprintf("物件詳細の取得失敗: %v", err)
+		return result
+	}
+
+	result.Details = map[string]interface{}{
+		"free_rent":        property.FreeRent,
+		"no_brokerage_fee": property.NoBrokerageFee,
+	}
+	if property.FreeRent || property.NoBrokerageFee {
+		result.Message = "終了済みキャンペーンがフラグとして抽出されました"
+		log.Printf("  ❌ free_rent=%v no_brokerage_fee=%v", property.FreeRent, property.NoBrokerageFee)
+		return result
+	}
+
+	result.Success = true
+	result.Message = "終了済みキャンペーンはフラグなし"
+	log.Printf("  ✅ キャンペーン終了を正しく判定")
+	return result
+}
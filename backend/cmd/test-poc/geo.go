@@ -0,0 +1,76 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"real-estate-portal/internal/models"
+	"real-estate-portal/internal/scraper"
+	"strings"
+	"time"
+)
+
+// Test 39: 緯度・経度の抽出（フィクスチャモードのみ）
+// 埋め込みJSONの座標が地図iframeより優先され、JSONにない場合は iframe から取得し、
+// 日本の範囲外（緯度経度の取り違え）は保存されず、_geo として Meilisearch 形式で出力されることを確認する
+func testCoordinateExtraction(s *scraper.Scraper, propertyURL string) TestResult {
+	result := TestResult{
+		TestName:  "緯度・経度の抽出",
+		Timestamp: time.Now(),
+	}
+
+	log.Println("\n[Test 39] 緯度・経度の抽出テスト...")
+
+	detailBase := propertyURL[:strings.Index(propertyURL, "/rent/detail/")+len("/rent/detail/")]
+
+	cases := []struct {
+		id       string
+		lat, lng *float64
+	}{
+		{"geo00json", floatp(35.690921), floatp(139.700258)},   // JSON wins over the iframe (rounded to 6 places)
+		{"geo01iframe", floatp(35.658034), floatp(139.701636)}, // lazy-loaded iframe data-src
+		{"geo02outside", nil, nil},                             // swapped values are outside Japan
+		{"station01one", nil, nil},                             // no map at all
+	}
+
+	var problems []string
+	for _, tc := range cases {
+		property, err := s.ScrapeProperty(detailBase + tc.id + "/")
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: 取得失敗: %v", tc.id, err))
+			continue
+		}
+		if !floatPtrEq(property.Latitude, tc.lat) || !floatPtrEq(property.Longitude, tc.lng) {
+			problems = append(problems, fmt.Sprintf("%s: lat=%s lng=%s (want %s / %s)", tc.id,
+				fmtFloatPtr(property.Latitude), fmtFloatPtr(property.Longitude), fmtFloatPtr(tc.lat), fmtFloatPtr(tc.lng)))
+		}
+	}
+
+	// Meilisearch document: _geo only when both coordinates are set; a lone one is dropped before saving
+	p := models.Property{Latitude: floatp(35.690921), Longitude: floatp(139.700258)}
+	p.Geo = p.GeoPoint()
+	doc, _ := json.Marshal(p)
+	if !strings.Contains(string(doc), `"_geo":{"lat":35.690921,"lng":139.700258}`) {
+		problems = append(problems, fmt.Sprintf("_geo not in document: %s", doc))
+	}
+	half := models.Property{Latitude: floatp(35.690921)}
+	half.NormalizeCoordinates()
+	if half.Latitude != nil || half.GeoPoint() != nil {
+		problems = append(problems, "latitude without longitude was kept")
+	}
+
+	result.Details = map[string]interface{}{
+		"cases":    len(cases),
+		"problems": problems,
+	}
+	if len(problems) > 0 {
+		result.Message = fmt.Sprintf("座標抽出が不正: %v", problems)
+		log.Printf("  ❌ %v", problems)
+		return result
+	}
+
+	result.Success = true
+	result.Message = "埋め込みJSON→地図iframeの順に座標を取得、日本の範囲外は破棄、_geo で出力"
+	log.Printf("  ✅ %s", result.Message)
+	return result
+}
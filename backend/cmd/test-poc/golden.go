@@ -0,0 +1,161 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"real-estate-portal/internal/models"
+	"real-estate-portal/internal/scraper"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// goldenParse is what a golden JSON file holds: the parsed property (fetched_at zeroed) and its stations
+type goldenParse struct {
+	Property json.RawMessage `json:"property"`
+	Stations json.RawMessage `json:"stations"`
+}
+
+// parseGoldenPage parses a saved detail page with ParsePropertyDocument (no HTTP) and renders
+// it in the golden file format
+func parseGoldenPage(htmlPath string) ([]byte, error) {
+	f, err := os.Open(htmlPath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	doc, err := goquery.NewDocumentFromReader(f)
+	if err != nil {
+		return nil, err
+	}
+
+	name := strings.TrimSuffix(filepath.Base(htmlPath), ".html")
+	s := scraper.NewScraperWithConfig(scraper.ScraperConfig{FixtureMode: true})
+	property, stations, err := s.ParsePropertyDocument(doc, "https://realestate.yahoo.co.jp/rent/detail/"+name+"/")
+	if err != nil {
+		return nil, err
+	}
+	property.FetchedAt = time.Time{}
+
+	var out goldenParse
+	if out.Property, err = json.Marshal(property); err != nil {
+		return nil, err
+	}
+	if stations == nil {
+		stations = []models.PropertyStation{}
+	}
+	if out.Stations, err = json.Marshal(stations); err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(out, "", "  ")
+}
+
+// goldenDiff lists the top-level property fields (and the station list) that differ
+func goldenDiff(got, want []byte) ([]string, error) {
+	var g, w struct {
+		Property map[string]interface{} `json:"property"`
+		Stations []interface{}          `json:"stations"`
+	}
+	if err := json.Unmarshal(got, &g); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(want, &w); err != nil {
+		return nil, err
+	}
+
+	keys := map[string]bool{}
+	for k := range g.Property {
+		keys[k] = true
+	}
+	for k := range w.Property {
+		keys[k] = true
+	}
+	var diffs []string
+	for k := range keys {
+		if !reflect.DeepEqual(g.Property[k], w.Property[k]) {
+			diffs = append(diffs, fmt.Sprintf("%s=%v (want %v)", k, g.Property[k], w.Property[k]))
+		}
+	}
+	sort.Strings(diffs)
+	if !reflect.DeepEqual(g.Stations, w.Stations) {
+		diffs = append(diffs, fmt.Sprintf("stations=%v (want %v)", g.Stations, w.Stations))
+	}
+	return diffs, nil
+}
+
+// Test 54: 保存済み詳細ページのゴールデン比較（フィクスチャモードのみ）
+// フィクスチャと並ぶ testdata/golden の各 HTML を HTTP なしで ParsePropertyDocument に通し、同名の
+// JSON と物件フィールド・駅リストが一致することを確認する（-update-golden で JSON を書き直す）
+func testGoldenParse(fixtureDir string, update bool) TestResult {
+	result := TestResult{
+		TestName:  "保存済み詳細ページのゴールデン比較",
+		Timestamp: time.Now(),
+	}
+
+	log.Println("\n[Test 54] 保存済み詳細ページのゴールデン比較テスト...")
+
+	goldenDir := filepath.Join(filepath.Dir(filepath.Clean(fixtureDir)), "golden")
+	pages, err := filepath.Glob(filepath.Join(goldenDir, "*.html"))
+	if err != nil || len(pages) == 0 {
+		result.Message = fmt.Sprintf("ゴールデンページが見つからない: %s (%v)", goldenDir, err)
+		log.Printf("  ❌ %s", result.Message)
+		return result
+	}
+
+	var problems []string
+	for _, page := range pages {
+		name := strings.TrimSuffix(filepath.Base(page), ".html")
+		jsonPath := strings.TrimSuffix(page, ".html") + ".json"
+		got, err := parseGoldenPage(page)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: パース失敗: %v", name, err))
+			continue
+		}
+		if update {
+			if err := os.WriteFile(jsonPath, append(got, '\n'), 0o644); err != nil {
+				problems = append(problems, fmt.Sprintf("%s: 書き込み失敗: %v", name, err))
+			} else {
+				log.Printf("  updated %s", jsonPath)
+			}
+			continue
+		}
+
+		want, err := os.ReadFile(jsonPath)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: 期待値なし（-update-golden で作成）: %v", name, err))
+			continue
+		}
+		diffs, err := goldenDiff(got, want)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: 比較失敗: %v", name, err))
+			continue
+		}
+		for _, d := range diffs {
+			problems = append(problems, name+": "+d)
+		}
+	}
+
+	result.Details = map[string]interface{}{
+		"pages":    len(pages),
+		"problems": problems,
+	}
+	if len(problems) > 0 {
+		result.Message = fmt.Sprintf("ゴールデンと不一致: %v", problems)
+		log.Printf("  ❌ %v", problems)
+		return result
+	}
+
+	result.Success = true
+	result.Message = fmt.Sprintf("%d件の保存済みページの抽出結果がゴールデンJSONと一致", len(pages))
+	if update {
+		result.Message = fmt.Sprintf("%d件のゴールデンJSONを更新", len(pages))
+	}
+	log.Printf("  ✅ %s", result.Message)
+	return result
+}
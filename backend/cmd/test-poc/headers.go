@@ -0,0 +1,157 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"real-estate-portal/internal/scraper"
+	"strings"
+	"sync"
+	"time"
+)
+
+// headerProfileUA is the source profile's User-Agent in Test 64 (Chrome 126 on macOS)
+const headerProfileUA = "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/126.0.0.0 Safari/537.36"
+
+// Test 64: ブラウザと同じヘッダー一式とReferer（オフライン）
+// 一覧・詳細・画像確認・robots.txt がそれぞれブラウザと同じヘッダー一式（UAと一致する
+// クライアントヒント、取得元プロファイルの上書き・削除を含む）で送られ、一覧から見つけた
+// 詳細ページにはその一覧ページがRefererとして付くことを確認する
+func testHeaderProfile() TestResult {
+	result := TestResult{
+		TestName:  "ブラウザと同じヘッダー一式とReferer",
+		Timestamp: time.Now(),
+	}
+
+	log.Println("\n[Test 64] ヘッダープロファイルテスト...")
+
+	var mu sync.Mutex
+	sent := map[string]http.Header{} // "METHOD path" → headers of the last such request
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		sent[r.Method+" "+r.URL.Path] = r.Header.Clone()
+		mu.Unlock()
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		switch {
+		case r.URL.Path == "/robots.txt":
+			w.Header().Set("Content-Type", "text/plain")
+			fmt.Fprint(w, "User-agent: *\nAllow: /\n")
+		case r.URL.Path == "/list/1":
+			fmt.Fprintf(w, `<html><body><input type="checkbox" class="_propertyCheckbox" value="0000%s">
+				<a rel="next" href="/list/2">次へ</a></body></html>`, strings.Repeat("1", 40))
+		case r.URL.Path == "/list/2":
+			fmt.Fprintf(w, `<html><body><input type="checkbox" class="_propertyCheckbox" value="0000%s"></body></html>`,
+				strings.Repeat("2", 40))
+		case strings.HasPrefix(r.URL.Path, "/rent/detail/"):
+			fmt.Fprintf(w, `<html><head><title>ヘッダー確認</title><meta property="og:image" content="%s/img/h.jpg"></head><body><h1>ヘッダー確認</h1></body></html>`, server.URL)
+		}
+	}))
+	defer server.Close()
+
+	s := scraper.NewScraperWithConfig(scraper.ScraperConfig{
+		Timeout:       5 * time.Second,
+		BaseURL:       server.URL,
+		FixtureMode:   true,
+		RespectRobots: true,
+		VerifyImages:  true,
+		Profile: &scraper.HeaderProfile{
+			UserAgents: []string{headerProfileUA},
+			Headers: map[string]string{
+				"Accept-Language":           "ja-JP,ja;q=0.9", // override
+				"Upgrade-Insecure-Requests": "",               // removed
+				"X-Poc-Source":              "yahoo",          // added
+			},
+		},
+	})
+
+	var problems []string
+	ctx := context.Background()
+	listURL := server.URL + "/list/1"
+	page, err := s.ScrapeListPagesWithMetaContext(ctx, listURL, 2)
+	if err != nil || len(page.URLs) != 2 {
+		result.Message = fmt.Sprintf("一覧取得失敗: %v (%d URLs)", err, len(page.URLs))
+		return result
+	}
+	// As the worker does with a queue row's referer_url
+	for _, u := range page.URLs {
+		if _, err := s.ScrapeDetail(scraper.WithReferer(ctx, page.Referer(u, listURL)), u, scraper.Validators{}); err != nil {
+			problems = append(problems, fmt.Sprintf("detail %s: %v", u, err))
+		}
+	}
+	// A detail scraped directly (no list page) has no Referer
+	direct := "/rent/detail/0000" + strings.Repeat("3", 40) + "/"
+	if _, err := s.ScrapeDetail(ctx, server.URL+direct, scraper.Validators{}); err != nil {
+		problems = append(problems, fmt.Sprintf("direct detail: %v", err))
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	expect := func(key string, want map[string]string) {
+		h, ok := sent[key]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("no %s request", key))
+			return
+		}
+		for name, value := range want {
+			if got := h.Get(name); got != value {
+				problems = append(problems, fmt.Sprintf("%s %s=%q (want %q)", key, name, got, value))
+			}
+		}
+	}
+	common := map[string]string{
+		"User-Agent":                headerProfileUA,
+		"Accept-Language":           "ja-JP,ja;q=0.9",
+		"Accept-Encoding":           "gzip, deflate, br",
+		"X-Poc-Source":              "yahoo",
+		"Upgrade-Insecure-Requests": "",
+		"sec-ch-ua":                 `"Chromium";v="126", "Google Chrome";v="126", "Not-A.Brand";v="99"`,
+		"sec-ch-ua-mobile":          "?0",
+		"sec-ch-ua-platform":        `"macOS"`,
+	}
+	with := func(extra map[string]string) map[string]string {
+		out := map[string]string{}
+		for k, v := range common {
+			out[k] = v
+		}
+		for k, v := range extra {
+			out[k] = v
+		}
+		return out
+	}
+	navigate := map[string]string{"Sec-Fetch-Mode": "navigate", "Sec-Fetch-Dest": "document", "Sec-Fetch-User": "?1"}
+	expect("GET /list/1", with(navigate))
+	expect("GET /list/1", map[string]string{"Sec-Fetch-Site": "none", "Referer": ""})
+	expect("GET /rent/detail/0000"+strings.Repeat("1", 40), with(map[string]string{"Referer": listURL, "Sec-Fetch-Site": "same-origin"}))
+	expect("GET /rent/detail/0000"+strings.Repeat("2", 40), with(map[string]string{"Referer": server.URL + "/list/2", "Sec-Fetch-Dest": "document"}))
+	expect("GET "+direct, map[string]string{"Referer": "", "Sec-Fetch-Site": "none"})
+	expect("HEAD /img/h.jpg", with(map[string]string{"Sec-Fetch-Dest": "image", "Sec-Fetch-Mode": "no-cors", "Referer": server.URL + "/"}))
+	expect("GET /robots.txt", with(map[string]string{"Accept": "text/plain,*/*;q=0.8", "Sec-Fetch-Dest": "empty"}))
+
+	// Non-Chromium User-Agents get no client hints
+	firefox := scraper.NewScraperWithConfig(scraper.ScraperConfig{
+		Profile: &scraper.HeaderProfile{UserAgents: []string{"Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:127.0) Gecko/20100101 Firefox/127.0"}},
+	})
+	req, _ := http.NewRequest(http.MethodGet, "https://realestate.yahoo.co.jp/rent/", nil)
+	firefox.ApplyHeaders(req, "https://realestate.yahoo.co.jp/")
+	if req.Header.Get("sec-ch-ua") != "" || req.Header.Get("Sec-Fetch-Site") != "same-origin" {
+		problems = append(problems, fmt.Sprintf("firefox: sec-ch-ua=%q Sec-Fetch-Site=%q", req.Header.Get("sec-ch-ua"), req.Header.Get("Sec-Fetch-Site")))
+	}
+
+	result.Details = map[string]interface{}{
+		"requests": len(sent),
+		"problems": problems,
+	}
+	if len(problems) > 0 {
+		result.Message = fmt.Sprintf("ヘッダーが不正: %v", problems)
+		log.Printf("  ❌ %v", problems)
+		return result
+	}
+
+	result.Success = true
+	result.Message = "一覧・詳細・画像・robots.txt をブラウザと同じヘッダー一式で送信し、一覧から見つけた詳細には一覧ページをRefererに付与"
+	log.Printf("  ✅ %s", result.Message)
+	return result
+}
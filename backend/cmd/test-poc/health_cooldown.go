@@ -0,0 +1,148 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"real-estate-portal/internal/scheduler"
+	"real-estate-portal/internal/scraper"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Test 69: WAF ヘルスチェックのクールダウン（オフライン）
+// ヘルスチェックが失敗しても QueueWorker.Start はすぐ戻り、クールダウン中はキューを処理せず、明けた次の
+// ティックで再チェックして再開すること、クールダウンが scraping_state に保存されること、Stop がクールダウン中や
+// ヘルスチェック中でもすぐ戻ることを確認する
+func testHealthCooldown() TestResult {
+	result := TestResult{
+		TestName:  "WAF ヘルスチェックのクールダウン",
+		Timestamp: time.Now(),
+	}
+
+	log.Println("\n[Test 69] WAF ヘルスチェックのクールダウンテスト...")
+
+	var problems []string
+
+	// Dry-run DB: no rows anywhere (First reports not found), writes to scraping_state recorded
+	var mu sync.Mutex
+	var stateWrites []string
+	db, err := openDryRunDB()
+	if err == nil {
+		err = db.Callback().Query().After("gorm:query").Register("poc:not_found", func(tx *gorm.DB) {
+			if tx.Statement.RaiseErrorOnNotFound && tx.Error == nil {
+				tx.AddError(gorm.ErrRecordNotFound)
+			}
+		})
+	}
+	if err == nil {
+		record := func(tx *gorm.DB) {
+			if tx.Statement.Table == "scraping_state" {
+				mu.Lock()
+				defer mu.Unlock()
+				stateWrites = append(stateWrites, tx.Dialector.Explain(tx.Statement.SQL.String(), tx.Statement.Vars...))
+			}
+		}
+		if err = db.Callback().Update().After("gorm:update").Register("poc:state_update", record); err == nil {
+			err = db.Callback().Create().After("gorm:create").Register("poc:state_create", record)
+		}
+	}
+	if err != nil {
+		result.Message = fmt.Sprintf("dry-run DB: %v", err)
+		log.Printf("  ❌ %s", result.Message)
+		return result
+	}
+
+	newWorker := func(check func(ctx context.Context) bool, cooldown time.Duration) *scheduler.QueueWorker {
+		w := scheduler.NewQueueWorkerWithScraper(db, scraper.NewScraper())
+		w.SetPollInterval(20 * time.Millisecond)
+		w.SetHealthCooldowns(cooldown)
+		w.SetHealthCheck(check)
+		return w
+	}
+
+	// 1. Blocked, then healthy: Start returns at once, processing waits out the 200ms cooldown
+	var checks int32
+	w := newWorker(func(context.Context) bool {
+		return atomic.AddInt32(&checks, 1) > 1
+	}, 200*time.Millisecond)
+	start := time.Now()
+	w.Start()
+	if elapsed := time.Since(start); elapsed > 100*time.Millisecond {
+		problems = append(problems, fmt.Sprintf("Start blocked for %v", elapsed.Round(time.Millisecond)))
+	}
+	time.Sleep(100 * time.Millisecond)
+	if status := w.HealthCooldown(); !status.Active || status.Failures != 1 || atomic.LoadInt32(&checks) != 1 {
+		problems = append(problems, fmt.Sprintf("during cooldown: %+v, %d checks (want active, 1 failure, 1 check)", status, atomic.LoadInt32(&checks)))
+	}
+	var recovered time.Duration
+	for deadline := time.Now().Add(2 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		if status := w.HealthCooldown(); status.Checked {
+			recovered = time.Since(start)
+			break
+		}
+	}
+	if recovered == 0 {
+		problems = append(problems, fmt.Sprintf("never recovered: %+v, %d checks", w.HealthCooldown(), atomic.LoadInt32(&checks)))
+	} else if recovered < 200*time.Millisecond {
+		problems = append(problems, fmt.Sprintf("rechecked after %v (want after the 200ms cooldown)", recovered.Round(time.Millisecond)))
+	}
+	if status := w.HealthCooldown(); status.Active || status.Failures != 0 || atomic.LoadInt32(&checks) != 2 {
+		problems = append(problems, fmt.Sprintf("after recovery: %+v, %d checks (want inactive, 0 failures, 2 checks)", status, atomic.LoadInt32(&checks)))
+	}
+	w.Stop()
+
+	// 2. The cooldown and the recovery were written to scraping_state
+	mu.Lock()
+	writes := strings.Join(stateWrites, "\n")
+	mu.Unlock()
+	if !strings.Contains(writes, "blocked_until") || !strings.Contains(writes, "WAF detected") {
+		problems = append(problems, fmt.Sprintf("cooldown not persisted: %q", writes))
+	}
+
+	// 3. Stop ends a 1h cooldown at once
+	w = newWorker(func(context.Context) bool { return false }, time.Hour)
+	w.Start()
+	time.Sleep(50 * time.Millisecond)
+	start = time.Now()
+	w.Stop()
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		problems = append(problems, fmt.Sprintf("Stop during cooldown took %v", elapsed.Round(time.Millisecond)))
+	}
+
+	// 4. Stop cancels a health check in progress, which does not count as a failure
+	w = newWorker(func(ctx context.Context) bool {
+		<-ctx.Done()
+		return false
+	}, time.Hour)
+	w.Start()
+	time.Sleep(50 * time.Millisecond)
+	start = time.Now()
+	w.Stop()
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		problems = append(problems, fmt.Sprintf("Stop during the health check took %v", elapsed.Round(time.Millisecond)))
+	}
+	if status := w.HealthCooldown(); status.Active || status.Failures != 0 {
+		problems = append(problems, fmt.Sprintf("canceled check: %+v (want no cooldown)", status))
+	}
+
+	result.Details = map[string]interface{}{
+		"recovered_ms": recovered.Milliseconds(),
+		"problems":     problems,
+	}
+	if len(problems) > 0 {
+		result.Message = fmt.Sprintf("ヘルスチェックのクールダウンが不正: %v", problems)
+		log.Printf("  ❌ %v", problems)
+		return result
+	}
+
+	result.Success = true
+	result.Message = fmt.Sprintf("Start はすぐ戻り、クールダウン明けの再チェックで処理を再開（%v）、Stop はクールダウン中もすぐ戻ることを確認",
+		recovered.Round(time.Millisecond))
+	log.Printf("  ✅ %s", result.Message)
+	return result
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"real-estate-portal/internal/scraper"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Test 51: 画像確認の省略とキャッシュ（フィクスチャモードのみ）
+// VerifyImages が無効なら画像のHEAD確認を送らずにそのまま使い、有効なら確認結果をURLごとに
+// キャッシュして2回目以降は送らないこと、省略・失敗件数がスクレイプ統計に出ることを確認する
+func testImageVerification() TestResult {
+	result := TestResult{
+		TestName:  "画像確認の省略とキャッシュ",
+		Timestamp: time.Now(),
+	}
+
+	log.Println("\n[Test 51] 画像確認の省略とキャッシュテスト...")
+
+	var mu sync.Mutex
+	heads := map[string]int{}
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead {
+			mu.Lock()
+			heads[r.URL.Path]++
+			mu.Unlock()
+		}
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/rent/detail/"):
+			// No __SERVER_SIDE_CONTEXT__ images: og:image and the 間取り図 <img> need checking
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			fmt.Fprintf(w, `<html><head><title>画像確認</title><meta property="og:image" content="%s/img/main.jpg"></head>`+
+				`<body><h1>画像確認</h1><img alt="間取り図" src="/img/missing.gif"></body></html>`, server.URL)
+		case r.URL.Path == "/img/main.jpg":
+			w.Header().Set("Content-Type", "image/jpeg")
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	newScraper := func(verify bool) *scraper.Scraper {
+		return scraper.NewScraperWithConfig(scraper.ScraperConfig{
+			Timeout:      5 * time.Second,
+			BaseURL:      server.URL,
+			FixtureMode:  true,
+			VerifyImages: verify,
+		})
+	}
+	headCount := func() int {
+		mu.Lock()
+		defer mu.Unlock()
+		n := 0
+		for _, c := range heads {
+			n += c
+		}
+		return n
+	}
+
+	var problems []string
+	check := func(name string, s *scraper.Scraper, id string, wantLayout bool, wantHeads, skipped, failed int) {
+		before := headCount()
+		property, err := s.ScrapeProperty(server.URL + "/rent/detail/" + id + "/")
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: 取得失敗: %v", name, err))
+			return
+		}
+		if property.ImageURL != server.URL+"/img/main.jpg" {
+			problems = append(problems, fmt.Sprintf("%s: ImageURL=%q", name, property.ImageURL))
+		}
+		if (property.RoomLayoutImageURL != "") != wantLayout {
+			problems = append(problems, fmt.Sprintf("%s: RoomLayoutImageURL=%q (want kept=%v)", name, property.RoomLayoutImageURL, wantLayout))
+		}
+		if sent := headCount() - before; sent != wantHeads {
+			problems = append(problems, fmt.Sprintf("%s: %d HEAD requests (want %d)", name, sent, wantHeads))
+		}
+		st := s.LastStats()
+		if st.ImageChecksSkipped != skipped || st.ImageChecksFailed != failed {
+			problems = append(problems, fmt.Sprintf("%s: skipped=%d failed=%d (want %d / %d)", name, st.ImageChecksSkipped, st.ImageChecksFailed, skipped, failed))
+		}
+	}
+
+	// 1. Off (queue default): both images kept unchecked, no extra requests
+	check("off", newScraper(false), "img00off", true, 0, 2, 0)
+	// 2. On: main photo verified, unreachable layout dropped
+	check("on", newScraper(true), "img01on", false, 2, 0, 1)
+	// 3. On again (another scraper, same images): answered from the cache
+	check("cached", newScraper(true), "img02cached", false, 0, 2, 1)
+
+	result.Details = map[string]interface{}{
+		"heads":    heads,
+		"problems": problems,
+	}
+	if len(problems) > 0 {
+		result.Message = fmt.Sprintf("画像確認が不正: %v", problems)
+		log.Printf("  ❌ %v", problems)
+		return result
+	}
+
+	result.Success = true
+	result.Message = "無効時は未確認で採用、有効時は1回だけHEAD確認してキャッシュすることを確認"
+	log.Printf("  ✅ %s", result.Message)
+	return result
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"real-estate-portal/internal/models"
+	"real-estate-portal/internal/scraper"
+	"time"
+)
+
+// Test 24: 画像ギャラリーの抽出（フィクスチャモードのみ）
+// 詳細ページの埋め込みJSON・img から全画像を掲載順に取り出し、重複除去と上限が効くことを確認する
+func testGalleryImages(s *scraper.Scraper, propertyURL string) TestResult {
+	result := TestResult{
+		TestName:  "画像ギャラリーの抽出",
+		Timestamp: time.Now(),
+	}
+
+	log.Println("\n[Test 24] 画像ギャラリーの抽出テスト...")
+
+	var problems []string
+
+	property, err := s.ScrapeProperty(propertyURL)
+	if err != nil {
+		result.Message = fmt.Sprintf("スクレイピング失敗: %v", err)
+		return result
+	}
+	images := s.GetLastImagesAsModels(property.ID)
+
+	// detail.html carries og:image plus ExternalImageUrl and one ResizedExternalImageUrls entry
+	if len(images) < 2 {
+		problems = append(problems, fmt.Sprintf("expected a multi-image gallery, got %d", len(images)))
+	}
+	if len(images) > 0 && images[0].ImageURL != property.ImageURL {
+		problems = append(problems, fmt.Sprintf("first image %s is not the primary image %s", images[0].ImageURL, property.ImageURL))
+	}
+	for i, img := range images {
+		if img.SortOrder != i || img.PropertyID != property.ID {
+			problems = append(problems, fmt.Sprintf("image %d: sort_order=%d property_id=%s", i, img.SortOrder, img.PropertyID))
+		}
+	}
+
+	// Duplicates and blanks are dropped, and the gallery is capped
+	var urls []string
+	for i := 0; i < models.MaxPropertyImages+5; i++ {
+		u := fmt.Sprintf("https://realestate-pctr.c.yimg.jp/gallery%02d", i)
+		urls = append(urls, u, u, " ")
+	}
+	capped := models.NewPropertyImages("p1", urls)
+	if len(capped) != models.MaxPropertyImages {
+		problems = append(problems, fmt.Sprintf("cap: got %d images, want %d", len(capped), models.MaxPropertyImages))
+	}
+	seen := make(map[string]bool)
+	for i, img := range capped {
+		if seen[img.ImageURL] || img.ImageURL == "" || img.SortOrder != i {
+			problems = append(problems, fmt.Sprintf("cap: bad image %d (%q, sort_order=%d)", i, img.ImageURL, img.SortOrder))
+		}
+		seen[img.ImageURL] = true
+	}
+	if got := models.NewPropertyImages("p1", nil); len(got) != 0 {
+		problems = append(problems, fmt.Sprintf("empty extraction produced %d images", len(got)))
+	}
+
+	result.Details = map[string]interface{}{
+		"gallery_count": len(images),
+		"primary_image": property.ImageURL,
+		"problems":      problems,
+	}
+	if len(problems) > 0 {
+		result.Message = fmt.Sprintf("画像ギャラリーの検証失敗: %v", problems)
+		log.Printf("  ❌ %v", problems)
+		return result
+	}
+
+	result.Success = true
+	result.Message = fmt.Sprintf("画像%d件を掲載順に抽出、重複除去・上限%d件OK", len(images), models.MaxPropertyImages)
+	log.Printf("  ✅ %s", result.Message)
+	return result
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"real-estate-portal/internal/scraper"
+	"strings"
+	"time"
+)
+
+// Test 44: 間取り図の抽出（フィクスチャモードのみ）
+// 埋め込みJSONの画像リストで「間取り図」とされた画像、JSONの無いページでは alt="間取り図" の画像
+// （HEADで到達確認）を RoomLayoutImageURL にし、メイン写真しか無い物件では空のままにすることを確認する
+func testRoomLayoutImage(s *scraper.Scraper, propertyURL string) TestResult {
+	result := TestResult{
+		TestName:  "間取り図の抽出",
+		Timestamp: time.Now(),
+	}
+
+	log.Println("\n[Test 44] 間取り図の抽出テスト...")
+
+	serverBase := propertyURL[:strings.Index(propertyURL, "/rent/detail/")]
+	detailBase := serverBase + "/rent/detail/"
+	pages := []struct {
+		id   string
+		want string
+	}{
+		{"layout00json", "https://realestate-pctr.c.yimg.jp/fixtureLayout0101"}, // Category 間取り図 in the image list
+		{"layout01mainonly", ""},                               // RoomLayoutImageUrl is the main photo
+		{"layout02img", serverBase + "/images/layout0301.gif"}, // lazy-loaded <img alt="間取り図">
+		{"layout03noimage", ""},                                // <img alt="間取り図"> answers 404
+		{"station01one", ""},                                   // photos only
+	}
+
+	var problems []string
+	for _, tc := range pages {
+		property, err := s.ScrapeProperty(detailBase + tc.id + "/")
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: 取得失敗: %v", tc.id, err))
+			continue
+		}
+		if property.RoomLayoutImageURL != tc.want {
+			problems = append(problems, fmt.Sprintf("%s: room_layout_image_url=%q (want %q)", tc.id, property.RoomLayoutImageURL, tc.want))
+		}
+		if property.RoomLayoutImageURL != "" && property.RoomLayoutImageURL == property.ImageURL {
+			problems = append(problems, fmt.Sprintf("%s: layout image duplicates image_url %q", tc.id, property.ImageURL))
+		}
+	}
+
+	result.Details = map[string]interface{}{
+		"pages":    len(pages),
+		"problems": problems,
+	}
+	if len(problems) > 0 {
+		result.Message = fmt.Sprintf("間取り図の抽出が不正: %v", problems)
+		log.Printf("  ❌ %v", problems)
+		return result
+	}
+
+	result.Success = true
+	result.Message = fmt.Sprintf("%dページで間取り図を正しく抽出（メイン写真のみの物件は空）", len(pages))
+	log.Printf("  ✅ %s", result.Message)
+	return result
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"real-estate-portal/internal/scraper"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Test 20: 定期借家・入居条件の抽出（フィクスチャモードのみ）
+// 契約期間・条件等の行から is_fixed_term_lease / lease_term / 入居条件フラグを取り出せることを確認する
+func testLeaseTerms(s *scraper.Scraper, propertyURL string) TestResult {
+	result := TestResult{
+		TestName:  "定期借家・入居条件の抽出",
+		Timestamp: time.Now(),
+	}
+
+	log.Println("\n[Test 20] 定期借家・入居条件の抽出テスト...")
+
+	// Fixture detail pages share the list page's host; only the property ID differs
+	detailBase := propertyURL[:strings.Index(propertyURL, "/rent/detail/")+len("/rent/detail/")]
+
+	cases := []struct {
+		id         string
+		fixedTerm  bool
+		term       string
+		facilities []string
+		absent     []string
+	}{
+		{"lease00standard", false, "2年", []string{"office_use_allowed", "instruments_allowed"}, []string{"room_share_allowed"}},
+		{"lease01fixedterm", true, "2年", []string{"room_share_allowed"}, []string{"office_use_allowed", "instruments_allowed"}},
+		{"lease02fixednoterm", true, "", nil, []string{"office_use_allowed", "room_share_allowed", "instruments_allowed"}},
+		{"lease03norow", false, "", nil, []string{"office_use_allowed", "room_share_allowed", "instruments_allowed"}},
+	}
+
+	var problems []string
+	for _, tc := range cases {
+		property, err := s.ScrapeProperty(detailBase + tc.id + "/")
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: 取得失敗: %v", tc.id, err))
+			continue
+		}
+		if property.IsFixedTermLease != tc.fixedTerm || property.LeaseTerm != tc.term {
+			problems = append(problems, fmt.Sprintf("%s: is_fixed_term_lease=%v lease_term=%q (want %v/%q)",
+				tc.id, property.IsFixedTermLease, property.LeaseTerm, tc.fixedTerm, tc.term))
+		}
+		facilities := property.GetFacilities()
+		for _, key := range tc.facilities {
+			if !slices.Contains(facilities, key) {
+				problems = append(problems, fmt.Sprintf("%s: facilities missing %s (%v)", tc.id, key, facilities))
+			}
+		}
+		for _, key := range tc.absent {
+			if slices.Contains(facilities, key) {
+				problems = append(problems, fmt.Sprintf("%s: unexpected %s in facilities", tc.id, key))
+			}
+		}
+	}
+
+	result.Details = map[string]interface{}{
+		"cases":    len(cases),
+		"problems": problems,
+	}
+	if len(problems) > 0 {
+		result.Message = fmt.Sprintf("契約形態の抽出が不正: %v", problems)
+		log.Printf("  ❌ %v", problems)
+		return result
+	}
+
+	result.Success = true
+	result.Message = fmt.Sprintf("%d件の契約形態・入居条件を正しく抽出", len(cases))
+	log.Printf("  ✅ %d件の契約形態・入居条件を正しく抽出", len(cases))
+	return result
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"real-estate-portal/internal/database"
+	"real-estate-portal/internal/models"
+	"real-estate-portal/internal/scheduler"
+	"real-estate-portal/internal/scraper"
+	"testing"
+	"time"
+)
+
+// lightStub is a stub source with a light refresh that reports rent
+type lightStub struct {
+	*stubSource
+	rent int
+}
+
+func (s *lightStub) ScrapeLight(_ context.Context, detailURL string) (*scraper.LightResult, error) {
+	id, _ := s.SourcePropertyID(detailURL)
+	return &scraper.LightResult{URL: detailURL, SourcePropertyID: id, Rent: &s.rent}, nil
+}
+
+// A light rent change on a 敷1礼1 listing moves deposit_yen and key_money_yen with it, and
+// the landing page shows the new rent straight away
+func TestLightRefreshRecomputesFees(t *testing.T) {
+	db := openSQLiteDB(t)
+	gdb := database.NewGormDBFromDB(db)
+	gdb.InvalidateListingCache()
+
+	rent := 80000
+	property := &models.Property{ID: "light-fees-01", Source: "stub", SourcePropertyID: "light01",
+		DetailURL: "https://stub.example/detail/light01/", Title: "敷礼テスト", Rent: &rent,
+		Deposit: "1ヶ月", KeyMoney: "1ヶ月", Status: models.PropertyStatusActive}
+	if err := gdb.SaveProperty(property); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	if _, err := gdb.GetPropertiesWithFiltersPaginated(database.PropertyFilters{}); err != nil {
+		t.Fatalf("listing: %v", err)
+	}
+
+	item := models.DetailScrapeQueue{Source: "stub", SourcePropertyID: "light01",
+		DetailURL: property.DetailURL, Status: models.QueueStatusPending, Light: true}
+	if err := db.Create(&item).Error; err != nil {
+		t.Fatalf("queue item: %v", err)
+	}
+
+	source := &lightStub{stubSource: newStubSource(nil), rent: 90000}
+	w := scheduler.NewQueueWorkerWithSources(db, scraper.NewScraper(), scraper.NewRegistry(source))
+	w.SetPollInterval(10 * time.Millisecond)
+	w.SetHealthCheck(func(context.Context) bool { return true })
+	w.SetMinRefetchInterval(0)
+	w.SetPriorityAging(0)
+	if err := w.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	for deadline := time.Now().Add(10 * time.Second); ; time.Sleep(10 * time.Millisecond) {
+		if err := db.First(&item, item.ID).Error; err != nil {
+			t.Fatalf("read queue item: %v", err)
+		}
+		if item.Status != models.QueueStatusPending && item.Status != models.QueueStatusProcessing {
+			break
+		}
+		if time.Now().After(deadline) {
+			w.Stop()
+			t.Fatalf("item not processed: %+v", item)
+		}
+	}
+	if err := w.Stop(); err != nil {
+		t.Fatalf("Stop: %v", err)
+	}
+	if item.Status != models.QueueStatusDone || !item.Light {
+		t.Fatalf("item: %s light=%v (want a done light refresh)", item.Status, item.Light)
+	}
+
+	var stored models.Property
+	if err := db.First(&stored, "id = ?", property.ID).Error; err != nil {
+		t.Fatalf("read property: %v", err)
+	}
+	if fmtIntPtr(stored.Rent) != "90000" || fmtIntPtr(stored.DepositYen) != "90000" || fmtIntPtr(stored.KeyMoneyYen) != "90000" {
+		t.Errorf("rent %s, deposit_yen %s, key_money_yen %s (want 90000 each)",
+			fmtIntPtr(stored.Rent), fmtIntPtr(stored.DepositYen), fmtIntPtr(stored.KeyMoneyYen))
+	}
+
+	page, err := gdb.GetPropertiesWithFiltersPaginated(database.PropertyFilters{})
+	if err != nil {
+		t.Fatalf("listing: %v", err)
+	}
+	if len(page.Properties) != 1 || fmtIntPtr(page.Properties[0].Rent) != "90000" {
+		t.Errorf("landing page still serves the old rent: %+v", page.Properties)
+	}
+}
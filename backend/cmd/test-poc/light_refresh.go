@@ -0,0 +1,111 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"real-estate-portal/internal/config"
+	"real-estate-portal/internal/models"
+	"real-estate-portal/internal/scraper"
+	"real-estate-portal/internal/snapshot"
+	"strings"
+	"time"
+)
+
+// Test 65: 軽量リフレッシュ（フィクスチャモードのみ）
+// ScrapePropertyLight が og:title / og:image と賃料だけを読み、賃料・タイトルが通常の詳細取得と一致すること
+// （おすすめ物件の価格を拾わない）、掲載終了ページは ErrDelisted になること、部分スナップショットは賃料と
+// ステータスだけが比較され、引き継いだ項目の欠落が変更として記録されないことを確認する
+func testLightRefresh(propertyURL string) TestResult {
+	result := TestResult{
+		TestName:  "軽量リフレッシュ",
+		Timestamp: time.Now(),
+	}
+
+	log.Println("\n[Test 65] 軽量リフレッシュテスト...")
+
+	baseURL := propertyURL[:strings.Index(propertyURL, "/rent/detail/")]
+	s := newFixtureScraper(baseURL)
+
+	var problems []string
+
+	// 1. Same rent and title as the full parse, og:image as the photo
+	for _, url := range []string{
+		propertyURL,
+		baseURL + "/rent/detail/context00json/",
+		baseURL + "/rent/detail/rent00keymoneyfirst/",
+		baseURL + "/rent/detail/rent02textonly/",
+	} {
+		full, err := s.ScrapeProperty(url)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: full scrape failed: %v", url, err))
+			continue
+		}
+		light, err := s.ScrapePropertyLight(url)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: light refresh failed: %v", url, err))
+			continue
+		}
+		if !intPtrEq(light.Rent, full.Rent) {
+			problems = append(problems, fmt.Sprintf("%s: rent=%s (full %s)", url, fmtIntPtr(light.Rent), fmtIntPtr(full.Rent)))
+		}
+		if light.Title != full.Title {
+			problems = append(problems, fmt.Sprintf("%s: title=%q (full %q)", url, light.Title, full.Title))
+		}
+		if light.SourcePropertyID != full.SourcePropertyID {
+			problems = append(problems, fmt.Sprintf("%s: id=%s (full %s)", url, light.SourcePropertyID, full.SourcePropertyID))
+		}
+	}
+	if light, err := s.ScrapePropertyLight(baseURL + "/rent/detail/context00json/"); err == nil &&
+		light.ImageURL != "https://realestate-pctr.c.yimg.jp/fixtureOgImage0001" {
+		problems = append(problems, fmt.Sprintf("context00json: image=%q (want og:image)", light.ImageURL))
+	}
+
+	// 2. The 掲載終了 notice is a delisting, as for a full scrape
+	if _, err := s.ScrapePropertyLight(baseURL + "/rent/detail/ended00notice/"); !errors.Is(err, scraper.ErrDelisted) {
+		problems = append(problems, fmt.Sprintf("ended notice: err=%v (want ErrDelisted)", err))
+	}
+
+	// 3. Partial snapshots only compare rent and status
+	area := 25.3
+	age := 12
+	old := &models.PropertySnapshot{Rent: intp(98000), Area: &area, BuildingAge: &age, FloorPlan: "1K", Status: string(models.PropertyStatusActive), ImageURL: "a.jpg"}
+	partial := &models.PropertySnapshot{Rent: intp(95000), Status: string(models.PropertyStatusActive), Partial: true}
+	changes := snapshot.CompareSnapshots("p1", old, partial, time.Now())
+	if len(changes) != 1 || changes[0].ChangeType != models.ChangeTypeRent {
+		problems = append(problems, fmt.Sprintf("partial: changes=%v (want one rent change)", changeTypesOf(changes)))
+	}
+	partial.Rent = intp(98000)
+	if changes := snapshot.CompareSnapshots("p1", old, partial, time.Now()); len(changes) != 0 {
+		problems = append(problems, fmt.Sprintf("partial same rent: changes=%v (want none)", changeTypesOf(changes)))
+	}
+
+	// 4. Config: off by default, every 3h, 100 per run
+	var cfg config.LightRefreshConfig
+	if cfg.Enabled || cfg.Interval() != 3*time.Hour || cfg.Limit() != 100 {
+		problems = append(problems, fmt.Sprintf("config defaults: enabled=%v interval=%v limit=%d", cfg.Enabled, cfg.Interval(), cfg.Limit()))
+	}
+
+	result.Details = map[string]interface{}{
+		"problems": problems,
+	}
+	if len(problems) > 0 {
+		result.Message = fmt.Sprintf("軽量リフレッシュが不正: %v", problems)
+		log.Printf("  ❌ %v", problems)
+		return result
+	}
+
+	result.Success = true
+	result.Message = "軽量リフレッシュの賃料・タイトルが通常取得と一致し、部分スナップショットは賃料とステータスだけが比較されることを確認"
+	log.Printf("  ✅ %s", result.Message)
+	return result
+}
+
+// changeTypesOf lists the change types of changes (for messages)
+func changeTypesOf(changes []models.PropertyChange) []string {
+	types := make([]string, 0, len(changes))
+	for _, c := range changes {
+		types = append(types, c.ChangeType)
+	}
+	return types
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"real-estate-portal/internal/ratelimit"
+	"real-estate-portal/internal/scraper"
+	"strings"
+	"time"
+)
+
+// Test 62: ホストごとのリミッター・サーキットブレーカー（オフライン）
+// 同じホストのスクレイパーは共有のリミッター・ブレーカーを使い、別ホストや注入したものとは
+// 状態を共有しないこと、開いたブレーカーをリセットで再開できることを確認する
+func testPerHostLimits() TestResult {
+	result := TestResult{
+		TestName:  "ホストごとのリミッター・ブレーカー",
+		Timestamp: time.Now(),
+	}
+
+	log.Println("\n[Test 62] ホストごとのリミッター・ブレーカーテスト...")
+
+	newListServer := func() *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			fmt.Fprintf(w, `<html><body><input type="checkbox" class="_propertyCheckbox" value="0000%s"></body></html>`,
+				strings.Repeat("c", 40))
+		}))
+	}
+	serverA, serverB := newListServer(), newListServer()
+	defer serverA.Close()
+	defer serverB.Close()
+
+	var problems []string
+	a1, a2, b := newFixtureScraper(serverA.URL), newFixtureScraper(serverA.URL), newFixtureScraper(serverB.URL)
+	injectedBreaker := scraper.NewCircuitBreaker(8, time.Hour)
+	injected := scraper.NewScraperWithLimiter(scraper.ScraperConfig{
+		Timeout:     10 * time.Second,
+		BaseURL:     serverA.URL,
+		FixtureMode: true,
+	}, ratelimit.NewYahooLimiter(1, 10*time.Millisecond, 0), injectedBreaker)
+
+	// 1. Same host shares, other hosts and injected instances do not
+	if a1.CircuitBreaker() != a2.CircuitBreaker() || a1.ListLimiter() != a2.ListLimiter() {
+		problems = append(problems, "scrapers of one host got different limits")
+	}
+	if a1.CircuitBreaker() == b.CircuitBreaker() || a1.ListLimiter() == b.ListLimiter() {
+		problems = append(problems, "scrapers of different hosts share limits")
+	}
+	if injected.CircuitBreaker() != injectedBreaker || injected.CircuitBreaker() == a1.CircuitBreaker() {
+		problems = append(problems, "injected breaker not used")
+	}
+	limiter, breaker := scraper.SharedLimits("yahoo", strings.TrimPrefix(serverA.URL, "http://"))
+	if limiter != a1.ListLimiter() || breaker != a1.CircuitBreaker() {
+		problems = append(problems, "SharedLimits does not return the host's instances")
+	}
+
+	// 2. Tripping host A's breaker stops both of its scrapers, not host B's or the injected one
+	a1.CircuitBreaker().RecordFailure(http.StatusForbidden)
+	a1.CircuitBreaker().RecordFailure(http.StatusForbidden)
+	if _, err := a2.ScrapeListPage(serverA.URL + "/list/"); !errors.Is(err, scraper.ErrCircuitOpen) {
+		problems = append(problems, fmt.Sprintf("host A with open breaker: err=%v", err))
+	}
+	if isOpen, retryAt := a2.CircuitBreaker().State(); !isOpen || time.Until(retryAt) < 59*time.Minute {
+		problems = append(problems, fmt.Sprintf("host A state: open=%v retry_at=%v", isOpen, retryAt))
+	}
+	for name, tc := range map[string]struct {
+		s       *scraper.Scraper
+		listURL string
+	}{
+		"host B":   {b, serverB.URL + "/list/"},
+		"injected": {injected, serverA.URL + "/list/"},
+	} {
+		if urls, err := tc.s.ScrapeListPage(tc.listURL); err != nil || len(urls) != 1 {
+			problems = append(problems, fmt.Sprintf("%s: urls=%v err=%v", name, urls, err))
+		}
+	}
+
+	// 3. Reset closes the breaker without a restart
+	a1.CircuitBreaker().Reset()
+	if status := a2.CircuitBreaker().Status(); status.Open || status.Failures != 0 {
+		problems = append(problems, fmt.Sprintf("after reset: %+v", status))
+	}
+	if urls, err := a2.ScrapeListPage(serverA.URL + "/list/"); err != nil || len(urls) != 1 {
+		problems = append(problems, fmt.Sprintf("host A after reset: urls=%v err=%v", urls, err))
+	}
+
+	result.Details = map[string]interface{}{
+		"problems": problems,
+	}
+	if len(problems) > 0 {
+		result.Message = fmt.Sprintf("リミッター・ブレーカーの分離が不正: %v", problems)
+		log.Printf("  ❌ %v", problems)
+		return result
+	}
+
+	result.Success = true
+	result.Message = "同じホストは共有、別ホスト・注入したものは独立、リセットで再開"
+	log.Printf("  ✅ %s", result.Message)
+	return result
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"real-estate-portal/internal/scraper"
+	"strings"
+	"time"
+)
+
+// listMetaPages are the list pages served by Test 61 (path → body); each lists two properties
+var listMetaPages = map[string]string{
+	// Comma-separated count, no pager: pages = ceil(1234 / 2)
+	"/list/comma": `<div class="SearchResult__hitCount">1,234<span>件</span></div>`,
+	// SUUMO-style hit count with a pager
+	"/list/suumo": `<div class="paginate_set-hit">88<span>件</span></div>
+		<ol class="pagination-parts"><li><span>1</span></li><li><a href="?page=2">2</a></li><li><a href="?page=44">44</a></li></ol>`,
+	// Labelled count in plain text only
+	"/list/text": `<p>検索結果：45件　新着 3件</p>`,
+	// Neither a count nor a pager; an unrelated "N件" block must not be taken as the total
+	"/list/none": `<p class="Campaign">キャンペーン物件 3件</p><div class="NewArrival__count">新着あり</div>`,
+}
+
+// Test 61: 一覧ページの総件数・ページ数（フィクスチャモードのみ）
+// 一覧ヘッダーの総件数とページャーの総ページ数を読み取り、取れないときは -1 を返して
+// 物件URLの取得は失敗させないこと、ページ送りでは1ページ目の値が返ることを確認する
+func testListPageMeta(s *scraper.Scraper, listURL string) TestResult {
+	result := TestResult{
+		TestName:  "一覧ページの総件数・ページ数",
+		Timestamp: time.Now(),
+	}
+
+	log.Println("\n[Test 61] 一覧ページの総件数・ページ数テスト...")
+
+	var problems []string
+	check := func(step string, page *scraper.ListPage, err error, wantURLs, wantTotal, wantPages int) {
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", step, err))
+			return
+		}
+		if len(page.URLs) != wantURLs || page.TotalOnSite != wantTotal || page.Pages != wantPages {
+			problems = append(problems, fmt.Sprintf("%s: urls=%d total=%d pages=%d, want %d / %d / %d",
+				step, len(page.URLs), page.TotalOnSite, page.Pages, wantURLs, wantTotal, wantPages))
+		}
+	}
+
+	// 1. Fixture list: 5件 in the header (not 新着 2件), pager up to page 3
+	page, err := s.ScrapeListPageWithMeta(listURL)
+	check("list.html", page, err, 2, 5, 3)
+
+	// 2. Page 2 has no header: the count is -1, the pager still gives the page count
+	page, err = s.ScrapeListPageWithMeta(listURL + "?page=2")
+	check("list_page2.html", page, err, 2, -1, 3)
+
+	// 3. Crawling keeps the first page's counts
+	page, err = s.ScrapeListPagesWithMetaContext(context.Background(), listURL, 10)
+	check("crawl", page, err, 3, 5, 3)
+	if err == nil && page.PagesVisited != 3 {
+		problems = append(problems, fmt.Sprintf("crawl: pages_visited=%d, want 3", page.PagesVisited))
+	}
+
+	// 4. Other header forms, and pages without any count
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := listMetaPages[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		fmt.Fprintf(w, `<html><body>%s
+			<input type="checkbox" class="_propertyCheckbox" value="0000%s">
+			<input type="checkbox" class="_propertyCheckbox" value="0000%s"></body></html>`,
+			body, strings.Repeat("a", 40), strings.Repeat("b", 40))
+	}))
+	defer server.Close()
+	metaScraper := newFixtureScraper(server.URL)
+	for _, tc := range []struct {
+		path              string
+		wantTotal, wantPg int
+	}{
+		{"/list/comma", 1234, 617},
+		{"/list/suumo", 88, 44},
+		{"/list/text", 45, 23},
+		{"/list/none", -1, -1},
+	} {
+		page, err := metaScraper.ScrapeListPageWithMeta(server.URL + tc.path)
+		check(tc.path, page, err, 2, tc.wantTotal, tc.wantPg)
+	}
+
+	result.Details = map[string]interface{}{
+		"problems": problems,
+	}
+	if len(problems) > 0 {
+		result.Message = fmt.Sprintf("総件数・ページ数が不正: %v", problems)
+		log.Printf("  ❌ %v", problems)
+		return result
+	}
+
+	result.Success = true
+	result.Message = "ヘッダーの総件数とページャーのページ数を取得、取れない場合は -1 でURLは返る"
+	log.Printf("  ✅ %s", result.Message)
+	return result
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"real-estate-portal/internal/lookup"
+	"real-estate-portal/internal/models"
+	"time"
+)
+
+// Test 10: 管理者検索の分類（オフライン）
+// 掲載中・掲載終了（論理削除）・物理削除済みの各1件が正しいバケットに分類されることを確認する
+func testLookupClassification() TestResult {
+	result := TestResult{
+		TestName:  "管理者検索の分類",
+		Timestamp: time.Now(),
+	}
+
+	log.Println("\n[Test 10] 管理者検索の分類テスト...")
+
+	removedAt := time.Now().Add(-10 * 24 * time.Hour)
+	active := models.Property{ID: "a1", Title: "目黒区 1K", Status: models.PropertyStatusActive}
+	removed := models.Property{ID: "r1", Title: "目黒区 1LDK", Status: models.PropertyStatusRemoved, RemovedAt: &removedAt}
+	deleted := models.DeleteLog{PropertyID: "d1", Title: "目黒区 2DK", RemovedAt: removedAt, DeletedAt: time.Now(), Reason: models.DeleteReasonExpired}
+
+	hits := []lookup.Hit{
+		lookup.HitFromProperty(&active),
+		lookup.HitFromProperty(&removed),
+		lookup.HitFromDeleteLog(&deleted),
+	}
+	want := []string{lookup.LocationActive, lookup.LocationRemoved, lookup.LocationDeleted}
+
+	var problems []string
+	for i, hit := range hits {
+		if hit.Location != want[i] {
+			problems = append(problems, fmt.Sprintf("%s: %s (want %s)", hit.PropertyID, hit.Location, want[i]))
+		}
+	}
+	if hits[1].RemovedAt == nil {
+		problems = append(problems, "removed hit has no removed_at")
+	}
+	if hits[2].DeletedAt == nil || hits[2].Reason != models.DeleteReasonExpired {
+		problems = append(problems, "deleted hit has no deleted_at/reason")
+	}
+
+	result.Details = map[string]interface{}{
+		"hits":     hits,
+		"problems": problems,
+	}
+	if len(problems) > 0 {
+		result.Message = fmt.Sprintf("分類が不正: %v", problems)
+		log.Printf("  ❌ %v", problems)
+		return result
+	}
+
+	result.Success = true
+	result.Message = "掲載中/掲載終了/削除済みを正しく分類"
+	log.Printf("  ✅ 分類 OK")
+	return result
+}
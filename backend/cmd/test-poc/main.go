@@ -2,10 +2,12 @@ package main
 
 import (
 	"encoding/json"
+	"flag"
 	"fmt"
 	"log"
 	"os"
 	"real-estate-portal/internal/scraper"
+	"strings"
 	"time"
 )
 
@@ -30,12 +32,43 @@ type PoCResults struct {
 func main() {
 	log.SetFlags(log.LstdFlags | log.Lshortfile)
 
+	// フィクスチャモード: 保存済みHTMLをローカルで配信して実サイトにアクセスしない（CI向け）
+	fixtureDir := flag.String("fixtures", os.Getenv("TEST_FIXTURE_DIR"), "directory of saved list/detail pages (offline mode)")
+	benchActive := flag.Bool("bench-active", false, "benchmark active-property queries on a synthetic MySQL table and exit")
+	benchAddress := flag.Bool("bench-address", false, "benchmark address extraction on a large synthetic detail page and exit")
+	updateGolden := flag.Bool("update-golden", false, "rewrite the golden JSON next to the fixtures (testdata/golden) from the current parser")
+	flag.Parse()
+
+	if *benchActive {
+		runActivePropertiesBenchmark()
+		return
+	}
+	if *benchAddress {
+		runAddressBenchmark()
+		return
+	}
+
 	// テスト対象のURL（東京23区の賃貸物件検索結果ページ）
 	// 実際のYahoo不動産のURLを指定してください
 	testListURL := os.Getenv("TEST_LIST_URL")
-	if testListURL == "" {
-		testListURL = "https://realestate.yahoo.co.jp/rent/search/0123/list/"
-		log.Printf("TEST_LIST_URL not set, using default: %s", testListURL)
+	expectedHost := "realestate.yahoo.co.jp"
+
+	// スクレイパーの初期化
+	var s *scraper.Scraper
+	if *fixtureDir != "" {
+		server := startFixtureServer(*fixtureDir)
+		defer server.Close()
+
+		testListURL = server.URL + "/rent/search/0123/list/"
+		expectedHost = strings.TrimPrefix(server.URL, "http://")
+		s = newFixtureScraper(server.URL)
+		log.Printf("Fixture mode: serving %s at %s", *fixtureDir, server.URL)
+	} else {
+		if testListURL == "" {
+			testListURL = "https://realestate.yahoo.co.jp/rent/search/0123/list/"
+			log.Printf("TEST_LIST_URL not set, using default: %s", testListURL)
+		}
+		s = scraper.NewScraper()
 	}
 
 	results := &PoCResults{
@@ -47,9 +80,6 @@ func main() {
 	log.Println("Phase 0: PoC検証スクリプト開始")
 	log.Println("=" + "===========================================")
 
-	// スクレイパーの初期化
-	s := scraper.NewScraper()
-
 	// Test 1: スクレイピング安定性（同じURLで3回連続成功）
 	test1Result := testScrapingStability(s, testListURL)
 	results.Results = append(results.Results, test1Result)
@@ -78,9 +108,245 @@ func main() {
 	results.Results = append(results.Results, test3Result)
 
 	// Test 4: Yahoo不動産へのリンクが成立
-	test4Result := testYahooLink(propertyURLs[0])
+	test4Result := testYahooLink(propertyURLs[0], expectedHost)
 	results.Results = append(results.Results, test4Result)
 
+	// Test 5: 抽出フィールドのカバレッジ（フィクスチャモードのみ。実サイトは項目が揃わない物件がある）
+	if *fixtureDir != "" {
+		test5Result := testFieldCoverage(s, propertyURLs[0])
+		results.Results = append(results.Results, test5Result)
+
+		if len(propertyURLs) > 1 {
+			test6Result := testEndedCampaign(s, propertyURLs[1])
+			results.Results = append(results.Results, test6Result)
+		}
+
+		test7Result := testScrapeSpanTree(s, propertyURLs[0])
+		results.Results = append(results.Results, test7Result)
+
+		test9Result := testBatchOrdering()
+		results.Results = append(results.Results, test9Result)
+
+		test10Result := testLookupClassification()
+		results.Results = append(results.Results, test10Result)
+
+		test11Result := testPreventiveCooldownSpacing()
+		results.Results = append(results.Results, test11Result)
+
+		test14Result := testShareLinkParams()
+		results.Results = append(results.Results, test14Result)
+
+		test15Result := testQueueReprioritize()
+		results.Results = append(results.Results, test15Result)
+
+		test16Result := testReadOnlyMode()
+		results.Results = append(results.Results, test16Result)
+
+		test17Result := testSnapshotDiff()
+		results.Results = append(results.Results, test17Result)
+
+		test20Result := testLeaseTerms(s, propertyURLs[0])
+		results.Results = append(results.Results, test20Result)
+
+		test21Result := testParseBudget(*fixtureDir, propertyURLs[0])
+		results.Results = append(results.Results, test21Result)
+
+		test22Result := testMetaOptions()
+		results.Results = append(results.Results, test22Result)
+
+		test23Result := testListPagination(s, testListURL)
+		results.Results = append(results.Results, test23Result)
+
+		test24Result := testGalleryImages(s, propertyURLs[0])
+		results.Results = append(results.Results, test24Result)
+
+		test25Result := testStationExtraction(s, propertyURLs[0])
+		results.Results = append(results.Results, test25Result)
+
+		test26Result := testContractCosts(s, propertyURLs[0])
+		results.Results = append(results.Results, test26Result)
+
+		test27Result := testFeatureTags(s, propertyURLs[0])
+		results.Results = append(results.Results, test27Result)
+
+		test28Result := testBuildingType(s, propertyURLs[0])
+		results.Results = append(results.Results, test28Result)
+
+		test29Result := testContextExtraction(s, propertyURLs[0])
+		results.Results = append(results.Results, test29Result)
+
+		test30Result := testScrapeCancellation()
+		results.Results = append(results.Results, test30Result)
+
+		test31Result := testUserAgentPool()
+		results.Results = append(results.Results, test31Result)
+
+		test32Result := testScrapeErrorClassification()
+		results.Results = append(results.Results, test32Result)
+
+		test33Result := testRobotsTxt()
+		results.Results = append(results.Results, test33Result)
+
+		test34Result := testConditionalRescrape(*fixtureDir)
+		results.Results = append(results.Results, test34Result)
+
+		test35Result := testCompressedResponses(*fixtureDir)
+		results.Results = append(results.Results, test35Result)
+
+		test36Result := testShiftJISCharset(*fixtureDir)
+		results.Results = append(results.Results, test36Result)
+
+		test37Result := testDebugHTMLCapture(propertyURLs[0])
+		results.Results = append(results.Results, test37Result)
+
+		test38Result := testSuumoSource(*fixtureDir, propertyURLs[0])
+		results.Results = append(results.Results, test38Result)
+
+		test39Result := testCoordinateExtraction(s, propertyURLs[0])
+		results.Results = append(results.Results, test39Result)
+
+		test40Result := testRentExtraction(s, propertyURLs[0])
+		results.Results = append(results.Results, test40Result)
+
+		test41Result := testFloorPlanExtraction(s, propertyURLs[0])
+		results.Results = append(results.Results, test41Result)
+
+		test42Result := testAddressExtraction(s, propertyURLs[0])
+		results.Results = append(results.Results, test42Result)
+
+		test43Result := testAreaExtraction(s, propertyURLs[0])
+		results.Results = append(results.Results, test43Result)
+
+		test44Result := testRoomLayoutImage(s, propertyURLs[0])
+		results.Results = append(results.Results, test44Result)
+
+		test45Result := testDetailRows(s, propertyURLs[0])
+		results.Results = append(results.Results, test45Result)
+
+		test46Result := testProxyRotation(propertyURLs[0])
+		results.Results = append(results.Results, test46Result)
+
+		test47Result := testCheckAlive()
+		results.Results = append(results.Results, test47Result)
+
+		test48Result := testScrapeStats(s, propertyURLs[0])
+		results.Results = append(results.Results, test48Result)
+
+		test49Result := testRetryBudget()
+		results.Results = append(results.Results, test49Result)
+
+		test50Result := testRequestDelay()
+		results.Results = append(results.Results, test50Result)
+
+		test51Result := testImageVerification()
+		results.Results = append(results.Results, test51Result)
+
+		test52Result := testConfigSelectors(propertyURLs[0])
+		results.Results = append(results.Results, test52Result)
+
+		test53Result := testDetailRedirect()
+		results.Results = append(results.Results, test53Result)
+
+		test54Result := testGoldenParse(*fixtureDir, *updateGolden)
+		results.Results = append(results.Results, test54Result)
+
+		test55Result := testNotesAndConditions(propertyURLs[0])
+		results.Results = append(results.Results, test55Result)
+
+		test56Result := testEndedListing(propertyURLs[0])
+		results.Results = append(results.Results, test56Result)
+
+		test57Result := testBusAccess()
+		results.Results = append(results.Results, test57Result)
+
+		test58Result := testMinRefetchInterval()
+		results.Results = append(results.Results, test58Result)
+
+		test60Result := testWalkTimeBuckets()
+		results.Results = append(results.Results, test60Result)
+
+		test61Result := testListPageMeta(s, testListURL)
+		results.Results = append(results.Results, test61Result)
+
+		test62Result := testPerHostLimits()
+		results.Results = append(results.Results, test62Result)
+
+		test63Result := testSessionWarmup()
+		results.Results = append(results.Results, test63Result)
+
+		test64Result := testHeaderProfile()
+		results.Results = append(results.Results, test64Result)
+
+		test65Result := testLightRefresh(propertyURLs[0])
+		results.Results = append(results.Results, test65Result)
+
+		test66Result := testBuildingPages(propertyURLs[0])
+		results.Results = append(results.Results, test66Result)
+
+		test67Result := testRetryAfter()
+		results.Results = append(results.Results, test67Result)
+
+		test68Result := testSoftWAFBlock(propertyURLs[0], *fixtureDir)
+		results.Results = append(results.Results, test68Result)
+
+		test69Result := testHealthCooldown()
+		results.Results = append(results.Results, test69Result)
+
+		test70Result := testWorkerShutdown()
+		results.Results = append(results.Results, test70Result)
+
+		test71Result := testWorkerConcurrency()
+		results.Results = append(results.Results, test71Result)
+
+		test72Result := testStuckProcessing()
+		results.Results = append(results.Results, test72Result)
+
+		test73Result := testQueueInspection()
+		results.Results = append(results.Results, test73Result)
+
+		test74Result := testManualEnqueue()
+		results.Results = append(results.Results, test74Result)
+
+		test75Result := testQueueRetryCancel()
+		results.Results = append(results.Results, test75Result)
+
+		test76Result := testQueueDeadLetter()
+		results.Results = append(results.Results, test76Result)
+
+		test77Result := testEnqueueRace()
+		results.Results = append(results.Results, test77Result)
+
+		test78Result := testWorkerControl()
+		results.Results = append(results.Results, test78Result)
+
+		test79Result := testSchedulerDiscovery()
+		results.Results = append(results.Results, test79Result)
+
+		test80Result := testSchedulerSchedules()
+		results.Results = append(results.Results, test80Result)
+
+		test81Result := testBreakerStatePersist()
+		results.Results = append(results.Results, test81Result)
+
+		test82Result := testAdaptiveLimiter()
+		results.Results = append(results.Results, test82Result)
+
+		test83Result := testWorkerPause()
+		results.Results = append(results.Results, test83Result)
+
+		test84Result := testQueueBacklog()
+		results.Results = append(results.Results, test84Result)
+
+		test85Result := testEventStream()
+		results.Results = append(results.Results, test85Result)
+
+		test86Result := testPriorityAging()
+		results.Results = append(results.Results, test86Result)
+
+		test87Result := testBatchClaim()
+		results.Results = append(results.Results, test87Result)
+	}
+
 	// 総合判定
 	results.OverallSuccess = true
 	for _, result := range results.Results {
@@ -252,7 +518,7 @@ func testImageReference(s *scraper.Scraper, propertyURL string) TestResult {
 }
 
 // Test 4: Yahoo不動産リンク
-func testYahooLink(propertyURL string) TestResult {
+func testYahooLink(propertyURL, expectedHost string) TestResult {
 	result := TestResult{
 		TestName:  "Yahoo不動産リンクの確認",
 		Timestamp: time.Now(),
@@ -268,7 +534,7 @@ func testYahooLink(propertyURL string) TestResult {
 	}
 
 	// Yahoo不動産のURLであることを確認
-	if !contains(propertyURL, "realestate.yahoo.co.jp") {
+	if !contains(propertyURL, expectedHost) {
 		result.Success = false
 		result.Message = fmt.Sprintf("Yahoo不動産のURLではありません: %s", propertyURL)
 		return result
@@ -318,3 +584,19 @@ func findSubstring(s, substr string) bool {
 	}
 	return false
 }
+
+func intp(v int) *int { return &v }
+
+func intPtrEq(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func fmtIntPtr(v *int) string {
+	if v == nil {
+		return "nil"
+	}
+	return fmt.Sprint(*v)
+}
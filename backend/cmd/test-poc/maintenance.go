@@ -0,0 +1,89 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"real-estate-portal/internal/maintenance"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Test 16: 読み取り専用メンテナンスモード（オフライン）
+// フラグON中は書き込み（お気に入り登録）が503とメッセージで拒否され、GETと検索POSTは通ることを確認する
+func testReadOnlyMode() TestResult {
+	result := TestResult{
+		TestName:  "読み取り専用メンテナンスモード",
+		Timestamp: time.Now(),
+	}
+
+	log.Println("\n[Test 16] 読み取り専用モードテスト...")
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(maintenance.Middleware("/api/search/advanced", "/api/admin/maintenance/read-only"))
+	ok := func(c *gin.Context) { c.JSON(http.StatusOK, gin.H{"ok": true}) }
+	r.GET("/api/properties/:id", ok)
+	r.POST("/api/properties/:id/favorite", ok)
+	r.POST("/api/search/advanced", ok)
+	r.POST("/api/admin/maintenance/read-only", ok)
+
+	do := func(method, path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(method, path, nil))
+		return w
+	}
+
+	var problems []string
+	expect := func(step, method, path string, want int) *httptest.ResponseRecorder {
+		w := do(method, path)
+		if w.Code != want {
+			problems = append(problems, fmt.Sprintf("%s: %s %s = %d, want %d", step, method, path, w.Code, want))
+		}
+		return w
+	}
+
+	// Memory-only state (no DB): start from off
+	maintenance.Load(nil)
+	maintenance.Set(false, "")
+	expect("off", http.MethodPost, "/api/properties/abc/favorite", http.StatusOK)
+
+	const message = "DBメンテナンス中です（03:00まで）"
+	if state, err := maintenance.Set(true, message); err != nil || !state.ReadOnly || state.Since == nil {
+		problems = append(problems, fmt.Sprintf("Set(true): %+v %v", state, err))
+	}
+	w := expect("on", http.MethodPost, "/api/properties/abc/favorite", http.StatusServiceUnavailable)
+	if !strings.Contains(w.Body.String(), message) || !strings.Contains(w.Body.String(), `"read_only"`) {
+		problems = append(problems, fmt.Sprintf("on: 503 body lacks message/code: %s", w.Body.String()))
+	}
+	expect("on", http.MethodDelete, "/api/properties/abc/favorite", http.StatusServiceUnavailable)
+	expect("on", http.MethodGet, "/api/properties/abc", http.StatusOK)
+	expect("on", http.MethodPost, "/api/search/advanced", http.StatusOK)
+	expect("on", http.MethodPost, "/api/admin/maintenance/read-only", http.StatusOK)
+	if !maintenance.ReadOnly() {
+		problems = append(problems, "ReadOnly() false while enabled (scheduler/worker would write)")
+	}
+
+	maintenance.Set(false, "")
+	expect("off again", http.MethodPost, "/api/properties/abc/favorite", http.StatusOK)
+	if maintenance.ReadOnly() {
+		problems = append(problems, "ReadOnly() true after disabling")
+	}
+
+	result.Details = map[string]interface{}{
+		"problems": problems,
+	}
+	if len(problems) > 0 {
+		result.Message = fmt.Sprintf("読み取り専用モードが不正: %v", problems)
+		log.Printf("  ❌ %v", problems)
+		return result
+	}
+
+	result.Success = true
+	result.Message = "ON中は書き込みのみ503、読み取りと許可済みPOSTは継続"
+	log.Printf("  ✅ %s", result.Message)
+	return result
+}
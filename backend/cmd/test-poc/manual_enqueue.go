@@ -0,0 +1,115 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"real-estate-portal/internal/models"
+	"real-estate-portal/internal/queue"
+	"real-estate-portal/internal/scraper"
+	"time"
+)
+
+// Test 74: 手動キュー登録（オフライン）
+// POST /api/queue/enqueue(/batch) の処理（queue.Service.EnqueueURLs）が、新しい URL と failed の行を
+// 作成として ID を返し、pending の物件や同じバッチ内の重複を重複として既存行の ID で報告し、URL でない・
+// 未対応ホスト・詳細ページでない URL を無効として弾くことを確認する
+func testManualEnqueue() TestResult {
+	result := TestResult{
+		TestName:  "手動キュー登録",
+		Timestamp: time.Now(),
+	}
+
+	log.Println("\n[Test 74] 手動キュー登録テスト...")
+
+	const base = "https://realestate.yahoo.co.jp/rent/detail/"
+	fake := &fakeActiveQueue{rows: []models.DetailScrapeQueue{
+		{ID: 1, Source: "yahoo", SourcePropertyID: "pending01", DetailURL: base + "pending01", Status: models.QueueStatusPending},
+		{ID: 2, Source: "yahoo", SourcePropertyID: "failed01", DetailURL: base + "failed01", Status: models.QueueStatusFailed, Attempts: 3},
+	}}
+	db, err := openDryRunDB()
+	if err == nil {
+		err = fake.register(db)
+	}
+	if err != nil {
+		result.Message = fmt.Sprintf("dry-run DB: %v", err)
+		log.Printf("  ❌ %s", result.Message)
+		return result
+	}
+
+	urls := []string{
+		base + "new01/?sc_out=list#photo", // new (query, fragment and trailing slash dropped)
+		base + "pending01/",               // already pending: duplicate, priority raised
+		base + "new01/",                   // same listing again in this batch: duplicate
+		base + "failed01/",                // failed earlier: revived, counts as created
+		"not a url",
+		"ftp://realestate.yahoo.co.jp/rent/detail/x01/",
+		"https://example.com/rent/detail/x02/", // unsupported site
+		"https://realestate.yahoo.co.jp/rent/", // not a detail page
+	}
+	res, err := queue.NewService(db).EnqueueURLs(scraper.NewRegistry(scraper.NewScraper()), urls, queue.PriorityManual)
+
+	var problems []string
+	problems = append(problems, fake.problems...)
+	if err != nil {
+		problems = append(problems, fmt.Sprintf("EnqueueURLs: %v", err))
+	} else {
+		if len(res.Created) != 2 || len(res.Duplicates) != 2 || len(res.Invalid) != 4 {
+			problems = append(problems, fmt.Sprintf("created=%d duplicates=%d invalid=%d (want 2, 2, 4): %+v",
+				len(res.Created), len(res.Duplicates), len(res.Invalid), res))
+		} else {
+			newID := res.Created[0].ID
+			if newID == 0 || res.Created[0].Outcome != queue.EnqueueInserted {
+				problems = append(problems, fmt.Sprintf("new URL: %+v (want inserted with an ID)", res.Created[0]))
+			}
+			if res.Created[1].ID != 2 || res.Created[1].Outcome != queue.EnqueueRevived {
+				problems = append(problems, fmt.Sprintf("failed URL: %+v (want revived id=2)", res.Created[1]))
+			}
+			if res.Duplicates[0].ID != 1 || res.Duplicates[1].ID != newID {
+				problems = append(problems, fmt.Sprintf("duplicates %+v (want id=1 and id=%d)", res.Duplicates, newID))
+			}
+			if ids := res.CreatedIDs(); len(ids) != 2 || ids[0] != newID || ids[1] != 2 {
+				problems = append(problems, fmt.Sprintf("created_ids=%v", ids))
+			}
+		}
+	}
+
+	// Stored rows: one active row per listing, the new URL normalized, manual priority applied
+	active := map[string]int{}
+	for _, row := range fake.rows {
+		if row.Status == models.QueueStatusPending || row.Status == models.QueueStatusProcessing {
+			active[row.SourcePropertyID]++
+		}
+		switch row.SourcePropertyID {
+		case "new01":
+			if row.DetailURL != base+"new01" || row.Priority != queue.PriorityManual {
+				problems = append(problems, fmt.Sprintf("new row: url=%s priority=%d", row.DetailURL, row.Priority))
+			}
+		case "pending01", "failed01":
+			if row.Priority != queue.PriorityManual {
+				problems = append(problems, fmt.Sprintf("%s: priority=%d (want raised to %d)", row.SourcePropertyID, row.Priority, queue.PriorityManual))
+			}
+		}
+	}
+	for spid, n := range active {
+		if n != 1 {
+			problems = append(problems, fmt.Sprintf("%s has %d active rows", spid, n))
+		}
+	}
+	if len(active) != 3 {
+		problems = append(problems, fmt.Sprintf("%d active listings (want 3): %v", len(active), active))
+	}
+
+	result.Details = map[string]interface{}{
+		"problems": problems,
+	}
+	if len(problems) > 0 {
+		result.Message = fmt.Sprintf("手動キュー登録が不正: %v", problems)
+		log.Printf("  ❌ %v", problems)
+		return result
+	}
+
+	result.Success = true
+	result.Message = "新規・failed の URL が作成として ID 付きで返り、pending やバッチ内の重複は既存行の ID で、不正な URL は無効として報告されることを確認"
+	log.Printf("  ✅ %s", result.Message)
+	return result
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// Test 55: 備考・初期費用・条件等の取得（フィクスチャモードのみ）
+// detail_notes00blocks.html の 初期費用 / 備考 行が改行を保ったまま Notes に、条件等 が Conditions に入り、
+// 備考のフリーレントがキャンペーンとして判定されること、長い備考が 4KB で「…」付きに切られることを確認する
+func testNotesAndConditions(propertyURL string) TestResult {
+	result := TestResult{
+		TestName:  "備考・初期費用・条件等の取得",
+		Timestamp: time.Now(),
+	}
+
+	log.Println("\n[Test 55] 備考・初期費用・条件等の取得テスト...")
+
+	baseURL := propertyURL[:strings.Index(propertyURL, "/rent/detail/")]
+	s := newFixtureScraper(baseURL)
+
+	var problems []string
+
+	// 1. Rows from the fixture page, line breaks kept
+	property, err := s.ScrapeProperty(baseURL + "/rent/detail/notes00blocks/")
+	if err != nil {
+		problems = append(problems, fmt.Sprintf("取得失敗: %v", err))
+	} else {
+		wantNotes := "【初期費用】\n鍵交換費用 22,000円\n室内清掃費 33,000円\n\n" +
+			"フリーレント1ヶ月\n保証会社必須（初回保証料 賃料の50%）\n駐輪場あり"
+		if property.Notes != wantNotes {
+			problems = append(problems, fmt.Sprintf("notes=%q (want %q)", property.Notes, wantNotes))
+		}
+		if property.Conditions != "二人入居可\nペット相談" {
+			problems = append(problems, fmt.Sprintf("conditions=%q", property.Conditions))
+		}
+		if !property.FreeRent {
+			problems = append(problems, "フリーレント1ヶ月 in 備考 not detected as free_rent")
+		}
+	}
+
+	// 2. A 備考 far over the limit is cut on a rune boundary with an ellipsis
+	long := strings.Repeat("保証会社必須。", 1000)
+	page := `<html><head><title>長い備考</title></head><body><table><tr><th>備考</th><td>` + long + `</td></tr></table></body></html>`
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(page))
+	if err != nil {
+		problems = append(problems, fmt.Sprintf("long: パース失敗: %v", err))
+	} else if longProperty, _, err := s.ParsePropertyDocument(doc, baseURL+"/rent/detail/notes01long/"); err != nil {
+		problems = append(problems, fmt.Sprintf("long: 抽出失敗: %v", err))
+	} else {
+		notes := longProperty.Notes
+		if len(notes) > 4096 || !strings.HasSuffix(notes, "…") || !utf8.ValidString(notes) || !strings.HasPrefix(long, strings.TrimSuffix(notes, "…")) {
+			problems = append(problems, fmt.Sprintf("long: %d bytes, valid=%v, tail=%q", len(notes), utf8.ValidString(notes), notes[max(0, len(notes)-12):]))
+		}
+	}
+
+	result.Details = map[string]interface{}{
+		"problems": problems,
+	}
+	if len(problems) > 0 {
+		result.Message = fmt.Sprintf("備考・条件等が不正: %v", problems)
+		log.Printf("  ❌ %v", problems)
+		return result
+	}
+
+	result.Success = true
+	result.Message = "初期費用・備考が改行付きで Notes に、条件等が Conditions に入り、長い備考は 4KB で「…」付きに切られることを確認"
+	log.Printf("  ✅ %s", result.Message)
+	return result
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"real-estate-portal/internal/database"
+	"real-estate-portal/internal/meta"
+	"real-estate-portal/internal/models"
+	"real-estate-portal/internal/search"
+	"slices"
+	"time"
+)
+
+// Test 22: 選択肢一覧（/api/meta/options）と検証の一致（フィクスチャモードのみ）
+// GetPropertiesWithSort が受け付ける並び順キーがすべて payload に含まれ、検証も同じ一覧を使うことを確認する
+func testMetaOptions() TestResult {
+	result := TestResult{
+		TestName:  "選択肢一覧と検証の一致",
+		Timestamp: time.Now(),
+	}
+
+	log.Println("\n[Test 22] 選択肢一覧（/api/meta/options）テスト...")
+
+	var problems []string
+
+	// Round-trip through JSON so the check sees what the endpoint serves
+	raw, err := json.Marshal(meta.CurrentOptions())
+	if err != nil {
+		result.Message = fmt.Sprintf("payload のシリアライズ失敗: %v", err)
+		return result
+	}
+	var payload map[string][]string
+	if err := json.Unmarshal(raw, &payload); err != nil {
+		result.Message = fmt.Sprintf("payload のデシリアライズ失敗: %v", err)
+		return result
+	}
+	for _, field := range []string{"floor_plans", "building_types", "property_sort_keys", "search_sort_keys",
+		"filter_sort_keys", "change_types", "queue_statuses", "delete_reasons"} {
+		if len(payload[field]) == 0 {
+			problems = append(problems, fmt.Sprintf("%s is empty", field))
+		}
+	}
+
+	// Every key the DB layer orders by is advertised and validated, and vice versa
+	for _, key := range database.SortKeys() {
+		if !slices.Contains(payload["property_sort_keys"], key) {
+			problems = append(problems, fmt.Sprintf("sort key %q missing from payload", key))
+		}
+		if !models.IsPropertySortKey(key) {
+			problems = append(problems, fmt.Sprintf("sort key %q rejected by validation", key))
+		}
+	}
+	for _, key := range models.PropertySortKeys {
+		if !slices.Contains(database.SortKeys(), key) {
+			problems = append(problems, fmt.Sprintf("validated sort key %q has no ORDER BY", key))
+		}
+		f := database.PropertyFilters{SortBy: key}
+		if err := f.ValidateAndNormalize(); err != nil {
+			problems = append(problems, fmt.Sprintf("ValidateAndNormalize(%q): %v", key, err))
+		}
+	}
+	if err := (&database.PropertyFilters{SortBy: "bogus"}).ValidateAndNormalize(); err == nil {
+		problems = append(problems, "unknown sort key accepted")
+	}
+
+	for _, key := range payload["search_sort_keys"] {
+		if _, ok := search.SearchSort(key); !ok {
+			problems = append(problems, fmt.Sprintf("search sort %q has no Meilisearch expression", key))
+		}
+	}
+	for _, key := range payload["filter_sort_keys"] {
+		if err := (search.FilterParams{SortBy: key}).Validate(); err != nil {
+			problems = append(problems, fmt.Sprintf("filter sort %q rejected: %v", key, err))
+		}
+	}
+
+	// Multi-select values share the same lists
+	valid := database.PropertyFilters{FloorPlans: payload["floor_plans"], BuildingTypes: payload["building_types"]}
+	if err := valid.ValidateAndNormalize(); err != nil {
+		problems = append(problems, fmt.Sprintf("canonical floor plans/building types rejected: %v", err))
+	}
+	if err := (&database.PropertyFilters{FloorPlans: []string{"5SLDK"}}).ValidateAndNormalize(); err == nil {
+		problems = append(problems, "unknown floor plan accepted")
+	}
+	if err := (&database.PropertyFilters{BuildingTypes: []string{"castle"}}).ValidateAndNormalize(); err == nil {
+		problems = append(problems, "unknown building type accepted")
+	}
+	if err := (search.FilterParams{FloorPlans: []string{"1K", "5SLDK"}}).Validate(); err == nil {
+		problems = append(problems, "unknown floor plan accepted by /api/filter")
+	}
+
+	result.Details = map[string]interface{}{
+		"property_sort_keys": payload["property_sort_keys"],
+		"search_sort_keys":   payload["search_sort_keys"],
+		"floor_plans":        len(payload["floor_plans"]),
+		"change_types":       len(payload["change_types"]),
+		"problems":           problems,
+	}
+	if len(problems) > 0 {
+		result.Message = fmt.Sprintf("選択肢一覧の検証失敗: %v", problems)
+		log.Printf("  ❌ %v", problems)
+		return result
+	}
+
+	result.Success = true
+	result.Message = fmt.Sprintf("並び順キー%d件・間取り%d件が検証と一致", len(payload["property_sort_keys"]), len(payload["floor_plans"]))
+	log.Printf("  ✅ %s", result.Message)
+	return result
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"real-estate-portal/internal/scraper"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Test 50: インスタンスごとのリクエスト間隔（フィクスチャモードのみ）
+// 1つの Scraper を複数のゴルーチンから同時に使っても、ページ取得が RequestDelay 以上の間隔で
+// サイトに届くことを確認する（データ競合は pacing_test.go を go test -race で確認）
+func testRequestDelay() TestResult {
+	result := TestResult{
+		TestName:  "インスタンスごとのリクエスト間隔",
+		Timestamp: time.Now(),
+	}
+
+	log.Println("\n[Test 50] インスタンスごとのリクエスト間隔テスト...")
+
+	var mu sync.Mutex
+	var arrivals []time.Time
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		arrivals = append(arrivals, time.Now())
+		mu.Unlock()
+		fmt.Fprint(w, `<html><head><title>ok</title></head><body><h1>ok</h1></body></html>`)
+	}))
+	defer server.Close()
+
+	const delay = 150 * time.Millisecond
+	const callers = 4
+	s := scraper.NewScraperWithConfig(scraper.ScraperConfig{
+		Timeout:      5 * time.Second,
+		RequestDelay: delay,
+		BaseURL:      server.URL,
+		FixtureMode:  true,
+	})
+
+	var problems []string
+	var wg sync.WaitGroup
+	errs := make(chan error, callers)
+	start := time.Now()
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if _, err := s.ScrapeProperty(fmt.Sprintf("%s/rent/detail/pace%02d/", server.URL, i)); err != nil {
+				errs <- err
+			}
+		}(i)
+	}
+	wg.Wait()
+	elapsed := time.Since(start)
+	close(errs)
+	for err := range errs {
+		problems = append(problems, fmt.Sprintf("取得失敗: %v", err))
+	}
+
+	mu.Lock()
+	got := append([]time.Time(nil), arrivals...)
+	mu.Unlock()
+	sort.Slice(got, func(i, j int) bool { return got[i].Before(got[j]) })
+	if len(got) != callers {
+		problems = append(problems, fmt.Sprintf("%d requests reached the server (want %d)", len(got), callers))
+	}
+	minGap := time.Duration(0)
+	for i := 1; i < len(got); i++ {
+		gap := got[i].Sub(got[i-1])
+		if i == 1 || gap < minGap {
+			minGap = gap
+		}
+		// A few ms of slack for scheduling between the reserved slot and the request
+		if gap < delay-5*time.Millisecond {
+			problems = append(problems, fmt.Sprintf("requests %d and %d only %v apart (want >= %v)", i, i+1, gap.Round(time.Millisecond), delay))
+		}
+	}
+
+	result.Details = map[string]interface{}{
+		"callers":    callers,
+		"min_gap_ms": minGap.Milliseconds(),
+		"elapsed_ms": elapsed.Milliseconds(),
+		"problems":   problems,
+	}
+	if len(problems) > 0 {
+		result.Message = fmt.Sprintf("リクエスト間隔が不正: %v", problems)
+		log.Printf("  ❌ %v", problems)
+		return result
+	}
+
+	result.Success = true
+	result.Message = fmt.Sprintf("%d件の同時取得が RequestDelay（%v）間隔で送信されることを確認（最小間隔 %v）",
+		callers, delay, minGap.Round(time.Millisecond))
+	log.Printf("  ✅ %s", result.Message)
+	return result
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"real-estate-portal/internal/models"
+	"real-estate-portal/internal/queue"
+	"real-estate-portal/internal/scheduler"
+	"real-estate-portal/internal/scraper"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// pacingSlack allows for scheduling between the reserved slot and the request reaching the server
+const pacingSlack = 5 * time.Millisecond
+
+// arrivalRecorder wraps a handler and keeps the arrival time of every GET whose path
+// contains match
+type arrivalRecorder struct {
+	mu       sync.Mutex
+	arrivals []time.Time
+}
+
+func (a *arrivalRecorder) wrap(match string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet && strings.Contains(r.URL.Path, match) {
+			a.mu.Lock()
+			a.arrivals = append(a.arrivals, time.Now())
+			a.mu.Unlock()
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// checkSpacing fails the test unless want requests arrived, each at least delay after the one before
+func (a *arrivalRecorder) checkSpacing(t *testing.T, want int, delay time.Duration) {
+	t.Helper()
+	a.mu.Lock()
+	got := append([]time.Time(nil), a.arrivals...)
+	a.mu.Unlock()
+	sort.Slice(got, func(i, j int) bool { return got[i].Before(got[j]) })
+	if len(got) != want {
+		t.Errorf("%d requests reached the server (want %d)", len(got), want)
+	}
+	for i := 1; i < len(got); i++ {
+		if gap := got[i].Sub(got[i-1]); gap < delay-pacingSlack {
+			t.Errorf("requests %d and %d only %v apart (want >= %v)", i, i+1, gap.Round(time.Millisecond), delay)
+		}
+	}
+}
+
+// Concurrent ScrapeProperty calls on one Scraper reach the site RequestDelay apart
+func TestRequestDelayConcurrentScrapes(t *testing.T) {
+	var rec arrivalRecorder
+	srv := httptest.NewServer(rec.wrap("/rent/detail/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<html><head><title>ok</title></head><body><h1>ok</h1></body></html>`)
+	})))
+	t.Cleanup(srv.Close)
+
+	const delay = 100 * time.Millisecond
+	const callers = 5
+	scraper.ConfigureSourceLimits("yahoo", 10*time.Millisecond, 0, 1000) // keep the shared limiter out of the way
+	s := scraper.NewScraperWithConfig(scraper.ScraperConfig{
+		Timeout:      5 * time.Second,
+		RequestDelay: delay,
+		BaseURL:      srv.URL,
+		FixtureMode:  true,
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if _, err := s.ScrapeProperty(fmt.Sprintf("%s/rent/detail/pace%02d/", srv.URL, i)); err != nil {
+				t.Errorf("ScrapeProperty %d: %v", i, err)
+			}
+		}(i)
+	}
+	wg.Wait()
+	rec.checkSpacing(t, callers, delay)
+}
+
+// A queue worker processing items in parallel shares its Scraper's RequestDelay: every item
+// is saved, and the detail pages still reach the site RequestDelay apart
+func TestRequestDelayParallelWorker(t *testing.T) {
+	var rec arrivalRecorder
+	fixtures := startFixtureServer("testdata/fixtures")
+	t.Cleanup(fixtures.Close)
+	srv := httptest.NewServer(rec.wrap("/rent/detail/", fixtures.Config.Handler))
+	t.Cleanup(srv.Close)
+
+	const delay = 100 * time.Millisecond
+	scraper.ConfigureSourceLimits("yahoo", 10*time.Millisecond, 0, 1000) // keep the shared limiter out of the way
+	s := scraper.NewScraperWithConfig(scraper.ScraperConfig{
+		Timeout:      10 * time.Second,
+		RetryDelay:   100 * time.Millisecond,
+		RequestDelay: delay,
+		BaseURL:      srv.URL,
+		FixtureMode:  true,
+	})
+	sources := scraper.NewRegistry(s)
+	db := openSQLiteDB(t)
+
+	listURL := srv.URL + "/rent/list/"
+	urls, err := s.ScrapeList(context.Background(), listURL)
+	if err != nil || len(urls) < 2 {
+		t.Fatalf("ScrapeList: %d URLs, %v", len(urls), err)
+	}
+	for _, u := range urls {
+		source, id, detailURL, err := queue.ResolveDetailURL(sources, u)
+		if err != nil {
+			t.Fatalf("ResolveDetailURL(%s): %v", u, err)
+		}
+		item := models.DetailScrapeQueue{Source: source, SourcePropertyID: id, DetailURL: detailURL,
+			RefererURL: listURL, Status: models.QueueStatusPending, Priority: queue.PriorityList}
+		if err := db.Create(&item).Error; err != nil {
+			t.Fatalf("queue %s: %v", id, err)
+		}
+	}
+
+	w := scheduler.NewQueueWorkerWithSources(db, s, sources)
+	w.SetPollInterval(20 * time.Millisecond)
+	w.SetConcurrency(len(urls), len(urls))
+	w.SetHealthCheck(func(context.Context) bool { return true })
+	w.SetPriorityAging(0) // the aged ORDER BY is MySQL's TIMESTAMPDIFF
+	if err := w.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	for deadline := time.Now().Add(30 * time.Second); ; time.Sleep(50 * time.Millisecond) {
+		var done int64
+		if err := db.Model(&models.DetailScrapeQueue{}).Where("status = ?", models.QueueStatusDone).Count(&done).Error; err != nil {
+			t.Fatalf("read queue: %v", err)
+		}
+		if int(done) == len(urls) {
+			break
+		}
+		if time.Now().After(deadline) {
+			var items []models.DetailScrapeQueue
+			db.Find(&items)
+			w.Stop()
+			t.Fatalf("queue not drained: %+v", items)
+		}
+	}
+	if err := w.Stop(); err != nil {
+		t.Fatalf("Stop: %v", err)
+	}
+
+	var saved int64
+	db.Model(&models.Property{}).Count(&saved)
+	if int(saved) != len(urls) {
+		t.Errorf("%d properties saved for %d queue items", saved, len(urls))
+	}
+	rec.checkSpacing(t, len(urls), delay)
+}
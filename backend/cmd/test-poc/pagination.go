@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"real-estate-portal/internal/scraper"
+	"time"
+)
+
+// Test 23: 一覧ページのページ送り（フィクスチャモードのみ）
+// 「次へ」リンクを max_pages まで辿り、重複を除いたURLと訪問ページ数を返すこと、
+// 新規URLのないページで打ち切ることを確認する
+func testListPagination(s *scraper.Scraper, listURL string) TestResult {
+	result := TestResult{
+		TestName:  "一覧ページのページ送り",
+		Timestamp: time.Now(),
+	}
+
+	log.Println("\n[Test 23] 一覧ページのページ送りテスト...")
+
+	// list.html: 2件 → list_page2.html: 新規1件 → list_page3.html: 新規0件（ここで打ち切り）
+	cases := []struct {
+		maxPages  int
+		wantURLs  int
+		wantPages int
+	}{
+		{1, 2, 1},
+		{2, 3, 2},
+		{10, 3, 3}, // stops on page 3 (no new URLs) instead of following 次へ to page 4
+	}
+
+	var problems []string
+	details := map[string]interface{}{}
+	for _, tc := range cases {
+		urls, pages, err := s.ScrapeListPages(listURL, tc.maxPages)
+		details[fmt.Sprintf("max_pages_%d", tc.maxPages)] = map[string]int{"urls": len(urls), "pages": pages}
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("max_pages=%d: %v", tc.maxPages, err))
+			continue
+		}
+		if len(urls) != tc.wantURLs || pages != tc.wantPages {
+			problems = append(problems, fmt.Sprintf("max_pages=%d: got %d urls / %d pages, want %d / %d",
+				tc.maxPages, len(urls), pages, tc.wantURLs, tc.wantPages))
+		}
+		seen := make(map[string]bool)
+		for _, u := range urls {
+			if seen[u] {
+				problems = append(problems, fmt.Sprintf("max_pages=%d: duplicate url %s", tc.maxPages, u))
+			}
+			seen[u] = true
+		}
+	}
+
+	// The first page alone must match ScrapeListPage
+	single, err := s.ScrapeListPage(listURL)
+	if err != nil || len(single) != cases[0].wantURLs {
+		problems = append(problems, fmt.Sprintf("ScrapeListPage: got %d urls, err=%v", len(single), err))
+	}
+
+	details["problems"] = problems
+	result.Details = details
+	if len(problems) > 0 {
+		result.Message = fmt.Sprintf("ページ送りの検証失敗: %v", problems)
+		log.Printf("  ❌ %v", problems)
+		return result
+	}
+
+	result.Success = true
+	result.Message = "次へリンクを辿り、新規URLのないページで打ち切り"
+	log.Printf("  ✅ %s", result.Message)
+	return result
+}
@@ -0,0 +1,206 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"real-estate-portal/internal/scraper"
+	"runtime"
+	"runtime/debug"
+	"runtime/metrics"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// 並列パースのストレステストで使うページ数と水増し行数（1ページ約1MB・約6万ノード）
+const (
+	parseStressPages   = 20
+	parseStressPadding = 30000
+)
+
+// Test 21: HTMLパースの同時実行上限（フィクスチャモードのみ）
+// 20ページを同時にパースしても上限（既定2）を超えず、無制限時よりピークメモリが小さいことを確認する
+func testParseBudget(fixtureDir, propertyURL string) TestResult {
+	result := TestResult{
+		TestName:  "HTMLパースの同時実行上限",
+		Timestamp: time.Now(),
+	}
+
+	log.Println("\n[Test 21] HTMLパース同時実行上限テスト...")
+
+	base, err := os.ReadFile(filepath.Join(fixtureDir, "detail.html"))
+	if err != nil {
+		result.Message = fmt.Sprintf("フィクスチャ読み込み失敗: %v", err)
+		return result
+	}
+	var pad strings.Builder
+	pad.WriteString(`<ul class="StressPadding">`)
+	for i := 0; i < parseStressPadding; i++ {
+		fmt.Fprintf(&pad, "<li>padding row %d</li>", i)
+	}
+	pad.WriteString("</ul></body>")
+	page := strings.Replace(string(base), "</body>", pad.String(), 1)
+
+	// Keep the heap close to live data so RSS reflects how many DOMs are held at once
+	defer debug.SetGCPercent(debug.SetGCPercent(25))
+	defer scraper.ConfigureParseBudget(scraper.DefaultMaxConcurrentParses,
+		scraper.DefaultMaxDocumentBytes, scraper.DefaultMaxDocumentNodes)
+
+	var problems []string
+
+	scraper.ConfigureParseBudget(scraper.DefaultMaxConcurrentParses, 0, 0)
+	bounded, err := runParseStress(page, propertyURL)
+	if err != nil {
+		problems = append(problems, fmt.Sprintf("bounded: %v", err))
+	}
+	scraper.ConfigureParseBudget(parseStressPages, 0, 0)
+	unbounded, err := runParseStress(page, propertyURL)
+	if err != nil {
+		problems = append(problems, fmt.Sprintf("unbounded: %v", err))
+	}
+
+	if bounded.highWater > scraper.DefaultMaxConcurrentParses {
+		problems = append(problems, fmt.Sprintf("high-water %d exceeds bound %d", bounded.highWater, scraper.DefaultMaxConcurrentParses))
+	}
+	if unbounded.highWater <= scraper.DefaultMaxConcurrentParses {
+		problems = append(problems, fmt.Sprintf("unbounded run only reached %d concurrent parses", unbounded.highWater))
+	}
+	// "Materially lower": at least 30% below the unbounded peak
+	if bounded.peakBytes*10 > unbounded.peakBytes*7 {
+		problems = append(problems, fmt.Sprintf("peak memory %dMB not materially below unbounded %dMB",
+			bounded.peakBytes>>20, unbounded.peakBytes>>20))
+	}
+
+	// Node guard: an absurdly large page fails with the typed error instead of being processed
+	scraper.ConfigureParseBudget(0, 0, parseStressPadding)
+	_, err = scraper.NewScraperWithConfig(scraper.ScraperConfig{FixtureMode: true}).
+		ParsePropertyHTML(context.Background(), page, propertyURL)
+	var tooLarge *scraper.DocumentTooLargeError
+	if !errors.As(err, &tooLarge) || tooLarge.Limit != "nodes" {
+		problems = append(problems, fmt.Sprintf("node guard: got %v", err))
+	}
+
+	result.Details = map[string]interface{}{
+		"pages":                parseStressPages,
+		"page_bytes":           len(page),
+		"bounded_high_water":   bounded.highWater,
+		"bounded_peak_mb":      bounded.peakBytes >> 20,
+		"unbounded_high_water": unbounded.highWater,
+		"unbounded_peak_mb":    unbounded.peakBytes >> 20,
+		"memory_source":        bounded.source,
+		"problems":             problems,
+	}
+	if len(problems) > 0 {
+		result.Message = fmt.Sprintf("パース上限の検証失敗: %v", problems)
+		log.Printf("  ❌ %v", problems)
+		return result
+	}
+
+	result.Success = true
+	result.Message = fmt.Sprintf("同時パース最大%d（上限%d）、ピーク%dMB（無制限時%dMB）",
+		bounded.highWater, scraper.DefaultMaxConcurrentParses, bounded.peakBytes>>20, unbounded.peakBytes>>20)
+	log.Printf("  ✅ %s", result.Message)
+	return result
+}
+
+// parseStressRun is the outcome of parsing parseStressPages pages at once
+type parseStressRun struct {
+	highWater int
+	peakBytes int64 // peak memory above the pre-run baseline
+	source    string
+}
+
+// runParseStress parses the page concurrently with the current ParseLimiter while sampling memory
+func runParseStress(page, propertyURL string) (parseStressRun, error) {
+	runtime.GC()
+	debug.FreeOSMemory()
+	baseline, source := sampleMemory()
+
+	var peak atomic.Int64
+	done := make(chan struct{})
+	sampled := make(chan struct{})
+	go func() {
+		defer close(sampled)
+		ticker := time.NewTicker(2 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			if v, _ := sampleMemory(); v > peak.Load() {
+				peak.Store(v)
+			}
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	var failures atomic.Int64
+	var firstErr atomic.Value
+	for i := 0; i < parseStressPages; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s := scraper.NewScraperWithConfig(scraper.ScraperConfig{FixtureMode: true})
+			if _, err := s.ParsePropertyHTML(context.Background(), page, propertyURL); err != nil {
+				failures.Add(1)
+				firstErr.CompareAndSwap(nil, err)
+			}
+		}()
+	}
+	wg.Wait()
+	close(done)
+	<-sampled
+
+	run := parseStressRun{
+		highWater: scraper.ParseLimiter.Status().HighWater,
+		peakBytes: peak.Load() - baseline,
+		source:    source,
+	}
+	if n := failures.Load(); n > 0 {
+		return run, fmt.Errorf("%d/%d parses failed: %v", n, parseStressPages, firstErr.Load())
+	}
+	return run, nil
+}
+
+// sampleMemory returns the process RSS (Linux), falling back to live heap bytes elsewhere
+func sampleMemory() (int64, string) {
+	if rss, ok := readRSS(); ok {
+		return rss, "rss"
+	}
+	sample := []metrics.Sample{{Name: "/memory/classes/heap/objects:bytes"}}
+	metrics.Read(sample)
+	return int64(sample[0].Value.Uint64()), "heap"
+}
+
+// readRSS reads VmRSS from /proc/self/status
+func readRSS() (int64, bool) {
+	f, err := os.Open("/proc/self/status")
+	if err != nil {
+		return 0, false
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "VmRSS:") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			return 0, false
+		}
+		kb, err := strconv.ParseInt(fields[1], 10, 64)
+		return kb << 10, err == nil
+	}
+	return 0, false
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"real-estate-portal/internal/models"
+	"real-estate-portal/internal/queue"
+	"real-estate-portal/internal/scheduler"
+	"real-estate-portal/internal/scraper"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Test 86: キューの優先度エージング（オフライン）
+// ワーカーの ORDER BY が created_at からの経過時間で priority を引き上げる式になり、古い低優先度の
+// 項目を新しい高優先度の項目より先に取り出すこと（エージング無効なら priority 順のまま）を確認する。
+// 設定値と AgedPriority の計算は internal/config・internal/queue のテストで確認する
+func testPriorityAging() TestResult {
+	result := TestResult{
+		TestName:  "キューの優先度エージング",
+		Timestamp: time.Now(),
+	}
+
+	log.Println("\n[Test 86] キューの優先度エージングテスト...")
+
+	var problems []string
+
+	// The worker picks the three-day-old light item before the fresh manual one, and the
+	// manual one first with aging off
+	order := func(aging time.Duration) ([]string, string, error) {
+		now := time.Now()
+		q := &fakeWorkerQueue{rows: []models.DetailScrapeQueue{
+			{ID: 1, Source: "stub", SourcePropertyID: "fresh01", DetailURL: "https://stub.example/detail/fresh01/",
+				Status: models.QueueStatusPending, Priority: queue.PriorityManual, CreatedAt: now},
+			{ID: 2, Source: "stub", SourcePropertyID: "old01", DetailURL: "https://stub.example/detail/old01/",
+				Status: models.QueueStatusPending, Priority: queue.PriorityLight, CreatedAt: now.Add(-72 * time.Hour)},
+		}}
+		db, err := openFakeWorkerDB(q)
+		if err != nil {
+			return nil, "", err
+		}
+		var orderSQL string
+		err = db.Callback().Query().After("gorm:query").Register("poc:priority_aging", func(tx *gorm.DB) {
+			if sql := tx.Dialector.Explain(tx.Statement.SQL.String(), tx.Statement.Vars...); strings.Contains(sql, "detail_scrape_queue") && strings.Contains(sql, "ORDER BY") && orderSQL == "" {
+				orderSQL = sql
+			}
+		})
+		if err != nil {
+			return nil, "", err
+		}
+		var mu sync.Mutex
+		var scraped []string
+		source := newStubSource(func(_ context.Context, url string) (*models.Property, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			scraped = append(scraped, strings.TrimSuffix(url[strings.LastIndex(strings.TrimSuffix(url, "/"), "/")+1:], "/"))
+			return nil, fmt.Errorf("%w: status code 404", scraper.ErrNotFound)
+		})
+		w := scheduler.NewQueueWorkerWithSources(db, scraper.NewScraper(), scraper.NewRegistry(source))
+		w.SetPollInterval(10 * time.Millisecond)
+		w.SetHealthCheck(func(context.Context) bool { return true })
+		w.SetPriorityAging(aging)
+		if err := w.Start(); err != nil {
+			return nil, "", err
+		}
+		for deadline := time.Now().Add(3 * time.Second); time.Now().Before(deadline) &&
+			(q.row(1).Status == models.QueueStatusPending || q.row(2).Status == models.QueueStatusPending); time.Sleep(5 * time.Millisecond) {
+		}
+		err = w.Stop()
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), scraped...), orderSQL, err
+	}
+
+	aged, agedSQL, err := order(24 * time.Hour)
+	if err != nil {
+		problems = append(problems, fmt.Sprintf("aging worker: %v", err))
+	}
+	if fmt.Sprint(aged) != "[old01 fresh01]" {
+		problems = append(problems, fmt.Sprintf("with aging the worker scraped %v (want [old01 fresh01])", aged))
+	}
+	if !strings.Contains(agedSQL, "ORDER BY priority + FLOOR(TIMESTAMPDIFF(HOUR, created_at, '") || !strings.Contains(agedSQL, "/ 24) DESC, created_at ASC") {
+		problems = append(problems, fmt.Sprintf("aging ORDER BY: %s", agedSQL))
+	}
+	strict, strictSQL, err := order(0)
+	if err != nil {
+		problems = append(problems, fmt.Sprintf("strict worker: %v", err))
+	}
+	if fmt.Sprint(strict) != "[fresh01 old01]" {
+		problems = append(problems, fmt.Sprintf("without aging the worker scraped %v (want [fresh01 old01])", strict))
+	}
+	if !strings.Contains(strictSQL, "ORDER BY priority DESC, created_at ASC") {
+		problems = append(problems, fmt.Sprintf("strict ORDER BY: %s", strictSQL))
+	}
+
+	result.Details = map[string]interface{}{
+		"aged_order":   aged,
+		"strict_order": strict,
+		"order_sql":    agedSQL,
+		"problems":     problems,
+	}
+	if len(problems) > 0 {
+		result.Message = fmt.Sprintf("優先度エージングが不正: %v", problems)
+		log.Printf("  ❌ %v", problems)
+		return result
+	}
+
+	result.Success = true
+	result.Message = "待ち時間で priority が引き上げられ、古い低優先度の項目が新しい高優先度の項目より先に取り出されることを確認"
+	log.Printf("  ✅ %s", result.Message)
+	return result
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"real-estate-portal/internal/errtext"
+	"real-estate-portal/internal/scheduler"
+	"real-estate-portal/internal/scraper"
+	"strings"
+	"sync"
+	"time"
+)
+
+// forwardProxy is a plain HTTP forward proxy that records the requests it relayed
+type forwardProxy struct {
+	*httptest.Server
+	mu   sync.Mutex
+	hits []string // "GET /rent/detail/<id>/"
+}
+
+func newForwardProxy() *forwardProxy {
+	p := &forwardProxy{}
+	p.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !r.URL.IsAbs() {
+			http.Error(w, "not a proxy request", http.StatusBadRequest)
+			return
+		}
+		p.mu.Lock()
+		p.hits = append(p.hits, r.Method+" "+r.URL.Path)
+		p.mu.Unlock()
+
+		out := r.Clone(r.Context())
+		out.RequestURI = ""
+		resp, err := http.DefaultTransport.RoundTrip(out)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+		defer resp.Body.Close()
+		for k, vs := range resp.Header {
+			for _, v := range vs {
+				w.Header().Add(k, v)
+			}
+		}
+		w.WriteHeader(resp.StatusCode)
+		io.Copy(w, resp.Body)
+	}))
+	return p
+}
+
+func (p *forwardProxy) relayed() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]string(nil), p.hits...)
+}
+
+// Test 46: プロキシ経由の取得とローテーション（フィクスチャモードのみ）
+// 設定したプロキシを順番に使い、接続できない・407を返すプロキシは次へ回すこと、
+// 画像のHEAD確認も同じプロキシを通ること、全プロキシ失敗は proxy_error として区別されることを確認する
+func testProxyRotation(propertyURL string) TestResult {
+	result := TestResult{
+		TestName:  "プロキシ経由の取得とローテーション",
+		Timestamp: time.Now(),
+	}
+
+	log.Println("\n[Test 46] プロキシ経由の取得とローテーションテスト...")
+
+	detailBase := propertyURL[:strings.Index(propertyURL, "/rent/detail/")+len("/rent/detail/")]
+	baseURL := strings.TrimSuffix(detailBase, "/rent/detail/")
+
+	proxyA, proxyB := newForwardProxy(), newForwardProxy()
+	defer proxyA.Close()
+	defer proxyB.Close()
+	authProxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Proxy-Authenticate", `Basic realm="egress"`)
+		w.WriteHeader(http.StatusProxyAuthRequired)
+	}))
+	defer authProxy.Close()
+	dead := httptest.NewServer(http.NotFoundHandler())
+	deadURL := strings.Replace(dead.URL, "http://", "http://user:secret@", 1) // credentials must not leak into errors
+	dead.Close()
+
+	newProxiedScraper := func(proxies ...string) *scraper.Scraper {
+		return scraper.NewScraperWithConfig(scraper.ScraperConfig{
+			Timeout:      5 * time.Second,
+			BaseURL:      baseURL,
+			FixtureMode:  true,
+			Proxies:      proxies,
+			VerifyImages: true,
+		})
+	}
+	// Earlier tests already checked the layout image; it must be checked (and proxied) again
+	scraper.ResetImageVerifyCache()
+
+	var problems []string
+
+	// 1. Two healthy proxies: pages alternate between them; the layout HEAD check is proxied too
+	s := newProxiedScraper(proxyA.URL, proxyB.URL)
+	for _, id := range []string{"station01one", "layout02img"} {
+		property, err := s.ScrapeProperty(detailBase + id + "/")
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("rotation %s: 取得失敗: %v", id, err))
+		} else if property.Title == "" {
+			problems = append(problems, fmt.Sprintf("rotation %s: empty title through proxy", id))
+		}
+	}
+	hitsA, hitsB := proxyA.relayed(), proxyB.relayed()
+	if len(hitsA) == 0 || len(hitsB) == 0 {
+		problems = append(problems, fmt.Sprintf("rotation: proxy A relayed %v, proxy B relayed %v (want both used)", hitsA, hitsB))
+	}
+	headProxied := false
+	for _, hit := range append(hitsA, hitsB...) {
+		if hit == "HEAD /images/layout0301.gif" {
+			headProxied = true
+		}
+	}
+	if !headProxied {
+		problems = append(problems, fmt.Sprintf("image check not proxied: relayed %v", append(hitsA, hitsB...)))
+	}
+
+	// 2. A dead proxy and a 407 proxy hand the request over to the healthy one
+	before := len(proxyA.relayed())
+	s = newProxiedScraper(deadURL, authProxy.URL, proxyA.URL)
+	if _, err := s.ScrapeProperty(detailBase + "station01one/"); err != nil {
+		problems = append(problems, fmt.Sprintf("failover: 取得失敗: %v", err))
+	} else if len(proxyA.relayed()) == before {
+		problems = append(problems, "failover: healthy proxy never reached")
+	}
+
+	// 3. Every proxy failing is a proxy error, not a site failure
+	s = newProxiedScraper(deadURL, authProxy.URL)
+	_, err := s.ScrapeProperty(detailBase + "station01one/")
+	switch {
+	case err == nil:
+		problems = append(problems, "all proxies down: no error returned")
+	case !errors.Is(err, scraper.ErrProxy):
+		problems = append(problems, fmt.Sprintf("all proxies down: %v is not ErrProxy", err))
+	default:
+		if code, _ := errtext.FromError(err); code != errtext.CodeProxy {
+			problems = append(problems, fmt.Sprintf("all proxies down: code=%s (want %s)", code, errtext.CodeProxy))
+		}
+		if failure := scheduler.ClassifyScrapeFailure(err); failure != scheduler.FailureProxy {
+			problems = append(problems, fmt.Sprintf("all proxies down: failure=%s (want %s)", failure, scheduler.FailureProxy))
+		}
+		if strings.Contains(err.Error(), "secret") {
+			problems = append(problems, fmt.Sprintf("all proxies down: credentials in error %q", err))
+		}
+	}
+
+	result.Details = map[string]interface{}{
+		"proxy_a_relayed": len(proxyA.relayed()),
+		"proxy_b_relayed": len(proxyB.relayed()),
+		"problems":        problems,
+	}
+	if len(problems) > 0 {
+		result.Message = fmt.Sprintf("プロキシ経由の取得が不正: %v", problems)
+		log.Printf("  ❌ %v", problems)
+		return result
+	}
+
+	result.Success = true
+	result.Message = "プロキシのローテーション・失敗時の切り替え・proxy_error の分類を確認"
+	log.Printf("  ✅ %s", result.Message)
+	return result
+}
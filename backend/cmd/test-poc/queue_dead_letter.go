@@ -0,0 +1,138 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"real-estate-portal/internal/errtext"
+	"real-estate-portal/internal/models"
+	"real-estate-portal/internal/queue"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Test 76: デッドレターの確認と一括再投入（オフライン）
+// queue.Service.ListDead が failed / permanent_fail を last_error_code（無ければ last_error から分類）で
+// 404・WAF・タイムアウトなどにまとめ、件数の多い順に返すこと、Requeue が条件を1回の UPDATE（最新の行だけ・
+// 有効な行がある物件は除外・上限 1000 件）にして影響件数を返し、上限超えや他のステータスを拒否することを確認する
+func testQueueDeadLetter() TestResult {
+	result := TestResult{
+		TestName:  "デッドレターの確認と一括再投入",
+		Timestamp: time.Now(),
+	}
+
+	log.Println("\n[Test 76] デッドレターの確認と一括再投入テスト...")
+
+	dead := []models.DetailScrapeQueue{
+		{ID: 11, Status: models.QueueStatusPermanentFail, LastError: "Permanent failure: status code 404", LastErrorCode: errtext.CodeNotFound},
+		{ID: 12, Status: models.QueueStatusPermanentFail, LastError: "status code 404"}, // written before codes were stored
+		{ID: 13, Status: models.QueueStatusFailed, LastError: "WAF detected: access blocked", LastErrorCode: errtext.CodeWAF},
+		{ID: 14, Status: models.QueueStatusFailed, LastError: "Max retries exceeded (5): context deadline exceeded (Client.Timeout)"},
+	}
+
+	// Dry-run DB: counts per status (failed 3, permanent_fail 2), 1500 for the requeue filter;
+	// the dead list returns the rows above; the requeue UPDATE "affects" its LIMIT
+	var updates []string
+	db, err := openDryRunDB()
+	if err == nil {
+		err = db.Callback().Query().After("gorm:query").Register("poc:dead_letter", func(tx *gorm.DB) {
+			switch dest := tx.Statement.Dest.(type) {
+			case *int64:
+				vars := whereVars(tx)
+				switch {
+				case len(vars) == 1 && vars[0] == models.QueueStatusFailed:
+					*dest = 3
+				case len(vars) == 1 && vars[0] == models.QueueStatusPermanentFail:
+					*dest = 2
+				default:
+					*dest = 1500
+				}
+				tx.RowsAffected = 1
+			case *[]models.DetailScrapeQueue:
+				*dest = append(*dest, dead...)
+				tx.RowsAffected = int64(len(dead))
+			}
+		})
+	}
+	if err == nil {
+		err = db.Callback().Update().After("gorm:update").Register("poc:dead_letter_update", func(tx *gorm.DB) {
+			updates = append(updates, tx.Dialector.Explain(tx.Statement.SQL.String(), tx.Statement.Vars...))
+			tx.RowsAffected = 1000
+		})
+	}
+	if err != nil {
+		result.Message = fmt.Sprintf("dry-run DB: %v", err)
+		log.Printf("  ❌ %s", result.Message)
+		return result
+	}
+	service := queue.NewService(db)
+
+	var problems []string
+
+	// 1. Dead list: grouped by class, biggest group first, totals from the counts
+	report, err := service.ListDead(0)
+	if err != nil {
+		problems = append(problems, fmt.Sprintf("ListDead: %v", err))
+	} else {
+		var classes []string
+		for _, group := range report.Groups {
+			classes = append(classes, fmt.Sprintf("%s:%d", group.ErrorClass, group.Count))
+		}
+		want := []string{errtext.CodeNotFound + ":2", errtext.CodeTimeout + ":1", errtext.CodeWAF + ":1"}
+		if strings.Join(classes, " ") != strings.Join(want, " ") {
+			problems = append(problems, fmt.Sprintf("groups %v (want %v)", classes, want))
+		}
+		if report.Total != 5 || report.ByStatus[models.QueueStatusFailed] != 3 || report.Loaded != 4 || !report.Truncated {
+			problems = append(problems, fmt.Sprintf("totals: total=%d by_status=%v loaded=%d truncated=%v",
+				report.Total, report.ByStatus, report.Loaded, report.Truncated))
+		}
+	}
+
+	// 2. Requeue: one UPDATE with every condition, capped at 1000, oldest first
+	before := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	res, err := service.Requeue(queue.RequeueFilter{ErrorContains: "WAF", CreatedBefore: &before})
+	if err != nil {
+		problems = append(problems, fmt.Sprintf("Requeue: %v", err))
+	} else {
+		if res.Matched != 1500 || res.Affected != 1000 || res.Limit != queue.DefaultMaxAffected {
+			problems = append(problems, fmt.Sprintf("requeue result %+v (want matched 1500, affected 1000, limit %d)", res, queue.DefaultMaxAffected))
+		}
+		if len(updates) != 1 {
+			problems = append(problems, fmt.Sprintf("%d UPDATE statements (want 1)", len(updates)))
+		} else {
+			for _, want := range []string{"status IN ('failed','permanent_fail')", "last_error LIKE '%WAF%'", "created_at < '2026-10-01",
+				"MAX(id)", "NOT IN (SELECT active_key", "`status`='pending'", "`attempts`=0", "ORDER BY id ASC LIMIT 1000"} {
+				if !strings.Contains(updates[0], want) {
+					problems = append(problems, fmt.Sprintf("UPDATE lacks %q: %s", want, updates[0]))
+				}
+			}
+		}
+	}
+
+	// 3. Over the cap or another status: refused without touching the table
+	updates = nil
+	for _, filter := range []queue.RequeueFilter{{Limit: 1001}, {Statuses: []string{models.QueueStatusDone}}} {
+		if _, err := service.Requeue(filter); !errors.Is(err, queue.ErrInvalidFilter) {
+			problems = append(problems, fmt.Sprintf("filter %+v: err=%v (want ErrInvalidFilter)", filter, err))
+		}
+	}
+	if len(updates) > 0 {
+		problems = append(problems, fmt.Sprintf("refused filters ran %d UPDATEs", len(updates)))
+	}
+
+	result.Details = map[string]interface{}{
+		"problems": problems,
+	}
+	if len(problems) > 0 {
+		result.Message = fmt.Sprintf("デッドレターの処理が不正: %v", problems)
+		log.Printf("  ❌ %v", problems)
+		return result
+	}
+
+	result.Success = true
+	result.Message = "失敗項目がエラー種別ごとにまとまり、一括再投入が上限 1000 件の1回の UPDATE になることを確認"
+	log.Printf("  ✅ %s", result.Message)
+	return result
+}
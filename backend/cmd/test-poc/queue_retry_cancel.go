@@ -0,0 +1,183 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"real-estate-portal/internal/database"
+	"real-estate-portal/internal/models"
+	"real-estate-portal/internal/scheduler"
+	"real-estate-portal/internal/scraper"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// fakeQueueItems answers the statements of GormDB.GetQueueItem / RetryQueueItem / CancelQueueItem
+// and the status counts of QueueWorker.GetQueueStats from an in-memory detail_scrape_queue
+type fakeQueueItems struct {
+	rows []models.DetailScrapeQueue
+}
+
+var fakeIDPattern = regexp.MustCompile("`id` = (\\d+)")
+
+func (q *fakeQueueItems) register(db *gorm.DB) error {
+	if err := db.Callback().Query().After("gorm:query").Register("poc:queue_items_query", func(tx *gorm.DB) {
+		vars := whereVars(tx)
+		switch dest := tx.Statement.Dest.(type) {
+		case *models.DetailScrapeQueue: // First(&item, id)
+			m := fakeIDPattern.FindStringSubmatch(tx.Dialector.Explain(tx.Statement.SQL.String(), tx.Statement.Vars...))
+			for _, row := range q.rows {
+				if m != nil && strconv.FormatInt(row.ID, 10) == m[1] {
+					*dest = row
+					tx.RowsAffected = 1
+					return
+				}
+			}
+			tx.AddError(gorm.ErrRecordNotFound)
+		case *[]models.DetailScrapeQueue: // Where("active_key = ? AND id <> ?")
+			*dest = nil
+			for _, row := range q.rows {
+				active := row.Status == models.QueueStatusPending || row.Status == models.QueueStatusProcessing
+				if active && models.QueueActiveKey(row.Source, row.SourcePropertyID) == vars[0] && row.ID != vars[1] {
+					*dest = append(*dest, row)
+				}
+			}
+			tx.RowsAffected = int64(len(*dest))
+		case *int64: // Where("status = ?").Count
+			*dest = 0
+			for _, row := range q.rows {
+				if len(vars) > 0 && row.Status == vars[0] {
+					*dest++
+				}
+			}
+			tx.RowsAffected = 1
+		}
+	}); err != nil {
+		return err
+	}
+
+	return db.Callback().Update().After("gorm:update").Register("poc:queue_items_update", func(tx *gorm.DB) {
+		set, _ := tx.Statement.Dest.(map[string]interface{})
+		vars := whereVars(tx) // id, statuses
+		from, _ := vars[1].([]string)
+		for i := range q.rows {
+			row := &q.rows[i]
+			if row.ID != vars[0] || !slices.Contains(from, row.Status) {
+				continue
+			}
+			row.Status = set["status"].(string)
+			row.NextRetryAt = nil
+			if attempts, ok := set["attempts"].(int); ok {
+				row.Attempts = attempts
+			}
+			tx.RowsAffected = 1
+		}
+	})
+}
+
+// Test 75: キュー項目の再試行と取り消し（オフライン）
+// GormDB.RetryQueueItem が failed / done の項目を pending に戻し（next_retry_at を消し、指定時は試行回数も
+// 0 に）、pending の項目や同じ物件に別の有効な行がある項目は衝突にすること、CancelQueueItem が pending /
+// failed を cancelled にし、processing は衝突（409）で拒否すること、cancelled が GetQueueStats に出ることを確認する
+func testQueueRetryCancel() TestResult {
+	result := TestResult{
+		TestName:  "キュー項目の再試行と取り消し",
+		Timestamp: time.Now(),
+	}
+
+	log.Println("\n[Test 75] キュー項目の再試行と取り消しテスト...")
+
+	retryAt := time.Now().Add(time.Hour)
+	fake := &fakeQueueItems{rows: []models.DetailScrapeQueue{
+		{ID: 1, Source: "yahoo", SourcePropertyID: "r01", Status: models.QueueStatusFailed, Attempts: 5, NextRetryAt: &retryAt},
+		{ID: 2, Source: "yahoo", SourcePropertyID: "r02", Status: models.QueueStatusProcessing, Attempts: 1},
+		{ID: 3, Source: "yahoo", SourcePropertyID: "r03", Status: models.QueueStatusPending},
+		{ID: 4, Source: "yahoo", SourcePropertyID: "dup", Status: models.QueueStatusPermanentFail, Attempts: 1},
+		{ID: 5, Source: "yahoo", SourcePropertyID: "dup", Status: models.QueueStatusPending},
+		{ID: 6, Source: "yahoo", SourcePropertyID: "r06", Status: models.QueueStatusDone, Attempts: 2},
+	}}
+	db, err := openDryRunDB()
+	if err == nil {
+		err = fake.register(db)
+	}
+	if err != nil {
+		result.Message = fmt.Sprintf("dry-run DB: %v", err)
+		log.Printf("  ❌ %s", result.Message)
+		return result
+	}
+	gdb := database.NewGormDBFromDB(db)
+
+	var problems []string
+	expect := func(name string, item *models.DetailScrapeQueue, err error, status string, attempts int) {
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", name, err))
+			return
+		}
+		if item.Status != status || item.Attempts != attempts || item.NextRetryAt != nil {
+			problems = append(problems, fmt.Sprintf("%s: status=%s attempts=%d next_retry_at=%v (want %s, %d, nil)",
+				name, item.Status, item.Attempts, item.NextRetryAt, status, attempts))
+		}
+	}
+	expectErr := func(name string, err, want error) {
+		if !errors.Is(err, want) {
+			problems = append(problems, fmt.Sprintf("%s: err=%v (want %v)", name, err, want))
+		}
+	}
+
+	// 1. Retry: failed keeps its attempts unless asked; done comes back with a fresh budget
+	item, err := gdb.RetryQueueItem(1, false)
+	expect("retry failed", item, err, models.QueueStatusPending, 5)
+	item, err = gdb.RetryQueueItem(6, true)
+	expect("retry done with reset", item, err, models.QueueStatusPending, 0)
+
+	// 2. Retry conflicts: already pending, or the listing has another active row; unknown ID
+	_, err = gdb.RetryQueueItem(3, false)
+	expectErr("retry pending", err, database.ErrQueueItemConflict)
+	_, err = gdb.RetryQueueItem(4, false)
+	expectErr("retry with another active row", err, database.ErrQueueItemConflict)
+	_, err = gdb.RetryQueueItem(99, false)
+	expectErr("retry unknown", err, gorm.ErrRecordNotFound)
+
+	// 3. Cancel: processing refused and left alone, pending cancelled once
+	_, err = gdb.CancelQueueItem(2)
+	expectErr("cancel processing", err, database.ErrQueueItemConflict)
+	if fake.rows[1].Status != models.QueueStatusProcessing {
+		problems = append(problems, fmt.Sprintf("cancel processing changed it to %s", fake.rows[1].Status))
+	}
+	item, err = gdb.CancelQueueItem(3)
+	expect("cancel pending", item, err, models.QueueStatusCancelled, 0)
+	_, err = gdb.CancelQueueItem(3)
+	expectErr("cancel twice", err, database.ErrQueueItemConflict)
+
+	// 4. Stats count the new status
+	stats := scheduler.NewQueueWorkerWithScraper(db, scraper.NewScraper()).GetQueueStats()
+	for key, want := range map[string]int64{
+		"pending": 3, "processing": 1, "permanent_fail": 1, "cancelled": 1, "done": 0, "failed": 0,
+	} {
+		if got, _ := stats[key].(int64); got != want {
+			problems = append(problems, fmt.Sprintf("stats %s=%v (want %d)", key, stats[key], want))
+		}
+	}
+	if !slices.Contains(models.QueueStatuses, models.QueueStatusCancelled) {
+		problems = append(problems, "QueueStatuses lacks cancelled")
+	}
+
+	result.Details = map[string]interface{}{
+		"problems": problems,
+	}
+	if len(problems) > 0 {
+		result.Message = fmt.Sprintf("再試行・取り消しが不正: %v", strings.Join(problems, "; "))
+		log.Printf("  ❌ %v", problems)
+		return result
+	}
+
+	result.Success = true
+	result.Message = "再試行で pending に戻り（試行回数は指定時のみ 0）、processing の取り消しは衝突になり、cancelled が統計に出ることを確認"
+	log.Printf("  ✅ %s", result.Message)
+	return result
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"real-estate-portal/internal/scraper"
+	"sync"
+	"time"
+)
+
+// Test 53: 詳細ページのリダイレクト検出（フィクスチャモードのみ）
+// 再掲載で旧詳細URLが新URLへ301された場合に、物件が新しいIDとURLで返り、旧ID→新IDの
+// リダイレクトが GetLastRedirect で取れること、同じ物件内のリダイレクトは報告されないことを確認する。
+// 同じ Scraper で並行する ScrapeDetail は、それぞれ自分の呼び出しのリダイレクトと統計を返すこと
+func testDetailRedirect() TestResult {
+	result := TestResult{
+		TestName:  "詳細ページのリダイレクト検出",
+		Timestamp: time.Now(),
+	}
+
+	log.Println("\n[Test 53] 詳細ページのリダイレクト検出テスト...")
+
+	const oldID = "00000825067800000000000000000000000000000001"
+	const newID = "00000825067800000000000000000000000000000002"
+	mux := http.NewServeMux()
+	mux.HandleFunc("/rent/detail/"+oldID+"/", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/rent/detail/"+newID+"/", http.StatusMovedPermanently)
+	})
+	mux.HandleFunc("/rent/detail/"+newID+"/", func(w http.ResponseWriter, r *http.Request) {
+		// Same listing, tracking query added: not a redirect to another property
+		if r.URL.Query().Get("self") != "" {
+			http.Redirect(w, r, r.URL.Path+"?sc_out=list", http.StatusFound)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		fmt.Fprint(w, `<html><head><title>再掲載物件</title></head><body><h1>再掲載物件</h1></body></html>`)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	s := scraper.NewScraperWithConfig(scraper.ScraperConfig{
+		Timeout:     5 * time.Second,
+		BaseURL:     server.URL,
+		FixtureMode: true,
+	})
+	oldURL := server.URL + "/rent/detail/" + oldID + "/"
+	newURL := server.URL + "/rent/detail/" + newID + "/"
+
+	var problems []string
+
+	// 1. Old URL 301s to the re-published listing
+	property, err := s.ScrapeProperty(oldURL)
+	if err != nil {
+		problems = append(problems, fmt.Sprintf("redirected: 取得失敗: %v", err))
+	} else {
+		if property.SourcePropertyID != newID || property.DetailURL != newURL {
+			problems = append(problems, fmt.Sprintf("redirected: property id=%s url=%s (want the new listing)", property.SourcePropertyID, property.DetailURL))
+		}
+		want := scraper.PropertyRedirect{FromID: oldID, ToID: newID, FromURL: oldURL, ToURL: newURL}
+		if redirect := s.GetLastRedirect(); redirect == nil {
+			problems = append(problems, "redirected: GetLastRedirect is nil")
+		} else if *redirect != want {
+			problems = append(problems, fmt.Sprintf("redirected: GetLastRedirect=%+v (want %+v)", *redirect, want))
+		}
+	}
+
+	// 2. A plain fetch and a redirect within the same listing report nothing (and clear the last one)
+	for _, tc := range []struct{ name, url string }{
+		{"direct", newURL},
+		{"same_listing", newURL + "?self=1"},
+	} {
+		if _, err := s.ScrapeProperty(tc.url); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: 取得失敗: %v", tc.name, err))
+		} else if redirect := s.GetLastRedirect(); redirect != nil {
+			problems = append(problems, fmt.Sprintf("%s: unexpected redirect %+v", tc.name, *redirect))
+		}
+	}
+
+	// 3. Concurrent ScrapeDetail calls on one scraper: each result has its own redirect and stats
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(redirected bool) {
+			defer wg.Done()
+			u := newURL
+			if redirected {
+				u = oldURL
+			}
+			detail, err := s.ScrapeDetail(context.Background(), u, scraper.Validators{})
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err != nil:
+				problems = append(problems, fmt.Sprintf("concurrent: 取得失敗: %v", err))
+			case redirected != (detail.Redirect != nil):
+				problems = append(problems, fmt.Sprintf("concurrent: %s got redirect %+v", u, detail.Redirect))
+			case detail.Stats.Requests < 1:
+				problems = append(problems, fmt.Sprintf("concurrent: %s stats %s", u, detail.Stats))
+			}
+		}(i%2 == 0)
+	}
+	wg.Wait()
+
+	result.Details = map[string]interface{}{
+		"problems": problems,
+	}
+	if len(problems) > 0 {
+		result.Message = fmt.Sprintf("リダイレクト検出が不正: %v", problems)
+		log.Printf("  ❌ %v", problems)
+		return result
+	}
+
+	result.Success = true
+	result.Message = "旧URLの301で新ID・新URLの物件と旧ID→新IDのリダイレクトが返り、同一物件内のリダイレクトは無視され、並行呼び出しでも結果が混ざらないことを確認"
+	log.Printf("  ✅ %s", result.Message)
+	return result
+}
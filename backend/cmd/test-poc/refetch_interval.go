@@ -0,0 +1,75 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"real-estate-portal/internal/config"
+	"real-estate-portal/internal/models"
+	"time"
+)
+
+// Test 58: 再取得の最小間隔（オフライン）
+// min_refetch_interval_minutes の既定値（6時間）・無効化（負数）・指定値が解釈され、取得から間隔内の
+// 物件だけが FetchedWithin で「新しい」と判定される（未取得・間隔0は再取得する）ことを確認する
+func testMinRefetchInterval() TestResult {
+	result := TestResult{
+		TestName:  "再取得の最小間隔",
+		Timestamp: time.Now(),
+	}
+
+	log.Println("\n[Test 58] 再取得の最小間隔テスト...")
+
+	var problems []string
+
+	// 1. Config: default 6h, negative = off, minutes otherwise
+	for _, tc := range []struct {
+		minutes int
+		want    time.Duration
+	}{
+		{0, 6 * time.Hour},
+		{-1, 0},
+		{30, 30 * time.Minute},
+	} {
+		cfg := config.ScraperConfig{MinRefetchIntervalMinutes: tc.minutes}
+		if got := cfg.MinRefetchInterval(); got != tc.want {
+			problems = append(problems, fmt.Sprintf("min_refetch_interval_minutes=%d: %v (want %v)", tc.minutes, got, tc.want))
+		}
+	}
+	if got := config.DefaultConfig().Scraper.MinRefetchInterval(); got != 6*time.Hour {
+		problems = append(problems, fmt.Sprintf("default config: %v (want 6h)", got))
+	}
+
+	// 2. Freshness of a stored listing
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	for _, tc := range []struct {
+		name     string
+		fetched  time.Time
+		interval time.Duration
+		want     bool
+	}{
+		{"10m_ago", now.Add(-10 * time.Minute), 6 * time.Hour, true},
+		{"7h_ago", now.Add(-7 * time.Hour), 6 * time.Hour, false},
+		{"exactly_6h", now.Add(-6 * time.Hour), 6 * time.Hour, false},
+		{"never_fetched", time.Time{}, 6 * time.Hour, false},
+		{"check_off", now.Add(-time.Minute), 0, false},
+	} {
+		p := &models.Property{FetchedAt: tc.fetched}
+		if got := p.FetchedWithin(now, tc.interval); got != tc.want {
+			problems = append(problems, fmt.Sprintf("%s: FetchedWithin=%v (want %v)", tc.name, got, tc.want))
+		}
+	}
+
+	result.Details = map[string]interface{}{
+		"problems": problems,
+	}
+	if len(problems) > 0 {
+		result.Message = fmt.Sprintf("再取得間隔の判定が不正: %v", problems)
+		log.Printf("  ❌ %v", problems)
+		return result
+	}
+
+	result.Success = true
+	result.Message = "既定6時間・負数で無効の再取得間隔が解釈され、間隔内に取得済みの物件だけが再取得を省かれることを確認"
+	log.Printf("  ✅ %s", result.Message)
+	return result
+}
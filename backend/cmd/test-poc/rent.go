@@ -0,0 +1,60 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"real-estate-portal/internal/scraper"
+	"strings"
+	"time"
+)
+
+// Test 40: 賃料の抽出範囲（フィクスチャモードのみ）
+// __SERVER_SIDE_CONTEXT__ の無いページで、賃料より前に礼金「1万円」や「おすすめ 5.5万円〜」があっても
+// 賃料行（無ければ「賃料」の直後の金額）が使われ、「8.5万円（管理費 5,000円）」も正しく読めることを確認する
+func testRentExtraction(s *scraper.Scraper, propertyURL string) TestResult {
+	result := TestResult{
+		TestName:  "賃料の抽出範囲",
+		Timestamp: time.Now(),
+	}
+
+	log.Println("\n[Test 40] 賃料の抽出範囲テスト...")
+
+	detailBase := propertyURL[:strings.Index(propertyURL, "/rent/detail/")+len("/rent/detail/")]
+
+	cases := []struct {
+		id   string
+		rent int
+	}{
+		{"rent00keymoneyfirst", 98000}, // 礼金1万円 and a 5.5万円〜 banner precede the 賃料 row
+		{"rent01withfee", 85000},       // 賃料（管理費等） row: 8.5万円（管理費 5,000円）
+		{"rent02textonly", 72000},      // no table: 賃料：7.2万円 in the page text
+		{"context02htmlonly", 98000},   // plain 賃料 row
+	}
+
+	var problems []string
+	for _, tc := range cases {
+		property, err := s.ScrapeProperty(detailBase + tc.id + "/")
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: 取得失敗: %v", tc.id, err))
+			continue
+		}
+		if !intPtrEq(property.Rent, intp(tc.rent)) {
+			problems = append(problems, fmt.Sprintf("%s: rent=%s (want %d)", tc.id, fmtIntPtr(property.Rent), tc.rent))
+		}
+	}
+
+	result.Details = map[string]interface{}{
+		"cases":    len(cases),
+		"problems": problems,
+	}
+	if len(problems) > 0 {
+		result.Message = fmt.Sprintf("賃料の抽出が不正: %v", problems)
+		log.Printf("  ❌ %v", problems)
+		return result
+	}
+
+	result.Success = true
+	result.Message = fmt.Sprintf("%d件とも礼金・おすすめ金額ではなく賃料を取得", len(cases))
+	log.Printf("  ✅ %s", result.Message)
+	return result
+}
@@ -0,0 +1,216 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"real-estate-portal/internal/models"
+	"real-estate-portal/internal/queue"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// fakeQueueTable answers the COUNT and UPDATE issued by queue.Service against an in-memory
+// detail_scrape_queue, so the real WHERE clause built by the service decides which rows match.
+type fakeQueueTable struct {
+	rows    []models.DetailScrapeQueue
+	updates []string // rendered UPDATE statements
+}
+
+// openDryRunDB returns a GORM handle that renders statements without a server; tests
+// register callbacks on it to answer queries
+func openDryRunDB() (*gorm.DB, error) {
+	return gorm.Open(mysql.New(mysql.Config{DSN: "poc:poc@tcp(127.0.0.1:1)/poc?parseTime=true", SkipInitializeWithVersion: true}), &gorm.Config{
+		DryRun:                 true,
+		DisableAutomaticPing:   true,
+		SkipDefaultTransaction: true, // BEGIN would dial the (nonexistent) server
+		Logger:                 logger.Default.LogMode(logger.Silent),
+	})
+}
+
+// openFakeQueueDB returns a dry-run GORM handle whose statements are evaluated by the fake table
+func openFakeQueueDB(table *fakeQueueTable) (*gorm.DB, error) {
+	db, err := openDryRunDB()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := db.Callback().Query().After("gorm:query").Register("poc:fake_count", func(tx *gorm.DB) {
+		if count, ok := tx.Statement.Dest.(*int64); ok {
+			*count = int64(len(table.match(tx)))
+			tx.RowsAffected = 1 // Count only trusts Dest when a single row came back
+		}
+	}); err != nil {
+		return nil, err
+	}
+	if err := db.Callback().Update().After("gorm:update").Register("poc:fake_update", func(tx *gorm.DB) {
+		priority, _ := tx.Statement.Vars[0].(int)
+		matched := table.match(tx)
+		for _, i := range matched {
+			table.rows[i].Priority = priority
+		}
+		tx.RowsAffected = int64(len(matched))
+		table.updates = append(table.updates, tx.Dialector.Explain(tx.Statement.SQL.String(), tx.Statement.Vars...))
+	}); err != nil {
+		return nil, err
+	}
+	return db, nil
+}
+
+// match evaluates the statement's status IN (...) and detail_url LIKE conditions; any other
+// condition makes the statement unsupported and matches nothing.
+func (t *fakeQueueTable) match(tx *gorm.DB) []int {
+	sql := tx.Statement.SQL.String()
+	where := sql[strings.Index(sql, "WHERE")+len("WHERE"):]
+	vars := tx.Statement.Vars
+	if strings.HasPrefix(sql, "UPDATE") {
+		vars = vars[2:] // priority, updated_at
+	}
+
+	var statuses []string
+	var likePattern *regexp.Regexp
+	for _, cond := range strings.Split(where, " AND ") {
+		cond = strings.Trim(strings.TrimSpace(cond), "()")
+		switch {
+		case strings.HasPrefix(cond, "status IN"):
+			n := strings.Count(cond, "?")
+			for _, v := range vars[:n] {
+				statuses = append(statuses, v.(string))
+			}
+			vars = vars[n:]
+		case strings.HasPrefix(cond, "detail_url LIKE"):
+			likePattern = likeToRegexp(vars[0].(string))
+			vars = vars[1:]
+		default:
+			return nil
+		}
+	}
+
+	var matched []int
+	for i, row := range t.rows {
+		statusOK := false
+		for _, s := range statuses {
+			statusOK = statusOK || row.Status == s
+		}
+		if statusOK && (likePattern == nil || likePattern.MatchString(row.DetailURL)) {
+			matched = append(matched, i)
+		}
+	}
+	return matched
+}
+
+// likeToRegexp converts a MySQL LIKE pattern (backslash escapes) to an anchored regexp
+func likeToRegexp(pattern string) *regexp.Regexp {
+	var b strings.Builder
+	b.WriteString("^")
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; {
+		case c == '\\' && i+1 < len(pattern):
+			i++
+			b.WriteString(regexp.QuoteMeta(string(pattern[i])))
+		case c == '%':
+			b.WriteString(".*")
+		case c == '_':
+			b.WriteString(".")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	b.WriteString("$")
+	return regexp.MustCompile(b.String())
+}
+
+// Test 15: キューの一括優先度変更（オフライン）
+// URLプレフィックスに一致する pending/failed の行だけが更新され、上限超過時は force なしで拒否されることを確認する
+func testQueueReprioritize() TestResult {
+	result := TestResult{
+		TestName:  "キューの一括優先度変更",
+		Timestamp: time.Now(),
+	}
+
+	log.Println("\n[Test 15] キュー優先度一括変更テスト...")
+
+	const prefix = "https://realestate.yahoo.co.jp/rent/detail/ward_13113"
+	table := &fakeQueueTable{rows: []models.DetailScrapeQueue{
+		{ID: 1, Status: models.QueueStatusPending, DetailURL: prefix + "/001/"},
+		{ID: 2, Status: models.QueueStatusFailed, DetailURL: prefix + "/002/"},
+		{ID: 3, Status: models.QueueStatusProcessing, DetailURL: prefix + "/003/"},                                          // owned by a worker
+		{ID: 4, Status: models.QueueStatusDone, DetailURL: prefix + "/004/"},                                                // finished
+		{ID: 5, Status: models.QueueStatusPending, DetailURL: "https://realestate.yahoo.co.jp/rent/detail/ward_13104/005/"}, // other ward
+		{ID: 6, Status: models.QueueStatusPending, DetailURL: "https://realestate.yahoo.co.jp/rent/detail/wardX13113/006/"}, // '_' must not act as a wildcard
+		{ID: 7, Status: models.QueueStatusPending, DetailURL: prefix + "/007/"},
+	}}
+
+	db, err := openFakeQueueDB(table)
+	if err != nil {
+		result.Message = fmt.Sprintf("dry-run DB: %v", err)
+		log.Printf("  ❌ %s", result.Message)
+		return result
+	}
+	service := queue.NewService(db)
+	filter := queue.Filter{URLPrefix: prefix}
+
+	var problems []string
+
+	// Cap: 3 matches against a limit of 2 is refused without force and changes nothing
+	_, err = service.Reprioritize(filter, 10, 2, false)
+	var capErr *queue.CapExceededError
+	if !errors.As(err, &capErr) || capErr.Matched != 3 {
+		problems = append(problems, fmt.Sprintf("cap: expected CapExceededError(3), got %v", err))
+	}
+	if len(table.updates) != 0 {
+		problems = append(problems, "cap: UPDATE issued despite refusal")
+	}
+
+	// Force: the same request goes through in one UPDATE
+	res, err := service.Reprioritize(filter, 10, 2, true)
+	if err != nil {
+		problems = append(problems, fmt.Sprintf("force: %v", err))
+	} else if res.Matched != 3 || res.Affected != 3 {
+		problems = append(problems, fmt.Sprintf("force: matched=%d affected=%d, want 3/3", res.Matched, res.Affected))
+	}
+	if len(table.updates) != 1 {
+		problems = append(problems, fmt.Sprintf("expected exactly 1 UPDATE, got %d", len(table.updates)))
+	}
+
+	var changed []int64
+	for _, row := range table.rows {
+		if row.Priority == 10 {
+			changed = append(changed, row.ID)
+		}
+	}
+	sort.Slice(changed, func(i, j int) bool { return changed[i] < changed[j] })
+	if fmt.Sprint(changed) != "[1 2 7]" {
+		problems = append(problems, fmt.Sprintf("changed rows %v, want [1 2 7]", changed))
+	}
+
+	// Empty filters and non-queued statuses are rejected before touching the DB
+	if _, err := service.Reprioritize(queue.Filter{}, 10, 0, true); !errors.Is(err, queue.ErrInvalidFilter) {
+		problems = append(problems, fmt.Sprintf("empty filter: got %v", err))
+	}
+	if _, err := service.Reprioritize(queue.Filter{URLPrefix: prefix, Statuses: []string{models.QueueStatusDone}}, 10, 0, true); !errors.Is(err, queue.ErrInvalidFilter) {
+		problems = append(problems, fmt.Sprintf("done status: got %v", err))
+	}
+
+	result.Details = map[string]interface{}{
+		"changed":  changed,
+		"updates":  table.updates,
+		"problems": problems,
+	}
+	if len(problems) > 0 {
+		result.Message = fmt.Sprintf("一括優先度変更が不正: %v", problems)
+		log.Printf("  ❌ %v", problems)
+		return result
+	}
+
+	result.Success = true
+	result.Message = fmt.Sprintf("一致した%d件のみ更新、上限超過はforceなしで拒否", len(changed))
+	log.Printf("  ✅ %s", table.updates[0])
+	return result
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"real-estate-portal/internal/errtext"
+	"real-estate-portal/internal/ratelimit"
+	"real-estate-portal/internal/scheduler"
+	"real-estate-portal/internal/scraper"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Test 67: 429 の Retry-After（フィクスチャモードのみ）
+// 429 の Retry-After（秒数・HTTP-date）だけ次の試行を待つこと、上限を超える Retry-After は待たずに
+// ErrRateLimited（Retry-After 付き）を返すこと、開いたブレーカーは Retry-After が過ぎるまで半開にならないことを確認する
+func testRetryAfter() TestResult {
+	result := TestResult{
+		TestName:  "429 の Retry-After",
+		Timestamp: time.Now(),
+	}
+
+	log.Println("\n[Test 67] 429 の Retry-After テスト...")
+
+	// Each path answers 429 the first time it is requested, then 200
+	var mu sync.Mutex
+	seen := make(map[string]int)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		seen[r.URL.Path]++
+		first := seen[r.URL.Path] == 1
+		mu.Unlock()
+
+		switch {
+		case strings.Contains(r.URL.Path, "long"):
+			w.Header().Set("Retry-After", "3600")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		case strings.Contains(r.URL.Path, "always"):
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		case first && strings.Contains(r.URL.Path, "seconds"):
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		case first && strings.Contains(r.URL.Path, "date"):
+			// HTTP-dates have whole seconds: 2s ahead waits at least 1s
+			w.Header().Set("Retry-After", time.Now().Add(2*time.Second).UTC().Format(http.TimeFormat))
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		fmt.Fprint(w, `<html><head><title>ok</title></head><body><h1>ok</h1><table><tr><th>賃料</th><td>8.5万円</td></tr></table></body></html>`)
+	}))
+	defer server.Close()
+
+	newScraper := func(maxRetries int, maxRetryAfter time.Duration) (*scraper.Scraper, *scraper.CircuitBreaker) {
+		breaker := scraper.NewCircuitBreaker(2, 50*time.Millisecond)
+		s := scraper.NewScraperWithLimiter(scraper.ScraperConfig{
+			Timeout:       5 * time.Second,
+			MaxRetries:    maxRetries,
+			RetryDelay:    10 * time.Millisecond,
+			MaxTotal:      30 * time.Second,
+			MaxRetryAfter: maxRetryAfter,
+			BaseURL:       server.URL,
+			FixtureMode:   true,
+		}, ratelimit.NewYahooLimiter(1, 0, 0), breaker)
+		return s, breaker
+	}
+
+	var problems []string
+
+	// 1. Retry-After in seconds and as an HTTP-date: the retry waits for it (10ms backoff otherwise)
+	for _, name := range []string{"seconds", "date"} {
+		s, _ := newScraper(2, time.Minute)
+		start := time.Now()
+		_, err := s.ScrapeProperty(server.URL + "/rent/detail/" + name + "00/")
+		elapsed := time.Since(start)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", name, err))
+		}
+		if elapsed < 900*time.Millisecond {
+			problems = append(problems, fmt.Sprintf("%s: retried after %v (want >= 1s)", name, elapsed.Round(time.Millisecond)))
+		}
+	}
+
+	// 2. Past the ceiling: ErrRateLimited at once, carrying the Retry-After; the worker retries after it
+	s, breaker := newScraper(3, time.Minute)
+	start := time.Now()
+	_, err := s.ScrapeProperty(server.URL + "/rent/detail/long00/")
+	elapsed := time.Since(start)
+	if !errors.Is(err, scraper.ErrRateLimited) || scraper.RetryAfterOf(err) != time.Hour {
+		problems = append(problems, fmt.Sprintf("long: err=%v retry_after=%v (want ErrRateLimited, 1h)", err, scraper.RetryAfterOf(err)))
+	}
+	if code, _ := errtext.FromError(err); code != errtext.CodeRateLimited {
+		problems = append(problems, fmt.Sprintf("long: code=%s (want %s)", code, errtext.CodeRateLimited))
+	}
+	if scheduler.ClassifyScrapeFailure(err) != scheduler.FailureRetry {
+		problems = append(problems, fmt.Sprintf("long: failure=%s (want %s)", scheduler.ClassifyScrapeFailure(err), scheduler.FailureRetry))
+	}
+	if elapsed > 500*time.Millisecond {
+		problems = append(problems, fmt.Sprintf("long: took %v (want no wait)", elapsed.Round(time.Millisecond)))
+	}
+
+	// 3. Two 429s open the breaker: it stays open until the Retry-After, not the 50ms reset timeout
+	_, err = s.ScrapeProperty(server.URL + "/rent/detail/long01/")
+	if !errors.Is(err, scraper.ErrRateLimited) {
+		problems = append(problems, fmt.Sprintf("second 429: err=%v", err))
+	}
+	time.Sleep(100 * time.Millisecond)
+	if breaker.CanProceed() {
+		problems = append(problems, "breaker went half-open before the Retry-After")
+	} else if retryAt := breaker.RetryAt(); time.Until(retryAt) < 50*time.Minute {
+		problems = append(problems, fmt.Sprintf("breaker retry_at=%v (want ~1h ahead)", retryAt))
+	}
+
+	// 4. Still 429 after every retry: ErrRateLimited with the last Retry-After
+	s, _ = newScraper(1, time.Minute)
+	if _, err := s.ScrapeProperty(server.URL + "/rent/detail/always00/"); !errors.Is(err, scraper.ErrRateLimited) || scraper.RetryAfterOf(err) != time.Second {
+		problems = append(problems, fmt.Sprintf("always: err=%v retry_after=%v (want ErrRateLimited, 1s)", err, scraper.RetryAfterOf(err)))
+	}
+
+	result.Details = map[string]interface{}{
+		"problems": problems,
+	}
+	if len(problems) > 0 {
+		result.Message = fmt.Sprintf("Retry-After の扱いが不正: %v", problems)
+		log.Printf("  ❌ %v", problems)
+		return result
+	}
+
+	result.Success = true
+	result.Message = "429 の Retry-After だけ再試行を待ち、上限を超えると ErrRateLimited で打ち切り、ブレーカーも Retry-After まで開いたままであることを確認"
+	log.Printf("  ✅ %s", result.Message)
+	return result
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"real-estate-portal/internal/errtext"
+	"real-estate-portal/internal/scheduler"
+	"real-estate-portal/internal/scraper"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 検証用 robots.txt（Googlebot 向けグループは対象外、* グループのみ適用される）
+const pocRobotsTxt = `# test robots.txt
+User-agent: Googlebot
+Disallow: /
+
+User-agent: *
+Disallow: /rent/detail/
+Allow: /rent/detail/ok
+Disallow: /*sc_out=
+`
+
+// Test 33: robots.txt の遵守（フィクスチャモードのみ）
+// Disallow された一覧・詳細URLはリクエストせず ErrRobotsDisallowed になり、robots.txt は
+// ホストごとに1回だけ取得され、respect_robots 無効時と取得失敗時は permanent にならないことを確認する
+func testRobotsTxt() TestResult {
+	result := TestResult{
+		TestName:  "robots.txtの遵守",
+		Timestamp: time.Now(),
+	}
+
+	log.Println("\n[Test 33] robots.txt テスト...")
+
+	var mu sync.Mutex
+	hits := make(map[string]int)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		hits[r.URL.Path]++
+		mu.Unlock()
+		if r.URL.Path == "/robots.txt" {
+			fmt.Fprint(w, pocRobotsTxt)
+			return
+		}
+		fmt.Fprint(w, `<html><head><title>ok</title></head><body><h1>ok</h1></body></html>`)
+	}))
+	defer server.Close()
+	hitCount := func(path string) int {
+		mu.Lock()
+		defer mu.Unlock()
+		return hits[path]
+	}
+
+	newScraper := func(respect bool) *scraper.Scraper {
+		return scraper.NewScraperWithConfig(scraper.ScraperConfig{
+			Timeout:       5 * time.Second,
+			BaseURL:       server.URL,
+			FixtureMode:   true,
+			RespectRobots: respect,
+		})
+	}
+	s := newScraper(true)
+
+	var problems []string
+
+	// Disallowed detail: refused before any request, classified as a robots failure
+	_, err := s.ScrapeProperty(server.URL + "/rent/detail/blocked00/")
+	switch {
+	case !errors.Is(err, scraper.ErrRobotsDisallowed):
+		problems = append(problems, fmt.Sprintf("blocked detail: err=%v (want ErrRobotsDisallowed)", err))
+	case hitCount("/rent/detail/blocked00/") != 0:
+		problems = append(problems, "blocked detail: page was requested")
+	}
+	if got := scheduler.ClassifyScrapeFailure(err); got != scheduler.FailureRobots {
+		problems = append(problems, fmt.Sprintf("blocked detail: failure=%s (want %s)", got, scheduler.FailureRobots))
+	}
+	if code, _ := errtext.FromError(err); code != errtext.CodeRobots {
+		problems = append(problems, fmt.Sprintf("blocked detail: code=%s (want %s)", code, errtext.CodeRobots))
+	}
+
+	// Longer Allow wins over the Disallow prefix
+	if _, err := s.ScrapeProperty(server.URL + "/rent/detail/ok00allowed/"); errors.Is(err, scraper.ErrRobotsDisallowed) {
+		problems = append(problems, fmt.Sprintf("allowed detail refused: %v", err))
+	}
+	if hitCount("/rent/detail/ok00allowed/") == 0 {
+		problems = append(problems, "allowed detail: page was not requested")
+	}
+
+	// Wildcard rule on a list URL query
+	if _, err := s.ScrapeListPage(server.URL + "/rent/search/?pf=13&sc_out=1"); !errors.Is(err, scraper.ErrRobotsDisallowed) {
+		problems = append(problems, fmt.Sprintf("wildcard list: err=%v (want ErrRobotsDisallowed)", err))
+	}
+
+	// Cached: three checks, one robots.txt fetch
+	if n := hitCount("/robots.txt"); n != 1 {
+		problems = append(problems, fmt.Sprintf("robots.txt fetched %d times (want 1)", n))
+	}
+	var cachedRules int
+	for _, entry := range scraper.RobotsCacheSnapshot() {
+		if entry.Origin == server.URL {
+			cachedRules = len(entry.Rules)
+		}
+	}
+	if cachedRules != 3 {
+		problems = append(problems, fmt.Sprintf("cached rules=%d (want 3, Googlebot group skipped)", cachedRules))
+	}
+
+	// respect_robots: false fetches the disallowed page
+	if _, err := newScraper(false).ScrapeProperty(server.URL + "/rent/detail/blocked01/"); errors.Is(err, scraper.ErrRobotsDisallowed) {
+		problems = append(problems, fmt.Sprintf("respect_robots=false still refused: %v", err))
+	}
+	if hitCount("/rent/detail/blocked01/") == 0 {
+		problems = append(problems, "respect_robots=false: page was not requested")
+	}
+
+	// An unreachable robots.txt (5xx) is retryable, never permanent
+	down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprint(w, `<html><body>ok</body></html>`)
+	}))
+	defer down.Close()
+	_, err = scraper.NewScraperWithConfig(scraper.ScraperConfig{
+		Timeout: 5 * time.Second, BaseURL: down.URL, FixtureMode: true, RespectRobots: true,
+	}).ScrapeProperty(down.URL + "/rent/detail/ok01down/")
+	if err == nil || errors.Is(err, scraper.ErrRobotsDisallowed) || !strings.Contains(err.Error(), "robots.txt unavailable") {
+		problems = append(problems, fmt.Sprintf("robots.txt 503: err=%v (want retryable unavailable error)", err))
+	} else if got := scheduler.ClassifyScrapeFailure(err); got != scheduler.FailureRetry {
+		problems = append(problems, fmt.Sprintf("robots.txt 503: failure=%s (want %s)", got, scheduler.FailureRetry))
+	}
+
+	result.Details = map[string]interface{}{
+		"robots_fetches": hitCount("/robots.txt"),
+		"cached_rules":   cachedRules,
+		"problems":       problems,
+	}
+	if len(problems) > 0 {
+		result.Message = fmt.Sprintf("robots.txt の扱いが不正: %v", problems)
+		log.Printf("  ❌ %v", problems)
+		return result
+	}
+
+	result.Success = true
+	result.Message = "Disallow のURLは取得せず拒否、robots.txt はキャッシュから再利用、取得失敗は再試行扱い"
+	log.Printf("  ✅ %s", result.Message)
+	return result
+}
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"real-estate-portal/internal/models"
+	"real-estate-portal/internal/scheduler"
+	"real-estate-portal/internal/scraper"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// stationSource is a stub source whose detail scrape also returns stations
+type stationSource struct {
+	*stubSource
+	stations []models.PropertyStation
+}
+
+func (s *stationSource) ScrapeDetail(ctx context.Context, detailURL string, v scraper.Validators) (*scraper.DetailResult, error) {
+	result, err := s.stubSource.ScrapeDetail(ctx, detailURL, v)
+	result.Stations = s.stations
+	return result, err
+}
+
+// recordingSaver is a PropertySaver that keeps what it was given (or fails with err)
+type recordingSaver struct {
+	mu       sync.Mutex
+	err      error
+	saved    []*models.Property
+	stations [][]models.PropertyStation
+}
+
+func (s *recordingSaver) SavePropertyWithStationsAndImagesContext(_ context.Context, property *models.Property, stations []models.PropertyStation, _ []models.PropertyImage) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return s.err
+	}
+	s.saved = append(s.saved, property)
+	s.stations = append(s.stations, stations)
+	return nil
+}
+
+const savePathPropertyID = "save-path-01"
+
+// runSavePath queues one stub listing with two stations in a fresh SQLite database, lets a
+// worker with saver (nil: the default GormDB) process it, and returns the database and the
+// item as the worker left it. before runs on the database ahead of the worker.
+func runSavePath(t *testing.T, saver scheduler.PropertySaver, before func(db *gorm.DB)) (*gorm.DB, models.DetailScrapeQueue) {
+	t.Helper()
+	db := openSQLiteDB(t)
+	item := models.DetailScrapeQueue{Source: "stub", SourcePropertyID: "save01",
+		DetailURL: "https://stub.example/detail/save01/", Status: models.QueueStatusPending}
+	if err := db.Create(&item).Error; err != nil {
+		t.Fatalf("queue item: %v", err)
+	}
+	if before != nil {
+		before(db)
+	}
+
+	source := &stationSource{
+		stubSource: newStubSource(func(_ context.Context, url string) (*models.Property, error) {
+			return &models.Property{ID: savePathPropertyID, Source: "stub", SourcePropertyID: "save01", DetailURL: url,
+				Title: "保存経路テスト", Status: models.PropertyStatusActive}, nil
+		}),
+		stations: []models.PropertyStation{
+			{PropertyID: savePathPropertyID, StationName: "新宿", LineName: "JR山手線", WalkMinutes: 5, SortOrder: 1},
+			{PropertyID: savePathPropertyID, StationName: "代々木", LineName: "JR山手線", WalkMinutes: 9, SortOrder: 2},
+		},
+	}
+	w := scheduler.NewQueueWorkerWithSources(db, scraper.NewScraper(), scraper.NewRegistry(source))
+	w.SetPollInterval(10 * time.Millisecond)
+	w.SetHealthCheck(func(context.Context) bool { return true })
+	w.SetMinRefetchInterval(0)
+	w.SetPriorityAging(0) // the aged ORDER BY is MySQL's TIMESTAMPDIFF
+	if saver != nil {
+		w.SetPropertySaver(saver)
+	}
+	if err := w.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	for deadline := time.Now().Add(10 * time.Second); ; time.Sleep(10 * time.Millisecond) {
+		if err := db.First(&item, item.ID).Error; err != nil {
+			t.Fatalf("read queue item: %v", err)
+		}
+		if item.Status != models.QueueStatusPending && item.Status != models.QueueStatusProcessing {
+			break
+		}
+		if time.Now().After(deadline) {
+			w.Stop()
+			t.Fatalf("item not processed: %+v", item)
+		}
+	}
+	if err := w.Stop(); err != nil {
+		t.Fatalf("Stop: %v", err)
+	}
+	return db, item
+}
+
+// countRows returns the rows of model for the test listing
+func countRows(t *testing.T, db *gorm.DB, model interface{}) int64 {
+	t.Helper()
+	var n int64
+	if err := db.Model(model).Where("property_id = ?", savePathPropertyID).Count(&n).Error; err != nil {
+		t.Fatalf("count %T: %v", model, err)
+	}
+	return n
+}
+
+// The default saver writes the property, replaces its stations and takes a snapshot, and
+// the item is done
+func TestWorkerSavePath(t *testing.T) {
+	db, item := runSavePath(t, nil, func(db *gorm.DB) {
+		// A station left from an earlier scrape is replaced, not kept
+		stale := models.PropertyStation{PropertyID: savePathPropertyID, StationName: "旧駅", LineName: "旧線", SortOrder: 1}
+		if err := db.Create(&stale).Error; err != nil {
+			t.Fatalf("stale station: %v", err)
+		}
+	})
+
+	if item.Status != models.QueueStatusDone || item.CompletedAt == nil || item.Attempts != 1 || item.LastError != "" {
+		t.Errorf("item: %s, completed_at %v, %d attempts, %q (want done once)", item.Status, item.CompletedAt, item.Attempts, item.LastError)
+	}
+
+	var property models.Property
+	if err := db.First(&property, "id = ?", savePathPropertyID).Error; err != nil {
+		t.Fatalf("property not saved: %v", err)
+	}
+	if property.Title != "保存経路テスト" || property.Source != "stub" || property.SourcePropertyID != "save01" ||
+		property.DetailURL != "https://stub.example/detail/save01/" {
+		t.Errorf("property: %+v", property)
+	}
+
+	var stations []models.PropertyStation
+	if err := db.Where("property_id = ?", savePathPropertyID).Order("sort_order").Find(&stations).Error; err != nil {
+		t.Fatalf("read stations: %v", err)
+	}
+	var names []string
+	for _, s := range stations {
+		names = append(names, s.StationName+"/"+s.LineName)
+	}
+	if strings.Join(names, ",") != "新宿/JR山手線,代々木/JR山手線" {
+		t.Errorf("stations %v (want 新宿 and 代々木 only)", names)
+	}
+
+	var snapshots []models.PropertySnapshot
+	if err := db.Where("property_id = ?", savePathPropertyID).Find(&snapshots).Error; err != nil {
+		t.Fatalf("read snapshots: %v", err)
+	}
+	if len(snapshots) != 1 {
+		t.Fatalf("%d snapshots (want 1)", len(snapshots))
+	}
+}
+
+// An injected saver receives the property and its stations instead of the database
+func TestWorkerInjectedSaver(t *testing.T) {
+	saver := &recordingSaver{}
+	db, item := runSavePath(t, saver, nil)
+
+	if item.Status != models.QueueStatusDone {
+		t.Errorf("item: %s (want done)", item.Status)
+	}
+	if len(saver.saved) != 1 || saver.saved[0].ID != savePathPropertyID || len(saver.stations[0]) != 2 {
+		t.Fatalf("saver got %d properties (want 1 with 2 stations)", len(saver.saved))
+	}
+	if n := countRows(t, db, &models.PropertyStation{}); n != 0 {
+		t.Errorf("%d stations written to the database (want the saver to get them)", n)
+	}
+	var properties int64
+	db.Model(&models.Property{}).Count(&properties)
+	if properties != 0 {
+		t.Errorf("%d properties written to the database (want the saver to get them)", properties)
+	}
+}
+
+// A failed save leaves the item for a retry and takes no snapshot
+func TestWorkerSaveFailure(t *testing.T) {
+	db, item := runSavePath(t, &recordingSaver{err: errors.New("deadlock found")}, nil)
+
+	if item.Status != models.QueueStatusFailed || !strings.Contains(item.LastError, "database save error") || item.NextRetryAt == nil {
+		t.Errorf("item: %s, %q, retry %v (want failed with a retry)", item.Status, item.LastError, item.NextRetryAt)
+	}
+	if n := countRows(t, db, &models.PropertySnapshot{}); n != 0 {
+		t.Errorf("%d snapshots after the save failed (want 0)", n)
+	}
+}
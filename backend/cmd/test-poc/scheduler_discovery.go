@@ -0,0 +1,232 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"real-estate-portal/internal/config"
+	"real-estate-portal/internal/models"
+	"real-estate-portal/internal/scheduler"
+	"real-estate-portal/internal/scraper"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// listStubSource is a stubSource whose list pages are canned URL lists (or errors)
+type listStubSource struct {
+	*stubSource
+	lists map[string][]string
+}
+
+func (s *listStubSource) ScrapeList(_ context.Context, listURL string) ([]string, error) {
+	urls, ok := s.lists[listURL]
+	if !ok {
+		return nil, fmt.Errorf("%w: status code 429", scraper.ErrRateLimited)
+	}
+	return urls, nil
+}
+
+// Test 79: スケジューラーの一覧URL巡回（オフライン）
+// 定期実行（Scheduler.RunNow）が scheduler.list_urls の一覧ページを順に（間隔を空けて）取得し、
+// properties にもキューにも無い詳細URLだけを一覧ページを referer にして投入すること、既知の物件・
+// 投入済み・完了済みは数えるだけにすること、一覧URLごとの件数が scheduler_runs に保存され LastRun で
+// 読めること、取得に失敗した一覧URLや未対応のURLがエラーとして記録されることを確認する。実行中の2つ目の実行は
+// ErrRunInProgress で断られ、Stop で間隔待ちが打ち切られることも確認する
+func testSchedulerDiscovery() TestResult {
+	result := TestResult{
+		TestName:  "スケジューラーの一覧URL巡回",
+		Timestamp: time.Now(),
+	}
+
+	log.Println("\n[Test 79] スケジューラー一覧URL巡回テスト...")
+
+	const (
+		listA   = "https://stub.example/list/a/"
+		listB   = "https://stub.example/list/b/" // the site answers 429
+		listBad = "https://unknown.example/list/"
+		detail  = "https://stub.example/detail/"
+	)
+	source := &listStubSource{
+		stubSource: newStubSource(func(context.Context, string) (*models.Property, error) { return nil, nil }),
+		lists: map[string][]string{
+			listA: {detail + "new01/", detail + "known01/", detail + "queued01/", detail + "done01/", detail + "new02/?from=list"},
+		},
+	}
+
+	// Dry-run DB: known01 is a property, queued01 has a pending row, done01 a done row;
+	// queue inserts and the scheduler_runs row are kept
+	var created []models.DetailScrapeQueue
+	var run *models.SchedulerRun
+	db, err := openDryRunDB()
+	if err == nil {
+		err = db.Callback().Query().After("gorm:query").Register("poc:discovery_query", func(tx *gorm.DB) {
+			vars := whereVars(tx)
+			sql := tx.Statement.SQL.String()
+			switch dest := tx.Statement.Dest.(type) {
+			case *[]string: // Pluck("id") on properties
+				if len(vars) == 2 && vars[1] == "known01" {
+					*dest = []string{"prop-known01"}
+					tx.RowsAffected = 1
+				}
+			case *[]models.DetailScrapeQueue:
+				switch {
+				case strings.Contains(sql, "active_key = ?") && vars[0] == models.QueueActiveKey("stub", "queued01"):
+					*dest = []models.DetailScrapeQueue{{ID: 1, Status: models.QueueStatusPending}}
+					tx.RowsAffected = 1
+				case strings.Contains(sql, "ORDER BY id DESC") && len(vars) == 2 && vars[1] == "done01":
+					*dest = []models.DetailScrapeQueue{{Status: models.QueueStatusDone}}
+					tx.RowsAffected = 1
+				}
+			case *models.SchedulerRun:
+				if run == nil {
+					tx.AddError(gorm.ErrRecordNotFound)
+					return
+				}
+				*dest = *run
+				tx.RowsAffected = 1
+			}
+		})
+	}
+	if err == nil {
+		err = db.Callback().Create().After("gorm:create").Register("poc:discovery_create", func(tx *gorm.DB) {
+			switch dest := tx.Statement.Dest.(type) {
+			case *models.DetailScrapeQueue:
+				created = append(created, *dest)
+			case *models.SchedulerRun:
+				dest.ID = 1
+				saved := *dest
+				run = &saved
+			}
+			tx.RowsAffected = 1
+		})
+	}
+	if err == nil {
+		err = db.Callback().Update().After("gorm:update").Register("poc:discovery_update", func(tx *gorm.DB) {
+			if dest, ok := tx.Statement.Dest.(*models.SchedulerRun); ok {
+				saved := *dest
+				run = &saved
+				tx.RowsAffected = 1
+			}
+		})
+	}
+	if err != nil {
+		result.Message = fmt.Sprintf("dry-run DB: %v", err)
+		log.Printf("  ❌ %s", result.Message)
+		return result
+	}
+
+	aliveCheck := false
+	cfg := &config.Config{
+		Scraper:   config.ScraperConfig{AliveCheck: config.AliveCheckConfig{Enabled: &aliveCheck}},
+		Scheduler: config.SchedulerConfig{ListURLs: []string{listA, listB, listBad}, ListSpacingSeconds: 1},
+	}
+	sched := scheduler.NewSchedulerWithSources(db, cfg, scraper.NewRegistry(source))
+
+	var problems []string
+	start := time.Now()
+	if err := sched.RunNow(); err != nil {
+		problems = append(problems, fmt.Sprintf("RunNow: %v", err))
+	}
+	if elapsed := time.Since(start); elapsed < 2*time.Second {
+		problems = append(problems, fmt.Sprintf("three list URLs took %v (want >= 2s of spacing)", elapsed))
+	}
+
+	// 1. Only the two unknown listings were queued, normalized, with the list page as referer
+	var queued []string
+	for _, row := range created {
+		queued = append(queued, row.SourcePropertyID)
+		if row.RefererURL != listA || strings.Contains(row.DetailURL, "?") || row.Status != models.QueueStatusPending {
+			problems = append(problems, fmt.Sprintf("queued %s: url=%s referer=%s status=%s", row.SourcePropertyID, row.DetailURL, row.RefererURL, row.Status))
+		}
+	}
+	if strings.Join(queued, ",") != "new01,new02" {
+		problems = append(problems, fmt.Sprintf("queued %v (want new01, new02)", queued))
+	}
+
+	// 2. The run and its per-URL counts are persisted and read back
+	last, err := sched.LastRun()
+	switch {
+	case err != nil:
+		problems = append(problems, fmt.Sprintf("LastRun: %v", err))
+	case last == nil:
+		problems = append(problems, "LastRun: no run recorded")
+	default:
+		if last.Status != models.SchedulerRunDone || last.Trigger != "manual" || last.Discovered != 2 || last.FinishedAt == nil {
+			problems = append(problems, fmt.Sprintf("run: status=%s trigger=%s discovered=%d finished=%v",
+				last.Status, last.Trigger, last.Discovered, last.FinishedAt))
+		}
+		if len(last.Discoveries) != 3 {
+			problems = append(problems, fmt.Sprintf("%d list URL results (want 3): %+v", len(last.Discoveries), last.Discoveries))
+			break
+		}
+		if a := last.Discoveries[0]; a.Source != "stub" || a.Found != 5 || a.New != 2 || a.Existing != 1 || a.Skipped != 2 || a.Errors != 0 || a.Error != "" {
+			problems = append(problems, fmt.Sprintf("list a: %+v (want found 5, new 2, existing 1, skipped 2)", a))
+		}
+		if b := last.Discoveries[1]; b.Error == "" || b.Found != 0 || b.PagesVisited != 0 {
+			problems = append(problems, fmt.Sprintf("list b: %+v (want the 429 recorded)", b))
+		}
+		if bad := last.Discoveries[2]; bad.Error == "" || bad.Source != "" {
+			problems = append(problems, fmt.Sprintf("unknown host: %+v (want an error)", bad))
+		}
+	}
+
+	// 3. Nothing configured: the run still records, with no list URL results
+	run = nil
+	empty := scheduler.NewSchedulerWithSources(db, &config.Config{
+		Scraper: config.ScraperConfig{AliveCheck: config.AliveCheckConfig{Enabled: &aliveCheck}},
+	}, scraper.NewRegistry(source))
+	if err := empty.RunNow(); err != nil {
+		problems = append(problems, fmt.Sprintf("RunNow without list URLs: %v", err))
+	}
+	if last, err := empty.LastRun(); err != nil || last == nil || len(last.Discoveries) != 0 || last.Discovered != 0 {
+		problems = append(problems, fmt.Sprintf("run without list URLs: %+v (%v)", last, err))
+	}
+
+	// 4. A second run while one is crawling is refused, and Stop cuts the spacing wait short
+	slow := scheduler.NewSchedulerWithSources(db, &config.Config{
+		Scraper:   config.ScraperConfig{AliveCheck: config.AliveCheckConfig{Enabled: &aliveCheck}},
+		Scheduler: config.SchedulerConfig{ListURLs: []string{listA, listA}, ListSpacingSeconds: 30},
+	}, scraper.NewRegistry(source))
+	done := make(chan error, 1)
+	go func() { done <- slow.RunNow() }()
+	for deadline := time.Now().Add(2 * time.Second); !slow.RunInProgress() && time.Now().Before(deadline); time.Sleep(5 * time.Millisecond) {
+	}
+	if err := slow.RunNow(); !errors.Is(err, scheduler.ErrRunInProgress) {
+		problems = append(problems, fmt.Sprintf("second RunNow during a run: %v (want ErrRunInProgress)", err))
+	}
+	time.Sleep(100 * time.Millisecond)
+	stopped := time.Now()
+	slow.Stop()
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			problems = append(problems, fmt.Sprintf("run stopped mid-spacing returned %v (want context.Canceled)", err))
+		}
+		if took := time.Since(stopped); took > time.Second {
+			problems = append(problems, fmt.Sprintf("run took %v to stop", took))
+		}
+	case <-time.After(5 * time.Second):
+		problems = append(problems, "run still sleeping out the list spacing after Stop")
+	}
+	if slow.RunInProgress() {
+		problems = append(problems, "run still marked in progress after it ended")
+	}
+
+	result.Details = map[string]interface{}{
+		"queued":   queued,
+		"problems": problems,
+	}
+	if len(problems) > 0 {
+		result.Message = fmt.Sprintf("一覧URLの巡回が不正: %v", problems)
+		log.Printf("  ❌ %v", problems)
+		return result
+	}
+
+	result.Success = true
+	result.Message = "設定した一覧URLから未知の物件だけが投入され、一覧URLごとの件数が実行結果として保存されることを確認"
+	log.Printf("  ✅ %s", result.Message)
+	return result
+}
@@ -0,0 +1,222 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"real-estate-portal/internal/config"
+	"real-estate-portal/internal/models"
+	"real-estate-portal/internal/scheduler"
+	"real-estate-portal/internal/scraper"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Test 80: 複数の定期実行（オフライン）
+// scheduler.schedules の設定チェック（解釈できない at・範囲外の時刻・二重に走るエントリの拒否）と
+// HH:MM の cron 変換、各エントリが自分の一覧URLと max_enqueue で実行され、実行記録にエントリ名が残ること、
+// Schedules が登録済みの実行と次回時刻を返すこと（daily_run_time のみ・無効時も）を確認する
+func testSchedulerSchedules() TestResult {
+	result := TestResult{
+		TestName:  "複数の定期実行",
+		Timestamp: time.Now(),
+	}
+
+	log.Println("\n[Test 80] 複数定期実行テスト...")
+
+	const (
+		listA  = "https://stub.example/list/a/"
+		listB  = "https://stub.example/list/b/" // not crawled by the fast schedule
+		detail = "https://stub.example/detail/"
+	)
+	var problems []string
+
+	// 1. Config validation
+	validate := func(name string, schedules []config.ScheduleConfig, wantErr string) {
+		cfg := &config.Config{
+			CORS:      config.CORSConfig{AllowOrigins: []string{"https://shiboroom.example"}},
+			Scheduler: config.SchedulerConfig{ListURLs: []string{listA}, Schedules: schedules},
+		}
+		err := cfg.Validate()
+		switch {
+		case wantErr == "" && err != nil:
+			problems = append(problems, fmt.Sprintf("%s: unexpected error %v", name, err))
+		case wantErr != "" && (err == nil || !strings.Contains(err.Error(), wantErr)):
+			problems = append(problems, fmt.Sprintf("%s: err=%v (want %q)", name, err, wantErr))
+		}
+	}
+	validate("valid", []config.ScheduleConfig{
+		{Name: "morning", At: "06:00"},
+		{Name: "evening", At: "0 6,18 * * *", ListURLs: []string{listB}, MaxEnqueue: 50},
+		{At: "@weekly"},
+	}, "")
+	validate("bad cron", []config.ScheduleConfig{{Name: "x", At: "every day"}}, "not \"HH:MM\" or a valid cron expression")
+	validate("bad time", []config.ScheduleConfig{{Name: "x", At: "25:00"}}, "not a time of day")
+	validate("missing at", []config.ScheduleConfig{{Name: "x"}}, "at is required")
+	validate("negative limit", []config.ScheduleConfig{{Name: "x", At: "06:00", MaxEnqueue: -1}}, "must not be negative")
+	validate("bad list URL", []config.ScheduleConfig{{Name: "x", At: "06:00", ListURLs: []string{"stub.example/list/"}}}, "not an http(s) URL")
+	validate("duplicate name", []config.ScheduleConfig{{Name: "x", At: "06:00"}, {Name: "x", At: "18:00"}}, "used by another schedule")
+	validate("same run twice", []config.ScheduleConfig{{Name: "a", At: "06:00"}, {Name: "b", At: "0 6 * * *", ListURLs: []string{listA}}}, "would happen twice")
+
+	for at, want := range map[string]string{"06:30": "30 6 * * *", "0 6,18 * * *": "0 6,18 * * *", " @weekly ": "@weekly"} {
+		if got := (config.ScheduleConfig{At: at}).CronSpec(); got != want {
+			problems = append(problems, fmt.Sprintf("CronSpec(%q) = %q (want %q)", at, got, want))
+		}
+	}
+
+	// 2. A schedule fires with its own list URLs and enqueue limit.
+	// Dry-run DB: three active properties stream in, nothing is queued or done yet
+	source := &listStubSource{
+		stubSource: newStubSource(func(context.Context, string) (*models.Property, error) { return nil, nil }),
+		lists:      map[string][]string{listA: {detail + "new01/"}},
+	}
+	var mu sync.Mutex
+	var created []models.DetailScrapeQueue
+	var run *models.SchedulerRun
+	db, err := openDryRunDB()
+	if err == nil {
+		err = db.Callback().Query().After("gorm:query").Register("poc:schedules_query", func(tx *gorm.DB) {
+			mu.Lock()
+			defer mu.Unlock()
+			switch dest := tx.Statement.Dest.(type) {
+			case *[]models.Property:
+				*dest = nil
+				for i := 1; i <= 3; i++ {
+					spid := fmt.Sprintf("known%02d", i)
+					*dest = append(*dest, models.Property{ID: "prop-" + spid, Source: "stub", SourcePropertyID: spid, DetailURL: detail + spid + "/"})
+				}
+				tx.RowsAffected = int64(len(*dest))
+			case *models.DetailScrapeQueue: // no recently completed row
+				tx.AddError(gorm.ErrRecordNotFound)
+			case *models.SchedulerRun:
+				if run == nil {
+					tx.AddError(gorm.ErrRecordNotFound)
+					return
+				}
+				*dest = *run
+				tx.RowsAffected = 1
+			}
+		})
+	}
+	if err == nil {
+		err = db.Callback().Create().After("gorm:create").Register("poc:schedules_create", func(tx *gorm.DB) {
+			mu.Lock()
+			defer mu.Unlock()
+			switch dest := tx.Statement.Dest.(type) {
+			case *models.DetailScrapeQueue:
+				created = append(created, *dest)
+			case *models.SchedulerRun:
+				dest.ID = 1
+				saved := *dest
+				run = &saved
+			}
+			tx.RowsAffected = 1
+		})
+	}
+	if err == nil {
+		err = db.Callback().Update().After("gorm:update").Register("poc:schedules_update", func(tx *gorm.DB) {
+			mu.Lock()
+			defer mu.Unlock()
+			if dest, ok := tx.Statement.Dest.(*models.SchedulerRun); ok {
+				saved := *dest
+				run = &saved
+				tx.RowsAffected = 1
+			}
+		})
+	}
+	if err != nil {
+		result.Message = fmt.Sprintf("dry-run DB: %v", err)
+		log.Printf("  ❌ %s", result.Message)
+		return result
+	}
+
+	aliveCheck := false
+	newConfig := func(enabled bool, schedules []config.ScheduleConfig) *config.Config {
+		return &config.Config{
+			Scraper: config.ScraperConfig{
+				DailyRunEnabled: enabled,
+				DailyRunTime:    "02:00",
+				AliveCheck:      config.AliveCheckConfig{Enabled: &aliveCheck},
+			},
+			Scheduler: config.SchedulerConfig{ListURLs: []string{listA, listB}, Schedules: schedules},
+		}
+	}
+
+	sched := scheduler.NewSchedulerWithSources(db, newConfig(true, []config.ScheduleConfig{
+		{Name: "fast", At: "@every 1s", ListURLs: []string{listA}, MaxEnqueue: 1},
+		{Name: "slow", At: "@weekly"},
+	}), scraper.NewRegistry(source))
+	if err := sched.Start(); err != nil {
+		problems = append(problems, fmt.Sprintf("Start: %v", err))
+	}
+	jobs := sched.Schedules()
+	if len(jobs) != 2 || jobs[0].Name != "fast" || jobs[1].Name != "slow" || jobs[1].Cron != "@weekly" ||
+		jobs[0].NextRun == nil || time.Until(*jobs[0].NextRun) > time.Second || jobs[1].NextRun == nil {
+		problems = append(problems, fmt.Sprintf("Schedules: %+v", jobs))
+	}
+
+	var last *models.SchedulerRun
+	var queued []string
+	for deadline := time.Now().Add(3 * time.Second); time.Now().Before(deadline) && last == nil; time.Sleep(10 * time.Millisecond) {
+		mu.Lock()
+		if run != nil && run.FinishedAt != nil {
+			saved := *run
+			last = &saved
+			for _, row := range created {
+				queued = append(queued, row.SourcePropertyID)
+			}
+		}
+		mu.Unlock()
+	}
+	sched.Stop()
+
+	if last == nil {
+		problems = append(problems, "schedule \"fast\" did not run within 3s")
+	} else {
+		if last.Schedule != "fast" || last.Trigger != "scheduled" || last.Status != models.SchedulerRunDone ||
+			last.Discovered != 1 || last.Enqueued != 1 || strings.Contains(last.ListURLs, listB) {
+			problems = append(problems, fmt.Sprintf("run: schedule=%q trigger=%s status=%s discovered=%d enqueued=%d list_urls=%s",
+				last.Schedule, last.Trigger, last.Status, last.Discovered, last.Enqueued, last.ListURLs))
+		}
+		if strings.Join(queued, ",") != "new01,known01" {
+			problems = append(problems, fmt.Sprintf("queued %v (want new01 from list a, then one known listing)", queued))
+		}
+	}
+
+	// 3. Without schedules the daily_run_time job is the only one; disabled runs register none
+	legacy := scheduler.NewScheduler(db, newConfig(true, nil))
+	if err := legacy.Start(); err != nil {
+		problems = append(problems, fmt.Sprintf("legacy Start: %v", err))
+	}
+	if jobs := legacy.Schedules(); len(jobs) != 1 || jobs[0].Name != "" || jobs[0].Cron != "0 2 * * *" || jobs[0].NextRun == nil {
+		problems = append(problems, fmt.Sprintf("legacy Schedules: %+v", jobs))
+	}
+	legacy.Stop()
+
+	disabled := scheduler.NewScheduler(db, newConfig(false, []config.ScheduleConfig{{Name: "fast", At: "@every 1s"}}))
+	if err := disabled.Start(); err != nil {
+		problems = append(problems, fmt.Sprintf("disabled Start: %v", err))
+	}
+	if jobs := disabled.Schedules(); len(jobs) != 0 {
+		problems = append(problems, fmt.Sprintf("disabled Schedules: %+v (want none)", jobs))
+	}
+	disabled.Stop()
+
+	result.Details = map[string]interface{}{
+		"queued":   queued,
+		"problems": problems,
+	}
+	if len(problems) > 0 {
+		result.Message = fmt.Sprintf("複数の定期実行が不正: %v", problems)
+		log.Printf("  ❌ %v", problems)
+		return result
+	}
+
+	result.Success = true
+	result.Message = "不正な schedules が拒否され、各エントリが自分の一覧URLと投入上限で実行されて名前が記録されることを確認"
+	log.Printf("  ✅ %s", result.Message)
+	return result
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"real-estate-portal/internal/errtext"
+	"real-estate-portal/internal/scheduler"
+	"real-estate-portal/internal/scraper"
+	"strings"
+	"time"
+)
+
+// Test 32: スクレイプエラーの型による分類（フィクスチャモードのみ）
+// 404/429 の実応答と WAF・ブレーカー・パース失敗のエラーが errors.Is で分類され、
+// 本文に "404" を含むだけのエラーは再試行扱いになることを確認する
+func testScrapeErrorClassification() TestResult {
+	result := TestResult{
+		TestName:  "スクレイプエラーの型による分類",
+		Timestamp: time.Now(),
+	}
+
+	log.Println("\n[Test 32] スクレイプエラー分類テスト...")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.Contains(r.URL.Path, "err404"):
+			http.NotFound(w, r)
+		case strings.Contains(r.URL.Path, "err429"):
+			w.WriteHeader(http.StatusTooManyRequests)
+		default:
+			fmt.Fprint(w, `<html><head><title>ok</title></head><body><h1>ok</h1></body></html>`)
+		}
+	}))
+	defer server.Close()
+
+	s := scraper.NewScraperWithConfig(scraper.ScraperConfig{
+		Timeout:     5 * time.Second,
+		BaseURL:     server.URL,
+		FixtureMode: true,
+	})
+	scrapeErr := func(id string) error {
+		_, err := s.ScrapeProperty(server.URL + "/rent/detail/" + id + "/")
+		return err
+	}
+
+	cases := []struct {
+		name    string
+		err     error
+		target  error // nil = untyped
+		failure scheduler.ScrapeFailure
+		code    string
+	}{
+		{"http 404", scrapeErr("err404"), scraper.ErrNotFound, scheduler.FailurePermanent, errtext.CodeNotFound},
+		{"http 429", scrapeErr("err429"), scraper.ErrRateLimited, scheduler.FailureRetry, errtext.CodeRateLimited},
+		{"waf", fmt.Errorf("failed to fetch URL: %w", fmt.Errorf("%w: immediate retreat required", scraper.ErrWAFBlocked)),
+			scraper.ErrWAFBlocked, scheduler.FailureCooldown, errtext.CodeWAF},
+		{"circuit open", fmt.Errorf("failed to fetch URL: %w", fmt.Errorf("%w: suspected WAF block", scraper.ErrCircuitOpen)),
+			scraper.ErrCircuitOpen, scheduler.FailureCooldown, errtext.CodeCircuitOpen},
+		{"parse", fmt.Errorf("%w: failed to parse HTML: %w", scraper.ErrParse, errors.New("unexpected EOF")),
+			scraper.ErrParse, scheduler.FailureRetry, errtext.CodeParse},
+		// A title containing "404" is not a delisting
+		{"title 404", errors.New(`database save error: duplicate entry "メゾン404号室"`), nil, scheduler.FailureRetry, errtext.CodeDatabase},
+	}
+
+	// The 429 counted one breaker failure; a success resets the consecutive run for later tests
+	if err := scrapeErr("ok00reset"); err != nil {
+		log.Printf("  reset request failed: %v", err)
+	}
+
+	var problems []string
+	for _, tc := range cases {
+		if tc.err == nil {
+			problems = append(problems, fmt.Sprintf("%s: no error returned", tc.name))
+			continue
+		}
+		if tc.target != nil && !errors.Is(tc.err, tc.target) {
+			problems = append(problems, fmt.Sprintf("%s: %v is not %v", tc.name, tc.err, tc.target))
+		}
+		if got := scheduler.ClassifyScrapeFailure(tc.err); got != tc.failure {
+			problems = append(problems, fmt.Sprintf("%s: failure=%s (want %s)", tc.name, got, tc.failure))
+		}
+		if code, _ := errtext.FromError(tc.err); code != tc.code {
+			problems = append(problems, fmt.Sprintf("%s: code=%s (want %s)", tc.name, code, tc.code))
+		}
+	}
+
+	result.Details = map[string]interface{}{
+		"cases":    len(cases),
+		"problems": problems,
+	}
+	if len(problems) > 0 {
+		result.Message = fmt.Sprintf("エラー分類が不正: %v", problems)
+		log.Printf("  ❌ %v", problems)
+		return result
+	}
+
+	result.Success = true
+	result.Message = fmt.Sprintf("%d件のエラーを型で分類（\"404\"を含むだけのエラーは再試行）", len(cases))
+	log.Printf("  ✅ %s", result.Message)
+	return result
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"real-estate-portal/internal/scraper"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Test 63: セッションのウォームアップとCookieの永続化（オフライン）
+// 詳細ページの前に一度だけ賃貸トップを訪れてCookieを受け取り、詳細ページにCookieが付くこと、
+// Cookieファイルから復元したジャーで再起動後も送られること、無効時は訪問しないことを確認する
+func testSessionWarmup() TestResult {
+	result := TestResult{
+		TestName:  "セッションのウォームアップとCookie永続化",
+		Timestamp: time.Now(),
+	}
+
+	log.Println("\n[Test 63] セッションのウォームアップテスト...")
+
+	const detailID = "00000825067800000000000000000000000000000063"
+	var mu sync.Mutex
+	var landingVisits int
+	var detailCookies []string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/rent/", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		landingVisits++
+		mu.Unlock()
+		http.SetCookie(w, &http.Cookie{Name: "B", Value: "visitor63", Path: "/", MaxAge: 3600})
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		fmt.Fprint(w, `<html><body>賃貸トップ</body></html>`)
+	})
+	mux.HandleFunc("/rent/detail/"+detailID+"/", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		detailCookies = append(detailCookies, r.Header.Get("Cookie"))
+		mu.Unlock()
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		fmt.Fprint(w, `<html><head><title>セッション物件</title></head><body><h1>セッション物件</h1></body></html>`)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	dir, err := os.MkdirTemp("", "poc-session")
+	if err != nil {
+		result.Message = fmt.Sprintf("一時ディレクトリ作成失敗: %v", err)
+		return result
+	}
+	defer os.RemoveAll(dir)
+	cookieFile := filepath.Join(dir, "cookies.json")
+
+	newScraper := func(warmup bool, file string) *scraper.Scraper {
+		return scraper.NewScraperWithConfig(scraper.ScraperConfig{
+			Timeout:       5 * time.Second,
+			BaseURL:       server.URL,
+			FixtureMode:   true,
+			SessionWarmup: warmup,
+			CookieFile:    file,
+		})
+	}
+	detailURL := server.URL + "/rent/detail/" + detailID + "/"
+
+	var problems []string
+	scrape := func(step string, s *scraper.Scraper) {
+		if _, err := s.ScrapeProperty(detailURL); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: 取得失敗: %v", step, err))
+		}
+	}
+
+	// 1. Warm-up disabled: no landing page visit, the detail page goes out without cookies
+	scrape("no warmup", newScraper(false, ""))
+	// 2. Warm-up on: one landing visit, then the detail page carries its cookie (twice, one visit)
+	warm := newScraper(true, cookieFile)
+	scrape("warmup", warm)
+	if st := warm.LastStats(); st.WithCookies != 1 || st.Requests != 2 {
+		problems = append(problems, fmt.Sprintf("warmup stats: requests=%d with_cookies=%d (want 2 / 1)", st.Requests, st.WithCookies))
+	}
+	scrape("warm again", newScraper(true, cookieFile))
+
+	mu.Lock()
+	if landingVisits != 1 {
+		problems = append(problems, fmt.Sprintf("landing page visited %d times (want 1)", landingVisits))
+	}
+	want := []string{"", "B=visitor63", "B=visitor63"}
+	if strings.Join(detailCookies, ",") != strings.Join(want, ",") {
+		problems = append(problems, fmt.Sprintf("detail Cookie headers %q (want %q)", detailCookies, want))
+	}
+	mu.Unlock()
+
+	// 3. The jar was written to the cookie file with its expiry, without the file's temp copies
+	data, err := os.ReadFile(cookieFile)
+	if err != nil || !strings.Contains(string(data), `"value": "visitor63"`) || !strings.Contains(string(data), `"expires"`) {
+		problems = append(problems, fmt.Sprintf("cookie file: %s (err=%v)", data, err))
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+		problems = append(problems, fmt.Sprintf("cookie dir has %d entries (want 1)", len(entries)))
+	}
+
+	// 4. After a restart (a jar loaded from a copy of the file) the cookie is sent without a warm-up
+	restartFile := filepath.Join(dir, "restored.json")
+	if err := os.WriteFile(restartFile, data, 0o600); err != nil {
+		problems = append(problems, err.Error())
+	}
+	scrape("restored", newScraper(false, restartFile))
+	mu.Lock()
+	if n := len(detailCookies); n != 4 || detailCookies[n-1] != "B=visitor63" || landingVisits != 1 {
+		problems = append(problems, fmt.Sprintf("restored: Cookie headers %q, landing visits %d", detailCookies, landingVisits))
+	}
+	mu.Unlock()
+
+	result.Details = map[string]interface{}{
+		"landing_visits": landingVisits,
+		"detail_cookies": detailCookies,
+		"problems":       problems,
+	}
+	if len(problems) > 0 {
+		result.Message = fmt.Sprintf("セッションのウォームアップが不正: %v", problems)
+		log.Printf("  ❌ %v", problems)
+		return result
+	}
+
+	result.Success = true
+	result.Message = "賃貸トップを一度訪れてCookieを取得、詳細ページに付与し、Cookieファイルへ保存"
+	log.Printf("  ✅ %s", result.Message)
+	return result
+}
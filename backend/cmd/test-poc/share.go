@@ -0,0 +1,88 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"real-estate-portal/internal/models"
+	"real-estate-portal/internal/search"
+	"reflect"
+	"time"
+)
+
+// Test 14: 共有リンクの条件保存と有効期限（オフライン）
+// 保存した絞り込み条件が欠けずに復元され、名前がクエリパラメータと一致し、期限切れが判定されることを確認する
+func testShareLinkParams() TestResult {
+	result := TestResult{
+		TestName:  "共有リンクの条件保存と有効期限",
+		Timestamp: time.Now(),
+	}
+
+	log.Println("\n[Test 14] 共有リンクテスト...")
+
+	excludeFixedTerm := false
+	original := search.FilterParams{
+		Query:             "渋谷",
+		MinRent:           intp(80000),
+		MaxRent:           intp(150000),
+		FloorPlans:        []string{"1LDK", "2LDK"},
+		MaxWalkTime:       intp(10),
+		Lines:             []string{"JR山手線", "東京メトロ銀座線"},
+		MinBuildingFloors: intp(20),
+		FreeRent:          true,
+		NoBrokerageFee:    true,
+		FixedTermLease:    &excludeFixedTerm,
+		SortBy:            "rent:asc",
+		Limit:             50,
+	}
+
+	var problems []string
+
+	// Stored form round-trips without loss
+	stored, err := json.Marshal(original)
+	if err != nil {
+		problems = append(problems, fmt.Sprintf("marshal: %v", err))
+	}
+	var restored search.FilterParams
+	if err := json.Unmarshal(stored, &restored); err != nil {
+		problems = append(problems, fmt.Sprintf("unmarshal: %v", err))
+	}
+	if !reflect.DeepEqual(original, restored) {
+		problems = append(problems, fmt.Sprintf("round trip mismatch: %+v", restored))
+	}
+
+	// Keys are the /api/filter query parameter names (frontend hydrates from them)
+	var keys map[string]any
+	_ = json.Unmarshal(stored, &keys)
+	for _, name := range []string{"q", "min_rent", "max_rent", "floor_plan", "max_walk_time", "lines",
+		"min_building_floors", "free_rent", "no_brokerage_fee", "fixed_term_lease", "sort_by", "limit"} {
+		if _, ok := keys[name]; !ok {
+			problems = append(problems, fmt.Sprintf("missing key %q", name))
+		}
+	}
+	if !original.HasFilters() || (search.FilterParams{Limit: 20}).HasFilters() {
+		problems = append(problems, "HasFilters mismatch")
+	}
+
+	// Expiry is inclusive of the expiry instant
+	now := time.Now()
+	shared := models.SharedSearch{ExpiresAt: now.Add(time.Hour)}
+	if shared.IsExpired(now) || !shared.IsExpired(now.Add(time.Hour)) || !shared.IsExpired(now.Add(2*time.Hour)) {
+		problems = append(problems, "IsExpired boundary")
+	}
+
+	result.Details = map[string]interface{}{
+		"stored":   string(stored),
+		"problems": problems,
+	}
+	if len(problems) > 0 {
+		result.Message = fmt.Sprintf("共有条件が不正: %v", problems)
+		log.Printf("  ❌ %v", problems)
+		return result
+	}
+
+	result.Success = true
+	result.Message = fmt.Sprintf("条件%dバイトを欠落なく復元、期限判定OK", len(stored))
+	log.Printf("  ✅ %s", stored)
+	return result
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"real-estate-portal/internal/models"
+	"real-estate-portal/internal/snapshot"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Test 17: スナップショット差分とDetectChangesの一致（オフライン）
+// 2つの合成スナップショットの差分が、同じデータに対するDetectChangesの検出結果と一致することを確認する
+func testSnapshotDiff() TestResult {
+	result := TestResult{
+		TestName:  "スナップショット差分とDetectChangesの一致",
+		Timestamp: time.Now(),
+	}
+
+	log.Println("\n[Test 17] スナップショット差分テスト...")
+
+	floatp := func(v float64) *float64 { return &v }
+	from := models.PropertySnapshot{
+		PropertyID:  "prop-diff",
+		SnapshotAt:  time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		Rent:        intp(100000),
+		FloorPlan:   "1K",
+		Area:        floatp(25.5),
+		BuildingAge: intp(10),
+		ImageURL:    "https://example.com/a.jpg",
+		Status:      string(models.PropertyStatusActive),
+	}
+	property := models.Property{
+		ID:             "prop-diff",
+		Rent:           intp(95000),
+		FloorPlan:      "1DK",
+		Area:           floatp(26),
+		BuildingAge:    intp(11),
+		ImageURL:       "https://example.com/b.jpg",
+		Status:         models.PropertyStatusActive,
+		FreeRent:       true,
+		FreeRentMonths: intp(1),
+	}
+	to := models.PropertySnapshot{
+		PropertyID:     property.ID,
+		SnapshotAt:     time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC),
+		Rent:           property.Rent,
+		FloorPlan:      property.FloorPlan,
+		Area:           property.Area,
+		BuildingAge:    property.BuildingAge,
+		ImageURL:       property.ImageURL,
+		Status:         string(property.Status),
+		FreeRent:       property.FreeRent,
+		FreeRentMonths: property.FreeRentMonths,
+	}
+
+	var problems []string
+
+	// DetectChanges against a dry-run DB whose "last snapshot" is from
+	db, err := openDryRunDB()
+	if err == nil {
+		err = db.Callback().Query().After("gorm:query").Register("poc:last_snapshot", func(tx *gorm.DB) {
+			if dest, ok := tx.Statement.Dest.(*models.PropertySnapshot); ok {
+				*dest = from
+				tx.RowsAffected = 1
+			}
+		})
+	}
+	if err != nil {
+		result.Message = fmt.Sprintf("dry-run DB: %v", err)
+		log.Printf("  ❌ %s", result.Message)
+		return result
+	}
+	detected, err := snapshot.NewService(db).DetectChanges(&property)
+	if err != nil {
+		problems = append(problems, fmt.Sprintf("DetectChanges: %v", err))
+	}
+
+	fields := snapshot.DiffSnapshots(&from, &to)
+
+	render := func(changeType, oldValue, newValue string, magnitude *float64) string {
+		return fmt.Sprintf("%s %s->%s %s", changeType, oldValue, newValue, fmtFloatPtr(magnitude))
+	}
+	var want, got []string
+	for _, c := range detected {
+		want = append(want, render(c.ChangeType, c.OldValue, c.NewValue, c.ChangeMagnitude))
+	}
+	for _, f := range fields {
+		got = append(got, render(f.ChangeType, f.OldValue, f.NewValue, f.Magnitude))
+		if f.Field == "" {
+			problems = append(problems, fmt.Sprintf("%s has no field name", f.ChangeType))
+		}
+	}
+	if fmt.Sprint(want) != fmt.Sprint(got) {
+		problems = append(problems, fmt.Sprintf("diff %v != DetectChanges %v", got, want))
+	}
+	if len(fields) != 6 {
+		problems = append(problems, fmt.Sprintf("expected 6 changed fields, got %d", len(fields)))
+	}
+
+	// Numeric fields carry new - old
+	magnitudes := map[string]float64{"rent": -5000, "area": 0.5, "building_age": 1}
+	for _, f := range fields {
+		if wantMag, ok := magnitudes[f.Field]; ok && (f.Magnitude == nil || *f.Magnitude != wantMag) {
+			problems = append(problems, fmt.Sprintf("%s magnitude %s, want %v", f.Field, fmtFloatPtr(f.Magnitude), wantMag))
+		}
+	}
+
+	// Identical snapshots: no diff
+	if same := snapshot.DiffSnapshots(&to, &to); len(same) != 0 {
+		problems = append(problems, fmt.Sprintf("identical snapshots produced %d fields", len(same)))
+	}
+
+	result.Details = map[string]interface{}{
+		"fields":   got,
+		"problems": problems,
+	}
+	if len(problems) > 0 {
+		result.Message = fmt.Sprintf("差分がDetectChangesと不一致: %v", problems)
+		log.Printf("  ❌ %v", problems)
+		return result
+	}
+
+	result.Success = true
+	result.Message = fmt.Sprintf("%d項目の差分がDetectChangesと一致（数値項目は差分量付き）", len(fields))
+	log.Printf("  ✅ %v", got)
+	return result
+}
+
+func floatp(v float64) *float64 { return &v }
+
+func floatPtrEq(a, b *float64) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func fmtFloatPtr(v *float64) string {
+	if v == nil {
+		return "nil"
+	}
+	return fmt.Sprintf("%g", *v)
+}
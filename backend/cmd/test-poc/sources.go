@@ -0,0 +1,170 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"real-estate-portal/internal/scraper"
+	"strings"
+	"time"
+)
+
+// Test 38: SUUMO ソースとホスト別ディスパッチ（フィクスチャモードのみ）
+// suumo_list*.html / suumo_detail.html を SUUMO のパスで配信し、Registry がホストで Yahoo / SUUMO を振り分け、
+// 未対応ホストは ErrUnknownSource になり、SUUMO の一覧・詳細が取得でき、詳細枠が Yahoo と別であることを確認する
+func testSuumoSource(fixtureDir, yahooURL string) TestResult {
+	result := TestResult{
+		TestName:  "SUUMOソースとホスト別ディスパッチ",
+		Timestamp: time.Now(),
+	}
+
+	log.Println("\n[Test 38] SUUMOソーステスト...")
+
+	pages := make(map[string][]byte)
+	for _, name := range []string{"suumo_list.html", "suumo_list_page2.html", "suumo_detail.html"} {
+		data, err := os.ReadFile(filepath.Join(fixtureDir, name))
+		if err != nil {
+			result.Message = fmt.Sprintf("フィクスチャ読み込み失敗: %v", err)
+			return result
+		}
+		pages[name] = data
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var page []byte
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/jj/chintai/ichiran/") && r.URL.Query().Get("page") == "2":
+			page = pages["suumo_list_page2.html"]
+		case strings.HasPrefix(r.URL.Path, "/jj/chintai/ichiran/"):
+			page = pages["suumo_list.html"]
+		case r.URL.Path == "/chintai/jnc_000012345678/":
+			page = pages["suumo_detail.html"]
+		default:
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=UTF-8")
+		w.Write(page)
+	}))
+	defer server.Close()
+
+	// Keep SUUMO's limiters from sleeping 5-8s between fixture requests
+	scraper.ConfigureSourceLimits("suumo", 10*time.Millisecond, 0, 1000)
+
+	yahoo := newFixtureScraper(yahooURL[:strings.Index(yahooURL, "/rent/detail/")])
+	suumo := scraper.NewSuumoSource(scraper.ScraperConfig{Timeout: 10 * time.Second, BaseURL: server.URL})
+	registry := scraper.NewRegistry(yahoo, suumo)
+	ctx := context.Background()
+	var problems []string
+
+	// Dispatch by host
+	if src, err := registry.ForURL(yahooURL); err != nil || src.Name() != "yahoo" {
+		problems = append(problems, fmt.Sprintf("yahoo URL dispatched to %v (err=%v)", sourceName(src), err))
+	}
+	detailURL := server.URL + "/chintai/jnc_000012345678/?bc=100012345678"
+	if src, err := registry.ForURL(detailURL); err != nil || src.Name() != "suumo" {
+		problems = append(problems, fmt.Sprintf("suumo URL dispatched to %v (err=%v)", sourceName(src), err))
+	}
+	if _, err := registry.ForURL("https://www.example.com/rent/123/"); !errors.Is(err, scraper.ErrUnknownSource) {
+		problems = append(problems, fmt.Sprintf("unknown host: err=%v (want ErrUnknownSource)", err))
+	}
+
+	// List pages: tracking query strings deduplicated, 次へ followed, canonical detail URLs,
+	// and the 3件 / 2 pages reported on the first page
+	listPage, err := suumo.ScrapeListPagesWithMetaContext(ctx, server.URL+"/jj/chintai/ichiran/FR301FC001/?ta=13&sc=13113", 5)
+	if err != nil {
+		problems = append(problems, fmt.Sprintf("list: %v", err))
+	} else {
+		want := []string{
+			server.URL + "/chintai/jnc_000012345678/",
+			server.URL + "/chintai/jnc_000012345679/",
+			server.URL + "/chintai/bc_100098765432/",
+		}
+		if listPage.PagesVisited != 2 || strings.Join(listPage.URLs, " ") != strings.Join(want, " ") {
+			problems = append(problems, fmt.Sprintf("list: %d pages %v (want 2 pages %v)", listPage.PagesVisited, listPage.URLs, want))
+		}
+		if listPage.TotalOnSite != 3 || listPage.Pages != 2 {
+			problems = append(problems, fmt.Sprintf("list: total_on_site=%d pages=%d (want 3 / 2)", listPage.TotalOnSite, listPage.Pages))
+		}
+	}
+
+	// Detail page, paced by SUUMO's own budget
+	yahooUsage, suumoUsage := scraper.DetailLimiter.GetUsage(), suumo.Limiter().GetUsage()
+	if suumo.Limiter() == scraper.DetailLimiter {
+		problems = append(problems, "suumo shares the Yahoo detail limiter")
+	}
+	if err := suumo.Limiter().AcquireContext(ctx, "test-poc"); err != nil {
+		problems = append(problems, fmt.Sprintf("suumo limiter: %v", err))
+	}
+	detail, err := suumo.ScrapeDetail(ctx, detailURL, scraper.Validators{})
+	if err != nil {
+		problems = append(problems, fmt.Sprintf("detail: %v", err))
+	} else {
+		property, stations := detail.Property, detail.Stations
+		checks := []struct {
+			field string
+			ok    bool
+			got   string
+		}{
+			{"source", property.Source == "suumo", property.Source},
+			{"source_property_id", property.SourcePropertyID == "jnc_000012345678", property.SourcePropertyID},
+			{"title", property.Title == "パークサイド渋谷 3階", property.Title},
+			{"rent", intPtrEq(property.Rent, intp(85000)), fmtIntPtr(property.Rent)},
+			{"management_fee_yen", intPtrEq(property.ManagementFeeYen, intp(5000)), fmtIntPtr(property.ManagementFeeYen)},
+			{"key_money", property.KeyMoney == "なし", property.KeyMoney},
+			{"address", property.Address == "東京都渋谷区神南1", property.Address},
+			{"floor_plan", property.FloorPlan == "1K", property.FloorPlan},
+			{"area", property.Area != nil && *property.Area == 25.5, fmtFloatPtr(property.Area)},
+			{"building_age", intPtrEq(property.BuildingAge, intp(12)), fmtIntPtr(property.BuildingAge)},
+			{"unit_floor", intPtrEq(property.UnitFloor, intp(3)), fmtIntPtr(property.UnitFloor)},
+			{"building_floors", intPtrEq(property.BuildingFloors, intp(10)), fmtIntPtr(property.BuildingFloors)},
+			{"stations", len(stations) == 3 && stations[0].StationName == "渋谷" && stations[0].WalkMinutes == 7 &&
+				stations[2].WalkMinutes == 0, fmt.Sprintf("%d", len(stations))},
+			{"images", len(detail.Images) == 2, fmt.Sprintf("%d", len(detail.Images))},
+		}
+		for _, c := range checks {
+			if !c.ok {
+				problems = append(problems, fmt.Sprintf("%s=%s", c.field, c.got))
+			}
+		}
+	}
+	if got := scraper.DetailLimiter.GetUsage(); got != yahooUsage {
+		problems = append(problems, fmt.Sprintf("yahoo detail usage changed %d→%d", yahooUsage, got))
+	}
+	if got := suumo.Limiter().GetUsage(); got != suumoUsage+1 {
+		problems = append(problems, fmt.Sprintf("suumo detail usage %d→%d (want +1)", suumoUsage, got))
+	}
+
+	list := 0
+	if listPage != nil {
+		list = len(listPage.URLs)
+	}
+	result.Details = map[string]interface{}{
+		"hosts":    registry.Hosts(),
+		"list":     list,
+		"problems": problems,
+	}
+	if len(problems) > 0 {
+		result.Message = fmt.Sprintf("SUUMOソースが不正: %v", problems)
+		log.Printf("  ❌ %v", problems)
+		return result
+	}
+
+	result.Success = true
+	result.Message = "ホストで Yahoo / SUUMO を振り分け（未対応ホストは拒否）、SUUMO の一覧・詳細を別枠で取得"
+	log.Printf("  ✅ %s", result.Message)
+	return result
+}
+
+// sourceName is src's Name, or "<nil>" when dispatch failed
+func sourceName(src scraper.PropertySource) string {
+	if src == nil {
+		return "<nil>"
+	}
+	return src.Name()
+}
@@ -0,0 +1,13 @@
+package main
+
+import (
+	"real-estate-portal/internal/sqlitetest"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+// openSQLiteDB returns a fresh SQLite database with the app's schema (see sqlitetest.Open)
+func openSQLiteDB(t *testing.T) *gorm.DB {
+	return sqlitetest.Open(t)
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"real-estate-portal/internal/scraper"
+	"strings"
+	"time"
+)
+
+// Test 25: 交通（最寄り駅）の抽出（フィクスチャモードのみ）
+// 駅0件・1件・3件（うち1件はバス便）の詳細ページと交通行だけのページから、近い順の property_stations 用の行を取り出し、
+// 旧フィールド（station / walk_time）が最寄り駅と一致し、駅ブロックの無いページでは本文から拾わないことを確認する
+func testStationExtraction(s *scraper.Scraper, propertyURL string) TestResult {
+	result := TestResult{
+		TestName:  "交通（最寄り駅）の抽出",
+		Timestamp: time.Now(),
+	}
+
+	log.Println("\n[Test 25] 交通（最寄り駅）の抽出テスト...")
+
+	// Fixture detail pages share the list page's host; only the property ID differs
+	detailBase := propertyURL[:strings.Index(propertyURL, "/rent/detail/")+len("/rent/detail/")]
+
+	type want struct {
+		line, station string
+		walk          int
+		viaBus        bool
+		bus           int
+	}
+	cases := []struct {
+		id       string
+		stations []want
+	}{
+		{"station00none", nil},
+		{"station01one", []want{{"東京メトロ丸ノ内線", "西新宿", 4, false, 0}}},
+		{"station03three", []want{ // nearest first: 都庁前 is listed second on the page
+			{"都営大江戸線", "都庁前", 5, false, 0},
+			{"JR山手線", "新宿", 7, false, 0},
+			{"JR中央線", "中野", 0, true, 10}, // 徒歩2分 is from the bus stop
+		}},
+		{"station04table", []want{ // no summary list: the 交通 row, one station per <br> line
+			{"東京メトロ丸ノ内線", "西新宿", 4, false, 0},
+			{"JR山手線", "新宿", 12, false, 0},
+			{"都営バス", "中野", 0, true, 10},
+		}},
+		{"station05textonly", nil}, // no station block; "新宿駅 徒歩圏" in the text is not a station
+	}
+
+	var problems []string
+	for _, tc := range cases {
+		property, err := s.ScrapeProperty(detailBase + tc.id + "/")
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: 取得失敗: %v", tc.id, err))
+			continue
+		}
+
+		rows := s.GetLastStationsAsModels(property.ID)
+		access := s.GetLastStations()
+		if len(rows) != len(tc.stations) || len(access) != len(tc.stations) {
+			problems = append(problems, fmt.Sprintf("%s: got %d stations, want %d", tc.id, len(rows), len(tc.stations)))
+			continue
+		}
+		for i, w := range tc.stations {
+			got := rows[i]
+			if got.LineName != w.line || got.StationName != w.station || got.WalkMinutes != w.walk ||
+				got.SortOrder != i+1 || got.PropertyID != property.ID || access[i].ViaBus != w.viaBus || access[i].BusMinutes != w.bus {
+				problems = append(problems, fmt.Sprintf("%s[%d]: %s/%s walk=%d sort=%d bus=%v/%d分 (want %s/%s walk=%d sort=%d bus=%v/%d分)",
+					tc.id, i, got.LineName, got.StationName, got.WalkMinutes, got.SortOrder, access[i].ViaBus, access[i].BusMinutes,
+					w.line, w.station, w.walk, i+1, w.viaBus, w.bus))
+			}
+		}
+
+		// Legacy station/walk_time follow the nearest station when there is one
+		if len(tc.stations) > 0 {
+			first := tc.stations[0]
+			if property.Station != first.station || property.WalkTime == nil || *property.WalkTime != first.walk {
+				problems = append(problems, fmt.Sprintf("%s: legacy station=%q walk_time=%v", tc.id, property.Station, property.WalkTime))
+			}
+		}
+		if tc.id == "station05textonly" && (property.Station != "" || property.WalkTime != nil) {
+			problems = append(problems, fmt.Sprintf("%s: station=%q walk_time=%s from page text (want none)", tc.id, property.Station, fmtIntPtr(property.WalkTime)))
+		}
+	}
+
+	// Rows built for the save path keep the primary flag on the nearest station
+	if rows := s.GetLastStationsAsModels("p1"); len(rows) > 0 && !rows[0].IsPrimary() {
+		problems = append(problems, "first station is not primary")
+	}
+
+	result.Details = map[string]interface{}{
+		"cases":    len(cases),
+		"problems": problems,
+	}
+	if len(problems) > 0 {
+		result.Message = fmt.Sprintf("交通の抽出が不正: %v", problems)
+		log.Printf("  ❌ %v", problems)
+		return result
+	}
+
+	result.Success = true
+	result.Message = fmt.Sprintf("%d件の詳細ページから駅を正しく抽出（バス便はwalk_minutes=0）", len(cases))
+	log.Printf("  ✅ %s", result.Message)
+	return result
+}
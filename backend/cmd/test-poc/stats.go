@@ -0,0 +1,119 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"real-estate-portal/internal/scraper"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Test 48: スクレイプ統計（フィクスチャモードのみ）
+// ScrapeProperty / ScrapeListPage ごとにリクエスト数・リトライ・ステータス別件数・待ち時間・所要時間が
+// 集計され（呼び出しごとにリセット）、503→200 のリトライがバックオフ待ちとして計上されることを確認する
+func testScrapeStats(s *scraper.Scraper, propertyURL string) TestResult {
+	result := TestResult{
+		TestName:  "スクレイプ統計",
+		Timestamp: time.Now(),
+	}
+
+	log.Println("\n[Test 48] スクレイプ統計テスト...")
+
+	var problems []string
+	check := func(name string, st scraper.ScrapeStats, requests, retries int, statuses map[int]int) {
+		if st.Requests != requests || st.Retries != retries {
+			problems = append(problems, fmt.Sprintf("%s: requests=%d retries=%d (want %d / %d)", name, st.Requests, st.Retries, requests, retries))
+		}
+		for status, n := range statuses {
+			if st.StatusCounts[status] != n {
+				problems = append(problems, fmt.Sprintf("%s: status %d counted %d (want %d): %s", name, status, st.StatusCounts[status], n, st))
+			}
+		}
+		if st.WallTime <= 0 {
+			problems = append(problems, fmt.Sprintf("%s: wall time not recorded", name))
+		}
+	}
+
+	// 1. A detail page: one GET, reset per call
+	if _, err := s.ScrapeProperty(propertyURL); err != nil {
+		problems = append(problems, fmt.Sprintf("detail: 取得失敗: %v", err))
+	}
+	detail := s.LastStats()
+	check("detail", detail, 1, 0, map[int]int{200: 1})
+
+	// 2. A list page: its own counts, not added to the detail call's
+	listURL := propertyURL[:strings.Index(propertyURL, "/rent/detail/")] + "/list"
+	if _, err := s.ScrapeListPage(listURL); err != nil {
+		problems = append(problems, fmt.Sprintf("list: 取得失敗: %v", err))
+	}
+	check("list", s.LastStats(), 1, 0, map[int]int{200: 1})
+
+	// 3. 503 then 200: one retry, both statuses, backoff counted as pacing
+	var mu sync.Mutex
+	hits := map[string]int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		hits[r.URL.Path]++
+		n := hits[r.URL.Path]
+		mu.Unlock()
+		if n == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprint(w, `<html><head><title>ok</title></head><body><h1>ok</h1></body></html>`)
+	}))
+	defer server.Close()
+	retrying := scraper.NewScraperWithConfig(scraper.ScraperConfig{
+		Timeout:     5 * time.Second,
+		MaxRetries:  1,
+		RetryDelay:  5 * time.Millisecond,
+		BaseURL:     server.URL,
+		FixtureMode: true,
+	})
+	if _, err := retrying.ScrapeProperty(server.URL + "/rent/detail/stats00retry/"); err != nil {
+		problems = append(problems, fmt.Sprintf("retry: 取得失敗: %v", err))
+	}
+	retried := retrying.LastStats()
+	check("retry", retried, 2, 1, map[int]int{503: 1, 200: 1})
+	// 5ms backoff before the retry plus 20ms server-error backoff
+	if retried.PacingWait < 25*time.Millisecond {
+		problems = append(problems, fmt.Sprintf("retry: pacing wait %v (want >= 25ms)", retried.PacingWait))
+	}
+
+	// 4. Batch totals and the API form
+	var total scraper.ScrapeStats
+	total.Add(detail)
+	total.Add(retried)
+	if total.Requests != 3 || total.StatusCounts[200] != 2 || total.StatusCounts[503] != 1 {
+		problems = append(problems, fmt.Sprintf("batch total: %s", total))
+	}
+	data, err := json.Marshal(retried)
+	if err != nil {
+		problems = append(problems, fmt.Sprintf("json: %v", err))
+	}
+	for _, want := range []string{`"requests":2`, `"retries":1`, `"200":1`, `"503":1`, `"limiter_wait_ms":`, `"pacing_wait_ms":`, `"wall_time_ms":`} {
+		if !strings.Contains(string(data), want) {
+			problems = append(problems, fmt.Sprintf("json: %s missing %s", data, want))
+		}
+	}
+
+	result.Details = map[string]interface{}{
+		"detail":   detail.String(),
+		"retry":    retried.String(),
+		"problems": problems,
+	}
+	if len(problems) > 0 {
+		result.Message = fmt.Sprintf("スクレイプ統計が不正: %v", problems)
+		log.Printf("  ❌ %v", problems)
+		return result
+	}
+
+	result.Success = true
+	result.Message = fmt.Sprintf("呼び出しごとの統計を確認（retry: %s）", retried)
+	log.Printf("  ✅ %s", result.Message)
+	return result
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"real-estate-portal/internal/errtext"
+	"real-estate-portal/internal/scraper"
+	"strings"
+	"time"
+)
+
+// Test 49: 試行タイムアウトとリトライ予算（フィクスチャモードのみ）
+// 応答しないサーバーでは各試行が AttemptTimeout で打ち切られ、リトライ全体が MaxTotal 内に収まること、
+// 予算を超えるバックオフ（503）は待たずに ErrTimeout を返すことを確認する
+func testRetryBudget() TestResult {
+	result := TestResult{
+		TestName:  "試行タイムアウトとリトライ予算",
+		Timestamp: time.Now(),
+	}
+
+	log.Println("\n[Test 49] 試行タイムアウトとリトライ予算テスト...")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.Contains(r.URL.Path, "slow"):
+			// Never answers in time; returns as soon as the client gives up
+			select {
+			case <-r.Context().Done():
+			case <-time.After(5 * time.Second):
+			}
+		case strings.Contains(r.URL.Path, "busy"):
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			fmt.Fprint(w, `<html><head><title>ok</title></head><body><h1>ok</h1></body></html>`)
+		}
+	}))
+	defer server.Close()
+
+	newScraper := func(attemptTimeout, retryDelay, maxTotal time.Duration) *scraper.Scraper {
+		return scraper.NewScraperWithConfig(scraper.ScraperConfig{
+			Timeout:        30 * time.Second,
+			AttemptTimeout: attemptTimeout,
+			MaxRetries:     5,
+			RetryDelay:     retryDelay,
+			MaxTotal:       maxTotal,
+			BaseURL:        server.URL,
+			FixtureMode:    true,
+		})
+	}
+
+	var problems []string
+	checkTimeout := func(name string, err error, elapsed, bound time.Duration) {
+		if !errors.Is(err, scraper.ErrTimeout) {
+			problems = append(problems, fmt.Sprintf("%s: %v is not ErrTimeout", name, err))
+		} else if code, _ := errtext.FromError(err); code != errtext.CodeTimeout {
+			problems = append(problems, fmt.Sprintf("%s: code %q (want %q)", name, code, errtext.CodeTimeout))
+		}
+		if elapsed > bound {
+			problems = append(problems, fmt.Sprintf("%s: took %v (want <= %v)", name, elapsed, bound))
+		}
+	}
+
+	// 1. Slow server: 100ms attempts, 20/40ms backoff, cut at the 300ms budget (3 attempts,
+	// not 6 × 30s with the client timeout alone)
+	slow := newScraper(100*time.Millisecond, 20*time.Millisecond, 300*time.Millisecond)
+	start := time.Now()
+	_, err := slow.ScrapeProperty(server.URL + "/rent/detail/slow00/")
+	slowElapsed := time.Since(start)
+	checkTimeout("slow", err, slowElapsed, 600*time.Millisecond)
+	if st := slow.LastStats(); st.Requests != 3 {
+		problems = append(problems, fmt.Sprintf("slow: %d attempts (want 3): %s", st.Requests, st))
+	}
+
+	// 2. 503 with a 4s server backoff against a 500ms budget: fails at once instead of sleeping
+	busy := newScraper(0, time.Second, 500*time.Millisecond)
+	start = time.Now()
+	_, err = busy.ScrapeProperty(server.URL + "/rent/detail/busy00/")
+	busyElapsed := time.Since(start)
+	checkTimeout("busy", err, busyElapsed, 500*time.Millisecond)
+	if err != nil && !strings.Contains(err.Error(), "status 503") {
+		problems = append(problems, fmt.Sprintf("busy: last failure missing from %q", err.Error()))
+	}
+
+	// The timeouts counted breaker failures; a success resets the consecutive run for later tests
+	if _, err := slow.ScrapeProperty(server.URL + "/rent/detail/ok00reset/"); err != nil {
+		problems = append(problems, fmt.Sprintf("reset: 取得失敗: %v", err))
+	}
+
+	result.Details = map[string]interface{}{
+		"slow_elapsed_ms": slowElapsed.Milliseconds(),
+		"busy_elapsed_ms": busyElapsed.Milliseconds(),
+		"problems":        problems,
+	}
+	if len(problems) > 0 {
+		result.Message = fmt.Sprintf("リトライ予算が不正: %v", problems)
+		log.Printf("  ❌ %v", problems)
+		return result
+	}
+
+	result.Success = true
+	result.Message = fmt.Sprintf("リトライ全体が予算内で打ち切られることを確認（slow: %v, 503: %v）",
+		slowElapsed.Round(time.Millisecond), busyElapsed.Round(time.Millisecond))
+	log.Printf("  ✅ %s", result.Message)
+	return result
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"real-estate-portal/internal/scraper"
+	"real-estate-portal/internal/tracing"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// Test 7: トレースのスパン構成（フィクスチャモードのみ）
+// インメモリのエクスポーターで1件のスクレイプを記録し、期待どおりの親子関係になっていることを確認する
+func testScrapeSpanTree(s *scraper.Scraper, propertyURL string) TestResult {
+	result := TestResult{
+		TestName:  "トレースのスパン構成",
+		Timestamp: time.Now(),
+	}
+
+	log.Println("\n[Test 7] トレースのスパン構成テスト...")
+
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	defer otel.SetTracerProvider(prev)
+
+	ctx, root := tracing.Start(context.Background(), "test-poc.scrape")
+	_, err := s.ScrapePropertyContext(ctx, propertyURL)
+	root.End()
+	if err != nil {
+		result.Message = fmt.Sprintf("物件詳細の取得失敗: %v", err)
+		return result
+	}
+
+	spans := exporter.GetSpans()
+	byName := map[string]tracetest.SpanStub{}
+	for _, span := range spans {
+		byName[span.Name] = span
+	}
+
+	// child -> expected parent
+	expected := [][2]string{
+		{"scraper.ScrapeProperty", "test-poc.scrape"},
+		{"scraper.doRequestWithRetry", "scraper.ScrapeProperty"},
+		{"scraper.ParsePropertyHTML", "scraper.ScrapeProperty"},
+	}
+
+	var problems []string
+	for _, pair := range expected {
+		child, ok := byName[pair[0]]
+		if !ok {
+			problems = append(problems, pair[0]+" missing")
+			continue
+		}
+		parent, ok := byName[pair[1]]
+		if !ok || child.Parent.SpanID() != parent.SpanContext.SpanID() {
+			problems = append(problems, pair[0]+" not under "+pair[1])
+		}
+	}
+
+	if fetch, ok := byName["scraper.doRequestWithRetry"]; ok {
+		attrs := map[string]int64{}
+		for _, kv := range fetch.Attributes {
+			attrs[string(kv.Key)] = kv.Value.AsInt64()
+		}
+		if attrs["http.status_code"] != 200 {
+			problems = append(problems, fmt.Sprintf("http.status_code=%d", attrs["http.status_code"]))
+		}
+		if attrs["retry.count"] != 0 {
+			problems = append(problems, fmt.Sprintf("retry.count=%d", attrs["retry.count"]))
+		}
+	}
+
+	names := make([]string, 0, len(spans))
+	for _, span := range spans {
+		names = append(names, span.Name)
+	}
+	result.Details = map[string]interface{}{
+		"spans":    names,
+		"problems": problems,
+	}
+	if len(problems) > 0 {
+		result.Message = fmt.Sprintf("スパン構成が不正: %s", strings.Join(problems, ", "))
+		log.Printf("  ❌ %v", problems)
+		return result
+	}
+
+	result.Success = true
+	result.Message = fmt.Sprintf("%d スパンを期待どおりの親子関係で記録", len(spans))
+	log.Printf("  ✅ スパン構成 OK: %v", names)
+	return result
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"real-estate-portal/internal/config"
+	"real-estate-portal/internal/scraper"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 検証用の User-Agent（設定ファイルの user_agents と同じ形で渡す）
+var pocUserAgents = []string{"PocAgent/1.0 (pool-a)", "PocAgent/1.0 (pool-b)"}
+
+// Test 31: 設定した User-Agent の送信（フィクスチャモードのみ）
+// user_agents の候補から毎回選ばれ、一覧・詳細・画像確認の各リクエストで実際に送られることを確認する
+func testUserAgentPool() TestResult {
+	result := TestResult{
+		TestName:  "設定したUser-Agentの送信",
+		Timestamp: time.Now(),
+	}
+
+	log.Println("\n[Test 31] User-Agent プールテスト...")
+
+	var problems []string
+
+	// user_agents in the YAML becomes the pool; user_agent alone is a pool of one
+	dir, err := os.MkdirTemp("", "poc-ua")
+	if err != nil {
+		result.Message = fmt.Sprintf("一時ディレクトリ作成失敗: %v", err)
+		return result
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "config.yaml")
+	yamlText := fmt.Sprintf("user_agent: \"PocAgent/0.9\"\nuser_agents:\n  - %q\n  - %q\n", pocUserAgents[0], pocUserAgents[1])
+	if err := os.WriteFile(path, []byte(yamlText), 0o600); err != nil {
+		result.Message = fmt.Sprintf("設定ファイル書き込み失敗: %v", err)
+		return result
+	}
+	cfg, err := config.LoadConfig(path)
+	if err != nil {
+		result.Message = fmt.Sprintf("設定読み込み失敗: %v", err)
+		return result
+	}
+	if pool := cfg.UserAgentPool(); !slices.Equal(pool, pocUserAgents) {
+		problems = append(problems, fmt.Sprintf("UserAgentPool=%v", pool))
+	}
+	if pool := (&config.Config{UserAgent: "PocAgent/0.9"}).UserAgentPool(); !slices.Equal(pool, []string{"PocAgent/0.9"}) {
+		problems = append(problems, fmt.Sprintf("user_agent only: UserAgentPool=%v", pool))
+	}
+
+	// Record the User-Agent of every request the scraper sends, keyed by method + path
+	var mu sync.Mutex
+	sent := map[string][]string{}
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		sent[r.Method+" "+r.URL.Path] = append(sent[r.Method+" "+r.URL.Path], r.UserAgent())
+		mu.Unlock()
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		if strings.HasPrefix(r.URL.Path, "/rent/detail/") {
+			// No __SERVER_SIDE_CONTEXT__ images, so og:image is verified with a HEAD request
+			fmt.Fprintf(w, `<html><head><title>UA確認</title><meta property="og:image" content="%s/img/ua.jpg"></head><body><h1>UA確認</h1></body></html>`, server.URL)
+			return
+		}
+		fmt.Fprint(w, `<html><body></body></html>`)
+	}))
+	defer server.Close()
+
+	s := scraper.NewScraperWithConfig(scraper.ScraperConfig{
+		Timeout:      5 * time.Second,
+		BaseURL:      server.URL,
+		FixtureMode:  true,
+		UserAgents:   cfg.UserAgentPool(),
+		VerifyImages: true,
+	})
+	const listRequests = 12
+	for i := 0; i < listRequests; i++ {
+		if _, err := s.ScrapeListPage(server.URL + "/list/"); err != nil {
+			problems = append(problems, fmt.Sprintf("list: %v", err))
+			break
+		}
+	}
+	if _, err := s.ScrapeProperty(server.URL + "/rent/detail/ua00pool/"); err != nil {
+		problems = append(problems, fmt.Sprintf("detail: %v", err))
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	seen := map[string]bool{}
+	for key, agents := range sent {
+		for _, ua := range agents {
+			seen[ua] = true
+			if !slices.Contains(pocUserAgents, ua) {
+				problems = append(problems, fmt.Sprintf("%s sent %q", key, ua))
+			}
+		}
+	}
+	for _, prefix := range []string{"GET /list/", "GET /rent/detail/ua00pool", "HEAD /img/ua.jpg"} {
+		found := false
+		for key := range sent {
+			found = found || strings.HasPrefix(key, prefix)
+		}
+		if !found {
+			problems = append(problems, fmt.Sprintf("no %s request (sent: %v)", prefix, sent))
+		}
+	}
+	// Picked per request: 12 list requests using only one of two entries is ~1 in 2000
+	if len(seen) != len(pocUserAgents) {
+		problems = append(problems, fmt.Sprintf("only %d of %d pool entries used", len(seen), len(pocUserAgents)))
+	}
+
+	// A source profile pool (sources.<name>.user_agents) wins over the global pool
+	profiled := scraper.NewScraperWithConfig(scraper.ScraperConfig{
+		UserAgents: pocUserAgents,
+		Profile:    &scraper.HeaderProfile{UserAgents: []string{"PocAgent/2.0 (source)"}},
+	})
+	if ua := profiled.UserAgent(); ua != "PocAgent/2.0 (source)" {
+		problems = append(problems, fmt.Sprintf("profile pool ignored: %q", ua))
+	}
+
+	result.Details = map[string]interface{}{
+		"requests": len(sent),
+		"seen":     len(seen),
+		"problems": problems,
+	}
+	if len(problems) > 0 {
+		result.Message = fmt.Sprintf("User-Agentが設定どおりでない: %v", problems)
+		log.Printf("  ❌ %v", problems)
+		return result
+	}
+
+	result.Success = true
+	result.Message = fmt.Sprintf("一覧・詳細・画像確認のすべてで設定したUser-Agentを送信（%d種類を使用）", len(seen))
+	log.Printf("  ✅ %s", result.Message)
+	return result
+}
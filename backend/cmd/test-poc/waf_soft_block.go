@@ -0,0 +1,98 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"real-estate-portal/internal/errtext"
+	"real-estate-portal/internal/ratelimit"
+	"real-estate-portal/internal/scheduler"
+	"real-estate-portal/internal/scraper"
+	"strings"
+	"time"
+)
+
+// Test 68: 200 で返る WAF ブロックページ（フィクスチャモードのみ）
+// 「ご覧になろうとしているページは現在表示できません」が 200 で返っても ErrWAFBlocked になり（物件として
+// パースしない・再試行しない）、2回続くとサーキットブレーカーが開くことを確認する
+func testSoftWAFBlock(propertyURL, fixtureDir string) TestResult {
+	result := TestResult{
+		TestName:  "200 で返る WAF ブロックページ",
+		Timestamp: time.Now(),
+	}
+
+	log.Println("\n[Test 68] 200 で返る WAF ブロックページテスト...")
+
+	baseURL := propertyURL[:strings.Index(propertyURL, "/rent/detail/")]
+	blockURL := baseURL + "/rent/detail/waf00block/"
+
+	// Own breaker (threshold 5, 1h reset) so the shared Yahoo one used by other tests stays closed
+	breaker := scraper.NewCircuitBreaker(5, time.Hour)
+	s := scraper.NewScraperWithLimiter(scraper.ScraperConfig{
+		Timeout:     10 * time.Second,
+		MaxRetries:  2,
+		RetryDelay:  10 * time.Millisecond,
+		BaseURL:     baseURL,
+		FixtureMode: true,
+	}, ratelimit.NewYahooLimiter(1, 0, 0), breaker)
+
+	var problems []string
+
+	// 1. The shared classifier: the block page and nothing else
+	block, err := os.ReadFile(filepath.Join(fixtureDir, "detail_waf00block.html"))
+	if err != nil {
+		problems = append(problems, fmt.Sprintf("fixture: %v", err))
+	} else if !scraper.IsWAFBlockPage(string(block)) {
+		problems = append(problems, "IsWAFBlockPage misses the block page")
+	}
+	if detail, err := os.ReadFile(filepath.Join(fixtureDir, "detail.html")); err == nil && scraper.IsWAFBlockPage(string(detail)) {
+		problems = append(problems, "IsWAFBlockPage flags an ordinary detail page")
+	}
+
+	// 2. First 200 block page: ErrWAFBlocked after one request, breaker still closed
+	_, err = s.ScrapeProperty(blockURL)
+	if !errors.Is(err, scraper.ErrWAFBlocked) {
+		problems = append(problems, fmt.Sprintf("first block: err=%v (want ErrWAFBlocked)", err))
+	}
+	if code, _ := errtext.FromError(err); code != errtext.CodeWAF {
+		problems = append(problems, fmt.Sprintf("first block: code=%s (want %s)", code, errtext.CodeWAF))
+	}
+	if failure := scheduler.ClassifyScrapeFailure(err); failure != scheduler.FailureCooldown {
+		problems = append(problems, fmt.Sprintf("first block: failure=%s (want %s)", failure, scheduler.FailureCooldown))
+	}
+	if st := s.LastStats(); st.Requests != 1 {
+		problems = append(problems, fmt.Sprintf("first block: %d requests (want 1, no retry)", st.Requests))
+	}
+	if open, _, _ := breaker.GetStatus(); open {
+		problems = append(problems, "breaker opened after one block page")
+	}
+
+	// 3. Second one (through the light refresh this time): the breaker opens
+	if _, err := s.ScrapePropertyLight(blockURL); !errors.Is(err, scraper.ErrWAFBlocked) {
+		problems = append(problems, fmt.Sprintf("second block: err=%v (want ErrWAFBlocked)", err))
+	}
+	if open, failures, _ := breaker.GetStatus(); !open {
+		problems = append(problems, fmt.Sprintf("breaker still closed after two 200 block pages (%d failures)", failures))
+	}
+
+	// 4. Open breaker: even an ordinary page is refused without a request
+	if _, err := s.ScrapeProperty(propertyURL); !errors.Is(err, scraper.ErrCircuitOpen) {
+		problems = append(problems, fmt.Sprintf("after open: err=%v (want ErrCircuitOpen)", err))
+	}
+
+	result.Details = map[string]interface{}{
+		"problems": problems,
+	}
+	if len(problems) > 0 {
+		result.Message = fmt.Sprintf("200 の WAF ブロックページの扱いが不正: %v", problems)
+		log.Printf("  ❌ %v", problems)
+		return result
+	}
+
+	result.Success = true
+	result.Message = "200 で返る WAF ブロックページが ErrWAFBlocked になり、2回続くとサーキットブレーカーが開くことを確認"
+	log.Printf("  ✅ %s", result.Message)
+	return result
+}
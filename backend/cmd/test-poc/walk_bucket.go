@@ -0,0 +1,95 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"real-estate-portal/internal/meta"
+	"real-estate-portal/internal/models"
+	"real-estate-portal/internal/snapshot"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Test 60: 駅徒歩の分数帯（オフライン）
+// 境界値（5分・10分・15分ちょうど）が下の帯に入り、徒歩分数なしが unknown になること、
+// SQL式が同じ境界を使うこと、帯をまたぐ徒歩分の変化が access の変更1件だけになることを確認する
+func testWalkTimeBuckets() TestResult {
+	result := TestResult{
+		TestName:  "駅徒歩の分数帯",
+		Timestamp: time.Now(),
+	}
+
+	log.Println("\n[Test 60] 駅徒歩の分数帯テスト...")
+
+	intPtr := func(v int) *int { return &v }
+	var problems []string
+
+	// 1. Boundaries and NULL
+	for _, tc := range []struct {
+		minutes *int
+		want    string
+	}{
+		{nil, models.WalkBucketUnknown},
+		{intPtr(-1), models.WalkBucketUnknown},
+		{intPtr(0), models.WalkBucket1To5},
+		{intPtr(1), models.WalkBucket1To5},
+		{intPtr(5), models.WalkBucket1To5},
+		{intPtr(6), models.WalkBucket6To10},
+		{intPtr(10), models.WalkBucket6To10},
+		{intPtr(11), models.WalkBucket11To15},
+		{intPtr(15), models.WalkBucket11To15},
+		{intPtr(16), models.WalkBucket16Plus},
+		{intPtr(45), models.WalkBucket16Plus},
+	} {
+		if got := models.WalkTimeBucket(tc.minutes); got != tc.want {
+			label := "nil"
+			if tc.minutes != nil {
+				label = fmt.Sprint(*tc.minutes)
+			}
+			problems = append(problems, fmt.Sprintf("WalkTimeBucket(%s) = %q, want %q", label, got, tc.want))
+		}
+	}
+
+	// 2. Bus-only listings (walk_time NULL) land in unknown on save
+	bus := &models.Property{BusMinutes: intPtr(10), WalkTimeBucket: models.WalkBucket1To5}
+	bus.NormalizeWalkTimeBucket()
+	if bus.WalkTimeBucket != models.WalkBucketUnknown {
+		problems = append(problems, fmt.Sprintf("bus listing bucket = %q, want unknown", bus.WalkTimeBucket))
+	}
+
+	// 3. The SQL expression (per-station stats) uses the same boundaries
+	expr := models.WalkTimeBucketSQL("w")
+	for _, part := range []string{"w IS NULL OR w < 0 THEN 'unknown'", "w <= 5 THEN '1-5'", "w <= 10 THEN '6-10'", "w <= 15 THEN '11-15'", "ELSE '16+'"} {
+		if !strings.Contains(expr, part) {
+			problems = append(problems, fmt.Sprintf("WalkTimeBucketSQL lacks %q: %s", part, expr))
+		}
+	}
+
+	// 4. Crossing a bucket boundary is one access change, not a separate bucket change
+	old := &models.PropertySnapshot{Status: "active", WalkTime: intPtr(5)}
+	cur := &models.PropertySnapshot{Status: "active", WalkTime: intPtr(6)}
+	changes := snapshot.CompareSnapshots("bucket", old, cur, time.Now())
+	if len(changes) != 1 || changes[0].ChangeType != models.ChangeTypeAccess {
+		problems = append(problems, fmt.Sprintf("5→6分の変更 = %+v, want access 1件", changes))
+	}
+
+	// 5. Bucket list is published for the frontend
+	if !slices.Equal(meta.CurrentOptions().WalkTimeBuckets, []string{"1-5", "6-10", "11-15", "16+", "unknown"}) {
+		problems = append(problems, fmt.Sprintf("meta walk_time_buckets = %v", meta.CurrentOptions().WalkTimeBuckets))
+	}
+
+	result.Details = map[string]interface{}{
+		"problems": problems,
+	}
+	if len(problems) > 0 {
+		result.Message = fmt.Sprintf("分数帯が不正: %v", problems)
+		log.Printf("  ❌ %v", problems)
+		return result
+	}
+
+	result.Success = true
+	result.Message = "5/10/15分は下の帯、徒歩なしは unknown、帯の変化は access の変更に含まれる"
+	log.Printf("  ✅ %s", result.Message)
+	return result
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"real-estate-portal/internal/config"
+	"real-estate-portal/internal/models"
+	"real-estate-portal/internal/scheduler"
+	"real-estate-portal/internal/scraper"
+	"sync"
+	"time"
+)
+
+// Test 71: ワーカーの並列処理（オフライン）
+// max_concurrency=3 で 8 件の pending を処理すると同時実行数が 3 を超えず（3 に達し）、各項目が一度だけ
+// 処理されること、busy_slots / in_flight が統計に出ること、既定の設定では 1 件ずつ処理されることを確認する
+func testWorkerConcurrency() TestResult {
+	result := TestResult{
+		TestName:  "ワーカーの並列処理",
+		Timestamp: time.Now(),
+	}
+
+	log.Println("\n[Test 71] ワーカー並列処理テスト...")
+
+	var problems []string
+
+	// run processes eight pending rows with cfg until all are done and returns the highest
+	// number of scrapes seen at once, how often each row was scraped and the highest busy_slots stat
+	run := func(name string, cfg config.QueueWorkerConfig) (int, map[string]int, int) {
+		q := &fakeWorkerQueue{}
+		for i := 1; i <= 8; i++ {
+			id := fmt.Sprintf("%s%02d", name, i)
+			q.rows = append(q.rows, models.DetailScrapeQueue{
+				ID: int64(i), Source: "stub", SourcePropertyID: id, DetailURL: "https://stub.example/detail/" + id + "/",
+				Status: models.QueueStatusPending,
+			})
+		}
+		db, err := openFakeWorkerDB(q)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", name, err))
+			return 0, nil, 0
+		}
+
+		var mu sync.Mutex
+		running, peak := 0, 0
+		scraped := make(map[string]int)
+		source := newStubSource(func(_ context.Context, detailURL string) (*models.Property, error) {
+			mu.Lock()
+			running++
+			if running > peak {
+				peak = running
+			}
+			scraped[detailURL]++
+			mu.Unlock()
+
+			time.Sleep(60 * time.Millisecond)
+
+			mu.Lock()
+			running--
+			mu.Unlock()
+			return nil, fmt.Errorf("%w: status code 404", scraper.ErrNotFound)
+		})
+		w := scheduler.NewQueueWorkerWithConfig(db, scraper.NewScraper(), scraper.NewRegistry(source), cfg)
+		w.SetPollInterval(10 * time.Millisecond)
+		w.SetHealthCheck(func(context.Context) bool { return true })
+		w.Start()
+
+		peakBusy := 0
+		for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(5 * time.Millisecond) {
+			stats := w.GetQueueStats()
+			if n, _ := stats["busy_slots"].(int); n > peakBusy {
+				peakBusy = n
+			}
+			if n, _ := stats["in_flight"].(int); n > 8 {
+				problems = append(problems, fmt.Sprintf("%s: in_flight %d with 8 rows", name, n))
+			}
+			finished := 0
+			for i := int64(1); i <= 8; i++ {
+				if q.row(i).Status == models.QueueStatusPermanentFail {
+					finished++
+				}
+			}
+			if finished == 8 {
+				break
+			}
+		}
+		w.Stop()
+
+		for i := int64(1); i <= 8; i++ {
+			if row := q.row(i); row.Status != models.QueueStatusPermanentFail || row.Attempts != 1 {
+				problems = append(problems, fmt.Sprintf("%s: id=%d status=%s attempts=%d (want %s, 1)",
+					name, i, row.Status, row.Attempts, models.QueueStatusPermanentFail))
+			}
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		return peak, scraped, peakBusy
+	}
+
+	// 1. Three slots, a batch big enough to fill them: three at once, never more
+	peak, scraped, busy := run("parallel", config.QueueWorkerConfig{MaxConcurrency: 3, BatchSize: 8})
+	if peak != 3 {
+		problems = append(problems, fmt.Sprintf("parallel: %d scrapes at once (want 3)", peak))
+	}
+	if busy > 3 || busy == 0 {
+		problems = append(problems, fmt.Sprintf("parallel: busy_slots peaked at %d (want 1-3)", busy))
+	}
+	for u, n := range scraped {
+		if n != 1 {
+			problems = append(problems, fmt.Sprintf("parallel: %s scraped %d times", u, n))
+		}
+	}
+
+	// 2. The default config stays one at a time
+	if peak, _, _ := run("serial", config.QueueWorkerConfig{}); peak != 1 {
+		problems = append(problems, fmt.Sprintf("default: %d scrapes at once (want 1)", peak))
+	}
+
+	result.Details = map[string]interface{}{
+		"peak_parallel":   peak,
+		"peak_busy_slots": busy,
+		"problems":        problems,
+	}
+	if len(problems) > 0 {
+		result.Message = fmt.Sprintf("並列処理が不正: %v", problems)
+		log.Printf("  ❌ %v", problems)
+		return result
+	}
+
+	result.Success = true
+	result.Message = fmt.Sprintf("max_concurrency=3 で同時実行が %d 件に収まり、各項目が一度だけ処理されることを確認", peak)
+	log.Printf("  ✅ %s", result.Message)
+	return result
+}
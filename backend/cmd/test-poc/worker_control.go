@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"real-estate-portal/internal/models"
+	"real-estate-portal/internal/scheduler"
+	"real-estate-portal/internal/scraper"
+	"time"
+)
+
+// Test 78: ワーカーの開始・停止（オフライン）
+// POST /api/admin/worker/start / stop の処理（QueueWorker.Start / Stop）で、二重の開始・停止が
+// ErrWorkerRunning / ErrWorkerNotRunning になること、is_running が状態どおりに出ること、停止中は
+// 項目を取らず、再開後に処理を続けることを確認する
+func testWorkerControl() TestResult {
+	result := TestResult{
+		TestName:  "ワーカーの開始・停止",
+		Timestamp: time.Now(),
+	}
+
+	log.Println("\n[Test 78] ワーカー開始・停止テスト...")
+
+	pending := func(id int64) models.DetailScrapeQueue {
+		spid := fmt.Sprintf("ctl%02d", id)
+		return models.DetailScrapeQueue{
+			ID: id, Source: "stub", SourcePropertyID: spid, DetailURL: "https://stub.example/detail/" + spid + "/",
+			Status: models.QueueStatusPending,
+		}
+	}
+	q := &fakeWorkerQueue{rows: []models.DetailScrapeQueue{pending(1)}}
+	db, err := openFakeWorkerDB(q)
+	if err != nil {
+		result.Message = fmt.Sprintf("dry-run DB: %v", err)
+		log.Printf("  ❌ %s", result.Message)
+		return result
+	}
+	source := newStubSource(func(context.Context, string) (*models.Property, error) {
+		return nil, fmt.Errorf("%w: status code 404", scraper.ErrNotFound)
+	})
+	w := scheduler.NewQueueWorkerWithSources(db, scraper.NewScraper(), scraper.NewRegistry(source))
+	w.SetPollInterval(10 * time.Millisecond)
+	w.SetHealthCheck(func(context.Context) bool { return true })
+
+	var problems []string
+	expectErr := func(name string, err, want error) {
+		if !errors.Is(err, want) {
+			problems = append(problems, fmt.Sprintf("%s: err=%v (want %v)", name, err, want))
+		}
+	}
+	expectRunning := func(name string, want bool) {
+		if w.IsRunning() != want || w.GetQueueStats()["is_running"] != want {
+			problems = append(problems, fmt.Sprintf("%s: is_running=%v stats=%v (want %v)", name, w.IsRunning(), w.GetQueueStats()["is_running"], want))
+		}
+	}
+	waitDone := func(id int64) bool {
+		for deadline := time.Now().Add(3 * time.Second); time.Now().Before(deadline); time.Sleep(5 * time.Millisecond) {
+			if q.row(id).Status == models.QueueStatusPermanentFail {
+				return true
+			}
+		}
+		return false
+	}
+
+	// 1. Start once; a second start is refused and changes nothing
+	expectRunning("before start", false)
+	expectErr("stop before start", w.Stop(), scheduler.ErrWorkerNotRunning)
+	expectErr("start", w.Start(), nil)
+	expectErr("second start", w.Start(), scheduler.ErrWorkerRunning)
+	expectRunning("started", true)
+	if !waitDone(1) {
+		problems = append(problems, fmt.Sprintf("item 1 not processed: %s", q.row(1).Status))
+	}
+
+	// 2. Stop once; a stopped worker leaves new items alone
+	expectErr("stop", w.Stop(), nil)
+	expectErr("second stop", w.Stop(), scheduler.ErrWorkerNotRunning)
+	expectRunning("stopped", false)
+	q.mu.Lock()
+	q.rows = append(q.rows, pending(2))
+	q.mu.Unlock()
+	time.Sleep(100 * time.Millisecond)
+	if status := q.row(2).Status; status != models.QueueStatusPending {
+		problems = append(problems, fmt.Sprintf("stopped worker took item 2: %s", status))
+	}
+
+	// 3. Start again: the new run picks up where the old one left off
+	expectErr("restart", w.Start(), nil)
+	expectRunning("restarted", true)
+	if !waitDone(2) {
+		problems = append(problems, fmt.Sprintf("item 2 not processed after restart: %s", q.row(2).Status))
+	}
+	expectErr("final stop", w.Stop(), nil)
+
+	result.Details = map[string]interface{}{
+		"problems": problems,
+	}
+	if len(problems) > 0 {
+		result.Message = fmt.Sprintf("開始・停止が不正: %v", problems)
+		log.Printf("  ❌ %v", problems)
+		return result
+	}
+
+	result.Success = true
+	result.Message = "二重の開始・停止が拒否され、is_running が状態どおりで、停止後に再開できることを確認"
+	log.Printf("  ✅ %s", result.Message)
+	return result
+}
@@ -0,0 +1,368 @@
+package main
+
+import (
+	"context"
+	"real-estate-portal/internal/models"
+	"real-estate-portal/internal/queue"
+	"real-estate-portal/internal/ratelimit"
+	"real-estate-portal/internal/scraper"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// fakeWorkerQueue is an in-memory detail_scrape_queue behind a dry-run GORM handle, enough for
+// a QueueWorker loop: the next-item lookup returns the pending row the worker's ORDER BY would
+// pick (aged priority, then oldest, slice order on a tie; excluded sources skipped), saves and
+// status updates are applied to the rows, and every other First reports not found (the
+// scraping_state row is kept in state when set).
+type fakeWorkerQueue struct {
+	mu      sync.Mutex
+	rows    []models.DetailScrapeQueue
+	history map[int64][]string // statuses each row was saved with, in order
+	state   *fakeScrapingState
+}
+
+// openFakeWorkerDB returns a dry-run GORM handle backed by q
+func openFakeWorkerDB(q *fakeWorkerQueue) (*gorm.DB, error) {
+	q.history = make(map[int64][]string)
+	db, err := openDryRunDB()
+	if err != nil {
+		return nil, err
+	}
+	if err := db.Callback().Query().After("gorm:query").Register("poc:fake_worker_query", q.query); err != nil {
+		return nil, err
+	}
+	if err := db.Callback().Update().After("gorm:update").Register("poc:fake_worker_update", q.update); err != nil {
+		return nil, err
+	}
+	if q.state != nil {
+		if err := q.state.registerWrites(db); err != nil {
+			return nil, err
+		}
+	}
+	return db, nil
+}
+
+func (q *fakeWorkerQueue) query(tx *gorm.DB) {
+	if q.state != nil && q.state.answer(tx) {
+		return
+	}
+	if stale, ok := tx.Statement.Dest.(*[]models.DetailScrapeQueue); ok && strings.Contains(tx.Statement.SQL.String(), "updated_at <") {
+		q.stale(tx, stale)
+		return
+	}
+	if !tx.Statement.RaiseErrorOnNotFound || tx.Error != nil {
+		return
+	}
+	item, ok := tx.Statement.Dest.(*models.DetailScrapeQueue)
+	sql := tx.Statement.SQL.String()
+	if ok && strings.Contains(sql, "status = ?") && !strings.Contains(sql, "next_retry_at") {
+		q.mu.Lock()
+		defer q.mu.Unlock()
+		now, aging := queueOrderVars(tx)
+		var excluded []string // source NOT IN ? (sources cooling down or out of budget): the non-status strings
+		for _, v := range tx.Statement.Vars {
+			if v, ok := v.(string); ok && v != models.QueueStatusPending && v != models.QueueStatusFailed {
+				excluded = append(excluded, v)
+			}
+		}
+		best := -1
+		for i, row := range q.rows {
+			if row.Status != models.QueueStatusPending || slices.Contains(excluded, row.Source) {
+				continue
+			}
+			if best < 0 {
+				best = i
+				continue
+			}
+			p := queue.AgedPriority(row.Priority, row.CreatedAt, now, aging)
+			bp := queue.AgedPriority(q.rows[best].Priority, q.rows[best].CreatedAt, now, aging)
+			if p > bp || p == bp && row.CreatedAt.Before(q.rows[best].CreatedAt) {
+				best = i
+			}
+		}
+		if best >= 0 {
+			*item = q.rows[best]
+			tx.RowsAffected = 1
+			return
+		}
+	}
+	tx.AddError(gorm.ErrRecordNotFound)
+}
+
+// queueOrderVars reads the clock and aging step the worker's ORDER BY passed (aging 0: strict priority)
+func queueOrderVars(tx *gorm.DB) (time.Time, time.Duration) {
+	if !strings.Contains(tx.Statement.SQL.String(), "TIMESTAMPDIFF") {
+		return time.Time{}, 0
+	}
+	var now time.Time
+	var aging time.Duration
+	for _, v := range tx.Statement.Vars {
+		switch v := v.(type) {
+		case time.Time:
+			now = v
+		case int:
+			aging = time.Duration(v) * time.Hour
+		}
+	}
+	return now, aging
+}
+
+func (q *fakeWorkerQueue) update(tx *gorm.DB) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	switch dest := tx.Statement.Dest.(type) {
+	case *models.DetailScrapeQueue:
+		for i := range q.rows {
+			if q.rows[i].ID == dest.ID {
+				q.rows[i] = *dest
+				q.history[dest.ID] = append(q.history[dest.ID], dest.Status)
+				tx.RowsAffected = 1
+			}
+		}
+	case map[string]interface{}:
+		status, _ := dest["status"].(string)
+		if strings.Contains(tx.Statement.SQL.String(), "updated_at <") {
+			q.reap(tx, status)
+			return
+		}
+
+		// Model(&DetailScrapeQueue{}).Where("id IN ? AND status = ?", ...).Updates(...): items in
+		// processing returned to status, or (status processing) a claim of an item still in the
+		// status it was read with
+		var ids []int64
+		from := models.QueueStatusProcessing
+		for _, v := range tx.Statement.Vars {
+			switch v := v.(type) {
+			case int64:
+				ids = append(ids, v)
+			case []int64:
+				ids = append(ids, v...)
+			case string:
+				if status == models.QueueStatusProcessing && v != status {
+					from = v
+				}
+			}
+		}
+		for _, id := range ids {
+			for i := range q.rows {
+				if q.rows[i].ID == id && q.rows[i].Status == from && status != "" {
+					q.rows[i].Status = status
+					if status == models.QueueStatusProcessing {
+						q.rows[i].Attempts++
+					}
+					q.history[id] = append(q.history[id], status)
+					tx.RowsAffected++
+				}
+			}
+		}
+	}
+}
+
+// stale answers the reaper's Where("status = ? AND updated_at < ?").Where("id NOT IN ?") read:
+// the cutoff is the time var, int64 vars are excluded IDs
+func (q *fakeWorkerQueue) stale(tx *gorm.DB, dest *[]models.DetailScrapeQueue) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	var cutoff time.Time
+	excluded := make(map[int64]bool)
+	for _, v := range tx.Statement.Vars {
+		switch v := v.(type) {
+		case time.Time:
+			cutoff = v
+		case int64:
+			excluded[v] = true
+		case []int64:
+			for _, id := range v {
+				excluded[id] = true
+			}
+		}
+	}
+	for _, row := range q.rows {
+		if row.Status == models.QueueStatusProcessing && row.UpdatedAt.Before(cutoff) && !excluded[row.ID] {
+			*dest = append(*dest, models.DetailScrapeQueue{ID: row.ID, LastError: row.LastError})
+			tx.RowsAffected++
+		}
+	}
+}
+
+// reap applies the reaper's per-item Where("id = ? AND status = ? AND updated_at < ?") update:
+// the cutoff is the last time var (the SET updated_at comes first), the int64 var is the ID
+func (q *fakeWorkerQueue) reap(tx *gorm.DB, status string) {
+	var cutoff time.Time
+	var id int64
+	for _, v := range tx.Statement.Vars {
+		switch v := v.(type) {
+		case time.Time:
+			cutoff = v
+		case int64:
+			id = v
+		}
+	}
+	lastError, _ := tx.Statement.Dest.(map[string]interface{})["last_error"].(string)
+	for i := range q.rows {
+		row := &q.rows[i]
+		if row.ID != id || row.Status != models.QueueStatusProcessing || !row.UpdatedAt.Before(cutoff) {
+			continue
+		}
+		row.Status = status
+		row.LastError = lastError
+		q.history[row.ID] = append(q.history[row.ID], status)
+		tx.RowsAffected++
+	}
+}
+
+// row returns a copy of the row with id
+func (q *fakeWorkerQueue) row(id int64) models.DetailScrapeQueue {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for _, row := range q.rows {
+		if row.ID == id {
+			return row
+		}
+	}
+	return models.DetailScrapeQueue{}
+}
+
+// stubSource is a PropertySource for stub.example whose detail scrape is a test function
+type stubSource struct {
+	limiter *ratelimit.DetailLimiter
+	scrape  func(ctx context.Context, detailURL string) (*models.Property, error)
+}
+
+func newStubSource(scrape func(ctx context.Context, detailURL string) (*models.Property, error)) *stubSource {
+	return &stubSource{limiter: ratelimit.NewSourceDetailLimiter("stub", 1000), scrape: scrape}
+}
+
+func (s *stubSource) Name() string                      { return "stub" }
+func (s *stubSource) Hosts() []string                   { return []string{"stub.example"} }
+func (s *stubSource) Limiter() *ratelimit.DetailLimiter { return s.limiter }
+func (s *stubSource) SourcePropertyID(u string) (string, error) {
+	return strings.Trim(u[strings.LastIndex(strings.TrimSuffix(u, "/"), "/"):], "/"), nil
+}
+func (s *stubSource) ScrapeList(context.Context, string) ([]string, error) { return nil, nil }
+func (s *stubSource) ScrapeDetail(ctx context.Context, detailURL string, _ scraper.Validators) (*scraper.DetailResult, error) {
+	property, err := s.scrape(ctx, detailURL)
+	return &scraper.DetailResult{Property: property}, err
+}
+
+// fakeActiveQueue emulates detail_scrape_queue with its generated active_key unique index
+// for the statements issued by queue.Service.Enqueue; each statement runs under mu, as a row
+// lock would serialize it, so concurrent enqueues interleave only between statements
+type fakeActiveQueue struct {
+	mu         sync.Mutex
+	rows       []models.DetailScrapeQueue
+	hideActive bool // next active_key lookup misses (simulates a concurrent insert)
+	problems   []string
+}
+
+func (q *fakeActiveQueue) activeIndex(source, sourcePropertyID string) int {
+	for i, row := range q.rows {
+		if (row.Status == models.QueueStatusPending || row.Status == models.QueueStatusProcessing) &&
+			row.Source == source && row.SourcePropertyID == sourcePropertyID {
+			return i
+		}
+	}
+	return -1
+}
+
+func whereVars(tx *gorm.DB) []interface{} {
+	where, _ := tx.Statement.Clauses["WHERE"].Expression.(clause.Where)
+	var vars []interface{}
+	for _, expr := range where.Exprs {
+		if e, ok := expr.(clause.Expr); ok {
+			vars = append(vars, e.Vars...)
+		}
+	}
+	return vars
+}
+
+func (q *fakeActiveQueue) register(db *gorm.DB) error {
+	if err := db.Callback().Query().After("gorm:query").Register("poc:active_lookup", func(tx *gorm.DB) {
+		dest, ok := tx.Statement.Dest.(*[]models.DetailScrapeQueue)
+		if !ok || !strings.Contains(tx.Statement.SQL.String(), "active_key = ?") {
+			return
+		}
+		q.mu.Lock()
+		defer q.mu.Unlock()
+		*dest = nil
+		if q.hideActive {
+			q.hideActive = false
+			return
+		}
+		key := whereVars(tx)[0].(string)
+		for _, row := range q.rows {
+			if (row.Status == models.QueueStatusPending || row.Status == models.QueueStatusProcessing) &&
+				models.QueueActiveKey(row.Source, row.SourcePropertyID) == key {
+				*dest = append(*dest, row)
+				break
+			}
+		}
+		tx.RowsAffected = int64(len(*dest))
+	}); err != nil {
+		return err
+	}
+
+	if err := db.Callback().Update().After("gorm:update").Register("poc:active_update", func(tx *gorm.DB) {
+		q.mu.Lock()
+		defer q.mu.Unlock()
+		set, _ := tx.Statement.Dest.(map[string]interface{})
+		vars := whereVars(tx)
+		sql := tx.Statement.SQL.String()
+		switch {
+		case strings.Contains(sql, "WHERE id = ?"): // priority bump
+			for i := range q.rows {
+				if q.rows[i].ID == vars[0].(int64) {
+					q.rows[i].Priority = set["priority"].(int)
+					tx.RowsAffected = 1
+				}
+			}
+		case strings.Contains(sql, "status = ?"): // failed row reset
+			source, spid := vars[0].(string), vars[1].(string)
+			for i := len(q.rows) - 1; i >= 0; i-- {
+				row := &q.rows[i]
+				if row.Source != source || row.SourcePropertyID != spid || row.Status != vars[2].(string) {
+					continue
+				}
+				if q.activeIndex(source, spid) >= 0 {
+					tx.AddError(gorm.ErrDuplicatedKey) // uniq_queue_active_key
+					return
+				}
+				bump := set["priority"].(clause.Expr).Vars[0].(int)
+				row.Status = set["status"].(string)
+				row.Attempts = set["attempts"].(int)
+				row.Priority = max(row.Priority, bump)
+				tx.RowsAffected = 1
+				return
+			}
+		}
+	}); err != nil {
+		return err
+	}
+
+	return db.Callback().Create().After("gorm:create").Register("poc:active_upsert", func(tx *gorm.DB) {
+		q.mu.Lock()
+		defer q.mu.Unlock()
+		item := tx.Statement.Dest.(*models.DetailScrapeQueue)
+		if _, ok := tx.Statement.Clauses["ON CONFLICT"]; !ok {
+			q.problems = append(q.problems, "INSERT without ON DUPLICATE KEY UPDATE")
+		}
+		if i := q.activeIndex(item.Source, item.SourcePropertyID); i >= 0 {
+			// Duplicate active_key: GREATEST(priority, ?) with the inserted priority
+			if item.Priority > q.rows[i].Priority {
+				q.rows[i].Priority = item.Priority
+				tx.RowsAffected = 2
+			}
+			return
+		}
+		item.ID = int64(len(q.rows) + 1)
+		q.rows = append(q.rows, *item)
+		tx.RowsAffected = 1
+	})
+}
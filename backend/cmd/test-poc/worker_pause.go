@@ -0,0 +1,180 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"real-estate-portal/internal/models"
+	"real-estate-portal/internal/scheduler"
+	"real-estate-portal/internal/scraper"
+	"strings"
+	"sync"
+	"time"
+)
+
+// logCapture copies the log output while a test watches for a message
+type logCapture struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (c *logCapture) Write(p []byte) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.buf.Write(p)
+}
+
+func (c *logCapture) count(message string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return strings.Count(c.buf.String(), message)
+}
+
+// Test 83: キューワーカーの一時停止（オフライン）
+// Pause したワーカーがループを続けたまま項目を取らず（ヘルスチェックも送らない）、スキップを1回だけ
+// ログに出すこと、一時停止が scraping_state に保存されて再起動したワーカーが一時停止のまま起動すること、
+// Resume で次のティックから処理が再開して保存も消えること、二重の Pause / Resume が理由の更新・何もしないで
+// 済むことを確認する
+func testWorkerPause() TestResult {
+	result := TestResult{
+		TestName:  "キューワーカーの一時停止",
+		Timestamp: time.Now(),
+	}
+
+	log.Println("\n[Test 83] キューワーカー一時停止テスト...")
+
+	state := &fakeScrapingState{}
+	q := &fakeWorkerQueue{
+		rows: []models.DetailScrapeQueue{{
+			ID: 1, Source: "stub", SourcePropertyID: "paused01", DetailURL: "https://stub.example/detail/paused01/",
+			Status: models.QueueStatusPending,
+		}},
+		state: state,
+	}
+	db, err := openFakeWorkerDB(q)
+	if err != nil {
+		result.Message = fmt.Sprintf("dry-run DB: %v", err)
+		log.Printf("  ❌ %s", result.Message)
+		return result
+	}
+
+	var problems []string
+	source := newStubSource(func(context.Context, string) (*models.Property, error) {
+		return nil, fmt.Errorf("%w: status code 404", scraper.ErrNotFound)
+	})
+	var checks int
+	var checksMu sync.Mutex
+	newWorker := func() *scheduler.QueueWorker {
+		w := scheduler.NewQueueWorkerWithSources(db, scraper.NewScraper(), scraper.NewRegistry(source))
+		w.SetPollInterval(10 * time.Millisecond)
+		w.SetHealthCheck(func(context.Context) bool {
+			checksMu.Lock()
+			defer checksMu.Unlock()
+			checks++
+			return true
+		})
+		return w
+	}
+	healthChecks := func() int {
+		checksMu.Lock()
+		defer checksMu.Unlock()
+		return checks
+	}
+
+	capture := &logCapture{}
+	out := log.Writer()
+	log.SetOutput(io.MultiWriter(out, capture))
+	defer log.SetOutput(out)
+	const skipped = "not claiming items until resumed"
+
+	// 1. Paused before Start: many ticks, nothing claimed, no health check, one log line
+	w := newWorker()
+	if _, err := w.Pause("incident"); err != nil {
+		problems = append(problems, fmt.Sprintf("Pause: %v", err))
+	}
+	if err := w.Start(); err != nil {
+		problems = append(problems, fmt.Sprintf("Start: %v", err))
+	}
+	time.Sleep(150 * time.Millisecond)
+	if stats := w.GetQueueStats(); stats["paused"] != true || stats["is_running"] != true {
+		problems = append(problems, fmt.Sprintf("paused worker: stats paused=%v is_running=%v", stats["paused"], stats["is_running"]))
+	}
+	if err := w.Stop(); err != nil {
+		problems = append(problems, fmt.Sprintf("Stop: %v", err))
+	}
+	if row := q.row(1); row.Status != models.QueueStatusPending || len(q.history[1]) != 0 {
+		problems = append(problems, fmt.Sprintf("paused worker claimed the item: %s %v", row.Status, q.history[1]))
+	}
+	if n := healthChecks(); n != 0 {
+		problems = append(problems, fmt.Sprintf("paused worker ran %d health checks (want none)", n))
+	}
+	if n := capture.count(skipped); n != 1 {
+		problems = append(problems, fmt.Sprintf("skipped ticks logged %d times (want once)", n))
+	}
+	if row := state.get(); row == nil || !row.WorkerPaused || row.WorkerPausedReason != "incident" || row.WorkerPausedAt == nil {
+		problems = append(problems, fmt.Sprintf("pause not persisted: %+v", row))
+	}
+
+	// 2. Restart: a new worker comes back paused with the persisted reason
+	restarted := newWorker()
+	if err := restarted.Start(); err != nil {
+		problems = append(problems, fmt.Sprintf("restarted Start: %v", err))
+	}
+	pause := restarted.Paused()
+	if !pause.Paused || pause.Reason != "incident" || pause.Since == nil {
+		problems = append(problems, fmt.Sprintf("restarted worker: %+v (want paused for the incident)", pause))
+	}
+	time.Sleep(100 * time.Millisecond)
+	if row := q.row(1); row.Status != models.QueueStatusPending {
+		problems = append(problems, fmt.Sprintf("restarted worker claimed the item: %s", row.Status))
+	}
+
+	// 3. Pausing again only replaces the reason
+	again, err := restarted.Pause("still investigating")
+	if err != nil || again.Reason != "still investigating" || again.Since == nil || pause.Since == nil || !again.Since.Equal(*pause.Since) {
+		problems = append(problems, fmt.Sprintf("second Pause: %+v, %v (want the new reason, since %v)", again, err, pause.Since))
+	}
+
+	// 4. Resume: the next tick runs the health check and processes the item; the row is cleared
+	if _, err := restarted.Resume(); err != nil {
+		problems = append(problems, fmt.Sprintf("Resume: %v", err))
+	}
+	for deadline := time.Now().Add(3 * time.Second); time.Now().Before(deadline) &&
+		(q.row(1).Status == models.QueueStatusPending || q.row(1).Status == models.QueueStatusProcessing); time.Sleep(5 * time.Millisecond) {
+	}
+	if stats := restarted.GetQueueStats(); stats["paused"] != false {
+		problems = append(problems, fmt.Sprintf("resumed worker: stats paused=%v", stats["paused"]))
+	}
+	if err := restarted.Stop(); err != nil {
+		problems = append(problems, fmt.Sprintf("restarted Stop: %v", err))
+	}
+	if row := q.row(1); row.Status != models.QueueStatusPermanentFail {
+		problems = append(problems, fmt.Sprintf("resumed worker: item %s (want %s)", row.Status, models.QueueStatusPermanentFail))
+	}
+	if n := healthChecks(); n != 1 {
+		problems = append(problems, fmt.Sprintf("resumed worker ran %d health checks (want 1)", n))
+	}
+	if row := state.get(); row == nil || row.WorkerPaused || row.WorkerPausedReason != "" || row.WorkerPausedAt != nil {
+		problems = append(problems, fmt.Sprintf("resume not persisted: %+v", row))
+	}
+	if _, err := restarted.Resume(); err != nil || restarted.IsPaused() {
+		problems = append(problems, fmt.Sprintf("second Resume: %v, paused=%v", err, restarted.IsPaused()))
+	}
+
+	result.Details = map[string]interface{}{
+		"history":  q.history[1],
+		"problems": problems,
+	}
+	if len(problems) > 0 {
+		result.Message = fmt.Sprintf("一時停止が不正: %v", problems)
+		log.Printf("  ❌ %v", problems)
+		return result
+	}
+
+	result.Success = true
+	result.Message = "一時停止中は項目を取らずログも1回だけで、再起動後も一時停止が続き、再開で処理が戻ることを確認"
+	log.Printf("  ✅ %s", result.Message)
+	return result
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"real-estate-portal/internal/models"
+	"real-estate-portal/internal/scheduler"
+	"real-estate-portal/internal/scraper"
+	"time"
+)
+
+// Test 70: ワーカーの停止と処理中の項目（オフライン）
+// 処理中に Stop しても項目が processing のまま残らないこと（猶予 0 なら即キャンセルして pending に戻し、
+// 猶予内に終わる項目は最後まで処理し、猶予を過ぎた項目はキャンセルして pending に戻す）を確認する
+func testWorkerShutdown() TestResult {
+	result := TestResult{
+		TestName:  "ワーカーの停止と処理中の項目",
+		Timestamp: time.Now(),
+	}
+
+	log.Println("\n[Test 70] ワーカー停止テスト...")
+
+	var problems []string
+
+	// run starts a worker on one pending row, stops it once the scrape has begun and returns
+	// the row and how long Stop took
+	run := func(name string, grace time.Duration, scrape func(ctx context.Context) error) (models.DetailScrapeQueue, time.Duration) {
+		q := &fakeWorkerQueue{rows: []models.DetailScrapeQueue{{
+			ID: 1, Source: "stub", SourcePropertyID: name, DetailURL: "https://stub.example/detail/" + name + "/",
+			Status: models.QueueStatusPending,
+		}}}
+		db, err := openFakeWorkerDB(q)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", name, err))
+			return models.DetailScrapeQueue{}, 0
+		}
+
+		started := make(chan struct{}, 1)
+		source := newStubSource(func(ctx context.Context, _ string) (*models.Property, error) {
+			started <- struct{}{}
+			return nil, scrape(ctx)
+		})
+		w := scheduler.NewQueueWorkerWithSources(db, scraper.NewScraper(), scraper.NewRegistry(source))
+		w.SetPollInterval(20 * time.Millisecond)
+		w.SetHealthCheck(func(context.Context) bool { return true })
+		w.SetShutdownGrace(grace)
+		w.Start()
+
+		select {
+		case <-started:
+		case <-time.After(2 * time.Second):
+			problems = append(problems, fmt.Sprintf("%s: the item was never scraped", name))
+		}
+		start := time.Now()
+		w.Stop()
+		return q.row(1), time.Since(start)
+	}
+	untilCanceled := func(ctx context.Context) error {
+		<-ctx.Done()
+		return fmt.Errorf("scrape canceled: %w", ctx.Err())
+	}
+
+	// 1. No grace: canceled at once and back to pending, the attempt not counted
+	row, took := run("nograce", 0, untilCanceled)
+	if row.Status != models.QueueStatusPending || row.Attempts != 0 {
+		problems = append(problems, fmt.Sprintf("no grace: status=%s attempts=%d (want pending, 0)", row.Status, row.Attempts))
+	}
+	if took > time.Second {
+		problems = append(problems, fmt.Sprintf("no grace: Stop took %v", took.Round(time.Millisecond)))
+	}
+
+	// 2. Finishes within the grace period: processed to the end (a 404 fails permanently)
+	row, took = run("finishes", 2*time.Second, func(context.Context) error {
+		time.Sleep(150 * time.Millisecond)
+		return fmt.Errorf("%w: status code 404", scraper.ErrNotFound)
+	})
+	if row.Status != models.QueueStatusPermanentFail {
+		problems = append(problems, fmt.Sprintf("within grace: status=%s (want %s)", row.Status, models.QueueStatusPermanentFail))
+	}
+	if took < 100*time.Millisecond || took > time.Second {
+		problems = append(problems, fmt.Sprintf("within grace: Stop took %v (want the ~150ms the item needed)", took.Round(time.Millisecond)))
+	}
+
+	// 3. Outlasts the grace period: canceled after it and back to pending
+	row, took = run("outlasts", 200*time.Millisecond, untilCanceled)
+	if row.Status != models.QueueStatusPending {
+		problems = append(problems, fmt.Sprintf("past grace: status=%s (want pending)", row.Status))
+	}
+	if took < 150*time.Millisecond || took > time.Second {
+		problems = append(problems, fmt.Sprintf("past grace: Stop took %v (want ~200ms)", took.Round(time.Millisecond)))
+	}
+
+	result.Details = map[string]interface{}{
+		"problems": problems,
+	}
+	if len(problems) > 0 {
+		result.Message = fmt.Sprintf("停止時の項目の扱いが不正: %v", problems)
+		log.Printf("  ❌ %v", problems)
+		return result
+	}
+
+	result.Success = true
+	result.Message = "停止時に処理中の項目は猶予内なら最後まで処理、それ以外はキャンセルして pending に戻り、processing のまま残らないことを確認"
+	log.Printf("  ✅ %s", result.Message)
+	return result
+}
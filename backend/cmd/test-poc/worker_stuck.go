@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"real-estate-portal/internal/errtext"
+	"real-estate-portal/internal/models"
+	"real-estate-portal/internal/scheduler"
+	"real-estate-portal/internal/scraper"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// Test 72: processing のまま残った項目の回収（オフライン）
+// 前のプロセスが落ちて processing のまま古くなった項目が起動時に pending に戻され（last_error に追記、
+// 試行回数はそのまま）再び処理されること、まだ新しい processing の項目はしきい値を過ぎてから定期実行で
+// 回収されること、回収件数が統計の recovered_stuck に出ることを確認する
+func testStuckProcessing() TestResult {
+	result := TestResult{
+		TestName:  "processing のまま残った項目の回収",
+		Timestamp: time.Now(),
+	}
+
+	log.Println("\n[Test 72] processing のまま残った項目の回収テスト...")
+
+	var problems []string
+
+	// 1: stranded an hour ago by a crashed process; 2: updated just now (another worker's, for now);
+	// 3: stranded too, with a last_error already at the length cap
+	q := &fakeWorkerQueue{rows: []models.DetailScrapeQueue{
+		{ID: 1, Source: "stub", SourcePropertyID: "stuck01", DetailURL: "https://stub.example/detail/stuck01/",
+			Status: models.QueueStatusProcessing, Attempts: 1, LastError: "timeout", UpdatedAt: time.Now().Add(-time.Hour)},
+		{ID: 2, Source: "stub", SourcePropertyID: "stuck02", DetailURL: "https://stub.example/detail/stuck02/",
+			Status: models.QueueStatusProcessing, Attempts: 1, UpdatedAt: time.Now()},
+		{ID: 3, Source: "stub", SourcePropertyID: "stuck03", DetailURL: "https://stub.example/detail/stuck03/",
+			Status: models.QueueStatusProcessing, Attempts: 1, LastError: strings.Repeat("x", errtext.MaxLength()), UpdatedAt: time.Now().Add(-time.Hour)},
+	}}
+	db, err := openFakeWorkerDB(q)
+	if err != nil {
+		result.Message = fmt.Sprintf("fake DB: %v", err)
+		log.Printf("  ❌ %s", result.Message)
+		return result
+	}
+
+	// The last_error each item carried when it was scraped again
+	var mu sync.Mutex
+	notes := make(map[string]string)
+	source := newStubSource(func(_ context.Context, detailURL string) (*models.Property, error) {
+		id := int64(1)
+		if strings.Contains(detailURL, "stuck02") {
+			id = 2
+		} else if strings.Contains(detailURL, "stuck03") {
+			id = 3
+		}
+		mu.Lock()
+		notes[detailURL] = q.row(id).LastError
+		mu.Unlock()
+		return nil, fmt.Errorf("%w: status code 404", scraper.ErrNotFound)
+	})
+
+	const staleAfter = 300 * time.Millisecond
+	w := scheduler.NewQueueWorkerWithSources(db, scraper.NewScraper(), scraper.NewRegistry(source))
+	w.SetPollInterval(20 * time.Millisecond)
+	w.SetHealthCheck(func(context.Context) bool { return true })
+	w.SetStaleProcessingAfter(staleAfter)
+	start := time.Now()
+	w.Start()
+	defer w.Stop()
+
+	waitDone := func(id int64) time.Duration {
+		for deadline := time.Now().Add(3 * time.Second); time.Now().Before(deadline); time.Sleep(5 * time.Millisecond) {
+			if q.row(id).Status == models.QueueStatusPermanentFail {
+				return time.Since(start)
+			}
+		}
+		return 0
+	}
+
+	// 1. The stale item is recovered at start and scraped again; the fresh one is left alone
+	first := waitDone(1)
+	second := q.row(2)
+	if first == 0 {
+		problems = append(problems, fmt.Sprintf("stale item never processed again: %+v", q.row(1)))
+	} else if second.Status != models.QueueStatusProcessing && first < staleAfter {
+		problems = append(problems, fmt.Sprintf("fresh item touched at start: status=%s", second.Status))
+	}
+	if row := q.row(1); row.Attempts != 2 {
+		problems = append(problems, fmt.Sprintf("stale item: attempts=%d (want 2, the lost attempt counted)", row.Attempts))
+	}
+
+	// 2. The other one is recovered by the periodic run once it is past the threshold
+	if took := waitDone(2); took == 0 {
+		problems = append(problems, fmt.Sprintf("second item never recovered: %+v", q.row(2)))
+	} else if took < staleAfter {
+		problems = append(problems, fmt.Sprintf("second item recovered after %v (before the %v threshold)", took.Round(time.Millisecond), staleAfter))
+	}
+
+	// 3. A note was appended to last_error (the old text cut to keep it within the cap), and all
+	// recoveries were counted
+	if waitDone(3) == 0 {
+		problems = append(problems, fmt.Sprintf("third item never processed again: %+v", q.row(3)))
+	}
+	mu.Lock()
+	note1, note2 := notes["https://stub.example/detail/stuck01/"], notes["https://stub.example/detail/stuck02/"]
+	note3 := notes["https://stub.example/detail/stuck03/"]
+	mu.Unlock()
+	if n := utf8.RuneCountInString(note3); n > errtext.MaxLength() || !strings.HasSuffix(note3, " | recovered: stuck in processing for over 300ms") {
+		problems = append(problems, fmt.Sprintf("capped item last_error: %d chars, ends %q (want <= %d, ending with the note)",
+			n, note3[max(0, len(note3)-60):], errtext.MaxLength()))
+	}
+	if !strings.HasPrefix(note1, "timeout | recovered:") {
+		problems = append(problems, fmt.Sprintf("stale item last_error=%q (want the note appended)", note1))
+	}
+	if !strings.HasPrefix(note2, "recovered:") {
+		problems = append(problems, fmt.Sprintf("second item last_error=%q (want the note)", note2))
+	}
+	recovered, _ := w.GetQueueStats()["recovered_stuck"].(int64)
+	if recovered != 3 {
+		problems = append(problems, fmt.Sprintf("recovered_stuck=%d (want 3)", recovered))
+	}
+
+	result.Details = map[string]interface{}{
+		"recovered_stuck": recovered,
+		"problems":        problems,
+	}
+	if len(problems) > 0 {
+		result.Message = fmt.Sprintf("processing の回収が不正: %v", problems)
+		log.Printf("  ❌ %v", problems)
+		return result
+	}
+
+	result.Success = true
+	result.Message = fmt.Sprintf("processing のまま古くなった項目が pending に戻って再処理され（%d 件）、新しい項目はしきい値まで残ることを確認", recovered)
+	log.Printf("  ✅ %s", result.Message)
+	return result
+}
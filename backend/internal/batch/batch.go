@@ -0,0 +1,89 @@
+// Package batch runs per-URL work for batch endpoints and keeps results in request order.
+package batch
+
+import (
+	"real-estate-portal/internal/errtext"
+	"sync"
+)
+
+// Item statuses
+const (
+	StatusOK    = "ok"
+	StatusError = "error"
+)
+
+// Result is the outcome for one input URL. Results are returned at the same index
+// as the input, so callers can correlate them without matching on URL.
+type Result struct {
+	URL        string `json:"url"`
+	Status     string `json:"status"` // "ok" | "error"
+	PropertyID string `json:"property_id,omitempty"`
+	Error      string `json:"error,omitempty"`      // sanitized and length-bounded
+	ErrorCode  string `json:"error_code,omitempty"` // short classification (see errtext)
+	Action     string `json:"action,omitempty"`     // endpoint-specific detail (e.g. "queued", "existing")
+}
+
+// OK builds a successful result
+func OK(url, propertyID string) Result {
+	return Result{URL: url, Status: StatusOK, PropertyID: propertyID}
+}
+
+// Failed builds an error result
+func Failed(url string, err error) Result {
+	code, msg := errtext.FromError(err)
+	return Result{URL: url, Status: StatusError, Error: msg, ErrorCode: code}
+}
+
+// Run calls fn for each index in [0, n) using up to concurrency goroutines and
+// collects the results by index, so ordering never depends on completion order.
+func Run(n, concurrency int, fn func(i int) Result) []Result {
+	results := make([]Result, n)
+	if n == 0 {
+		return results
+	}
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	if concurrency > n {
+		concurrency = n
+	}
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				results[i] = fn(i)
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	return results
+}
+
+// Summary counts results by status
+type Summary struct {
+	Total  int `json:"total"`
+	OK     int `json:"ok"`
+	Failed int `json:"failed"`
+}
+
+// Summarize counts ok and error results
+func Summarize(results []Result) Summary {
+	s := Summary{Total: len(results)}
+	for _, r := range results {
+		if r.Status == StatusOK {
+			s.OK++
+		} else {
+			s.Failed++
+		}
+	}
+	return s
+}
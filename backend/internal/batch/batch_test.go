@@ -0,0 +1,23 @@
+package batch
+
+import (
+	"errors"
+	"real-estate-portal/internal/errtext"
+	"strings"
+	"testing"
+)
+
+// Failed stores the sanitized message and code, never the raw error
+func TestFailedSanitizes(t *testing.T) {
+	raw := errors.New("status code 503 url=https://example.com/rent/detail/abc/?token=s3cr3t body=<html><body>" +
+		strings.Repeat("x", 2000) + "</body></html>")
+	code, msg := errtext.FromError(raw)
+
+	got := Failed("https://example.com/rent/detail/abc/", raw)
+	if got.Status != StatusError || got.Error != msg || got.ErrorCode != code {
+		t.Errorf("Failed = %+v (want error %q, code %s)", got, msg, code)
+	}
+	if strings.Contains(got.Error, "s3cr3t") || strings.Contains(got.Error, "<html") {
+		t.Errorf("unsanitized error: %s", got.Error)
+	}
+}
@@ -0,0 +1,30 @@
+package config
+
+import "testing"
+
+// An empty allow list or "*" with credentials, and origins that aren't scheme://host, fail validation
+func TestValidateCORS(t *testing.T) {
+	tests := []struct {
+		yaml    string
+		wantErr bool
+	}{
+		{"cors:\n  allow_origins: []\n", true},
+		{"cors:\n  allow_origins: [\"*\"]\n", true},
+		{"cors:\n  allow_origins: [\"shiboroom.example\"]\n", true},
+		{"cors:\n  allow_origins: [\"https://shiboroom.example/app\"]\n", true},
+		{"cors:\n  allow_origins: []\n  allow_credentials: false\n", false},
+		{"cors:\n  allow_origins: [\"*\"]\n  allow_credentials: false\n", false},
+		{"cors:\n  allow_origins: [\"https://shiboroom.example/\", \"http://localhost:5176\"]\n", false},
+		{"timezone: Asia/Tokyo\n", false},
+	}
+	for _, tt := range tests {
+		cfg, err := loadYAML(t, tt.yaml)
+		if err != nil {
+			t.Errorf("load %q: %v", tt.yaml, err)
+			continue
+		}
+		if err := cfg.Validate(); (err != nil) != tt.wantErr {
+			t.Errorf("Validate(%q) = %v, wantErr %v", tt.yaml, err, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,25 @@
+package config
+
+import (
+	"real-estate-portal/internal/models"
+	"testing"
+	"time"
+)
+
+func TestFreshnessThresholds(t *testing.T) {
+	if got := (FreshnessConfig{}).Thresholds(); got != models.DefaultFreshnessThresholds {
+		t.Errorf("unset config: %+v (want defaults %+v)", got, models.DefaultFreshnessThresholds)
+	}
+
+	got := FreshnessConfig{FreshHours: 12, StaleDays: 14}.Thresholds()
+	want := models.DefaultFreshnessThresholds
+	want.Fresh = 12 * time.Hour
+	want.Stale = 14 * 24 * time.Hour
+	if got != want {
+		t.Errorf("partial config: %+v (want %+v)", got, want)
+	}
+
+	if got := DefaultConfig().Freshness.Thresholds(); got != models.DefaultFreshnessThresholds {
+		t.Errorf("default config: %+v (want %+v)", got, models.DefaultFreshnessThresholds)
+	}
+}
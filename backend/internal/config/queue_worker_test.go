@@ -0,0 +1,19 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPriorityAging(t *testing.T) {
+	for hours, want := range map[int]time.Duration{
+		0:  DefaultPriorityAging,
+		-1: 0,
+		6:  6 * time.Hour,
+		48: 48 * time.Hour,
+	} {
+		if got := (QueueWorkerConfig{PriorityAgingHours: hours}).PriorityAging(); got != want {
+			t.Errorf("priority_aging_hours %d = %v (want %v)", hours, got, want)
+		}
+	}
+}
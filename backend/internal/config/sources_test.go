@@ -0,0 +1,85 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const sourcesYAML = `
+user_agents:
+  - "Global/1.0"
+scraper:
+  request_delay_seconds: 3
+  proxy:
+    urls: ["http://global-proxy.example:8080"]
+sources:
+  yahoo:
+    user_agents: ["YahooAgent/1.0"]
+    base_delay_ms: 5000
+    jitter_ms: 1500
+    detail_per_hour: 40
+    headers:
+      Accept-Language: "ja-JP"
+  suumo:
+    headers:
+      X-Profile: "suumo"
+`
+
+// loadYAML writes text to a config file and loads it
+func loadYAML(t *testing.T, text string) (*Config, error) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(text), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	return LoadConfig(path)
+}
+
+func TestResolveSource(t *testing.T) {
+	cfg, err := loadYAML(t, sourcesYAML)
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+
+	tests := []struct {
+		source        string
+		configured    bool
+		userAgents    string
+		baseDelay     time.Duration
+		jitter        time.Duration
+		detailPerHour int
+		headers       string
+	}{
+		// Every field set in the block
+		{"yahoo", true, "[YahooAgent/1.0]", 5 * time.Second, 1500 * time.Millisecond, 40, "map[Accept-Language:ja-JP]"},
+		// Only headers set: UA pool and delay fall back to the global values
+		{"suumo", true, "[Global/1.0]", 3 * time.Second, 0, 0, "map[X-Profile:suumo]"},
+		// No block: built-in defaults apply
+		{"athome", false, "[]", 0, 0, 0, "map[]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.source, func(t *testing.T) {
+			got := cfg.ResolveSource(tt.source)
+			if got.Configured != tt.configured || fmt.Sprint(got.UserAgents) != tt.userAgents ||
+				got.BaseDelay != tt.baseDelay || got.Jitter != tt.jitter || got.DetailPerHour != tt.detailPerHour ||
+				fmt.Sprint(got.Headers) != tt.headers {
+				t.Errorf("ResolveSource(%q) = %+v", tt.source, got)
+			}
+			// Every source falls back to the global proxy list
+			if fmt.Sprint(got.Proxies) != "[http://global-proxy.example:8080]" {
+				t.Errorf("proxies %v", got.Proxies)
+			}
+		})
+	}
+}
+
+func TestSourceBlockUnknownKey(t *testing.T) {
+	_, err := loadYAML(t, "sources:\n  yahoo:\n    user_agent: \"typo/1.0\"\n")
+	if err == nil || !strings.Contains(err.Error(), `unknown key "user_agent"`) {
+		t.Errorf("LoadConfig error %v (want unknown key user_agent)", err)
+	}
+}
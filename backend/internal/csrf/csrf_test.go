@@ -0,0 +1,104 @@
+package csrf
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-contrib/cors"
+	"github.com/gin-gonic/gin"
+)
+
+const frontend = "https://shiboroom.example"
+
+// router mirrors the API setup: CORS with the custom headers allowed, then the csrf middleware
+func router(withCORS bool) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	origins := []string{frontend}
+	r := gin.New()
+	if withCORS {
+		r.Use(cors.New(cors.Config{
+			AllowOrigins:     origins,
+			AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE"},
+			AllowHeaders:     append([]string{"Origin", "Content-Type"}, CustomHeaders...),
+			AllowCredentials: true,
+		}))
+	}
+	r.Use(Middleware(origins))
+	ok := func(c *gin.Context) { c.JSON(http.StatusOK, gin.H{"ok": true}) }
+	r.GET("/api/properties/:id", ok)
+	r.POST("/api/properties/:id/favorite", ok)
+	r.DELETE("/api/properties/:id/favorite", ok)
+	return r
+}
+
+func TestMiddleware(t *testing.T) {
+	tests := []struct {
+		name     string
+		withCORS bool
+		method   string
+		headers  map[string]string
+		want     int
+	}{
+		// Cross-site form post: foreign Origin, no custom header
+		{"foreign form post", true, http.MethodPost, map[string]string{"Origin": "https://evil.example"}, http.StatusForbidden},
+		// Only the csrf middleware stands between a foreign Origin and the handler when CORS is off
+		{"foreign origin with header, csrf only", false, http.MethodPost,
+			map[string]string{"Origin": "https://evil.example", "X-Requested-With": "XMLHttpRequest"}, http.StatusForbidden},
+
+		// Browser requests without a custom header
+		{"allowed origin, no header", true, http.MethodPost, map[string]string{"Origin": frontend}, http.StatusForbidden},
+		{"referer only", true, http.MethodDelete, map[string]string{"Referer": "https://evil.example/page"}, http.StatusForbidden},
+		{"sec-fetch only", true, http.MethodPost, map[string]string{"Sec-Fetch-Site": "cross-site"}, http.StatusForbidden},
+
+		// Legitimate requests
+		{"frontend fetch", true, http.MethodPost, map[string]string{"Origin": frontend, "X-Requested-With": "XMLHttpRequest"}, http.StatusOK},
+		{"frontend delete", true, http.MethodDelete, map[string]string{"Origin": frontend, "X-Requested-With": "XMLHttpRequest"}, http.StatusOK},
+		{"same host", true, http.MethodPost, map[string]string{"Origin": "https://api.shiboroom.example", "X-Client-Token": "t"}, http.StatusOK},
+		{"internal caller", true, http.MethodPost, map[string]string{"X-API-Key": "k"}, http.StatusOK},
+		{"curl", true, http.MethodPost, nil, http.StatusOK},
+		{"foreign read", true, http.MethodGet, map[string]string{"Referer": "https://evil.example/"}, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := "/api/properties/abc/favorite"
+			if tt.method == http.MethodGet {
+				path = "/api/properties/abc"
+			}
+			req := httptest.NewRequest(tt.method, path, nil)
+			req.Host = "api.shiboroom.example"
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+			w := httptest.NewRecorder()
+			router(tt.withCORS).ServeHTTP(w, req)
+			if w.Code != tt.want {
+				t.Errorf("%s = %d, want %d (%s)", tt.method, w.Code, tt.want, w.Body.String())
+			}
+		})
+	}
+}
+
+// "*" allows any origin but browsers still need a custom header
+func TestMiddlewareWildcard(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(Middleware([]string{"*"}))
+	r.POST("/x", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	for _, tt := range []struct {
+		header string
+		want   int
+	}{{"", http.StatusForbidden}, {"X-Requested-With", http.StatusOK}} {
+		req := httptest.NewRequest(http.MethodPost, "/x", nil)
+		req.Header.Set("Origin", "https://anywhere.example")
+		if tt.header != "" {
+			req.Header.Set(tt.header, "1")
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != tt.want {
+			t.Errorf("header %q: %d, want %d", tt.header, w.Code, tt.want)
+		}
+	}
+}
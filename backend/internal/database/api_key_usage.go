@@ -0,0 +1,64 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"real-estate-portal/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Quota kinds counted per API key
+const (
+	QuotaKindScrape  = "scrape"  // synchronous scrape-triggering requests
+	QuotaKindEnqueue = "enqueue" // items added to the detail queue
+)
+
+// quotaColumn maps a quota kind to its counter column
+func quotaColumn(kind string) (string, error) {
+	switch kind {
+	case QuotaKindScrape:
+		return "scrape_count", nil
+	case QuotaKindEnqueue:
+		return "enqueue_count", nil
+	}
+	return "", fmt.Errorf("unknown quota kind: %s", kind)
+}
+
+// GetAPIKeyUsage returns the counters for a key on a day (zero counters if no row yet)
+func (gdb *GormDB) GetAPIKeyUsage(keyName, day string) (*models.APIKeyUsage, error) {
+	var usage models.APIKeyUsage
+	err := gdb.db.Where("key_name = ? AND day = ?", keyName, day).First(&usage).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return &models.APIKeyUsage{KeyName: keyName, Day: day}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &usage, nil
+}
+
+// IncrementAPIKeyUsage adds n to one counter for a key on a day, creating the row if needed
+func (gdb *GormDB) IncrementAPIKeyUsage(keyName, day, kind string, n int) error {
+	column, err := quotaColumn(kind)
+	if err != nil {
+		return err
+	}
+
+	usage := models.APIKeyUsage{KeyName: keyName, Day: day}
+	if kind == QuotaKindScrape {
+		usage.ScrapeCount = n
+	} else {
+		usage.EnqueueCount = n
+	}
+
+	return gdb.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "key_name"}, {Name: "day"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			column:       gorm.Expr(column+" + ?", n),
+			"updated_at": time.Now(), // bound, not MySQL's NOW(), so the upsert runs on any dialect
+		}),
+	}).Create(&usage).Error
+}
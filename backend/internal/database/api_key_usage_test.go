@@ -0,0 +1,51 @@
+package database_test
+
+import (
+	"real-estate-portal/internal/database"
+	"real-estate-portal/internal/sqlitetest"
+	"testing"
+)
+
+// Counters are kept per key, per day and per kind; the upsert adds to an existing row
+func TestIncrementAPIKeyUsage(t *testing.T) {
+	gdb := database.NewGormDBFromDB(sqlitetest.Open(t))
+
+	increments := []struct {
+		key, day, kind string
+		n              int
+	}{
+		{"crawler", "2026-10-16", database.QuotaKindEnqueue, 3},
+		{"crawler", "2026-10-16", database.QuotaKindEnqueue, 2},
+		{"crawler", "2026-10-16", database.QuotaKindScrape, 1},
+		{"crawler", "2026-10-17", database.QuotaKindEnqueue, 1},
+		{"admin", "2026-10-16", database.QuotaKindScrape, 4},
+	}
+	for _, inc := range increments {
+		if err := gdb.IncrementAPIKeyUsage(inc.key, inc.day, inc.kind, inc.n); err != nil {
+			t.Fatalf("increment %+v: %v", inc, err)
+		}
+	}
+
+	tests := []struct {
+		key, day              string
+		wantScrape, wantQueue int
+	}{
+		{"crawler", "2026-10-16", 1, 5},
+		{"crawler", "2026-10-17", 0, 1},
+		{"admin", "2026-10-16", 4, 0},
+		{"unknown", "2026-10-16", 0, 0},
+	}
+	for _, tt := range tests {
+		usage, err := gdb.GetAPIKeyUsage(tt.key, tt.day)
+		if err != nil {
+			t.Fatalf("%s %s: %v", tt.key, tt.day, err)
+		}
+		if usage.KeyName != tt.key || usage.Day != tt.day || usage.ScrapeCount != tt.wantScrape || usage.EnqueueCount != tt.wantQueue {
+			t.Errorf("%s %s: %+v (want scrape %d, enqueue %d)", tt.key, tt.day, usage, tt.wantScrape, tt.wantQueue)
+		}
+	}
+
+	if err := gdb.IncrementAPIKeyUsage("crawler", "2026-10-16", "export", 1); err == nil {
+		t.Error("unknown quota kind accepted")
+	}
+}
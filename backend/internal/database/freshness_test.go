@@ -0,0 +1,94 @@
+package database_test
+
+import (
+	"fmt"
+	"real-estate-portal/internal/database"
+	"real-estate-portal/internal/models"
+	"real-estate-portal/internal/sqlitetest"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// countChangeQueries counts SELECTs against property_changes
+func countChangeQueries(t *testing.T, db *gorm.DB) *int {
+	n := new(int)
+	err := db.Callback().Query().After("gorm:query").Register("test:count_changes", func(tx *gorm.DB) {
+		if tx.Statement.Table == "property_changes" {
+			*n++
+		}
+	})
+	if err != nil {
+		t.Fatalf("register callback: %v", err)
+	}
+	return n
+}
+
+func TestAttachFreshnessRecentPriceChange(t *testing.T) {
+	db := sqlitetest.Open(t)
+	gdb := database.NewGormDBFromDB(db)
+	window := models.DefaultFreshnessThresholds.PriceChangeWindow
+	now := time.Now()
+
+	changes := []models.PropertyChange{
+		{PropertyID: "recent", ChangeType: models.ChangeTypeRent, DetectedAt: now.Add(-time.Hour)},
+		{PropertyID: "edge", ChangeType: models.ChangeTypeRent, DetectedAt: now.Add(-window + time.Minute)},
+		{PropertyID: "old", ChangeType: models.ChangeTypeRent, DetectedAt: now.Add(-window - time.Minute)},
+		{PropertyID: "status", ChangeType: models.ChangeTypeStatus, DetectedAt: now.Add(-time.Hour)},
+		{PropertyID: "twice", ChangeType: models.ChangeTypeRent, DetectedAt: now.Add(-2 * time.Hour)},
+		{PropertyID: "twice", ChangeType: models.ChangeTypeRent, DetectedAt: now.Add(-time.Hour)},
+	}
+	for i := range changes {
+		changes[i].SnapshotID = 1
+	}
+	if err := db.Create(&changes).Error; err != nil {
+		t.Fatalf("seed changes: %v", err)
+	}
+
+	want := map[string]bool{"recent": true, "edge": true, "old": false, "status": false, "twice": true, "none": false}
+	properties := make([]models.Property, 0, len(want))
+	for id := range want {
+		properties = append(properties, models.Property{ID: id, FetchedAt: now})
+	}
+	if err := gdb.AttachFreshness(properties, models.DefaultFreshnessThresholds); err != nil {
+		t.Fatalf("attach: %v", err)
+	}
+	for _, p := range properties {
+		if p.HasRecentPriceChange != want[p.ID] {
+			t.Errorf("%s: has_recent_price_change = %v (want %v)", p.ID, p.HasRecentPriceChange, want[p.ID])
+		}
+		if p.Freshness != models.FreshnessFresh {
+			t.Errorf("%s: freshness = %q (want fresh)", p.ID, p.Freshness)
+		}
+	}
+}
+
+// The change lookup is one query per page, whatever the page size (no N+1)
+func TestAttachFreshnessSingleQuery(t *testing.T) {
+	db := sqlitetest.Open(t)
+	gdb := database.NewGormDBFromDB(db)
+	queries := countChangeQueries(t, db)
+
+	for _, size := range []int{1, 20, 100} {
+		properties := make([]models.Property, size)
+		for i := range properties {
+			properties[i] = models.Property{ID: fmt.Sprintf("p%d", i), FetchedAt: time.Now()}
+		}
+		*queries = 0
+		if err := gdb.AttachFreshness(properties, models.DefaultFreshnessThresholds); err != nil {
+			t.Fatalf("page of %d: %v", size, err)
+		}
+		if *queries != 1 {
+			t.Errorf("page of %d: %d property_changes queries (want 1)", size, *queries)
+		}
+	}
+
+	*queries = 0
+	if err := gdb.AttachFreshness(nil, models.DefaultFreshnessThresholds); err != nil {
+		t.Fatalf("empty page: %v", err)
+	}
+	if *queries != 0 {
+		t.Errorf("empty page: %d queries (want 0)", *queries)
+	}
+}
@@ -0,0 +1,93 @@
+package database_test
+
+import (
+	"fmt"
+	"real-estate-portal/internal/database"
+	"real-estate-portal/internal/models"
+	"real-estate-portal/internal/sqlitetest"
+	"sort"
+	"testing"
+)
+
+// saveWithLines saves an active listing reachable from the given lines
+func saveWithLines(t *testing.T, gdb *database.GormDB, id string, lines ...string) {
+	t.Helper()
+	p := &models.Property{Source: "yahoo", SourcePropertyID: id, DetailURL: "https://realestate.example/rent/detail/" + id + "/",
+		Title: "物件" + id}
+	p.ID = id
+	stations := make([]models.PropertyStation, len(lines))
+	for i, line := range lines {
+		stations[i] = models.PropertyStation{PropertyID: id, StationName: "駅" + id, LineName: line, SortOrder: i + 1}
+	}
+	if err := gdb.SavePropertyWithStations(p, stations); err != nil {
+		t.Fatalf("save %s: %v", id, err)
+	}
+}
+
+// Two lines spelled with mixed operator prefixes filter, count and index as one line each
+func TestLinesWithMixedPrefixes(t *testing.T) {
+	db := sqlitetest.Open(t)
+	gdb := database.NewGormDBFromDB(db)
+	saveWithLines(t, gdb, "a1", "ＪＲ山手線")
+	saveWithLines(t, gdb, "b1", "JR 山手線", "東急東横線")
+	saveWithLines(t, gdb, "c1", "東急東横線")
+
+	t.Run("filter", func(t *testing.T) {
+		for _, tt := range []struct {
+			line string
+			want string
+		}{
+			{"JR山手線", "[a1 b1]"},
+			{"ＪＲ山手線", "[a1 b1]"},
+			{"JR 山手線", "[a1 b1]"},
+			{"東急東横線", "[b1 c1]"},
+		} {
+			page, err := gdb.GetPropertiesWithFiltersPaginated(database.PropertyFilters{Line: tt.line, Limit: 50})
+			if err != nil {
+				t.Fatalf("filter %q: %v", tt.line, err)
+			}
+			var ids []string
+			for _, p := range page.Properties {
+				ids = append(ids, p.ID)
+			}
+			sort.Strings(ids)
+			if got := fmt.Sprint(ids); got != tt.want {
+				t.Errorf("line %q: %s (want %s)", tt.line, got, tt.want)
+			}
+		}
+	})
+
+	// A row written before stations were normalized on save counts and indexes under the
+	// normalized name
+	if err := db.Create(&models.PropertyStation{PropertyID: "c1", StationName: "渋谷", LineName: "ＪＲ 山手線", SortOrder: 9}).Error; err != nil {
+		t.Fatalf("legacy station: %v", err)
+	}
+
+	t.Run("counts", func(t *testing.T) {
+		counts, err := gdb.GetLineCounts()
+		if err != nil {
+			t.Fatalf("GetLineCounts: %v", err)
+		}
+		if got := fmt.Sprint(counts); got != "[{JR山手線 3} {東急東横線 2}]" {
+			t.Errorf("line counts %s (want JR山手線 3, 東急東横線 2)", got)
+		}
+	})
+
+	t.Run("index", func(t *testing.T) {
+		properties, err := gdb.GetPropertiesByIDs([]string{"a1", "b1", "c1"})
+		if err != nil {
+			t.Fatalf("read: %v", err)
+		}
+		if err := gdb.AttachLines(properties); err != nil {
+			t.Fatalf("AttachLines: %v", err)
+		}
+		got := map[string]string{}
+		for _, p := range properties {
+			got[p.ID] = fmt.Sprint(p.Lines)
+		}
+		want := map[string]string{"a1": "[JR山手線]", "b1": "[JR山手線 東急東横線]", "c1": "[東急東横線 JR山手線]"}
+		if fmt.Sprint(got) != fmt.Sprint(want) {
+			t.Errorf("indexed lines %v (want %v)", got, want)
+		}
+	})
+}
@@ -0,0 +1,212 @@
+package database
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ListingCacheTTL is how long a cached default listing page stays valid
+const ListingCacheTTL = 30 * time.Second
+
+// listingCacheMaxEntries bounds the number of cached pages (sort x limit x offset)
+const listingCacheMaxEntries = 64
+
+// listingCache is a small read-through cache for the unfiltered listing page
+// (the landing page). It is shared by every GormDB in the process so that saves
+// from the queue worker invalidate pages served by the API.
+type listingCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[string]listingCacheEntry
+	hits    int64
+	misses  int64
+
+	// shared replaces entries when set, so replicas serve and invalidate one cache
+	shared SharedCache
+}
+
+// SharedCache is an out-of-process byte cache (e.g. Redis) used instead of the
+// in-memory entries when configured
+type SharedCache interface {
+	Get(key string) ([]byte, bool, error)
+	Set(key string, value []byte, ttl time.Duration) error
+	DeletePrefix(prefix string) error
+}
+
+// listingCachePrefix namespaces listing pages in the shared cache
+const listingCachePrefix = "listing:"
+
+// SetSharedListingCache switches the listing cache to a shared backend (nil restores in-memory)
+func SetSharedListingCache(shared SharedCache) {
+	defaultListingCache.mu.Lock()
+	defer defaultListingCache.mu.Unlock()
+	defaultListingCache.shared = shared
+	defaultListingCache.entries = make(map[string]listingCacheEntry)
+}
+
+type listingCacheEntry struct {
+	response  *PaginatedPropertiesResponse
+	expiresAt time.Time
+}
+
+// ListingCacheStats holds hit/miss counters for the listing cache
+type ListingCacheStats struct {
+	Hits    int64   `json:"hits"`
+	Misses  int64   `json:"misses"`
+	HitRate float64 `json:"hit_rate"`
+	Entries int     `json:"entries"`
+	TTL     string  `json:"ttl"`
+}
+
+var defaultListingCache = newListingCache(ListingCacheTTL)
+
+func newListingCache(ttl time.Duration) *listingCache {
+	return &listingCache{
+		ttl:     ttl,
+		entries: make(map[string]listingCacheEntry),
+	}
+}
+
+// listingCacheKey returns the cache key for filters, or "" if the request is not cacheable.
+// Only the default listing (no filters, no cursor) is cached; key includes sort and page.
+func listingCacheKey(f PropertyFilters) string {
+	if f.Station != "" || f.Line != "" || f.MaxWalk > 0 || f.Cursor != "" ||
+		f.MinRent != nil || f.MaxRent != nil || f.MinArea != nil || f.MaxArea != nil ||
+		f.MinBuildingAge != nil || f.MaxBuildingAge != nil || f.MinFloor != nil || f.MaxFloor != nil ||
+		f.MinBuildingFloors != nil || f.MaxBuildingFloors != nil ||
+		len(f.FloorPlans) > 0 || len(f.BuildingTypes) > 0 || len(f.Facilities) > 0 ||
+		len(f.ExcludeIDs) > 0 || len(f.ExcludeStatuses) > 0 {
+		return ""
+	}
+
+	offset := 0
+	if f.Offset != nil {
+		offset = *f.Offset
+	}
+	return fmt.Sprintf("%s|%d|%d", f.SortBy, f.Limit, offset)
+}
+
+func (c *listingCache) get(key string) (*PaginatedPropertiesResponse, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.shared != nil {
+		data, ok, err := c.shared.Get(listingCachePrefix + key)
+		if err != nil {
+			log.Printf("[ListingCache] shared get failed: %v", err)
+		}
+		var response PaginatedPropertiesResponse
+		if !ok || err != nil || json.Unmarshal(data, &response) != nil {
+			c.misses++
+			return nil, false
+		}
+		c.hits++
+		return &response, true
+	}
+
+	entry, ok := c.entries[key]
+	if !ok || time.Now().After(entry.expiresAt) {
+		delete(c.entries, key)
+		c.misses++
+		return nil, false
+	}
+	c.hits++
+	return entry.response, true
+}
+
+func (c *listingCache) set(key string, response *PaginatedPropertiesResponse) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.shared != nil {
+		data, err := json.Marshal(response)
+		if err == nil {
+			err = c.shared.Set(listingCachePrefix+key, data, c.ttl)
+		}
+		if err != nil {
+			log.Printf("[ListingCache] shared set failed: %v", err)
+		}
+		return
+	}
+
+	if len(c.entries) >= listingCacheMaxEntries {
+		// Simple bound: drop everything rather than tracking LRU order
+		c.entries = make(map[string]listingCacheEntry)
+	}
+	c.entries[key] = listingCacheEntry{
+		response:  response,
+		expiresAt: time.Now().Add(c.ttl),
+	}
+}
+
+// invalidate drops all cached pages (called after any property write)
+func (c *listingCache) invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[string]listingCacheEntry)
+
+	if c.shared != nil {
+		if err := c.shared.DeletePrefix(listingCachePrefix); err != nil {
+			log.Printf("[ListingCache] shared invalidate failed: %v", err)
+		}
+	}
+}
+
+func (c *listingCache) stats() ListingCacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats := ListingCacheStats{
+		Hits:    c.hits,
+		Misses:  c.misses,
+		Entries: len(c.entries),
+		TTL:     c.ttl.String(),
+	}
+	if total := c.hits + c.misses; total > 0 {
+		stats.HitRate = float64(c.hits) / float64(total)
+	}
+	return stats
+}
+
+// listingCacheCallback names the GORM callbacks that drop cached pages after property writes
+const listingCacheCallback = "listing_cache:invalidate"
+
+// registerListingCacheCallbacks invalidates the listing cache after every create, update or
+// delete on the properties table made through db. This covers writers holding the raw
+// *gorm.DB (light refresh, devseed, queue status updates), not only GormDB methods.
+func registerListingCacheCallbacks(db *gorm.DB) {
+	cb := db.Callback()
+	if cb.Create().Get(listingCacheCallback) != nil {
+		return // already registered on this connection
+	}
+
+	invalidate := func(tx *gorm.DB) {
+		if tx.Error == nil && tx.RowsAffected > 0 && tx.Statement.Table == "properties" {
+			defaultListingCache.invalidate()
+		}
+	}
+	for _, err := range []error{
+		cb.Create().After("gorm:create").Register(listingCacheCallback, invalidate),
+		cb.Update().After("gorm:update").Register(listingCacheCallback, invalidate),
+		cb.Delete().After("gorm:delete").Register(listingCacheCallback, invalidate),
+	} {
+		if err != nil {
+			log.Printf("[ListingCache] register callback failed: %v", err)
+		}
+	}
+}
+
+// GetListingCacheStats returns hit/miss counters for the default listing cache
+func (gdb *GormDB) GetListingCacheStats() ListingCacheStats {
+	return defaultListingCache.stats()
+}
+
+// InvalidateListingCache drops cached listing pages
+func (gdb *GormDB) InvalidateListingCache() {
+	defaultListingCache.invalidate()
+}
@@ -0,0 +1,118 @@
+package database
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestListingCacheKey(t *testing.T) {
+	rent, offset := 10, 40
+	tests := []struct {
+		name    string
+		filters PropertyFilters
+		want    string
+	}{
+		{"landing page", PropertyFilters{SortBy: "created_at_desc", Limit: 20}, "created_at_desc|20|0"},
+		{"second page", PropertyFilters{SortBy: "rent_asc", Limit: 20, Offset: &offset}, "rent_asc|20|40"},
+		{"station filter", PropertyFilters{Station: "渋谷", Limit: 20}, ""},
+		{"rent filter", PropertyFilters{MaxRent: &rent, Limit: 20}, ""},
+		{"floor plans", PropertyFilters{FloorPlans: []string{"1K"}, Limit: 20}, ""},
+		{"cursor page", PropertyFilters{Cursor: "abc", Limit: 20}, ""},
+		{"excluded ids", PropertyFilters{ExcludeIDs: []string{"p1"}, Limit: 20}, ""},
+	}
+	for _, tt := range tests {
+		if got := listingCacheKey(tt.filters); got != tt.want {
+			t.Errorf("%s: key %q (want %q)", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestListingCacheExpiresAndCounts(t *testing.T) {
+	c := newListingCache(20 * time.Millisecond)
+	page := &PaginatedPropertiesResponse{}
+
+	if _, ok := c.get("k"); ok {
+		t.Fatal("hit on an empty cache")
+	}
+	c.set("k", page)
+	if got, ok := c.get("k"); !ok || got != page {
+		t.Fatal("miss right after set")
+	}
+	time.Sleep(30 * time.Millisecond)
+	if _, ok := c.get("k"); ok {
+		t.Error("hit after the TTL")
+	}
+
+	stats := c.stats()
+	if stats.Hits != 1 || stats.Misses != 2 || stats.Entries != 0 || stats.HitRate != 1.0/3 {
+		t.Errorf("stats = %+v (want 1 hit, 2 misses, no entries)", stats)
+	}
+}
+
+func TestListingCacheBounded(t *testing.T) {
+	c := newListingCache(time.Minute)
+	for i := 0; i < listingCacheMaxEntries; i++ {
+		c.set(fmt.Sprint(i), &PaginatedPropertiesResponse{})
+	}
+	if n := c.stats().Entries; n != listingCacheMaxEntries {
+		t.Fatalf("%d entries (want %d)", n, listingCacheMaxEntries)
+	}
+	c.set("one more", &PaginatedPropertiesResponse{})
+	if n := c.stats().Entries; n != 1 {
+		t.Errorf("%d entries after exceeding the bound (want 1)", n)
+	}
+
+	c.invalidate()
+	if n := c.stats().Entries; n != 0 {
+		t.Errorf("%d entries after invalidate", n)
+	}
+}
+
+// mapSharedCache is an in-memory SharedCache
+type mapSharedCache map[string][]byte
+
+func (m mapSharedCache) Get(key string) ([]byte, bool, error) {
+	v, ok := m[key]
+	return v, ok, nil
+}
+
+func (m mapSharedCache) Set(key string, value []byte, _ time.Duration) error {
+	m[key] = value
+	return nil
+}
+
+func (m mapSharedCache) DeletePrefix(prefix string) error {
+	for k := range m {
+		if strings.HasPrefix(k, prefix) {
+			delete(m, k)
+		}
+	}
+	return nil
+}
+
+// With a shared backend, pages go through it under the listing: prefix, and invalidate
+// clears only that prefix
+func TestListingCacheShared(t *testing.T) {
+	shared := mapSharedCache{"other:key": []byte("x")}
+	c := newListingCache(time.Minute)
+	c.shared = shared
+
+	c.set("created_at_desc|20|0", &PaginatedPropertiesResponse{Total: 7})
+	if _, ok := shared["listing:created_at_desc|20|0"]; !ok {
+		t.Fatalf("page not stored in the shared cache: %v", shared)
+	}
+	got, ok := c.get("created_at_desc|20|0")
+	if !ok || got.Total != 7 {
+		t.Fatalf("shared get = %+v, %v", got, ok)
+	}
+
+	c.invalidate()
+	if _, ok := c.get("created_at_desc|20|0"); ok {
+		t.Error("hit after invalidate")
+	}
+	if _, ok := shared["other:key"]; !ok {
+		t.Error("invalidate removed keys outside the listing prefix")
+	}
+}
@@ -0,0 +1,149 @@
+package database_test
+
+import (
+	"fmt"
+	"real-estate-portal/internal/database"
+	"real-estate-portal/internal/models"
+	"real-estate-portal/internal/sqlitetest"
+	"sort"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+func rentProperty(id string, rent int) *models.Property {
+	return &models.Property{
+		Source:           "yahoo",
+		SourcePropertyID: id,
+		DetailURL:        "https://realestate.example/rent/detail/" + id + "/",
+		Title:            "メゾン " + id,
+		Rent:             &rent,
+		FloorPlan:        "1K",
+		Status:           models.PropertyStatusActive,
+	}
+}
+
+// landingPage loads the default listing and reports whether it was served from the cache
+func landingPage(t *testing.T, gdb *database.GormDB) (*database.PaginatedPropertiesResponse, bool) {
+	t.Helper()
+	before := gdb.GetListingCacheStats().Hits
+	page, err := gdb.GetPropertiesWithFiltersPaginated(database.PropertyFilters{})
+	if err != nil {
+		t.Fatalf("listing: %v", err)
+	}
+	return page, gdb.GetListingCacheStats().Hits > before
+}
+
+func TestListingCacheInvalidatedByRawWrites(t *testing.T) {
+	tests := []struct {
+		name  string
+		write func(db *gorm.DB, id string) error
+		want  string // sorted rents on the landing page after the write
+	}{
+		{
+			name: "update through raw gorm",
+			write: func(db *gorm.DB, id string) error {
+				return db.Model(&models.Property{}).Where("id = ?", id).Update("rent", 75000).Error
+			},
+			want: "[75000]",
+		},
+		{
+			name: "delete through raw gorm",
+			write: func(db *gorm.DB, id string) error {
+				return db.Where("id = ?", id).Delete(&models.Property{}).Error
+			},
+			want: "[]",
+		},
+		{
+			name: "create through raw gorm",
+			write: func(db *gorm.DB, id string) error {
+				p := rentProperty("raw2", 90000)
+				p.ID = "raw2"
+				return db.Create(p).Error
+			},
+			want: "[80000 90000]",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := sqlitetest.Open(t)
+			gdb := database.NewGormDBFromDB(db)
+			gdb.InvalidateListingCache()
+
+			p := rentProperty("raw1", 80000)
+			if err := gdb.SaveProperty(p); err != nil {
+				t.Fatalf("save: %v", err)
+			}
+			if _, hit := landingPage(t, gdb); hit {
+				t.Fatal("first load after save was a cache hit")
+			}
+			if _, hit := landingPage(t, gdb); !hit {
+				t.Fatal("second load was not a cache hit")
+			}
+
+			if err := tt.write(db, p.ID); err != nil {
+				t.Fatalf("write: %v", err)
+			}
+			page, hit := landingPage(t, gdb)
+			if hit {
+				t.Error("load after write was a cache hit")
+			}
+			var rents []int
+			for _, q := range page.Properties {
+				rents = append(rents, *q.Rent)
+			}
+			sort.Ints(rents)
+			if got := fmt.Sprint(rents); got != tt.want {
+				t.Errorf("rents %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestListingCacheIgnoresOtherTables(t *testing.T) {
+	db := sqlitetest.Open(t)
+	gdb := database.NewGormDBFromDB(db)
+	gdb.InvalidateListingCache()
+
+	p := rentProperty("other1", 80000)
+	if err := gdb.SaveProperty(p); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	landingPage(t, gdb)
+
+	if err := db.Create(&models.PropertyStation{PropertyID: p.ID, StationName: "中野", LineName: "JR中央線"}).Error; err != nil {
+		t.Fatalf("station: %v", err)
+	}
+	if _, hit := landingPage(t, gdb); !hit {
+		t.Error("a property_stations write dropped the listing cache")
+	}
+}
+
+func BenchmarkListingCache(b *testing.B) {
+	db := sqlitetest.Open(b)
+	gdb := database.NewGormDBFromDB(db)
+	for i := 0; i < 50; i++ {
+		if err := gdb.SaveProperty(rentProperty(fmt.Sprintf("bench%02d", i), 60000+i*1000)); err != nil {
+			b.Fatalf("save: %v", err)
+		}
+	}
+
+	b.Run("hit", func(b *testing.B) {
+		gdb.InvalidateListingCache()
+		gdb.GetPropertiesWithFiltersPaginated(database.PropertyFilters{})
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			if _, err := gdb.GetPropertiesWithFiltersPaginated(database.PropertyFilters{}); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("miss", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			gdb.InvalidateListingCache()
+			if _, err := gdb.GetPropertiesWithFiltersPaginated(database.PropertyFilters{}); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
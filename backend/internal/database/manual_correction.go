@@ -0,0 +1,124 @@
+package database
+
+import (
+	"encoding/json"
+	"fmt"
+	"real-estate-portal/internal/models"
+	"sort"
+
+	"gorm.io/gorm"
+)
+
+// preserveManualCorrections keeps manually corrected (locked) fields from the stored row
+// when a scrape updates a property, and recomputes the values derived from them
+func preserveManualCorrections(p, existing *models.Property) {
+	if p.ApplyLockedFields(existing) {
+		p.NormalizeFees()
+		p.NormalizeFloors()
+		p.NormalizeWalkTimeBucket()
+		p.Fingerprint = p.ComputeFingerprint()
+	}
+}
+
+// CorrectProperty applies manual corrections to a property, locks the corrected fields
+// against re-scrapes and records one audit row per field
+func (gdb *GormDB) CorrectProperty(id string, values map[string]json.RawMessage, author, reason string) (*models.Property, []models.ManualCorrection, error) {
+	if len(values) == 0 {
+		return nil, nil, fmt.Errorf("no fields to correct")
+	}
+
+	// Apply in a stable order so audit rows are deterministic
+	fields := make([]string, 0, len(values))
+	for field := range values {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	var property models.Property
+	var corrections []models.ManualCorrection
+
+	err := gdb.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("id = ?", id).First(&property).Error; err != nil {
+			return err
+		}
+
+		for _, field := range fields {
+			oldValue, err := property.FieldValueString(field)
+			if err != nil {
+				return err
+			}
+			if err := property.SetFieldJSON(field, values[field]); err != nil {
+				return err
+			}
+			newValue, _ := property.FieldValueString(field)
+
+			property.LockField(field)
+			corrections = append(corrections, models.ManualCorrection{
+				PropertyID: property.ID,
+				Field:      field,
+				Action:     models.CorrectionActionSet,
+				OldValue:   oldValue,
+				NewValue:   newValue,
+				Author:     author,
+				Reason:     reason,
+			})
+		}
+
+		property.NormalizeFees()
+		property.NormalizeFloors()
+		property.NormalizeWalkTimeBucket()
+		property.Fingerprint = property.ComputeFingerprint()
+		if err := tx.Save(&property).Error; err != nil {
+			return err
+		}
+		return tx.Create(&corrections).Error
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	defaultListingCache.invalidate()
+	return &property, corrections, nil
+}
+
+// UnlockCorrection removes the lock on a manually corrected field so the next scrape
+// may overwrite it again. The current value is kept until then.
+func (gdb *GormDB) UnlockCorrection(id, field, author string) (*models.Property, error) {
+	var property models.Property
+
+	err := gdb.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("id = ?", id).First(&property).Error; err != nil {
+			return err
+		}
+		if !property.UnlockField(field) {
+			return fmt.Errorf("%w: %s", models.ErrFieldNotLocked, field)
+		}
+
+		if err := tx.Model(&models.Property{}).Where("id = ?", id).
+			UpdateColumn("locked_fields", property.LockedFields).Error; err != nil {
+			return err
+		}
+
+		currentValue, _ := property.FieldValueString(field)
+		return tx.Create(&models.ManualCorrection{
+			PropertyID: id,
+			Field:      field,
+			Action:     models.CorrectionActionUnlock,
+			OldValue:   currentValue,
+			Author:     author,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	defaultListingCache.invalidate()
+	return &property, nil
+}
+
+// GetManualCorrections returns the correction audit trail for a property (newest first)
+func (gdb *GormDB) GetManualCorrections(id string) ([]models.ManualCorrection, error) {
+	var corrections []models.ManualCorrection
+	err := gdb.db.Where("property_id = ?", id).Order("created_at DESC, id DESC").Find(&corrections).Error
+	return corrections, err
+}
@@ -0,0 +1,158 @@
+package database_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"real-estate-portal/internal/database"
+	"real-estate-portal/internal/models"
+	"real-estate-portal/internal/sqlitetest"
+	"testing"
+)
+
+func rentPtr(v int) *int { return &v }
+
+// rescrape returns the listing as a scrape would see it: typo'd area, new rent
+func rescrape(p *models.Property, area float64, rent int) *models.Property {
+	return &models.Property{
+		Source:           p.Source,
+		SourcePropertyID: p.SourcePropertyID,
+		DetailURL:        p.DetailURL,
+		Title:            p.Title,
+		ImageURL:         p.ImageURL,
+		FloorPlan:        p.FloorPlan,
+		Address:          p.Address,
+		Area:             floatPtr(area),
+		Rent:             rentPtr(rent),
+	}
+}
+
+// A manual correction holds across re-scrapes on every save path, and unlocking
+// lets the next scrape overwrite the field again
+func TestManualCorrectionSurvivesRescrape(t *testing.T) {
+	tests := []struct {
+		name string
+		// save stores a re-scraped listing; prev is the stored listing it replaces
+		save func(t *testing.T, gdb *database.GormDB, prev *models.Property, area float64, rent int) *models.Property
+	}{
+		{"SaveProperty", func(t *testing.T, gdb *database.GormDB, prev *models.Property, area float64, rent int) *models.Property {
+			p := rescrape(prev, area, rent)
+			if err := gdb.SaveProperty(p); err != nil {
+				t.Fatalf("save: %v", err)
+			}
+			return p
+		}},
+		{"SavePropertyWithStations", func(t *testing.T, gdb *database.GormDB, prev *models.Property, area float64, rent int) *models.Property {
+			p := rescrape(prev, area, rent)
+			if err := gdb.SavePropertyWithStations(p, nil); err != nil {
+				t.Fatalf("save: %v", err)
+			}
+			return p
+		}},
+		{"SavePropertyWithStationsAndImages", func(t *testing.T, gdb *database.GormDB, prev *models.Property, area float64, rent int) *models.Property {
+			p := rescrape(prev, area, rent)
+			if err := gdb.SavePropertyWithStationsAndImages(p, nil, nil); err != nil {
+				t.Fatalf("save: %v", err)
+			}
+			return p
+		}},
+		{"redirect move", func(t *testing.T, gdb *database.GormDB, prev *models.Property, area float64, rent int) *models.Property {
+			// The old detail URL redirects to a new listing ID; the row is moved, then saved under the new URL
+			moved := unit(prev.SourcePropertyID + "x")
+			if err := gdb.RecordPropertyRedirect(context.Background(), "yahoo", prev.SourcePropertyID, moved.SourcePropertyID, moved.DetailURL); err != nil {
+				t.Fatalf("redirect: %v", err)
+			}
+			p := rescrape(moved, area, rent)
+			if err := gdb.SaveProperty(p); err != nil {
+				t.Fatalf("save: %v", err)
+			}
+			return p
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := sqlitetest.Open(t)
+			gdb := database.NewGormDBFromDB(db)
+
+			first := unit("c1")
+			first.Rent = rentPtr(80000)
+			if err := gdb.SaveProperty(first); err != nil {
+				t.Fatalf("save: %v", err)
+			}
+			id := first.ID
+
+			if _, _, err := gdb.CorrectProperty(id, map[string]json.RawMessage{"area": json.RawMessage("30")}, "admin", "source typo"); err != nil {
+				t.Fatalf("correct: %v", err)
+			}
+
+			check := func(step string, wantArea float64, wantRent int, wantLocked bool) {
+				t.Helper()
+				saved, err := gdb.GetPropertyByID(id)
+				if err != nil {
+					t.Fatalf("%s: read: %v", step, err)
+				}
+				if saved.Area == nil || *saved.Area != wantArea {
+					t.Errorf("%s: area = %v (want %v)", step, fmtFloat(saved.Area), wantArea)
+				}
+				if saved.Rent == nil || *saved.Rent != wantRent {
+					t.Errorf("%s: rent = %v (want %d); unlocked fields should follow the scrape", step, saved.Rent, wantRent)
+				}
+				if saved.IsFieldLocked("area") != wantLocked {
+					t.Errorf("%s: area locked = %v (want %v)", step, !wantLocked, wantLocked)
+				}
+			}
+
+			prev := tt.save(t, gdb, first, 40, 82000)
+			check("re-scrape while locked", 30, 82000, true)
+			prev = tt.save(t, gdb, prev, 41, 83000)
+			check("second re-scrape while locked", 30, 83000, true)
+
+			if _, err := gdb.UnlockCorrection(id, "area", "admin"); err != nil {
+				t.Fatalf("unlock: %v", err)
+			}
+			check("unlocked, before the next scrape", 30, 83000, false)
+			tt.save(t, gdb, prev, 42, 84000)
+			check("re-scrape after unlock", 42, 84000, false)
+
+			audit, err := gdb.GetManualCorrections(id)
+			if err != nil {
+				t.Fatalf("audit: %v", err)
+			}
+			if len(audit) != 2 {
+				t.Fatalf("%d audit rows (want set + unlock): %+v", len(audit), audit)
+			}
+			unlock, set := audit[0], audit[1]
+			if set.Field != "area" || set.Action != models.CorrectionActionSet || set.OldValue != "25.5" || set.NewValue != "30" ||
+				set.Author != "admin" || set.Reason != "source typo" {
+				t.Errorf("set row = %+v", set)
+			}
+			if unlock.Field != "area" || unlock.Action != models.CorrectionActionUnlock || unlock.OldValue != "30" {
+				t.Errorf("unlock row = %+v", unlock)
+			}
+		})
+	}
+}
+
+func TestUnlockCorrectionNotLocked(t *testing.T) {
+	gdb := database.NewGormDBFromDB(sqlitetest.Open(t))
+	p := unit("c2")
+	if err := gdb.SaveProperty(p); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	if _, err := gdb.UnlockCorrection(p.ID, "area", "admin"); !errors.Is(err, models.ErrFieldNotLocked) {
+		t.Errorf("unlock of an unlocked field: %v (want ErrFieldNotLocked)", err)
+	}
+	if _, _, err := gdb.CorrectProperty(p.ID, map[string]json.RawMessage{"status": json.RawMessage(`"removed"`)}, "admin", ""); !errors.Is(err, models.ErrFieldNotCorrectable) {
+		t.Errorf("correcting status: %v (want ErrFieldNotCorrectable)", err)
+	}
+	if audit, _ := gdb.GetManualCorrections(p.ID); len(audit) != 0 {
+		t.Errorf("%d audit rows after rejected requests (want 0)", len(audit))
+	}
+}
+
+func fmtFloat(f *float64) interface{} {
+	if f == nil {
+		return "nil"
+	}
+	return *f
+}
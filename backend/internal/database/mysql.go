@@ -1,15 +1,22 @@
 package database
 
 import (
+	"context"
 	"crypto/md5"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log"
 	"net/url"
 	"real-estate-portal/internal/models"
+	"real-estate-portal/internal/tracing"
+	"slices"
+	"sort"
 	"strings"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
@@ -28,6 +35,8 @@ func NewGormDB(host, port, user, password, dbname string) (*GormDB, error) {
 		NowFunc: func() time.Time {
 			return time.Now().Local()
 		},
+		// Report unique index violations (e.g. uniq_queue_active_key) as gorm.ErrDuplicatedKey
+		TranslateError: true,
 	})
 	if err != nil {
 		return nil, err
@@ -43,11 +52,13 @@ func NewGormDB(host, port, user, password, dbname string) (*GormDB, error) {
 		return nil, err
 	}
 
+	registerListingCacheCallbacks(db)
 	return &GormDB{db: db}, nil
 }
 
 // NewGormDBFromDB creates a GormDB wrapper from an existing gorm.DB instance
 func NewGormDBFromDB(db *gorm.DB) *GormDB {
+	registerListingCacheCallbacks(db)
 	return &GormDB{db: db}
 }
 
@@ -71,6 +82,14 @@ func (gdb *GormDB) GetDB() (*gorm.DB, error) {
 
 // InitSchema creates tables using GORM AutoMigrate
 func (gdb *GormDB) InitSchema() error {
+	// The active_key unique index can't be added while duplicates exist (see migration 020)
+	migrator := gdb.db.Migrator()
+	if migrator.HasTable(&models.DetailScrapeQueue{}) && !migrator.HasColumn(&models.DetailScrapeQueue{}, "active_key") {
+		if err := gdb.collapseDuplicateQueueRows(); err != nil {
+			return fmt.Errorf("collapse duplicate queue rows: %w", err)
+		}
+	}
+
 	// AutoMigrate will create tables if they don't exist
 	return gdb.db.AutoMigrate(
 		&models.Property{},
@@ -79,9 +98,59 @@ func (gdb *GormDB) InitSchema() error {
 		&models.DeleteLog{},
 		&models.DetailScrapeQueue{},
 		&models.PropertyStation{},
+		&models.APIKeyUsage{},
+		&models.ManualCorrection{},
+		&models.PropertyView{},
+		&models.PropertyFavorite{},
+		&models.SharedSearch{},
+		&models.MaintenanceState{},
+		&models.PropertyAlias{},
+		&models.ScrapingState{},
+		&models.SchedulerRun{},
 	)
 }
 
+// collapseDuplicateQueueRows keeps one pending/processing row per listing (the processing
+// one if any, else the oldest) with the highest priority among them, and deletes the rest
+func (gdb *GormDB) collapseDuplicateQueueRows() error {
+	var dups []struct {
+		Source           string
+		SourcePropertyID string
+		KeepID           int64
+		MaxPriority      int
+	}
+	err := gdb.db.Raw(`SELECT source, source_property_id,
+			COALESCE(MIN(CASE WHEN status = ? THEN id END), MIN(id)) AS keep_id,
+			MAX(priority) AS max_priority
+		FROM detail_scrape_queue
+		WHERE status IN ?
+		GROUP BY source, source_property_id
+		HAVING COUNT(*) > 1`,
+		models.QueueStatusProcessing, []string{models.QueueStatusPending, models.QueueStatusProcessing}).
+		Scan(&dups).Error
+	if err != nil {
+		return err
+	}
+
+	return gdb.db.Transaction(func(tx *gorm.DB) error {
+		for _, d := range dups {
+			if err := tx.Model(&models.DetailScrapeQueue{}).Where("id = ?", d.KeepID).
+				Update("priority", d.MaxPriority).Error; err != nil {
+				return err
+			}
+			if err := tx.Where("source = ? AND source_property_id = ? AND status IN ? AND id <> ?",
+				d.Source, d.SourcePropertyID, []string{models.QueueStatusPending, models.QueueStatusProcessing}, d.KeepID).
+				Delete(&models.DetailScrapeQueue{}).Error; err != nil {
+				return err
+			}
+		}
+		if len(dups) > 0 {
+			log.Printf("Collapsed duplicate active queue rows for %d listings", len(dups))
+		}
+		return nil
+	})
+}
+
 // SaveProperty saves or updates a property (upsert by detail_url)
 func (gdb *GormDB) SaveProperty(p *models.Property) error {
 	// Generate ID from normalized URL if not set
@@ -100,23 +169,42 @@ func (gdb *GormDB) SaveProperty(p *models.Property) error {
 		p.Status = models.PropertyStatusActive
 	}
 
+	// Numeric fee companions, structured floors, walk-time bucket, canonical list JSON, in-Japan coordinates, and fingerprint for relist detection
+	p.NormalizeFees()
+	p.NormalizeFloors()
+	p.NormalizeWalkTimeBucket()
+	p.NormalizeListFields()
+	p.NormalizeCoordinates()
+	p.Fingerprint = p.ComputeFingerprint()
+
 	// Upsert: try to create, on conflict (detail_url unique) update
 	// First try to find existing property by detail_url
 	var existing models.Property
 	result := gdb.db.Where("detail_url = ?", p.DetailURL).First(&existing)
 
+	defer defaultListingCache.invalidate()
+
 	if result.Error == gorm.ErrRecordNotFound {
-		// Create new
-		return gdb.db.Create(p).Error
+		// Create new (link to a removed listing of the same unit if any)
+		return gdb.db.Transaction(func(tx *gorm.DB) error {
+			if err := linkRelistedProperty(tx, p); err != nil {
+				return err
+			}
+			return tx.Create(p).Error
+		})
 	} else if result.Error != nil {
 		return result.Error
 	}
 
-	// Update existing (keep original CreatedAt, Status, and RemovedAt)
+	// Update existing (keep original CreatedAt, Status, RemovedAt, and relist link)
 	p.CreatedAt = existing.CreatedAt
 	p.ID = existing.ID
 	p.Status = existing.Status
 	p.RemovedAt = existing.RemovedAt
+	p.RelistedFrom = existing.RelistedFrom
+	preserveManualCorrections(p, &existing)
+	preserveValidators(p, &existing)
+	preserveCoordinates(p, &existing)
 	return gdb.db.Save(p).Error
 }
 
@@ -136,14 +224,16 @@ type PropertyFilters struct {
 	WalkMode string // "nearest" (use properties.walk_time) or "any" (use property_stations)
 
 	// Range filters
-	MinRent        *int     // Minimum rent (万円単位)
-	MaxRent        *int     // Maximum rent (万円単位)
-	MinArea        *float64 // Minimum area (㎡)
-	MaxArea        *float64 // Maximum area (㎡)
-	MinBuildingAge *int     // Minimum building age (years)
-	MaxBuildingAge *int     // Maximum building age (years)
-	MinFloor       *int     // Minimum floor
-	MaxFloor       *int     // Maximum floor
+	MinRent           *int     // Minimum rent (万円単位)
+	MaxRent           *int     // Maximum rent (万円単位)
+	MinArea           *float64 // Minimum area (㎡)
+	MaxArea           *float64 // Maximum area (㎡)
+	MinBuildingAge    *int     // Minimum building age (years)
+	MaxBuildingAge    *int     // Maximum building age (years)
+	MinFloor          *int     // Minimum floor
+	MaxFloor          *int     // Maximum floor
+	MinBuildingFloors *int     // Minimum building floors (e.g. 20 for タワー物件)
+	MaxBuildingFloors *int     // Maximum building floors
 
 	// Multi-select filters
 	FloorPlans     []string // Floor plan types (1K, 1DK, etc.)
@@ -218,6 +308,49 @@ func EncodeCursor(fetchedAt time.Time, id string) string {
 	return base64.URLEncoding.EncodeToString(jsonData)
 }
 
+// propertyOrderClauses maps each models.PropertySortKeys entry to its ORDER BY clause (MySQL syntax).
+// CASE puts NULLs last in both directions.
+var propertyOrderClauses = map[string]string{
+	models.SortNewest:          "fetched_at DESC, id DESC",
+	models.SortFetchedAt:       "fetched_at DESC, id DESC",
+	models.SortFetchedAtDesc:   "fetched_at DESC, id DESC",
+	models.SortFetchedAtAsc:    "fetched_at ASC, id ASC",
+	models.SortCreatedAtDesc:   "created_at DESC, id DESC",
+	models.SortRentAsc:         "CASE WHEN rent IS NULL THEN 1 ELSE 0 END, rent ASC",
+	models.SortRentDesc:        "CASE WHEN rent IS NULL THEN 1 ELSE 0 END, rent DESC",
+	models.SortAreaDesc:        "CASE WHEN area IS NULL THEN 1 ELSE 0 END, area DESC",
+	models.SortAreaAsc:         "CASE WHEN area IS NULL THEN 1 ELSE 0 END, area ASC",
+	models.SortWalkTimeAsc:     "CASE WHEN walk_time IS NULL THEN 1 ELSE 0 END, walk_time ASC",
+	models.SortBuildingAgeAsc:  "CASE WHEN building_age IS NULL THEN 1 ELSE 0 END, building_age ASC",
+	models.SortBuildingAgeDesc: "CASE WHEN building_age IS NULL THEN 1 ELSE 0 END, building_age DESC",
+}
+
+// propertyOrder returns the ORDER BY clause for sortBy and whether it supports cursor
+// pagination (fetched_at DESC, id DESC). Unknown or empty keys sort newest first.
+func propertyOrder(sortBy string) (clause string, cursorCompatible bool) {
+	clause, ok := propertyOrderClauses[sortBy]
+	if !ok {
+		clause = propertyOrderClauses[models.SortNewest]
+	}
+	return clause, clause == propertyOrderClauses[models.SortNewest]
+}
+
+// SortKeys returns the sort keys GetPropertiesWithSort / GetPropertiesWithFilters order by
+// (MySQL and PostgreSQL); anything else falls back to newest first
+func SortKeys() []string {
+	keys := make([]string, 0, len(propertyOrderClauses))
+	for key := range propertyOrderClauses {
+		keys = append(keys, key)
+	}
+	for key := range postgresOrderClauses {
+		if _, ok := propertyOrderClauses[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 // ValidateAndNormalize validates filter parameters and returns error if invalid
 func (f *PropertyFilters) ValidateAndNormalize() error {
 	// Validate range filters (min <= max)
@@ -233,6 +366,9 @@ func (f *PropertyFilters) ValidateAndNormalize() error {
 	if f.MinFloor != nil && f.MaxFloor != nil && *f.MinFloor > *f.MaxFloor {
 		return fmt.Errorf("min_floor cannot be greater than max_floor")
 	}
+	if f.MinBuildingFloors != nil && f.MaxBuildingFloors != nil && *f.MinBuildingFloors > *f.MaxBuildingFloors {
+		return fmt.Errorf("min_building_floors cannot be greater than max_building_floors")
+	}
 
 	// Validate array limits (prevent abuse)
 	if len(f.FloorPlans) > 20 {
@@ -248,22 +384,20 @@ func (f *PropertyFilters) ValidateAndNormalize() error {
 		return fmt.Errorf("exclude_ids: maximum 500 items allowed")
 	}
 
-	// Validate sort parameter (whitelist)
-	validSorts := map[string]bool{
-		"":                  true, // default
-		"newest":            true,
-		"fetched_at":        true,
-		"fetched_at_desc":   true,
-		"fetched_at_asc":    true,
-		"rent_asc":          true,
-		"rent_desc":         true,
-		"area_desc":         true,
-		"area_asc":          true,
-		"walk_time_asc":     true,
-		"building_age_asc":  true,
-		"building_age_desc": true,
-	}
-	if !validSorts[f.SortBy] {
+	// Validate multi-select values against the canonical lists (shared with /api/meta/options)
+	for _, plan := range f.FloorPlans {
+		if !models.IsFloorPlan(plan) {
+			return fmt.Errorf("invalid floor plan: %s", plan)
+		}
+	}
+	for _, buildingType := range f.BuildingTypes {
+		if !models.IsBuildingType(buildingType) {
+			return fmt.Errorf("invalid building type: %s", buildingType)
+		}
+	}
+
+	// Validate sort parameter (whitelist shared with /api/meta/options)
+	if !models.IsPropertySortKey(f.SortBy) {
 		return fmt.Errorf("invalid sort parameter: %s", f.SortBy)
 	}
 
@@ -314,28 +448,7 @@ func (gdb *GormDB) GetPropertiesWithFilters(filters PropertyFilters) ([]models.P
 		}
 	}
 
-	// Map sort parameter to SQL ORDER BY clause (MySQL syntax)
-	// Use CASE to put NULLs last for ASC, first for DESC
-	var orderClause string
-	switch filters.SortBy {
-	case "fetched_at", "fetched_at_desc":
-		orderClause = "fetched_at DESC"
-	case "fetched_at_asc":
-		orderClause = "fetched_at ASC"
-	case "rent_asc":
-		orderClause = "CASE WHEN rent IS NULL THEN 1 ELSE 0 END, rent ASC"
-	case "rent_desc":
-		orderClause = "CASE WHEN rent IS NULL THEN 1 ELSE 0 END, rent DESC"
-	case "area_desc":
-		orderClause = "CASE WHEN area IS NULL THEN 1 ELSE 0 END, area DESC"
-	case "walk_time_asc":
-		orderClause = "CASE WHEN walk_time IS NULL THEN 1 ELSE 0 END, walk_time ASC"
-	case "building_age_asc":
-		orderClause = "CASE WHEN building_age IS NULL THEN 1 ELSE 0 END, building_age ASC"
-	default:
-		// Default to newest first (by fetched_at)
-		orderClause = "fetched_at DESC"
-	}
+	orderClause, _ := propertyOrder(filters.SortBy)
 
 	err := query.Order(orderClause).Find(&properties).Error
 	return properties, err
@@ -355,7 +468,7 @@ func (gdb *GormDB) applyFilters(query *gorm.DB, filters PropertyFilters) *gorm.D
 	// Line filter (EXISTS on property_stations)
 	if filters.Line != "" {
 		query = query.Where("EXISTS (SELECT 1 FROM property_stations ps WHERE ps.property_id = properties.id AND ps.line_name LIKE ?)",
-			"%"+filters.Line+"%")
+			"%"+models.NormalizeLineName(filters.Line)+"%")
 	}
 
 	// Walk time filter
@@ -400,6 +513,14 @@ func (gdb *GormDB) applyFilters(query *gorm.DB, filters PropertyFilters) *gorm.D
 		query = query.Where("floor <= ?", *filters.MaxFloor)
 	}
 
+	// Building floors range filter
+	if filters.MinBuildingFloors != nil {
+		query = query.Where("building_floors >= ?", *filters.MinBuildingFloors)
+	}
+	if filters.MaxBuildingFloors != nil {
+		query = query.Where("building_floors <= ?", *filters.MaxBuildingFloors)
+	}
+
 	// Floor plans filter (multi-select)
 	if len(filters.FloorPlans) > 0 {
 		query = query.Where("floor_plan IN ?", filters.FloorPlans)
@@ -432,6 +553,14 @@ func (gdb *GormDB) GetPropertiesWithFiltersPaginated(filters PropertyFilters) (*
 		return nil, err
 	}
 
+	// Default listing (landing page) is served from the short-lived cache
+	cacheKey := listingCacheKey(filters)
+	if cacheKey != "" {
+		if cached, ok := defaultListingCache.get(cacheKey); ok {
+			return cached, nil
+		}
+	}
+
 	// Build base query for COUNT (no cursor condition)
 	countQuery := gdb.db.Model(&models.Property{})
 	countQuery = gdb.applyFilters(countQuery, filters)
@@ -446,31 +575,8 @@ func (gdb *GormDB) GetPropertiesWithFiltersPaginated(filters PropertyFilters) (*
 	listQuery := gdb.db.Model(&models.Property{})
 	listQuery = gdb.applyFilters(listQuery, filters)
 
-	// Map sort parameter to SQL ORDER BY clause (MySQL syntax)
-	// Use CASE to put NULLs last for ASC, first for DESC
-	var orderClause string
-	isCursorCompatible := false // Only newest/fetched_at sorts support cursor
-
-	switch filters.SortBy {
-	case "newest", "fetched_at", "fetched_at_desc", "":
-		orderClause = "fetched_at DESC, id DESC"
-		isCursorCompatible = true
-	case "fetched_at_asc":
-		orderClause = "fetched_at ASC, id ASC"
-	case "rent_asc":
-		orderClause = "CASE WHEN rent IS NULL THEN 1 ELSE 0 END, rent ASC"
-	case "rent_desc":
-		orderClause = "CASE WHEN rent IS NULL THEN 1 ELSE 0 END, rent DESC"
-	case "area_desc":
-		orderClause = "CASE WHEN area IS NULL THEN 1 ELSE 0 END, area DESC"
-	case "walk_time_asc":
-		orderClause = "CASE WHEN walk_time IS NULL THEN 1 ELSE 0 END, walk_time ASC"
-	case "building_age_asc":
-		orderClause = "CASE WHEN building_age IS NULL THEN 1 ELSE 0 END, building_age ASC"
-	default:
-		orderClause = "fetched_at DESC, id DESC"
-		isCursorCompatible = true
-	}
+	// Only newest/fetched_at sorts support cursor
+	orderClause, isCursorCompatible := propertyOrder(filters.SortBy)
 
 	// Apply cursor condition for cursor-compatible sorts
 	if filters.Cursor != "" && isCursorCompatible {
@@ -517,13 +623,17 @@ func (gdb *GormDB) GetPropertiesWithFiltersPaginated(filters PropertyFilters) (*
 		nextCursor = EncodeCursor(lastProperty.FetchedAt, lastProperty.ID)
 	}
 
-	return &PaginatedPropertiesResponse{
+	response := &PaginatedPropertiesResponse{
 		Properties: properties,
 		Total:      total,
 		Limit:      filters.Limit,
 		Offset:     offset,
 		NextCursor: nextCursor,
-	}, nil
+	}
+	if cacheKey != "" {
+		defaultListingCache.set(cacheKey, response)
+	}
+	return response, nil
 }
 
 // GetPropertyByID retrieves a property by ID
@@ -536,6 +646,16 @@ func (gdb *GormDB) GetPropertyByID(id string) (*models.Property, error) {
 	return &property, nil
 }
 
+// GetPropertiesByIDs retrieves properties by ID (missing IDs are skipped)
+func (gdb *GormDB) GetPropertiesByIDs(ids []string) ([]models.Property, error) {
+	var properties []models.Property
+	if len(ids) == 0 {
+		return properties, nil
+	}
+	err := gdb.db.Where("id IN ?", ids).Find(&properties).Error
+	return properties, err
+}
+
 // GetPropertyStations retrieves all stations for a property
 func (gdb *GormDB) GetPropertyStations(propertyID string) ([]models.PropertyStation, error) {
 	var stations []models.PropertyStation
@@ -557,6 +677,12 @@ func savePropertyStations(tx *gorm.DB, propertyID string, stations []models.Prop
 		return err
 	}
 
+	// Same spelling for every source, so the line filter and picker see one name per line
+	for i := range stations {
+		stations[i].StationName = models.NormalizeStationName(stations[i].StationName)
+		stations[i].LineName = models.NormalizeLineName(stations[i].LineName)
+	}
+
 	// Insert all new stations
 	if len(stations) > 0 {
 		if err := tx.Create(&stations).Error; err != nil {
@@ -569,6 +695,17 @@ func savePropertyStations(tx *gorm.DB, propertyID string, stations []models.Prop
 
 // SavePropertyWithStations saves a property and its stations in a transaction
 func (gdb *GormDB) SavePropertyWithStations(p *models.Property, stations []models.PropertyStation) error {
+	return gdb.SavePropertyWithStationsContext(context.Background(), p, stations)
+}
+
+// SavePropertyWithStationsContext is SavePropertyWithStations traced under the span carried by ctx
+func (gdb *GormDB) SavePropertyWithStationsContext(ctx context.Context, p *models.Property, stations []models.PropertyStation) (retErr error) {
+	ctx, span := tracing.Start(ctx, "database.SavePropertyWithStations", attribute.Int("stations.count", len(stations)))
+	defer func() {
+		tracing.RecordError(span, retErr)
+		span.End()
+	}()
+
 	// Generate ID from normalized URL if not set
 	if p.ID == "" {
 		normalizedURL := normalizeURL(p.DetailURL)
@@ -585,14 +722,29 @@ func (gdb *GormDB) SavePropertyWithStations(p *models.Property, stations []model
 		p.Status = models.PropertyStatusActive
 	}
 
+	// Numeric fee companions, structured floors, walk-time bucket, canonical list JSON, in-Japan coordinates, and fingerprint for relist detection
+	p.NormalizeFees()
+	p.NormalizeFloors()
+	p.NormalizeWalkTimeBucket()
+	p.NormalizeListFields()
+	p.NormalizeCoordinates()
+	p.Fingerprint = p.ComputeFingerprint()
+
+	defer defaultListingCache.invalidate()
+
+	span.SetAttributes(attribute.String("property.id", p.ID))
+
 	// Use transaction to save both property and stations
-	return gdb.db.Transaction(func(tx *gorm.DB) error {
+	return gdb.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		// Upsert property: try to find existing
 		var existing models.Property
 		result := tx.Where("detail_url = ?", p.DetailURL).First(&existing)
 
 		if result.Error == gorm.ErrRecordNotFound {
-			// Create new property
+			// Create new property (link to a removed listing of the same unit if any)
+			if err := linkRelistedProperty(tx, p); err != nil {
+				return err
+			}
 			if err := tx.Create(p).Error; err != nil {
 				return err
 			}
@@ -604,6 +756,10 @@ func (gdb *GormDB) SavePropertyWithStations(p *models.Property, stations []model
 			p.ID = existing.ID
 			p.Status = existing.Status
 			p.RemovedAt = existing.RemovedAt
+			p.RelistedFrom = existing.RelistedFrom
+			preserveManualCorrections(p, &existing)
+			preserveValidators(p, &existing)
+			preserveCoordinates(p, &existing)
 			if err := tx.Save(p).Error; err != nil {
 				return err
 			}
@@ -620,6 +776,18 @@ func (gdb *GormDB) SavePropertyWithStations(p *models.Property, stations []model
 
 // SavePropertyWithStationsAndImages saves a property with its stations and images in a transaction
 func (gdb *GormDB) SavePropertyWithStationsAndImages(p *models.Property, stations []models.PropertyStation, images []models.PropertyImage) error {
+	return gdb.SavePropertyWithStationsAndImagesContext(context.Background(), p, stations, images)
+}
+
+// SavePropertyWithStationsAndImagesContext is SavePropertyWithStationsAndImages traced under the span carried by ctx
+func (gdb *GormDB) SavePropertyWithStationsAndImagesContext(ctx context.Context, p *models.Property, stations []models.PropertyStation, images []models.PropertyImage) (retErr error) {
+	ctx, span := tracing.Start(ctx, "database.SavePropertyWithStationsAndImages",
+		attribute.Int("stations.count", len(stations)), attribute.Int("images.count", len(images)))
+	defer func() {
+		tracing.RecordError(span, retErr)
+		span.End()
+	}()
+
 	// Generate ID from normalized URL if not set
 	if p.ID == "" {
 		normalizedURL := normalizeURL(p.DetailURL)
@@ -636,14 +804,29 @@ func (gdb *GormDB) SavePropertyWithStationsAndImages(p *models.Property, station
 		p.Status = models.PropertyStatusActive
 	}
 
+	// Numeric fee companions, structured floors, walk-time bucket, canonical list JSON, in-Japan coordinates, and fingerprint for relist detection
+	p.NormalizeFees()
+	p.NormalizeFloors()
+	p.NormalizeWalkTimeBucket()
+	p.NormalizeListFields()
+	p.NormalizeCoordinates()
+	p.Fingerprint = p.ComputeFingerprint()
+
+	defer defaultListingCache.invalidate()
+
+	span.SetAttributes(attribute.String("property.id", p.ID))
+
 	// Use transaction to save property, stations, and images
-	return gdb.db.Transaction(func(tx *gorm.DB) error {
+	return gdb.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		// Upsert property: try to find existing
 		var existing models.Property
 		result := tx.Where("detail_url = ?", p.DetailURL).First(&existing)
 
 		if result.Error == gorm.ErrRecordNotFound {
-			// Create new property
+			// Create new property (link to a removed listing of the same unit if any)
+			if err := linkRelistedProperty(tx, p); err != nil {
+				return err
+			}
 			if err := tx.Create(p).Error; err != nil {
 				return err
 			}
@@ -653,37 +836,302 @@ func (gdb *GormDB) SavePropertyWithStationsAndImages(p *models.Property, station
 			// Update existing property
 			p.ID = existing.ID // Preserve existing ID
 			p.CreatedAt = existing.CreatedAt // Preserve creation time
+			p.RelistedFrom = existing.RelistedFrom
+			preserveManualCorrections(p, &existing)
+			preserveValidators(p, &existing)
+			preserveCoordinates(p, &existing)
 			if err := tx.Save(p).Error; err != nil {
 				return err
 			}
 		}
 
-		// Delete existing stations for this property
-		if err := tx.Where("property_id = ?", p.ID).Delete(&models.PropertyStation{}).Error; err != nil {
+		// Save stations and images (each only if extraction returned data)
+		if err := savePropertyStations(tx, p.ID, stations); err != nil {
+			return err
+		}
+		if err := savePropertyImages(tx, p.ID, images); err != nil {
 			return err
 		}
 
-		// Insert new stations
-		if len(stations) > 0 {
-			if err := tx.Create(&stations).Error; err != nil {
-				return err
-			}
+		return nil
+	})
+}
+
+// savePropertyImages replaces a property's gallery with images, renumbering sort_order
+// in slice order. The rows are bound to propertyID, which may differ from the ID the
+// images were built with when the save resolved to an existing property.
+func savePropertyImages(tx *gorm.DB, propertyID string, images []models.PropertyImage) error {
+	if len(images) == 0 {
+		// Same as stations: an empty extraction (WAF page, markup change) keeps the existing gallery
+		return nil
+	}
+
+	if err := tx.Where("property_id = ?", propertyID).Delete(&models.PropertyImage{}).Error; err != nil {
+		return err
+	}
+
+	rows := make([]models.PropertyImage, len(images))
+	for i, img := range images {
+		rows[i] = models.PropertyImage{PropertyID: propertyID, ImageURL: img.ImageURL, SortOrder: i}
+	}
+	return tx.Create(&rows).Error
+}
+
+// RelistWindow is how far back removed properties are considered for relist detection
+const RelistWindow = 90 * 24 * time.Hour
+
+// maxRelistChain bounds how many relist hops are followed when computing days on market
+const maxRelistChain = 20
+
+// linkRelistedProperty sets RelistedFrom on a new property when its fingerprint matches
+// a property removed within RelistWindow, and records a relisted change on the old row
+func linkRelistedProperty(tx *gorm.DB, p *models.Property) error {
+	if p.Fingerprint == "" || p.RelistedFrom != nil {
+		return nil
+	}
+
+	var previous models.Property
+	err := tx.Where("fingerprint = ? AND status = ? AND removed_at >= ? AND id <> ?",
+		p.Fingerprint, models.PropertyStatusRemoved, time.Now().Add(-RelistWindow), p.ID).
+		Order("removed_at DESC").
+		First(&previous).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	p.RelistedFrom = &previous.ID
+
+	change := models.PropertyChange{
+		PropertyID: previous.ID,
+		ChangeType: models.ChangeTypeRelisted,
+		OldValue:   previous.SourcePropertyID,
+		NewValue:   p.ID,
+	}
+	return tx.Create(&change).Error
+}
+
+// GetListingFirstSeen follows the relisted_from chain and returns when the unit was
+// first listed, so days on market survives a delist/relist under a new ID
+func (gdb *GormDB) GetListingFirstSeen(p *models.Property) (time.Time, error) {
+	firstSeen := p.CreatedAt
+	current := p
+	for i := 0; i < maxRelistChain && current.RelistedFrom != nil; i++ {
+		var previous models.Property
+		err := gdb.db.Where("id = ?", *current.RelistedFrom).First(&previous).Error
+		if err == gorm.ErrRecordNotFound {
+			break
+		} else if err != nil {
+			return firstSeen, err
+		}
+		if previous.CreatedAt.Before(firstSeen) {
+			firstSeen = previous.CreatedAt
 		}
+		current = &previous
+	}
+	return firstSeen, nil
+}
 
-		// Delete existing images for this property
-		if err := tx.Where("property_id = ?", p.ID).Delete(&models.PropertyImage{}).Error; err != nil {
-			return err
+// WalkBucketCount holds a walk-time bucket with the number of active properties in it
+type WalkBucketCount struct {
+	Bucket string `json:"bucket"`
+	Count  int64  `json:"count"`
+}
+
+// GetWalkBucketCounts returns active-property counts per walk-time bucket, in
+// models.WalkTimeBuckets order with empty buckets included. Without a station the
+// nearest-station walk (walk_time_bucket) is counted; with one, the walk to that
+// station from property_stations, so a listing counts wherever it lists the station.
+func (gdb *GormDB) GetWalkBucketCounts(station string) ([]WalkBucketCount, error) {
+	var rows []WalkBucketCount
+	var err error
+	if station == "" {
+		err = gdb.db.Model(&models.Property{}).
+			Select("walk_time_bucket AS bucket, COUNT(*) AS count").
+			Where("status = ?", models.PropertyStatusActive).
+			Group("walk_time_bucket").
+			Scan(&rows).Error
+	} else {
+		bucket := models.WalkTimeBucketSQL("MIN(ps.walk_minutes)")
+		perProperty := gdb.db.Table("property_stations ps").
+			Select("ps.property_id, "+bucket+" AS bucket").
+			Joins("JOIN properties p ON p.id = ps.property_id").
+			Where("p.status = ? AND ps.station_name = ?", models.PropertyStatusActive, station).
+			Group("ps.property_id")
+		err = gdb.db.Table("(?) AS b", perProperty).
+			Select("b.bucket AS bucket, COUNT(*) AS count").
+			Group("b.bucket").
+			Scan(&rows).Error
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Bucket] += row.Count
+	}
+	result := make([]WalkBucketCount, len(models.WalkTimeBuckets))
+	for i, bucket := range models.WalkTimeBuckets {
+		result[i] = WalkBucketCount{Bucket: bucket, Count: counts[bucket]}
+	}
+	return result, nil
+}
+
+// LineCount holds a line name with the number of active properties reachable from it
+type LineCount struct {
+	LineName string `json:"line_name"`
+	Count    int64  `json:"count"`
+}
+
+// GetLineCounts returns distinct line names with active-property counts (for the line picker).
+// Names are merged after NormalizeLineName, so rows saved before normalization ("ＪＲ山手線")
+// count under the same line as current ones ("JR山手線").
+func (gdb *GormDB) GetLineCounts() ([]LineCount, error) {
+	var rows []struct {
+		LineName   string
+		PropertyID string
+	}
+	err := gdb.db.Table("property_stations ps").
+		Distinct("ps.line_name", "ps.property_id").
+		Joins("JOIN properties p ON p.id = ps.property_id").
+		Where("p.status = ? AND ps.line_name <> ''", models.PropertyStatusActive).
+		Find(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	propertiesByLine := make(map[string]map[string]bool)
+	for _, row := range rows {
+		line := models.NormalizeLineName(row.LineName)
+		if line == "" {
+			continue
+		}
+		if propertiesByLine[line] == nil {
+			propertiesByLine[line] = make(map[string]bool)
+		}
+		propertiesByLine[line][row.PropertyID] = true
+	}
+	counts := make([]LineCount, 0, len(propertiesByLine))
+	for line, ids := range propertiesByLine {
+		counts = append(counts, LineCount{LineName: line, Count: int64(len(ids))})
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Count != counts[j].Count {
+			return counts[i].Count > counts[j].Count
 		}
+		return counts[i].LineName < counts[j].LineName
+	})
+	return counts, nil
+}
 
-		// Insert new images
-		if len(images) > 0 {
-			if err := tx.Create(&images).Error; err != nil {
-				return err
-			}
+// AttachLines fills the non-persisted Lines field from property_stations (for search indexing)
+func (gdb *GormDB) AttachLines(properties []models.Property) error {
+	if len(properties) == 0 {
+		return nil
+	}
+
+	ids := make([]string, len(properties))
+	for i := range properties {
+		ids[i] = properties[i].ID
+	}
+
+	var stations []models.PropertyStation
+	if err := gdb.db.Select("property_id", "line_name").Where("property_id IN ?", ids).
+		Order("sort_order ASC").Find(&stations).Error; err != nil {
+		return err
+	}
+
+	linesByID := make(map[string][]string)
+	for _, st := range stations {
+		line := models.NormalizeLineName(st.LineName)
+		if line == "" {
+			continue
 		}
+		linesByID[st.PropertyID] = appendUnique(linesByID[st.PropertyID], line)
+	}
+	for i := range properties {
+		properties[i].Lines = linesByID[properties[i].ID]
+	}
+	return nil
+}
 
+// AttachFreshness fills the freshness fields for a page of properties.
+// Recent rent changes are looked up with a single aggregated query for the whole page.
+func (gdb *GormDB) AttachFreshness(properties []models.Property, t models.FreshnessThresholds) error {
+	if len(properties) == 0 {
 		return nil
-	})
+	}
+
+	now := time.Now()
+	ids := make([]string, len(properties))
+	for i := range properties {
+		properties[i].ApplyFreshness(now, t)
+		ids[i] = properties[i].ID
+	}
+
+	var changedIDs []string
+	if err := gdb.db.Model(&models.PropertyChange{}).
+		Distinct("property_id").
+		Where("property_id IN ? AND change_type = ? AND detected_at >= ?",
+			ids, models.ChangeTypeRent, now.Add(-t.PriceChangeWindow)).
+		Pluck("property_id", &changedIDs).Error; err != nil {
+		return err
+	}
+
+	changed := make(map[string]bool, len(changedIDs))
+	for _, id := range changedIDs {
+		changed[id] = true
+	}
+	for i := range properties {
+		properties[i].HasRecentPriceChange = changed[properties[i].ID]
+	}
+	return nil
+}
+
+// RepairListFields rewrites facilities/features columns that are empty, invalid,
+// unsorted or contain duplicates into canonical JSON arrays. Returns the number of rows fixed.
+func (gdb *GormDB) RepairListFields() (int, error) {
+	repaired := 0
+	var batch []models.Property
+
+	result := gdb.db.Select("id", "facilities", "features").
+		FindInBatches(&batch, 500, func(tx *gorm.DB, _ int) error {
+			for i := range batch {
+				p := &batch[i]
+				if !p.NormalizeListFields() {
+					continue
+				}
+				if err := gdb.db.Model(&models.Property{}).Where("id = ?", p.ID).
+					UpdateColumns(map[string]interface{}{
+						"facilities": p.Facilities,
+						"features":   p.Features,
+					}).Error; err != nil {
+					return err
+				}
+				repaired++
+			}
+			return nil
+		})
+	if result.Error != nil {
+		return repaired, result.Error
+	}
+
+	if repaired > 0 {
+		defaultListingCache.invalidate()
+	}
+	return repaired, nil
+}
+
+// appendUnique appends s to list if not already present
+func appendUnique(list []string, s string) []string {
+	for _, v := range list {
+		if v == s {
+			return list
+		}
+	}
+	return append(list, s)
 }
 
 // GetPropertyImages retrieves all images for a property
@@ -700,8 +1148,70 @@ func (gdb *GormDB) GetActiveProperties() ([]models.Property, error) {
 	return properties, err
 }
 
+// diffColumns are the only columns DetectDifferences reads (see hasPropertyChanged)
+var diffColumns = []string{"id", "title", "rent", "image_url", "detail_url"}
+
+// GetActivePropertiesForDiff retrieves active properties with only the columns needed for diffing.
+// No ORDER BY: callers build maps, so there is no reason to pay for a sort over the whole active set.
+func (gdb *GormDB) GetActivePropertiesForDiff() ([]models.Property, error) {
+	var properties []models.Property
+	err := gdb.db.Select(diffColumns).Where("status = ?", models.PropertyStatusActive).Find(&properties).Error
+	return properties, err
+}
+
+// ErrStopStream can be returned from a StreamActiveProperties callback to end the scan early
+var ErrStopStream = errors.New("stop streaming")
+
+// StreamActiveProperties pages through active properties newest first (created_at DESC, id DESC),
+// batchSize rows at a time, so callers never hold the whole active set in memory. Pages are read
+// by keyset (rows after the previous page's last created_at/id), not OFFSET, so a deep page costs
+// no more than the first. columns limits the SELECT (nil = all columns; "id" and "created_at" are
+// always included for paging). Returning ErrStopStream from fn stops the scan without an error.
+func (gdb *GormDB) StreamActiveProperties(batchSize int, columns []string, fn func(batch []models.Property) error) error {
+	if batchSize <= 0 {
+		batchSize = 500
+	}
+	if len(columns) > 0 {
+		for _, key := range []string{"created_at", "id"} {
+			if !slices.Contains(columns, key) {
+				columns = append([]string{key}, columns...)
+			}
+		}
+	}
+
+	var last *models.Property
+	for {
+		q := gdb.db.Where("status = ?", models.PropertyStatusActive)
+		if len(columns) > 0 {
+			q = q.Select(columns)
+		}
+		if last != nil {
+			q = q.Where("created_at < ? OR (created_at = ? AND id < ?)", last.CreatedAt, last.CreatedAt, last.ID)
+		}
+
+		var batch []models.Property
+		if err := q.Order("created_at DESC, id DESC").Limit(batchSize).Find(&batch).Error; err != nil {
+			return err
+		}
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := fn(batch); err != nil {
+			if errors.Is(err, ErrStopStream) {
+				return nil
+			}
+			return err
+		}
+		if len(batch) < batchSize {
+			return nil
+		}
+		last = &batch[len(batch)-1]
+	}
+}
+
 // MarkPropertyAsRemoved marks a property as removed (logical deletion)
 func (gdb *GormDB) MarkPropertyAsRemoved(id string) error {
+	defer defaultListingCache.invalidate()
 	now := time.Now()
 	return gdb.db.Model(&models.Property{}).
 		Where("id = ?", id).
@@ -716,6 +1226,7 @@ func (gdb *GormDB) MarkPropertiesAsRemoved(ids []string) error {
 	if len(ids) == 0 {
 		return nil
 	}
+	defer defaultListingCache.invalidate()
 	now := time.Now()
 	return gdb.db.Model(&models.Property{}).
 		Where("id IN ?", ids).
@@ -725,11 +1236,50 @@ func (gdb *GormDB) MarkPropertiesAsRemoved(ids []string) error {
 		}).Error
 }
 
+// MarkPropertyDelisted marks an active property as removed after the site answered 404/410
+// for its detail page, and records a property_removed change (old value: the status code)
+// so feeds and history show the delisting. A property that is no longer active is left as is.
+func (gdb *GormDB) MarkPropertyDelisted(id string, status int) error {
+	return gdb.markPropertyRemoved(id, fmt.Sprintf("HTTP %d", status))
+}
+
+// MarkPropertyListingEnded is MarkPropertyDelisted for a detail page that answered 200 with the
+// site's 掲載終了 notice instead of the listing
+func (gdb *GormDB) MarkPropertyListingEnded(id string) error {
+	return gdb.markPropertyRemoved(id, "掲載終了ページ")
+}
+
+// markPropertyRemoved marks an active property removed with a property_removed change whose
+// old value says how the delisting was detected
+func (gdb *GormDB) markPropertyRemoved(id, reason string) error {
+	defer defaultListingCache.invalidate()
+	return gdb.db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		result := tx.Model(&models.Property{}).
+			Where("id = ? AND status = ?", id, models.PropertyStatusActive).
+			Updates(map[string]interface{}{
+				"status":     models.PropertyStatusRemoved,
+				"removed_at": &now,
+			})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+
+		change := models.PropertyChange{
+			PropertyID: id,
+			ChangeType: models.ChangeTypeRemoved,
+			OldValue:   reason,
+			NewValue:   string(models.PropertyStatusRemoved),
+		}
+		return tx.Create(&change).Error
+	})
+}
+
 // DetectDifferences compares current active properties with newly scraped properties
 // Returns: new IDs, removed IDs, updated properties
 func (gdb *GormDB) DetectDifferences(scrapedProperties []models.Property) (newIDs []string, removedIDs []string, updatedProperties []models.Property, err error) {
-	// Get all currently active properties
-	activeProperties, err := gdb.GetActiveProperties()
+	// Get all currently active properties (comparison columns only)
+	activeProperties, err := gdb.GetActivePropertiesForDiff()
 	if err != nil {
 		return nil, nil, nil, err
 	}
@@ -772,6 +1322,22 @@ func (gdb *GormDB) DetectDifferences(scrapedProperties []models.Property) (newID
 	return newIDs, removedIDs, updatedProperties, nil
 }
 
+// preserveValidators keeps the stored ETag / Last-Modified when p comes from a save without a
+// detail fetch (list pages, imports), so the next re-scrape can still be conditional
+func preserveValidators(p, existing *models.Property) {
+	if p.ETag == "" && p.LastModified == "" {
+		p.ETag, p.LastModified = existing.ETag, existing.LastModified
+	}
+}
+
+// preserveCoordinates keeps the stored latitude / longitude when p carries none (the page
+// dropped its map, or the save comes from a list page or import)
+func preserveCoordinates(p, existing *models.Property) {
+	if p.Latitude == nil && p.Longitude == nil {
+		p.Latitude, p.Longitude = existing.Latitude, existing.Longitude
+	}
+}
+
 // hasPropertyChanged checks if property data has changed
 func hasPropertyChanged(old, new *models.Property) bool {
 	// Compare key fields that might change
@@ -784,6 +1350,9 @@ func hasPropertyChanged(old, new *models.Property) bool {
 	if old.ImageURL != new.ImageURL {
 		return true
 	}
+	if old.BuildingType != new.BuildingType || old.Structure != new.Structure {
+		return true
+	}
 	// Add more field comparisons as needed
 	return false
 }
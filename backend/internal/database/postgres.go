@@ -129,28 +129,28 @@ func (db *DB) GetAllProperties() ([]models.Property, error) {
 	return properties, nil
 }
 
+// postgresOrderClauses maps each models.PropertySortKeys entry to its ORDER BY clause (PostgreSQL syntax)
+var postgresOrderClauses = map[string]string{
+	models.SortNewest:          "fetched_at DESC",
+	models.SortFetchedAt:       "fetched_at DESC",
+	models.SortFetchedAtDesc:   "fetched_at DESC",
+	models.SortFetchedAtAsc:    "fetched_at ASC",
+	models.SortCreatedAtDesc:   "created_at DESC",
+	models.SortRentAsc:         "rent ASC NULLS LAST",
+	models.SortRentDesc:        "rent DESC NULLS LAST",
+	models.SortAreaDesc:        "area DESC NULLS LAST",
+	models.SortAreaAsc:         "area ASC NULLS LAST",
+	models.SortWalkTimeAsc:     "walk_time ASC NULLS LAST",
+	models.SortBuildingAgeAsc:  "building_age ASC NULLS LAST",
+	models.SortBuildingAgeDesc: "building_age DESC NULLS LAST",
+}
+
 // GetPropertiesWithSort retrieves all properties with custom sorting
 func (db *DB) GetPropertiesWithSort(sortBy string) ([]models.Property, error) {
-	// Map sort parameter to SQL ORDER BY clause
-	var orderClause string
-	switch sortBy {
-	case "fetched_at", "fetched_at_desc":
-		orderClause = "fetched_at DESC"
-	case "fetched_at_asc":
-		orderClause = "fetched_at ASC"
-	case "rent_asc":
-		orderClause = "rent ASC NULLS LAST"
-	case "rent_desc":
-		orderClause = "rent DESC NULLS LAST"
-	case "area_desc":
-		orderClause = "area DESC NULLS LAST"
-	case "walk_time_asc":
-		orderClause = "walk_time ASC NULLS LAST"
-	case "building_age_asc":
-		orderClause = "building_age ASC NULLS LAST"
-	default:
-		// Default to newest first (by fetched_at)
-		orderClause = "fetched_at DESC"
+	// Map sort parameter to SQL ORDER BY clause (unknown keys: newest first)
+	orderClause, ok := postgresOrderClauses[sortBy]
+	if !ok {
+		orderClause = postgresOrderClauses[models.SortNewest]
 	}
 
 	query := fmt.Sprintf(`
@@ -0,0 +1,69 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"log"
+	"real-estate-portal/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// RecordPropertyRedirect handles a detail page that redirected from fromID to toID / toURL (Yahoo
+// 301s a re-published listing's old URL to its new one). The property stored under fromID is moved
+// to the new ID and URL, so the save that follows updates it instead of inserting a duplicate;
+// fromID is kept in property_aliases and a url_changed change records old URL → new URL.
+// Nothing happens when fromID is not stored; if a row already exists under toID, that row is
+// kept and only the alias is recorded.
+func (gdb *GormDB) RecordPropertyRedirect(ctx context.Context, source, fromID, toID, toURL string) error {
+	defer defaultListingCache.invalidate()
+	return gdb.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var old models.Property
+		err := tx.Where("source = ? AND source_property_id = ?", source, fromID).First(&old).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		propertyID := old.ID
+
+		var current models.Property
+		err = tx.Where("source = ? AND source_property_id = ?", source, toID).First(&current).Error
+		switch {
+		case err == nil:
+			// Both listings were already crawled separately: keep the new one, point the old ID at it
+			log.Printf("[redirect] %s %s is already stored as %s; recording alias only", source, toID, current.ID)
+			propertyID = current.ID
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			if err := tx.Model(&old).Updates(map[string]interface{}{
+				"source_property_id": toID,
+				"detail_url":         toURL,
+			}).Error; err != nil {
+				return err
+			}
+			change := models.PropertyChange{
+				PropertyID: old.ID,
+				ChangeType: models.ChangeTypeURLChanged,
+				OldValue:   old.DetailURL,
+				NewValue:   toURL,
+			}
+			if err := tx.Create(&change).Error; err != nil {
+				return err
+			}
+		default:
+			return err
+		}
+
+		alias := models.PropertyAlias{
+			Source:           source,
+			SourcePropertyID: fromID,
+			DetailURL:        old.DetailURL,
+			PropertyID:       propertyID,
+		}
+		return tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "source"}, {Name: "source_property_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"property_id"}),
+		}).Create(&alias).Error
+	})
+}
@@ -0,0 +1,148 @@
+package database
+
+import (
+	"log"
+	"real-estate-portal/internal/maintenance"
+	"real-estate-portal/internal/models"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ViewCounter buffers detail-page views in memory and writes them to property_views
+// in one batch per flush, so GET /api/properties/:id never waits on a write.
+type ViewCounter struct {
+	gdb      *GormDB
+	mu       sync.Mutex
+	pending  map[string]int
+	stopChan chan struct{}
+	done     chan struct{}
+}
+
+// NewViewCounter creates a view counter backed by gdb
+func NewViewCounter(gdb *GormDB) *ViewCounter {
+	return &ViewCounter{
+		gdb:     gdb,
+		pending: make(map[string]int),
+	}
+}
+
+// Record counts one view of a property (flushed later)
+func (vc *ViewCounter) Record(propertyID string) {
+	if propertyID == "" {
+		return
+	}
+	vc.mu.Lock()
+	vc.pending[propertyID]++
+	vc.mu.Unlock()
+}
+
+// Flush writes buffered views to today's property_views rows.
+// Views stay buffered while read-only maintenance mode is on.
+func (vc *ViewCounter) Flush() error {
+	if maintenance.ReadOnly() {
+		return nil
+	}
+
+	vc.mu.Lock()
+	if len(vc.pending) == 0 {
+		vc.mu.Unlock()
+		return nil
+	}
+	pending := vc.pending
+	vc.pending = make(map[string]int)
+	vc.mu.Unlock()
+
+	// One upsert per property with the count bound (no MySQL-only VALUES()/NOW()), in a
+	// single transaction so a failed flush writes nothing and is retried whole
+	now := time.Now()
+	day := now.Format("2006-01-02")
+	err := vc.gdb.db.Transaction(func(tx *gorm.DB) error {
+		for id, n := range pending {
+			row := models.PropertyView{PropertyID: id, Day: day, Views: n}
+			if err := tx.Clauses(clause.OnConflict{
+				Columns: []clause.Column{{Name: "property_id"}, {Name: "day"}},
+				DoUpdates: clause.Assignments(map[string]interface{}{
+					"views":      gorm.Expr("views + ?", n),
+					"updated_at": now,
+				}),
+			}).Create(&row).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		// Put the counts back so the next flush retries them
+		vc.mu.Lock()
+		for id, n := range pending {
+			vc.pending[id] += n
+		}
+		vc.mu.Unlock()
+	}
+	return err
+}
+
+// Start flushes every interval until Stop is called
+func (vc *ViewCounter) Start(interval time.Duration) {
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	vc.stopChan = make(chan struct{})
+	vc.done = make(chan struct{})
+
+	go func() {
+		defer close(vc.done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if err := vc.Flush(); err != nil {
+					log.Printf("[ViewCounter] Flush failed: %v", err)
+				}
+			case <-vc.stopChan:
+				return
+			}
+		}
+	}()
+	log.Printf("[ViewCounter] Started (flush every %v)", interval)
+}
+
+// Stop stops the flush loop and writes whatever is still buffered
+func (vc *ViewCounter) Stop() {
+	if vc.stopChan != nil {
+		close(vc.stopChan)
+		<-vc.done
+		vc.stopChan = nil
+	}
+	if err := vc.Flush(); err != nil {
+		log.Printf("[ViewCounter] Final flush failed: %v", err)
+	}
+}
+
+// AdjustFavoriteCount adds delta (+1 / -1) to a property's favorite count, never going below zero
+func (gdb *GormDB) AdjustFavoriteCount(propertyID string, delta int) (int, error) {
+	initial := delta
+	if initial < 0 {
+		initial = 0
+	}
+	fav := models.PropertyFavorite{PropertyID: propertyID, Count: initial}
+	err := gdb.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "property_id"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"count":      gorm.Expr("GREATEST(`count` + ?, 0)", delta),
+			"updated_at": time.Now(),
+		}),
+	}).Create(&fav).Error
+	if err != nil {
+		return 0, err
+	}
+
+	if err := gdb.db.Where("property_id = ?", propertyID).First(&fav).Error; err != nil {
+		return 0, err
+	}
+	return fav.Count, nil
+}
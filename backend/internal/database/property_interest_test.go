@@ -0,0 +1,48 @@
+package database_test
+
+import (
+	"real-estate-portal/internal/database"
+	"real-estate-portal/internal/models"
+	"real-estate-portal/internal/sqlitetest"
+	"testing"
+	"time"
+)
+
+// Flushes add to today's row (the upsert increments), per property
+func TestViewCounterFlush(t *testing.T) {
+	db := sqlitetest.Open(t)
+	vc := database.NewViewCounter(database.NewGormDBFromDB(db))
+
+	for _, batch := range [][]string{{"a", "a", "b"}, {"a"}, {}} {
+		for _, id := range batch {
+			vc.Record(id)
+		}
+		if err := vc.Flush(); err != nil {
+			t.Fatalf("flush: %v", err)
+		}
+	}
+
+	day := time.Now().Format("2006-01-02")
+	for id, want := range map[string]int{"a": 3, "b": 1} {
+		var row models.PropertyView
+		if err := db.Where("property_id = ? AND day = ?", id, day).First(&row).Error; err != nil {
+			t.Fatalf("%s: %v", id, err)
+		}
+		if row.Views != want {
+			t.Errorf("%s: %d views (want %d)", id, row.Views, want)
+		}
+	}
+}
+
+func TestAdjustFavoriteCount(t *testing.T) {
+	gdb := database.NewGormDBFromDB(sqlitetest.Open(t))
+	for i, tt := range []struct{ delta, want int }{{1, 1}, {1, 2}, {-1, 1}, {-1, 0}, {-1, 0}, {1, 1}} {
+		got, err := gdb.AdjustFavoriteCount("p1", tt.delta)
+		if err != nil {
+			t.Fatalf("step %d: %v", i, err)
+		}
+		if got != tt.want {
+			t.Errorf("step %d: %+d gave %d (want %d)", i, tt.delta, got, tt.want)
+		}
+	}
+}
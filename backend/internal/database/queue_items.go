@@ -0,0 +1,177 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"real-estate-portal/internal/models"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"gorm.io/gorm"
+)
+
+// ErrInvalidQueueFilter is returned for an unknown status or a negative min_attempts / offset
+var ErrInvalidQueueFilter = errors.New("invalid queue filter")
+
+// Page sizes for ListQueueItems
+const (
+	DefaultQueueListLimit = 50
+	QueueListLimit        = 500
+)
+
+// queueListErrorLength is how much of last_error a list row carries (GetQueueItem has all of it)
+const queueListErrorLength = 120
+
+// QueueItemFilter selects detail_scrape_queue rows for inspection; empty fields match everything
+type QueueItemFilter struct {
+	Status      string // one of models.QueueStatuses
+	Source      string // e.g. yahoo
+	MinAttempts int    // attempts >= MinAttempts
+}
+
+// QueuePage is a limit/offset page (Limit 0 = DefaultQueueListLimit, capped at QueueListLimit)
+type QueuePage struct {
+	Limit  int
+	Offset int
+}
+
+// QueueItemList is a page of queue items with the total matching the filter
+type QueueItemList struct {
+	Items  []models.DetailScrapeQueue `json:"items"`
+	Total  int64                      `json:"total"`
+	Limit  int                        `json:"limit"`
+	Offset int                        `json:"offset"`
+}
+
+// ListQueueItems returns queue items matching filter in processing order (priority desc, then
+// oldest first). last_error is shortened in the list; GetQueueItem returns it in full.
+func (gdb *GormDB) ListQueueItems(filter QueueItemFilter, page QueuePage) (*QueueItemList, error) {
+	if filter.Status != "" && !slices.Contains(models.QueueStatuses, filter.Status) {
+		return nil, fmt.Errorf("%w: unknown status %q", ErrInvalidQueueFilter, filter.Status)
+	}
+	if filter.MinAttempts < 0 || page.Offset < 0 {
+		return nil, fmt.Errorf("%w: min_attempts and offset must not be negative", ErrInvalidQueueFilter)
+	}
+	if page.Limit <= 0 {
+		page.Limit = DefaultQueueListLimit
+	}
+	if page.Limit > QueueListLimit {
+		page.Limit = QueueListLimit
+	}
+
+	list := &QueueItemList{Items: []models.DetailScrapeQueue{}, Limit: page.Limit, Offset: page.Offset}
+	if err := filter.apply(gdb.db.Model(&models.DetailScrapeQueue{})).Count(&list.Total).Error; err != nil {
+		return nil, fmt.Errorf("count queue items: %w", err)
+	}
+	if err := filter.apply(gdb.db).Order("priority DESC, created_at ASC, id ASC").
+		Limit(page.Limit).Offset(page.Offset).
+		Find(&list.Items).Error; err != nil {
+		return nil, fmt.Errorf("list queue items: %w", err)
+	}
+	for i := range list.Items {
+		if msg := list.Items[i].LastError; utf8.RuneCountInString(msg) > queueListErrorLength {
+			list.Items[i].LastError = string([]rune(msg)[:queueListErrorLength]) + "…"
+		}
+	}
+	return list, nil
+}
+
+// apply adds the filter conditions to a detail_scrape_queue query
+func (f QueueItemFilter) apply(q *gorm.DB) *gorm.DB {
+	if f.Status != "" {
+		q = q.Where("status = ?", f.Status)
+	}
+	if f.Source != "" {
+		q = q.Where("source = ?", f.Source)
+	}
+	if f.MinAttempts > 0 {
+		q = q.Where("attempts >= ?", f.MinAttempts)
+	}
+	return q
+}
+
+// GetQueueItem returns one queue item with its full last_error (gorm.ErrRecordNotFound if none)
+func (gdb *GormDB) GetQueueItem(id int64) (*models.DetailScrapeQueue, error) {
+	var item models.DetailScrapeQueue
+	if err := gdb.db.First(&item, id).Error; err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// ErrQueueItemConflict is returned (wrapped) when a queue item is not in a state the action
+// applies to, e.g. cancelling an item a worker is processing
+var ErrQueueItemConflict = errors.New("queue item conflict")
+
+// Statuses RetryQueueItem and CancelQueueItem act on
+var (
+	retryableQueueStatuses   = []string{models.QueueStatusFailed, models.QueueStatusPermanentFail, models.QueueStatusDone, models.QueueStatusCancelled}
+	cancellableQueueStatuses = []string{models.QueueStatusPending, models.QueueStatusFailed}
+)
+
+// RetryQueueItem puts a failed, permanently failed, done or cancelled item back to pending with
+// no retry wait; resetAttempts also gives it a fresh retry budget. A pending or processing item,
+// or one whose listing already has another pending/processing row, is an ErrQueueItemConflict.
+func (gdb *GormDB) RetryQueueItem(id int64, resetAttempts bool) (*models.DetailScrapeQueue, error) {
+	item, err := gdb.GetQueueItem(id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only one active row per listing (uniq_queue_active_key)
+	var active []models.DetailScrapeQueue
+	if err := gdb.db.Select("id").
+		Where("active_key = ? AND id <> ?", models.QueueActiveKey(item.Source, item.SourcePropertyID), id).
+		Limit(1).Find(&active).Error; err != nil {
+		return nil, fmt.Errorf("find active queue row: %w", err)
+	}
+	if len(active) > 0 {
+		return nil, fmt.Errorf("%w: listing is already queued as item %d", ErrQueueItemConflict, active[0].ID)
+	}
+
+	updates := map[string]interface{}{
+		"status":        models.QueueStatusPending,
+		"next_retry_at": nil,
+		"completed_at":  nil,
+	}
+	if resetAttempts {
+		updates["attempts"] = 0
+	}
+	return gdb.transitionQueueItem(id, retryableQueueStatuses, updates)
+}
+
+// CancelQueueItem marks a pending or failed item cancelled so no worker picks it up again.
+// A processing item (or one already finished) is an ErrQueueItemConflict.
+func (gdb *GormDB) CancelQueueItem(id int64) (*models.DetailScrapeQueue, error) {
+	return gdb.transitionQueueItem(id, cancellableQueueStatuses, map[string]interface{}{
+		"status":        models.QueueStatusCancelled,
+		"next_retry_at": nil,
+		"completed_at":  time.Now(),
+	})
+}
+
+// transitionQueueItem applies updates to item id only while its status is one of from (a single
+// conditional UPDATE, so a worker claiming it meanwhile wins) and returns the updated row
+func (gdb *GormDB) transitionQueueItem(id int64, from []string, updates map[string]interface{}) (*models.DetailScrapeQueue, error) {
+	result := gdb.db.Model(&models.DetailScrapeQueue{}).
+		Where("id = ? AND status IN ?", id, from).
+		Updates(updates)
+	if errors.Is(result.Error, gorm.ErrDuplicatedKey) {
+		// The listing became active through another row since RetryQueueItem checked
+		return nil, fmt.Errorf("%w: listing of item %d is already queued", ErrQueueItemConflict, id)
+	}
+	if result.Error != nil {
+		return nil, fmt.Errorf("update queue item %d: %w", id, result.Error)
+	}
+
+	item, err := gdb.GetQueueItem(id)
+	if err != nil {
+		return nil, err
+	}
+	if result.RowsAffected == 0 {
+		return nil, fmt.Errorf("%w: item %d is %s (allowed: %s)", ErrQueueItemConflict, id, item.Status, strings.Join(from, ", "))
+	}
+	return item, nil
+}
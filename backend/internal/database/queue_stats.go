@@ -0,0 +1,51 @@
+package database
+
+import (
+	"fmt"
+	"real-estate-portal/internal/models"
+	"time"
+)
+
+// QueueBacklog is how far behind detail_scrape_queue is, on top of the per-status counts
+type QueueBacklog struct {
+	OldestPendingAt         *time.Time `json:"oldest_pending_at,omitempty"`
+	OldestPendingAgeSeconds int64      `json:"oldest_pending_age_seconds"` // 0 when nothing is pending
+	DoneLast24h             int64      `json:"done_last_24h"`              // completed_at within 24h of now
+	AvgAttemptsDone         float64    `json:"avg_attempts_done"`          // over every done item
+}
+
+// GetQueueBacklog returns the age of the oldest pending item, the items done in the 24 hours
+// before now and the average attempts a done item took
+func (gdb *GormDB) GetQueueBacklog(now time.Time) (*QueueBacklog, error) {
+	backlog := &QueueBacklog{}
+
+	var oldest []models.DetailScrapeQueue
+	if err := gdb.db.Select("id, created_at").
+		Where("status = ?", models.QueueStatusPending).
+		Order("created_at ASC").Limit(1).
+		Find(&oldest).Error; err != nil {
+		return nil, fmt.Errorf("find oldest pending item: %w", err)
+	}
+	if len(oldest) > 0 {
+		createdAt := oldest[0].CreatedAt
+		backlog.OldestPendingAt = &createdAt
+		backlog.OldestPendingAgeSeconds = max(int64(now.Sub(createdAt).Seconds()), 0)
+	}
+
+	if err := gdb.db.Model(&models.DetailScrapeQueue{}).
+		Where("status = ? AND completed_at >= ?", models.QueueStatusDone, now.Add(-24*time.Hour)).
+		Count(&backlog.DoneLast24h).Error; err != nil {
+		return nil, fmt.Errorf("count items done in the last 24h: %w", err)
+	}
+
+	var avgAttempts []float64
+	if err := gdb.db.Model(&models.DetailScrapeQueue{}).
+		Where("status = ?", models.QueueStatusDone).
+		Pluck("COALESCE(AVG(attempts), 0)", &avgAttempts).Error; err != nil {
+		return nil, fmt.Errorf("average attempts of done items: %w", err)
+	}
+	if len(avgAttempts) > 0 {
+		backlog.AvgAttemptsDone = avgAttempts[0]
+	}
+	return backlog, nil
+}
@@ -0,0 +1,130 @@
+package database_test
+
+import (
+	"real-estate-portal/internal/database"
+	"real-estate-portal/internal/models"
+	"real-estate-portal/internal/sqlitetest"
+	"testing"
+	"time"
+)
+
+func floatPtr(f float64) *float64 { return &f }
+
+// unit returns a listing of the same flat under the Yahoo ID id: same photo path, area,
+// floor plan and address, so it has the same fingerprint whatever the ID
+func unit(id string) *models.Property {
+	return &models.Property{
+		Source:           "yahoo",
+		SourcePropertyID: id,
+		DetailURL:        "https://realestate.example/rent/detail/" + id + "/",
+		Title:            "リバーサイド中野 302",
+		ImageURL:         "https://img.example/photos/abc123.jpg",
+		Area:             floatPtr(25.5),
+		FloorPlan:        "1K",
+		Address:          "東京都中野区中野 1-2-3",
+	}
+}
+
+func TestRelistLinking(t *testing.T) {
+	tests := []struct {
+		name       string
+		removedAgo time.Duration
+		edit       func(p *models.Property)
+		wantLink   bool
+	}{
+		{name: "relist within the window", removedAgo: 30 * 24 * time.Hour, wantLink: true},
+		{name: "relist just inside the window", removedAgo: database.RelistWindow - time.Hour, wantLink: true},
+		{name: "relist after the window", removedAgo: database.RelistWindow + 24*time.Hour},
+		{name: "different area", removedAgo: 30 * 24 * time.Hour, edit: func(p *models.Property) { p.Area = floatPtr(30) }},
+		{name: "different photo", removedAgo: 30 * 24 * time.Hour, edit: func(p *models.Property) { p.ImageURL = "https://img.example/photos/zzz.jpg" }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := sqlitetest.Open(t)
+			gdb := database.NewGormDBFromDB(db)
+
+			old := unit("old1")
+			if err := gdb.SaveProperty(old); err != nil {
+				t.Fatalf("save old listing: %v", err)
+			}
+			firstSeen := time.Now().Add(-tt.removedAgo - 60*24*time.Hour).Truncate(time.Second)
+			removedAt := time.Now().Add(-tt.removedAgo)
+			if err := db.Model(&models.Property{}).Where("id = ?", old.ID).Updates(map[string]interface{}{
+				"status": models.PropertyStatusRemoved, "removed_at": removedAt, "created_at": firstSeen,
+			}).Error; err != nil {
+				t.Fatalf("remove old listing: %v", err)
+			}
+
+			relisted := unit("new1")
+			if tt.edit != nil {
+				tt.edit(relisted)
+			}
+			if err := gdb.SaveProperty(relisted); err != nil {
+				t.Fatalf("save new listing: %v", err)
+			}
+			saved, err := gdb.GetPropertyByID(relisted.ID)
+			if err != nil {
+				t.Fatalf("read new listing: %v", err)
+			}
+			var changes []models.PropertyChange
+			db.Where("property_id = ? AND change_type = ?", old.ID, models.ChangeTypeRelisted).Find(&changes)
+			gotFirstSeen, err := gdb.GetListingFirstSeen(saved)
+			if err != nil {
+				t.Fatalf("GetListingFirstSeen: %v", err)
+			}
+
+			if !tt.wantLink {
+				if saved.RelistedFrom != nil || len(changes) != 0 {
+					t.Errorf("relisted_from %v, %d relisted changes (want no link)", saved.RelistedFrom, len(changes))
+				}
+				if !gotFirstSeen.Equal(saved.CreatedAt) {
+					t.Errorf("first seen %v (want the new listing's own %v)", gotFirstSeen, saved.CreatedAt)
+				}
+				return
+			}
+			if saved.RelistedFrom == nil || *saved.RelistedFrom != old.ID {
+				t.Fatalf("relisted_from %v (want %s)", saved.RelistedFrom, old.ID)
+			}
+			if len(changes) != 1 || changes[0].NewValue != relisted.ID {
+				t.Errorf("relisted changes on the old row: %+v (want one pointing at %s)", changes, relisted.ID)
+			}
+			if !gotFirstSeen.Equal(firstSeen) {
+				t.Errorf("first seen %v (want the old listing's %v)", gotFirstSeen, firstSeen)
+			}
+		})
+	}
+}
+
+// A re-scrape of a linked listing keeps the link
+func TestRelistLinkSurvivesRescrape(t *testing.T) {
+	db := sqlitetest.Open(t)
+	gdb := database.NewGormDBFromDB(db)
+
+	old := unit("old2")
+	if err := gdb.SaveProperty(old); err != nil {
+		t.Fatalf("save old listing: %v", err)
+	}
+	if err := gdb.MarkPropertyAsRemoved(old.ID); err != nil {
+		t.Fatalf("remove old listing: %v", err)
+	}
+	if err := gdb.SaveProperty(unit("new2")); err != nil {
+		t.Fatalf("save new listing: %v", err)
+	}
+	again := unit("new2")
+	again.Title = "リバーサイド中野 302（値下げ）"
+	if err := gdb.SaveProperty(again); err != nil {
+		t.Fatalf("re-scrape: %v", err)
+	}
+	saved, err := gdb.GetPropertyByID(again.ID)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if saved.RelistedFrom == nil || *saved.RelistedFrom != old.ID {
+		t.Errorf("relisted_from after a re-scrape: %v (want %s)", saved.RelistedFrom, old.ID)
+	}
+	var n int64
+	db.Model(&models.PropertyChange{}).Where("change_type = ?", models.ChangeTypeRelisted).Count(&n)
+	if n != 1 {
+		t.Errorf("%d relisted changes (want 1)", n)
+	}
+}
@@ -0,0 +1,327 @@
+package devseed
+
+import (
+	"crypto/md5"
+	"fmt"
+	"log"
+	"math/rand"
+	"real-estate-portal/internal/database"
+	"real-estate-portal/internal/models"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// SeedSource tags every seeded row (properties.source) so Reset can remove exactly those rows
+const SeedSource = "seed"
+
+// MaxSeedCount caps a single seed request
+const MaxSeedCount = 2000
+
+// CanonicalFloorPlans is the set of floor plans the generator draws from (a subset of models.FloorPlans)
+var CanonicalFloorPlans = []string{"1R", "1K", "1DK", "1LDK", "2K", "2DK", "2LDK", "3LDK"}
+
+// baseRentByFloorPlan is the median rent (yen) per floor plan used for the distribution
+var baseRentByFloorPlan = map[string]int{
+	"1R": 70000, "1K": 80000, "1DK": 95000, "1LDK": 130000,
+	"2K": 100000, "2DK": 120000, "2LDK": 170000, "3LDK": 230000,
+}
+
+// Rent bounds for generated properties (yen)
+const (
+	MinSeedRent = 30000
+	MaxSeedRent = 500000
+)
+
+type seedStation struct {
+	Name string
+	Line string
+	Ward string
+}
+
+// seedStations is a built-in list of Tokyo stations with a line and ward
+var seedStations = []seedStation{
+	{"新宿", "JR山手線", "新宿区"},
+	{"新宿三丁目", "東京メトロ丸ノ内線", "新宿区"},
+	{"渋谷", "東急東横線", "渋谷区"},
+	{"恵比寿", "JR山手線", "渋谷区"},
+	{"中目黒", "東急東横線", "目黒区"},
+	{"学芸大学", "東急東横線", "目黒区"},
+	{"池袋", "JR山手線", "豊島区"},
+	{"高田馬場", "JR山手線", "新宿区"},
+	{"中野", "JR中央線", "中野区"},
+	{"高円寺", "JR中央線", "杉並区"},
+	{"荻窪", "東京メトロ丸ノ内線", "杉並区"},
+	{"三軒茶屋", "東急田園都市線", "世田谷区"},
+	{"下北沢", "小田急線", "世田谷区"},
+	{"北千住", "東京メトロ日比谷線", "足立区"},
+	{"錦糸町", "JR総武線", "墨田区"},
+	{"門前仲町", "東京メトロ東西線", "江東区"},
+}
+
+var seedBuildingTypes = []string{models.BuildingTypeMansion, models.BuildingTypeApartment, models.BuildingTypeHouse}
+
+// Service generates and removes development seed data
+type Service struct {
+	gormDB *database.GormDB
+	rng    *rand.Rand // safe for concurrent Seed calls (see lockedSource)
+}
+
+// NewService creates a new seed service
+func NewService(gormDB *database.GormDB) *Service {
+	return &Service{
+		gormDB: gormDB,
+		rng:    rand.New(&lockedSource{src: rand.NewSource(time.Now().UnixNano()).(rand.Source64)}),
+	}
+}
+
+// lockedSource serializes a rand.Source, so concurrent seed requests can share one generator
+type lockedSource struct {
+	mu  sync.Mutex
+	src rand.Source64
+}
+
+func (s *lockedSource) Int63() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.src.Int63()
+}
+
+func (s *lockedSource) Uint64() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.src.Uint64()
+}
+
+func (s *lockedSource) Seed(seed int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.src.Seed(seed)
+}
+
+// SeedResult holds the result of a seed run
+type SeedResult struct {
+	Created    int               `json:"created"`
+	Removed    int               `json:"removed"`
+	Snapshots  int               `json:"snapshots"`
+	Changes    int               `json:"changes"`
+	Errors     []string          `json:"errors,omitempty"`
+	Properties []models.Property `json:"-"`
+}
+
+// Seed generates count plausible properties with stations, snapshots and changes
+// Properties are saved through GormDB.SavePropertyWithStations like scraped ones.
+func (s *Service) Seed(count int) (*SeedResult, error) {
+	if count <= 0 {
+		return nil, fmt.Errorf("count must be positive")
+	}
+	if count > MaxSeedCount {
+		return nil, fmt.Errorf("count: maximum %d allowed", MaxSeedCount)
+	}
+
+	result := &SeedResult{}
+	batch := time.Now().UnixNano()
+
+	for i := 0; i < count; i++ {
+		p, stations := s.generateProperty(batch, i)
+
+		if err := s.gormDB.SavePropertyWithStations(p, stations); err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", p.ID, err))
+			continue
+		}
+		result.Created++
+		if p.Status == models.PropertyStatusRemoved {
+			result.Removed++
+		}
+
+		// A few daily snapshots, with an occasional rent change
+		snapshots, changes, err := s.generateHistory(p)
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("%s history: %v", p.ID, err))
+		}
+		result.Snapshots += snapshots
+		result.Changes += changes
+
+		result.Properties = append(result.Properties, *p)
+	}
+
+	log.Printf("DevSeed: created %d properties (%d removed), %d snapshots, %d changes",
+		result.Created, result.Removed, result.Snapshots, result.Changes)
+	return result, nil
+}
+
+// generateProperty builds one property and its stations
+func (s *Service) generateProperty(batch int64, i int) (*models.Property, []models.PropertyStation) {
+	sourceID := fmt.Sprintf("seed-%d-%05d", batch, i)
+	detailURL := fmt.Sprintf("https://example.invalid/seed/%s", sourceID)
+
+	floorPlan := CanonicalFloorPlans[s.rng.Intn(len(CanonicalFloorPlans))]
+	station := seedStations[s.rng.Intn(len(seedStations))]
+
+	// Rent: median per floor plan with ±30% noise, rounded to 1,000 yen
+	base := baseRentByFloorPlan[floorPlan]
+	rent := int(float64(base)*(0.7+0.6*s.rng.Float64())) / 1000 * 1000
+	if rent < MinSeedRent {
+		rent = MinSeedRent
+	}
+	if rent > MaxSeedRent {
+		rent = MaxSeedRent
+	}
+
+	walk := 1 + s.rng.Intn(20)
+	area := float64(15+s.rng.Intn(60)) + float64(s.rng.Intn(100))/100
+	age := s.rng.Intn(50)
+	floor := 1 + s.rng.Intn(15)
+
+	p := &models.Property{
+		ID:               fmt.Sprintf("%x", md5.Sum([]byte(detailURL))),
+		Source:           SeedSource,
+		SourcePropertyID: sourceID,
+		DetailURL:        detailURL,
+		Title:            fmt.Sprintf("%s %s駅 %s", station.Ward, station.Name, floorPlan),
+		ImageURL:         fmt.Sprintf("https://example.invalid/seed/images/%s.jpg", sourceID),
+		Rent:             &rent,
+		FloorPlan:        floorPlan,
+		Area:             &area,
+		WalkTime:         &walk,
+		Station:          station.Name,
+		Address:          "東京都" + station.Ward,
+		BuildingAge:      &age,
+		Floor:            &floor,
+		BuildingType:     seedBuildingTypes[s.rng.Intn(len(seedBuildingTypes))],
+		Facilities:       "[]",
+		Features:         "[]",
+		ManagementFee:    fmt.Sprintf("%d円", (s.rng.Intn(10)+1)*1000),
+		Deposit:          "1ヶ月",
+		KeyMoney:         []string{"なし", "1ヶ月"}[s.rng.Intn(2)],
+		Status:           models.PropertyStatusActive,
+	}
+
+	// Some rows with missing fields (~15%)
+	if s.rng.Intn(100) < 15 {
+		p.Area = nil
+		p.BuildingAge = nil
+		p.ManagementFee = ""
+	}
+
+	// A share of removed properties (~10%)
+	if s.rng.Intn(100) < 10 {
+		p.MarkAsRemoved()
+	}
+
+	stations := []models.PropertyStation{{
+		PropertyID:  p.ID,
+		StationName: station.Name,
+		LineName:    station.Line,
+		WalkMinutes: walk,
+		SortOrder:   1,
+	}}
+	if s.rng.Intn(2) == 0 {
+		second := seedStations[s.rng.Intn(len(seedStations))]
+		if second.Name != station.Name {
+			stations = append(stations, models.PropertyStation{
+				PropertyID:  p.ID,
+				StationName: second.Name,
+				LineName:    second.Line,
+				WalkMinutes: walk + 3 + s.rng.Intn(10),
+				SortOrder:   2,
+			})
+		}
+	}
+
+	return p, stations
+}
+
+// generateHistory creates past daily snapshots; about a quarter of properties get a rent cut
+// between the oldest and the next snapshot, recorded as a rent change
+func (s *Service) generateHistory(p *models.Property) (snapshots int, changes int, err error) {
+	db := s.gormDB.DB()
+	days := 1 + s.rng.Intn(3)
+	rent := *p.Rent
+	oldRent := rent
+	if days >= 2 && s.rng.Intn(4) == 0 {
+		oldRent = rent + 5000
+	}
+
+	for d := days; d >= 1; d-- {
+		snapRent := rent
+		if d == days {
+			snapRent = oldRent
+		}
+		snap := models.PropertySnapshot{
+			PropertyID:  p.ID,
+			SnapshotAt:  time.Now().AddDate(0, 0, -d).Truncate(24 * time.Hour),
+			Rent:        &snapRent,
+			FloorPlan:   p.FloorPlan,
+			Area:        p.Area,
+			WalkTime:    p.WalkTime,
+			BusMinutes:  p.BusMinutes,
+			Station:     p.Station,
+			Address:     p.Address,
+			BuildingAge: p.BuildingAge,
+			Floor:       p.Floor,
+			ImageURL:    p.ImageURL,
+			Status:      string(models.PropertyStatusActive),
+			HasChanged:  d == days-1 && oldRent != rent,
+		}
+		if err := db.Create(&snap).Error; err != nil {
+			return snapshots, changes, err
+		}
+		snapshots++
+
+		if snap.HasChanged {
+			magnitude := float64(rent - oldRent)
+			change := models.PropertyChange{
+				PropertyID:      p.ID,
+				SnapshotID:      snap.ID,
+				ChangeType:      models.ChangeTypeRent,
+				OldValue:        fmt.Sprintf("%d", oldRent),
+				NewValue:        fmt.Sprintf("%d", rent),
+				ChangeMagnitude: &magnitude,
+			}
+			if err := db.Create(&change).Error; err != nil {
+				return snapshots, changes, err
+			}
+			changes++
+		}
+	}
+
+	return snapshots, changes, nil
+}
+
+// Reset deletes all seeded rows (tracked by properties.source = SeedSource)
+// Returns the IDs of the removed properties
+func (s *Service) Reset() ([]string, error) {
+	db := s.gormDB.DB()
+
+	var ids []string
+	if err := db.Model(&models.Property{}).Where("source = ?", SeedSource).Pluck("id", &ids).Error; err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return ids, nil
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		for _, m := range []interface{}{
+			&models.PropertyChange{},
+			&models.PropertySnapshot{},
+			&models.PropertyStation{},
+			&models.PropertyImage{},
+		} {
+			if err := tx.Where("property_id IN ?", ids).Delete(m).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Where("source = ?", SeedSource).Delete(&models.Property{}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	// The delete callback fired inside the transaction; drop pages cached before the commit too
+	s.gormDB.InvalidateListingCache()
+
+	log.Printf("DevSeed: reset removed %d seeded properties", len(ids))
+	return ids, nil
+}
@@ -0,0 +1,92 @@
+package devseed_test
+
+import (
+	"real-estate-portal/internal/database"
+	"real-estate-portal/internal/devseed"
+	"real-estate-portal/internal/models"
+	"real-estate-portal/internal/sqlitetest"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+func count(t *testing.T, db *gorm.DB, model interface{}, query string, args ...interface{}) int64 {
+	t.Helper()
+	var n int64
+	if err := db.Model(model).Where(query, args...).Count(&n).Error; err != nil {
+		t.Fatalf("count %T: %v", model, err)
+	}
+	return n
+}
+
+// Reset removes the seeded rows with their stations, snapshots and changes, and nothing else
+func TestSeedThenReset(t *testing.T) {
+	db := sqlitetest.Open(t)
+	gdb := database.NewGormDBFromDB(db)
+	service := devseed.NewService(gdb)
+
+	rent := 85000
+	scraped := &models.Property{ID: "scraped-01", Source: "yahoo", SourcePropertyID: "scraped01",
+		DetailURL: "https://realestate.example/rent/detail/scraped01/", Title: "スクレイプ物件", Rent: &rent,
+		Status: models.PropertyStatusActive}
+	station := []models.PropertyStation{{PropertyID: scraped.ID, StationName: "中野", LineName: "JR中央線", SortOrder: 1}}
+	if err := gdb.SavePropertyWithStations(scraped, station); err != nil {
+		t.Fatalf("save scraped: %v", err)
+	}
+	if err := db.Create(&models.PropertySnapshot{PropertyID: scraped.ID, Rent: &rent}).Error; err != nil {
+		t.Fatalf("scraped snapshot: %v", err)
+	}
+
+	const n = 30
+	result, err := service.Seed(n)
+	if err != nil {
+		t.Fatalf("Seed: %v", err)
+	}
+	if result.Created != n || len(result.Errors) != 0 {
+		t.Fatalf("seeded %d with errors %v (want %d)", result.Created, result.Errors, n)
+	}
+	if got := count(t, db, &models.Property{}, "source = ?", devseed.SeedSource); got != n {
+		t.Fatalf("%d seeded rows (want %d)", got, n)
+	}
+
+	// Cache the landing page with the seeded rows on it
+	page, err := gdb.GetPropertiesWithFiltersPaginated(database.PropertyFilters{})
+	if err != nil || page.Total <= 1 {
+		t.Fatalf("landing page before reset: total %v, err %v", page, err)
+	}
+
+	ids, err := service.Reset()
+	if err != nil {
+		t.Fatalf("Reset: %v", err)
+	}
+	if len(ids) != n {
+		t.Errorf("reset removed %d properties (want %d)", len(ids), n)
+	}
+
+	for _, m := range []interface{}{&models.PropertyStation{}, &models.PropertySnapshot{}, &models.PropertyChange{}} {
+		if got := count(t, db, m, "property_id IN ?", ids); got != 0 {
+			t.Errorf("%d %T rows of seeded properties left", got, m)
+		}
+	}
+	for _, m := range []interface{}{&models.PropertyStation{}, &models.PropertySnapshot{}} {
+		if got := count(t, db, m, "property_id = ?", scraped.ID); got != 1 {
+			t.Errorf("%d %T rows of the scraped property (want 1)", got, m)
+		}
+	}
+	if got := count(t, db, &models.Property{}, "1 = 1"); got != 1 {
+		t.Errorf("%d properties left (want the scraped one)", got)
+	}
+
+	page, err = gdb.GetPropertiesWithFiltersPaginated(database.PropertyFilters{})
+	if err != nil {
+		t.Fatalf("landing page after reset: %v", err)
+	}
+	if page.Total != 1 || len(page.Properties) != 1 || page.Properties[0].ID != scraped.ID {
+		t.Errorf("landing page after reset: total %d, %d rows (want only the scraped one)", page.Total, len(page.Properties))
+	}
+
+	// A second reset has nothing to remove
+	if ids, err := service.Reset(); err != nil || len(ids) != 0 {
+		t.Errorf("second Reset: %d ids, err %v (want none)", len(ids), err)
+	}
+}
@@ -0,0 +1,31 @@
+package devseed
+
+import (
+	"sync"
+	"testing"
+)
+
+// Concurrent seed requests share the service's generator (run with -race)
+func TestGeneratePropertyConcurrent(t *testing.T) {
+	s := NewService(nil)
+	plans := make(map[string]bool)
+	for _, fp := range CanonicalFloorPlans {
+		plans[fp] = true
+	}
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				p, stations := s.generateProperty(int64(g), i)
+				if *p.Rent < MinSeedRent || *p.Rent > MaxSeedRent || !plans[p.FloorPlan] || len(stations) == 0 {
+					t.Errorf("%s: rent %d, floor plan %q, %d stations", p.ID, *p.Rent, p.FloorPlan, len(stations))
+					return
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+}
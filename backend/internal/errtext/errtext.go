@@ -0,0 +1,148 @@
+// Package errtext turns raw error strings into bounded, credential-free text plus a
+// short code, for storing in queue rows and returning in API responses.
+package errtext
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync/atomic"
+	"unicode/utf8"
+)
+
+// Error codes stored in detail_scrape_queue.last_error_code
+const (
+	CodeNotFound    = "not_found"
+	CodeWAF         = "waf_blocked"
+	CodeCircuitOpen = "circuit_open"
+	CodeRateLimited = "rate_limited"
+	CodeForbidden   = "forbidden"
+	CodeTimeout     = "timeout"
+	CodeServerError = "server_error"
+	CodeNetwork     = "network"
+	CodeDatabase    = "database"
+	CodeParse       = "parse_error"
+	CodeRobots      = "robots_disallowed"
+	CodeUnsupported = "unsupported_source"
+	CodeProxy       = "proxy_error"
+	CodeDelisted    = "delisted"
+	CodeBuilding    = "building_page" // a building page listing several units: the units were queued instead
+	CodeFresh       = "skipped_fresh" // done without a request: fetched within min_refetch_interval
+	CodeUnknown     = "unknown"
+)
+
+// DefaultMaxLength is the stored message cap in characters
+const DefaultMaxLength = 500
+
+var maxLength atomic.Int64
+
+func init() {
+	maxLength.Store(DefaultMaxLength)
+}
+
+// SetMaxLength changes the message cap (values <= 0 restore the default). Call at startup.
+func SetMaxLength(n int) {
+	if n <= 0 {
+		n = DefaultMaxLength
+	}
+	maxLength.Store(int64(n))
+}
+
+// MaxLength returns the current message cap
+func MaxLength() int {
+	return int(maxLength.Load())
+}
+
+var (
+	// HTML documents or fragments: from the first block-level tag through the last '>'
+	htmlBlobPattern = regexp.MustCompile(`(?is)<(?:!doctype|html|head|body|script|style|div|table|p|span|meta|title)\b.*>`)
+	// key=value pairs in query strings whose names look like secrets
+	queryCredentialPattern = regexp.MustCompile(`(?i)([?&;](?:[a-z_]*token|[a-z_]*key|password|passwd|pwd|secret|sig|signature|auth|session|sid)=)[^&\s"'<>]+`)
+	// user:password@ in URLs and DSNs (e.g. mysql "user:pass@tcp(host)/db")
+	userinfoPattern = regexp.MustCompile(`([A-Za-z0-9_.\-]+):[^@\s/:]+@(tcp\(|unix\(|[A-Za-z0-9.\-\[])`)
+	statusPattern   = regexp.MustCompile(`\b(?:status(?: code)?|HTTP)[ :=]*([1-5]\d\d)\b`)
+)
+
+// Clean removes HTML bodies and credentials from msg and truncates it to MaxLength
+func Clean(msg string) string {
+	msg = strip(msg)
+	if limit := MaxLength(); utf8.RuneCountInString(msg) > limit {
+		runes := []rune(msg)
+		msg = string(runes[:limit]) + "…(truncated)"
+	}
+	return msg
+}
+
+// Classify maps an error message to a short code ("" for an empty message)
+func Classify(msg string) string {
+	if msg == "" {
+		return ""
+	}
+	// Classify on stripped text so numbers inside an HTML body don't match
+	lower := strings.ToLower(strip(msg))
+
+	status := ""
+	if m := statusPattern.FindStringSubmatch(lower); m != nil {
+		status = m[1]
+	}
+
+	switch {
+	// Only status codes count: a bare "404" may be part of a title, room number or URL
+	case strings.Contains(lower, "permanent_fail") || status == "404" || strings.Contains(lower, "404 not found"):
+		return CodeNotFound
+	case strings.Contains(lower, "disallowed by robots.txt"):
+		return CodeRobots
+	case strings.Contains(lower, "no scraper registered"):
+		return CodeUnsupported
+	case strings.Contains(lower, "circuit breaker"):
+		return CodeCircuitOpen
+	// Before timeout / network: the failure was at the proxy, not at the site
+	case strings.Contains(lower, "proxy error") || strings.Contains(lower, "proxyconnect"):
+		return CodeProxy
+	case strings.Contains(lower, "waf"):
+		return CodeWAF
+	case status == "429" || strings.Contains(lower, "too many requests") || strings.Contains(lower, "rate limit"):
+		return CodeRateLimited
+	case status == "403" || strings.Contains(lower, "forbidden"):
+		return CodeForbidden
+	case strings.Contains(lower, "timeout") || strings.Contains(lower, "deadline exceeded"):
+		return CodeTimeout
+	case strings.Contains(lower, "database") || strings.Contains(lower, "mysql") || strings.Contains(lower, "sql:"):
+		return CodeDatabase
+	case strings.Contains(lower, "connection refused") || strings.Contains(lower, "no such host") ||
+		strings.Contains(lower, "connection reset") || strings.HasSuffix(lower, "eof"):
+		return CodeNetwork
+	case strings.Contains(lower, "parse") || strings.Contains(lower, "json") || strings.Contains(lower, "script not found"):
+		return CodeParse
+	}
+	if status != "" && status[0] == '5' {
+		return CodeServerError
+	}
+	return CodeUnknown
+}
+
+// FromError returns the code and cleaned message for err (both empty for nil).
+// An error in the chain that reports its own code (ErrorCode() string, e.g. the scraper's
+// sentinel errors) wins over classifying the message text.
+func FromError(err error) (code, message string) {
+	if err == nil {
+		return "", ""
+	}
+	msg := err.Error()
+	var coded interface{ ErrorCode() string }
+	if errors.As(err, &coded) {
+		return coded.ErrorCode(), Clean(msg)
+	}
+	return Classify(msg), Clean(msg)
+}
+
+// strip removes HTML blobs and credentials without truncating
+func strip(msg string) string {
+	msg = htmlBlobPattern.ReplaceAllStringFunc(msg, func(blob string) string {
+		return fmt.Sprintf("[html omitted: %d bytes]", len(blob))
+	})
+	msg = queryCredentialPattern.ReplaceAllString(msg, "${1}***")
+	msg = userinfoPattern.ReplaceAllString(msg, "${1}:***@${2}")
+	return strings.TrimSpace(msg)
+}
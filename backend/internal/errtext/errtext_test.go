@@ -0,0 +1,112 @@
+package errtext
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+// htmlBody is a 50KB error page that mentions 404, to check classification ignores page content
+func htmlBody() string {
+	var body strings.Builder
+	body.WriteString("<!DOCTYPE html><html><head><title>Error</title></head><body>")
+	for body.Len() < 50*1024 {
+		body.WriteString("<div class=\"msg\">お探しのページ(404)は見つかりませんでした</div>")
+	}
+	body.WriteString("</body></html>")
+	return body.String()
+}
+
+func TestFromErrorSanitizes(t *testing.T) {
+	raw := fmt.Errorf("request failed after 3 retries: status code 503 url=https://realestate.yahoo.co.jp/rent/detail/abc/?sid=s3cr3t&page=1 dsn=app:hunter2@tcp(db:3306)/portal body=%s", htmlBody())
+
+	code, msg := FromError(raw)
+	if code != CodeServerError {
+		t.Errorf("code = %s (want %s)", code, CodeServerError)
+	}
+	if limit := MaxLength() + utf8.RuneCountInString("…(truncated)"); utf8.RuneCountInString(msg) > limit {
+		t.Errorf("length = %d (limit %d)", utf8.RuneCountInString(msg), limit)
+	}
+	for _, leak := range []string{"<html", "<div", "s3cr3t", "hunter2"} {
+		if strings.Contains(msg, leak) {
+			t.Errorf("message contains %q: %s", leak, msg)
+		}
+	}
+	for _, keep := range []string{"status code 503", "sid=***", "app:***@tcp(", "[html omitted:"} {
+		if !strings.Contains(msg, keep) {
+			t.Errorf("message lost %q: %s", keep, msg)
+		}
+	}
+}
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		msg  string
+		want string
+	}{
+		{"", ""},
+		{"status code 404", CodeNotFound},
+		{"HTTP 404 Not Found", CodeNotFound},
+		{"permanent_fail: gone", CodeNotFound},
+		{"room 404 is nice", CodeUnknown},
+		{"status code 503 body=" + htmlBody(), CodeServerError},
+		{"status code 500", CodeServerError},
+		{"status code 429", CodeRateLimited},
+		{"status code 403", CodeForbidden},
+		{"WAF block detected", CodeWAF},
+		{"circuit breaker is open", CodeCircuitOpen},
+		{"proxyconnect tcp: dial failed", CodeProxy},
+		{"context deadline exceeded", CodeTimeout},
+		{"dial tcp: connection refused", CodeNetwork},
+		{"unexpected EOF", CodeNetwork},
+		{"mysql: server gone away", CodeDatabase},
+		{"failed to parse JSON", CodeParse},
+		{"disallowed by robots.txt", CodeRobots},
+		{"no scraper registered for host", CodeUnsupported},
+		{"something odd", CodeUnknown},
+	}
+	for _, tt := range tests {
+		name := tt.msg
+		if len(name) > 40 {
+			name = name[:40]
+		}
+		t.Run(name, func(t *testing.T) {
+			if got := Classify(tt.msg); got != tt.want {
+				t.Errorf("Classify = %q (want %q)", got, tt.want)
+			}
+		})
+	}
+}
+
+type codedError struct{ code string }
+
+func (e codedError) Error() string     { return "status code 503 from upstream" }
+func (e codedError) ErrorCode() string { return e.code }
+
+func TestFromErrorCodedChain(t *testing.T) {
+	err := fmt.Errorf("scrape: %w", codedError{CodeDelisted})
+	if code, _ := FromError(err); code != CodeDelisted {
+		t.Errorf("code = %s (want the chain's %s)", code, CodeDelisted)
+	}
+	if code, msg := FromError(nil); code != "" || msg != "" {
+		t.Errorf("nil error: %q %q", code, msg)
+	}
+	if code, _ := FromError(errors.New("status 503")); code != CodeServerError {
+		t.Errorf("plain error: %s", code)
+	}
+}
+
+func TestSetMaxLength(t *testing.T) {
+	t.Cleanup(func() { SetMaxLength(0) })
+
+	SetMaxLength(10)
+	if got := Clean(strings.Repeat("あ", 20)); got != strings.Repeat("あ", 10)+"…(truncated)" {
+		t.Errorf("Clean = %q", got)
+	}
+	SetMaxLength(-1)
+	if MaxLength() != DefaultMaxLength {
+		t.Errorf("MaxLength = %d after reset (want %d)", MaxLength(), DefaultMaxLength)
+	}
+}
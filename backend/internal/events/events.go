@@ -0,0 +1,173 @@
+// Package events is a small in-process bus for queue and scheduler progress, streamed to admins
+// over Server-Sent Events (GET /api/admin/events). Publishing never blocks: a subscriber that
+// falls behind loses its oldest events, so a slow or gone client cannot hold up the worker.
+package events
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Event types
+const (
+	ItemStarted     = "item_started"     // the worker took a queue item
+	ItemDone        = "item_done"        // the item is done (property_id set when a listing was saved or confirmed)
+	ItemFailed      = "item_failed"      // the item failed (retried later or permanently)
+	CooldownEntered = "cooldown_entered" // the worker stopped taking items until a time
+	RunFinished     = "run_finished"     // a scheduler run (scheduled or manual) ended
+)
+
+// Types lists every event type, in the order above
+var Types = []string{ItemStarted, ItemDone, ItemFailed, CooldownEntered, RunFinished}
+
+// Event is one progress event; only the fields of its type are set
+type Event struct {
+	ID   int64     `json:"id"` // increasing per process (the SSE id)
+	Type string    `json:"type"`
+	Time time.Time `json:"time"`
+
+	// Queue items
+	ItemID     int64  `json:"item_id,omitempty"`
+	Source     string `json:"source,omitempty"`
+	URL        string `json:"url,omitempty"`
+	Attempt    int    `json:"attempt,omitempty"`
+	PropertyID string `json:"property_id,omitempty"`
+	Status     string `json:"status,omitempty"`      // the item's or run's final status
+	ErrorClass string `json:"error_class,omitempty"` // last_error_code, e.g. waf_blocked, timeout
+	Failure    string `json:"failure,omitempty"`     // how the worker handles it: retry, permanent, cooldown...
+	Error      string `json:"error,omitempty"`
+
+	// Cooldowns
+	Reason string     `json:"reason,omitempty"`
+	Until  *time.Time `json:"until,omitempty"` // also next_retry_at of a failed item
+
+	// Scheduler runs
+	RunID      uint   `json:"run_id,omitempty"`
+	Trigger    string `json:"trigger,omitempty"`
+	Schedule   string `json:"schedule,omitempty"`
+	Discovered int    `json:"discovered,omitempty"`
+	Enqueued   int    `json:"enqueued,omitempty"`
+}
+
+// ParseTypes reads a type filter from query values, each one type or a comma-separated list
+// (none: every type)
+func ParseTypes(values []string) ([]string, error) {
+	var types []string
+	for _, value := range values {
+		for _, t := range strings.Split(value, ",") {
+			if t = strings.TrimSpace(t); t == "" {
+				continue
+			}
+			if !slices.Contains(Types, t) {
+				return nil, fmt.Errorf("unknown event type %q (allowed: %s)", t, strings.Join(Types, ", "))
+			}
+			types = append(types, t)
+		}
+	}
+	return types, nil
+}
+
+// Bus fans published events out to its subscribers
+type Bus struct {
+	mu     sync.Mutex
+	nextID int64
+	subs   map[*Subscription]struct{}
+}
+
+// NewBus returns an empty bus
+func NewBus() *Bus {
+	return &Bus{subs: make(map[*Subscription]struct{})}
+}
+
+// Default is the process-wide bus the worker and scheduler publish to
+var Default = NewBus()
+
+// Publish sends e to Default
+func Publish(e Event) {
+	Default.Publish(e)
+}
+
+// Subscription receives the events of the types it asked for until Close
+type Subscription struct {
+	bus     *Bus
+	ch      chan Event
+	types   []string // empty: every type
+	dropped atomic.Int64
+}
+
+// Subscribe returns a subscription buffering up to buffer events of types (none: every type)
+func (b *Bus) Subscribe(buffer int, types ...string) *Subscription {
+	sub := &Subscription{bus: b, ch: make(chan Event, max(buffer, 1)), types: types}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.subs[sub] = struct{}{}
+	return sub
+}
+
+// Publish stamps e with an ID and time (if unset) and hands it to every matching subscriber.
+// A full subscriber drops its oldest event to make room.
+func (b *Bus) Publish(e Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.nextID++
+	e.ID = b.nextID
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+	for sub := range b.subs {
+		if len(sub.types) > 0 && !slices.Contains(sub.types, e.Type) {
+			continue
+		}
+		sub.send(e)
+	}
+}
+
+// send never blocks: only Publish sends, under the bus lock, so after dropping one event there is room
+func (s *Subscription) send(e Event) {
+	select {
+	case s.ch <- e:
+		return
+	default:
+	}
+	select {
+	case <-s.ch:
+		s.dropped.Add(1)
+	default:
+	}
+	s.ch <- e
+}
+
+// CloseAll ends every subscription (e.g. on server shutdown, so open streams return)
+func (b *Bus) CloseAll() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for sub := range b.subs {
+		delete(b.subs, sub)
+		close(sub.ch)
+	}
+}
+
+// Events returns the channel events arrive on; it is closed by Close or CloseAll
+func (s *Subscription) Events() <-chan Event {
+	return s.ch
+}
+
+// Dropped returns how many events were dropped because the subscriber fell behind
+func (s *Subscription) Dropped() int64 {
+	return s.dropped.Load()
+}
+
+// Close unsubscribes; closing twice is fine
+func (s *Subscription) Close() {
+	s.bus.mu.Lock()
+	defer s.bus.mu.Unlock()
+	if _, ok := s.bus.subs[s]; ok {
+		delete(s.bus.subs, s)
+		close(s.ch)
+	}
+}
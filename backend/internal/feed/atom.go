@@ -0,0 +1,178 @@
+package feed
+
+import (
+	"encoding/xml"
+	"fmt"
+	"math"
+	"real-estate-portal/internal/models"
+	"strconv"
+	"time"
+)
+
+// Atom 1.0 feed output for change / new-listing subscriptions
+
+const atomNS = "http://www.w3.org/2005/Atom"
+
+// Feed is an Atom <feed> element
+type Feed struct {
+	XMLName xml.Name `xml:"feed"`
+	XMLNS   string   `xml:"xmlns,attr"`
+	ID      string   `xml:"id"`
+	Title   string   `xml:"title"`
+	Updated string   `xml:"updated"`
+	Author  Person   `xml:"author"`
+	Links   []Link   `xml:"link"`
+	Entries []Entry  `xml:"entry"`
+}
+
+// Person is an Atom person construct
+type Person struct {
+	Name string `xml:"name"`
+}
+
+// Link is an Atom <link> element
+type Link struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+// Entry is an Atom <entry> element
+type Entry struct {
+	ID      string   `xml:"id"`
+	Title   string   `xml:"title"`
+	Updated string   `xml:"updated"`
+	Links   []Link   `xml:"link"`
+	Summary string   `xml:"summary,omitempty"`
+	Content *Content `xml:"content,omitempty"`
+}
+
+// Content is an Atom <content> element (used when an entry has no alternate link)
+type Content struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+// feedAuthor is used for the required feed-level author
+const feedAuthor = "shiboroom"
+
+// NewFeed creates an empty feed. updated is the newest entry time (or now for an empty feed).
+func NewFeed(id, title, selfURL string, updated time.Time) *Feed {
+	return &Feed{
+		XMLNS:   atomNS,
+		ID:      id,
+		Title:   title,
+		Updated: formatTime(updated),
+		Author:  Person{Name: feedAuthor},
+		Links:   []Link{{Href: selfURL, Rel: "self", Type: "application/atom+xml"}},
+		Entries: []Entry{},
+	}
+}
+
+// Render encodes the feed as an XML document
+func (f *Feed) Render() ([]byte, error) {
+	body, err := xml.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), body...), nil
+}
+
+// ChangeEntry builds an entry for a property change. property may be nil if it was deleted.
+func ChangeEntry(change models.PropertyChange, property *models.Property) Entry {
+	name := change.PropertyID
+	link := ""
+	if property != nil {
+		name = displayName(property)
+		link = property.DetailURL
+	}
+
+	entry := Entry{
+		ID:      fmt.Sprintf("urn:shiboroom:change:%s:%d", change.PropertyID, change.ID),
+		Title:   changeTitle(change, name),
+		Updated: formatTime(change.DetectedAt),
+		Summary: fmt.Sprintf("%s: %s → %s", change.ChangeType, change.OldValue, change.NewValue),
+	}
+	if link != "" {
+		entry.Links = []Link{{Href: link, Rel: "alternate", Type: "text/html"}}
+	} else {
+		// Atom requires content when there is no alternate link
+		entry.Content = &Content{Type: "text", Body: entry.Summary}
+	}
+	return entry
+}
+
+// NewListingEntry builds an entry for a newly listed property
+func NewListingEntry(property models.Property) Entry {
+	title := "【新着】" + displayName(&property)
+	if property.Rent != nil {
+		title += " " + FormatMan(*property.Rent)
+	}
+
+	summary := property.Station
+	if property.FloorPlan != "" {
+		summary += " " + property.FloorPlan
+	}
+	if property.Address != "" {
+		summary += " " + property.Address
+	}
+
+	return Entry{
+		ID:      "urn:shiboroom:property:" + property.ID,
+		Title:   title,
+		Updated: formatTime(property.CreatedAt),
+		Links:   []Link{{Href: property.DetailURL, Rel: "alternate", Type: "text/html"}},
+		Summary: summary,
+	}
+}
+
+// changeTitle renders a Japanese title such as 【値下げ】○○マンション 8.5万→8.2万
+func changeTitle(change models.PropertyChange, name string) string {
+	switch change.ChangeType {
+	case models.ChangeTypeRent:
+		oldRent, errOld := strconv.Atoi(change.OldValue)
+		newRent, errNew := strconv.Atoi(change.NewValue)
+		if errOld != nil || errNew != nil {
+			return "【賃料変更】" + name
+		}
+		label := "【値上げ】"
+		if newRent < oldRent {
+			label = "【値下げ】"
+		}
+		return fmt.Sprintf("%s%s %s→%s", label, name, FormatMan(oldRent), FormatMan(newRent))
+	case models.ChangeTypeNew:
+		return "【新着】" + name
+	case models.ChangeTypeRemoved:
+		return "【掲載終了】" + name
+	case models.ChangeTypeRelisted:
+		return "【再掲載】" + name
+	case models.ChangeTypeURLChanged:
+		return "【URL変更】" + name
+	case models.ChangeTypeCampaign:
+		return "【キャンペーン】" + name
+	case models.ChangeTypeStatus:
+		return "【ステータス変更】" + name
+	}
+	return "【変更】" + name
+}
+
+// displayName prefers the building name and falls back to the listing title
+func displayName(p *models.Property) string {
+	if p.BuildingName != "" {
+		return p.BuildingName
+	}
+	return p.Title
+}
+
+// FormatMan formats a yen amount in 万 units (85000 -> "8.5万")
+func FormatMan(yen int) string {
+	man := math.Round(float64(yen)/100) / 100
+	return strconv.FormatFloat(man, 'f', -1, 64) + "万"
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		t = time.Now()
+	}
+	return t.UTC().Format(time.RFC3339)
+}
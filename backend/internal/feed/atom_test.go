@@ -0,0 +1,284 @@
+package feed
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/url"
+	"real-estate-portal/internal/models"
+	"strings"
+	"testing"
+	"time"
+)
+
+// atomNode is a generic element tree, so validation doesn't reuse the encoder's structs
+type atomNode struct {
+	XMLName  xml.Name
+	Attrs    []xml.Attr `xml:",any,attr"`
+	Text     string     `xml:",chardata"`
+	Children []atomNode `xml:",any"`
+}
+
+func (n atomNode) attr(name string) string {
+	for _, a := range n.Attrs {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+func (n atomNode) children(local string) []atomNode {
+	var out []atomNode
+	for _, c := range n.Children {
+		if c.XMLName.Local == local {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// Element model from RFC 4287 (atomFeed / atomEntry in the RELAX NG schema, appendix B)
+var (
+	feedChildren = map[string]bool{
+		"author": true, "category": true, "contributor": true, "generator": true, "icon": true, "id": true,
+		"link": true, "logo": true, "rights": true, "subtitle": true, "title": true, "updated": true, "entry": true,
+	}
+	entryChildren = map[string]bool{
+		"author": true, "category": true, "content": true, "contributor": true, "id": true, "link": true,
+		"published": true, "rights": true, "source": true, "summary": true, "title": true, "updated": true,
+	}
+	textTypes = map[string]bool{"": true, "text": true, "html": true, "xhtml": true}
+)
+
+// validateAtom checks a rendered document against the RFC 4287 schema rules
+func validateAtom(doc []byte) error {
+	var root atomNode
+	if err := xml.Unmarshal(doc, &root); err != nil {
+		return fmt.Errorf("not well-formed: %w", err)
+	}
+	if root.XMLName.Space != atomNS || root.XMLName.Local != "feed" {
+		return fmt.Errorf("root element is {%s}%s, want {%s}feed", root.XMLName.Space, root.XMLName.Local, atomNS)
+	}
+	if err := validateContainer("feed", root, feedChildren); err != nil {
+		return err
+	}
+
+	entries := root.children("entry")
+	for i, entry := range entries {
+		where := fmt.Sprintf("entry[%d]", i)
+		if err := validateContainer(where, entry, entryChildren); err != nil {
+			return err
+		}
+		// atom:feed must have an author unless every entry has one
+		if len(root.children("author")) == 0 && len(entry.children("author")) == 0 {
+			return fmt.Errorf("%s: no atom:author in entry or feed", where)
+		}
+		hasAlternate := false
+		for _, link := range entry.children("link") {
+			if rel := link.attr("rel"); rel == "" || rel == "alternate" {
+				hasAlternate = true
+			}
+		}
+		contents := entry.children("content")
+		if len(contents) > 1 || len(entry.children("summary")) > 1 {
+			return fmt.Errorf("%s: more than one content or summary", where)
+		}
+		if !hasAlternate && len(contents) == 0 {
+			return fmt.Errorf("%s: needs an alternate link or content", where)
+		}
+		for _, content := range contents {
+			if !textTypes[content.attr("type")] && !strings.Contains(content.attr("type"), "/") {
+				return fmt.Errorf("%s: content type %q", where, content.attr("type"))
+			}
+		}
+	}
+	if len(entries) == 0 && len(root.children("author")) == 0 {
+		return fmt.Errorf("feed: no atom:author")
+	}
+	return nil
+}
+
+// validateContainer checks the rules shared by atom:feed and atom:entry
+func validateContainer(where string, n atomNode, allowed map[string]bool) error {
+	for _, c := range n.Children {
+		if c.XMLName.Space != atomNS || !allowed[c.XMLName.Local] {
+			return fmt.Errorf("%s: unexpected element {%s}%s", where, c.XMLName.Space, c.XMLName.Local)
+		}
+	}
+	for _, name := range []string{"id", "title", "updated"} {
+		if got := len(n.children(name)); got != 1 {
+			return fmt.Errorf("%s: %d atom:%s elements, want exactly 1", where, got, name)
+		}
+	}
+	if u, err := url.Parse(strings.TrimSpace(n.children("id")[0].Text)); err != nil || u.Scheme == "" {
+		return fmt.Errorf("%s: id %q is not an absolute IRI", where, n.children("id")[0].Text)
+	}
+	if strings.TrimSpace(n.children("title")[0].Text) == "" {
+		return fmt.Errorf("%s: empty title", where)
+	}
+	for _, text := range append(n.children("title"), n.children("summary")...) {
+		if !textTypes[text.attr("type")] {
+			return fmt.Errorf("%s: %s type %q", where, text.XMLName.Local, text.attr("type"))
+		}
+	}
+	for _, date := range append(n.children("updated"), n.children("published")...) {
+		if _, err := time.Parse(time.RFC3339, strings.TrimSpace(date.Text)); err != nil {
+			return fmt.Errorf("%s: %s %q is not an RFC 3339 date", where, date.XMLName.Local, date.Text)
+		}
+	}
+	for _, author := range n.children("author") {
+		if len(author.children("name")) != 1 {
+			return fmt.Errorf("%s: author without exactly one name", where)
+		}
+	}
+	alternates := map[string]bool{}
+	for _, link := range n.children("link") {
+		href := link.attr("href")
+		if u, err := url.Parse(href); err != nil || u.Scheme == "" {
+			return fmt.Errorf("%s: link href %q is not an absolute IRI", where, href)
+		}
+		if rel := link.attr("rel"); rel == "" || rel == "alternate" {
+			key := link.attr("type") + "|" + link.attr("hreflang")
+			if alternates[key] {
+				return fmt.Errorf("%s: duplicate alternate link for type %q", where, link.attr("type"))
+			}
+			alternates[key] = true
+		}
+	}
+	return nil
+}
+
+func rentChange(id uint, propertyID, oldRent, newRent string, at time.Time) models.PropertyChange {
+	return models.PropertyChange{
+		ID:         id,
+		PropertyID: propertyID,
+		ChangeType: models.ChangeTypeRent,
+		OldValue:   oldRent,
+		NewValue:   newRent,
+		DetectedAt: at,
+	}
+}
+
+func intPtr(v int) *int { return &v }
+
+func TestValidateAtomRejectsInvalid(t *testing.T) {
+	tests := map[string]string{
+		"wrong namespace": `<feed><id>urn:x</id><title>t</title><updated>2025-01-01T00:00:00Z</updated><author><name>a</name></author></feed>`,
+		"missing id":      `<feed xmlns="` + atomNS + `"><title>t</title><updated>2025-01-01T00:00:00Z</updated><author><name>a</name></author></feed>`,
+		"bad date":        `<feed xmlns="` + atomNS + `"><id>urn:x</id><title>t</title><updated>2025/01/01</updated><author><name>a</name></author></feed>`,
+		"no author":       `<feed xmlns="` + atomNS + `"><id>urn:x</id><title>t</title><updated>2025-01-01T00:00:00Z</updated></feed>`,
+		"entry without link or content": `<feed xmlns="` + atomNS + `"><id>urn:x</id><title>t</title><updated>2025-01-01T00:00:00Z</updated><author><name>a</name></author>` +
+			`<entry><id>urn:e</id><title>e</title><updated>2025-01-01T00:00:00Z</updated></entry></feed>`,
+	}
+	for name, doc := range tests {
+		if err := validateAtom([]byte(doc)); err == nil {
+			t.Errorf("%s: accepted", name)
+		}
+	}
+}
+
+func TestChangesFeedIsValidAtom(t *testing.T) {
+	at := time.Date(2025, 6, 10, 9, 30, 0, 0, time.FixedZone("JST", 9*60*60))
+	property := &models.Property{ID: "p1", BuildingName: "○○マンション", DetailURL: "https://realestate.yahoo.co.jp/rent/detail/p1"}
+
+	f := NewFeed("urn:shiboroom:feed:changes", "しぼるーむ 物件の変更", "http://localhost/api/feeds/changes.atom?type=rent_changed", at)
+	f.Entries = append(f.Entries,
+		ChangeEntry(rentChange(7, "p1", "85000", "82000", at), property),
+		ChangeEntry(rentChange(8, "gone", "70000", "75000", at.Add(-time.Hour)), nil),
+		ChangeEntry(models.PropertyChange{ID: 9, PropertyID: "p1", ChangeType: models.ChangeTypeRemoved, DetectedAt: at}, property),
+	)
+
+	doc, err := f.Render()
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	if err := validateAtom(doc); err != nil {
+		t.Fatalf("invalid Atom: %v\n%s", err, doc)
+	}
+
+	var parsed atomNode
+	if err := xml.Unmarshal(doc, &parsed); err != nil {
+		t.Fatal(err)
+	}
+	entries := parsed.children("entry")
+	want := []struct{ id, title, link string }{
+		{"urn:shiboroom:change:p1:7", "【値下げ】○○マンション 8.5万→8.2万", property.DetailURL},
+		{"urn:shiboroom:change:gone:8", "【値上げ】gone 7万→7.5万", ""},
+		{"urn:shiboroom:change:p1:9", "【掲載終了】○○マンション", property.DetailURL},
+	}
+	if len(entries) != len(want) {
+		t.Fatalf("%d entries (want %d)", len(entries), len(want))
+	}
+	for i, w := range want {
+		e := entries[i]
+		if got := e.children("id")[0].Text; got != w.id {
+			t.Errorf("entry %d id = %q (want %q)", i, got, w.id)
+		}
+		if got := e.children("title")[0].Text; got != w.title {
+			t.Errorf("entry %d title = %q (want %q)", i, got, w.title)
+		}
+		link := ""
+		if links := e.children("link"); len(links) > 0 {
+			link = links[0].attr("href")
+		}
+		if link != w.link {
+			t.Errorf("entry %d link = %q (want %q)", i, link, w.link)
+		}
+	}
+	if got := entries[0].children("updated")[0].Text; got != "2025-06-10T00:30:00Z" {
+		t.Errorf("updated = %q (want UTC RFC 3339)", got)
+	}
+}
+
+func TestNewListingsFeedIsValidAtom(t *testing.T) {
+	created := time.Date(2025, 6, 10, 0, 0, 0, 0, time.UTC)
+	f := NewFeed("urn:shiboroom:feed:new-listings", "しぼるーむ 新着物件", "http://localhost/api/feeds/new-listings.atom", created)
+	f.Entries = append(f.Entries, NewListingEntry(models.Property{
+		ID:        "p2",
+		Title:     "渋谷区の1LDK",
+		Rent:      intPtr(123400),
+		Station:   "渋谷駅",
+		FloorPlan: "1LDK",
+		DetailURL: "https://realestate.yahoo.co.jp/rent/detail/p2",
+		CreatedAt: created,
+	}))
+
+	doc, err := f.Render()
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	if err := validateAtom(doc); err != nil {
+		t.Fatalf("invalid Atom: %v\n%s", err, doc)
+	}
+	entry := f.Entries[0]
+	if entry.ID != "urn:shiboroom:property:p2" || entry.Title != "【新着】渋谷区の1LDK 12.34万" || entry.Summary != "渋谷駅 1LDK" {
+		t.Errorf("entry = %+v", entry)
+	}
+}
+
+// An empty feed is still a valid document: feed-level author, updated set, no entries
+func TestEmptyFeedIsValidAtom(t *testing.T) {
+	for _, updated := range []time.Time{time.Now(), {}} {
+		doc, err := NewFeed("urn:shiboroom:feed:changes", "しぼるーむ 物件の変更", "http://localhost/api/feeds/changes.atom", updated).Render()
+		if err != nil {
+			t.Fatalf("render: %v", err)
+		}
+		if err := validateAtom(doc); err != nil {
+			t.Fatalf("invalid Atom: %v\n%s", err, doc)
+		}
+		if strings.Contains(string(doc), "<entry") {
+			t.Errorf("empty feed has entries:\n%s", doc)
+		}
+		if !strings.HasPrefix(string(doc), "<?xml") {
+			t.Errorf("missing XML declaration:\n%s", doc)
+		}
+	}
+}
+
+func TestFormatMan(t *testing.T) {
+	for yen, want := range map[int]string{85000: "8.5万", 82000: "8.2万", 100000: "10万", 123456: "12.35万", 0: "0万"} {
+		if got := FormatMan(yen); got != want {
+			t.Errorf("FormatMan(%d) = %q (want %q)", yen, got, want)
+		}
+	}
+}
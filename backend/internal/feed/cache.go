@@ -0,0 +1,54 @@
+package feed
+
+import (
+	"sync"
+	"time"
+)
+
+// CacheTTL is how long rendered feeds are reused
+const CacheTTL = time.Minute
+
+// Cache holds rendered feed documents keyed by request URI
+type Cache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[string]cacheEntry
+}
+
+type cacheEntry struct {
+	body      []byte
+	expiresAt time.Time
+}
+
+// NewCache creates a feed cache with the given TTL
+func NewCache(ttl time.Duration) *Cache {
+	return &Cache{ttl: ttl, entries: make(map[string]cacheEntry)}
+}
+
+// Get returns a cached document if it hasn't expired
+func (c *Cache) Get(key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok || time.Now().After(entry.expiresAt) {
+		delete(c.entries, key)
+		return nil, false
+	}
+	return entry.body, true
+}
+
+// Set stores a rendered document
+func (c *Cache) Set(key string, body []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Feed URLs vary by query string; drop expired entries so the map stays small
+	now := time.Now()
+	for k, entry := range c.entries {
+		if now.After(entry.expiresAt) {
+			delete(c.entries, k)
+		}
+	}
+	c.entries[key] = cacheEntry{body: body, expiresAt: now.Add(c.ttl)}
+}
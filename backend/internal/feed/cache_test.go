@@ -0,0 +1,33 @@
+package feed
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCacheExpires(t *testing.T) {
+	c := NewCache(20 * time.Millisecond)
+	c.Set("/api/feeds/changes.atom", []byte("a"))
+	c.Set("/api/feeds/changes.atom?type=relisted", []byte("b"))
+
+	if body, ok := c.Get("/api/feeds/changes.atom"); !ok || string(body) != "a" {
+		t.Fatalf("Get = %q, %v (want cached)", body, ok)
+	}
+	if body, ok := c.Get("/api/feeds/changes.atom?type=relisted"); !ok || string(body) != "b" {
+		t.Fatalf("query string variant = %q, %v (want its own entry)", body, ok)
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	if _, ok := c.Get("/api/feeds/changes.atom"); ok {
+		t.Error("entry still served after the TTL")
+	}
+
+	// Set drops expired entries for other keys
+	c.Set("/api/feeds/new-listings.atom", []byte("c"))
+	c.mu.Lock()
+	n := len(c.entries)
+	c.mu.Unlock()
+	if n != 1 {
+		t.Errorf("%d entries after Set (want 1)", n)
+	}
+}
@@ -1,13 +1,18 @@
 package handlers
 
 import (
+	"errors"
 	"log"
 	"net/http"
 	"real-estate-portal/internal/cleanup"
+	"real-estate-portal/internal/lookup"
+	"real-estate-portal/internal/maintenance"
 	"real-estate-portal/internal/models"
+	"real-estate-portal/internal/queue"
 	"real-estate-portal/internal/scheduler"
 	"real-estate-portal/internal/snapshot"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -20,6 +25,8 @@ type AdminHandler struct {
 	scheduler       *scheduler.Scheduler
 	snapshotService *snapshot.Service
 	cleanupService  *cleanup.Service
+	lookupService   *lookup.Service
+	queueService    *queue.Service
 }
 
 // NewAdminHandler creates a new admin handler
@@ -29,6 +36,8 @@ func NewAdminHandler(db *gorm.DB, sched *scheduler.Scheduler) *AdminHandler {
 		scheduler:       sched,
 		snapshotService: snapshot.NewService(db),
 		cleanupService:  cleanup.NewService(db),
+		lookupService:   lookup.NewService(db),
+		queueService:    queue.NewService(db),
 	}
 }
 
@@ -41,10 +50,15 @@ func (h *AdminHandler) GetStats(c *gin.Context) {
 	h.db.Model(&models.Property{}).Where("status = ?", models.PropertyStatusActive).Count(&activeCount)
 	h.db.Model(&models.Property{}).Where("status = ?", models.PropertyStatusRemoved).Count(&removedCount)
 
+	// Active properties linked to an earlier removed listing of the same unit
+	var relistedCount int64
+	h.db.Model(&models.Property{}).Where("status = ? AND relisted_from IS NOT NULL", models.PropertyStatusActive).Count(&relistedCount)
+
 	stats["properties"] = map[string]interface{}{
-		"active":  activeCount,
-		"removed": removedCount,
-		"total":   activeCount + removedCount,
+		"active":   activeCount,
+		"removed":  removedCount,
+		"total":    activeCount + removedCount,
+		"relisted": relistedCount,
 	}
 
 	// Recent scraping activity (last 24 hours)
@@ -109,6 +123,10 @@ func (h *AdminHandler) TriggerScraping(c *gin.Context) {
 	}
 
 	log.Println("Admin: Manual scraping trigger requested")
+	if h.scheduler.RunInProgress() {
+		c.JSON(http.StatusConflict, gin.H{"error": scheduler.ErrRunInProgress.Error()})
+		return
+	}
 
 	// Run in goroutine to avoid blocking
 	go func() {
@@ -125,13 +143,45 @@ func (h *AdminHandler) TriggerScraping(c *gin.Context) {
 	})
 }
 
-// GetScrapingStatus returns current scraping status
+// GetScrapingStatus returns the last scheduler run, with what each configured list URL discovered,
+// and the registered schedules with their next run
 func (h *AdminHandler) GetScrapingStatus(c *gin.Context) {
-	// TODO: Implement actual status tracking
-	// For now, return basic info
+	if h.scheduler == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error": "Scheduler not available (MySQL/GORM required)",
+		})
+		return
+	}
+
+	run, err := h.scheduler.LastRun()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	status := "idle"
+	if run != nil && run.Status == models.SchedulerRunRunning {
+		status = "running"
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"status":    status,
+		"last_run":  run,
+		"schedules": h.scheduler.Schedules(),
+	})
+}
+
+// GetScrapingState returns the persisted WAF block (scraping_state): set when the circuit breaker
+// opens or the worker's health check fails, cleared on recovery, and restored at startup
+func (h *AdminHandler) GetScrapingState(c *gin.Context) {
+	state, err := scheduler.LoadScrapingState(h.db)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{
-		"status": "idle",
-		"message": "Status tracking not yet implemented",
+		"can_scrape": state == nil || state.CanScrape(),
+		"state":      state,
 	})
 }
 
@@ -174,6 +224,106 @@ func (h *AdminHandler) RunCleanup(c *gin.Context) {
 	c.JSON(http.StatusOK, result)
 }
 
+// SetReadOnly turns read-only maintenance mode on or off
+func (h *AdminHandler) SetReadOnly(c *gin.Context) {
+	var req struct {
+		Enabled *bool  `json:"enabled" binding:"required"`
+		Message string `json:"message"` // Returned in 503 responses while enabled
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	state, err := maintenance.Set(*req.Enabled, req.Message)
+	log.Printf("Admin: Read-only mode set to %v (message: %q)", state.ReadOnly, state.Message)
+	if err != nil {
+		// Already in effect for this process; it just won't survive a restart
+		log.Printf("Admin: %v", err)
+		c.JSON(http.StatusOK, gin.H{"maintenance": state, "persisted": false, "warning": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"maintenance": state, "persisted": true})
+}
+
+// ReprioritizeQueue sets a new priority on every pending/failed queue item matching a filter
+func (h *AdminHandler) ReprioritizeQueue(c *gin.Context) {
+	var req struct {
+		queue.Filter
+		Priority *int `json:"priority" binding:"required"`
+		Force    bool `json:"force"` // Allow more than queue.DefaultMaxAffected rows
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := h.queueService.Reprioritize(req.Filter, *req.Priority, 0, req.Force)
+	if err != nil {
+		var capErr *queue.CapExceededError
+		switch {
+		case errors.As(err, &capErr):
+			log.Printf("Admin: Queue reprioritize refused (%s): %v", req.Filter, err)
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "matched": capErr.Matched, "max": capErr.Max})
+		case errors.Is(err, queue.ErrInvalidFilter):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			log.Printf("Admin: Queue reprioritize failed (%s): %v", req.Filter, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	log.Printf("Admin: Queue reprioritized to %d: %d/%d items (%s, force: %v)",
+		result.Priority, result.Affected, result.Matched, req.Filter, req.Force)
+
+	c.JSON(http.StatusOK, result)
+}
+
+// GetDeadQueueItems lists failed and permanent_fail queue items grouped by error class
+// (?limit=, default and max queue.MaxDeadList)
+func (h *AdminHandler) GetDeadQueueItems(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(queue.MaxDeadList)))
+
+	report, err := h.queueService.ListDead(limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// RequeueDeadItems resets failed/permanent_fail queue items matching a filter to pending in a
+// single UPDATE (at most queue.DefaultMaxAffected per call)
+func (h *AdminHandler) RequeueDeadItems(c *gin.Context) {
+	var filter queue.RequeueFilter
+	if err := c.ShouldBindJSON(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Validate here too so the log lines show the defaulted statuses and limit
+	if err := filter.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := h.queueService.Requeue(filter)
+	if err != nil {
+		log.Printf("Admin: Queue requeue failed (%s): %v", filter, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	log.Printf("Admin: Queue requeued %d/%d dead items (%s)", result.Affected, result.Matched, filter)
+
+	c.JSON(http.StatusOK, result)
+}
+
 // GetDeleteLogs returns recent delete log entries
 func (h *AdminHandler) GetDeleteLogs(c *gin.Context) {
 	limitStr := c.DefaultQuery("limit", "100")
@@ -191,6 +341,38 @@ func (h *AdminHandler) GetDeleteLogs(c *gin.Context) {
 	})
 }
 
+// Lookup searches active/removed properties and delete logs by title, address or URL
+func (h *AdminHandler) Lookup(c *gin.Context) {
+	q := strings.TrimSpace(c.Query("q"))
+	if q == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "q is required"})
+		return
+	}
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(lookup.MaxResults)))
+
+	hits, err := h.lookupService.Search(q, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	counts := map[string]int{
+		lookup.LocationActive:  0,
+		lookup.LocationRemoved: 0,
+		lookup.LocationDeleted: 0,
+	}
+	for _, hit := range hits {
+		counts[hit.Location]++
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"query":     q,
+		"results":   hits,
+		"count":     len(hits),
+		"by_bucket": counts,
+	})
+}
+
 // GetPropertyHistory returns snapshot history for a property
 func (h *AdminHandler) GetPropertyHistory(c *gin.Context) {
 	propertyID := c.Param("id")
@@ -227,6 +409,41 @@ func (h *AdminHandler) GetRecentChanges(c *gin.Context) {
 	})
 }
 
+// GetSnapshotGaps reports properties whose queue item completed without a same-day snapshot.
+// With repair=true the missing snapshots are regenerated from the current property rows.
+func (h *AdminHandler) GetSnapshotGaps(c *gin.Context) {
+	days, err := strconv.Atoi(c.DefaultQuery("days", "7"))
+	if err != nil || days <= 0 || days > 90 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "days must be between 1 and 90"})
+		return
+	}
+
+	gaps, err := h.snapshotService.FindSnapshotGaps(days)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	response := gin.H{
+		"days":  days,
+		"gaps":  gaps,
+		"count": len(gaps),
+	}
+
+	if c.Query("repair") == "true" && len(gaps) > 0 {
+		repaired, err := h.snapshotService.RepairSnapshotGaps(gaps)
+		response["repaired"] = repaired
+		if err != nil {
+			response["error"] = err.Error()
+			c.JSON(http.StatusInternalServerError, response)
+			return
+		}
+		log.Printf("[SnapshotGaps] Repaired %d missing snapshots (last %d days)", repaired, days)
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
 // GetAreaStats returns statistics by area
 func (h *AdminHandler) GetAreaStats(c *gin.Context) {
 	type AreaStat struct {
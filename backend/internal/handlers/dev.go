@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"real-estate-portal/internal/database"
+	"real-estate-portal/internal/devseed"
+	"real-estate-portal/internal/search"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DevHandler handles development-only endpoints (seed data)
+type DevHandler struct {
+	enabled      bool
+	seedService  *devseed.Service
+	searchClient *search.SearchClient
+}
+
+// NewDevHandler creates a new dev handler; all endpoints refuse to run unless enabled
+func NewDevHandler(gormDB *database.GormDB, searchClient *search.SearchClient, enabled bool) *DevHandler {
+	return &DevHandler{
+		enabled:      enabled,
+		seedService:  devseed.NewService(gormDB),
+		searchClient: searchClient,
+	}
+}
+
+// requireDevMode rejects the request unless dev.seed_enabled is set
+func (h *DevHandler) requireDevMode(c *gin.Context) bool {
+	if !h.enabled {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": "Dev endpoints are disabled (set dev.seed_enabled in config)",
+		})
+		return false
+	}
+	return true
+}
+
+// Seed generates fake properties for frontend development
+func (h *DevHandler) Seed(c *gin.Context) {
+	if !h.requireDevMode(c) {
+		return
+	}
+
+	var req struct {
+		Count int `json:"count"` // Number of properties (default: 50)
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.Count == 0 {
+		req.Count = 50
+	}
+
+	result, err := h.seedService.Seed(req.Count)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if h.searchClient != nil && len(result.Properties) > 0 {
+		if err := h.searchClient.IndexProperties(result.Properties); err != nil {
+			log.Printf("DevSeed: Warning: Failed to index seeded properties: %v", err)
+		}
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// Reset removes all seeded rows
+func (h *DevHandler) Reset(c *gin.Context) {
+	if !h.requireDevMode(c) {
+		return
+	}
+
+	ids, err := h.seedService.Reset()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if h.searchClient != nil && len(ids) > 0 {
+		if err := h.searchClient.DeleteProperties(ids); err != nil {
+			log.Printf("DevSeed: Warning: Failed to remove seeded properties from index: %v", err)
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"deleted": len(ids),
+	})
+}
@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"real-estate-portal/internal/events"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// eventBuffer is how many events a stream holds for a slow client before dropping the oldest
+	eventBuffer = 256
+	// eventHeartbeat keeps idle streams open through proxies
+	eventHeartbeat = 25 * time.Second
+)
+
+// StreamEvents streams queue worker and scheduler progress as Server-Sent Events until the client
+// disconnects (?type=item_done,item_failed selects event types; repeatable). Each event is sent as
+// "event: <type>" with the JSON event as data; "event: dropped" reports events lost because the
+// client read too slowly.
+func (h *AdminHandler) StreamEvents(c *gin.Context) {
+	types, err := events.ParseTypes(c.QueryArray("type"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	sub := events.Default.Subscribe(eventBuffer, types...)
+	defer sub.Close()
+	log.Printf("Admin: Event stream opened from %s (types: %v)", c.ClientIP(), types)
+	defer log.Printf("Admin: Event stream from %s closed", c.ClientIP())
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no") // nginx: pass events through unbuffered
+	c.Status(http.StatusOK)
+	fmt.Fprint(c.Writer, ": connected\n\n")
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(eventHeartbeat)
+	defer heartbeat.Stop()
+	var dropped int64
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case <-heartbeat.C:
+			fmt.Fprint(c.Writer, ": ping\n\n")
+		case e, ok := <-sub.Events():
+			if !ok {
+				return // server shutting down
+			}
+			if n := sub.Dropped(); n > dropped {
+				fmt.Fprintf(c.Writer, "event: dropped\ndata: {\"dropped\":%d}\n\n", n-dropped)
+				dropped = n
+			}
+			data, err := json.Marshal(e)
+			if err != nil {
+				continue
+			}
+			fmt.Fprintf(c.Writer, "id: %d\nevent: %s\ndata: %s\n\n", e.ID, e.Type, data)
+		}
+		c.Writer.Flush()
+	}
+}
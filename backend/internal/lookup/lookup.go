@@ -0,0 +1,153 @@
+// Package lookup answers support questions like "did this listing get removed or deleted?"
+// by searching properties and delete_logs directly in the DB (no Meilisearch dependency).
+package lookup
+
+import (
+	"fmt"
+	"real-estate-portal/internal/models"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Where a hit lives
+const (
+	LocationActive  = "active"  // properties, status=active
+	LocationRemoved = "removed" // properties, status=removed (logically deleted, still in DB)
+	LocationDeleted = "deleted" // physically deleted, only the delete_logs row remains
+)
+
+// MaxResults caps the number of hits returned across all buckets
+const MaxResults = 50
+
+// Hit is one lookup result
+type Hit struct {
+	Location   string     `json:"location"`
+	PropertyID string     `json:"property_id"`
+	Title      string     `json:"title"`
+	Address    string     `json:"address,omitempty"`
+	DetailURL  string     `json:"detail_url"`
+	Rent       *int       `json:"rent,omitempty"`
+	LastSeenAt *time.Time `json:"last_seen_at,omitempty"`
+	RemovedAt  *time.Time `json:"removed_at,omitempty"`
+	DeletedAt  *time.Time `json:"deleted_at,omitempty"`
+	Reason     string     `json:"reason,omitempty"` // delete reason (deleted only)
+}
+
+// Service searches properties and delete logs
+type Service struct {
+	db *gorm.DB
+}
+
+// NewService creates a new lookup service
+func NewService(db *gorm.DB) *Service {
+	return &Service{db: db}
+}
+
+// HitFromProperty classifies a property row as active or removed
+func HitFromProperty(p *models.Property) Hit {
+	hit := Hit{
+		Location:   LocationActive,
+		PropertyID: p.ID,
+		Title:      p.Title,
+		Address:    p.Address,
+		DetailURL:  p.DetailURL,
+		Rent:       p.Rent,
+		LastSeenAt: p.LastSeenAt,
+	}
+	if p.Status == models.PropertyStatusRemoved {
+		hit.Location = LocationRemoved
+		hit.RemovedAt = p.RemovedAt
+	}
+	return hit
+}
+
+// HitFromDeleteLog classifies a delete log row as deleted
+func HitFromDeleteLog(l *models.DeleteLog) Hit {
+	hit := Hit{
+		Location:   LocationDeleted,
+		PropertyID: l.PropertyID,
+		Title:      l.Title,
+		DetailURL:  l.DetailURL,
+		Reason:     l.Reason,
+	}
+	deletedAt := l.DeletedAt
+	hit.DeletedAt = &deletedAt
+	if !l.RemovedAt.IsZero() {
+		removedAt := l.RemovedAt
+		hit.RemovedAt = &removedAt
+	}
+	return hit
+}
+
+// escapeLike escapes LIKE wildcards in user input
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+// Search finds properties (title, address, detail_url) and delete logs (title, detail_url)
+// matching every whitespace-separated term in q. A URL query is matched as a detail_url
+// prefix so the detail_url index can be used.
+func (s *Service) Search(q string, limit int) ([]Hit, error) {
+	terms := strings.Fields(q)
+	if len(terms) == 0 {
+		return nil, fmt.Errorf("query is empty")
+	}
+	if limit <= 0 || limit > MaxResults {
+		limit = MaxResults
+	}
+
+	urlPrefix := ""
+	if len(terms) == 1 && (strings.HasPrefix(terms[0], "http://") || strings.HasPrefix(terms[0], "https://")) {
+		urlPrefix = escapeLike(strings.TrimSuffix(terms[0], "/")) + "%"
+	}
+
+	propQuery := s.db.Model(&models.Property{})
+	logQuery := s.db.Model(&models.DeleteLog{})
+	if urlPrefix != "" {
+		propQuery = propQuery.Where("detail_url LIKE ?", urlPrefix)
+		logQuery = logQuery.Where("detail_url LIKE ?", urlPrefix)
+	} else {
+		for _, term := range terms {
+			pattern := "%" + escapeLike(term) + "%"
+			propQuery = propQuery.Where("(title LIKE ? OR address LIKE ? OR detail_url LIKE ?)", pattern, pattern, pattern)
+			logQuery = logQuery.Where("(title LIKE ? OR detail_url LIKE ?)", pattern, pattern)
+		}
+	}
+
+	var properties []models.Property
+	if err := propQuery.Order("updated_at DESC").Limit(limit).Find(&properties).Error; err != nil {
+		return nil, fmt.Errorf("search properties: %w", err)
+	}
+	var logs []models.DeleteLog
+	if err := logQuery.Order("deleted_at DESC").Limit(limit).Find(&logs).Error; err != nil {
+		return nil, fmt.Errorf("search delete logs: %w", err)
+	}
+
+	// Share the cap so a broad query can't crowd out the deleted bucket entirely
+	keepLogs := len(logs)
+	if keepLogs > limit-len(properties) {
+		keepLogs = limit - len(properties)
+		if keepLogs < limit/2 {
+			keepLogs = limit / 2
+		}
+		if keepLogs > len(logs) {
+			keepLogs = len(logs)
+		}
+	}
+	keepProps := len(properties)
+	if keepProps > limit-keepLogs {
+		keepProps = limit - keepLogs
+	}
+
+	hits := make([]Hit, 0, keepProps+keepLogs)
+	for i := 0; i < keepProps; i++ {
+		hits = append(hits, HitFromProperty(&properties[i]))
+	}
+	for i := 0; i < keepLogs; i++ {
+		hits = append(hits, HitFromDeleteLog(&logs[i]))
+	}
+
+	return hits, nil
+}
@@ -0,0 +1,150 @@
+// Package maintenance holds the global read-only flag used during maintenance windows
+// (e.g. DB migrations): the API keeps serving reads while every write is refused.
+package maintenance
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"real-estate-portal/internal/models"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// DefaultMessage is returned to clients when no message was given
+const DefaultMessage = "The service is in read-only mode for maintenance. Please try again later."
+
+// ErrReadOnly is returned by background jobs that skipped their writes
+var ErrReadOnly = errors.New("read-only mode is enabled")
+
+// State is the current maintenance mode
+type State struct {
+	ReadOnly bool       `json:"read_only"`
+	Message  string     `json:"message,omitempty"`
+	Since    *time.Time `json:"since,omitempty"`
+}
+
+var (
+	mu      sync.RWMutex
+	current State
+	store   *gorm.DB // persists the state when set (MySQL only)
+)
+
+// Load restores the persisted state from db and keeps db for later changes.
+// A nil db keeps the state in memory only.
+func Load(db *gorm.DB) error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	store = db
+	if db == nil {
+		return nil
+	}
+
+	var row models.MaintenanceState
+	err := db.Where("id = ?", 1).Limit(1).Find(&row).Error
+	if err != nil {
+		return fmt.Errorf("load maintenance state: %w", err)
+	}
+	if row.ID == 0 {
+		return nil
+	}
+
+	current = State{ReadOnly: row.ReadOnly, Message: row.Message}
+	if row.ReadOnly {
+		since := row.UpdatedAt
+		current.Since = &since
+	}
+	return nil
+}
+
+// Set turns read-only mode on or off. The new state applies immediately; an error
+// means it could not be persisted and will not survive a restart.
+func Set(enabled bool, message string) (State, error) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	now := time.Now()
+	next := State{ReadOnly: enabled}
+	if enabled {
+		next.Message = message
+		if current.ReadOnly && current.Since != nil {
+			next.Since = current.Since
+		} else {
+			next.Since = &now
+		}
+	}
+	current = next
+
+	if store == nil {
+		return current, nil
+	}
+	row := models.MaintenanceState{ID: 1, ReadOnly: next.ReadOnly, Message: next.Message, UpdatedAt: now}
+	if err := store.Save(&row).Error; err != nil {
+		return current, fmt.Errorf("persist maintenance state: %w", err)
+	}
+	return current, nil
+}
+
+// Override turns read-only mode on without persisting it, so removing the config flag
+// and restarting restores whatever was last set via the admin API
+func Override(message string) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	now := time.Now()
+	current = State{ReadOnly: true, Message: message, Since: &now}
+}
+
+// Current returns the current state
+func Current() State {
+	mu.RLock()
+	defer mu.RUnlock()
+	return current
+}
+
+// ReadOnly reports whether writes are currently refused
+func ReadOnly() bool {
+	mu.RLock()
+	defer mu.RUnlock()
+	return current.ReadOnly
+}
+
+// Middleware rejects mutating requests (anything but GET/HEAD/OPTIONS) with 503 while
+// read-only mode is on. allow lists route patterns (gin FullPath) that stay open, such as
+// read-only POST searches and the endpoint that turns the mode off.
+func Middleware(allow ...string) gin.HandlerFunc {
+	allowed := make(map[string]bool, len(allow))
+	for _, path := range allow {
+		allowed[path] = true
+	}
+
+	return func(c *gin.Context) {
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			c.Next()
+			return
+		}
+
+		state := Current()
+		if !state.ReadOnly || allowed[c.FullPath()] {
+			c.Next()
+			return
+		}
+
+		message := state.Message
+		if message == "" {
+			message = DefaultMessage
+		}
+		log.Printf("[Maintenance] Rejected %s %s (read-only)", c.Request.Method, c.Request.URL.Path)
+		c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{
+			"error":     message,
+			"code":      "read_only",
+			"read_only": true,
+		})
+	}
+}
@@ -0,0 +1,39 @@
+// Package meta assembles the canonical option lists (floor plans, sort keys, change types...)
+// served by GET /api/meta/options. Every list comes from the same definition the
+// scraper and handler validation use, so the frontend never drifts from the backend.
+package meta
+
+import (
+	"real-estate-portal/internal/database"
+	"real-estate-portal/internal/models"
+	"real-estate-portal/internal/search"
+)
+
+// Options is the /api/meta/options payload
+type Options struct {
+	FloorPlans       []string `json:"floor_plans"`
+	BuildingTypes    []string `json:"building_types"`
+	PropertySortKeys []string `json:"property_sort_keys"` // GET /api/properties?sort=
+	SearchSortKeys   []string `json:"search_sort_keys"`   // POST /api/search/advanced "sort"
+	FilterSortKeys   []string `json:"filter_sort_keys"`   // GET /api/filter?sort_by=
+	ChangeTypes      []string `json:"change_types"`
+	QueueStatuses    []string `json:"queue_statuses"`
+	DeleteReasons    []string `json:"delete_reasons"`
+	WalkTimeBuckets  []string `json:"walk_time_buckets"` // walk_time_bucket facet / GET /api/stats/walk-buckets
+}
+
+// CurrentOptions returns the option lists. Property sort keys are the ones both
+// database backends actually order by (database.SortKeys), not a copy of the list.
+func CurrentOptions() Options {
+	return Options{
+		FloorPlans:       models.FloorPlans,
+		BuildingTypes:    models.BuildingTypes,
+		PropertySortKeys: database.SortKeys(),
+		SearchSortKeys:   search.SearchSortKeys,
+		FilterSortKeys:   search.FilterSortKeys,
+		ChangeTypes:      models.ChangeTypes,
+		QueueStatuses:    models.QueueStatuses,
+		DeleteReasons:    models.DeleteReasons,
+		WalkTimeBuckets:  models.WalkTimeBuckets,
+	}
+}
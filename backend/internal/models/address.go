@@ -0,0 +1,62 @@
+package models
+
+import (
+	"regexp"
+	"strings"
+)
+
+// 住所表記を本文から取り出すヘルパー（所在地欄・埋め込みJSONが無いページ用）
+
+// Prefectures は47都道府県（JIS X 0401 の順）
+var Prefectures = []string{
+	"北海道", "青森県", "岩手県", "宮城県", "秋田県", "山形県", "福島県",
+	"茨城県", "栃木県", "群馬県", "埼玉県", "千葉県", "東京都", "神奈川県",
+	"新潟県", "富山県", "石川県", "福井県", "山梨県", "長野県", "岐阜県",
+	"静岡県", "愛知県", "三重県", "滋賀県", "京都府", "大阪府", "兵庫県",
+	"奈良県", "和歌山県", "鳥取県", "島根県", "岡山県", "広島県", "山口県",
+	"徳島県", "香川県", "愛媛県", "高知県", "福岡県", "佐賀県", "長崎県",
+	"熊本県", "大分県", "宮崎県", "鹿児島県", "沖縄県",
+}
+
+// addressRestPattern は都道府県の後ろの「市区郡町村＋以降」。都道府県の直後（8文字以内）に
+// 市区郡町村が無いもの（「東京都内の人気エリア」「東京都知事(3)第123号」）は住所とみなさない。
+// 空白・句読点・括弧で終わる。
+var addressRestPattern = regexp.MustCompile(`^[^\s、。,|｜・()（）「」【】]{0,8}?[市区郡町村][^\s、。,|｜()（）「」【】]*`)
+
+// maxAddressRunes は本文から取り出す住所の上限（これより長いものは切り詰める）
+const maxAddressRunes = 100
+
+// FindAddress は text 中の最初の住所（都道府県＋市区郡町村〜）を返す（無ければ ""）。
+// ページ全文に使うので、47都道府県の正規表現ではなく末尾の「道都府県」を探してから照合する。
+func FindAddress(text string) string {
+	for i := 0; i < len(text); {
+		j := strings.IndexAny(text[i:], "道都府県")
+		if j < 0 {
+			break
+		}
+		end := i + j + len("県") // 道都府県 are all 3 bytes in UTF-8
+		i = end
+		pref := prefectureEndingAt(text[:end])
+		if pref == "" {
+			continue
+		}
+		if rest := addressRestPattern.FindString(text[end:]); rest != "" {
+			address := pref + rest
+			if runes := []rune(address); len(runes) > maxAddressRunes {
+				address = string(runes[:maxAddressRunes])
+			}
+			return address
+		}
+	}
+	return ""
+}
+
+// prefectureEndingAt は text の末尾の都道府県名を返す（無ければ ""）
+func prefectureEndingAt(text string) string {
+	for _, pref := range Prefectures {
+		if strings.HasSuffix(text, pref) {
+			return pref
+		}
+	}
+	return ""
+}
@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// APIKeyUsage holds per-key daily counters for quota enforcement (persisted so restarts don't reset them)
+type APIKeyUsage struct {
+	ID           uint      `gorm:"primaryKey;autoIncrement" json:"-"`
+	KeyName      string    `gorm:"type:varchar(100);not null;uniqueIndex:idx_key_day,priority:1" json:"key_name"`
+	Day          string    `gorm:"type:varchar(10);not null;uniqueIndex:idx_key_day,priority:2" json:"day"` // YYYY-MM-DD
+	ScrapeCount  int       `gorm:"type:int;not null;default:0" json:"scrape_count"`
+	EnqueueCount int       `gorm:"type:int;not null;default:0" json:"enqueue_count"`
+	UpdatedAt    time.Time `gorm:"type:datetime;not null;autoUpdateTime" json:"updated_at"`
+}
+
+// TableName specifies the table name
+func (APIKeyUsage) TableName() string {
+	return "api_key_usage"
+}
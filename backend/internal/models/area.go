@@ -0,0 +1,61 @@
+package models
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// 専有面積の表記（"25.5㎡" "約7.5坪" "6帖"）を平米に換算するヘルパー
+
+// AreaUnit は Area の元の単位。坪・帖しか書かれていない物件は換算した概算値なので区別する
+const (
+	AreaUnitSquareMeters = "sqm"   // ㎡ / m² / 平米（換算なし）
+	AreaUnitTsubo        = "tsubo" // 坪 ×3.30578
+	AreaUnitJo           = "jo"    // 帖・畳 ×1.62（首都圏の不動産公正取引協議会の基準）
+)
+
+const (
+	TsuboToSquareMeters = 3.30578
+	JoToSquareMeters    = 1.62
+)
+
+// 住戸として妥当な専有面積の範囲（㎡）
+const (
+	minArea = 5.0
+	maxArea = 500.0
+)
+
+var areaPattern = regexp.MustCompile(`([0-9]+(?:\.[0-9]+)?)\s*(㎡|m²|m2|平米|平方メートル|坪|帖|畳)`)
+
+// areaUnitPriority は同じ値に複数の単位が併記されたときの優先順（"7.7坪（25.5㎡）" は ㎡ を使う）
+var areaUnitPriority = map[string]int{AreaUnitSquareMeters: 0, AreaUnitTsubo: 1, AreaUnitJo: 2}
+
+// ParseArea は text 中の面積を平米で返す。㎡ の値があればそれを、無ければ坪、帖・畳の順に
+// 換算して使う（小数2桁に丸める）。面積が無いか範囲外なら (0, "")
+func ParseArea(text string) (float64, string) {
+	best, bestUnit := 0.0, ""
+	text = strings.ReplaceAll(normalizeFloorPlanText(text), "．", ".")
+	for _, m := range areaPattern.FindAllStringSubmatch(text, -1) {
+		value, err := strconv.ParseFloat(m[1], 64)
+		if err != nil {
+			continue
+		}
+		unit := AreaUnitSquareMeters
+		switch m[2] {
+		case "坪":
+			unit, value = AreaUnitTsubo, value*TsuboToSquareMeters
+		case "帖", "畳":
+			unit, value = AreaUnitJo, value*JoToSquareMeters
+		}
+		value = math.Round(value*100) / 100
+		if value < minArea || value > maxArea {
+			continue
+		}
+		if bestUnit == "" || areaUnitPriority[unit] < areaUnitPriority[bestUnit] {
+			best, bestUnit = value, unit
+		}
+	}
+	return best, bestUnit
+}
@@ -0,0 +1,86 @@
+package models
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// キャンペーン表記の直後を見る範囲（文字数）
+const campaignTailRunes = 20
+
+var (
+	// フリーレントの月数（「フリーレント1ヶ月」「フリーレント2カ月」など）
+	freeRentMonthsPattern = regexp.MustCompile(`^[\s:：（(]*([0-9]+)\s*[ヶケカかヵ箇]?\s*月`)
+	// 仲介手数料が無料であることを示す表記
+	noBrokeragePattern = regexp.MustCompile(`^[\s:：]*(無料|不要|なし|無し|0円|0ヶ月|0ヵ月|0カ月)`)
+)
+
+// CampaignFlags はキャンペーン表記から抽出したフラグ
+type CampaignFlags struct {
+	FreeRent       bool
+	FreeRentMonths *int
+	NoBrokerageFee bool
+}
+
+// ParseCampaigns はページ内テキストからフリーレント・仲介手数料無料の表記を検出する。
+// 表記の直後に「終了」がある場合（「フリーレントキャンペーン終了」など）は対象外とする。
+func ParseCampaigns(texts ...string) CampaignFlags {
+	var flags CampaignFlags
+	text := normalizeWidth(strings.Join(texts, "\n"))
+
+	for _, tail := range campaignTails(text, "フリーレント") {
+		if strings.Contains(tail, "終了") {
+			continue
+		}
+		flags.FreeRent = true
+		if m := freeRentMonthsPattern.FindStringSubmatch(tail); m != nil {
+			if months, err := strconv.Atoi(m[1]); err == nil && months > 0 {
+				if flags.FreeRentMonths == nil || months > *flags.FreeRentMonths {
+					flags.FreeRentMonths = &months
+				}
+			}
+		}
+	}
+
+	for _, tail := range campaignTails(text, "仲介手数料") {
+		if strings.Contains(tail, "終了") {
+			continue
+		}
+		if noBrokeragePattern.MatchString(tail) {
+			flags.NoBrokerageFee = true
+		}
+	}
+
+	return flags
+}
+
+// campaignTails は marker の各出現位置について直後のテキスト（改行・句点まで）を返す
+func campaignTails(text, marker string) []string {
+	var tails []string
+	for {
+		idx := strings.Index(text, marker)
+		if idx < 0 {
+			return tails
+		}
+		text = text[idx+len(marker):]
+
+		tail := []rune(text)
+		if len(tail) > campaignTailRunes {
+			tail = tail[:campaignTailRunes]
+		}
+		end := strings.IndexAny(string(tail), "\n。")
+		if end >= 0 {
+			tails = append(tails, string(tail)[:end])
+		} else {
+			tails = append(tails, string(tail))
+		}
+	}
+}
+
+// ApplyCampaigns はキャンペーンフラグを物件に設定する
+func (p *Property) ApplyCampaigns(flags CampaignFlags) {
+	p.FreeRent = flags.FreeRent
+	p.FreeRentMonths = flags.FreeRentMonths
+	p.NoBrokerageFee = flags.NoBrokerageFee
+}
@@ -25,3 +25,8 @@ const (
 	DeleteReasonManual     = "manual_deletion"
 	DeleteReasonDataClean  = "data_cleanup"
 )
+
+// DeleteReasons lists every delete_logs.reason value
+var DeleteReasons = []string{
+	DeleteReasonExpired, DeleteReasonDuplicate, DeleteReasonManual, DeleteReasonDataClean,
+}
@@ -11,14 +11,22 @@ type DetailScrapeQueue struct {
 	Source           string     `gorm:"type:varchar(50);not null;index:idx_queue_lookup" json:"source"`
 	SourcePropertyID string     `gorm:"type:varchar(255);not null;index:idx_queue_lookup" json:"source_property_id"`
 	DetailURL        string     `gorm:"type:text;not null" json:"detail_url"`
+	RefererURL       string     `gorm:"type:text" json:"referer_url,omitempty"` // list page the URL was found on (sent as Referer)
 	Status           string     `gorm:"type:varchar(20);not null;default:'pending';index:idx_status" json:"status"` // pending, processing, done, failed
 	Priority         int        `gorm:"default:0;index:idx_priority" json:"priority"`                                // Higher = process first
 	Attempts         int        `gorm:"default:0" json:"attempts"`
 	LastError        string     `gorm:"type:text" json:"last_error,omitempty"`
+	LastErrorCode    string     `gorm:"type:varchar(32);index:idx_last_error_code" json:"last_error_code,omitempty"` // short classification, e.g. not_found, waf_blocked
 	NextRetryAt      *time.Time `gorm:"index:idx_retry" json:"next_retry_at,omitempty"`
+	Force            bool       `gorm:"default:false" json:"force,omitempty"` // scrape even if fetched within min_refetch_interval
+	Light            bool       `gorm:"default:false" json:"light,omitempty"` // light refresh: meta tags and rent only (see Scraper.ScrapePropertyLight)
 	CreatedAt        time.Time  `gorm:"autoCreateTime" json:"created_at"`
 	UpdatedAt        time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
 	CompletedAt      *time.Time `json:"completed_at,omitempty"`
+
+	// Generated: "source:source_property_id" while pending/processing, NULL otherwise.
+	// The unique index allows at most one active row per listing (see QueueActiveKey).
+	ActiveKey *string `gorm:"->;size:306;type:varchar(306) GENERATED ALWAYS AS (CASE WHEN status IN ('pending','processing') THEN CONCAT(source, ':', source_property_id) END) STORED;uniqueIndex:uniq_queue_active_key" json:"-"`
 }
 
 // TableName specifies the table name for GORM
@@ -26,6 +34,12 @@ func (DetailScrapeQueue) TableName() string {
 	return "detail_scrape_queue"
 }
 
+// QueueActiveKey is the active_key value of a pending/processing row for a listing
+// (must match the generated column expression)
+func QueueActiveKey(source, sourcePropertyID string) string {
+	return source + ":" + sourcePropertyID
+}
+
 // Status constants
 const (
 	QueueStatusPending      = "pending"
@@ -33,8 +47,15 @@ const (
 	QueueStatusDone         = "done"
 	QueueStatusFailed       = "failed"
 	QueueStatusPermanentFail = "permanent_fail" // 404 or other non-retryable failures
+	QueueStatusCancelled    = "cancelled"      // cancelled by an admin; never picked up again
 )
 
+// QueueStatuses lists every queue status
+var QueueStatuses = []string{
+	QueueStatusPending, QueueStatusProcessing, QueueStatusDone, QueueStatusFailed, QueueStatusPermanentFail,
+	QueueStatusCancelled,
+}
+
 // MaxRetryAttempts before marking as permanently failed
 const MaxRetryAttempts = 5
 
@@ -0,0 +1,178 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Facilities / Features は DB 上は JSON 配列の文字列として保存している。
+// 空文字や壊れた JSON が混ざっていても読み出し側が落ちないよう、アクセサ経由で扱う。
+
+// invalidListLogInterval は不正データ警告の最短間隔（間に出なかった件数は次の警告にまとめる）
+const invalidListLogInterval = time.Minute
+
+// invalidListLog は不正データの警告を間引く。行ごとの記録は持たないので、不正な行が
+// 何件あってもメモリは増えない
+var invalidListLog struct {
+	mu         sync.Mutex
+	last       time.Time
+	suppressed int
+}
+
+// logInvalidList は不正な JSON の警告を invalidListLogInterval に1回だけ出す
+func logInvalidList(field, propertyID string, err error) {
+	invalidListLog.mu.Lock()
+	defer invalidListLog.mu.Unlock()
+
+	now := time.Now()
+	if !invalidListLog.last.IsZero() && now.Sub(invalidListLog.last) < invalidListLogInterval {
+		invalidListLog.suppressed++
+		return
+	}
+	log.Printf("[Property] id=%s invalid %s JSON, treating as empty: %v (%d more since the last warning)",
+		propertyID, field, err, invalidListLog.suppressed)
+	invalidListLog.last = now
+	invalidListLog.suppressed = 0
+}
+
+// decodeStringList は保存済みの JSON 配列文字列をスライスに変換する（空・不正なら空スライス）。
+// [ / { で始まらない値は旧形式のカンマ区切り（"a,b" / "a、b"）として読む
+func decodeStringList(raw, field, propertyID string) []string {
+	raw = strings.TrimSpace(raw)
+	if raw == "" || raw == "null" {
+		return []string{}
+	}
+	if !strings.HasPrefix(raw, "[") && !strings.HasPrefix(raw, "{") {
+		return canonicalStringList(strings.FieldsFunc(raw, func(r rune) bool { return r == ',' || r == '、' }))
+	}
+
+	// 数値コードの配列も受け付ける（Pickouts など）
+	var values []interface{}
+	if err := json.Unmarshal([]byte(raw), &values); err != nil {
+		logInvalidList(field, propertyID, err)
+		return []string{}
+	}
+
+	list := make([]string, 0, len(values))
+	for _, v := range values {
+		switch t := v.(type) {
+		case string:
+			list = append(list, t)
+		case float64:
+			list = append(list, fmt.Sprintf("%g", t))
+		}
+	}
+	return canonicalStringList(list)
+}
+
+// canonicalStringList は空要素を除き、重複を排除してソートする
+func canonicalStringList(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	list := make([]string, 0, len(values))
+	for _, v := range values {
+		v = strings.TrimSpace(v)
+		if v == "" || seen[v] {
+			continue
+		}
+		seen[v] = true
+		list = append(list, v)
+	}
+	sort.Strings(list)
+	return list
+}
+
+// encodeStringList は常に有効な JSON 配列文字列を返す（空なら "[]"）
+func encodeStringList(values []string) string {
+	data, err := json.Marshal(canonicalStringList(values))
+	if err != nil {
+		return "[]"
+	}
+	return string(data)
+}
+
+// GetFacilities はこだわり条件キーを返す（保存値が空・不正なら空スライス）
+func (p *Property) GetFacilities() []string {
+	return decodeStringList(p.Facilities, "facilities", p.ID)
+}
+
+// SetFacilities はこだわり条件キーを重複排除・ソート済みの JSON 配列として設定する
+func (p *Property) SetFacilities(values []string) {
+	p.Facilities = encodeStringList(values)
+}
+
+// GetFeatures は特徴（ピックアウト）を返す（保存値が空・不正なら空スライス）
+func (p *Property) GetFeatures() []string {
+	return decodeStringList(p.Features, "features", p.ID)
+}
+
+// SetFeatures は特徴を重複排除・ソート済みの JSON 配列として設定する
+func (p *Property) SetFeatures(values []string) {
+	p.Features = encodeStringList(values)
+}
+
+// NormalizeListFields は Facilities / Features を正規形に書き直し、変更があれば true を返す
+func (p *Property) NormalizeListFields() bool {
+	facilities := encodeStringList(p.GetFacilities())
+	features := encodeStringList(p.GetFeatures())
+	changed := facilities != p.Facilities || features != p.Features
+	p.Facilities = facilities
+	p.Features = features
+	return changed
+}
+
+// propertyJSON は MarshalJSON/UnmarshalJSON の再帰を避けるための別名
+type propertyJSON Property
+
+// MarshalJSON は facilities / features / locked_fields を文字列ではなく JSON 配列として出力する
+func (p Property) MarshalJSON() ([]byte, error) {
+	var locked []string
+	if p.LockedFields != "" {
+		locked = p.GetLockedFields()
+	}
+	return json.Marshal(struct {
+		propertyJSON
+		Facilities   []string `json:"facilities"`
+		Features     []string `json:"features"`
+		LockedFields []string `json:"locked_fields,omitempty"`
+	}{
+		propertyJSON: propertyJSON(p),
+		Facilities:   p.GetFacilities(),
+		Features:     p.GetFeatures(),
+		LockedFields: locked,
+	})
+}
+
+// UnmarshalJSON は facilities / features を配列・文字列のどちらでも受け付ける
+func (p *Property) UnmarshalJSON(data []byte) error {
+	aux := struct {
+		*propertyJSON
+		Facilities   json.RawMessage `json:"facilities"`
+		Features     json.RawMessage `json:"features"`
+		LockedFields json.RawMessage `json:"locked_fields"`
+	}{propertyJSON: (*propertyJSON)(p)}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	p.Facilities = rawListToStored(aux.Facilities)
+	p.Features = rawListToStored(aux.Features)
+	p.LockedFields = rawListToStored(aux.LockedFields)
+	return nil
+}
+
+// rawListToStored は JSON 値（配列または JSON 文字列を含む文字列）を保存形式に変換する
+func rawListToStored(raw json.RawMessage) string {
+	if len(raw) == 0 || string(raw) == "null" {
+		return ""
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s
+	}
+	return string(raw)
+}
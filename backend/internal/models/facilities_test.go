@@ -0,0 +1,134 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGetFacilities(t *testing.T) {
+	tests := []struct {
+		name   string
+		stored string
+		want   string
+	}{
+		{"empty", "", "[]"},
+		{"null", "null", "[]"},
+		{"valid", `["pet_ok","auto_lock"]`, "[auto_lock pet_ok]"},
+		{"duplicates and blanks", `["auto_lock"," auto_lock ","",null]`, "[auto_lock]"},
+		{"numeric codes", `[1, "001"]`, "[001 1]"},
+		{"legacy CSV", "pet_ok,auto_lock, pet_ok", "[auto_lock pet_ok]"},
+		{"legacy CSV with 、", "オートロック、バス・トイレ別", "[オートロック バス・トイレ別]"},
+		{"invalid JSON", `["auto_lock",`, "[]"},
+		{"JSON object", `[{"key":"auto_lock"}]`, "[]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := Property{ID: "p1", Facilities: tt.stored}
+			got := p.GetFacilities()
+			if got == nil || fmt.Sprint(got) != tt.want {
+				t.Errorf("GetFacilities(%q) = %#v, want %s", tt.stored, got, tt.want)
+			}
+		})
+	}
+}
+
+// Stored lists render as JSON arrays, and decoding that JSON stores the canonical array again
+func TestPropertyJSONRoundTrip(t *testing.T) {
+	tests := []struct {
+		name                     string
+		facilities, features     string
+		wantJSONFac, wantJSONFea string
+	}{
+		{"valid", `["pet_ok","auto_lock"]`, `["南向き"]`, `["auto_lock","pet_ok"]`, `["南向き"]`},
+		{"empty", "", "", `[]`, `[]`},
+		{"legacy CSV", "pet_ok,auto_lock", "南向き、角部屋", `["auto_lock","pet_ok"]`, `["南向き","角部屋"]`},
+		{"invalid JSON", `["auto_lock",`, `{`, `[]`, `[]`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(Property{ID: "p1", Facilities: tt.facilities, Features: tt.features})
+			if err != nil {
+				t.Fatalf("marshal: %v", err)
+			}
+			var out struct {
+				Facilities json.RawMessage `json:"facilities"`
+				Features   json.RawMessage `json:"features"`
+			}
+			if err := json.Unmarshal(data, &out); err != nil {
+				t.Fatalf("unmarshal raw: %v", err)
+			}
+			if string(out.Facilities) != tt.wantJSONFac || string(out.Features) != tt.wantJSONFea {
+				t.Errorf("JSON facilities %s features %s, want %s %s", out.Facilities, out.Features, tt.wantJSONFac, tt.wantJSONFea)
+			}
+
+			var back Property
+			if err := json.Unmarshal(data, &back); err != nil {
+				t.Fatalf("unmarshal: %v", err)
+			}
+			if back.Facilities != tt.wantJSONFac || back.Features != tt.wantJSONFea {
+				t.Errorf("round trip stored %q %q, want %q %q", back.Facilities, back.Features, tt.wantJSONFac, tt.wantJSONFea)
+			}
+		})
+	}
+}
+
+// Clients may still send the lists as strings (a JSON array or legacy CSV inside a string)
+func TestPropertyUnmarshalStringLists(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"array", `{"facilities":["pet_ok","auto_lock"]}`, "[auto_lock pet_ok]"},
+		{"JSON in a string", `{"facilities":"[\"pet_ok\"]"}`, "[pet_ok]"},
+		{"legacy CSV string", `{"facilities":"pet_ok,auto_lock"}`, "[auto_lock pet_ok]"},
+		{"null", `{"facilities":null}`, "[]"},
+		{"missing", `{}`, "[]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var p Property
+			if err := json.Unmarshal([]byte(tt.in), &p); err != nil {
+				t.Fatalf("unmarshal: %v", err)
+			}
+			if got := fmt.Sprint(p.GetFacilities()); got != tt.want {
+				t.Errorf("facilities %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	var p Property
+	if err := json.Unmarshal([]byte(`{"facilities":`), &p); err == nil {
+		t.Error("truncated property JSON: no error")
+	}
+}
+
+// Invalid rows are logged at most once per interval, however many there are
+func TestInvalidListLogThrottled(t *testing.T) {
+	var buf bytes.Buffer
+	out := log.Writer()
+	log.SetOutput(&buf)
+	t.Cleanup(func() { log.SetOutput(out) })
+	invalidListLog.mu.Lock()
+	invalidListLog.last, invalidListLog.suppressed = time.Time{}, 0
+	invalidListLog.mu.Unlock()
+
+	for i := 0; i < 1000; i++ {
+		p := Property{ID: fmt.Sprintf("bad%04d", i), Facilities: "[broken"}
+		p.GetFacilities()
+	}
+	if n := strings.Count(buf.String(), "invalid facilities JSON"); n != 1 {
+		t.Errorf("%d warnings for 1000 invalid rows (want 1)", n)
+	}
+	invalidListLog.mu.Lock()
+	suppressed := invalidListLog.suppressed
+	invalidListLog.mu.Unlock()
+	if suppressed != 999 {
+		t.Errorf("%d suppressed (want 999)", suppressed)
+	}
+}
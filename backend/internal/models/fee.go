@@ -0,0 +1,139 @@
+package models
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// 費用テキスト（"5,000円", "1ヶ月", "敷1礼1", "なし" 等）を数値に正規化するヘルパー
+
+var (
+	feeMonthsPattern = regexp.MustCompile(`([0-9]+(?:\.[0-9]+)?)\s*(?:ヶ|ケ|ヵ|カ|か|箇)月`)
+	feeManYenPattern = regexp.MustCompile(`([0-9]+(?:\.[0-9]+)?)\s*万円?`)
+	feeYenPattern    = regexp.MustCompile(`([0-9]+)\s*円?`)
+	feeDigitsPattern = regexp.MustCompile(`^[0-9]+$`)
+
+	// feeCompoundPatterns は複合表記（"敷1礼1"）から marker ごとの値を取り出す
+	feeCompoundPatterns = map[string]*regexp.Regexp{
+		"敷":  compoundFeePattern("敷"),
+		"礼":  compoundFeePattern("礼"),
+		"敷引": compoundFeePattern("敷引"),
+	}
+)
+
+// compoundFeePattern は marker に続く値（月数・円額・なし）にマッチする
+func compoundFeePattern(marker string) *regexp.Regexp {
+	return regexp.MustCompile(regexp.QuoteMeta(marker) + `金?([0-9]+(?:\.[0-9]+)?|なし|無し|無|-|−|ー)(ヶ月|ヵ月|か月|カ月|万円|円)?`)
+}
+
+// feeZeroMarkers は「費用なし」を表す表記
+var feeZeroMarkers = []string{"なし", "無し", "無", "不要", "-", "−", "ー", "―", "－"}
+
+// normalizeFeeText は全角数字・カンマ・空白を除去して解析しやすくする
+func normalizeFeeText(text string) string {
+	text = strings.Map(func(r rune) rune {
+		switch {
+		case r >= '０' && r <= '９':
+			return r - '０' + '0'
+		case r == '．':
+			return '.'
+		case r == ',' || r == '，' || r == ' ' || r == '　' || r == '\t' || r == '\n':
+			return -1
+		}
+		return r
+	}, text)
+	return text
+}
+
+// isZeroFee は「なし」「無」「−」等の表記かどうか
+func isZeroFee(text string) bool {
+	for _, marker := range feeZeroMarkers {
+		if text == marker {
+			return true
+		}
+	}
+	return text == "0" || text == "0円" || text == "0ヶ月"
+}
+
+// ParseYenAmount は "5,000円" "1.2万円" "なし" 等を円単位の整数に変換する
+// 解析できない場合は nil を返す
+func ParseYenAmount(text string) *int {
+	t := normalizeFeeText(text)
+	if t == "" {
+		return nil
+	}
+	if isZeroFee(t) {
+		zero := 0
+		return &zero
+	}
+
+	if m := feeManYenPattern.FindStringSubmatch(t); m != nil {
+		if v, err := strconv.ParseFloat(m[1], 64); err == nil {
+			yen := int(v*10000 + 0.5)
+			return &yen
+		}
+	}
+	if strings.Contains(t, "円") || feeDigitsPattern.MatchString(t) {
+		if m := feeYenPattern.FindStringSubmatch(t); m != nil {
+			if v, err := strconv.Atoi(m[1]); err == nil {
+				return &v
+			}
+		}
+	}
+	return nil
+}
+
+// ParseMonthsOrYen は敷金・礼金の表記を「賃料の何ヶ月分」と円額に変換する
+// marker には複合表記（"敷1礼1"）から該当部分を取り出すための接頭辞（"敷" / "礼"）を指定する。
+// ヶ月表記で rent が分かる場合は円額も計算する。解析できない場合は両方 nil。
+func ParseMonthsOrYen(text, marker string, rent *int) (months *float64, yen *int) {
+	t := normalizeFeeText(text)
+	if t == "" {
+		return nil, nil
+	}
+
+	// 複合表記: "敷1礼1" / "敷金1ヶ月礼金なし"
+	if marker != "" {
+		compound, ok := feeCompoundPatterns[marker]
+		if !ok {
+			compound = compoundFeePattern(marker) // 上記以外の marker（呼び出し元には無い）
+		}
+		if m := compound.FindStringSubmatch(t); m != nil {
+			t = m[1] + m[2]
+			if m[2] == "" && !isZeroFee(m[1]) {
+				t += "ヶ月" // "敷1" は月数
+			}
+		}
+	}
+
+	if isZeroFee(t) {
+		zeroMonths := 0.0
+		zeroYen := 0
+		return &zeroMonths, &zeroYen
+	}
+
+	if m := feeMonthsPattern.FindStringSubmatch(t); m != nil {
+		if v, err := strconv.ParseFloat(m[1], 64); err == nil {
+			months = &v
+			if rent != nil {
+				amount := int(float64(*rent)*v + 0.5)
+				yen = &amount
+			}
+			return months, yen
+		}
+	}
+
+	return nil, ParseYenAmount(t)
+}
+
+// NormalizeFees は文字列の費用フィールドから数値の補助フィールドを計算する
+// 元の文字列はそのまま保持する
+func (p *Property) NormalizeFees() {
+	p.ManagementFeeYen = ParseYenAmount(p.ManagementFee)
+	p.DepositMonths, p.DepositYen = ParseMonthsOrYen(p.Deposit, "敷", p.Rent)
+	p.KeyMoneyMonths, p.KeyMoneyYen = ParseMonthsOrYen(p.KeyMoney, "礼", p.Rent)
+	// 保証金・敷引は月数表記でも円額だけを保持する
+	_, p.GuarantorDepositYen = ParseMonthsOrYen(p.GuarantorDeposit, "", p.Rent)
+	_, p.SecurityDepositYen = ParseMonthsOrYen(p.SecurityDeposit, "敷引", p.Rent)
+}
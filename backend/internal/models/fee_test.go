@@ -0,0 +1,115 @@
+package models
+
+import (
+	"fmt"
+	"testing"
+)
+
+func intPtr(v int) *int { return &v }
+
+func fmtPtr[T any](p *T) string {
+	if p == nil {
+		return "nil"
+	}
+	return fmt.Sprint(*p)
+}
+
+func TestParseYenAmount(t *testing.T) {
+	tests := []struct {
+		text string
+		want *int
+	}{
+		{"50,000円", intPtr(50000)},
+		{"５，０００円", intPtr(5000)},
+		{"5000", intPtr(5000)},
+		{"5.5万円", intPtr(55000)},
+		{"1.2万", intPtr(12000)},
+		{"10万円", intPtr(100000)},
+		{"0円", intPtr(0)},
+		{"なし", intPtr(0)},
+		{"無", intPtr(0)},
+		{"不要", intPtr(0)},
+		{"-", intPtr(0)},
+		{"−", intPtr(0)},
+		{" 3,000 円 ", intPtr(3000)},
+		{"", nil},
+		{"相談", nil},
+		{"要問合せ", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			if got := ParseYenAmount(tt.text); fmtPtr(got) != fmtPtr(tt.want) {
+				t.Errorf("ParseYenAmount(%q) = %s, want %s", tt.text, fmtPtr(got), fmtPtr(tt.want))
+			}
+		})
+	}
+}
+
+func TestParseMonthsOrYen(t *testing.T) {
+	rent := intPtr(85000)
+	tests := []struct {
+		text       string
+		marker     string
+		rent       *int
+		wantMonths string
+		wantYen    string
+	}{
+		{"1ヶ月", "敷", rent, "1", "85000"},
+		{"1ヶ月", "敷", nil, "1", "nil"},
+		{"1.5ヵ月", "礼", rent, "1.5", "127500"},
+		{"２か月", "敷", rent, "2", "170000"},
+		{"敷1礼1", "敷", rent, "1", "85000"},
+		{"敷1礼1", "礼", rent, "1", "85000"},
+		{"敷2礼なし", "敷", rent, "2", "170000"},
+		{"敷2礼なし", "礼", rent, "0", "0"},
+		{"敷金1ヶ月礼金なし", "礼", rent, "0", "0"},
+		{"敷金10万円", "敷", rent, "nil", "100000"},
+		{"50,000円", "敷", rent, "nil", "50000"},
+		{"5.5万円", "礼", rent, "nil", "55000"},
+		{"なし", "礼", rent, "0", "0"},
+		{"-", "敷", rent, "0", "0"},
+		{"", "敷", rent, "nil", "nil"},
+		{"相談", "敷", rent, "nil", "nil"},
+		{"敷引2ヶ月", "敷引", rent, "2", "170000"},
+		{"30万円", "", rent, "nil", "300000"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.marker+":"+tt.text, func(t *testing.T) {
+			months, yen := ParseMonthsOrYen(tt.text, tt.marker, tt.rent)
+			if fmtPtr(months) != tt.wantMonths || fmtPtr(yen) != tt.wantYen {
+				t.Errorf("ParseMonthsOrYen(%q, %q) = %s months, %s yen; want %s, %s",
+					tt.text, tt.marker, fmtPtr(months), fmtPtr(yen), tt.wantMonths, tt.wantYen)
+			}
+		})
+	}
+}
+
+// NormalizeFees fills every numeric companion and keeps the original text
+func TestNormalizeFees(t *testing.T) {
+	p := Property{
+		Rent:             intPtr(80000),
+		ManagementFee:    "5,000円",
+		Deposit:          "敷1礼1",
+		KeyMoney:         "敷1礼1",
+		GuarantorDeposit: "なし",
+		SecurityDeposit:  "要相談",
+	}
+	p.NormalizeFees()
+	got := fmt.Sprintf("mgmt=%s dep=%s/%s key=%s/%s guarantor=%s security=%s",
+		fmtPtr(p.ManagementFeeYen), fmtPtr(p.DepositMonths), fmtPtr(p.DepositYen),
+		fmtPtr(p.KeyMoneyMonths), fmtPtr(p.KeyMoneyYen), fmtPtr(p.GuarantorDepositYen), fmtPtr(p.SecurityDepositYen))
+	want := "mgmt=5000 dep=1/80000 key=1/80000 guarantor=0 security=nil"
+	if got != want {
+		t.Errorf("NormalizeFees: %s\nwant %s", got, want)
+	}
+	if p.Deposit != "敷1礼1" || p.SecurityDeposit != "要相談" {
+		t.Errorf("original text changed: %q, %q", p.Deposit, p.SecurityDeposit)
+	}
+
+	// A rent change moves the yen amounts derived from months
+	p.Rent = intPtr(90000)
+	p.NormalizeFees()
+	if fmtPtr(p.DepositYen) != "90000" || fmtPtr(p.KeyMoneyYen) != "90000" {
+		t.Errorf("after a rent change: deposit %s, key money %s (want 90000 each)", fmtPtr(p.DepositYen), fmtPtr(p.KeyMoneyYen))
+	}
+}
@@ -0,0 +1,107 @@
+package models
+
+import (
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// 階数表記（"地上15階建て/7階部分" 等）を所在階と建物の階数に分解するヘルパー
+
+var (
+	// 建物の階数（"地上15階建て" "15階建" "地上15階地下2階建て" "地下1階付地上5階建"）
+	buildingFloorsPattern = regexp.MustCompile(`(?:地下[0-9]+階(?:付)?)?(?:地上)?([0-9]+)階(?:地下[0-9]+階)?建`)
+	// 所在階（"7階部分" "地下1階" "B1F" "7-8階"）。建物の階数を除去した残りから探す
+	unitFloorPattern = regexp.MustCompile(`(地下|B)?([0-9]+)(?:[-~〜～][0-9]+)?(?:階|F)`)
+)
+
+// 階数として妥当な範囲（これを外れる値は誤抽出とみなす）
+const (
+	maxBuildingFloors = 200
+	maxBasementFloors = 10
+)
+
+// FloorInfo は階数表記から抽出した所在階と建物の階数（地上階数）
+// 地下の所在階は負数（地下1階 = -1）
+type FloorInfo struct {
+	UnitFloor      *int
+	BuildingFloors *int
+}
+
+// ParseFloorLabel は "地上15階建て/7階部分" のような表記から所在階と建物の階数を取り出す。
+// 建物の階数（〜階建）を先に取り除いてから所在階を探すため、15 ではなく 7 が所在階になる。
+// 取り出せない部分は nil のまま返す。
+func ParseFloorLabel(label string) FloorInfo {
+	var info FloorInfo
+	text := normalizeFloorText(label)
+	if text == "" {
+		return info
+	}
+
+	if strings.Contains(text, "平屋") {
+		one := 1
+		info.BuildingFloors = &one
+		text = strings.ReplaceAll(text, "平屋", "")
+	}
+	if m := buildingFloorsPattern.FindStringSubmatch(text); m != nil {
+		if v, err := strconv.Atoi(m[1]); err == nil && v > 0 && v <= maxBuildingFloors {
+			info.BuildingFloors = &v
+		}
+	}
+	rest := buildingFloorsPattern.ReplaceAllString(text, "/")
+
+	if m := unitFloorPattern.FindStringSubmatch(rest); m != nil {
+		if v, err := strconv.Atoi(m[2]); err == nil {
+			if m[1] != "" {
+				v = -v
+			}
+			if v != 0 && v >= -maxBasementFloors && v <= maxBuildingFloors &&
+				(info.BuildingFloors == nil || v <= *info.BuildingFloors) {
+				info.UnitFloor = &v
+			}
+		}
+	}
+	if info.UnitFloor == nil && info.BuildingFloors != nil && *info.BuildingFloors == 1 {
+		// 平屋・1階建ては所在階も1階
+		one := 1
+		info.UnitFloor = &one
+	}
+	return info
+}
+
+// normalizeFloorText は全角英数・区切り・空白を正規化する
+func normalizeFloorText(text string) string {
+	text = normalizeWidth(text)
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r == '／':
+			return '/'
+		case r == 'Ｂ':
+			return 'B'
+		case r == 'Ｆ' || r == 'f':
+			return 'F'
+		case r == ' ' || r == '\t' || r == '\n':
+			return -1
+		}
+		return r
+	}, text)
+}
+
+// NormalizeFloors は FloorLabel から unit_floor / building_floors を計算する。
+// Floor は unit_floor の別名として同じ値に揃える（手動修正で floor がロックされている場合は Floor を優先）。
+func (p *Property) NormalizeFloors() {
+	info := ParseFloorLabel(p.FloorLabel)
+	p.BuildingFloors = info.BuildingFloors
+
+	switch {
+	case slices.Contains(p.GetLockedFields(), "floor"):
+		p.UnitFloor = p.Floor
+	case info.UnitFloor != nil:
+		p.UnitFloor = info.UnitFloor
+		floor := *info.UnitFloor
+		p.Floor = &floor
+	default:
+		p.UnitFloor = p.Floor
+	}
+}
@@ -0,0 +1,80 @@
+package models
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// 間取り表記（"1LDK" "ワンルーム" "2SLDK" "5LDK"）を FloorPlans の値に正規化するヘルパー
+
+// FloorPlanLarge は 4LDK より大きい間取り（5K〜、4SLDK 等）の正規化後の値
+const FloorPlanLarge = "4LDK以上"
+
+// 部屋数＋記号の間取り。記号は長いものから照合する（"SLDK" を "K" と読まない）
+var floorPlanPattern = regexp.MustCompile(`([0-9]+)(SLDK|SDK|LDK|DK|K|R)(以上)?`)
+
+// ParseFloorPlan は text から最初の正しい間取りを取り出し FloorPlans の値で返す（無ければ ""）
+//   - ワンルーム は 1R
+//   - 記号だけ（"K" "LDK"）や英単語の一部（"SKY" "BLDK2"）は間取りとみなさない
+//   - 5部屋以上・4SDK / 4SLDK・"4LDK以上" は FloorPlanLarge
+func ParseFloorPlan(text string) string {
+	t := normalizeFloorPlanText(text)
+
+	oneRoom := strings.Index(t, "ワンルーム")
+	for _, m := range floorPlanPattern.FindAllStringSubmatchIndex(t, -1) {
+		if oneRoom >= 0 && oneRoom < m[0] {
+			break // ワンルーム appears first
+		}
+		// The plan must stand alone: no ASCII letter/digit right before or after it
+		if (m[0] > 0 && isFloorPlanWordByte(t[m[0]-1])) || (m[1] < len(t) && isFloorPlanWordByte(t[m[1]])) {
+			continue
+		}
+		rooms, err := strconv.Atoi(t[m[2]:m[3]])
+		if err != nil || rooms < 1 {
+			continue
+		}
+		layout, orMore := t[m[4]:m[5]], m[6] >= 0
+		if plan := canonicalFloorPlan(rooms, layout, orMore); plan != "" {
+			return plan
+		}
+	}
+	if oneRoom >= 0 {
+		return "1R"
+	}
+	return ""
+}
+
+// canonicalFloorPlan は部屋数と記号を FloorPlans の値にする（該当しなければ ""）
+func canonicalFloorPlan(rooms int, layout string, orMore bool) string {
+	plan := strconv.Itoa(rooms) + layout
+	switch {
+	case rooms > 4 || (rooms == 4 && (orMore || strings.HasPrefix(layout, "S"))):
+		if layout == "R" {
+			return ""
+		}
+		return FloorPlanLarge
+	case IsFloorPlan(plan):
+		return plan
+	}
+	return ""
+}
+
+// normalizeFloorPlanText は全角英数字を半角にする
+func normalizeFloorPlanText(text string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= '０' && r <= '９':
+			return r - '０' + '0'
+		case r >= 'Ａ' && r <= 'Ｚ':
+			return r - 'Ａ' + 'A'
+		case r >= 'ａ' && r <= 'ｚ':
+			return r - 'ａ' + 'a'
+		}
+		return r
+	}, text)
+}
+
+func isFloorPlanWordByte(b byte) bool {
+	return b >= '0' && b <= '9' || b >= 'A' && b <= 'Z' || b >= 'a' && b <= 'z'
+}
@@ -0,0 +1,62 @@
+package models
+
+import "testing"
+
+func TestParseFloorLabel(t *testing.T) {
+	tests := []struct {
+		label    string
+		unit     *int
+		building *int
+	}{
+		{"地上15階建て/7階部分", intPtr(7), intPtr(15)},
+		{"地上１５階建て／７階部分", intPtr(7), intPtr(15)},
+		{"15階建/7階", intPtr(7), intPtr(15)},
+		{"7階/15階建", intPtr(7), intPtr(15)},
+		{"地上15階地下2階建て/地下1階部分", intPtr(-1), intPtr(15)},
+		{"地下1階付地上5階建/3階部分", intPtr(3), intPtr(5)},
+		{"B1F/10階建", intPtr(-1), intPtr(10)},
+		{"地上3階建て", nil, intPtr(3)},
+		{"2階部分", intPtr(2), nil},
+		{"地下2階", intPtr(-2), nil},
+		{"地上10階建て/7-8階部分", intPtr(7), intPtr(10)},
+		{"平屋", intPtr(1), intPtr(1)},
+		{"地上45階建て/32階部分", intPtr(32), intPtr(45)},
+		{"", nil, nil},
+		{"階数不明", nil, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.label, func(t *testing.T) {
+			info := ParseFloorLabel(tt.label)
+			if fmtPtr(info.UnitFloor) != fmtPtr(tt.unit) || fmtPtr(info.BuildingFloors) != fmtPtr(tt.building) {
+				t.Errorf("unit=%s building=%s (want %s/%s)",
+					fmtPtr(info.UnitFloor), fmtPtr(info.BuildingFloors), fmtPtr(tt.unit), fmtPtr(tt.building))
+			}
+		})
+	}
+}
+
+func TestNormalizeFloors(t *testing.T) {
+	tests := []struct {
+		name      string
+		property  Property
+		floor     *int
+		unitFloor *int
+		building  *int
+	}{
+		// Floor is an alias of unit_floor: a wrong stored value (15) is replaced by the label's 7
+		{"label wins over a stored floor", Property{FloorLabel: "地上15階建て/7階部分", Floor: intPtr(15)}, intPtr(7), intPtr(7), intPtr(15)},
+		{"no unit floor in the label", Property{FloorLabel: "地上3階建て", Floor: intPtr(2)}, intPtr(2), intPtr(2), intPtr(3)},
+		{"no label", Property{Floor: intPtr(4)}, intPtr(4), intPtr(4), nil},
+		{"manually corrected floor is kept", Property{FloorLabel: "地上15階建て/7階部分", Floor: intPtr(8), LockedFields: `["floor"]`}, intPtr(8), intPtr(8), intPtr(15)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.property
+			p.NormalizeFloors()
+			if fmtPtr(p.Floor) != fmtPtr(tt.floor) || fmtPtr(p.UnitFloor) != fmtPtr(tt.unitFloor) || fmtPtr(p.BuildingFloors) != fmtPtr(tt.building) {
+				t.Errorf("floor=%s unit_floor=%s building_floors=%s (want %s/%s/%s)",
+					fmtPtr(p.Floor), fmtPtr(p.UnitFloor), fmtPtr(p.BuildingFloors), fmtPtr(tt.floor), fmtPtr(tt.unitFloor), fmtPtr(tt.building))
+			}
+		})
+	}
+}
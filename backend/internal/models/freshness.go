@@ -0,0 +1,71 @@
+package models
+
+import "time"
+
+// 鮮度区分（fetched_at からの経過時間で判定）
+const (
+	FreshnessFresh  = "fresh"  // 本日更新
+	FreshnessRecent = "recent" // 数日以内に更新
+	FreshnessAging  = "aging"  // recent と stale の間
+	FreshnessStale  = "stale"  // 情報が古い可能性あり
+)
+
+// FreshnessThresholds は鮮度区分の境界値
+type FreshnessThresholds struct {
+	Fresh             time.Duration // これ未満は fresh
+	Recent            time.Duration // これ未満は recent
+	Stale             time.Duration // これ以上は stale
+	PriceChangeWindow time.Duration // この期間内の賃料変更で has_recent_price_change=true
+}
+
+// DefaultFreshnessThresholds は設定がない場合の境界値
+var DefaultFreshnessThresholds = FreshnessThresholds{
+	Fresh:             24 * time.Hour,
+	Recent:            72 * time.Hour,
+	Stale:             7 * 24 * time.Hour,
+	PriceChangeWindow: 7 * 24 * time.Hour,
+}
+
+// ClassifyFreshness は取得日時から鮮度区分を返す（取得日時が未設定なら stale）
+func ClassifyFreshness(fetchedAt, now time.Time, t FreshnessThresholds) string {
+	if fetchedAt.IsZero() {
+		return FreshnessStale
+	}
+
+	age := now.Sub(fetchedAt)
+	switch {
+	case age < t.Fresh:
+		return FreshnessFresh
+	case age < t.Recent:
+		return FreshnessRecent
+	case age < t.Stale:
+		return FreshnessAging
+	default:
+		return FreshnessStale
+	}
+}
+
+// ApplyFreshness は鮮度関連の計算フィールドを設定する（賃料変更フラグは別途一括取得）
+func (p *Property) ApplyFreshness(now time.Time, t FreshnessThresholds) {
+	p.Freshness = ClassifyFreshness(p.FetchedAt, now, t)
+
+	seen := p.FetchedAt
+	if p.LastSeenAt != nil && p.LastSeenAt.After(seen) {
+		seen = *p.LastSeenAt
+	}
+	if !seen.IsZero() {
+		days := int(now.Sub(seen).Hours() / 24)
+		if days < 0 {
+			days = 0
+		}
+		p.LastSeenDays = &days
+	}
+}
+
+// FetchedWithin は取得日時が now から d 以内なら true（再取得を省く判定。d が 0 以下・未取得なら false）
+func (p *Property) FetchedWithin(now time.Time, d time.Duration) bool {
+	if d <= 0 || p.FetchedAt.IsZero() {
+		return false
+	}
+	return now.Sub(p.FetchedAt) < d
+}
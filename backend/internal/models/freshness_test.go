@@ -0,0 +1,86 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestClassifyFreshness(t *testing.T) {
+	now := time.Date(2025, 6, 10, 12, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+
+	tests := []struct {
+		name string
+		age  time.Duration
+		want string
+	}{
+		{"just fetched", 0, FreshnessFresh},
+		{"just under 24h", day - time.Second, FreshnessFresh},
+		{"exactly 24h", day, FreshnessRecent},
+		{"just under 72h", 3*day - time.Second, FreshnessRecent},
+		{"exactly 72h", 3 * day, FreshnessAging},
+		{"just under 7d", 7*day - time.Second, FreshnessAging},
+		{"exactly 7d", 7 * day, FreshnessStale},
+		{"a month", 30 * day, FreshnessStale},
+		{"clock skew", -time.Hour, FreshnessFresh},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ClassifyFreshness(now.Add(-tt.age), now, DefaultFreshnessThresholds)
+			if got != tt.want {
+				t.Errorf("age %v: %q (want %q)", tt.age, got, tt.want)
+			}
+		})
+	}
+
+	if got := ClassifyFreshness(time.Time{}, now, DefaultFreshnessThresholds); got != FreshnessStale {
+		t.Errorf("zero fetched_at: %q (want stale)", got)
+	}
+}
+
+func TestClassifyFreshnessCustomThresholds(t *testing.T) {
+	now := time.Date(2025, 6, 10, 12, 0, 0, 0, time.UTC)
+	th := FreshnessThresholds{Fresh: 6 * time.Hour, Recent: 48 * time.Hour, Stale: 72 * time.Hour}
+
+	for age, want := range map[time.Duration]string{
+		5 * time.Hour:  FreshnessFresh,
+		6 * time.Hour:  FreshnessRecent,
+		48 * time.Hour: FreshnessAging,
+		72 * time.Hour: FreshnessStale,
+	} {
+		if got := ClassifyFreshness(now.Add(-age), now, th); got != want {
+			t.Errorf("age %v: %q (want %q)", age, got, want)
+		}
+	}
+}
+
+func TestApplyFreshnessLastSeenDays(t *testing.T) {
+	now := time.Date(2025, 6, 10, 12, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) *time.Time { v := now.Add(-d); return &v }
+
+	tests := []struct {
+		name      string
+		fetchedAt time.Time
+		lastSeen  *time.Time
+		want      *int
+		freshness string
+	}{
+		{"fetched only", *at(50 * time.Hour), nil, intPtr(2), FreshnessRecent},
+		{"seen later than fetched", *at(10 * 24 * time.Hour), at(time.Hour), intPtr(0), FreshnessStale},
+		{"seen earlier than fetched", *at(25 * time.Hour), at(5 * 24 * time.Hour), intPtr(1), FreshnessRecent},
+		{"never fetched", time.Time{}, nil, nil, FreshnessStale},
+		{"future timestamp", *at(-2 * time.Hour), nil, intPtr(0), FreshnessFresh},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := Property{FetchedAt: tt.fetchedAt, LastSeenAt: tt.lastSeen}
+			p.ApplyFreshness(now, DefaultFreshnessThresholds)
+			if fmtPtr(p.LastSeenDays) != fmtPtr(tt.want) {
+				t.Errorf("last_seen_days = %s (want %s)", fmtPtr(p.LastSeenDays), fmtPtr(tt.want))
+			}
+			if p.Freshness != tt.freshness {
+				t.Errorf("freshness = %q (want %q)", p.Freshness, tt.freshness)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package models
+
+import "math"
+
+// 日本の範囲（与那国島〜南鳥島、沖ノ鳥島〜択捉島を含むおおまかな矩形）
+// 範囲外の座標は抽出ミス（緯度経度の取り違え・0,0 など）とみなして保存しない
+const (
+	japanMinLatitude  = 20.0
+	japanMaxLatitude  = 46.0
+	japanMinLongitude = 122.0
+	japanMaxLongitude = 154.0
+)
+
+// GeoPoint は Meilisearch の _geo フィールド形式（{"lat": .., "lng": ..}）
+type GeoPoint struct {
+	Lat float64 `json:"lat"`
+	Lng float64 `json:"lng"`
+}
+
+// InJapan は座標が日本の範囲内かどうか
+func InJapan(lat, lng float64) bool {
+	return lat >= japanMinLatitude && lat <= japanMaxLatitude &&
+		lng >= japanMinLongitude && lng <= japanMaxLongitude
+}
+
+// SetCoordinates は日本の範囲内の座標のみ設定する（小数6桁に丸める）
+// 範囲外なら何も変更せず false を返す
+func (p *Property) SetCoordinates(lat, lng float64) bool {
+	if !InJapan(lat, lng) {
+		return false
+	}
+	lat, lng = roundCoordinate(lat), roundCoordinate(lng)
+	p.Latitude, p.Longitude = &lat, &lng
+	return true
+}
+
+// NormalizeCoordinates は片方だけの座標や範囲外の座標を nil にする（保存前に呼ぶ）
+func (p *Property) NormalizeCoordinates() {
+	if p.Latitude == nil || p.Longitude == nil || !InJapan(*p.Latitude, *p.Longitude) {
+		p.Latitude, p.Longitude = nil, nil
+	}
+}
+
+// GeoPoint は座標があれば Meilisearch の _geo 用の値を返す（なければ nil）
+func (p *Property) GeoPoint() *GeoPoint {
+	if p.Latitude == nil || p.Longitude == nil {
+		return nil
+	}
+	return &GeoPoint{Lat: *p.Latitude, Lng: *p.Longitude}
+}
+
+// roundCoordinate は decimal(9,6) に収まるよう小数6桁（約10cm）に丸める
+func roundCoordinate(v float64) float64 {
+	return math.Round(v*1e6) / 1e6
+}
@@ -0,0 +1,69 @@
+package models
+
+import (
+	"regexp"
+	"strings"
+)
+
+// 契約期間・条件等の表記から定期借家かどうかと入居条件フラグを取り出すヘルパー
+
+var (
+	// 定期借家を示す表記（"定期借家2年" "定期借家契約" "定借" "定期建物賃貸借"）
+	fixedTermLeaseMarkers = []string{"定期借家", "定期建物賃貸借", "定借"}
+	// 契約期間（"2年" "2年6ヶ月" "6ヶ月" "2028年3月まで"）
+	leaseTermPattern = regexp.MustCompile(`[0-9]{4}年[0-9]{1,2}月(?:[0-9]{1,2}日)?まで|[0-9]{1,2}年(?:[0-9]{1,2}[ヶケカかヵ箇]?月)?|[0-9]{1,2}[ヶケカかヵ箇]?月`)
+)
+
+// 入居条件の表記とこだわり条件キーの対応（facilities 配列に追加する）
+var leaseConditionLabels = []struct {
+	labels []string
+	key    string
+}{
+	{[]string{"事務所利用可", "事務所使用可", "SOHO可"}, "office_use_allowed"},
+	{[]string{"ルームシェア可"}, "room_share_allowed"},
+	{[]string{"楽器可", "楽器使用可"}, "instruments_allowed"},
+}
+
+// LeaseTerms は契約期間・条件等から抽出した契約形態と入居条件
+type LeaseTerms struct {
+	IsFixedTerm bool
+	Term        string   // 契約期間（"2年" など。表記がなければ空）
+	Conditions  []string // こだわり条件キー（office_use_allowed など）
+}
+
+// ParseLeaseTerms は契約期間・条件等の表記から定期借家かどうか、契約期間、入居条件を取り出す。
+// 普通借家は IsFixedTerm=false のまま期間だけを返す。
+func ParseLeaseTerms(texts ...string) LeaseTerms {
+	var terms LeaseTerms
+	text := normalizeWidth(strings.Join(texts, "\n"))
+	compact := strings.Join(strings.Fields(text), "")
+
+	for _, marker := range fixedTermLeaseMarkers {
+		if strings.Contains(compact, marker) {
+			terms.IsFixedTerm = true
+			break
+		}
+	}
+	if m := leaseTermPattern.FindString(compact); m != "" {
+		terms.Term = m
+	}
+
+	for _, cond := range leaseConditionLabels {
+		for _, label := range cond.labels {
+			if strings.Contains(compact, label) {
+				terms.Conditions = append(terms.Conditions, cond.key)
+				break
+			}
+		}
+	}
+	return terms
+}
+
+// ApplyLeaseTerms は契約形態を物件に設定し、入居条件を facilities に追加する
+func (p *Property) ApplyLeaseTerms(terms LeaseTerms) {
+	p.IsFixedTermLease = terms.IsFixedTerm
+	p.LeaseTerm = terms.Term
+	if len(terms.Conditions) > 0 {
+		p.SetFacilities(append(p.GetFacilities(), terms.Conditions...))
+	}
+}
@@ -0,0 +1,50 @@
+package models
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseLeaseTerms(t *testing.T) {
+	tests := []struct {
+		name       string
+		texts      []string
+		fixedTerm  bool
+		term       string
+		conditions []string
+	}{
+		{"standard lease", []string{"2年", "事務所利用可、楽器可"}, false, "2年", []string{"office_use_allowed", "instruments_allowed"}},
+		{"fixed term", []string{"定期借家2年", "ルームシェア可"}, true, "2年", []string{"room_share_allowed"}},
+		{"fixed term without a period", []string{"定期借家契約"}, true, "", nil},
+		{"short form", []string{"定借 2年6ヶ月"}, true, "2年6ヶ月", nil},
+		{"formal name with an end date", []string{"定期建物賃貸借 2028年3月まで"}, true, "2028年3月まで", nil},
+		{"full-width digits", []string{"２年"}, false, "2年", nil},
+		{"months only", []string{"6ヵ月", "SOHO可"}, false, "6ヵ月", []string{"office_use_allowed"}},
+		{"no rows", nil, false, "", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParseLeaseTerms(tt.texts...)
+			if got.IsFixedTerm != tt.fixedTerm || got.Term != tt.term || !slices.Equal(got.Conditions, tt.conditions) {
+				t.Errorf("ParseLeaseTerms(%q) = %+v (want fixed=%v term=%q conditions=%v)",
+					tt.texts, got, tt.fixedTerm, tt.term, tt.conditions)
+			}
+		})
+	}
+}
+
+func TestApplyLeaseTerms(t *testing.T) {
+	p := &Property{}
+	p.SetFacilities([]string{"auto_lock"})
+	p.ApplyLeaseTerms(LeaseTerms{IsFixedTerm: true, Term: "2年", Conditions: []string{"room_share_allowed"}})
+
+	if !p.IsFixedTermLease || p.LeaseTerm != "2年" {
+		t.Errorf("is_fixed_term_lease=%v lease_term=%q", p.IsFixedTermLease, p.LeaseTerm)
+	}
+	facilities := p.GetFacilities()
+	for _, key := range []string{"auto_lock", "room_share_allowed"} {
+		if !slices.Contains(facilities, key) {
+			t.Errorf("facilities missing %s: %v", key, facilities)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package models
+
+import "strings"
+
+// NormalizeStationName は駅名の表記揺れを正規化する
+// 全角英数字を半角に、空白を除去し、末尾の「駅」を取り除く
+func NormalizeStationName(name string) string {
+	name = normalizeWidth(name)
+	name = strings.Join(strings.Fields(name), "")
+	return strings.TrimSuffix(name, "駅")
+}
+
+// NormalizeLineName は路線名の表記揺れを正規化する
+// 事業者名（JR/東京メトロ等）は残し、全角英数字（ＪＲ）を半角に統一して空白を除去する。
+// 「ＪＲ山手線」「JR 山手線」はどちらも「JR山手線」になる。
+func NormalizeLineName(name string) string {
+	name = normalizeWidth(name)
+	name = strings.Join(strings.Fields(name), "")
+	return strings.Trim(name, "/")
+}
+
+// normalizeWidth は全角英数字・全角スペースを半角に変換する
+func normalizeWidth(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'Ａ' && r <= 'Ｚ':
+			return r - 'Ａ' + 'A'
+		case r >= 'ａ' && r <= 'ｚ':
+			return r - 'ａ' + 'a'
+		case r >= '０' && r <= '９':
+			return r - '０' + '0'
+		case r == '　':
+			return ' '
+		}
+		return r
+	}, s)
+}
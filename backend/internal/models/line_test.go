@@ -0,0 +1,35 @@
+package models
+
+import "testing"
+
+func TestNormalizeLineName(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"JR山手線", "JR山手線"},
+		{"ＪＲ山手線", "JR山手線"},
+		{"JR 山手線", "JR山手線"},
+		{"　ＪＲ　山手線　", "JR山手線"},
+		{"東京メトロ丸ノ内線", "東京メトロ丸ノ内線"},
+		{"東急東横線/", "東急東横線"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := NormalizeLineName(tt.in); got != tt.want {
+			t.Errorf("NormalizeLineName(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestNormalizeStationName(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"新宿", "新宿"},
+		{"新宿駅", "新宿"},
+		{" 中野 駅", "中野"},
+		{"市ヶ谷", "市ヶ谷"},
+		{"ＪＲ難波駅", "JR難波"},
+	}
+	for _, tt := range tests {
+		if got := NormalizeStationName(tt.in); got != tt.want {
+			t.Errorf("NormalizeStationName(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// MaintenanceState はメンテナンス用の読み取り専用モード（1行のみ、ID=1）
+// 再起動後も状態を引き継ぐために保存する
+type MaintenanceState struct {
+	ID        int       `gorm:"primaryKey" json:"-"`
+	ReadOnly  bool      `gorm:"not null;default:false" json:"read_only"`
+	Message   string    `gorm:"type:varchar(500)" json:"message,omitempty"` // 503 応答で返すメッセージ
+	UpdatedAt time.Time `gorm:"not null" json:"updated_at"`
+}
+
+// TableName はテーブル名を明示的に指定
+func (MaintenanceState) TableName() string {
+	return "maintenance_state"
+}
@@ -0,0 +1,172 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// ManualCorrection は管理画面からの手動修正の監査ログ
+type ManualCorrection struct {
+	ID         uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	PropertyID string    `gorm:"type:varchar(32);not null;index" json:"property_id"`
+	Field      string    `gorm:"type:varchar(50);not null" json:"field"`
+	Action     string    `gorm:"type:varchar(20);not null" json:"action"` // set / unlock
+	OldValue   string    `gorm:"type:text" json:"old_value,omitempty"`
+	NewValue   string    `gorm:"type:text" json:"new_value,omitempty"`
+	Author     string    `gorm:"type:varchar(100)" json:"author,omitempty"`
+	Reason     string    `gorm:"type:text" json:"reason,omitempty"`
+	CreatedAt  time.Time `gorm:"type:datetime;not null;autoCreateTime;index" json:"created_at"`
+}
+
+// TableName specifies the table name
+func (ManualCorrection) TableName() string {
+	return "manual_corrections"
+}
+
+// 手動修正のアクション
+const (
+	CorrectionActionSet    = "set"
+	CorrectionActionUnlock = "unlock"
+)
+
+// ErrFieldNotCorrectable は手動修正の対象外フィールドを指定した場合のエラー
+var ErrFieldNotCorrectable = errors.New("field cannot be corrected manually")
+
+// ErrFieldNotLocked はロックされていないフィールドのロック解除を要求した場合のエラー
+var ErrFieldNotLocked = errors.New("field is not locked")
+
+// CorrectableFields は手動修正できるフィールド（JSON 名）
+// ID・ステータス・タイムスタンプなど管理用の列は対象外
+var CorrectableFields = map[string]bool{
+	"title":                 true,
+	"image_url":             true,
+	"rent":                  true,
+	"floor_plan":            true,
+	"area":                  true,
+	"walk_time":             true,
+	"bus_minutes":           true,
+	"station":               true,
+	"address":               true,
+	"building_age":          true,
+	"floor":                 true,
+	"building_type":         true,
+	"structure":             true,
+	"building_name":         true,
+	"direction":             true,
+	"floor_label":           true,
+	"parking":               true,
+	"contract_period":       true,
+	"insurance":             true,
+	"room_layout_image_url": true,
+	"management_fee":        true,
+	"deposit":               true,
+	"key_money":             true,
+	"guarantor_deposit":     true,
+	"security_deposit":      true,
+	"move_in_date":          true,
+	"conditions":            true,
+	"notes":                 true,
+}
+
+// GetLockedFields は手動修正でロックされたフィールドを返す
+func (p *Property) GetLockedFields() []string {
+	return decodeStringList(p.LockedFields, "locked_fields", p.ID)
+}
+
+// IsFieldLocked はフィールドが手動修正でロックされているかを返す
+func (p *Property) IsFieldLocked(field string) bool {
+	for _, f := range p.GetLockedFields() {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// LockField はフィールドをロックする（スクレイピングで上書きされなくなる）
+func (p *Property) LockField(field string) {
+	p.LockedFields = encodeStringList(append(p.GetLockedFields(), field))
+}
+
+// UnlockField はフィールドのロックを解除し、ロックされていた場合 true を返す
+func (p *Property) UnlockField(field string) bool {
+	fields := p.GetLockedFields()
+	kept := make([]string, 0, len(fields))
+	for _, f := range fields {
+		if f != field {
+			kept = append(kept, f)
+		}
+	}
+	p.LockedFields = encodeStringList(kept)
+	return len(kept) != len(fields)
+}
+
+// FieldValueString はフィールド値を監査ログ用の文字列で返す（nil は空文字）
+func (p *Property) FieldValueString(field string) (string, error) {
+	v, err := correctableField(reflect.ValueOf(p).Elem(), field)
+	if err != nil {
+		return "", err
+	}
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return "", nil
+		}
+		v = v.Elem()
+	}
+	if v.Kind() == reflect.String {
+		return v.String(), nil
+	}
+	return fmt.Sprint(v.Interface()), nil
+}
+
+// SetFieldJSON はフィールドに JSON 値を設定する（null でポインタ型をクリア）
+func (p *Property) SetFieldJSON(field string, raw json.RawMessage) error {
+	v, err := correctableField(reflect.ValueOf(p).Elem(), field)
+	if err != nil {
+		return err
+	}
+	target := reflect.New(v.Type())
+	if err := json.Unmarshal(raw, target.Interface()); err != nil {
+		return fmt.Errorf("%s: %w", field, err)
+	}
+	v.Set(target.Elem())
+	return nil
+}
+
+// ApplyLockedFields はロック中のフィールドを src（保存済みの行）からコピーする。
+// 再スクレイピング時に手動修正を維持するために使う。コピーした場合 true を返す。
+func (p *Property) ApplyLockedFields(src *Property) bool {
+	p.LockedFields = src.LockedFields
+	copied := false
+	dst := reflect.ValueOf(p).Elem()
+	from := reflect.ValueOf(src).Elem()
+	for _, field := range src.GetLockedFields() {
+		dv, err := correctableField(dst, field)
+		if err != nil {
+			continue
+		}
+		sv, _ := correctableField(from, field)
+		dv.Set(sv)
+		copied = true
+	}
+	return copied
+}
+
+// correctableField は JSON 名から修正可能なフィールドの reflect.Value を返す
+func correctableField(v reflect.Value, field string) (reflect.Value, error) {
+	if !CorrectableFields[field] {
+		return reflect.Value{}, fmt.Errorf("%w: %s", ErrFieldNotCorrectable, field)
+	}
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		name := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+		if name == field {
+			return v.Field(i), nil
+		}
+	}
+	return reflect.Value{}, fmt.Errorf("%w: %s", ErrFieldNotCorrectable, field)
+}
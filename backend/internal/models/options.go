@@ -0,0 +1,68 @@
+package models
+
+import "slices"
+
+// 検証・正規化とフロントエンドの選択肢（/api/meta/options）で共有する正規の列挙値。
+// 値を追加するときはここだけを変更する（switch 文に直書きしない）。
+
+// FloorPlans は正規化後の間取り（ParseFloorPlan が返す値）。ワンルームは 1R、4LDK より大きいものは FloorPlanLarge
+var FloorPlans = []string{
+	"1R", "1K", "1DK", "1LDK", "1SDK", "1SLDK",
+	"2K", "2DK", "2LDK", "2SDK", "2SLDK",
+	"3K", "3DK", "3LDK", "3SDK", "3SLDK",
+	"4K", "4DK", "4LDK", FloorPlanLarge,
+}
+
+// 建物種別（building_type の正規化後の値）
+const (
+	BuildingTypeMansion      = "mansion"       // マンション
+	BuildingTypeApartment    = "apartment"     // アパート
+	BuildingTypeHouse        = "house"         // 一戸建て
+	BuildingTypeTerraceHouse = "terrace_house" // テラスハウス
+	BuildingTypeTownHouse    = "town_house"    // タウンハウス
+	BuildingTypeShareHouse   = "share_house"   // シェアハウス
+)
+
+// BuildingTypes は建物種別の一覧
+var BuildingTypes = []string{
+	BuildingTypeMansion, BuildingTypeApartment, BuildingTypeHouse,
+	BuildingTypeTerraceHouse, BuildingTypeTownHouse, BuildingTypeShareHouse,
+}
+
+// 物件一覧（/api/properties?sort=）の並び順キー
+const (
+	SortNewest          = "newest" // fetched_at の新しい順（既定）
+	SortFetchedAt       = "fetched_at"
+	SortFetchedAtDesc   = "fetched_at_desc"
+	SortFetchedAtAsc    = "fetched_at_asc"
+	SortCreatedAtDesc   = "created_at_desc" // 初回取得の新しい順（新着フィード）
+	SortRentAsc         = "rent_asc"
+	SortRentDesc        = "rent_desc"
+	SortAreaDesc        = "area_desc"
+	SortAreaAsc         = "area_asc"
+	SortWalkTimeAsc     = "walk_time_asc"
+	SortBuildingAgeAsc  = "building_age_asc"
+	SortBuildingAgeDesc = "building_age_desc"
+)
+
+// PropertySortKeys は物件一覧で受け付ける並び順キー（空文字は SortNewest と同じ）
+var PropertySortKeys = []string{
+	SortNewest, SortFetchedAt, SortFetchedAtDesc, SortFetchedAtAsc, SortCreatedAtDesc,
+	SortRentAsc, SortRentDesc, SortAreaDesc, SortAreaAsc,
+	SortWalkTimeAsc, SortBuildingAgeAsc, SortBuildingAgeDesc,
+}
+
+// IsFloorPlan は正規の間取りかどうかを返す
+func IsFloorPlan(v string) bool {
+	return slices.Contains(FloorPlans, v)
+}
+
+// IsBuildingType は正規の建物種別かどうかを返す
+func IsBuildingType(v string) bool {
+	return slices.Contains(BuildingTypes, v)
+}
+
+// IsPropertySortKey は物件一覧の並び順キーとして受け付けるかを返す（空文字は既定の並び順）
+func IsPropertySortKey(v string) bool {
+	return v == "" || slices.Contains(PropertySortKeys, v)
+}
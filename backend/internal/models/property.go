@@ -1,6 +1,12 @@
 package models
 
-import "time"
+import (
+	"crypto/md5"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
 
 type Property struct {
 	// 基本情報
@@ -15,11 +21,16 @@ type Property struct {
 	Rent              *int     `gorm:"type:int;index" json:"rent,omitempty"`
 	FloorPlan         string   `gorm:"type:varchar(20);index" json:"floor_plan,omitempty"`
 	Area              *float64 `gorm:"type:decimal(10,2)" json:"area,omitempty"`
+	AreaUnit          string   `gorm:"type:varchar(8)" json:"area_unit,omitempty"`                  // Area の元の単位（sqm / tsubo / jo、tsubo・jo は換算値）
 	WalkTime          *int     `gorm:"type:int;index" json:"walk_time,omitempty"`
+	BusMinutes        *int     `gorm:"type:int" json:"bus_minutes,omitempty"`                      // 最寄り駅がバス便のときの乗車分（このとき walk_time は nil）
+	WalkTimeBucket    string   `gorm:"type:varchar(10);not null;default:'unknown';index" json:"walk_time_bucket"` // walk_time の分数帯（1-5/6-10/11-15/16+/unknown、保存時に計算）
 	Station           string   `gorm:"type:text" json:"station,omitempty"`
 	Address           string   `gorm:"type:text" json:"address,omitempty"`
 	BuildingAge       *int     `gorm:"type:int" json:"building_age,omitempty"`
-	Floor             *int     `gorm:"type:int" json:"floor,omitempty"`
+	Floor             *int     `gorm:"type:int" json:"floor,omitempty"`                            // unit_floor の別名（互換用）
+	UnitFloor         *int     `gorm:"type:int" json:"unit_floor,omitempty"`                       // 所在階（地下は負数、FloorLabel から計算）
+	BuildingFloors    *int     `gorm:"type:int;index" json:"building_floors,omitempty"`            // 建物の地上階数（FloorLabel から計算）
 	BuildingType      string   `gorm:"type:varchar(50);index" json:"building_type"`                // マンション/アパート/一戸建て
 	Structure         string   `gorm:"type:varchar(50)" json:"structure"`                          // 鉄筋コンクリート/軽量鉄骨等
 	Facilities        string   `gorm:"type:text" json:"facilities"`                                // こだわり条件(JSON配列形式)
@@ -43,11 +54,58 @@ type Property struct {
 	Conditions        string `gorm:"type:varchar(255)" json:"conditions,omitempty"`          // 条件等
 	Notes             string `gorm:"type:text" json:"notes,omitempty"`                       // 備考（初期費用詳細など）
 
+	// 費用の数値版（保存時に上記文字列から計算。解析不能時は nil）
+	ManagementFeeYen    *int     `gorm:"type:int" json:"management_fee_yen,omitempty"`
+	DepositMonths       *float64 `gorm:"type:decimal(4,2)" json:"deposit_months,omitempty"`
+	DepositYen          *int     `gorm:"type:int" json:"deposit_yen,omitempty"`
+	KeyMoneyMonths      *float64 `gorm:"type:decimal(4,2)" json:"key_money_months,omitempty"`
+	KeyMoneyYen         *int     `gorm:"type:int" json:"key_money_yen,omitempty"`
+	GuarantorDepositYen *int     `gorm:"type:int" json:"guarantor_deposit_yen,omitempty"` // 保証金
+	SecurityDepositYen  *int     `gorm:"type:int" json:"security_deposit_yen,omitempty"`  // 敷引
+
+	// キャンペーン（フリーレント・仲介手数料無料）
+	FreeRent       bool `gorm:"type:boolean;not null;default:false;index" json:"free_rent"`
+	FreeRentMonths *int `gorm:"type:int" json:"free_rent_months,omitempty"`
+	NoBrokerageFee bool `gorm:"type:boolean;not null;default:false;index" json:"no_brokerage_fee"`
+
+	// 契約形態（契約期間・条件等から計算）
+	IsFixedTermLease bool   `gorm:"type:boolean;not null;default:false;index" json:"is_fixed_term_lease"` // 定期借家
+	LeaseTerm        string `gorm:"type:varchar(50)" json:"lease_term,omitempty"`                         // 契約期間（例: 2年）
+
+	// 再掲載検出（画像パス+面積+間取り+住所のハッシュ）
+	Fingerprint  string  `gorm:"type:varchar(32);index" json:"fingerprint,omitempty"`
+	RelistedFrom *string `gorm:"type:varchar(32);index" json:"relisted_from,omitempty"` // 同一住戸と判定された削除済み物件ID
+
+	// 位置情報（詳細ページの埋め込みJSON・地図iframeから取得、日本の範囲外は保存しない）
+	Latitude  *float64 `gorm:"type:decimal(9,6)" json:"latitude,omitempty"`  // 緯度
+	Longitude *float64 `gorm:"type:decimal(9,6)" json:"longitude,omitempty"` // 経度
+
+	// 手動修正でロックされたフィールド（JSON配列、スクレイピングで上書きしない）
+	LockedFields string `gorm:"type:varchar(500)" json:"locked_fields,omitempty"`
+
+	// 条件付きリクエスト用の検証子（詳細ページ取得時の ETag / Last-Modified、再スクレイプ時に送信）
+	ETag         string `gorm:"column:etag;type:varchar(255)" json:"-"`
+	LastModified string `gorm:"type:varchar(64)" json:"-"`
+
 	// ステータス管理（論理削除）
 	Status     PropertyStatus `gorm:"type:varchar(20);not null;default:'active';index" json:"status"`
 	RemovedAt  *time.Time     `gorm:"type:datetime" json:"removed_at,omitempty"`
 	LastSeenAt *time.Time     `gorm:"type:datetime;index" json:"last_seen_at,omitempty"` // 最終確認日時
 
+	// 検索インデックス用（property_stations から設定、DBには保存しない）
+	Lines []string `gorm:"-" json:"lines,omitempty"`
+
+	// 鮮度表示用の計算フィールド（レスポンス時に設定、DBには保存しない）
+	Freshness            string `gorm:"-" json:"freshness,omitempty"`
+	LastSeenDays         *int   `gorm:"-" json:"last_seen_days,omitempty"`
+	HasRecentPriceChange bool   `gorm:"-" json:"has_recent_price_change"`
+
+	// Meilisearch のソート用（fetched_at の UNIX 秒、インデックス時に設定）
+	FetchedAtTS int64 `gorm:"-" json:"fetched_at_ts,omitempty"`
+
+	// Meilisearch の地理検索用（Latitude/Longitude から、インデックス時に設定）
+	Geo *GeoPoint `gorm:"-" json:"_geo,omitempty"`
+
 	// タイムスタンプ
 	FetchedAt time.Time `gorm:"type:datetime;not null" json:"fetched_at"`
 	CreatedAt time.Time `gorm:"type:datetime;not null;autoCreateTime;index:idx_created_at,sort:desc" json:"created_at"`
@@ -115,3 +173,26 @@ func (p *Property) MarkAsRemoved() {
 	now := time.Now()
 	p.RemovedAt = &now
 }
+
+// ComputeFingerprint は再掲載検出用のフィンガープリントを計算する
+// 画像URLのパス（ホスト・クエリ除去）と面積・間取り・住所を組み合わせてハッシュ化する。
+// 画像も面積もない場合は判定材料が不足するため空文字を返す。
+func (p *Property) ComputeFingerprint() string {
+	imagePath := ""
+	if p.ImageURL != "" {
+		if u, err := url.Parse(p.ImageURL); err == nil {
+			imagePath = strings.ToLower(strings.TrimSuffix(u.Path, "/"))
+		}
+	}
+	if imagePath == "" || p.Area == nil {
+		return ""
+	}
+
+	key := fmt.Sprintf("%s|%.2f|%s|%s",
+		imagePath,
+		*p.Area,
+		strings.TrimSpace(p.FloorPlan),
+		strings.Join(strings.Fields(p.Address), ""),
+	)
+	return fmt.Sprintf("%x", md5.Sum([]byte(key)))
+}
@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// PropertyAlias はサイト側でURLが変わった物件の旧ID（再掲載時に旧詳細URLが新URLへ301される）
+// 旧 source_property_id から現在の物件行を引けるように残す
+type PropertyAlias struct {
+	ID               uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	Source           string    `gorm:"type:varchar(20);not null;uniqueIndex:idx_property_alias_source,priority:1" json:"source"`
+	SourcePropertyID string    `gorm:"type:varchar(100);not null;uniqueIndex:idx_property_alias_source,priority:2" json:"source_property_id"` // 旧ID
+	DetailURL        string    `gorm:"type:varchar(500);not null" json:"detail_url"`                                                          // 旧詳細URL
+	PropertyID       string    `gorm:"type:varchar(32);not null;index" json:"property_id"`                                                    // 移行先の properties.id
+	CreatedAt        time.Time `gorm:"type:datetime;not null;autoCreateTime" json:"created_at"`
+}
+
+// TableName はテーブル名を明示的に指定
+func (PropertyAlias) TableName() string {
+	return "property_aliases"
+}
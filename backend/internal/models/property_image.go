@@ -1,6 +1,12 @@
 package models
 
-import "time"
+import (
+	"strings"
+	"time"
+)
+
+// MaxPropertyImages は1物件あたりに保存する画像の上限（Yahoo の詳細ページは概ね20枚まで）
+const MaxPropertyImages = 20
 
 // PropertyImage represents an image associated with a property
 type PropertyImage struct {
@@ -16,3 +22,26 @@ type PropertyImage struct {
 func (PropertyImage) TableName() string {
 	return "property_images"
 }
+
+// NewPropertyImages は画像URLを掲載順の PropertyImage に変換する。
+// 空・重複URLを除き、MaxPropertyImages 件で打ち切る（sort_order は 0 始まりの連番）
+func NewPropertyImages(propertyID string, imageURLs []string) []PropertyImage {
+	images := make([]PropertyImage, 0, min(len(imageURLs), MaxPropertyImages))
+	seen := make(map[string]bool, len(imageURLs))
+	for _, imageURL := range imageURLs {
+		imageURL = strings.TrimSpace(imageURL)
+		if imageURL == "" || seen[imageURL] {
+			continue
+		}
+		seen[imageURL] = true
+		images = append(images, PropertyImage{
+			PropertyID: propertyID,
+			ImageURL:   imageURL,
+			SortOrder:  len(images),
+		})
+		if len(images) >= MaxPropertyImages {
+			break
+		}
+	}
+	return images
+}
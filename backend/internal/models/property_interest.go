@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// PropertyView は物件詳細の日別閲覧数（GET /api/properties/:id をまとめて加算）
+type PropertyView struct {
+	ID         uint      `gorm:"primaryKey;autoIncrement" json:"-"`
+	PropertyID string    `gorm:"type:varchar(32);not null;uniqueIndex:idx_property_view_day,priority:1" json:"property_id"`
+	Day        string    `gorm:"type:varchar(10);not null;uniqueIndex:idx_property_view_day,priority:2;index" json:"day"` // YYYY-MM-DD
+	Views      int       `gorm:"type:int;not null;default:0" json:"views"`
+	UpdatedAt  time.Time `gorm:"type:datetime;not null;autoUpdateTime" json:"updated_at"`
+}
+
+// TableName はテーブル名を明示的に指定
+func (PropertyView) TableName() string {
+	return "property_views"
+}
+
+// PropertyFavorite は物件ごとのお気に入り登録数（ユーザー機能導入までは匿名カウンタ）
+type PropertyFavorite struct {
+	PropertyID string    `gorm:"type:varchar(32);primaryKey" json:"property_id"`
+	Count      int       `gorm:"type:int;not null;default:0" json:"count"`
+	UpdatedAt  time.Time `gorm:"type:datetime;not null;autoUpdateTime" json:"updated_at"`
+}
+
+// TableName はテーブル名を明示的に指定
+func (PropertyFavorite) TableName() string {
+	return "property_favorites"
+}
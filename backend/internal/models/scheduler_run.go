@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// SchedulerRun はスケジューラーの1回の実行（一覧URLの巡回と既知物件の再投入）の結果
+// GET /api/admin/scraping/status で直近の実行を返すために保存する
+type SchedulerRun struct {
+	ID         uint       `gorm:"primaryKey;autoIncrement" json:"id"`
+	Trigger    string     `gorm:"type:varchar(20);not null" json:"trigger"`    // scheduled / manual
+	Schedule   string     `gorm:"type:varchar(100)" json:"schedule,omitempty"` // scheduler.schedules の名前（daily_run_time・手動実行は空）
+	Status     string     `gorm:"type:varchar(20);not null" json:"status"`     // running / done / failed
+	StartedAt  time.Time  `gorm:"type:datetime;not null;index" json:"started_at"`
+	FinishedAt *time.Time `gorm:"type:datetime" json:"finished_at,omitempty"`
+	Discovered int        `gorm:"not null;default:0" json:"discovered"` // 一覧URLから新たにキューに入れた件数
+	Enqueued   int        `gorm:"not null;default:0" json:"enqueued"`   // 既知物件の再取得として入れた件数
+	ListURLs   string     `gorm:"type:text" json:"-"`                   // 一覧URLごとの結果（scheduler.ListDiscovery の JSON 配列）
+	Error      string     `gorm:"type:varchar(500)" json:"error,omitempty"`
+}
+
+// Scheduler run statuses
+const (
+	SchedulerRunRunning = "running"
+	SchedulerRunDone    = "done"
+	SchedulerRunFailed  = "failed"
+)
+
+// TableName はテーブル名を明示的に指定
+func (SchedulerRun) TableName() string {
+	return "scheduler_runs"
+}
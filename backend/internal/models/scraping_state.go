@@ -1,6 +1,9 @@
 package models
 
-import "time"
+import (
+	"real-estate-portal/internal/errtext"
+	"time"
+)
 
 // ScrapingState tracks scraping job status and blocking state
 type ScrapingState struct {
@@ -12,6 +15,12 @@ type ScrapingState struct {
 	LastSuccess   *time.Time `json:"last_success,omitempty"`
 	FailureCount  int       `gorm:"not null;default:0" json:"failure_count"`
 	SuccessCount  int       `gorm:"not null;default:0" json:"success_count"`
+
+	// Set by POST /api/admin/worker/pause: the queue worker claims nothing until resumed
+	WorkerPaused       bool       `gorm:"not null;default:false" json:"worker_paused"`
+	WorkerPausedReason string     `gorm:"size:500" json:"worker_paused_reason,omitempty"`
+	WorkerPausedAt     *time.Time `json:"worker_paused_at,omitempty"`
+
 	CreatedAt     time.Time `gorm:"not null" json:"created_at"`
 	UpdatedAt     time.Time `gorm:"not null" json:"updated_at"`
 }
@@ -38,7 +47,7 @@ func (s *ScrapingState) CanScrape() bool {
 // SetBlocked marks scraping as blocked with cooling period
 func (s *ScrapingState) SetBlocked(reason string, coolingPeriod time.Duration) {
 	s.IsBlocked = true
-	s.BlockedReason = reason
+	s.BlockedReason = errtext.Clean(reason)
 	blockedUntil := time.Now().Add(coolingPeriod)
 	s.BlockedUntil = &blockedUntil
 	s.LastAttempt = time.Now()
@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// SharedSearch は絞り込み条件の共有リンク（token で条件を復元して再検索する）
+type SharedSearch struct {
+	Token     string    `gorm:"type:varchar(32);primaryKey" json:"token"`
+	Params    string    `gorm:"type:text;not null" json:"-"` // 絞り込み条件（search.FilterParams の JSON）
+	ExpiresAt time.Time `gorm:"type:datetime;not null;index" json:"expires_at"`
+	CreatedAt time.Time `gorm:"type:datetime;not null;autoCreateTime" json:"created_at"`
+}
+
+// TableName はテーブル名を明示的に指定
+func (SharedSearch) TableName() string {
+	return "shared_searches"
+}
+
+// IsExpired は共有リンクの有効期限が切れているかどうか
+func (s *SharedSearch) IsExpired(now time.Time) bool {
+	return !now.Before(s.ExpiresAt)
+}
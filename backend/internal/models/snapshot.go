@@ -13,6 +13,7 @@ type PropertySnapshot struct {
 	FloorPlan   string   `gorm:"type:varchar(20)" json:"floor_plan,omitempty"`
 	Area        *float64 `gorm:"type:decimal(10,2)" json:"area,omitempty"`
 	WalkTime    *int     `gorm:"type:int" json:"walk_time,omitempty"`
+	BusMinutes  *int     `gorm:"type:int" json:"bus_minutes,omitempty"`
 	Station     string   `gorm:"type:text" json:"station,omitempty"`
 	Address     string   `gorm:"type:text" json:"address,omitempty"`
 	BuildingAge *int     `gorm:"type:int" json:"building_age,omitempty"`
@@ -20,6 +21,38 @@ type PropertySnapshot struct {
 	ImageURL    string   `gorm:"type:text" json:"image_url,omitempty"`
 	Status      string   `gorm:"type:varchar(20);not null" json:"status"`
 
+	// Location
+	Latitude  *float64 `gorm:"type:decimal(9,6)" json:"latitude,omitempty"`
+	Longitude *float64 `gorm:"type:decimal(9,6)" json:"longitude,omitempty"`
+
+	// Normalized fees
+	ManagementFeeYen    *int `gorm:"type:int" json:"management_fee_yen,omitempty"`
+	DepositYen          *int `gorm:"type:int" json:"deposit_yen,omitempty"`
+	KeyMoneyYen         *int `gorm:"type:int" json:"key_money_yen,omitempty"`
+	GuarantorDepositYen *int `gorm:"type:int" json:"guarantor_deposit_yen,omitempty"`
+	SecurityDepositYen  *int `gorm:"type:int" json:"security_deposit_yen,omitempty"`
+
+	// Campaigns
+	FreeRent       bool `gorm:"type:boolean;default:false" json:"free_rent"`
+	FreeRentMonths *int `gorm:"type:int" json:"free_rent_months,omitempty"`
+	NoBrokerageFee bool `gorm:"type:boolean;default:false" json:"no_brokerage_fee"`
+
+	// Lease type
+	IsFixedTermLease bool `gorm:"type:boolean;default:false" json:"is_fixed_term_lease"`
+
+	// Listing terms (入居可能時期 / 契約期間 / 条件等 / 駐車場 change between relistings)
+	MoveInDate     string `gorm:"type:varchar(100)" json:"move_in_date,omitempty"`
+	ContractPeriod string `gorm:"type:varchar(50)" json:"contract_period,omitempty"`
+	Conditions     string `gorm:"type:varchar(255)" json:"conditions,omitempty"`
+	Parking        string `gorm:"type:varchar(255)" json:"parking,omitempty"`
+
+	// Manually corrected fields at snapshot time (comma-separated)
+	ManualFields string `gorm:"type:varchar(500)" json:"manual_fields,omitempty"`
+
+	// Partial: taken from a light refresh, which only observed the rent (the other fields are
+	// carried over from the stored property and are not compared)
+	Partial bool `gorm:"type:boolean;default:false" json:"partial,omitempty"`
+
 	// Change detection
 	HasChanged bool   `gorm:"type:boolean;default:false" json:"has_changed"`
 	ChangeNote string `gorm:"type:text" json:"change_note,omitempty"`
@@ -59,4 +92,16 @@ const (
 	ChangeTypeImage       = "image_changed"
 	ChangeTypeNew         = "new_property"
 	ChangeTypeRemoved     = "property_removed"
+	ChangeTypeRelisted    = "relisted"           // 削除済み物件が別IDで再掲載された
+	ChangeTypeCampaign    = "campaign_changed"   // フリーレント・仲介手数料無料の開始/終了
+	ChangeTypeLeaseType   = "lease_type_changed" // 普通借家 ⇔ 定期借家
+	ChangeTypeURLChanged  = "url_changed"        // 詳細URLが別IDへリダイレクトされた（旧URL → 新URL）
+	ChangeTypeAccess      = "access_changed"     // 最寄り駅までの徒歩分・バス分が変わった
 )
+
+// ChangeTypes lists every change type recorded in property_changes
+var ChangeTypes = []string{
+	ChangeTypeRent, ChangeTypeStatus, ChangeTypeArea, ChangeTypeFloorPlan, ChangeTypeBuildingAge,
+	ChangeTypeImage, ChangeTypeNew, ChangeTypeRemoved, ChangeTypeRelisted, ChangeTypeCampaign,
+	ChangeTypeLeaseType, ChangeTypeURLChanged, ChangeTypeAccess,
+}
@@ -0,0 +1,45 @@
+package models
+
+// 駅徒歩の分数帯（walk_time_bucket）。walk_time から保存時・インデックス時に計算する派生値で、
+// ファセットと /api/stats/walk-buckets の集計に使う。
+const (
+	WalkBucket1To5    = "1-5"
+	WalkBucket6To10   = "6-10"
+	WalkBucket11To15  = "11-15"
+	WalkBucket16Plus  = "16+"
+	WalkBucketUnknown = "unknown" // 徒歩分数なし（バス便・抽出不可）
+)
+
+// WalkTimeBuckets は分数帯の一覧（表示順）
+var WalkTimeBuckets = []string{
+	WalkBucket1To5, WalkBucket6To10, WalkBucket11To15, WalkBucket16Plus, WalkBucketUnknown,
+}
+
+// WalkTimeBucket は徒歩分数の分数帯を返す（5分は 1-5、10分は 6-10。徒歩0分は 1-5、nil・負数は unknown）
+func WalkTimeBucket(minutes *int) string {
+	switch {
+	case minutes == nil || *minutes < 0:
+		return WalkBucketUnknown
+	case *minutes <= 5:
+		return WalkBucket1To5
+	case *minutes <= 10:
+		return WalkBucket6To10
+	case *minutes <= 15:
+		return WalkBucket11To15
+	}
+	return WalkBucket16Plus
+}
+
+// WalkTimeBucketSQL は column（分数）を WalkTimeBucket と同じ分数帯に変換する SQL 式
+func WalkTimeBucketSQL(column string) string {
+	return "CASE WHEN " + column + " IS NULL OR " + column + " < 0 THEN '" + WalkBucketUnknown + "'" +
+		" WHEN " + column + " <= 5 THEN '" + WalkBucket1To5 + "'" +
+		" WHEN " + column + " <= 10 THEN '" + WalkBucket6To10 + "'" +
+		" WHEN " + column + " <= 15 THEN '" + WalkBucket11To15 + "'" +
+		" ELSE '" + WalkBucket16Plus + "' END"
+}
+
+// NormalizeWalkTimeBucket は walk_time から walk_time_bucket を計算する
+func (p *Property) NormalizeWalkTimeBucket() {
+	p.WalkTimeBucket = WalkTimeBucket(p.WalkTime)
+}
@@ -0,0 +1,33 @@
+package queue
+
+import "time"
+
+// agingHours is the priority aging step in whole hours (0 = no aging): rounded down, at least one
+func agingHours(aging time.Duration) int {
+	if aging <= 0 {
+		return 0
+	}
+	return max(int(aging/time.Hour), 1)
+}
+
+// AgedPriorityExpr is the SQL for a row's priority raised one level per aging step since
+// created_at, with its vars ("priority" alone when aging is off). now is passed in rather than
+// NOW() so the age uses the same clock and time zone as created_at.
+func AgedPriorityExpr(now time.Time, aging time.Duration) (string, []interface{}) {
+	hours := agingHours(aging)
+	if hours == 0 {
+		return "priority", nil
+	}
+	return "priority + FLOOR(TIMESTAMPDIFF(HOUR, created_at, ?) / ?)", []interface{}{now, hours}
+}
+
+// AgedPriority is the priority an item is picked by at now: its priority plus one per full
+// aging step it has waited since createdAt (aging <= 0: the priority as is)
+func AgedPriority(priority int, createdAt, now time.Time, aging time.Duration) int {
+	hours := agingHours(aging)
+	if hours == 0 || now.Before(createdAt) {
+		return priority
+	}
+	waited := int(now.Sub(createdAt) / time.Hour)
+	return priority + waited/hours
+}
@@ -0,0 +1,56 @@
+package queue
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAgedPriority(t *testing.T) {
+	created := time.Date(2026, 10, 16, 0, 0, 0, 0, time.Local)
+	tests := []struct {
+		name     string
+		priority int
+		waited   time.Duration
+		aging    time.Duration
+		want     int
+	}{
+		{"fresh", PriorityLight, 0, 24 * time.Hour, PriorityLight},
+		{"just under a step", PriorityLight, 24*time.Hour - time.Second, 24 * time.Hour, PriorityLight},
+		{"one step", PriorityLight, 24 * time.Hour, 24 * time.Hour, PriorityLight + 1},
+		{"ties a fresh manual item after two steps", PriorityLight, 48 * time.Hour, 24 * time.Hour, PriorityManual},
+		{"three days", PriorityScheduled, 72 * time.Hour, 24 * time.Hour, PriorityScheduled + 3},
+		{"six-hour steps", PriorityLight, 13 * time.Hour, 6 * time.Hour, PriorityLight + 2},
+		{"sub-hour aging rounds up to an hour", PriorityLight, 3 * time.Hour, 10 * time.Minute, PriorityLight + 3},
+		{"aging off", PriorityLight, 100 * time.Hour, 0, PriorityLight},
+		{"negative aging", PriorityLight, 100 * time.Hour, -time.Hour, PriorityLight},
+		{"created in the future", PriorityScheduled, -time.Hour, 24 * time.Hour, PriorityScheduled},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := AgedPriority(tt.priority, created, created.Add(tt.waited), tt.aging); got != tt.want {
+				t.Errorf("AgedPriority = %d (want %d)", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAgedPriorityExpr(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.Local)
+
+	expr, vars := AgedPriorityExpr(now, 0)
+	if expr != "priority" || vars != nil {
+		t.Errorf("aging off: %q %v", expr, vars)
+	}
+
+	expr, vars = AgedPriorityExpr(now, 24*time.Hour)
+	if expr != "priority + FLOOR(TIMESTAMPDIFF(HOUR, created_at, ?) / ?)" {
+		t.Errorf("expr = %q", expr)
+	}
+	if len(vars) != 2 || vars[0] != now || vars[1] != 24 {
+		t.Errorf("vars = %v (want [now 24])", vars)
+	}
+
+	if _, vars := AgedPriorityExpr(now, 30*time.Minute); vars[1] != 1 {
+		t.Errorf("30m aging step = %v hours (want 1)", vars[1])
+	}
+}
@@ -0,0 +1,230 @@
+package queue
+
+import (
+	"fmt"
+	"real-estate-portal/internal/errtext"
+	"real-estate-portal/internal/models"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// DeadStatuses are the statuses the worker gives up on: failed (retries exhausted or waiting)
+// and permanent_fail
+var DeadStatuses = []string{models.QueueStatusFailed, models.QueueStatusPermanentFail}
+
+// MaxDeadList bounds how many dead items ListDead loads
+const MaxDeadList = 1000
+
+// DeadItem is a failed/permanent_fail queue item in the dead-letter review
+type DeadItem struct {
+	ID               int64     `json:"id"`
+	Source           string    `json:"source"`
+	SourcePropertyID string    `json:"source_property_id"`
+	DetailURL        string    `json:"detail_url"`
+	Status           string    `json:"status"`
+	Attempts         int       `json:"attempts"`
+	LastError        string    `json:"last_error"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+// DeadGroup is the dead items sharing an error class (errtext code, e.g. not_found, waf_blocked, timeout)
+type DeadGroup struct {
+	ErrorClass string     `json:"error_class"`
+	Count      int        `json:"count"`
+	Items      []DeadItem `json:"items"`
+}
+
+// DeadReport groups the most recently updated dead items by error class, largest group first
+type DeadReport struct {
+	Total     int64            `json:"total"`     // all dead items, not only the ones loaded
+	ByStatus  map[string]int64 `json:"by_status"` // failed / permanent_fail
+	Loaded    int              `json:"loaded"`
+	Truncated bool             `json:"truncated"` // more dead items exist than were loaded
+	Groups    []DeadGroup      `json:"groups"`
+}
+
+// ErrorClass is the class a dead item is grouped under: its stored last_error_code, or the code
+// classified from last_error for rows written before codes were stored
+func ErrorClass(item models.DetailScrapeQueue) string {
+	if item.LastErrorCode != "" {
+		return item.LastErrorCode
+	}
+	if code := errtext.Classify(item.LastError); code != "" {
+		return code
+	}
+	return errtext.CodeUnknown
+}
+
+// ListDead loads up to limit (<= 0 or above MaxDeadList: MaxDeadList) of the most recently
+// updated failed/permanent_fail items and groups them by error class
+func (s *Service) ListDead(limit int) (*DeadReport, error) {
+	if limit <= 0 || limit > MaxDeadList {
+		limit = MaxDeadList
+	}
+
+	report := &DeadReport{ByStatus: make(map[string]int64, len(DeadStatuses)), Groups: []DeadGroup{}}
+	for _, status := range DeadStatuses {
+		var n int64
+		if err := s.db.Model(&models.DetailScrapeQueue{}).Where("status = ?", status).Count(&n).Error; err != nil {
+			return nil, fmt.Errorf("count %s queue items: %w", status, err)
+		}
+		report.ByStatus[status] = n
+		report.Total += n
+	}
+
+	var rows []models.DetailScrapeQueue
+	if err := s.db.Where("status IN ?", DeadStatuses).
+		Order("updated_at DESC, id DESC").Limit(limit).
+		Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("list dead queue items: %w", err)
+	}
+	report.Loaded = len(rows)
+	report.Truncated = int64(len(rows)) < report.Total
+
+	byClass := make(map[string]*DeadGroup)
+	for _, row := range rows {
+		class := ErrorClass(row)
+		group := byClass[class]
+		if group == nil {
+			group = &DeadGroup{ErrorClass: class}
+			byClass[class] = group
+		}
+		group.Count++
+		group.Items = append(group.Items, DeadItem{
+			ID:               row.ID,
+			Source:           row.Source,
+			SourcePropertyID: row.SourcePropertyID,
+			DetailURL:        row.DetailURL,
+			Status:           row.Status,
+			Attempts:         row.Attempts,
+			LastError:        row.LastError,
+			CreatedAt:        row.CreatedAt,
+			UpdatedAt:        row.UpdatedAt,
+		})
+	}
+	for _, group := range byClass {
+		report.Groups = append(report.Groups, *group)
+	}
+	sort.Slice(report.Groups, func(i, j int) bool {
+		if report.Groups[i].Count != report.Groups[j].Count {
+			return report.Groups[i].Count > report.Groups[j].Count
+		}
+		return report.Groups[i].ErrorClass < report.Groups[j].ErrorClass
+	})
+	return report, nil
+}
+
+// RequeueFilter selects dead items for a bulk requeue; Limit (at most DefaultMaxAffected)
+// bounds how many one call resets
+type RequeueFilter struct {
+	Statuses      []string   `json:"status,omitempty"`         // failed and/or permanent_fail (default: both)
+	ErrorContains string     `json:"error_contains,omitempty"` // substring of last_error, e.g. "WAF"
+	ErrorCode     string     `json:"error_code,omitempty"`     // stored last_error_code, e.g. waf_blocked
+	CreatedBefore *time.Time `json:"created_before,omitempty"`
+	CreatedAfter  *time.Time `json:"created_after,omitempty"`
+	Limit         int        `json:"limit,omitempty"` // default and max DefaultMaxAffected
+}
+
+// Validate fills the default statuses and limit and rejects other statuses and oversized limits
+func (f *RequeueFilter) Validate() error {
+	if len(f.Statuses) == 0 {
+		f.Statuses = DeadStatuses
+	}
+	for _, status := range f.Statuses {
+		if status != models.QueueStatusFailed && status != models.QueueStatusPermanentFail {
+			return fmt.Errorf("%w: status must be failed or permanent_fail, got %q", ErrInvalidFilter, status)
+		}
+	}
+	if f.Limit < 0 || f.Limit > DefaultMaxAffected {
+		return fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidFilter, DefaultMaxAffected)
+	}
+	if f.Limit == 0 {
+		f.Limit = DefaultMaxAffected
+	}
+	return nil
+}
+
+// String describes the filter for logs
+func (f RequeueFilter) String() string {
+	parts := []string{"status=" + strings.Join(f.Statuses, ",")}
+	if f.ErrorContains != "" {
+		parts = append(parts, fmt.Sprintf("error_contains=%q", f.ErrorContains))
+	}
+	if f.ErrorCode != "" {
+		parts = append(parts, "error_code="+f.ErrorCode)
+	}
+	if f.CreatedBefore != nil {
+		parts = append(parts, "created_before="+f.CreatedBefore.Format(time.RFC3339))
+	}
+	if f.CreatedAfter != nil {
+		parts = append(parts, "created_after="+f.CreatedAfter.Format(time.RFC3339))
+	}
+	return strings.Join(parts, " ") + fmt.Sprintf(" limit=%d", f.Limit)
+}
+
+// apply adds the filter conditions to a detail_scrape_queue query. Only the latest dead row of
+// a listing is matched, and only while the listing has no pending/processing row, so the reset
+// cannot collide on uniq_queue_active_key (the subqueries read derived tables: MySQL refuses a
+// subquery on the table an UPDATE writes).
+func (f RequeueFilter) apply(q *gorm.DB) *gorm.DB {
+	q = q.Where("status IN ?", f.Statuses)
+	if f.ErrorContains != "" {
+		q = q.Where("last_error LIKE ?", "%"+escapeLike(f.ErrorContains)+"%")
+	}
+	if f.ErrorCode != "" {
+		q = q.Where("last_error_code = ?", f.ErrorCode)
+	}
+	if f.CreatedBefore != nil {
+		q = q.Where("created_at < ?", *f.CreatedBefore)
+	}
+	if f.CreatedAfter != nil {
+		q = q.Where("created_at > ?", *f.CreatedAfter)
+	}
+	return q.
+		Where("id IN (SELECT id FROM (SELECT MAX(id) AS id FROM detail_scrape_queue WHERE status IN ? GROUP BY source, source_property_id) latest_dead)", DeadStatuses).
+		Where("CONCAT(source, ':', source_property_id) NOT IN (SELECT active_key FROM (SELECT active_key FROM detail_scrape_queue WHERE active_key IS NOT NULL) active)")
+}
+
+// RequeueResult reports a bulk requeue: how many items matched the filter and how many of them
+// this call reset (at most Limit; call again for the rest)
+type RequeueResult struct {
+	Matched  int64 `json:"matched"`
+	Affected int64 `json:"affected"`
+	Limit    int   `json:"limit"`
+}
+
+// Requeue resets up to filter.Limit matching dead items (oldest first) to pending with a fresh
+// retry budget in a single UPDATE, as a failed row revived by Enqueue
+func (s *Service) Requeue(filter RequeueFilter) (*RequeueResult, error) {
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+
+	result := &RequeueResult{Limit: filter.Limit}
+	if err := filter.apply(s.db.Model(&models.DetailScrapeQueue{})).Count(&result.Matched).Error; err != nil {
+		return nil, fmt.Errorf("count dead queue items: %w", err)
+	}
+	if result.Matched == 0 {
+		return result, nil
+	}
+
+	tx := filter.apply(s.db.Model(&models.DetailScrapeQueue{})).
+		Order("id ASC").Limit(filter.Limit).
+		Updates(map[string]interface{}{
+			"status":          models.QueueStatusPending,
+			"attempts":        0,
+			"last_error":      "",
+			"last_error_code": "",
+			"next_retry_at":   nil,
+			"completed_at":    nil,
+		})
+	if tx.Error != nil {
+		return nil, fmt.Errorf("requeue dead queue items: %w", tx.Error)
+	}
+	result.Affected = tx.RowsAffected
+	return result, nil
+}
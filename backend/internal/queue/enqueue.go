@@ -0,0 +1,152 @@
+package queue
+
+import (
+	"errors"
+	"fmt"
+	"real-estate-portal/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Enqueue priorities by origin (higher = processed first)
+const (
+	PriorityList      = 0 // discovered on a list page (POST /api/scrape/list)
+	PriorityScheduled = 1 // daily refresh of a known listing
+	PriorityLight     = 0 // light refresh of a known listing (scraper.light_refresh)
+)
+
+// EnqueueOutcome says how an enqueue request was applied
+type EnqueueOutcome string
+
+const (
+	EnqueueInserted  EnqueueOutcome = "inserted"  // new pending row
+	EnqueueRevived   EnqueueOutcome = "revived"   // failed row reset to pending
+	EnqueueBumped    EnqueueOutcome = "bumped"    // already pending/processing; priority raised (or light row made full)
+	EnqueueUnchanged EnqueueOutcome = "unchanged" // already pending/processing at the same or higher priority
+)
+
+// Enqueue queues a listing for a detail scrape without ever creating a second active row:
+// an existing pending/processing row keeps its place and gets max(old, new) priority, a
+// failed row is reset to pending, and only otherwise a new row is inserted. The insert is
+// an upsert on active_key, so concurrent enqueues of the same listing also fold together.
+func (s *Service) Enqueue(source, sourcePropertyID, detailURL string, priority int) (EnqueueOutcome, error) {
+	return s.EnqueueFrom(source, sourcePropertyID, detailURL, "", priority)
+}
+
+// EnqueueFrom is Enqueue for a URL found on the list page refererURL, which the worker sends
+// as the Referer of the detail request. A revived row takes the new referer; an active row
+// keeps its own. A pending light refresh of the listing becomes a full scrape.
+func (s *Service) EnqueueFrom(source, sourcePropertyID, detailURL, refererURL string, priority int) (EnqueueOutcome, error) {
+	// 1. Already active: raise the priority if needed
+	var active []models.DetailScrapeQueue
+	if err := s.db.Select("id", "priority", "status", "light").
+		Where("active_key = ?", models.QueueActiveKey(source, sourcePropertyID)).
+		Limit(1).Find(&active).Error; err != nil {
+		return "", fmt.Errorf("find active queue row: %w", err)
+	}
+	if len(active) > 0 {
+		upgrade := active[0].Light && active[0].Status == models.QueueStatusPending
+		if active[0].Priority >= priority && !upgrade {
+			return EnqueueUnchanged, nil
+		}
+		updates := map[string]interface{}{
+			"priority": max(active[0].Priority, priority),
+		}
+		if upgrade {
+			updates["light"] = false
+		}
+		if err := s.db.Model(&models.DetailScrapeQueue{}).Where("id = ?", active[0].ID).
+			Updates(updates).Error; err != nil {
+			return "", fmt.Errorf("bump queue priority: %w", err)
+		}
+		return EnqueueBumped, nil
+	}
+
+	// 2. Failed earlier: retry the existing row instead of adding another. If a concurrent
+	// enqueue made the listing active meanwhile, the reset hits active_key; fall through to the
+	// upsert, which then only bumps that row.
+	reset := map[string]interface{}{
+		"status":          models.QueueStatusPending,
+		"priority":        gorm.Expr("GREATEST(priority, ?)", priority),
+		"attempts":        0,
+		"last_error":      "",
+		"last_error_code": "",
+		"next_retry_at":   nil,
+		"light":           false,
+	}
+	if refererURL != "" {
+		reset["referer_url"] = refererURL
+	}
+	revived := s.db.Model(&models.DetailScrapeQueue{}).
+		Where("source = ? AND source_property_id = ? AND status = ?", source, sourcePropertyID, models.QueueStatusFailed).
+		Order("id DESC").Limit(1).
+		Updates(reset)
+	if revived.Error != nil && !errors.Is(revived.Error, gorm.ErrDuplicatedKey) {
+		return "", fmt.Errorf("reset failed queue row: %w", revived.Error)
+	}
+	if revived.Error == nil && revived.RowsAffected > 0 {
+		return EnqueueRevived, nil
+	}
+
+	// 3. New row; a concurrent insert of the same listing hits active_key and only bumps
+	item := models.DetailScrapeQueue{
+		Source:           source,
+		SourcePropertyID: sourcePropertyID,
+		DetailURL:        detailURL,
+		RefererURL:       refererURL,
+		Status:           models.QueueStatusPending,
+		Priority:         priority,
+	}
+	inserted := s.db.Clauses(clause.OnConflict{
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			// The inserted priority is bound rather than read back with MySQL's VALUES()
+			"priority": gorm.Expr("GREATEST(priority, ?)", priority),
+		}),
+	}).Create(&item)
+	if inserted.Error != nil {
+		return "", fmt.Errorf("insert queue row: %w", inserted.Error)
+	}
+	// MySQL reports 1 for an insert, 2 for an update on duplicate key, 0 if nothing changed
+	switch inserted.RowsAffected {
+	case 1:
+		return EnqueueInserted, nil
+	case 2:
+		return EnqueueBumped, nil
+	default:
+		return EnqueueUnchanged, nil
+	}
+}
+
+// EnqueueLight queues a light refresh (meta tags and rent only) of a known listing. Any active
+// row already covers it and is left as is, and failed rows are not revived: a light refresh
+// never replaces a full scrape.
+func (s *Service) EnqueueLight(source, sourcePropertyID, detailURL string) (EnqueueOutcome, error) {
+	var active int64
+	if err := s.db.Model(&models.DetailScrapeQueue{}).
+		Where("active_key = ?", models.QueueActiveKey(source, sourcePropertyID)).
+		Count(&active).Error; err != nil {
+		return "", fmt.Errorf("find active queue row: %w", err)
+	}
+	if active > 0 {
+		return EnqueueUnchanged, nil
+	}
+
+	// A concurrent enqueue of the same listing wins; the light row is simply not added
+	item := models.DetailScrapeQueue{
+		Source:           source,
+		SourcePropertyID: sourcePropertyID,
+		DetailURL:        detailURL,
+		Status:           models.QueueStatusPending,
+		Priority:         PriorityLight,
+		Light:            true,
+	}
+	inserted := s.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&item)
+	if inserted.Error != nil {
+		return "", fmt.Errorf("insert queue row: %w", inserted.Error)
+	}
+	if inserted.RowsAffected == 0 {
+		return EnqueueUnchanged, nil
+	}
+	return EnqueueInserted, nil
+}
@@ -0,0 +1,170 @@
+package queue_test
+
+import (
+	"real-estate-portal/internal/models"
+	"real-estate-portal/internal/queue"
+	"real-estate-portal/internal/sqlitetest"
+	"strings"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+func detailURL(spid string) string {
+	return "https://realestate.yahoo.co.jp/rent/detail/" + spid + "/"
+}
+
+// rowsFor returns every queue row of a listing
+func rowsFor(t *testing.T, db *gorm.DB, spid string) []models.DetailScrapeQueue {
+	t.Helper()
+	var rows []models.DetailScrapeQueue
+	if err := db.Where("source = ? AND source_property_id = ?", "yahoo", spid).Order("id").Find(&rows).Error; err != nil {
+		t.Fatalf("rows %s: %v", spid, err)
+	}
+	return rows
+}
+
+// Enqueueing one listing from several paths leaves one pending row at the highest priority
+func TestEnqueueDedup(t *testing.T) {
+	type step struct {
+		light    bool
+		priority int
+		want     queue.EnqueueOutcome
+	}
+	tests := []struct {
+		name      string
+		seed      *models.DetailScrapeQueue // existing row for the listing
+		steps     []step
+		wantPrio  int
+		wantLight bool
+	}{
+		{
+			name: "list, scheduler, manual, late list hit",
+			steps: []step{
+				{priority: queue.PriorityList, want: queue.EnqueueInserted},
+				{priority: queue.PriorityScheduled, want: queue.EnqueueBumped},
+				{priority: 10, want: queue.EnqueueBumped},
+				{priority: queue.PriorityList, want: queue.EnqueueUnchanged},
+			},
+			wantPrio: 10,
+		},
+		{
+			name: "failed row is revived, keeping its higher priority",
+			seed: &models.DetailScrapeQueue{Status: models.QueueStatusFailed, Priority: 3, Attempts: 2, LastError: "status code 503"},
+			steps: []step{
+				{priority: queue.PriorityScheduled, want: queue.EnqueueRevived},
+				{priority: 5, want: queue.EnqueueBumped},
+			},
+			wantPrio: 5,
+		},
+		{
+			name: "done row gets a new row",
+			seed: &models.DetailScrapeQueue{Status: models.QueueStatusDone, Priority: 1},
+			steps: []step{
+				{priority: queue.PriorityList, want: queue.EnqueueInserted},
+			},
+			wantPrio: queue.PriorityList,
+		},
+		{
+			name: "light refresh then full scrape",
+			steps: []step{
+				{light: true, want: queue.EnqueueInserted},
+				{light: true, want: queue.EnqueueUnchanged},
+				{priority: queue.PriorityList, want: queue.EnqueueBumped},
+			},
+			wantPrio: queue.PriorityList,
+		},
+		{
+			name: "light refresh never replaces a full scrape",
+			steps: []step{
+				{priority: queue.PriorityScheduled, want: queue.EnqueueInserted},
+				{light: true, want: queue.EnqueueUnchanged},
+			},
+			wantPrio: queue.PriorityScheduled,
+		},
+		{
+			name: "pending light row stays light",
+			steps: []step{
+				{light: true, want: queue.EnqueueInserted},
+			},
+			wantPrio:  queue.PriorityLight,
+			wantLight: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := sqlitetest.Open(t)
+			service := queue.NewService(db)
+			const spid = "listing01"
+
+			if tt.seed != nil {
+				seed := *tt.seed
+				seed.Source, seed.SourcePropertyID, seed.DetailURL = "yahoo", spid, detailURL(spid)
+				if err := db.Create(&seed).Error; err != nil {
+					t.Fatalf("seed: %v", err)
+				}
+			}
+			for i, s := range tt.steps {
+				var got queue.EnqueueOutcome
+				var err error
+				if s.light {
+					got, err = service.EnqueueLight("yahoo", spid, detailURL(spid))
+				} else {
+					got, err = service.Enqueue("yahoo", spid, detailURL(spid), s.priority)
+				}
+				if err != nil || got != s.want {
+					t.Errorf("step %d (light=%v priority=%d): %q, %v (want %q)", i, s.light, s.priority, got, err, s.want)
+				}
+			}
+
+			var active []models.DetailScrapeQueue
+			for _, row := range rowsFor(t, db, spid) {
+				if row.Status == models.QueueStatusPending || row.Status == models.QueueStatusProcessing {
+					active = append(active, row)
+				}
+			}
+			if len(active) != 1 {
+				t.Fatalf("%d active rows (want 1): %+v", len(active), active)
+			}
+			if row := active[0]; row.Priority != tt.wantPrio || row.Light != tt.wantLight || row.Attempts != 0 || row.LastError != "" {
+				t.Errorf("active row = priority %d light %v attempts %d error %q (want priority %d light %v, reset)",
+					row.Priority, row.Light, row.Attempts, row.LastError, tt.wantPrio, tt.wantLight)
+			}
+		})
+	}
+}
+
+// A concurrent enqueue that misses the active lookup collides on active_key and folds into
+// the existing row instead of adding a second one
+func TestEnqueueLostLookup(t *testing.T) {
+	db := sqlitetest.Open(t)
+	service := queue.NewService(db)
+	const spid = "listing02"
+
+	if _, err := service.Enqueue("yahoo", spid, detailURL(spid), queue.PriorityList); err != nil {
+		t.Fatalf("first enqueue: %v", err)
+	}
+
+	hide := true
+	if err := db.Callback().Query().After("gorm:query").Register("test:hide_active", func(tx *gorm.DB) {
+		rows, ok := tx.Statement.Dest.(*[]models.DetailScrapeQueue)
+		if ok && hide && strings.Contains(tx.Statement.SQL.String(), "active_key = ?") {
+			hide = false
+			*rows = nil
+			tx.RowsAffected = 0
+		}
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := service.Enqueue("yahoo", spid, detailURL(spid), queue.PriorityScheduled); err != nil {
+		t.Fatalf("racing enqueue: %v", err)
+	}
+	if hide {
+		t.Fatal("the active lookup never ran")
+	}
+	rows := rowsFor(t, db, spid)
+	if len(rows) != 1 || rows[0].Status != models.QueueStatusPending || rows[0].Priority != queue.PriorityScheduled {
+		t.Errorf("rows = %+v (want one pending row at priority %d)", rows, queue.PriorityScheduled)
+	}
+}
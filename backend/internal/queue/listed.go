@@ -0,0 +1,74 @@
+package queue
+
+import (
+	"fmt"
+	"real-estate-portal/internal/models"
+	"time"
+)
+
+// ListedOutcome says how a detail URL found on a list page was handled
+type ListedOutcome string
+
+const (
+	ListedExisting      ListedOutcome = "existing"       // already in properties; last_seen_at refreshed
+	ListedQueued        ListedOutcome = "queued"         // new detail_scrape_queue row
+	ListedRequeued      ListedOutcome = "requeued"       // failed queue row reset to pending
+	ListedAlreadyQueued ListedOutcome = "already_queued" // pending/processing already
+	ListedAlreadyDone   ListedOutcome = "already_done"   // queue row done
+	ListedPermanentFail ListedOutcome = "permanent_fail" // not retried (404 etc.)
+)
+
+// IsNew reports whether the URL was (re)added to the queue
+func (o ListedOutcome) IsNew() bool {
+	return o == ListedQueued || o == ListedRequeued
+}
+
+// EnqueueListed handles a detail URL found on the list page listURL: a known property only
+// gets last_seen_at refreshed (its ID is returned), a listing whose latest queue row is done or
+// permanently failed is left alone, and anything else goes through EnqueueFrom at PriorityList
+// with listURL as the referer
+func (s *Service) EnqueueListed(source, sourcePropertyID, detailURL, listURL string) (ListedOutcome, string, error) {
+	// Existing property: update last_seen_at
+	var ids []string
+	if err := s.db.Model(&models.Property{}).
+		Where("source = ? AND source_property_id = ?", source, sourcePropertyID).
+		Pluck("id", &ids).Error; err != nil {
+		return "", "", fmt.Errorf("find property: %w", err)
+	}
+	if len(ids) > 0 {
+		s.db.Model(&models.Property{}).
+			Where("source = ? AND source_property_id = ?", source, sourcePropertyID).
+			Update("last_seen_at", time.Now())
+		return ListedExisting, ids[0], nil
+	}
+
+	// Done and permanently failed listings are not queued again from list pages
+	var latest []models.DetailScrapeQueue
+	if err := s.db.Select("status").
+		Where("source = ? AND source_property_id = ?", source, sourcePropertyID).
+		Order("id DESC").Limit(1).Find(&latest).Error; err != nil {
+		return "", "", fmt.Errorf("check queue: %w", err)
+	}
+	if len(latest) > 0 {
+		switch latest[0].Status {
+		case models.QueueStatusPermanentFail:
+			return ListedPermanentFail, "", nil
+		case models.QueueStatusDone:
+			return ListedAlreadyDone, "", nil
+		}
+	}
+
+	// Upsert: new row, failed row reset to pending, or the existing pending/processing row
+	outcome, err := s.EnqueueFrom(source, sourcePropertyID, detailURL, listURL, PriorityList)
+	if err != nil {
+		return "", "", err
+	}
+	switch outcome {
+	case EnqueueInserted:
+		return ListedQueued, "", nil
+	case EnqueueRevived:
+		return ListedRequeued, "", nil
+	default:
+		return ListedAlreadyQueued, "", nil
+	}
+}
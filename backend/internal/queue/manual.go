@@ -0,0 +1,119 @@
+package queue
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"real-estate-portal/internal/models"
+	"real-estate-portal/internal/scraper"
+	"strings"
+)
+
+// PriorityManual is the priority of URLs queued by hand (POST /api/queue/enqueue) unless the
+// request sets one
+const PriorityManual = 2
+
+// MaxManualBatch is the most URLs one POST /api/queue/enqueue/batch may carry
+const MaxManualBatch = 100
+
+// ErrInvalidURL is returned (wrapped) for a URL that is not a detail page of a registered source
+var ErrInvalidURL = errors.New("invalid detail URL")
+
+// ManualItem is one URL of a manual enqueue with the queue row that now covers it
+type ManualItem struct {
+	URL     string         `json:"url"`
+	ID      int64          `json:"id"`
+	Outcome EnqueueOutcome `json:"outcome"`
+}
+
+// RejectedURL is a URL a manual enqueue refused, with the reason
+type RejectedURL struct {
+	URL   string `json:"url"`
+	Error string `json:"error"`
+}
+
+// ManualResult reports a manual enqueue: new (or revived) rows, URLs skipped because the listing
+// is already pending/processing (with that row's ID), and URLs that failed validation
+type ManualResult struct {
+	Created    []ManualItem  `json:"created"`
+	Duplicates []ManualItem  `json:"duplicates"`
+	Invalid    []RejectedURL `json:"invalid"`
+}
+
+// CreatedIDs returns the IDs of the rows the enqueue created or revived
+func (r *ManualResult) CreatedIDs() []int64 {
+	ids := make([]int64, 0, len(r.Created))
+	for _, item := range r.Created {
+		ids = append(ids, item.ID)
+	}
+	return ids
+}
+
+// ResolveDetailURL checks rawURL is an http(s) detail page of a source in sources and returns
+// the source name, its property ID and the URL without query, fragment and trailing slash (as
+// list-page enqueues store it)
+func ResolveDetailURL(sources *scraper.Registry, rawURL string) (source, sourcePropertyID, detailURL string, err error) {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", "", "", fmt.Errorf("%w: %q is not an http(s) URL", ErrInvalidURL, rawURL)
+	}
+	u.RawQuery = ""
+	u.Fragment = ""
+	detailURL = strings.TrimSuffix(u.String(), "/")
+
+	src, err := sources.ForURL(detailURL)
+	if err != nil {
+		return "", "", "", fmt.Errorf("%w: %v", ErrInvalidURL, err)
+	}
+	sourcePropertyID, err = src.SourcePropertyID(detailURL)
+	if err != nil {
+		return "", "", "", fmt.Errorf("%w: %v", ErrInvalidURL, err)
+	}
+	return src.Name(), sourcePropertyID, detailURL, nil
+}
+
+// EnqueueURLs queues detail URLs given by hand at priority. Each URL is validated against
+// sources; a listing already pending/processing (in the queue or earlier in urls) is reported as
+// a duplicate and only gets its priority raised (see Enqueue). A database error stops the batch
+// and is returned with the result so far.
+func (s *Service) EnqueueURLs(sources *scraper.Registry, urls []string, priority int) (*ManualResult, error) {
+	result := &ManualResult{Created: []ManualItem{}, Duplicates: []ManualItem{}, Invalid: []RejectedURL{}}
+	for _, rawURL := range urls {
+		source, sourcePropertyID, detailURL, err := ResolveDetailURL(sources, rawURL)
+		if err != nil {
+			result.Invalid = append(result.Invalid, RejectedURL{URL: rawURL, Error: err.Error()})
+			continue
+		}
+
+		outcome, err := s.Enqueue(source, sourcePropertyID, detailURL, priority)
+		if err != nil {
+			return result, fmt.Errorf("enqueue %s: %w", rawURL, err)
+		}
+		id, err := s.activeID(source, sourcePropertyID)
+		if err != nil {
+			return result, fmt.Errorf("enqueue %s: %w", rawURL, err)
+		}
+
+		item := ManualItem{URL: rawURL, ID: id, Outcome: outcome}
+		if outcome == EnqueueInserted || outcome == EnqueueRevived {
+			result.Created = append(result.Created, item)
+		} else {
+			result.Duplicates = append(result.Duplicates, item)
+		}
+	}
+	return result, nil
+}
+
+// activeID returns the ID of the listing's pending/processing row (0 if there is none)
+func (s *Service) activeID(source, sourcePropertyID string) (int64, error) {
+	var active []models.DetailScrapeQueue
+	if err := s.db.Select("id").
+		Where("active_key = ?", models.QueueActiveKey(source, sourcePropertyID)).
+		Limit(1).Find(&active).Error; err != nil {
+		return 0, fmt.Errorf("find active queue row: %w", err)
+	}
+	if len(active) == 0 {
+		return 0, nil
+	}
+	return active[0].ID, nil
+}
@@ -0,0 +1,104 @@
+package queue
+
+import (
+	"fmt"
+	"real-estate-portal/internal/models"
+	"real-estate-portal/internal/ratelimit"
+	"time"
+)
+
+// RefreshStatus tells when a listing's detail page will be fetched again
+type RefreshStatus struct {
+	Queued      bool       `json:"queued"` // pending, processing, or failed with a retry scheduled
+	Status      string     `json:"status,omitempty"`
+	EstimatedAt *Estimate  `json:"estimated_at,omitempty"`
+	Attempts    int        `json:"attempts,omitempty"`
+	NextRetryAt *time.Time `json:"next_retry_at,omitempty"`
+	ErrorCode   string     `json:"last_error_code,omitempty"`
+	Position    int        `json:"position,omitempty"` // 1 = next to be fetched (0 while processing)
+	PerHour     int        `json:"per_hour,omitempty"` // detail fetches per hour used for the estimate
+}
+
+// Estimate is the window in which the fetch is expected to start
+type Estimate struct {
+	Earliest time.Time `json:"earliest"`
+	Latest   time.Time `json:"latest"`
+}
+
+// Public returns the reduced view shown to anonymous callers
+func (r *RefreshStatus) Public() *RefreshStatus {
+	return &RefreshStatus{Queued: r.Queued, Status: r.Status, EstimatedAt: r.EstimatedAt}
+}
+
+// RefreshStatus looks up the listing's queue row (the active one, else the latest) and
+// estimates when it will be fetched from its position (in the worker's aged priority order,
+// see SetPriorityAging) and the detail limiter's state
+func (s *Service) RefreshStatus(source, sourcePropertyID string, limiter ratelimit.DetailLimiterStatus) (*RefreshStatus, error) {
+	var rows []models.DetailScrapeQueue
+	if err := s.db.Where("source = ? AND source_property_id = ?", source, sourcePropertyID).
+		Order("active_key IS NULL, id DESC").Limit(1).Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("find queue row: %w", err)
+	}
+	if len(rows) == 0 {
+		return &RefreshStatus{}, nil
+	}
+	item := rows[0]
+
+	status := &RefreshStatus{
+		Status:      item.Status,
+		Attempts:    item.Attempts,
+		NextRetryAt: item.NextRetryAt,
+		ErrorCode:   item.LastErrorCode,
+		PerHour:     limiter.MaxPerHour,
+	}
+
+	now := time.Now()
+	var notBefore time.Time
+	switch item.Status {
+	case models.QueueStatusProcessing:
+		status.Queued = true
+	case models.QueueStatusPending:
+		status.Queued = true
+		// Ahead: everything in flight plus pending rows the worker orders first (aged priority,
+		// then oldest)
+		expr, vars := AgedPriorityExpr(now, s.priorityAging)
+		aged := AgedPriority(item.Priority, item.CreatedAt, now, s.priorityAging)
+		args := []interface{}{models.QueueStatusProcessing, models.QueueStatusPending}
+		args = append(append(args, vars...), aged)
+		args = append(append(args, vars...), aged, item.CreatedAt, item.CreatedAt, item.ID)
+		var ahead int64
+		if err := s.db.Model(&models.DetailScrapeQueue{}).
+			Where("status = ? OR (status = ? AND ("+expr+" > ? OR ("+expr+" = ? AND (created_at < ? OR (created_at = ? AND id < ?)))))", args...).
+			Count(&ahead).Error; err != nil {
+			return nil, fmt.Errorf("count queue position: %w", err)
+		}
+		status.Position = int(ahead) + 1
+	case models.QueueStatusFailed:
+		if item.NextRetryAt == nil {
+			return status, nil
+		}
+		status.Queued = true
+		// Retries are only picked up once nothing is pending
+		var ahead int64
+		if err := s.db.Model(&models.DetailScrapeQueue{}).
+			Where("status IN ?", []string{models.QueueStatusPending, models.QueueStatusProcessing}).
+			Count(&ahead).Error; err != nil {
+			return nil, fmt.Errorf("count queue position: %w", err)
+		}
+		status.Position = int(ahead) + 1
+		notBefore = *item.NextRetryAt
+	default:
+		return status, nil
+	}
+
+	cooldown := time.Duration(limiter.PreventiveCooldown.RemainingSec) * time.Second
+	earliest, latest := ratelimit.EstimateQueueWait(status.Position, limiter.MaxPerHour, cooldown)
+	estimate := &Estimate{Earliest: now.Add(earliest), Latest: now.Add(latest)}
+	if estimate.Earliest.Before(notBefore) {
+		shift := notBefore.Sub(estimate.Earliest)
+		estimate.Earliest = estimate.Earliest.Add(shift)
+		estimate.Latest = estimate.Latest.Add(shift)
+	}
+	status.EstimatedAt = estimate
+	return status, nil
+}
@@ -0,0 +1,173 @@
+package queue_test
+
+import (
+	"real-estate-portal/internal/models"
+	"real-estate-portal/internal/queue"
+	"real-estate-portal/internal/ratelimit"
+	"real-estate-portal/internal/sqlitetest"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestRefreshStatus(t *testing.T) {
+	retryAt := time.Now().Add(2 * time.Hour).Round(0) // wall clock only, as it comes back from the database
+	hourAgo := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name   string
+		queue  []models.DetailScrapeQueue // the listing under test is "target"
+		queued bool
+		// position and the estimate window from now (5 per hour: 12 minutes per slot)
+		position      int
+		earliest      time.Duration
+		latest        time.Duration
+		estimateAfter *time.Time
+	}{
+		{
+			name:  "not queued",
+			queue: nil,
+		},
+		{
+			name: "third in line",
+			queue: []models.DetailScrapeQueue{
+				{SourcePropertyID: "ahead1", Status: models.QueueStatusPending, Priority: 2, CreatedAt: time.Now()},
+				{SourcePropertyID: "ahead2", Status: models.QueueStatusProcessing, Priority: 0, CreatedAt: time.Now()},
+				{SourcePropertyID: "target", Status: models.QueueStatusPending, Priority: 1, CreatedAt: hourAgo},
+				{SourcePropertyID: "behind1", Status: models.QueueStatusPending, Priority: 1, CreatedAt: time.Now()},
+				{SourcePropertyID: "behind2", Status: models.QueueStatusPending, Priority: 0, CreatedAt: hourAgo.Add(-time.Hour)},
+				{SourcePropertyID: "finished", Status: models.QueueStatusDone, Priority: 9, CreatedAt: hourAgo},
+			},
+			queued: true, position: 3, earliest: 24 * time.Minute, latest: 36 * time.Minute,
+		},
+		{
+			name: "older row at the same priority goes first",
+			queue: []models.DetailScrapeQueue{
+				{SourcePropertyID: "target", Status: models.QueueStatusPending, Priority: 1, CreatedAt: hourAgo},
+				{SourcePropertyID: "older", Status: models.QueueStatusPending, Priority: 1, CreatedAt: hourAgo.Add(-time.Minute)},
+			},
+			queued: true, position: 2, earliest: 12 * time.Minute, latest: 24 * time.Minute,
+		},
+		{
+			name: "processing",
+			queue: []models.DetailScrapeQueue{
+				{SourcePropertyID: "target", Status: models.QueueStatusProcessing, Priority: 1, CreatedAt: hourAgo},
+			},
+			queued: true, position: 0,
+		},
+		{
+			name: "failed with a retry waits for the pending rows and the retry time",
+			queue: []models.DetailScrapeQueue{
+				{SourcePropertyID: "target", Status: models.QueueStatusFailed, Attempts: 2, NextRetryAt: &retryAt, CreatedAt: hourAgo},
+				{SourcePropertyID: "other", Status: models.QueueStatusPending, CreatedAt: hourAgo},
+			},
+			queued: true, position: 2, estimateAfter: &retryAt,
+		},
+		{
+			name: "failed for good",
+			queue: []models.DetailScrapeQueue{
+				{SourcePropertyID: "target", Status: models.QueueStatusPermanentFail, Attempts: 3, CreatedAt: hourAgo},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := sqlitetest.Open(t)
+			for i := range tt.queue {
+				row := tt.queue[i]
+				row.Source, row.DetailURL = "yahoo", detailURL(row.SourcePropertyID)
+				if err := db.Create(&row).Error; err != nil {
+					t.Fatalf("seed %s: %v", row.SourcePropertyID, err)
+				}
+			}
+			service := queue.NewService(db)
+			service.SetPriorityAging(0) // SQLite has no TIMESTAMPDIFF; see TestRefreshStatusAgedOrder
+
+			start := time.Now()
+			status, err := service.RefreshStatus("yahoo", "target", ratelimit.NewDetailLimiter(5).Status())
+			if err != nil {
+				t.Fatalf("RefreshStatus: %v", err)
+			}
+			if status.Queued != tt.queued || status.Position != tt.position {
+				t.Fatalf("status = %+v (want queued %v at position %d)", status, tt.queued, tt.position)
+			}
+			if !tt.queued {
+				if status.EstimatedAt != nil {
+					t.Errorf("estimate for an unqueued listing: %+v", status.EstimatedAt)
+				}
+				return
+			}
+			if status.EstimatedAt == nil {
+				t.Fatal("no estimate")
+			}
+			if tt.estimateAfter != nil {
+				if status.EstimatedAt.Earliest.Before(*tt.estimateAfter) {
+					t.Errorf("earliest %v is before the retry at %v", status.EstimatedAt.Earliest, *tt.estimateAfter)
+				}
+			} else if tt.latest > 0 {
+				earliest, latest := status.EstimatedAt.Earliest.Sub(start), status.EstimatedAt.Latest.Sub(start)
+				if earliest < tt.earliest || earliest > tt.earliest+time.Second || latest < tt.latest || latest > tt.latest+time.Second {
+					t.Errorf("estimate %v..%v from now (want %v..%v)", earliest, latest, tt.earliest, tt.latest)
+				}
+			}
+
+			public := status.Public()
+			if public.Position != 0 || public.Attempts != 0 || public.PerHour != 0 || public.EstimatedAt != status.EstimatedAt {
+				t.Errorf("public view leaks queue details: %+v", public)
+			}
+		})
+	}
+}
+
+// With aging on, the position counts rows in the worker's aged order. SQLite can't evaluate
+// TIMESTAMPDIFF, so the query is checked as MySQL would receive it.
+func TestRefreshStatusAgedOrder(t *testing.T) {
+	db, err := gorm.Open(mysql.New(mysql.Config{DSN: "test:test@tcp(127.0.0.1:1)/test?parseTime=true", SkipInitializeWithVersion: true}), &gorm.Config{
+		DryRun:               true,
+		DisableAutomaticPing: true,
+		Logger:               logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A three-day-old light item ranks at priority 3 with the default 24h aging
+	item := models.DetailScrapeQueue{ID: 43, Source: "yahoo", SourcePropertyID: "aged", Status: models.QueueStatusPending,
+		Priority: queue.PriorityLight, CreatedAt: time.Now().Add(-72*time.Hour - time.Minute)}
+	var positionSQL string
+	if err := db.Callback().Query().After("gorm:query").Register("test:queue_position", func(tx *gorm.DB) {
+		switch dest := tx.Statement.Dest.(type) {
+		case *[]models.DetailScrapeQueue:
+			*dest = []models.DetailScrapeQueue{item}
+			tx.RowsAffected = 1
+		case *int64:
+			positionSQL = tx.Dialector.Explain(tx.Statement.SQL.String(), tx.Statement.Vars...)
+			tx.RowsAffected = 1
+		}
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	service := queue.NewService(db)
+	for _, tt := range []struct {
+		aging time.Duration
+		want  []string
+	}{
+		{24 * time.Hour, []string{"(priority + FLOOR(TIMESTAMPDIFF(HOUR, created_at, '", "/ 24) > 3 OR"}},
+		{0, []string{"(priority > 0 OR (priority = 0 AND"}},
+	} {
+		service.SetPriorityAging(tt.aging)
+		if _, err := service.RefreshStatus(item.Source, item.SourcePropertyID, ratelimit.NewDetailLimiter(5).Status()); err != nil {
+			t.Fatalf("aging %v: %v", tt.aging, err)
+		}
+		for _, want := range tt.want {
+			if !strings.Contains(positionSQL, want) {
+				t.Errorf("aging %v: position SQL lacks %q: %s", tt.aging, want, positionSQL)
+			}
+		}
+	}
+}
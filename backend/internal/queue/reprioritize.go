@@ -0,0 +1,152 @@
+// Package queue provides bulk admin operations on the detail_scrape_queue.
+package queue
+
+import (
+	"errors"
+	"fmt"
+	"real-estate-portal/internal/config"
+	"real-estate-portal/internal/models"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// DefaultMaxAffected is the number of rows a reprioritize may touch without force
+const DefaultMaxAffected = 1000
+
+// ErrInvalidFilter is returned for unsupported statuses or a filter that would match the whole queue
+var ErrInvalidFilter = errors.New("invalid queue filter")
+
+// CapExceededError is returned when the filter matches more rows than allowed without force
+type CapExceededError struct {
+	Matched int64
+	Max     int64
+}
+
+func (e *CapExceededError) Error() string {
+	return fmt.Sprintf("filter matches %d queue items, above the limit of %d (set force=true to proceed)", e.Matched, e.Max)
+}
+
+// Filter selects queue items for a bulk operation. Only pending and failed items are
+// ever matched: processing items are owned by a worker and done items have no use for a priority.
+type Filter struct {
+	Source        string     `json:"source,omitempty"`
+	Statuses      []string   `json:"status,omitempty"`     // pending and/or failed (default: both)
+	Area          string     `json:"area,omitempty"`       // substring of the property's address, e.g. 渋谷区
+	URLPrefix     string     `json:"url_prefix,omitempty"` // detail_url prefix
+	CreatedBefore *time.Time `json:"created_before,omitempty"`
+}
+
+// Validate fills the default statuses and rejects unsupported or empty filters
+func (f *Filter) Validate() error {
+	if len(f.Statuses) == 0 {
+		f.Statuses = []string{models.QueueStatusPending, models.QueueStatusFailed}
+	}
+	for _, status := range f.Statuses {
+		if status != models.QueueStatusPending && status != models.QueueStatusFailed {
+			return fmt.Errorf("%w: status must be pending or failed, got %q", ErrInvalidFilter, status)
+		}
+	}
+	if f.Source == "" && strings.TrimSpace(f.Area) == "" && f.URLPrefix == "" && f.CreatedBefore == nil {
+		return fmt.Errorf("%w: at least one of source, area, url_prefix or created_before is required", ErrInvalidFilter)
+	}
+	return nil
+}
+
+// String describes the filter for logs
+func (f Filter) String() string {
+	parts := []string{"status=" + strings.Join(f.Statuses, ",")}
+	if f.Source != "" {
+		parts = append(parts, "source="+f.Source)
+	}
+	if area := strings.TrimSpace(f.Area); area != "" {
+		parts = append(parts, "area="+area)
+	}
+	if f.URLPrefix != "" {
+		parts = append(parts, "url_prefix="+f.URLPrefix)
+	}
+	if f.CreatedBefore != nil {
+		parts = append(parts, "created_before="+f.CreatedBefore.Format(time.RFC3339))
+	}
+	return strings.Join(parts, " ")
+}
+
+// apply adds the filter conditions to a detail_scrape_queue query
+func (f Filter) apply(q *gorm.DB) *gorm.DB {
+	q = q.Where("status IN ?", f.Statuses)
+	if f.Source != "" {
+		q = q.Where("source = ?", f.Source)
+	}
+	if f.URLPrefix != "" {
+		q = q.Where("detail_url LIKE ?", escapeLike(f.URLPrefix)+"%")
+	}
+	if area := strings.TrimSpace(f.Area); area != "" {
+		// Queue rows carry no address; only re-scrapes of known properties can match an area
+		q = q.Where("EXISTS (SELECT 1 FROM properties p WHERE p.source = detail_scrape_queue.source AND p.source_property_id = detail_scrape_queue.source_property_id AND p.address LIKE ?)",
+			"%"+escapeLike(area)+"%")
+	}
+	if f.CreatedBefore != nil {
+		q = q.Where("created_at < ?", *f.CreatedBefore)
+	}
+	return q
+}
+
+// escapeLike escapes LIKE wildcards in user input
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+// ReprioritizeResult reports what a reprioritize matched and changed
+type ReprioritizeResult struct {
+	Matched  int64 `json:"matched"`
+	Affected int64 `json:"affected"`
+	Priority int   `json:"priority"`
+}
+
+// Service runs bulk operations on the queue
+type Service struct {
+	db            *gorm.DB
+	priorityAging time.Duration // the worker's queue_worker.priority_aging_hours, for RefreshStatus
+}
+
+// NewService creates a new queue service
+func NewService(db *gorm.DB) *Service {
+	return &Service{db: db, priorityAging: config.DefaultPriorityAging}
+}
+
+// SetPriorityAging sets the priority aging the worker picks items with (0 = strict priority
+// order), so RefreshStatus counts the queue position in the same order
+func (s *Service) SetPriorityAging(d time.Duration) {
+	s.priorityAging = d
+}
+
+// Reprioritize sets priority on every queue item matching the filter in a single UPDATE.
+// When more than maxAffected rows match and force is false nothing is changed and a
+// *CapExceededError is returned. maxAffected <= 0 uses DefaultMaxAffected.
+func (s *Service) Reprioritize(filter Filter, priority int, maxAffected int64, force bool) (*ReprioritizeResult, error) {
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+	if maxAffected <= 0 {
+		maxAffected = DefaultMaxAffected
+	}
+
+	result := &ReprioritizeResult{Priority: priority}
+	if err := filter.apply(s.db.Model(&models.DetailScrapeQueue{})).Count(&result.Matched).Error; err != nil {
+		return nil, fmt.Errorf("count queue items: %w", err)
+	}
+	if result.Matched > maxAffected && !force {
+		return nil, &CapExceededError{Matched: result.Matched, Max: maxAffected}
+	}
+	if result.Matched == 0 {
+		return result, nil
+	}
+
+	tx := filter.apply(s.db.Model(&models.DetailScrapeQueue{})).Update("priority", priority)
+	if tx.Error != nil {
+		return nil, fmt.Errorf("update queue priority: %w", tx.Error)
+	}
+	result.Affected = tx.RowsAffected
+	return result, nil
+}
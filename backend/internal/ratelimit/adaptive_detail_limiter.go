@@ -1,6 +1,7 @@
 package ratelimit
 
 import (
+	"context"
 	"log"
 	"math"
 	"sync"
@@ -46,9 +47,13 @@ type AdaptiveDetailLimiter struct {
 	slowUntil       time.Time
 	currentCapPerHr int
 	nextRampAt      time.Time
+	lastMode        string // last mode logged by logTransitionLocked
 
 	// pacing: enforce minimum interval on top (prevents mid-hour limiter switch loophole)
 	lastAcquireAt time.Time
+
+	// preventive cooldown: off unless enabled explicitly (adaptive pacing already slows down)
+	cooldown cooldownPacer
 }
 
 func NewAdaptiveDetailLimiter(base DetailRateConfig, ada AdaptiveConfig) *AdaptiveDetailLimiter {
@@ -75,26 +80,49 @@ func NewAdaptiveDetailLimiter(base DetailRateConfig, ada AdaptiveConfig) *Adapti
 		ada.RampMinInterval = 30 * time.Minute
 	}
 
-	return &AdaptiveDetailLimiter{
+	l := &AdaptiveDetailLimiter{
 		base:     base,
 		ada:      ada,
 		limiters: make(map[int]*DetailLimiter),
 		results:  make([]bool, ada.Window),
 	}
+	l.SetPreventiveCooldown(DefaultPreventiveCooldown)
+	return l
+}
+
+// SetPreventiveCooldown applies the preventive cooldown policy. Unless it was enabled
+// explicitly in configuration it stays off here to avoid double-throttling.
+func (l *AdaptiveDetailLimiter) SetPreventiveCooldown(p PreventiveCooldown) {
+	if !p.Explicit {
+		p.Enabled = false
+	}
+	l.cooldown.setPolicy(p)
+}
+
+// CooldownRemaining returns how long the preventive cooldown still has to run (0 if none)
+func (l *AdaptiveDetailLimiter) CooldownRemaining() time.Duration {
+	return l.cooldown.remaining()
 }
 
 // Acquire keeps the same signature as DetailLimiter.Acquire(caller)
 func (l *AdaptiveDetailLimiter) Acquire(caller string) {
+	_ = l.AcquireContext(context.Background(), caller)
+}
+
+// AcquireContext keeps the same signature as DetailLimiter.AcquireContext(ctx, caller)
+func (l *AdaptiveDetailLimiter) AcquireContext(ctx context.Context, caller string) error {
 	perHr, failRate, slow, capPerHr, sleep := l.prepare(caller)
 
 	// 1) pacing layer (global) - prevents exceeding when perHr changes mid-hour
-	if sleep > 0 {
-		time.Sleep(sleep)
+	if err := SleepContext(ctx, sleep); err != nil {
+		return err
 	}
 
 	// 2) existing limiter as a backstop (keeps existing behavior/logs)
 	lim := l.getOrCreateLimiter(perHr)
-	lim.Acquire(caller)
+	if err := lim.AcquireContext(ctx, caller); err != nil {
+		return err
+	}
 
 	// mark lastAcquireAt after limiter passes
 	l.mu.Lock()
@@ -104,10 +132,13 @@ func (l *AdaptiveDetailLimiter) Acquire(caller string) {
 	// optional: adaptive debug log (keep prefix compatible)
 	log.Printf("[DetailLimiter] caller=%s mode=adaptive perHr=%d failRate=%.2f slow=%t cap=%d",
 		caller, perHr, failRate, slow, capPerHr)
+	return nil
 }
 
 // Observe should be called once per detail attempt (success=true/false)
 func (l *AdaptiveDetailLimiter) Observe(success bool) {
+	l.cooldown.record(success)
+
 	l.mu.Lock()
 	defer l.mu.Unlock()
 
@@ -121,36 +152,86 @@ func (l *AdaptiveDetailLimiter) Observe(success bool) {
 
 	failRate := l.failureRateLocked()
 	now := time.Now()
+	defer l.logTransitionLocked(now, failRate)
 
-	// enter slow mode
+	// enter slow mode (failures while slow extend it)
 	if failRate >= l.ada.SlowThreshold {
 		l.slowUntil = now.Add(l.ada.Cooldown)
 		l.currentCapPerHr = l.ada.SlowPerHour
 		l.nextRampAt = l.slowUntil.Add(l.ada.RampMinInterval)
-		log.Printf("[DetailLimiter] ⚠️  Entering slow mode: failRate=%.2f threshold=%.2f cooldown=%v",
-			failRate, l.ada.SlowThreshold, l.ada.Cooldown)
 		return
 	}
 
-	// during cooldown do nothing
-	if now.Before(l.slowUntil) {
+	// during cooldown do nothing; at the base rate there is nothing to ramp
+	if now.Before(l.slowUntil) || l.currentCapPerHr <= 0 {
 		return
 	}
 
-	// ramp-up only when stable enough
-	if failRate <= l.ada.RecoverThreshold {
-		if now.After(l.nextRampAt) {
-			// if cap not set, start from slow cap
-			if l.currentCapPerHr <= 0 {
-				l.currentCapPerHr = l.ada.SlowPerHour
-			}
-			oldCap := l.currentCapPerHr
-			l.currentCapPerHr += l.ada.RampStep
-			l.nextRampAt = now.Add(l.ada.RampMinInterval)
-			log.Printf("[DetailLimiter] ✅ Ramping up: %d -> %d/hr (failRate=%.2f)",
-				oldCap, l.currentCapPerHr, failRate)
+	// ramp-up only when stable enough, at most once per RampMinInterval
+	if failRate <= l.ada.RecoverThreshold && !now.Before(l.nextRampAt) {
+		oldCap := l.currentCapPerHr
+		l.currentCapPerHr += l.ada.RampStep
+		l.nextRampAt = now.Add(l.ada.RampMinInterval)
+		if base := l.basePerHourLocked(now); l.currentCapPerHr >= base {
+			l.currentCapPerHr = 0 // back to the time-of-day rate (logged as a transition)
+			return
 		}
+		log.Printf("[DetailLimiter] ✅ Ramping up: %d -> %d/hr (failRate=%.2f)",
+			oldCap, l.currentCapPerHr, failRate)
+	}
+}
+
+// Adaptive limiter modes
+const (
+	AdaptiveModeNormal  = "normal"  // the time-of-day rate
+	AdaptiveModeSlow    = "slow"    // too many failures: SlowPerHour until the cooldown ends
+	AdaptiveModeRamping = "ramping" // cooldown over: capped, raised by RampStep per RampMinInterval
+)
+
+// modeLocked returns the limiter's mode at now (l.mu held)
+func (l *AdaptiveDetailLimiter) modeLocked(now time.Time) string {
+	switch {
+	case now.Before(l.slowUntil):
+		return AdaptiveModeSlow
+	case l.currentCapPerHr > 0:
+		return AdaptiveModeRamping
+	}
+	return AdaptiveModeNormal
+}
+
+// logTransitionLocked logs a change of mode since the last one logged (l.mu held)
+func (l *AdaptiveDetailLimiter) logTransitionLocked(now time.Time, failRate float64) {
+	mode := l.modeLocked(now)
+	if l.lastMode == "" {
+		l.lastMode = AdaptiveModeNormal
 	}
+	if mode == l.lastMode {
+		return
+	}
+	switch mode {
+	case AdaptiveModeSlow:
+		log.Printf("[DetailLimiter] ⚠️  Entering slow mode: failRate=%.2f threshold=%.2f cap=%d/hr cooldown=%v",
+			failRate, l.ada.SlowThreshold, l.ada.SlowPerHour, l.ada.Cooldown)
+	case AdaptiveModeRamping:
+		log.Printf("[DetailLimiter] Slow mode over: ramping up from %d/hr (failRate=%.2f, next ramp %s)",
+			l.currentCapPerHr, failRate, l.nextRampAt.Format(time.RFC3339))
+	default:
+		log.Printf("[DetailLimiter] ✅ Back to the base rate %d/hr (failRate=%.2f)", l.basePerHourLocked(now), failRate)
+	}
+	l.lastMode = mode
+}
+
+// effectiveCapLocked returns the per-hour budget at now: the time-of-day rate, capped in slow
+// mode and while ramping (l.mu held)
+func (l *AdaptiveDetailLimiter) effectiveCapLocked(now time.Time) int {
+	base := l.basePerHourLocked(now)
+	switch {
+	case now.Before(l.slowUntil):
+		return minInt(base, l.ada.SlowPerHour)
+	case l.currentCapPerHr > 0:
+		return minInt(base, l.currentCapPerHr)
+	}
+	return base
 }
 
 func (l *AdaptiveDetailLimiter) prepare(caller string) (perHr int, failRate float64, slow bool, capPerHr int, sleep time.Duration) {
@@ -158,21 +239,12 @@ func (l *AdaptiveDetailLimiter) prepare(caller string) (perHr int, failRate floa
 	defer l.mu.Unlock()
 
 	now := time.Now()
-
-	base := l.basePerHourLocked(now)
 	failRate = l.failureRateLocked()
 
-	// state & effective cap
+	// state & effective cap (slow mode ending between two attempts is logged here)
+	l.logTransitionLocked(now, failRate)
 	slow = now.Before(l.slowUntil)
-	capPerHr = 0
-
-	if slow {
-		capPerHr = minInt(base, l.ada.SlowPerHour)
-	} else if l.currentCapPerHr > 0 {
-		capPerHr = minInt(base, l.currentCapPerHr)
-	} else {
-		capPerHr = base
-	}
+	capPerHr = l.effectiveCapLocked(now)
 
 	// clamp
 	perHr = clampInt(capPerHr, 1, 60) // 1〜60/h（上限は安全側に適当）
@@ -190,6 +262,61 @@ func (l *AdaptiveDetailLimiter) prepare(caller string) (perHr int, failRate floa
 	return
 }
 
+// AdaptiveStatus is the adaptive limiter's state for status endpoints
+type AdaptiveStatus struct {
+	Mode               string         `json:"mode"`          // normal / slow / ramping
+	PerHour            int            `json:"per_hour"`      // budget right now
+	BasePerHour        int            `json:"base_per_hour"` // the time-of-day rate
+	FailureRate        float64        `json:"failure_rate"`
+	Observed           int            `json:"observed"` // attempts in the failure-rate window
+	SlowUntil          *time.Time     `json:"slow_until,omitempty"`
+	NextRampAt         *time.Time     `json:"next_ramp_at,omitempty"`
+	PreventiveCooldown CooldownStatus `json:"preventive_cooldown"`
+}
+
+// Status returns the mode, budget and failure rate
+func (l *AdaptiveDetailLimiter) Status() AdaptiveStatus {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	status := AdaptiveStatus{
+		Mode:               l.modeLocked(now),
+		PerHour:            clampInt(l.effectiveCapLocked(now), 1, 60),
+		BasePerHour:        l.basePerHourLocked(now),
+		FailureRate:        l.failureRateLocked(),
+		Observed:           l.idx,
+		PreventiveCooldown: l.cooldown.status(),
+	}
+	if l.filled {
+		status.Observed = len(l.results)
+	}
+	switch status.Mode {
+	case AdaptiveModeSlow:
+		until := l.slowUntil
+		status.SlowUntil = &until
+		fallthrough
+	case AdaptiveModeRamping:
+		next := l.nextRampAt
+		status.NextRampAt = &next
+	}
+	return status
+}
+
+// Exhausted reports whether an acquire now would wait: the pacing interval at the current
+// rate has not passed since the last one, or that rate's hourly window is used up
+func (l *AdaptiveDetailLimiter) Exhausted() bool {
+	l.mu.Lock()
+	now := time.Now()
+	perHr := clampInt(l.effectiveCapLocked(now), 1, 60)
+	interval := time.Duration(math.Round(float64(time.Hour) / float64(perHr)))
+	paced := !l.lastAcquireAt.IsZero() && now.Before(l.lastAcquireAt.Add(interval))
+	lim := l.limiters[perHr]
+	l.mu.Unlock()
+
+	return paced || lim != nil && lim.Exhausted()
+}
+
 func (l *AdaptiveDetailLimiter) getOrCreateLimiter(perHr int) *DetailLimiter {
 	l.mu.Lock()
 	defer l.mu.Unlock()
@@ -0,0 +1,93 @@
+package ratelimit
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+// ConcurrencyLimiter bounds how many callers hold a slot at the same time
+// (e.g. goquery parsing, where each in-flight document holds a full DOM)
+type ConcurrencyLimiter struct {
+	slots     chan struct{}
+	current   atomic.Int64
+	waiting   atomic.Int64
+	highWater atomic.Int64
+}
+
+// ConcurrencyStatus is the limiter's current usage
+type ConcurrencyStatus struct {
+	Current   int `json:"current"`
+	Max       int `json:"max"`
+	Waiting   int `json:"waiting"`
+	HighWater int `json:"high_water"` // highest concurrent holders since start (or ResetHighWater)
+}
+
+// NewConcurrencyLimiter creates a limiter allowing max concurrent holders (at least 1)
+func NewConcurrencyLimiter(max int) *ConcurrencyLimiter {
+	if max < 1 {
+		max = 1
+	}
+	return &ConcurrencyLimiter{slots: make(chan struct{}, max)}
+}
+
+// Acquire waits for a free slot. It returns ctx.Err() if ctx ends first;
+// every successful Acquire must be paired with Release.
+func (cl *ConcurrencyLimiter) Acquire(ctx context.Context) error {
+	select {
+	case cl.slots <- struct{}{}:
+	default:
+		cl.waiting.Add(1)
+		defer cl.waiting.Add(-1)
+		select {
+		case cl.slots <- struct{}{}:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	n := cl.current.Add(1)
+	for {
+		hw := cl.highWater.Load()
+		if n <= hw || cl.highWater.CompareAndSwap(hw, n) {
+			break
+		}
+	}
+	return nil
+}
+
+// Release frees a slot taken by Acquire
+func (cl *ConcurrencyLimiter) Release() {
+	cl.current.Add(-1)
+	<-cl.slots
+}
+
+// Status returns current/max holders, waiters and the high-water mark
+func (cl *ConcurrencyLimiter) Status() ConcurrencyStatus {
+	return ConcurrencyStatus{
+		Current:   int(cl.current.Load()),
+		Max:       cap(cl.slots),
+		Waiting:   int(cl.waiting.Load()),
+		HighWater: int(cl.highWater.Load()),
+	}
+}
+
+// ResetHighWater resets the high-water mark to the current holder count
+func (cl *ConcurrencyLimiter) ResetHighWater() {
+	cl.highWater.Store(cl.current.Load())
+}
+
+// SleepContext sleeps for d, returning ctx.Err() early if ctx ends first
+func SleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,111 @@
+package ratelimit
+
+import (
+	"log"
+	"sync"
+	"time"
+)
+
+// PreventiveCooldown pauses detail fetching after a run of consecutive successes
+// (human-like breaks). The pause is pacing state, not a sleep: callers check
+// CooldownRemaining and skip work while it is positive.
+type PreventiveCooldown struct {
+	Enabled        bool
+	AfterSuccesses int           // consecutive successes that trigger a pause (default 3)
+	Duration       time.Duration // pause length (default 5m)
+
+	// Explicit is true when Enabled came from configuration rather than a default.
+	// The adaptive limiter only applies the cooldown when it was enabled explicitly.
+	Explicit bool
+}
+
+// DefaultPreventiveCooldown matches the worker's historical behavior
+var DefaultPreventiveCooldown = PreventiveCooldown{
+	Enabled:        true,
+	AfterSuccesses: 3,
+	Duration:       5 * time.Minute,
+}
+
+// CooldownStatus is the preventive cooldown part of limiter status
+type CooldownStatus struct {
+	Enabled            bool       `json:"enabled"`
+	AfterSuccesses     int        `json:"after_successes"`
+	DurationSec        int        `json:"duration_sec"`
+	ConsecutiveSuccess int        `json:"consecutive_success"`
+	CooldownUntil      *time.Time `json:"cooldown_until,omitempty"`
+	RemainingSec       int        `json:"remaining_sec"`
+}
+
+// cooldownPacer tracks consecutive successes and the current pause
+type cooldownPacer struct {
+	mu            sync.Mutex
+	policy        PreventiveCooldown
+	consecutive   int
+	cooldownUntil time.Time
+}
+
+func (cp *cooldownPacer) setPolicy(p PreventiveCooldown) {
+	if p.AfterSuccesses <= 0 {
+		p.AfterSuccesses = DefaultPreventiveCooldown.AfterSuccesses
+	}
+	if p.Duration <= 0 {
+		p.Duration = DefaultPreventiveCooldown.Duration
+	}
+
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+	cp.policy = p
+	cp.consecutive = 0
+	if !p.Enabled {
+		cp.cooldownUntil = time.Time{}
+	}
+}
+
+// record counts one detail attempt and starts a pause when the success run hits the threshold
+func (cp *cooldownPacer) record(success bool) {
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+
+	if !cp.policy.Enabled {
+		return
+	}
+	if !success {
+		cp.consecutive = 0
+		return
+	}
+
+	cp.consecutive++
+	if cp.consecutive >= cp.policy.AfterSuccesses {
+		cp.cooldownUntil = time.Now().Add(cp.policy.Duration)
+		log.Printf("[DetailLimiter] Preventive cooldown after %d successes - pausing for %v", cp.consecutive, cp.policy.Duration)
+		cp.consecutive = 0
+	}
+}
+
+func (cp *cooldownPacer) remaining() time.Duration {
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+
+	if d := time.Until(cp.cooldownUntil); d > 0 {
+		return d
+	}
+	return 0
+}
+
+func (cp *cooldownPacer) status() CooldownStatus {
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+
+	st := CooldownStatus{
+		Enabled:            cp.policy.Enabled,
+		AfterSuccesses:     cp.policy.AfterSuccesses,
+		DurationSec:        int(cp.policy.Duration.Seconds()),
+		ConsecutiveSuccess: cp.consecutive,
+	}
+	if d := time.Until(cp.cooldownUntil); d > 0 {
+		until := cp.cooldownUntil
+		st.CooldownUntil = &until
+		st.RemainingSec = int(d.Seconds())
+	}
+	return st
+}
@@ -0,0 +1,20 @@
+package ratelimit
+
+import "time"
+
+// EstimateQueueWait estimates how long until the queue item at position (1 = next to be
+// fetched) is fetched when detail pages go out at perHour. Each fetch takes one interval
+// (1h / perHour) of budget, so the item starts somewhere between position-1 and position
+// intervals from now, after any preventive cooldown still running.
+func EstimateQueueWait(position, perHour int, cooldown time.Duration) (earliest, latest time.Duration) {
+	if position <= 0 {
+		return 0, 0 // already being fetched
+	}
+	if perHour <= 0 {
+		perHour = 1
+	}
+	interval := time.Hour / time.Duration(perHour)
+	earliest = cooldown + time.Duration(position-1)*interval
+	latest = cooldown + time.Duration(position)*interval
+	return earliest, latest
+}
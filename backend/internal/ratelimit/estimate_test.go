@@ -0,0 +1,30 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEstimateQueueWait(t *testing.T) {
+	tests := []struct {
+		name             string
+		position         int
+		perHour          int
+		cooldown         time.Duration
+		earliest, latest time.Duration
+	}{
+		{"third at 5/h", 3, 5, 0, 24 * time.Minute, 36 * time.Minute},
+		{"next at 5/h", 1, 5, 0, 0, 12 * time.Minute},
+		{"third at 5/h behind a 5m cooldown", 3, 5, 5 * time.Minute, 29 * time.Minute, 41 * time.Minute},
+		{"being fetched", 0, 5, 5 * time.Minute, 0, 0},
+		{"no limit known", 2, 0, 0, time.Hour, 2 * time.Hour},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			earliest, latest := EstimateQueueWait(tt.position, tt.perHour, tt.cooldown)
+			if earliest != tt.earliest || latest != tt.latest {
+				t.Errorf("EstimateQueueWait = %v..%v (want %v..%v)", earliest, latest, tt.earliest, tt.latest)
+			}
+		})
+	}
+}
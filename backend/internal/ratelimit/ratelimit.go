@@ -39,6 +39,15 @@ func (rl *RateLimiter) AllowRequest() bool {
 		return true
 	}
 
+	// Shared across replicas when a store is configured
+	if store := getSharedStore(); store != nil {
+		ok, _, err := store.Reserve(apiWindowKey, rl.windowLimits())
+		if err == nil {
+			return ok
+		}
+		logStoreError("api", err)
+	}
+
 	rl.mu.Lock()
 	defer rl.mu.Unlock()
 
@@ -66,6 +75,18 @@ func (rl *RateLimiter) AllowRequest() bool {
 	return true
 }
 
+// apiWindowKey is the shared-store key for the API request windows
+const apiWindowKey = "api"
+
+// windowLimits returns the minute/hour/day caps for the shared store
+func (rl *RateLimiter) windowLimits() []WindowLimit {
+	return []WindowLimit{
+		{Window: time.Minute, Limit: rl.requestsPerMinute},
+		{Window: time.Hour, Limit: rl.requestsPerHour},
+		{Window: 24 * time.Hour, Limit: rl.requestsPerDay},
+	}
+}
+
 // cleanup removes expired entries from the time windows
 func (rl *RateLimiter) cleanup(now time.Time) {
 	// Clean minute window (keep last 60 seconds)
@@ -98,23 +119,37 @@ func (rl *RateLimiter) GetStats() Stats {
 		return Stats{Enabled: false}
 	}
 
+	if store := getSharedStore(); store != nil {
+		minute, errMin := store.Count(apiWindowKey, time.Minute)
+		hour, errHour := store.Count(apiWindowKey, time.Hour)
+		day, errDay := store.Count(apiWindowKey, 24*time.Hour)
+		if errMin == nil && errHour == nil && errDay == nil {
+			return rl.buildStats(minute, hour, day)
+		}
+	}
+
 	rl.mu.Lock()
 	defer rl.mu.Unlock()
 
 	now := time.Now()
 	rl.cleanup(now)
 
+	return rl.buildStats(len(rl.minuteWindow), len(rl.hourWindow), len(rl.dayWindow))
+}
+
+// buildStats fills Stats from window counts
+func (rl *RateLimiter) buildStats(minute, hour, day int) Stats {
 	return Stats{
 		Enabled:              true,
-		RequestsLastMinute:   len(rl.minuteWindow),
-		RequestsLastHour:     len(rl.hourWindow),
-		RequestsLastDay:      len(rl.dayWindow),
+		RequestsLastMinute:   minute,
+		RequestsLastHour:     hour,
+		RequestsLastDay:      day,
 		LimitPerMinute:       rl.requestsPerMinute,
 		LimitPerHour:         rl.requestsPerHour,
 		LimitPerDay:          rl.requestsPerDay,
-		RemainingThisMinute:  max(0, rl.requestsPerMinute-len(rl.minuteWindow)),
-		RemainingThisHour:    max(0, rl.requestsPerHour-len(rl.hourWindow)),
-		RemainingThisDay:     max(0, rl.requestsPerDay-len(rl.dayWindow)),
+		RemainingThisMinute:  max(0, rl.requestsPerMinute-minute),
+		RemainingThisHour:    max(0, rl.requestsPerHour-hour),
+		RemainingThisDay:     max(0, rl.requestsPerDay-day),
 	}
 }
 
@@ -130,6 +165,11 @@ type Stats struct {
 	RemainingThisMinute  int  `json:"remaining_this_minute"`
 	RemainingThisHour    int  `json:"remaining_this_hour"`
 	RemainingThisDay     int  `json:"remaining_this_day"`
+
+	// DetailLimiter is filled by the API handler (window usage and preventive cooldown)
+	DetailLimiter *DetailLimiterStatus `json:"detail_limiter,omitempty"`
+	// ParseLimiter is filled by the API handler (current/max concurrent HTML parses)
+	ParseLimiter *ConcurrencyStatus `json:"parse_limiter,omitempty"`
 }
 
 // Reset clears all tracked requests (useful for testing)
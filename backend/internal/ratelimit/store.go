@@ -0,0 +1,48 @@
+package ratelimit
+
+import (
+	"log"
+	"sync"
+	"time"
+)
+
+// WindowLimit is one sliding-window cap checked by a WindowStore
+type WindowLimit struct {
+	Window time.Duration
+	Limit  int // <= 0 means unlimited
+}
+
+// WindowStore keeps sliding-window request logs outside the process so that
+// several API replicas draw from one budget. When no store is configured the
+// limiters keep their in-memory windows.
+type WindowStore interface {
+	// Reserve records one request under key if every window is below its limit.
+	// When refused, retryAt is the earliest time a slot frees up.
+	Reserve(key string, limits []WindowLimit) (ok bool, retryAt time.Time, err error)
+	// Count returns the number of requests recorded under key within window.
+	Count(key string, window time.Duration) (int, error)
+}
+
+var (
+	sharedStoreMu sync.RWMutex
+	sharedStore   WindowStore
+)
+
+// SetSharedStore makes all limiters use store for their windows (nil restores in-memory windows)
+func SetSharedStore(store WindowStore) {
+	sharedStoreMu.Lock()
+	defer sharedStoreMu.Unlock()
+	sharedStore = store
+}
+
+// getSharedStore returns the configured shared store, or nil for in-memory mode
+func getSharedStore() WindowStore {
+	sharedStoreMu.RLock()
+	defer sharedStoreMu.RUnlock()
+	return sharedStore
+}
+
+// logStoreError reports a shared-store failure; callers fall back to the in-memory window
+func logStoreError(limiter string, err error) {
+	log.Printf("[RateLimit] limiter=%s shared store error, using in-memory window: %v", limiter, err)
+}
@@ -1,6 +1,7 @@
 package ratelimit
 
 import (
+	"context"
 	"log"
 	"math/rand"
 	"sync"
@@ -23,6 +24,8 @@ type DetailLimiter struct {
 	requestTimes  []time.Time
 	maxPerHour    int
 	windowDuration time.Duration
+	windowKey     string        // shared-store key ("detail" for Yahoo, "detail:<source>" otherwise)
+	cooldown      cooldownPacer // preventive cooldown after consecutive successes
 }
 
 // NewYahooLimiter creates a new rate limiter for Yahoo scraping
@@ -37,26 +40,41 @@ func NewYahooLimiter(maxInFlight int, baseDelay, jitter time.Duration) *YahooLim
 
 // Acquire waits until it's safe to make a request
 func (yl *YahooLimiter) Acquire() {
+	_ = yl.AcquireContext(context.Background())
+}
+
+// AcquireContext is Acquire that gives up with ctx.Err() once ctx ends;
+// Release must only be called after a nil return
+func (yl *YahooLimiter) AcquireContext(ctx context.Context) error {
 	yl.mutex.Lock()
 
 	// Wait for in-flight count to drop
 	for yl.currentInFlight >= yl.maxInFlight {
 		yl.mutex.Unlock()
-		time.Sleep(100 * time.Millisecond)
+		if err := SleepContext(ctx, 100*time.Millisecond); err != nil {
+			return err
+		}
 		yl.mutex.Lock()
 	}
 
 	// Apply rate limiting with jitter
 	elapsed := time.Since(yl.lastRequest)
-	requiredDelay := yl.baseDelay + time.Duration(rand.Int63n(int64(yl.jitter)))
+	requiredDelay := yl.baseDelay
+	if yl.jitter > 0 {
+		requiredDelay += time.Duration(rand.Int63n(int64(yl.jitter)))
+	}
 
 	if elapsed < requiredDelay {
-		time.Sleep(requiredDelay - elapsed)
+		if err := SleepContext(ctx, requiredDelay-elapsed); err != nil {
+			yl.mutex.Unlock()
+			return err
+		}
 	}
 
 	yl.currentInFlight++
 	yl.lastRequest = time.Now()
 	yl.mutex.Unlock()
+	return nil
 }
 
 // Release marks a request as completed
@@ -66,6 +84,14 @@ func (yl *YahooLimiter) Release() {
 	yl.mutex.Unlock()
 }
 
+// SetDelay changes the base delay and jitter applied from the next Acquire on
+func (yl *YahooLimiter) SetDelay(baseDelay, jitter time.Duration) {
+	yl.mutex.Lock()
+	defer yl.mutex.Unlock()
+	yl.baseDelay = baseDelay
+	yl.jitter = jitter
+}
+
 // GetInFlight returns current in-flight request count (for debugging)
 func (yl *YahooLimiter) GetInFlight() int {
 	yl.mutex.Lock()
@@ -75,15 +101,80 @@ func (yl *YahooLimiter) GetInFlight() int {
 
 // NewDetailLimiter creates a new detail page rate limiter
 func NewDetailLimiter(maxPerHour int) *DetailLimiter {
-	return &DetailLimiter{
+	dl := &DetailLimiter{
 		requestTimes:   make([]time.Time, 0),
 		maxPerHour:     maxPerHour,
 		windowDuration: 1 * time.Hour,
+		windowKey:      detailWindowKey,
+	}
+	dl.cooldown.setPolicy(DefaultPreventiveCooldown)
+	return dl
+}
+
+// NewSourceDetailLimiter creates a detail limiter for another site. Its shared-store window
+// is keyed by source, so each site's hourly budget is counted separately across replicas.
+func NewSourceDetailLimiter(source string, maxPerHour int) *DetailLimiter {
+	dl := NewDetailLimiter(maxPerHour)
+	dl.windowKey = detailWindowKey + ":" + source
+	return dl
+}
+
+// SetPreventiveCooldown replaces the preventive cooldown policy
+func (dl *DetailLimiter) SetPreventiveCooldown(p PreventiveCooldown) {
+	dl.cooldown.setPolicy(p)
+}
+
+// RecordOutcome should be called once per detail attempt; consecutive successes
+// start the preventive cooldown, a failure resets the run
+func (dl *DetailLimiter) RecordOutcome(success bool) {
+	dl.cooldown.record(success)
+}
+
+// CooldownRemaining returns how long the preventive cooldown still has to run (0 if none)
+func (dl *DetailLimiter) CooldownRemaining() time.Duration {
+	return dl.cooldown.remaining()
+}
+
+// DetailLimiterStatus is the detail limiter's window usage and pacing state
+type DetailLimiterStatus struct {
+	UsedLastHour       int            `json:"used_last_hour"`
+	MaxPerHour         int            `json:"max_per_hour"`
+	PreventiveCooldown CooldownStatus `json:"preventive_cooldown"`
+}
+
+// Status returns usage and preventive cooldown state
+func (dl *DetailLimiter) Status() DetailLimiterStatus {
+	return DetailLimiterStatus{
+		UsedLastHour:       dl.GetUsage(),
+		MaxPerHour:         dl.maxPerHour,
+		PreventiveCooldown: dl.cooldown.status(),
 	}
 }
 
+// Exhausted reports whether the hourly window is used up (an acquire now would wait)
+func (dl *DetailLimiter) Exhausted() bool {
+	return dl.GetUsage() >= dl.maxPerHour
+}
+
+// detailWindowKey is the shared-store key for the (Yahoo) detail page window
+const detailWindowKey = "detail"
+
 // Acquire waits until it's safe to make a detail page request
 func (dl *DetailLimiter) Acquire(caller string) {
+	_ = dl.AcquireContext(context.Background(), caller)
+}
+
+// AcquireContext is Acquire that gives up with ctx.Err() once ctx ends (the wait for a
+// free slot in the hourly window can otherwise last up to an hour)
+func (dl *DetailLimiter) AcquireContext(ctx context.Context, caller string) error {
+	if store := getSharedStore(); store != nil {
+		err := dl.acquireShared(ctx, store, caller)
+		if err == nil || ctx.Err() != nil {
+			return err
+		}
+		logStoreError("detail", err)
+	}
+
 	dl.mutex.Lock()
 	defer dl.mutex.Unlock()
 
@@ -112,8 +203,12 @@ func (dl *DetailLimiter) Acquire(caller string) {
 			log.Printf("[DetailLimiter] caller=%s limiter=detail now_epoch=%d next_epoch=%d wait_sec=%d reason=rate_limit count=%d/%d",
 				caller, nowEpoch, nextEpoch, waitSec, len(dl.requestTimes), dl.maxPerHour)
 			dl.mutex.Unlock()
-			time.Sleep(waitDuration + 1*time.Second)
+			err := SleepContext(ctx, waitDuration+1*time.Second)
 			dl.mutex.Lock()
+			if err != nil {
+				log.Printf("[DetailLimiter] caller=%s wait canceled: %v", caller, err)
+				return err
+			}
 
 			// Re-check after waiting
 			now = time.Now()
@@ -135,10 +230,42 @@ func (dl *DetailLimiter) Acquire(caller string) {
 	dl.requestTimes = append(dl.requestTimes, now)
 	log.Printf("[DetailLimiter] caller=%s Request allowed (%d/%d used in last hour)",
 		caller, len(dl.requestTimes), dl.maxPerHour)
+	return nil
+}
+
+// acquireShared waits for a slot in the shared detail window (all replicas draw from one budget)
+func (dl *DetailLimiter) acquireShared(ctx context.Context, store WindowStore, caller string) error {
+	limits := []WindowLimit{{Window: dl.windowDuration, Limit: dl.maxPerHour}}
+	for {
+		ok, retryAt, err := store.Reserve(dl.windowKey, limits)
+		if err != nil {
+			return err
+		}
+		if ok {
+			log.Printf("[DetailLimiter] caller=%s Request allowed (shared window, max %d/hour)", caller, dl.maxPerHour)
+			return nil
+		}
+
+		waitDuration := time.Until(retryAt)
+		if waitDuration < time.Second {
+			waitDuration = time.Second
+		}
+		log.Printf("[DetailLimiter] caller=%s limiter=detail next_epoch=%d wait_sec=%d reason=rate_limit shared=true",
+			caller, retryAt.Unix(), int(waitDuration.Seconds()))
+		if err := SleepContext(ctx, waitDuration); err != nil {
+			return err
+		}
+	}
 }
 
 // GetUsage returns current usage count in the window
 func (dl *DetailLimiter) GetUsage() int {
+	if store := getSharedStore(); store != nil {
+		if count, err := store.Count(dl.windowKey, dl.windowDuration); err == nil {
+			return count
+		}
+	}
+
 	dl.mutex.Lock()
 	defer dl.mutex.Unlock()
 
@@ -0,0 +1,159 @@
+package redisstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"real-estate-portal/internal/ratelimit"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// keyPrefix namespaces every key this app writes
+const keyPrefix = "shiboroom:"
+
+// opTimeout bounds each Redis round trip so a slow Redis can't stall request handling
+const opTimeout = 2 * time.Second
+
+// reserveScript atomically trims a sorted-set request log, checks every window and records the request.
+// ARGV: now_ms, member, then (window_ms, limit) pairs. Returns {1, 0} when allowed,
+// {0, retry_at_ms} when a window is full.
+var reserveScript = redis.NewScript(`
+local now = tonumber(ARGV[1])
+local maxWindow = 0
+for i = 3, #ARGV, 2 do
+	local w = tonumber(ARGV[i])
+	if w > maxWindow then maxWindow = w end
+end
+redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', now - maxWindow)
+for i = 3, #ARGV, 2 do
+	local w = tonumber(ARGV[i])
+	local limit = tonumber(ARGV[i + 1])
+	if limit > 0 then
+		local count = redis.call('ZCOUNT', KEYS[1], '(' .. (now - w), '+inf')
+		if count >= limit then
+			local oldest = redis.call('ZRANGEBYSCORE', KEYS[1], '(' .. (now - w), '+inf', 'WITHSCORES', 'LIMIT', 0, 1)
+			return {0, tonumber(oldest[2]) + w}
+		end
+	end
+end
+redis.call('ZADD', KEYS[1], now, ARGV[2])
+redis.call('PEXPIRE', KEYS[1], maxWindow)
+return {1, 0}
+`)
+
+// Store provides Redis-backed limiter windows and a shared byte cache.
+// It implements ratelimit.WindowStore and database.SharedCache.
+type Store struct {
+	client *redis.Client
+}
+
+// New connects to Redis and verifies the connection
+func New(addr, password string, db int) (*Store, error) {
+	client := redis.NewClient(&redis.Options{
+		Addr:     addr,
+		Password: password,
+		DB:       db,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), opTimeout)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("redis ping %s: %w", addr, err)
+	}
+
+	return &Store{client: client}, nil
+}
+
+// Close closes the Redis connection
+func (s *Store) Close() error {
+	return s.client.Close()
+}
+
+func windowKey(key string) string {
+	return keyPrefix + "ratelimit:" + key
+}
+
+func cacheKey(key string) string {
+	return keyPrefix + "cache:" + key
+}
+
+// Reserve records one request under key if every window is below its limit
+func (s *Store) Reserve(key string, limits []ratelimit.WindowLimit) (bool, time.Time, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), opTimeout)
+	defer cancel()
+
+	now := time.Now().UnixMilli()
+	member := strconv.FormatInt(time.Now().UnixNano(), 10) + "-" + strconv.Itoa(rand.Intn(1_000_000))
+	args := []interface{}{now, member}
+	for _, l := range limits {
+		args = append(args, l.Window.Milliseconds(), l.Limit)
+	}
+
+	res, err := reserveScript.Run(ctx, s.client, []string{windowKey(key)}, args...).Int64Slice()
+	if err != nil {
+		return false, time.Time{}, err
+	}
+	if len(res) != 2 {
+		return false, time.Time{}, fmt.Errorf("unexpected reserve result: %v", res)
+	}
+	if res[0] == 1 {
+		return true, time.Time{}, nil
+	}
+	return false, time.UnixMilli(res[1]), nil
+}
+
+// Count returns the number of requests recorded under key within window
+func (s *Store) Count(key string, window time.Duration) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), opTimeout)
+	defer cancel()
+
+	min := "(" + strconv.FormatInt(time.Now().Add(-window).UnixMilli(), 10)
+	count, err := s.client.ZCount(ctx, windowKey(key), min, "+inf").Result()
+	return int(count), err
+}
+
+// Get returns a cached value (ok=false when missing or expired)
+func (s *Store) Get(key string) ([]byte, bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), opTimeout)
+	defer cancel()
+
+	data, err := s.client.Get(ctx, cacheKey(key)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return data, true, nil
+}
+
+// Set stores a value with a TTL
+func (s *Store) Set(key string, value []byte, ttl time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), opTimeout)
+	defer cancel()
+	return s.client.Set(ctx, cacheKey(key), value, ttl).Err()
+}
+
+// DeletePrefix removes every cached value whose key starts with prefix
+func (s *Store) DeletePrefix(prefix string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), opTimeout)
+	defer cancel()
+
+	iter := s.client.Scan(ctx, 0, cacheKey(prefix)+"*", 100).Iterator()
+	var keys []string
+	for iter.Next(ctx) {
+		keys = append(keys, iter.Val())
+	}
+	if err := iter.Err(); err != nil {
+		return err
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+	return s.client.Del(ctx, keys...).Err()
+}
@@ -0,0 +1,168 @@
+package redisstore_test
+
+import (
+	"context"
+	"errors"
+	"real-estate-portal/internal/ratelimit"
+	"real-estate-portal/internal/redisstore"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+)
+
+// newReplicas returns n stores on one miniredis, each with its own connection, as n API
+// replicas pointed at the same redis.addr would have
+func newReplicas(t *testing.T, n int) []*redisstore.Store {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	stores := make([]*redisstore.Store, n)
+	for i := range stores {
+		store, err := redisstore.New(mr.Addr(), "", 0)
+		if err != nil {
+			t.Fatalf("New: %v", err)
+		}
+		t.Cleanup(func() { store.Close() })
+		stores[i] = store
+	}
+	return stores
+}
+
+// useSharedStore makes the ratelimit package use store until the test ends
+func useSharedStore(t *testing.T, store ratelimit.WindowStore) {
+	ratelimit.SetSharedStore(store)
+	t.Cleanup(func() { ratelimit.SetSharedStore(nil) })
+}
+
+func TestReserveSharesWindowAcrossReplicas(t *testing.T) {
+	stores := newReplicas(t, 2)
+	a, b := stores[0], stores[1]
+	limits := []ratelimit.WindowLimit{{Window: time.Minute, Limit: 3}}
+
+	start := time.Now()
+	for i, store := range []*redisstore.Store{a, b, a} {
+		if ok, _, err := store.Reserve("detail:test", limits); err != nil || !ok {
+			t.Fatalf("reserve %d: ok=%v err=%v (want allowed)", i+1, ok, err)
+		}
+	}
+	for name, store := range map[string]*redisstore.Store{"a": a, "b": b} {
+		ok, retryAt, err := store.Reserve("detail:test", limits)
+		if err != nil || ok {
+			t.Fatalf("replica %s: 4th reserve ok=%v err=%v (want refused)", name, ok, err)
+		}
+		if retryAt.Before(start.Add(time.Minute-time.Second)) || retryAt.After(time.Now().Add(time.Minute)) {
+			t.Errorf("replica %s: retry at %v (want about a minute after the first reserve)", name, retryAt)
+		}
+		if count, err := store.Count("detail:test", time.Minute); err != nil || count != 3 {
+			t.Errorf("replica %s: count=%d err=%v (want 3)", name, count, err)
+		}
+	}
+
+	// Other keys have their own window
+	if ok, _, err := b.Reserve("detail:other", limits); err != nil || !ok {
+		t.Errorf("other key: ok=%v err=%v (want allowed)", ok, err)
+	}
+}
+
+func TestReserveChecksEveryWindow(t *testing.T) {
+	store := newReplicas(t, 1)[0]
+	limits := []ratelimit.WindowLimit{
+		{Window: time.Minute, Limit: 10},
+		{Window: time.Hour, Limit: 2},
+		{Window: 24 * time.Hour, Limit: 0}, // unlimited
+	}
+	for i := 0; i < 2; i++ {
+		if ok, _, err := store.Reserve("api", limits); err != nil || !ok {
+			t.Fatalf("reserve %d: ok=%v err=%v", i+1, ok, err)
+		}
+	}
+	if ok, _, err := store.Reserve("api", limits); err != nil || ok {
+		t.Fatalf("3rd reserve: ok=%v err=%v (want refused by the hourly window)", ok, err)
+	}
+}
+
+// Two DetailLimiter instances (one per replica) with the same source draw from one hourly budget
+func TestDetailLimitersShareOneBudget(t *testing.T) {
+	stores := newReplicas(t, 1)
+	useSharedStore(t, stores[0])
+
+	replica1 := ratelimit.NewSourceDetailLimiter("suumo", 2)
+	replica2 := ratelimit.NewSourceDetailLimiter("suumo", 2)
+	ctx := context.Background()
+	if err := replica1.AcquireContext(ctx, "test"); err != nil {
+		t.Fatalf("replica 1, 1st acquire: %v", err)
+	}
+	if err := replica2.AcquireContext(ctx, "test"); err != nil {
+		t.Fatalf("replica 2, 1st acquire: %v", err)
+	}
+
+	// The budget is used up for both: the next acquire on either waits for the window
+	for name, l := range map[string]*ratelimit.DetailLimiter{"1": replica1, "2": replica2} {
+		waitCtx, cancel := context.WithTimeout(ctx, 200*time.Millisecond)
+		err := l.AcquireContext(waitCtx, "test")
+		cancel()
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("replica %s, 3rd acquire: %v (want it to wait past the deadline)", name, err)
+		}
+		if status := l.Status(); status.UsedLastHour != 2 || status.MaxPerHour != 2 {
+			t.Errorf("replica %s: status %+v (want 2 of 2 used)", name, status)
+		}
+	}
+
+	// Another source keeps its own budget
+	if err := ratelimit.NewSourceDetailLimiter("yahoo", 2).AcquireContext(ctx, "test"); err != nil {
+		t.Errorf("other source: %v", err)
+	}
+}
+
+// Two API rate limiters (one per replica) refuse together once the shared minute is used up
+func TestRateLimitersShareOneBudget(t *testing.T) {
+	stores := newReplicas(t, 1)
+	useSharedStore(t, stores[0])
+
+	replica1 := ratelimit.NewRateLimiter(3, 0, 0, true)
+	replica2 := ratelimit.NewRateLimiter(3, 0, 0, true)
+	allowed := 0
+	for i := 0; i < 4; i++ {
+		for _, l := range []*ratelimit.RateLimiter{replica1, replica2} {
+			if l.AllowRequest() {
+				allowed++
+			}
+		}
+	}
+	if allowed != 3 {
+		t.Errorf("%d requests allowed across two replicas (want 3, the shared per-minute limit)", allowed)
+	}
+	if stats := replica2.GetStats(); stats.RequestsLastMinute != 3 {
+		t.Errorf("replica 2 stats: %+v (want 3 used this minute)", stats)
+	}
+}
+
+func TestCache(t *testing.T) {
+	stores := newReplicas(t, 2)
+	a, b := stores[0], stores[1]
+
+	if err := a.Set("listing:page1", []byte("one"), time.Minute); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if err := a.Set("listing:page2", []byte("two"), time.Minute); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if err := a.Set("other", []byte("kept"), time.Minute); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if data, ok, err := b.Get("listing:page1"); err != nil || !ok || string(data) != "one" {
+		t.Fatalf("Get from the other replica: %q ok=%v err=%v", data, ok, err)
+	}
+	if err := b.DeletePrefix("listing:"); err != nil {
+		t.Fatalf("DeletePrefix: %v", err)
+	}
+	for _, key := range []string{"listing:page1", "listing:page2"} {
+		if _, ok, err := a.Get(key); err != nil || ok {
+			t.Errorf("%s after DeletePrefix: ok=%v err=%v (want gone)", key, ok, err)
+		}
+	}
+	if data, ok, _ := a.Get("other"); !ok || string(data) != "kept" {
+		t.Errorf("unrelated key: %q ok=%v (want kept)", data, ok)
+	}
+}
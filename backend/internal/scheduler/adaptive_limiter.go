@@ -0,0 +1,122 @@
+package scheduler
+
+import (
+	"context"
+	"real-estate-portal/internal/models"
+	"real-estate-portal/internal/ratelimit"
+	"real-estate-portal/internal/scraper"
+)
+
+// SetAdaptiveLimiter makes the worker pace source's detail scrapes with l instead of the
+// source's fixed DetailLimiter, and report every attempt's outcome to it; call before Start
+func (w *QueueWorker) SetAdaptiveLimiter(source string, l *ratelimit.AdaptiveDetailLimiter) {
+	if w.adaptive == nil {
+		w.adaptive = make(map[string]*ratelimit.AdaptiveDetailLimiter)
+	}
+	w.adaptive[source] = l
+}
+
+// acquireDetail waits for source's detail budget: the adaptive limiter when one is set for it
+func (w *QueueWorker) acquireDetail(ctx context.Context, source scraper.PropertySource, caller string) error {
+	if adaptive := w.adaptive[source.Name()]; adaptive != nil {
+		return adaptive.AcquireContext(ctx, caller)
+	}
+	return source.Limiter().AcquireContext(ctx, caller)
+}
+
+// adaptiveFor returns the adaptive limiter of item's source (nil if it uses the fixed one)
+func (w *QueueWorker) adaptiveFor(item *models.DetailScrapeQueue) *ratelimit.AdaptiveDetailLimiter {
+	if source, err := w.sources.ForURL(item.DetailURL); err == nil {
+		return w.adaptive[source.Name()]
+	}
+	return nil
+}
+
+// recordSuccess reports a detail attempt the site answered normally
+func (w *QueueWorker) recordSuccess(item *models.DetailScrapeQueue) {
+	if adaptive := w.adaptiveFor(item); adaptive != nil {
+		adaptive.Observe(true)
+		return
+	}
+	w.limiterFor(item).RecordOutcome(true)
+}
+
+// recordFailure reports a failed detail attempt. The adaptive limiter counts the failures that
+// say the site is pushing back (errors, timeouts, WAF); a 404 only says the listing is gone.
+func (w *QueueWorker) recordFailure(item *models.DetailScrapeQueue, failure ScrapeFailure) {
+	if adaptive := w.adaptiveFor(item); adaptive != nil {
+		if failure != FailurePermanent {
+			adaptive.Observe(false)
+		}
+		return
+	}
+	w.limiterFor(item).RecordOutcome(false)
+}
+
+// coolingDown returns the sources whose detail limiter (adaptive or fixed) is in a preventive
+// cooldown
+func (w *QueueWorker) coolingDown() []string {
+	var names []string
+	for _, source := range w.sources.Sources() {
+		remaining := source.Limiter().CooldownRemaining()
+		if adaptive := w.adaptive[source.Name()]; adaptive != nil {
+			remaining = adaptive.CooldownRemaining()
+		}
+		if remaining > 0 {
+			names = append(names, source.Name())
+		}
+	}
+	return names
+}
+
+// detailRoom returns how many more detail requests each source's fixed limiter allows this hour.
+// Sources paced by an adaptive limiter are left out: it spreads its budget over the hour itself.
+func (w *QueueWorker) detailRoom() map[string]int {
+	room := make(map[string]int)
+	for _, source := range w.sources.Sources() {
+		if w.adaptive[source.Name()] != nil {
+			continue
+		}
+		status := source.Limiter().Status()
+		room[source.Name()] = status.MaxPerHour - status.UsedLastHour
+	}
+	return room
+}
+
+// limiterBound returns the sources with pending items whose detail limiter (the one
+// acquireDetail waits on: adaptive or fixed) has no budget left right now
+func (w *QueueWorker) limiterBound() []string {
+	var pending []struct {
+		Source string
+		Count  int64
+	}
+	if err := w.db.Model(&models.DetailScrapeQueue{}).Select("source, COUNT(*) AS count").
+		Where("status = ?", models.QueueStatusPending).Group("source").Find(&pending).Error; err != nil {
+		return nil
+	}
+
+	bound := []string{}
+	for _, p := range pending {
+		source := w.sources.ByName(p.Source)
+		if p.Count == 0 || source == nil {
+			continue
+		}
+		exhausted := source.Limiter().Exhausted()
+		if adaptive := w.adaptive[source.Name()]; adaptive != nil {
+			exhausted = adaptive.Exhausted()
+		}
+		if exhausted {
+			bound = append(bound, source.Name())
+		}
+	}
+	return bound
+}
+
+// adaptiveStatus returns each adaptive limiter's state keyed by source name
+func (w *QueueWorker) adaptiveStatus() map[string]ratelimit.AdaptiveStatus {
+	status := make(map[string]ratelimit.AdaptiveStatus, len(w.adaptive))
+	for name, l := range w.adaptive {
+		status[name] = l.Status()
+	}
+	return status
+}
@@ -0,0 +1,28 @@
+package scheduler
+
+import (
+	"real-estate-portal/internal/queue"
+	"time"
+
+	"gorm.io/gorm/clause"
+)
+
+// SetPriorityAging sets how long an item waits in the queue to gain one priority level when the
+// next item is picked (default 24h, 0 = strict priority order); call before Start. Rounded down
+// to whole hours, at least one.
+func (w *QueueWorker) SetPriorityAging(d time.Duration) {
+	w.priorityAging = d
+}
+
+// queueOrder orders candidate items by queue.AgedPriorityExpr, then oldest first
+func (w *QueueWorker) queueOrder(now time.Time) interface{} {
+	expr, vars := queue.AgedPriorityExpr(now, w.priorityAging)
+	if vars == nil {
+		return "priority DESC, created_at ASC"
+	}
+	return clause.OrderBy{Expression: clause.Expr{
+		SQL:                expr + " DESC, created_at ASC",
+		Vars:               vars,
+		WithoutParentheses: true,
+	}}
+}
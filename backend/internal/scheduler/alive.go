@@ -0,0 +1,91 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"log"
+	"real-estate-portal/internal/database"
+	"real-estate-portal/internal/models"
+	"real-estate-portal/internal/scraper"
+	"time"
+)
+
+// aliveColumns are the property columns the alive check needs
+var aliveColumns = []string{"id", "source", "source_property_id", "detail_url", "last_seen_at"}
+
+// aliveCheckResult counts the outcomes of one runAliveChecks pass
+type aliveCheckResult struct {
+	Checked int // properties the site answered for
+	Removed int // answered 404/410: marked removed with a property_removed change
+	Seen    int // still listed: last_seen_at bumped
+	Failed  int // no answer (network, throttling, DB errors); left for the next run
+}
+
+// runAliveChecks HEAD-checks the active properties unseen for longest (scraper.alive_check) so
+// delisted ones are removed without a detail scrape, and listed ones don't go stale. It stops
+// early when a site throttles or its circuit breaker is open.
+func (s *Scheduler) runAliveChecks() aliveCheckResult {
+	var result aliveCheckResult
+	cfg := s.config.Scraper.AliveCheck
+
+	var props []models.Property
+	cutoff := time.Now().Add(-cfg.UnseenAfter())
+	err := s.db.Select(aliveColumns).
+		Where("status = ? AND (last_seen_at IS NULL OR last_seen_at < ?)", models.PropertyStatusActive, cutoff).
+		Order("last_seen_at ASC").
+		Limit(cfg.Limit()).
+		Find(&props).Error
+	if err != nil {
+		log.Printf("Scheduler: Alive check query failed: %v", err)
+		return result
+	}
+
+	gormDB := database.NewGormDBFromDB(s.db)
+	for i := range props {
+		prop := &props[i]
+		source, err := s.sources.ForURL(prop.DetailURL)
+		if err != nil {
+			result.Failed++
+			continue
+		}
+		checker, ok := source.(scraper.AliveChecker)
+		if !ok {
+			continue
+		}
+
+		alive, status, err := checker.CheckAliveContext(context.Background(), prop.DetailURL)
+		if err != nil {
+			result.Failed++
+			log.Printf("Scheduler: Alive check failed for property %s: %v", prop.ID, err)
+			if ClassifyScrapeFailure(err) == FailureCooldown || errors.Is(err, scraper.ErrRateLimited) {
+				log.Printf("Scheduler: Stopping alive checks (site is throttling or blocked)")
+				break
+			}
+			continue
+		}
+		result.Checked++
+
+		if !alive {
+			if err := gormDB.MarkPropertyDelisted(prop.ID, status); err != nil {
+				result.Failed++
+				log.Printf("Scheduler: Failed to mark property %s removed: %v", prop.ID, err)
+				continue
+			}
+			result.Removed++
+			continue
+		}
+
+		// UpdateColumn: being listed is not a content change, so updated_at stays
+		prop.UpdateLastSeen()
+		if err := s.db.Model(prop).UpdateColumn("last_seen_at", prop.LastSeenAt).Error; err != nil {
+			result.Failed++
+			log.Printf("Scheduler: Failed to bump last_seen_at for property %s: %v", prop.ID, err)
+			continue
+		}
+		result.Seen++
+	}
+
+	log.Printf("Scheduler: Alive checks completed. Candidates=%d, Checked=%d, Removed=%d, Seen=%d, Failed=%d",
+		len(props), result.Checked, result.Removed, result.Seen, result.Failed)
+	return result
+}
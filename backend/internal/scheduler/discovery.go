@@ -0,0 +1,192 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"real-estate-portal/internal/errtext"
+	"real-estate-portal/internal/maintenance"
+	"real-estate-portal/internal/models"
+	"real-estate-portal/internal/queue"
+	"real-estate-portal/internal/scraper"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ListDiscovery is what crawling one scheduler.list_urls entry found
+type ListDiscovery struct {
+	URL          string `json:"url"`
+	Source       string `json:"source,omitempty"`
+	PagesVisited int    `json:"pages_visited"`
+	Found        int    `json:"found"`           // detail URLs on the crawled pages
+	New          int    `json:"new"`             // queued (or a failed row requeued)
+	Existing     int    `json:"existing"`        // known properties; last_seen_at refreshed
+	Skipped      int    `json:"skipped"`         // already queued, done or permanently failed
+	Errors       int    `json:"errors"`          // URLs that could not be checked or queued
+	Error        string `json:"error,omitempty"` // the list page failed (after pages_visited pages)
+}
+
+// RunSummary is a recorded scheduler run with its per-list-URL results
+type RunSummary struct {
+	models.SchedulerRun
+	Discoveries []ListDiscovery `json:"list_urls"`
+}
+
+// discoverListURLs crawls the given list URLs, spaced out by scheduler.list_spacing_seconds
+// on top of each source's list limiter, and queues the detail URLs not known yet. Canceling
+// ctx (Stop) ends the crawl, keeping what was found so far.
+func (s *Scheduler) discoverListURLs(ctx context.Context, listURLs []string) []ListDiscovery {
+	if len(listURLs) == 0 {
+		return nil
+	}
+	if s.sources == nil {
+		log.Printf("Scheduler: %d list URLs configured but no sources registered, skipping discovery", len(listURLs))
+		return nil
+	}
+
+	discoveries := make([]ListDiscovery, 0, len(listURLs))
+	for i, listURL := range listURLs {
+		if i > 0 && !sleepContext(ctx, s.config.Scheduler.ListSpacing()) {
+			log.Println("Scheduler: Stopping, list discovery canceled")
+			break
+		}
+		if maintenance.ReadOnly() {
+			log.Println("Scheduler: Read-only maintenance mode, stopping list discovery")
+			break
+		}
+
+		d := s.discoverListURL(ctx, listURL)
+		log.Printf("Scheduler: Discovered %s: pages=%d found=%d new=%d existing=%d skipped=%d errors=%d %s",
+			listURL, d.PagesVisited, d.Found, d.New, d.Existing, d.Skipped, d.Errors, d.Error)
+		discoveries = append(discoveries, d)
+	}
+	return discoveries
+}
+
+// sleepContext waits for d, returning false if ctx is canceled first
+func sleepContext(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// discoverListURL crawls one list URL (up to scheduler.list_max_pages pages) and hands each
+// detail URL to queue.Service.EnqueueListed with the page it was found on as the referer
+func (s *Scheduler) discoverListURL(ctx context.Context, listURL string) ListDiscovery {
+	d := ListDiscovery{URL: listURL}
+	source, err := s.sources.ForURL(listURL)
+	if err != nil {
+		d.Error = errtext.Clean(err.Error())
+		return d
+	}
+	d.Source = source.Name()
+
+	page := &scraper.ListPage{PagesVisited: 1}
+	if paged, ok := source.(scraper.PagedSource); ok {
+		maxPages := min(s.config.Scheduler.MaxPages(), scraper.MaxListPages)
+		page, err = paged.ScrapeListPagesWithMetaContext(ctx, listURL, maxPages)
+	} else {
+		page.URLs, err = source.ScrapeList(ctx, listURL)
+	}
+	if page == nil {
+		page = &scraper.ListPage{}
+	}
+	// A later page failing keeps the URLs already collected
+	if err != nil {
+		d.Error = errtext.Clean(err.Error())
+		if len(page.URLs) == 0 {
+			page.PagesVisited = 0
+		}
+	}
+	d.PagesVisited = page.PagesVisited
+	d.Found = len(page.URLs)
+
+	for _, detailURL := range page.URLs {
+		sourceName, sourcePropertyID, normalized, err := queue.ResolveDetailURL(s.sources, detailURL)
+		if err != nil {
+			d.Errors++
+			continue
+		}
+		outcome, _, err := s.queue.EnqueueListed(sourceName, sourcePropertyID, normalized, page.Referer(detailURL, listURL))
+		switch {
+		case err != nil:
+			log.Printf("Scheduler: Failed to enqueue %s: %v", detailURL, err)
+			d.Errors++
+		case outcome.IsNew():
+			d.New++
+		case outcome == queue.ListedExisting:
+			d.Existing++
+		default:
+			d.Skipped++
+		}
+	}
+	return d
+}
+
+// startRun records a scheduler run as running (nil if it could not be recorded)
+func (s *Scheduler) startRun(plan runPlan) *models.SchedulerRun {
+	run := &models.SchedulerRun{
+		Trigger:   plan.trigger,
+		Schedule:  plan.schedule,
+		Status:    models.SchedulerRunRunning,
+		StartedAt: time.Now(),
+	}
+	if err := s.db.Create(run).Error; err != nil {
+		log.Printf("Scheduler: Failed to record run: %v", err)
+		return nil
+	}
+	return run
+}
+
+// finishRun stores the outcome of a run recorded by startRun
+func (s *Scheduler) finishRun(run *models.SchedulerRun, discoveries []ListDiscovery, enqueued int, runErr error) {
+	if run == nil {
+		return
+	}
+	if discoveries == nil {
+		discoveries = []ListDiscovery{}
+	}
+	listURLs, _ := json.Marshal(discoveries)
+
+	now := time.Now()
+	run.FinishedAt = &now
+	run.Status = models.SchedulerRunDone
+	run.Enqueued = enqueued
+	run.ListURLs = string(listURLs)
+	for _, d := range discoveries {
+		run.Discovered += d.New
+	}
+	if runErr != nil {
+		run.Status = models.SchedulerRunFailed
+		run.Error = errtext.Clean(runErr.Error())
+	}
+	if err := s.db.Save(run).Error; err != nil {
+		log.Printf("Scheduler: Failed to record run %d: %v", run.ID, err)
+	}
+}
+
+// LastRun returns the most recent scheduler run (nil if none was recorded yet)
+func (s *Scheduler) LastRun() (*RunSummary, error) {
+	var run models.SchedulerRun
+	if err := s.db.Order("started_at DESC, id DESC").First(&run).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	summary := &RunSummary{SchedulerRun: run, Discoveries: []ListDiscovery{}}
+	if run.ListURLs != "" {
+		if err := json.Unmarshal([]byte(run.ListURLs), &summary.Discoveries); err != nil {
+			return nil, err
+		}
+	}
+	return summary, nil
+}
@@ -0,0 +1,85 @@
+package scheduler
+
+import (
+	"real-estate-portal/internal/errtext"
+	"real-estate-portal/internal/events"
+	"real-estate-portal/internal/models"
+	"time"
+)
+
+// publishItemStarted reports a queue item the worker took
+func publishItemStarted(item *models.DetailScrapeQueue) {
+	events.Publish(events.Event{
+		Type:    events.ItemStarted,
+		ItemID:  item.ID,
+		Source:  item.Source,
+		URL:     item.DetailURL,
+		Attempt: item.Attempts,
+	})
+}
+
+// publishItemDone reports an item marked done; propertyID is empty when no listing was stored
+// for it (an ended listing or a building page seen for the first time)
+func publishItemDone(item *models.DetailScrapeQueue, propertyID string) {
+	events.Publish(events.Event{
+		Type:       events.ItemDone,
+		ItemID:     item.ID,
+		Source:     item.Source,
+		URL:        item.DetailURL,
+		Attempt:    item.Attempts,
+		PropertyID: propertyID,
+		Status:     item.Status,
+		ErrorClass: item.LastErrorCode, // why a done item was not scraped: skipped_fresh, delisted, building_page
+	})
+}
+
+// publishItemFailed reports an item left failed (retried at next_retry_at) or permanent_fail
+func publishItemFailed(item *models.DetailScrapeQueue, failure ScrapeFailure) {
+	events.Publish(events.Event{
+		Type:       events.ItemFailed,
+		ItemID:     item.ID,
+		Source:     item.Source,
+		URL:        item.DetailURL,
+		Attempt:    item.Attempts,
+		Status:     item.Status,
+		ErrorClass: item.LastErrorCode,
+		Failure:    string(failure),
+		Error:      item.LastError,
+		Until:      item.NextRetryAt,
+	})
+}
+
+// publishCooldown reports the worker taking no items until until
+func publishCooldown(reason string, until time.Time) {
+	events.Publish(events.Event{Type: events.CooldownEntered, Reason: reason, Until: &until})
+}
+
+// publishRunFinished reports the end of a scheduler run (run is nil if it could not be recorded)
+func publishRunFinished(plan runPlan, run *models.SchedulerRun, discoveries []ListDiscovery, enqueued int, runErr error) {
+	e := events.Event{
+		Type:     events.RunFinished,
+		Trigger:  plan.trigger,
+		Schedule: plan.schedule,
+		Status:   models.SchedulerRunDone,
+		Enqueued: enqueued,
+	}
+	for _, d := range discoveries {
+		e.Discovered += d.New
+	}
+	if run != nil {
+		e.RunID = run.ID
+	}
+	if runErr != nil {
+		e.Status = models.SchedulerRunFailed
+		e.Error = errtext.Clean(runErr.Error())
+	}
+	events.Publish(e)
+}
+
+// propertyIDOf returns the ID of a stored property, or "" for none
+func propertyIDOf(property *models.Property) string {
+	if property == nil {
+		return ""
+	}
+	return property.ID
+}
@@ -0,0 +1,151 @@
+package scheduler
+
+import (
+	"context"
+	"log"
+	"real-estate-portal/internal/models"
+	"time"
+)
+
+// defaultHealthCooldowns pause the worker after failed WAF health checks: 4h, another 4h, then
+// 12h for every further failure
+var defaultHealthCooldowns = []time.Duration{4 * time.Hour, 4 * time.Hour, 12 * time.Hour}
+
+// scrapingStateID is the single scraping_state row (inserted by migration 003)
+const scrapingStateID = 1
+
+// SetHealthCheck replaces the WAF health check (tests use a fake); call before Start
+func (w *QueueWorker) SetHealthCheck(check func(ctx context.Context) bool) {
+	w.healthCheckFn = check
+}
+
+// SetHealthCooldowns sets the pauses after 1, 2, 3+ failed health checks in a row (the last one
+// repeats; default 4h, 4h, 12h); call before Start
+func (w *QueueWorker) SetHealthCooldowns(cooldowns ...time.Duration) {
+	if len(cooldowns) > 0 {
+		w.healthCooldowns = cooldowns
+	}
+}
+
+// HealthCooldownStatus is the worker's WAF health state for status endpoints
+type HealthCooldownStatus struct {
+	Active   bool       `json:"active"`
+	Until    *time.Time `json:"until,omitempty"`
+	Failures int        `json:"failures"` // failed health checks in a row
+	Checked  bool       `json:"checked"`  // the health check passed since Start / the last cooldown
+}
+
+// HealthCooldown returns whether the worker is paused (a failed WAF health check, or a WAF error
+// on an item) and until when
+func (w *QueueWorker) HealthCooldown() HealthCooldownStatus {
+	w.cooldownMu.Lock()
+	defer w.cooldownMu.Unlock()
+
+	status := HealthCooldownStatus{Failures: w.healthFailures, Checked: w.healthChecked}
+	if time.Now().Before(w.cooldownUntil) {
+		until := w.cooldownUntil
+		status.Active = true
+		status.Until = &until
+	}
+	return status
+}
+
+// healthy reports whether items may be processed: no cooldown is running and the health check
+// passed since the last one ended. The check runs here, on the first tick after a cooldown;
+// failing it starts the next cooldown. Nothing sleeps, so Stop ends the loop at once.
+func (w *QueueWorker) healthy() bool {
+	w.cooldownMu.Lock()
+	until, checked := w.cooldownUntil, w.healthChecked
+	w.cooldownMu.Unlock()
+
+	if time.Now().Before(until) || w.ctx.Err() != nil {
+		return false
+	}
+	if checked {
+		return true
+	}
+
+	log.Println("QueueWorker: Running WAF health check...")
+	if !w.healthCheckFn(w.ctx) {
+		// Stop canceled the check: that says nothing about the site
+		if w.ctx.Err() != nil {
+			return false
+		}
+		w.enterHealthCooldown()
+		return false
+	}
+
+	log.Println("QueueWorker: Health check passed")
+	w.cooldownMu.Lock()
+	w.healthChecked = true
+	w.healthFailures = 0
+	w.cooldownUntil = time.Time{}
+	w.cooldownMu.Unlock()
+	w.saveScrapingState(func(state *models.ScrapingState) { state.RecordSuccess() })
+	return true
+}
+
+// enterHealthCooldown starts the cooldown for one more failed health check and persists it
+func (w *QueueWorker) enterHealthCooldown() {
+	w.cooldownMu.Lock()
+	cooldown := w.healthCooldowns[min(w.healthFailures, len(w.healthCooldowns)-1)]
+	w.healthFailures++
+	failures := w.healthFailures
+	w.cooldownUntil = time.Now().Add(cooldown)
+	w.healthChecked = false
+	until := w.cooldownUntil
+	w.cooldownMu.Unlock()
+
+	log.Printf("QueueWorker: WAF detected in health check (%d in a row), pausing for %v until %s",
+		failures, cooldown, until.Format(time.RFC3339))
+	w.saveScrapingState(func(state *models.ScrapingState) {
+		state.RecordFailure()
+		state.SetBlocked("WAF detected in queue worker health check", cooldown)
+	})
+	publishCooldown("WAF detected in health check", until)
+}
+
+// pause holds processing for d without a health check afterwards (a WAF error on an item: the
+// circuit breaker decides when requests go out again). A longer cooldown is kept.
+func (w *QueueWorker) pause(d time.Duration, reason string) {
+	w.cooldownMu.Lock()
+	if until := time.Now().Add(d); until.After(w.cooldownUntil) {
+		w.cooldownUntil = until
+	}
+	until := w.cooldownUntil
+	w.cooldownMu.Unlock()
+	publishCooldown(reason, until)
+}
+
+// restoreCooldown resumes a cooldown persisted in scraping_state by a previous process, so a
+// restart does not probe the site before it ends, and reopens the Yahoo circuit breaker until
+// then. A block without blocked_until (the row migration 003 inserts) is not a cooldown.
+func (w *QueueWorker) restoreCooldown() {
+	if w.scraper != nil {
+		w.scraper.CircuitBreaker().SetStore(ScrapingStateStore(w.db))
+	}
+
+	state, err := LoadScrapingState(w.db)
+	if err != nil {
+		log.Printf("QueueWorker: Failed to load scraping state: %v", err)
+		return
+	}
+	if state == nil || !state.IsBlocked || state.BlockedUntil == nil || !time.Now().Before(*state.BlockedUntil) {
+		return
+	}
+
+	if w.scraper != nil {
+		w.scraper.CircuitBreaker().Restore(*state.BlockedUntil)
+	}
+	w.cooldownMu.Lock()
+	w.cooldownUntil = *state.BlockedUntil
+	w.healthFailures = state.FailureCount
+	w.cooldownMu.Unlock()
+	log.Printf("QueueWorker: Resuming WAF cooldown until %s (%s)", state.BlockedUntil.Format(time.RFC3339), state.BlockedReason)
+}
+
+// saveScrapingState applies update to the scraping_state row (creating it if missing). A failed
+// write is logged: the in-memory cooldown still holds for this process.
+func (w *QueueWorker) saveScrapingState(update func(state *models.ScrapingState)) {
+	saveScrapingState(w.db, update)
+}
@@ -0,0 +1,197 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"real-estate-portal/internal/maintenance"
+	"real-estate-portal/internal/models"
+	"real-estate-portal/internal/queue"
+	"real-estate-portal/internal/scraper"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+// lightColumns are the property columns the light refresh enqueue needs
+var lightColumns = []string{"id", "source", "source_property_id", "detail_url", "last_seen_at"}
+
+// runLightRefresh queues light refreshes (scraper.light_refresh) of the active properties
+// unseen for longest, skipping those seen within the interval. Like the daily run it only
+// enqueues; the queue worker fetches them.
+func (s *Scheduler) runLightRefresh() error {
+	if maintenance.ReadOnly() {
+		log.Println("Scheduler: Read-only maintenance mode, skipping light refresh")
+		return maintenance.ErrReadOnly
+	}
+	cfg := s.config.Scraper.LightRefresh
+
+	var props []models.Property
+	cutoff := time.Now().Add(-cfg.Interval())
+	err := s.db.Select(lightColumns).
+		Where("status = ? AND (last_seen_at IS NULL OR last_seen_at < ?)", models.PropertyStatusActive, cutoff).
+		Order("last_seen_at ASC").
+		Limit(cfg.Limit()).
+		Find(&props).Error
+	if err != nil {
+		return err
+	}
+
+	enqueued, skipped, failed := 0, 0, 0
+	for _, prop := range props {
+		if prop.Source == "" || prop.SourcePropertyID == "" || prop.DetailURL == "" {
+			failed++
+			continue
+		}
+		outcome, err := s.queue.EnqueueLight(prop.Source, prop.SourcePropertyID, prop.DetailURL)
+		if err != nil {
+			failed++
+			log.Printf("Scheduler: Failed to enqueue light refresh of property %s: %v", prop.ID, err)
+			continue
+		}
+		if outcome == queue.EnqueueInserted {
+			enqueued++
+		} else {
+			skipped++
+		}
+	}
+
+	log.Printf("Scheduler: Light refresh enqueue completed. Candidates=%d, Enqueued=%d, SkippedExisting=%d, Errors=%d",
+		len(props), enqueued, skipped, failed)
+	return nil
+}
+
+// processLightItem runs a light refresh item (see Scraper.ScrapePropertyLight) on the source's
+// detail limiter. Items without a stored listing, sources without light support and relisting
+// redirects are turned into full scrapes instead.
+func (w *QueueWorker) processLightItem(ctx context.Context, item *models.DetailScrapeQueue, source scraper.PropertySource) {
+	known := w.knownProperty(item)
+	lightSource, ok := source.(scraper.LightSource)
+	switch {
+	case known == nil:
+		w.upgradeToFull(item, "no stored listing")
+		return
+	case !ok:
+		w.upgradeToFull(item, "source has no light refresh")
+		return
+	}
+
+	log.Printf("QueueWorker: Acquiring DetailLimiter (caller=worker-light, source=%s, id=%d)", source.Name(), item.ID)
+	if err := w.acquireDetail(ctx, source, "worker-light"); err != nil {
+		w.requeueCanceled(item, err)
+		return
+	}
+
+	result, err := lightSource.ScrapeLight(ctx, item.DetailURL)
+	if errors.Is(err, scraper.ErrDelisted) {
+		w.handleEndedListing(item, known)
+		return
+	}
+	if err != nil {
+		if ctx.Err() != nil {
+			w.requeueCanceled(item, err)
+			return
+		}
+		w.handleScrapeError(item, err)
+		return
+	}
+
+	// Redirected to a re-published listing: the stored row has to move, which takes a full scrape
+	if result.SourcePropertyID != item.SourcePropertyID {
+		w.upgradeToFull(item, fmt.Sprintf("redirected to %s", result.SourcePropertyID))
+		return
+	}
+
+	w.handleLightSuccess(context.WithoutCancel(ctx), item, known, result)
+}
+
+// handleLightSuccess records a light refresh: last_seen_at is bumped, a changed rent (unless
+// manually locked) is saved with the fee columns derived from it, and a partial snapshot is
+// taken so only the rent is compared. The listing cache is dropped by the properties callback.
+// A changed photo says more than the rent changed, so a full scrape is queued.
+func (w *QueueWorker) handleLightSuccess(ctx context.Context, item *models.DetailScrapeQueue, property *models.Property, result *scraper.LightResult) {
+	atomic.AddInt64(&w.lightRefreshed, 1)
+
+	rentChanged := result.Rent != nil && !property.IsFieldLocked("rent") &&
+		(property.Rent == nil || *property.Rent != *result.Rent)
+	imageChanged := result.ImageURL != "" && property.ImageURL != "" && result.ImageURL != property.ImageURL
+
+	property.UpdateLastSeen()
+	query := w.db.WithContext(ctx).Model(property)
+	var err error
+	if rentChanged {
+		log.Printf("QueueWorker: Light refresh id=%d property_id=%s rent %s -> %d", item.ID, property.ID, formatRent(property.Rent), *result.Rent)
+		property.Rent = result.Rent
+		// Month-based fees (敷1礼1) are stored in yen too, so they move with the rent
+		property.NormalizeFees()
+		err = query.Updates(map[string]interface{}{
+			"rent":                  property.Rent,
+			"management_fee_yen":    property.ManagementFeeYen,
+			"deposit_months":        property.DepositMonths,
+			"deposit_yen":           property.DepositYen,
+			"key_money_months":      property.KeyMoneyMonths,
+			"key_money_yen":         property.KeyMoneyYen,
+			"guarantor_deposit_yen": property.GuarantorDepositYen,
+			"security_deposit_yen":  property.SecurityDepositYen,
+			"last_seen_at":          property.LastSeenAt,
+		}).Error
+	} else {
+		// UpdateColumn: being listed at the same rent is not a content change, so updated_at stays
+		err = query.UpdateColumn("last_seen_at", property.LastSeenAt).Error
+	}
+	if err != nil {
+		log.Printf("QueueWorker: Failed to save light refresh: %v", err)
+		w.handleScrapeError(item, fmt.Errorf("database save error: %w", err))
+		return
+	}
+
+	if err := w.snapshot.CreatePartialSnapshotWithChangeDetection(property); err != nil {
+		atomic.AddInt64(&w.snapshotFailures, 1)
+		log.Printf("QueueWorker: Warning: Failed to create partial snapshot: %v", err)
+	}
+
+	item.Status = models.QueueStatusDone
+	item.LastError = ""
+	item.LastErrorCode = ""
+	completedAt := time.Now()
+	item.CompletedAt = &completedAt
+	item.NextRetryAt = nil
+
+	if err := w.db.Save(item).Error; err != nil {
+		log.Printf("QueueWorker: Failed to mark light item as done: %v", err)
+		return
+	}
+	log.Printf("QueueWorker: ✅ Completed id=%d property_id=%s (light)", item.ID, property.ID)
+	publishItemDone(item, property.ID)
+	w.recordSuccess(item)
+
+	if imageChanged {
+		log.Printf("QueueWorker: Light refresh id=%d found a new photo, queueing a full scrape", item.ID)
+		if _, err := queue.NewService(w.db).Enqueue(item.Source, item.SourcePropertyID, item.DetailURL, queue.PriorityScheduled); err != nil {
+			log.Printf("QueueWorker: Failed to queue full scrape for id=%d: %v", item.ID, err)
+		}
+	}
+}
+
+// upgradeToFull turns a light item into a full scrape and puts it back to pending without
+// counting the attempt (no request was made for it, or the light one proved insufficient)
+func (w *QueueWorker) upgradeToFull(item *models.DetailScrapeQueue, reason string) {
+	log.Printf("QueueWorker: id=%d light refresh not possible (%s), switching to a full scrape", item.ID, reason)
+	item.Light = false
+	item.Status = models.QueueStatusPending
+	if item.Attempts > 0 {
+		item.Attempts--
+	}
+	if err := w.db.Save(item).Error; err != nil {
+		log.Printf("QueueWorker: Failed to switch id=%d to a full scrape: %v", item.ID, err)
+	}
+}
+
+// formatRent formats an optional rent for logs
+func formatRent(rent *int) string {
+	if rent == nil {
+		return "none"
+	}
+	return strconv.Itoa(*rent)
+}
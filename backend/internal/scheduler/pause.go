@@ -0,0 +1,124 @@
+package scheduler
+
+import (
+	"log"
+	"real-estate-portal/internal/errtext"
+	"real-estate-portal/internal/models"
+	"time"
+)
+
+// PauseState is the worker's admin pause (POST /api/admin/worker/pause)
+type PauseState struct {
+	Paused bool       `json:"paused"`
+	Reason string     `json:"reason,omitempty"`
+	Since  *time.Time `json:"since,omitempty"`
+}
+
+// Pause stops the worker from claiming queue items until Resume; the items in progress finish.
+// Unlike Stop, the loop keeps running and the pause survives a restart: it is stored on the
+// scraping_state row. The pause applies immediately; an error means it could not be persisted.
+// Pausing again only replaces the reason.
+func (w *QueueWorker) Pause(reason string) (PauseState, error) {
+	w.pauseMu.Lock()
+	if !w.paused {
+		w.paused = true
+		w.pausedAt = time.Now()
+		w.pauseLogged = false
+	}
+	w.pausedReason = errtext.Clean(reason)
+	state := w.pauseStateLocked()
+	w.pauseMu.Unlock()
+
+	log.Printf("QueueWorker: Paused (reason: %q)", state.Reason)
+	return state, updateScrapingState(w.db, func(row *models.ScrapingState) {
+		row.WorkerPaused = true
+		row.WorkerPausedReason = state.Reason
+		row.WorkerPausedAt = state.Since
+	})
+}
+
+// Resume lets the worker claim queue items again (from the next poll tick) and clears the
+// persisted pause. Resuming a worker that is not paused is a no-op apart from the write.
+func (w *QueueWorker) Resume() (PauseState, error) {
+	w.pauseMu.Lock()
+	wasPaused := w.paused
+	w.paused = false
+	w.pausedReason = ""
+	w.pausedAt = time.Time{}
+	w.pauseLogged = false
+	w.pauseMu.Unlock()
+
+	if wasPaused {
+		log.Println("QueueWorker: Resumed")
+	}
+	return PauseState{}, updateScrapingState(w.db, func(row *models.ScrapingState) {
+		row.WorkerPaused = false
+		row.WorkerPausedReason = ""
+		row.WorkerPausedAt = nil
+	})
+}
+
+// IsPaused reports whether the worker is paused by an admin
+func (w *QueueWorker) IsPaused() bool {
+	w.pauseMu.Lock()
+	defer w.pauseMu.Unlock()
+	return w.paused
+}
+
+// Paused returns the worker's admin pause
+func (w *QueueWorker) Paused() PauseState {
+	w.pauseMu.Lock()
+	defer w.pauseMu.Unlock()
+	return w.pauseStateLocked()
+}
+
+func (w *QueueWorker) pauseStateLocked() PauseState {
+	if !w.paused {
+		return PauseState{}
+	}
+	since := w.pausedAt
+	return PauseState{Paused: true, Reason: w.pausedReason, Since: &since}
+}
+
+// skipPaused reports whether this tick is skipped for the admin pause, logging the first one
+// only
+func (w *QueueWorker) skipPaused() bool {
+	w.pauseMu.Lock()
+	defer w.pauseMu.Unlock()
+	if !w.paused {
+		return false
+	}
+	if !w.pauseLogged {
+		w.pauseLogged = true
+		log.Printf("QueueWorker: Paused since %s (reason: %q), not claiming items until resumed",
+			w.pausedAt.Format(time.RFC3339), w.pausedReason)
+	}
+	return true
+}
+
+// restorePause picks up a pause persisted by a previous process, so a restart during an
+// incident does not start scraping again. A pause set in this process is kept even if the
+// row says otherwise (its write may have failed).
+func (w *QueueWorker) restorePause() {
+	state, err := LoadScrapingState(w.db)
+	if err != nil {
+		log.Printf("QueueWorker: Failed to load scraping state: %v", err)
+		return
+	}
+	if state == nil || !state.WorkerPaused {
+		return
+	}
+
+	w.pauseMu.Lock()
+	defer w.pauseMu.Unlock()
+	if w.paused {
+		return
+	}
+	w.paused = true
+	w.pausedReason = state.WorkerPausedReason
+	w.pausedAt = state.UpdatedAt
+	if state.WorkerPausedAt != nil {
+		w.pausedAt = *state.WorkerPausedAt
+	}
+	w.pauseLogged = false
+}
@@ -0,0 +1,93 @@
+package scheduler
+
+import (
+	"fmt"
+	"log"
+	"real-estate-portal/internal/errtext"
+	"real-estate-portal/internal/models"
+	"strings"
+	"sync/atomic"
+	"time"
+	"unicode/utf8"
+)
+
+// maxReapInterval bounds how often stuck items are looked for (more often when the threshold is shorter)
+const maxReapInterval = 5 * time.Minute
+
+// SetStaleProcessingAfter sets how long an item may sit in processing without an update before
+// the reaper returns it to pending (default 30m, 0 disables the reaper); call before Start
+func (w *QueueWorker) SetStaleProcessingAfter(d time.Duration) {
+	w.staleAfter = d
+}
+
+// reapInterval is how often reapStale runs
+func (w *QueueWorker) reapInterval() time.Duration {
+	if w.staleAfter < maxReapInterval {
+		return w.staleAfter
+	}
+	return maxReapInterval
+}
+
+// reapStale returns items stranded in processing (the process died or was killed mid-item) to
+// pending, with a note appended to last_error. The attempt stays counted, so an item that keeps
+// killing the worker still runs out of retries. This worker's own items in progress are skipped:
+// a limiter wait can outlast the threshold.
+func (w *QueueWorker) reapStale() {
+	if w.staleAfter <= 0 {
+		return
+	}
+
+	note := fmt.Sprintf("recovered: stuck in processing for over %v", w.staleAfter)
+	cutoff := time.Now().Add(-w.staleAfter)
+	query := w.db.Model(&models.DetailScrapeQueue{}).
+		Where("status = ? AND updated_at < ?", models.QueueStatusProcessing, cutoff)
+	if ids := w.inFlightItems(); len(ids) > 0 {
+		query = query.Where("id NOT IN ?", ids)
+	}
+	var stale []models.DetailScrapeQueue
+	if err := query.Select("id", "last_error").Find(&stale).Error; err != nil {
+		log.Printf("QueueWorker: Failed to recover stuck items: %v", err)
+		return
+	}
+
+	var recovered int64
+	for _, item := range stale {
+		// Guarded by the same status / age check: the item may have been finished or reclaimed since the read
+		result := w.db.Model(&models.DetailScrapeQueue{}).
+			Where("id = ? AND status = ? AND updated_at < ?", item.ID, models.QueueStatusProcessing, cutoff).
+			Updates(map[string]interface{}{
+				"status":     models.QueueStatusPending,
+				"last_error": reapNote(item.LastError, note),
+			})
+		if result.Error != nil {
+			log.Printf("QueueWorker: Failed to recover stuck item %d: %v", item.ID, result.Error)
+			continue
+		}
+		recovered += result.RowsAffected
+	}
+	if recovered > 0 {
+		total := atomic.AddInt64(&w.recoveredStuck, recovered)
+		log.Printf("QueueWorker: Recovered %d item(s) stuck in processing for over %v (%d since start)",
+			recovered, w.staleAfter, total)
+	}
+}
+
+// reapNote appends note to the item's last_error, cleaned and within errtext.MaxLength: the
+// earlier text is cut to make room, and an item reaped again doesn't get the same note twice
+func reapNote(prev, note string) string {
+	prev = errtext.Clean(prev)
+	if prev == "" {
+		return note
+	}
+	if strings.HasSuffix(prev, note) {
+		return prev
+	}
+	const sep = " | "
+	if room := errtext.MaxLength() - utf8.RuneCountInString(note) - len(sep); utf8.RuneCountInString(prev) > room {
+		if room <= 0 {
+			return errtext.Clean(note)
+		}
+		prev = string([]rune(prev)[:room])
+	}
+	return prev + sep + note
+}
@@ -0,0 +1,18 @@
+package scheduler
+
+import (
+	"context"
+	"real-estate-portal/internal/models"
+)
+
+// PropertySaver stores a scraped listing with its stations and images in one transaction
+// (*database.GormDB by default)
+type PropertySaver interface {
+	SavePropertyWithStationsAndImagesContext(ctx context.Context, property *models.Property, stations []models.PropertyStation, images []models.PropertyImage) error
+}
+
+// SetPropertySaver replaces where scraped listings are saved (default: a GormDB over the
+// worker's db); call before Start
+func (w *QueueWorker) SetPropertySaver(saver PropertySaver) {
+	w.saver = saver
+}
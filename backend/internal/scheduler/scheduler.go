@@ -1,11 +1,18 @@
 package scheduler
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
 	"real-estate-portal/internal/config"
+	"real-estate-portal/internal/database"
+	"real-estate-portal/internal/maintenance"
 	"real-estate-portal/internal/models"
+	"real-estate-portal/internal/queue"
+	"real-estate-portal/internal/scraper"
 	"real-estate-portal/internal/snapshot"
+	"sync/atomic"
 	"time"
 
 	"github.com/robfig/cron/v3"
@@ -17,53 +24,156 @@ type Scheduler struct {
 	cron      *cron.Cron
 	db        *gorm.DB
 	snapshot  *snapshot.Service
+	queue     *queue.Service
+	sources   *scraper.Registry // Runs the alive checks (nil = no alive checks)
 	config    *config.Config
 	isRunning bool
+	runJobs   []ScheduledRun  // Registered by Start
+	ctx       context.Context // canceled by Stop; aborts the list crawl in progress
+	cancel    context.CancelFunc
+	inRun     atomic.Bool // a run (scheduled or manual) is in progress
+}
+
+// ErrRunInProgress is returned when a run is started while another one is still going
+var ErrRunInProgress = errors.New("scheduler run already in progress")
+
+// ScheduledRun is a registered run job, as reported by Schedules
+type ScheduledRun struct {
+	Name    string       `json:"name,omitempty"` // "" for scraper.daily_run_time
+	Cron    string       `json:"cron"`
+	NextRun *time.Time   `json:"next_run,omitempty"`
+	entry   cron.EntryID // for NextRun
 }
 
 // NewScheduler creates a new scheduler
 func NewScheduler(db *gorm.DB, cfg *config.Config) *Scheduler {
+	ctx, cancel := context.WithCancel(context.Background())
 	return &Scheduler{
 		cron:     cron.New(),
 		db:       db,
 		snapshot: snapshot.NewService(db),
+		queue:    queue.NewService(db),
 		config:   cfg,
+		ctx:      ctx,
+		cancel:   cancel,
 	}
 }
 
+// NewSchedulerWithSources creates a scheduler that also HEAD-checks long-unseen properties
+// with the source registered for their URL host before each daily enqueue
+func NewSchedulerWithSources(db *gorm.DB, cfg *config.Config, sources *scraper.Registry) *Scheduler {
+	s := NewScheduler(db, cfg)
+	s.sources = sources
+	return s
+}
+
 // Start starts the scheduler
 func (s *Scheduler) Start() error {
-	if !s.config.Scraper.DailyRunEnabled {
+	lightEnabled := s.config.Scraper.LightRefresh.Enabled
+	if !s.config.Scraper.DailyRunEnabled && !lightEnabled {
 		log.Println("Scheduler: Daily run is disabled in configuration")
 		return nil
 	}
+	if s.ctx.Err() != nil {
+		s.ctx, s.cancel = context.WithCancel(context.Background())
+	}
 
-	// Parse daily run time (HH:MM format in config)
-	cronSpec := s.parseDailyRunTime(s.config.Scraper.DailyRunTime)
-
-	// Add daily scraping job
-	_, err := s.cron.AddFunc(cronSpec, func() {
-		log.Println("Scheduler: Starting daily scraping job...")
-		if err := s.runDailyScraping(); err != nil {
-			log.Printf("Scheduler: Daily scraping failed: %v", err)
-		} else {
-			log.Println("Scheduler: Daily scraping completed successfully")
+	if s.config.Scraper.DailyRunEnabled {
+		if err := s.addRunJobs(); err != nil {
+			return err
 		}
-	})
+	}
 
-	if err != nil {
-		return err
+	// Light refreshes run on their own, shorter cadence
+	if lightEnabled {
+		interval := s.config.Scraper.LightRefresh.Interval()
+		if _, err := s.cron.AddFunc(fmt.Sprintf("@every %s", interval), func() {
+			if err := s.runLightRefresh(); err != nil {
+				log.Printf("Scheduler: Light refresh failed: %v", err)
+			}
+		}); err != nil {
+			return err
+		}
+		log.Printf("Scheduler: Light refresh every %s", interval)
 	}
 
 	s.cron.Start()
 	s.isRunning = true
-	log.Printf("Scheduler: Started with daily run at %s (cron: %s)", s.config.Scraper.DailyRunTime, cronSpec)
+	log.Println("Scheduler: Started")
 
 	return nil
 }
 
+// addRunJobs registers one cron job per scheduler.schedules entry, or the single
+// scraper.daily_run_time job when no schedules are configured
+func (s *Scheduler) addRunJobs() error {
+	schedules := s.config.Scheduler.Schedules
+	if len(schedules) == 0 {
+		// Parse daily run time (HH:MM format in config)
+		cronSpec := s.parseDailyRunTime(s.config.Scraper.DailyRunTime)
+		plan := runPlan{
+			trigger:    runTriggerScheduled,
+			listURLs:   s.config.Scheduler.ListURLs,
+			maxEnqueue: config.DefaultMaxEnqueue,
+		}
+		id, err := s.cron.AddFunc(cronSpec, func() { s.runScheduled(plan) })
+		if err != nil {
+			return err
+		}
+		s.runJobs = append(s.runJobs, ScheduledRun{Cron: cronSpec, entry: id})
+		log.Printf("Scheduler: Daily run at %s (cron: %s)", s.config.Scraper.DailyRunTime, cronSpec)
+		return nil
+	}
+
+	for _, schedule := range schedules {
+		plan := runPlan{
+			trigger:    runTriggerScheduled,
+			schedule:   schedule.Label(),
+			listURLs:   schedule.Targets(s.config.Scheduler.ListURLs),
+			maxEnqueue: schedule.Enqueue(),
+		}
+		id, err := s.cron.AddFunc(schedule.CronSpec(), func() { s.runScheduled(plan) })
+		if err != nil {
+			return fmt.Errorf("schedule %q: %w", plan.schedule, err)
+		}
+		s.runJobs = append(s.runJobs, ScheduledRun{Name: plan.schedule, Cron: schedule.CronSpec(), entry: id})
+		log.Printf("Scheduler: Schedule %q at %s (cron: %s, %d list URLs, max enqueue %d)",
+			plan.schedule, schedule.At, schedule.CronSpec(), len(plan.listURLs), plan.maxEnqueue)
+	}
+	return nil
+}
+
+// runScheduled is the cron job of a run plan. A tick that comes while the previous run (or a
+// manual one) is still crawling is skipped.
+func (s *Scheduler) runScheduled(plan runPlan) {
+	if s.inRun.Load() {
+		log.Printf("Scheduler: %s scraping skipped, a run is still in progress", plan.label())
+		return
+	}
+	log.Printf("Scheduler: Starting %s scraping job...", plan.label())
+	if err := s.runDailyScraping(plan); err != nil {
+		log.Printf("Scheduler: %s scraping failed: %v", plan.label(), err)
+	} else {
+		log.Printf("Scheduler: %s scraping completed successfully", plan.label())
+	}
+}
+
+// Schedules returns the registered run jobs with their next run time (empty when scheduled
+// runs are disabled)
+func (s *Scheduler) Schedules() []ScheduledRun {
+	jobs := make([]ScheduledRun, 0, len(s.runJobs))
+	for _, job := range s.runJobs {
+		if next := s.cron.Entry(job.entry).Next; s.isRunning && !next.IsZero() {
+			job.NextRun = &next
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs
+}
+
 // Stop stops the scheduler
 func (s *Scheduler) Stop() {
+	s.cancel()
 	if s.isRunning {
 		s.cron.Stop()
 		s.isRunning = false
@@ -71,96 +181,169 @@ func (s *Scheduler) Stop() {
 	}
 }
 
-// runDailyScraping executes the daily scraping routine
+// enqueueColumns are the property columns the daily enqueue needs
+var enqueueColumns = []string{"id", "source", "source_property_id", "detail_url"}
+
+// Triggers recorded on scheduler runs
+const (
+	runTriggerScheduled = "scheduled"
+	runTriggerManual    = "manual"
+)
+
+// runPlan is what one scheduler run covers
+type runPlan struct {
+	trigger    string
+	schedule   string   // scheduler.schedules name ("" for daily_run_time and manual runs)
+	listURLs   []string // list pages crawled for new listings
+	maxEnqueue int      // known listings re-enqueued
+}
+
+// label names the run in logs
+func (p runPlan) label() string {
+	if p.schedule != "" {
+		return fmt.Sprintf("Schedule %q", p.schedule)
+	}
+	return "Daily"
+}
+
+// runDailyScraping executes the daily scraping routine: new listings from the configured list
+// pages first, then up to plan.maxEnqueue known ones. The run is recorded in scheduler_runs.
 // NOTE: This ONLY enqueues URLs for processing. Actual scraping happens via queue workers.
-func (s *Scheduler) runDailyScraping() error {
-	// Get all active properties to re-scrape
-	var properties []models.Property
-	if err := s.db.Where("status = ?", models.PropertyStatusActive).Find(&properties).Error; err != nil {
-		return err
+// Only one run goes at a time: another one returns ErrRunInProgress.
+func (s *Scheduler) runDailyScraping(plan runPlan) (err error) {
+	if !s.inRun.CompareAndSwap(false, true) {
+		return ErrRunInProgress
+	}
+	defer s.inRun.Store(false)
+	if maintenance.ReadOnly() {
+		log.Println("Scheduler: Read-only maintenance mode, skipping enqueue")
+		return maintenance.ErrReadOnly
 	}
 
-	log.Printf("Scheduler: Found %d active properties to enqueue for update", len(properties))
+	run := s.startRun(plan)
+	var discoveries []ListDiscovery
+	enqueuedCount := 0
+	defer func() {
+		s.finishRun(run, discoveries, enqueuedCount, err)
+		publishRunFinished(plan, run, discoveries, enqueuedCount, err)
+	}()
 
-	// Limit: Don't overwhelm the queue (max 100 per scheduler run)
-	maxEnqueue := 100
-	if len(properties) > maxEnqueue {
-		log.Printf("Scheduler: Limiting to %d properties (total: %d)", maxEnqueue, len(properties))
-		properties = properties[:maxEnqueue]
+	// New listings: crawl the plan's list pages
+	discoveries = s.discoverListURLs(s.ctx, plan.listURLs)
+	if err = s.ctx.Err(); err != nil {
+		return err
 	}
 
-	enqueuedCount := 0
+	// Delisted properties found by a cheap HEAD are removed before they take a queue slot
+	if s.sources != nil && s.config.Scraper.AliveCheck.IsEnabled() {
+		s.runAliveChecks()
+	}
+
+	// Limit: Don't overwhelm the queue
+	maxEnqueue := plan.maxEnqueue
+
 	skippedExisting := 0
 	skippedDone := 0
 	errorCount := 0
+	scanned := 0
+
+	// Stream active properties in pages instead of loading the whole active set,
+	// and stop as soon as enough have been enqueued
+	gormDB := database.NewGormDBFromDB(s.db)
+	err = gormDB.StreamActiveProperties(500, enqueueColumns, func(batch []models.Property) error {
+		for _, prop := range batch {
+			scanned++
+			switch s.enqueueProperty(prop) {
+			case enqueueAdded:
+				enqueuedCount++
+			case enqueueSkippedExisting:
+				skippedExisting++
+			case enqueueSkippedDone:
+				skippedDone++
+			default:
+				errorCount++
+			}
 
-	// Enqueue each property URL (no direct scraping!)
-	for i, prop := range properties {
-		// Extract source_property_id from the property
-		// For Yahoo: it's stored in SourcePropertyID field
-		if prop.Source == "" || prop.SourcePropertyID == "" || prop.DetailURL == "" {
-			log.Printf("Scheduler: [%d/%d] Skipping property %s (missing source/URL)", i+1, len(properties), prop.ID)
-			errorCount++
-			continue
+			if enqueuedCount >= maxEnqueue {
+				log.Printf("Scheduler: Reached enqueue limit %d after scanning %d properties", maxEnqueue, scanned)
+				return database.ErrStopStream
+			}
 		}
+		log.Printf("Scheduler: Progress: %d scanned, %d enqueued", scanned, enqueuedCount)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
 
-		// Check if already in queue with pending/processing status
-		var existingQueue models.DetailScrapeQueue
-		result := s.db.Where("source = ? AND source_property_id = ? AND status IN ?",
-			prop.Source, prop.SourcePropertyID, []string{models.QueueStatusPending, models.QueueStatusProcessing}).
-			First(&existingQueue)
+	log.Printf("Scheduler: Daily enqueue completed. Scanned=%d, Enqueued=%d, SkippedExisting=%d, SkippedDone=%d, Errors=%d",
+		scanned, enqueuedCount, skippedExisting, skippedDone, errorCount)
 
-		if result.Error == nil {
-			// Already in queue, skip
-			skippedExisting++
-			continue
-		}
+	return nil
+}
 
-		// Check if recently completed (within 12 hours) to avoid re-scraping too soon
-		var recentDone models.DetailScrapeQueue
-		twelveHoursAgo := time.Now().Add(-12 * time.Hour)
-		resultDone := s.db.Where("source = ? AND source_property_id = ? AND status = ? AND updated_at > ?",
-			prop.Source, prop.SourcePropertyID, models.QueueStatusDone, twelveHoursAgo).
-			First(&recentDone)
-
-		if resultDone.Error == nil {
-			// Recently completed, skip
-			skippedDone++
-			continue
-		}
+// enqueueResult is the outcome of enqueueProperty
+type enqueueResult int
 
-		// Enqueue for processing
-		queue := models.DetailScrapeQueue{
-			Source:           prop.Source,
-			SourcePropertyID: prop.SourcePropertyID,
-			DetailURL:        prop.DetailURL,
-			Status:           models.QueueStatusPending,
-			Priority:         1, // Scheduled updates have priority 1 (manual can be higher)
-		}
+const (
+	enqueueAdded enqueueResult = iota
+	enqueueSkippedExisting
+	enqueueSkippedDone
+	enqueueFailed
+)
 
-		if err := s.db.Create(&queue).Error; err != nil {
-			log.Printf("Scheduler: [%d/%d] Failed to enqueue property %s: %v", i+1, len(properties), prop.ID, err)
-			errorCount++
-			continue
-		}
+// enqueueProperty adds one property's detail URL to the queue unless it was completed
+// within the last 12 hours. A row that is already pending/processing is kept (with its
+// priority raised to the scheduled priority if lower) rather than duplicated.
+func (s *Scheduler) enqueueProperty(prop models.Property) enqueueResult {
+	// Extract source_property_id from the property
+	// For Yahoo: it's stored in SourcePropertyID field
+	if prop.Source == "" || prop.SourcePropertyID == "" || prop.DetailURL == "" {
+		log.Printf("Scheduler: Skipping property %s (missing source/URL)", prop.ID)
+		return enqueueFailed
+	}
 
-		enqueuedCount++
+	// Check if recently completed (within 12 hours) to avoid re-scraping too soon
+	var recentDone models.DetailScrapeQueue
+	twelveHoursAgo := time.Now().Add(-12 * time.Hour)
+	resultDone := s.db.Where("source = ? AND source_property_id = ? AND status = ? AND updated_at > ?",
+		prop.Source, prop.SourcePropertyID, models.QueueStatusDone, twelveHoursAgo).
+		First(&recentDone)
 
-		if (i+1)%50 == 0 {
-			log.Printf("Scheduler: Progress: %d/%d processed", i+1, len(properties))
-		}
+	if resultDone.Error == nil {
+		// Recently completed, skip
+		return enqueueSkippedDone
 	}
 
-	log.Printf("Scheduler: Daily enqueue completed. Enqueued=%d, SkippedExisting=%d, SkippedDone=%d, Errors=%d",
-		enqueuedCount, skippedExisting, skippedDone, errorCount)
+	// Scheduled updates have priority 1 (manual can be higher)
+	outcome, err := s.queue.Enqueue(prop.Source, prop.SourcePropertyID, prop.DetailURL, queue.PriorityScheduled)
+	if err != nil {
+		log.Printf("Scheduler: Failed to enqueue property %s: %v", prop.ID, err)
+		return enqueueFailed
+	}
 
-	return nil
+	switch outcome {
+	case queue.EnqueueInserted, queue.EnqueueRevived:
+		return enqueueAdded
+	default:
+		// Already in queue
+		return enqueueSkippedExisting
+	}
+}
+
+// RunInProgress reports whether a scheduled or manual run is going
+func (s *Scheduler) RunInProgress() bool {
+	return s.inRun.Load()
 }
 
 // RunNow immediately executes the daily scraping job (for manual trigger)
 func (s *Scheduler) RunNow() error {
 	log.Println("Scheduler: Manual trigger - starting scraping job...")
-	return s.runDailyScraping()
+	return s.runDailyScraping(runPlan{
+		trigger:    runTriggerManual,
+		listURLs:   s.config.Scheduler.ListURLs,
+		maxEnqueue: config.DefaultMaxEnqueue,
+	})
 }
 
 // parseDailyRunTime converts HH:MM format to cron specification
@@ -0,0 +1,89 @@
+package scheduler
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"real-estate-portal/internal/models"
+	"real-estate-portal/internal/scraper"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// scrapingStateStore persists circuit breaker blocks in the scraping_state row
+type scrapingStateStore struct {
+	db *gorm.DB
+}
+
+// ScrapingStateStore returns a scraper.BreakerStore writing to scraping_state: a breaker
+// opening marks the row blocked until its retry time, a recovery clears it
+func ScrapingStateStore(db *gorm.DB) scraper.BreakerStore {
+	return scrapingStateStore{db: db}
+}
+
+func (s scrapingStateStore) SaveBlock(reason string, until time.Time) {
+	saveScrapingState(s.db, func(state *models.ScrapingState) {
+		state.SetBlocked(reason, time.Until(until))
+	})
+}
+
+func (s scrapingStateStore) ClearBlock() {
+	saveScrapingState(s.db, func(state *models.ScrapingState) {
+		state.ClearBlock()
+		state.LastAttempt = time.Now()
+	})
+}
+
+// LoadScrapingState returns the scraping_state row (nil if there is none yet)
+func LoadScrapingState(db *gorm.DB) (*models.ScrapingState, error) {
+	var state models.ScrapingState
+	if err := db.First(&state, scrapingStateID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &state, nil
+}
+
+// RestoreBreakerState reopens breaker until the block persisted in scraping_state ends (so
+// CanProceed keeps refusing after a restart) and makes it persist its blocks from now on
+func RestoreBreakerState(db *gorm.DB, breaker *scraper.CircuitBreaker) {
+	breaker.SetStore(ScrapingStateStore(db))
+
+	state, err := LoadScrapingState(db)
+	if err != nil {
+		log.Printf("Scheduler: Failed to load scraping state: %v", err)
+		return
+	}
+	if state != nil && state.IsBlocked && state.BlockedUntil != nil {
+		breaker.Restore(*state.BlockedUntil)
+	}
+}
+
+// saveScrapingState applies update to the scraping_state row (creating it if missing). A failed
+// write is logged: the in-memory block still holds for this process.
+func saveScrapingState(db *gorm.DB, update func(state *models.ScrapingState)) {
+	if err := updateScrapingState(db, update); err != nil {
+		log.Printf("Scheduler: %v", err)
+	}
+}
+
+// updateScrapingState is saveScrapingState for callers that report the error
+func updateScrapingState(db *gorm.DB, update func(state *models.ScrapingState)) error {
+	var state models.ScrapingState
+	err := db.First(&state, scrapingStateID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		state, err = models.ScrapingState{ID: scrapingStateID}, nil
+	}
+	if err != nil {
+		return fmt.Errorf("load scraping state: %w", err)
+	}
+
+	update(&state)
+	if err := db.Save(&state).Error; err != nil {
+		return fmt.Errorf("save scraping state: %w", err)
+	}
+	return nil
+}
@@ -1,185 +1,907 @@
 package scheduler
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
+	"real-estate-portal/internal/config"
 	"real-estate-portal/internal/database"
+	"real-estate-portal/internal/errtext"
+	"real-estate-portal/internal/maintenance"
 	"real-estate-portal/internal/models"
+	"real-estate-portal/internal/queue"
+	"real-estate-portal/internal/ratelimit"
 	"real-estate-portal/internal/scraper"
 	"real-estate-portal/internal/snapshot"
-	"strings"
+	"real-estate-portal/internal/tracing"
+	"slices"
+	"sort"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
 	"gorm.io/gorm"
 )
 
 // QueueWorker processes detail_scrape_queue items with rate limiting and WAF protection
 type QueueWorker struct {
 	db                *gorm.DB
-	scraper           *scraper.Scraper
+	scraper           *scraper.Scraper  // Yahoo scraper; also runs the WAF health check
+	sources           *scraper.Registry // Items are dispatched to the source registered for their URL host
 	snapshot          *snapshot.Service
+	saver             PropertySaver // Saves each scraped property with its stations and images
+	runMu             sync.Mutex    // Serializes Start and Stop
+	started           bool          // Start was called at least once
 	stopChan          chan struct{}
-	isRunning         bool
+	done              chan struct{}   // closed when run returns
+	ctx               context.Context // canceled by Stop; aborts the item in progress
+	cancel            context.CancelFunc
+	isRunning         atomic.Bool
 	pollInterval      time.Duration
-	maxConcurrency    int
-	consecutiveSuccess int // Track consecutive successes for preventive cooldown
+	maxConcurrency    int // Items processed in parallel, each still gated by its source's DetailLimiter
+	batchSize         int // Items claimed per poll tick, worked through by the free slots
+	minRefetch        time.Duration
+	snapshotFailures  int64 // Snapshots that failed after a successful scrape (see /api/admin/reports/snapshot-gaps)
+	notModified       int64 // Re-scrapes answered 304 Not Modified (counted by DetailLimiter, not parsed)
+	delisted          int64 // Items whose HEAD check answered 404/410 (no detail scrape)
+	endedPages        int64 // Items whose detail page was the 掲載終了 notice (nothing saved)
+	freshSkipped      int64 // Items done without a request: fetched within minRefetch
+	lightRefreshed    int64 // Light items confirmed listed from meta tags and rent (partial snapshot)
+	buildingsExpanded int64 // Items whose page was a building page (its units were queued instead)
+	recoveredStuck    int64 // Items the reaper returned from processing to pending
+	staleAfter        time.Duration
+	priorityAging     time.Duration // Waiting this long adds one priority level when picking items (0 = off)
+
+	adaptive map[string]*ratelimit.AdaptiveDetailLimiter // Replace these sources' fixed detail limiters (see SetAdaptiveLimiter)
+
+	healthCheckFn   func(ctx context.Context) bool // WAF health check (default: GET the Yahoo rent top page)
+	healthCooldowns []time.Duration                // Pauses after 1, 2, 3+ failed health checks in a row
+	cooldownMu      sync.Mutex                     // Guards the fields below (read by GetQueueStats)
+	cooldownUntil   time.Time                      // No items are processed before this
+	healthFailures  int                            // Failed health checks in a row
+	healthChecked   bool                           // The health check passed since Start / the last cooldown
+
+	pauseMu      sync.Mutex // Guards the admin pause below (see Pause)
+	paused       bool       // No items are claimed until Resume
+	pausedReason string
+	pausedAt     time.Time
+	pauseLogged  bool // The first skipped tick was logged
+
+	shutdownGrace time.Duration // How long Stop lets the items in progress finish before canceling them
+	inFlightMu    sync.Mutex
+	inFlight      map[int64]struct{} // Queue items claimed and not finished yet
+	lanes         int                // Goroutines working through claimed items (at most maxConcurrency)
+	items         sync.WaitGroup     // One per slot working through a batch; run waits for them before closing done
 }
 
-// NewQueueWorker creates a new queue worker
+// NewQueueWorker creates a new queue worker with the built-in scraper settings, pacing and
+// tripping Yahoo's shared list limiter and circuit breaker
 func NewQueueWorker(db *gorm.DB) *QueueWorker {
-	return &QueueWorker{
-		db:             db,
-		scraper:        scraper.NewScraper(),
-		snapshot:       snapshot.NewService(db),
-		stopChan:       make(chan struct{}),
-		pollInterval:   30 * time.Second, // Check queue every 30 seconds
-		maxConcurrency: 1,                // Process 1 at a time (strict rate limiting)
+	limiter, breaker := scraper.SharedLimits("yahoo", scraper.YahooHost)
+	return NewQueueWorkerWithScraper(db, scraper.NewScraperWithLimiter(scraper.DefaultScraperConfig(), limiter, breaker))
+}
+
+// NewQueueWorkerWithScraper creates a queue worker that scrapes (and health-checks) with s,
+// so configured User-Agents and headers apply to the worker too. Only Yahoo URLs are scraped.
+func NewQueueWorkerWithScraper(db *gorm.DB, s *scraper.Scraper) *QueueWorker {
+	return NewQueueWorkerWithSources(db, s, scraper.NewRegistry(s))
+}
+
+// NewQueueWorkerWithSources creates a queue worker that scrapes each item with the source
+// registered for its URL host; s (Yahoo) runs the WAF health check
+func NewQueueWorkerWithSources(db *gorm.DB, s *scraper.Scraper, sources *scraper.Registry) *QueueWorker {
+	ctx, cancel := context.WithCancel(context.Background())
+	w := &QueueWorker{
+		db:              db,
+		scraper:         s,
+		sources:         sources,
+		snapshot:        snapshot.NewService(db),
+		saver:           database.NewGormDBFromDB(db),
+		stopChan:        make(chan struct{}),
+		done:            make(chan struct{}),
+		ctx:             ctx,
+		cancel:          cancel,
+		pollInterval:    30 * time.Second, // Check queue every 30 seconds
+		maxConcurrency:  1,                // Process 1 at a time (strict rate limiting)
+		batchSize:       1,
+		minRefetch:      config.DefaultMinRefetchInterval,
+		staleAfter:      config.DefaultStaleProcessingAfter,
+		priorityAging:   config.DefaultPriorityAging,
+		healthCooldowns: defaultHealthCooldowns,
+		inFlight:        make(map[int64]struct{}),
 	}
+	w.healthCheckFn = w.healthCheck
+	return w
 }
 
-// Start starts the queue worker
-func (w *QueueWorker) Start() {
-	if w.isRunning {
-		log.Println("QueueWorker: Already running")
-		return
+// NewQueueWorkerWithConfig creates a queue worker like NewQueueWorkerWithSources, with the poll
+// interval, concurrency, batch size, shutdown grace, stuck-item threshold and priority aging from
+// the queue_worker config section
+func NewQueueWorkerWithConfig(db *gorm.DB, s *scraper.Scraper, sources *scraper.Registry, cfg config.QueueWorkerConfig) *QueueWorker {
+	w := NewQueueWorkerWithSources(db, s, sources)
+	w.SetPollInterval(cfg.PollInterval())
+	w.SetConcurrency(cfg.Concurrency(), cfg.Batch())
+	w.SetShutdownGrace(cfg.ShutdownGrace())
+	w.SetStaleProcessingAfter(cfg.StaleProcessingAfter())
+	w.SetPriorityAging(cfg.PriorityAging())
+	return w
+}
+
+// SetMinRefetchInterval sets how recently a listing may have been fetched before its queue
+// item is done without a request (0 disables the check); call before Start
+func (w *QueueWorker) SetMinRefetchInterval(d time.Duration) {
+	w.minRefetch = d
+}
+
+// SetPollInterval sets how often the queue is polled (default 30s); call before Start
+func (w *QueueWorker) SetPollInterval(d time.Duration) {
+	if d > 0 {
+		w.pollInterval = d
 	}
+}
 
-	// WAF Health Check（起動前に1回だけ）
-	log.Println("QueueWorker: Running WAF health check...")
-	if !w.healthCheck() {
-		// WAF detected: enter long cooldown (4 hours minimum)
-		log.Println("QueueWorker: WAF detected in health check, entering 4-hour cooldown")
-		time.Sleep(4 * time.Hour)
+// SetConcurrency sets how many items are processed in parallel (default 1) and how many are
+// claimed per poll tick (default 1; the free slots work through them); call before Start
+func (w *QueueWorker) SetConcurrency(maxConcurrency, batchSize int) {
+	if maxConcurrency > 0 {
+		w.maxConcurrency = maxConcurrency
+	}
+	if batchSize > 0 {
+		w.batchSize = batchSize
+	}
+}
 
-		// Re-check after delay
-		if !w.healthCheck() {
-			log.Println("QueueWorker: WAF still active after 4h, entering another 4-hour cooldown")
-			time.Sleep(4 * time.Hour)
+// Errors returned by Start and Stop
+var (
+	ErrWorkerRunning    = errors.New("queue worker is already running")
+	ErrWorkerNotRunning = errors.New("queue worker is not running")
+	ErrWorkerStopping   = errors.New("queue worker is still stopping") // items of the last run are unwinding
+)
 
-			// Final check
-			if !w.healthCheck() {
-				log.Println("QueueWorker: WAF persists after 8h total, entering 12-hour cooldown")
-				time.Sleep(12 * time.Hour)
-			}
+// Start starts the queue worker and returns at once. The WAF health check runs in the worker
+// loop (see healthy): a failed one pauses processing without blocking the caller or Stop.
+// A stopped worker can be started again once the items of its last run have finished.
+func (w *QueueWorker) Start() error {
+	w.runMu.Lock()
+	defer w.runMu.Unlock()
+
+	if w.isRunning.Load() {
+		log.Println("QueueWorker: Already running")
+		return ErrWorkerRunning
+	}
+	if w.started {
+		select {
+		case <-w.done:
+		default:
+			return ErrWorkerStopping
 		}
-	} else {
-		log.Println("QueueWorker: Health check passed")
+		w.stopChan = make(chan struct{})
+		w.done = make(chan struct{})
+		w.ctx, w.cancel = context.WithCancel(context.Background())
 	}
 
-	w.isRunning = true
-	log.Printf("QueueWorker: Started (poll_interval=%v, max_concurrency=%d)", w.pollInterval, w.maxConcurrency)
+	// A pause persisted by the previous process still holds
+	w.restorePause()
+
+	w.started = true
+	w.isRunning.Store(true)
+	log.Printf("QueueWorker: Started (poll_interval=%v, max_concurrency=%d, batch_size=%d)", w.pollInterval, w.maxConcurrency, w.batchSize)
 
 	go w.run()
+	return nil
 }
 
-// Stop stops the queue worker
-func (w *QueueWorker) Stop() {
-	if !w.isRunning {
-		return
+// IsRunning reports whether the worker was started and not stopped since
+func (w *QueueWorker) IsRunning() bool {
+	return w.isRunning.Load()
+}
+
+// SetShutdownGrace sets how long Stop lets the items in progress finish on their own before
+// canceling them (default 0: cancel at once); call before Start
+func (w *QueueWorker) SetShutdownGrace(d time.Duration) {
+	w.shutdownGrace = d
+}
+
+// stopTimeout bounds how long Stop waits for the canceled items to unwind
+const stopTimeout = 10 * time.Second
+
+// Stop stops the queue worker. No new item is taken; the items in progress get the shutdown
+// grace period to finish, then are canceled (limiter wait, human-pace sleep, retries and the
+// fetch all return early) and put back to pending. Those that still do not unwind are reset to
+// pending in the table, so no item is left in processing.
+func (w *QueueWorker) Stop() error {
+	w.runMu.Lock()
+	defer w.runMu.Unlock()
+
+	if !w.isRunning.Load() {
+		return ErrWorkerNotRunning
 	}
 
 	log.Println("QueueWorker: Stopping...")
-	w.isRunning = false
+	w.isRunning.Store(false)
 	close(w.stopChan)
+
+	if ids := w.inFlightItems(); len(ids) > 0 && w.shutdownGrace > 0 {
+		log.Printf("QueueWorker: Waiting up to %v for %v to finish", w.shutdownGrace, ids)
+		select {
+		case <-w.done:
+		case <-time.After(w.shutdownGrace):
+			log.Printf("QueueWorker: %v still running after %v, canceling", w.inFlightItems(), w.shutdownGrace)
+		}
+	}
+	w.cancel()
+
+	select {
+	case <-w.done:
+	case <-time.After(stopTimeout):
+		log.Printf("QueueWorker: Items in progress did not finish within %v, giving up", stopTimeout)
+		w.releaseInFlight()
+	}
+	return nil
+}
+
+// stopping reports whether Stop was called (the loop takes no new item then)
+func (w *QueueWorker) stopping() bool {
+	select {
+	case <-w.stopChan:
+		return true
+	default:
+		return false
+	}
+}
+
+// inFlightItems returns the IDs of the queue items in progress, in ascending order
+func (w *QueueWorker) inFlightItems() []int64 {
+	w.inFlightMu.Lock()
+	defer w.inFlightMu.Unlock()
+	ids := make([]int64, 0, len(w.inFlight))
+	for id := range w.inFlight {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+// inFlightCount returns how many queue items are in progress
+func (w *QueueWorker) inFlightCount() int {
+	w.inFlightMu.Lock()
+	defer w.inFlightMu.Unlock()
+	return len(w.inFlight)
+}
+
+// releaseInFlight resets the items Stop gave up waiting for to pending, without counting the
+// attempt, unless they already left processing
+func (w *QueueWorker) releaseInFlight() {
+	ids := w.inFlightItems()
+	if len(ids) == 0 {
+		return
+	}
+	result := w.db.Model(&models.DetailScrapeQueue{}).
+		Where("id IN ? AND status = ?", ids, models.QueueStatusProcessing).
+		Updates(map[string]interface{}{
+			"status":   models.QueueStatusPending,
+			"attempts": gorm.Expr("CASE WHEN attempts > 0 THEN attempts - 1 ELSE 0 END"),
+		})
+	if result.Error != nil {
+		log.Printf("QueueWorker: Failed to return %v to pending: %v", ids, result.Error)
+		return
+	}
+	log.Printf("QueueWorker: Returned %v to pending (%d rows)", ids, result.RowsAffected)
 }
 
-// run is the main worker loop
+// run is the main worker loop. Ticks during a WAF cooldown or an admin pause are skipped; the
+// first one after a cooldown runs the health check again. Items stuck in processing are recovered at start and then
+// periodically. After Stop it waits for the items in progress.
 func (w *QueueWorker) run() {
+	defer close(w.done)
+	defer w.items.Wait()
 	ticker := time.NewTicker(w.pollInterval)
 	defer ticker.Stop()
 
+	// Items a previous process left in processing go back to pending before anything is claimed
+	var reap <-chan time.Time
+	if w.staleAfter > 0 {
+		w.reapStale()
+		reapTicker := time.NewTicker(w.reapInterval())
+		defer reapTicker.Stop()
+		reap = reapTicker.C
+	}
+
+	// A cooldown persisted by the previous process still holds; otherwise check right away
+	// (unless paused: the check is a request to the site too)
+	w.restoreCooldown()
+	if !w.IsPaused() {
+		w.healthy()
+	}
+
 	for {
 		select {
 		case <-w.stopChan:
 			log.Println("QueueWorker: Stopped")
 			return
+		case <-reap:
+			if !w.stopping() && !maintenance.ReadOnly() {
+				w.reapStale()
+			}
 		case <-ticker.C:
-			w.processNextBatch()
+			// A tick racing Stop must not start another item
+			if !w.stopping() && !w.skipPaused() && w.healthy() {
+				w.processNextBatch()
+			}
 		}
 	}
 }
 
-// processNextBatch processes the next batch of queue items
+// processNextBatch claims up to batchSize queue items and works through them in the free
+// concurrency slots: each slot gets its share of the batch in one goroutine and processes it item
+// after item. Items are claimed (marked processing) here, one after another, so the next lookup
+// cannot return an item already taken. A source's items are claimed only while its detail budget
+// for the hour has room: the rest stay pending rather than wait in processing for an hour.
 func (w *QueueWorker) processNextBatch() {
-	// Get next pending item (ordered by priority desc, then created_at asc)
+	// No queue or property writes during a maintenance window, nothing claimed while paused
+	if maintenance.ReadOnly() || w.skipPaused() {
+		return
+	}
+
+	slots := w.maxConcurrency - w.busyLanes()
+	if slots <= 0 {
+		return
+	}
+
+	// Preventive cooldown is limiter pacing: skip this tick instead of sleeping.
+	// Each source cools down on its own; only its items are held back.
+	cooling := w.coolingDown()
+	if len(cooling) > 0 && len(cooling) == len(w.sources.Sources()) {
+		log.Printf("QueueWorker: Preventive cooldown active for all sources (%v), skipping tick", cooling)
+		return
+	}
+	room := w.detailRoom()
+	held := append([]string(nil), cooling...)
+	for name, n := range room {
+		if n <= 0 && !slices.Contains(held, name) {
+			held = append(held, name)
+		}
+	}
+	if len(held) > len(cooling) && len(held) == len(w.sources.Sources()) {
+		log.Printf("QueueWorker: Hourly detail budget used up or cooling down for all sources (%v), skipping tick", held)
+		return
+	}
+	eligible := func() *gorm.DB {
+		if len(held) == 0 {
+			return w.db
+		}
+		return w.db.Where("source NOT IN ?", held)
+	}
+
+	var batch []*models.DetailScrapeQueue
+	for tries := 0; tries < w.batchSize && !w.stopping(); tries++ {
+		item := w.nextItem(eligible)
+		if item == nil {
+			break
+		}
+		if !w.claim(item) {
+			continue
+		}
+		batch = append(batch, item)
+		if n, ok := room[item.Source]; ok {
+			if room[item.Source] = n - 1; n == 1 && len(batch) < w.batchSize {
+				log.Printf("QueueWorker: Hourly detail budget of %s used up, no more of its items this tick", item.Source)
+				held = append(held, item.Source)
+			}
+		}
+	}
+	if len(batch) == 0 {
+		return
+	}
+
+	// Items are dealt out in claim order, so each slot still takes the best items first
+	if slots > len(batch) {
+		slots = len(batch)
+	}
+	for lane := 0; lane < slots; lane++ {
+		var share []*models.DetailScrapeQueue
+		for i := lane; i < len(batch); i += slots {
+			share = append(share, batch[i])
+		}
+		w.addLane(1)
+		w.items.Add(1)
+		go func() {
+			defer w.items.Done()
+			defer w.addLane(-1)
+			w.processShare(share)
+		}()
+	}
+}
+
+// processShare processes one slot's items of a batch in order. Once the worker stops, is paused
+// or cools down, the items not started yet go back to pending without counting an attempt.
+func (w *QueueWorker) processShare(share []*models.DetailScrapeQueue) {
+	for i, item := range share {
+		if reason := w.holdReason(); reason != "" {
+			for _, rest := range share[i:] {
+				w.requeueCanceled(rest, errors.New(reason))
+				w.finish(rest.ID)
+			}
+			return
+		}
+		w.processQueueItem(item)
+		w.finish(item.ID)
+	}
+}
+
+// holdReason says why the rest of a batch must not be started ("" when it may)
+func (w *QueueWorker) holdReason() string {
+	w.cooldownMu.Lock()
+	until := w.cooldownUntil
+	w.cooldownMu.Unlock()
+	switch {
+	case w.stopping():
+		return "worker stopping"
+	case w.IsPaused():
+		return "worker paused"
+	case time.Now().Before(until):
+		return "worker cooling down"
+	}
+	return ""
+}
+
+// addLane counts a goroutine starting (+1) or done with (-1) its share of a batch
+func (w *QueueWorker) addLane(delta int) {
+	w.inFlightMu.Lock()
+	w.lanes += delta
+	w.inFlightMu.Unlock()
+}
+
+// busyLanes returns how many concurrency slots are working through a batch
+func (w *QueueWorker) busyLanes() int {
+	w.inFlightMu.Lock()
+	defer w.inFlightMu.Unlock()
+	return w.lanes
+}
+
+// nextItem returns the next eligible queue item (pending first, then failed items whose retry
+// time has passed; each by aged priority desc, then created_at asc), or nil
+func (w *QueueWorker) nextItem(eligible func() *gorm.DB) *models.DetailScrapeQueue {
 	var queueItem models.DetailScrapeQueue
 	now := time.Now()
 
-	// Priority 1: Try to get a pending item first
-	result := w.db.Where("status = ?", models.QueueStatusPending).
-		Order("priority DESC, created_at ASC").
-		First(&queueItem)
+	// Priority 1: Try to get a pending item first. Take, not First: First's ORDER BY id would
+	// replace the priority aging expression.
+	result := eligible().Where("status = ?", models.QueueStatusPending).
+		Order(w.queueOrder(now)).
+		Take(&queueItem)
 
 	// Priority 2: If no pending items, try failed items with retry time passed
 	if result.Error == gorm.ErrRecordNotFound {
-		result = w.db.Where("status = ? AND next_retry_at IS NOT NULL AND next_retry_at <= ?", models.QueueStatusFailed, now).
-			Order("priority DESC, created_at ASC").
-			First(&queueItem)
+		result = eligible().Where("status = ? AND next_retry_at IS NOT NULL AND next_retry_at <= ?", models.QueueStatusFailed, now).
+			Order(w.queueOrder(now)).
+			Take(&queueItem)
 	}
 
 	if result.Error != nil {
 		if result.Error != gorm.ErrRecordNotFound {
 			log.Printf("QueueWorker: Error fetching next queue item: %v", result.Error)
 		}
-		return
+		return nil
 	}
+	return &queueItem
+}
 
-	// Process this item
-	w.processQueueItem(&queueItem)
+// claim marks item processing, counts the attempt and records it as in flight (until finish). The update only
+// applies while the item still has the status it was read with, so of two workers polling the
+// same queue only one takes it; false means it failed or another worker was first.
+func (w *QueueWorker) claim(item *models.DetailScrapeQueue) bool {
+	result := w.db.Model(&models.DetailScrapeQueue{}).
+		Where("id = ? AND status = ?", item.ID, item.Status).
+		Updates(map[string]interface{}{
+			"status":   models.QueueStatusProcessing,
+			"attempts": gorm.Expr("attempts + 1"),
+		})
+	if result.Error != nil {
+		log.Printf("QueueWorker: Failed to update status to processing: %v", result.Error)
+		return false
+	}
+	if result.RowsAffected == 0 {
+		log.Printf("QueueWorker: id=%d was claimed by another worker, skipping", item.ID)
+		return false
+	}
+	item.Status = models.QueueStatusProcessing
+	item.Attempts++
+	w.inFlightMu.Lock()
+	w.inFlight[item.ID] = struct{}{}
+	w.inFlightMu.Unlock()
+	return true
 }
 
-// processQueueItem processes a single queue item
+// finish removes a processed item from the in-flight set
+func (w *QueueWorker) finish(id int64) {
+	w.inFlightMu.Lock()
+	delete(w.inFlight, id)
+	w.inFlightMu.Unlock()
+}
+
+// processQueueItem processes a single queue item already claimed by processNextBatch
 func (w *QueueWorker) processQueueItem(item *models.DetailScrapeQueue) {
-	log.Printf("QueueWorker: Processing id=%d url=%s attempt=%d", item.ID, item.DetailURL, item.Attempts+1)
+	ctx, span := tracing.Start(w.ctx, "worker.processQueueItem",
+		attribute.Int64("queue.item_id", item.ID),
+		attribute.String("queue.source_property_id", item.SourcePropertyID),
+	)
+	defer span.End()
 
-	// Mark as processing
-	item.Status = models.QueueStatusProcessing
-	item.Attempts++
-	if err := w.db.Save(item).Error; err != nil {
-		log.Printf("QueueWorker: Failed to update status to processing: %v", err)
+	log.Printf("QueueWorker: Processing id=%d url=%s attempt=%d", item.ID, item.DetailURL, item.Attempts)
+	publishItemStarted(item)
+
+	// The site this URL belongs to (an unknown host fails permanently without a request)
+	source, err := w.sources.ForURL(item.DetailURL)
+	if err != nil {
+		w.handleScrapeError(item, err)
 		return
 	}
+	span.SetAttributes(attribute.String("queue.source", source.Name()))
 
-	// CRITICAL: Apply DetailLimiter (5 per hour max)
+	// Fetched moments ago (a duplicate enqueue or an overlapping /api/scrape): no request at all
+	if known := w.knownProperty(item); known != nil && w.isFresh(item, known) {
+		w.handleFresh(item, known)
+		return
+	}
+
+	// Light refresh: one GET read for meta tags and rent, no HEAD check first
+	if item.Light {
+		w.processLightItem(ctx, item, source)
+		return
+	}
+
+	// A cheap HEAD first: a delisted page fails permanently without spending detail budget.
+	// A failed check proves nothing, so the detail scrape goes ahead.
+	if checker, ok := source.(scraper.AliveChecker); ok {
+		alive, status, err := checker.CheckAliveContext(ctx, item.DetailURL)
+		if ctx.Err() != nil {
+			w.requeueCanceled(item, ctx.Err())
+			return
+		}
+		if err == nil && !alive {
+			w.handleDelisted(item, status)
+			return
+		}
+		if err != nil {
+			log.Printf("QueueWorker: Alive check failed for id=%d (%v), scraping anyway", item.ID, err)
+		}
+	}
+
+	// CRITICAL: Apply the source's DetailLimiter (N per hour max)
 	// This is the ONLY place where detail pages should be scraped
-	log.Printf("QueueWorker: Acquiring DetailLimiter (caller=worker, id=%d)", item.ID)
-	scraper.DetailLimiter.Acquire("worker")
+	log.Printf("QueueWorker: Acquiring DetailLimiter (caller=worker, source=%s, id=%d)", source.Name(), item.ID)
+	_, waitSpan := tracing.Start(ctx, "ratelimit.DetailLimiter.Acquire")
+	waitStart := time.Now()
+	err = w.acquireDetail(ctx, source, "worker")
+	detailWait := time.Since(waitStart)
+	waitSpan.End()
+	if err != nil {
+		w.requeueCanceled(item, err)
+		return
+	}
 
-	// Scrape the property
-	property, err := w.scraper.ScrapeProperty(item.DetailURL)
+	// Scrape the property (conditional when the listing was fetched before)
+	known := w.knownProperty(item)
+	var validators scraper.Validators
+	if known != nil {
+		validators = scraper.ValidatorsOf(known)
+	}
+	// Items found on a list page are requested with that page as the Referer
+	result, err := source.ScrapeDetail(scraper.WithReferer(ctx, item.RefererURL), item.DetailURL, validators)
+	stats := result.Stats
+	stats.LimiterWait += detailWait
+	log.Printf("QueueWorker: Scrape stats for id=%d: %s", item.ID, stats)
 
+	if errors.Is(err, scraper.ErrNotModified) && known != nil {
+		w.handleNotModified(context.WithoutCancel(ctx), item, known)
+		return
+	}
+	if errors.Is(err, scraper.ErrDelisted) {
+		w.handleEndedListing(item, known)
+		return
+	}
+	var building *scraper.BuildingPageError
+	if errors.As(err, &building) {
+		w.handleBuildingPage(item, known, building)
+		return
+	}
 	if err != nil {
+		tracing.RecordError(span, err)
+		if ctx.Err() != nil {
+			w.requeueCanceled(item, err)
+			return
+		}
 		w.handleScrapeError(item, err)
 		return
 	}
 
-	// Get stations from scraper (extracted during scraping)
-	stations := w.scraper.GetLastStationsAsModels(property.ID)
+	// The page redirected to a re-published listing: move the stored row to the new ID first
+	// so the save below updates it instead of inserting a duplicate
+	property := result.Property
+	if redirect := result.Redirect; redirect != nil {
+		log.Printf("QueueWorker: id=%d redirected %s -> %s", item.ID, redirect.FromID, redirect.ToID)
+		if err := database.NewGormDBFromDB(w.db).RecordPropertyRedirect(context.WithoutCancel(ctx),
+			property.Source, redirect.FromID, redirect.ToID, redirect.ToURL); err != nil {
+			log.Printf("QueueWorker: Failed to record redirect for id=%d: %v", item.ID, err)
+		}
+	}
+
+	// Success: save property with stations/images and mark queue item as done
+	// (a page already fetched is saved even if Stop arrives meanwhile)
+	w.handleScrapeSuccess(context.WithoutCancel(ctx), item, property, result.Stations, result.Images)
+}
+
+// knownProperty returns the active property already stored for item, or nil
+func (w *QueueWorker) knownProperty(item *models.DetailScrapeQueue) *models.Property {
+	var existing models.Property
+	err := w.db.Where("source = ? AND source_property_id = ? AND status = ?",
+		item.Source, item.SourcePropertyID, models.PropertyStatusActive).First(&existing).Error
+	if err != nil {
+		return nil
+	}
+	return &existing
+}
 
-	// Success: save property with stations and mark queue item as done
-	w.handleScrapeSuccess(item, property, stations)
+// isFresh reports whether property was fetched within minRefetch and item does not force a scrape
+func (w *QueueWorker) isFresh(item *models.DetailScrapeQueue, property *models.Property) bool {
+	return !item.Force && property.FetchedWithin(time.Now(), w.minRefetch)
+}
+
+// handleFresh finishes an item whose listing was fetched within minRefetch: done with a
+// skipped_fresh note, without a HEAD check, detail budget or limiter outcome. The attempt is
+// not counted since nothing was tried.
+func (w *QueueWorker) handleFresh(item *models.DetailScrapeQueue, property *models.Property) {
+	atomic.AddInt64(&w.freshSkipped, 1)
+	age := time.Since(property.FetchedAt).Round(time.Second)
+	log.Printf("QueueWorker: id=%d property_id=%s fetched %s ago - skipping (min_refetch_interval %s)", item.ID, property.ID, age, w.minRefetch)
+
+	item.Status = models.QueueStatusDone
+	if item.Attempts > 0 {
+		item.Attempts--
+	}
+	item.LastErrorCode = errtext.CodeFresh
+	item.LastError = fmt.Sprintf("skipped_fresh: fetched %s ago", age)
+	completedAt := time.Now()
+	item.CompletedAt = &completedAt
+	item.NextRetryAt = nil
+
+	if err := w.db.Save(item).Error; err != nil {
+		log.Printf("QueueWorker: Failed to mark fresh item as done: %v", err)
+	} else {
+		publishItemDone(item, property.ID)
+	}
+}
+
+// handleNotModified finishes an item whose page answered 304: the stored listing is still
+// current, so only last_seen_at is bumped and a snapshot taken (nothing is parsed or re-saved)
+func (w *QueueWorker) handleNotModified(ctx context.Context, item *models.DetailScrapeQueue, property *models.Property) {
+	atomic.AddInt64(&w.notModified, 1)
+	log.Printf("QueueWorker: 304 Not Modified for id=%d property_id=%s - marking seen without parsing", item.ID, property.ID)
+
+	// UpdateColumn: a 304 is not a content change, so updated_at stays
+	property.UpdateLastSeen()
+	if err := w.db.WithContext(ctx).Model(property).UpdateColumn("last_seen_at", property.LastSeenAt).Error; err != nil {
+		log.Printf("QueueWorker: Failed to bump last_seen_at: %v", err)
+		w.handleScrapeError(item, fmt.Errorf("database save error: %w", err))
+		return
+	}
+
+	if err := w.snapshot.CreateSnapshotWithChangeDetection(property); err != nil {
+		atomic.AddInt64(&w.snapshotFailures, 1)
+		log.Printf("QueueWorker: Warning: Failed to create snapshot: %v", err)
+	}
+
+	item.Status = models.QueueStatusDone
+	item.LastError = ""
+	item.LastErrorCode = ""
+	completedAt := time.Now()
+	item.CompletedAt = &completedAt
+	item.NextRetryAt = nil
+
+	if err := w.db.Save(item).Error; err != nil {
+		log.Printf("QueueWorker: Failed to mark item as done: %v", err)
+	} else {
+		log.Printf("QueueWorker: ✅ Completed id=%d property_id=%s (not modified)", item.ID, property.ID)
+		publishItemDone(item, property.ID)
+		w.recordSuccess(item)
+	}
+}
+
+// handleDelisted finishes an item whose HEAD check answered 404/410: permanent_fail without a
+// detail scrape (no limiter outcome either), and the stored property is marked removed with a
+// property_removed change
+func (w *QueueWorker) handleDelisted(item *models.DetailScrapeQueue, status int) {
+	atomic.AddInt64(&w.delisted, 1)
+	log.Printf("QueueWorker: HEAD %d for id=%d - delisted, marking as permanent_fail (no detail scrape)", status, item.ID)
+
+	if known := w.knownProperty(item); known != nil {
+		if err := database.NewGormDBFromDB(w.db).MarkPropertyDelisted(known.ID, status); err != nil {
+			log.Printf("QueueWorker: Failed to mark property %s removed: %v", known.ID, err)
+		}
+	}
+
+	item.Status = models.QueueStatusPermanentFail
+	item.LastErrorCode = errtext.CodeNotFound
+	item.LastError = errtext.Clean(fmt.Sprintf("%d Not Found (permanent, HEAD check): listing removed", status))
+	completedAt := time.Now()
+	item.CompletedAt = &completedAt
+	item.NextRetryAt = nil
+
+	if err := w.db.Save(item).Error; err != nil {
+		log.Printf("QueueWorker: Failed to save delisted permanent_fail status: %v", err)
+	}
+	publishItemFailed(item, FailurePermanent)
+}
+
+// handleEndedListing finishes an item whose page was the site's 掲載終了 notice (served with 200):
+// nothing is saved, the stored property is marked removed with a property_removed change, and
+// the item is done rather than retried. The site answered normally, so it counts as a success.
+func (w *QueueWorker) handleEndedListing(item *models.DetailScrapeQueue, known *models.Property) {
+	atomic.AddInt64(&w.endedPages, 1)
+	log.Printf("QueueWorker: id=%d is an ended-listing page - marking done (no save)", item.ID)
+
+	if known != nil {
+		if err := database.NewGormDBFromDB(w.db).MarkPropertyListingEnded(known.ID); err != nil {
+			log.Printf("QueueWorker: Failed to mark property %s removed: %v", known.ID, err)
+		}
+	}
+
+	item.Status = models.QueueStatusDone
+	item.LastErrorCode = errtext.CodeDelisted
+	item.LastError = "listing ended (掲載終了ページ)"
+	completedAt := time.Now()
+	item.CompletedAt = &completedAt
+	item.NextRetryAt = nil
+
+	if err := w.db.Save(item).Error; err != nil {
+		log.Printf("QueueWorker: Failed to mark ended listing as done: %v", err)
+	} else {
+		publishItemDone(item, propertyIDOf(known))
+		w.recordSuccess(item)
+	}
+}
+
+// handleBuildingPage finishes an item whose page listed several units: each unit's detail URL is
+// queued (with the building page as Referer, at the item's priority) and nothing is saved for the
+// building itself. A property stored for the building URL before is marked removed.
+func (w *QueueWorker) handleBuildingPage(item *models.DetailScrapeQueue, known *models.Property, building *scraper.BuildingPageError) {
+	atomic.AddInt64(&w.buildingsExpanded, 1)
+
+	queueService := queue.NewService(w.db)
+	queued := 0
+	for _, unit := range building.Units {
+		outcome, err := queueService.EnqueueFrom(unit.Source, unit.SourcePropertyID, unit.DetailURL, building.URL, item.Priority)
+		if err != nil {
+			log.Printf("QueueWorker: Failed to queue unit %s of id=%d: %v", unit.DetailURL, item.ID, err)
+			continue
+		}
+		if outcome == queue.EnqueueInserted || outcome == queue.EnqueueRevived {
+			queued++
+		}
+	}
+	log.Printf("QueueWorker: id=%d is a building page with %d units (%d queued)", item.ID, len(building.Units), queued)
+
+	if known != nil {
+		if err := database.NewGormDBFromDB(w.db).MarkPropertyListingEnded(known.ID); err != nil {
+			log.Printf("QueueWorker: Failed to mark building property %s removed: %v", known.ID, err)
+		}
+	}
+
+	item.Status = models.QueueStatusDone
+	item.LastErrorCode = errtext.CodeBuilding
+	item.LastError = fmt.Sprintf("building page: %d units, %d queued", len(building.Units), queued)
+	completedAt := time.Now()
+	item.CompletedAt = &completedAt
+	item.NextRetryAt = nil
+
+	if err := w.db.Save(item).Error; err != nil {
+		log.Printf("QueueWorker: Failed to mark building item as done: %v", err)
+	} else {
+		publishItemDone(item, propertyIDOf(known))
+		w.recordSuccess(item)
+	}
+}
+
+// limiterFor returns the detail limiter of item's source (Yahoo's when the host is unknown)
+func (w *QueueWorker) limiterFor(item *models.DetailScrapeQueue) *ratelimit.DetailLimiter {
+	if source, err := w.sources.ForURL(item.DetailURL); err == nil {
+		return source.Limiter()
+	}
+	return scraper.DetailLimiter
+}
+
+// requeueCanceled puts an item interrupted by Stop back to pending without counting the attempt
+// or touching the preventive cooldown run (a shutdown says nothing about the site)
+func (w *QueueWorker) requeueCanceled(item *models.DetailScrapeQueue, err error) {
+	log.Printf("QueueWorker: id=%d canceled (%v), returning to pending", item.ID, err)
+	item.Status = models.QueueStatusPending
+	if item.Attempts > 0 {
+		item.Attempts--
+	}
+	if err := w.db.Save(item).Error; err != nil {
+		log.Printf("QueueWorker: Failed to return canceled item to pending: %v", err)
+	}
+}
+
+// ScrapeFailure is how the worker handles a failed scrape
+type ScrapeFailure string
+
+const (
+	FailureRetry     ScrapeFailure = "retry"       // exponential backoff up to MaxRetryAttempts
+	FailurePermanent ScrapeFailure = "permanent"   // 404: delisted, never retried
+	FailureCooldown  ScrapeFailure = "cooldown"    // WAF / open circuit breaker: retry after 1h, worker pauses
+	FailureRobots    ScrapeFailure = "robots"      // disallowed by robots.txt: permanent, no request was sent
+	FailureUnknown   ScrapeFailure = "unsupported" // no source registered for the URL host: permanent, no request was sent
+	FailureProxy     ScrapeFailure = "proxy"       // every configured proxy failed: retried like FailureRetry, the site was never reached
+)
+
+// ClassifyScrapeFailure decides how a scrape error is handled from the scraper's typed errors
+// (errors.Is), never from the message text, so a title or URL containing "404" can't flip it
+func ClassifyScrapeFailure(err error) ScrapeFailure {
+	switch {
+	case errors.Is(err, scraper.ErrNotFound), errors.Is(err, scraper.ErrDelisted):
+		return FailurePermanent
+	case errors.Is(err, scraper.ErrRobotsDisallowed):
+		return FailureRobots
+	case errors.Is(err, scraper.ErrUnknownSource):
+		return FailureUnknown
+	case errors.Is(err, scraper.ErrWAFBlocked), errors.Is(err, scraper.ErrCircuitOpen):
+		return FailureCooldown
+	case errors.Is(err, scraper.ErrProxy):
+		return FailureProxy
+	}
+	return FailureRetry
 }
 
 // handleScrapeError handles scraping errors with smart retry logic
 func (w *QueueWorker) handleScrapeError(item *models.DetailScrapeQueue, err error) {
-	errMsg := err.Error()
-	log.Printf("QueueWorker: Scrape failed for id=%d: %v", item.ID, err)
+	// Raw errors can embed whole HTML bodies or DSNs; store a bounded, classified form
+	code, errMsg := errtext.FromError(err)
+	item.LastErrorCode = code
+	failure := ClassifyScrapeFailure(err)
+	log.Printf("QueueWorker: Scrape failed for id=%d (%s, %s): %s", item.ID, code, failure, errMsg)
+	defer publishItemFailed(item, failure)
+
+	// robots.txt refusals and unknown sites never reached a site: no limiter outcome, never retried
+	if failure == FailureRobots || failure == FailureUnknown {
+		reason := "Blocked by robots.txt"
+		if failure == FailureUnknown {
+			reason = "Unsupported site"
+		}
+		log.Printf("QueueWorker: %s for id=%d - marking as permanent_fail (no retry)", reason, item.ID)
+		item.Status = models.QueueStatusPermanentFail
+		item.LastError = errtext.Clean(fmt.Sprintf("%s (permanent): %s", reason, errMsg))
+		completedAt := time.Now()
+		item.CompletedAt = &completedAt
+		item.NextRetryAt = nil
+
+		if err := w.db.Save(item).Error; err != nil {
+			log.Printf("QueueWorker: Failed to save robots permanent_fail status: %v", err)
+		}
+		return
+	}
+
+	// Any site failure resets the consecutive success run for preventive cooldown
+	// (a dead proxy is not one: it is retried below without touching the limiter)
+	if failure != FailureProxy {
+		w.recordFailure(item, failure)
+	}
 
 	// Check if it's a permanent failure (404 Not Found)
-	if strings.Contains(errMsg, "permanent_fail") || strings.Contains(errMsg, "404") {
+	if failure == FailurePermanent {
 		// 404: Property delisted or URL invalid - don't retry
 		log.Printf("QueueWorker: Permanent failure (404) for id=%d - marking as permanent_fail (no retry)", item.ID)
 		item.Status = models.QueueStatusPermanentFail
-		item.LastError = fmt.Sprintf("404 Not Found (permanent): %s", errMsg)
+		item.LastError = errtext.Clean(fmt.Sprintf("404 Not Found (permanent): %s", errMsg))
 		completedAt := time.Now()
 		item.CompletedAt = &completedAt
 		item.NextRetryAt = nil
 
-		// Reset consecutive success counter on failure
-		w.consecutiveSuccess = 0
-
 		if err := w.db.Save(item).Error; err != nil {
 			log.Printf("QueueWorker: Failed to save permanent_fail status: %v", err)
 		}
@@ -187,15 +909,12 @@ func (w *QueueWorker) handleScrapeError(item *models.DetailScrapeQueue, err erro
 	}
 
 	// Check for WAF block
-	if strings.Contains(errMsg, "WAF") || strings.Contains(errMsg, "circuit breaker open") {
+	if failure == FailureCooldown {
 		log.Printf("QueueWorker: WAF/circuit breaker detected for id=%d - entering cooldown", item.ID)
 
-		// Reset consecutive success counter on WAF
-		w.consecutiveSuccess = 0
-
 		// WAF detected: enter long cooldown (1 hour minimum)
 		item.Status = models.QueueStatusFailed
-		item.LastError = fmt.Sprintf("WAF/circuit breaker: %s", errMsg)
+		item.LastError = errtext.Clean(fmt.Sprintf("WAF/circuit breaker: %s", errMsg))
 		nextRetry := time.Now().Add(1 * time.Hour)
 		item.NextRetryAt = &nextRetry
 
@@ -203,27 +922,29 @@ func (w *QueueWorker) handleScrapeError(item *models.DetailScrapeQueue, err erro
 			log.Printf("QueueWorker: Failed to save WAF cooldown: %v", err)
 		}
 
-		// Also: pause worker for a bit to let circuit breaker reset
+		// Also: pause worker for a bit to let circuit breaker reset (ticks are skipped, no sleep)
 		log.Printf("QueueWorker: Pausing for 5 minutes due to WAF detection")
-		time.Sleep(5 * time.Minute)
+		w.pause(5*time.Minute, fmt.Sprintf("WAF/circuit breaker on item %d", item.ID))
 		return
 	}
 
 	// Retryable error (500, 503, timeout, etc.)
-	// Reset consecutive success counter on any error
-	w.consecutiveSuccess = 0
 
 	if item.Attempts >= models.MaxRetryAttempts {
 		// Max retries exceeded
 		log.Printf("QueueWorker: Max retries exceeded for id=%d (%d attempts)", item.ID, item.Attempts)
 		item.Status = models.QueueStatusFailed
-		item.LastError = fmt.Sprintf("Max retries exceeded (%d): %s", item.Attempts, errMsg)
+		item.LastError = errtext.Clean(fmt.Sprintf("Max retries exceeded (%d): %s", item.Attempts, errMsg))
 		completedAt := time.Now()
 		item.CompletedAt = &completedAt
 		item.NextRetryAt = nil
 	} else {
 		// Schedule retry with exponential backoff
 		delay := models.GetNextRetryDelay(item.Attempts - 1) // -1 because we already incremented Attempts
+		// A 429 the scraper gave up on asked us not to come back before its Retry-After
+		if retryAfter := scraper.RetryAfterOf(err); retryAfter > delay {
+			delay = retryAfter
+		}
 		nextRetry := time.Now().Add(delay)
 		item.Status = models.QueueStatusFailed
 		item.LastError = errMsg
@@ -238,8 +959,8 @@ func (w *QueueWorker) handleScrapeError(item *models.DetailScrapeQueue, err erro
 }
 
 // handleScrapeSuccess handles successful scraping
-func (w *QueueWorker) handleScrapeSuccess(item *models.DetailScrapeQueue, property *models.Property, stations []models.PropertyStation) {
-	log.Printf("QueueWorker: Successfully scraped id=%d property_id=%s stations=%d", item.ID, property.ID, len(stations))
+func (w *QueueWorker) handleScrapeSuccess(ctx context.Context, item *models.DetailScrapeQueue, property *models.Property, stations []models.PropertyStation, images []models.PropertyImage) {
+	log.Printf("QueueWorker: Successfully scraped id=%d property_id=%s stations=%d images=%d", item.ID, property.ID, len(stations), len(images))
 
 	// Check if property already exists
 	var existing models.Property
@@ -260,11 +981,9 @@ func (w *QueueWorker) handleScrapeSuccess(item *models.DetailScrapeQueue, proper
 		}
 	}
 
-	// Save property with stations to database (transaction-based)
-	// Create GormDB wrapper from the worker's db instance
-	gormDB := database.NewGormDBFromDB(w.db)
-	if err := gormDB.SavePropertyWithStations(property, stations); err != nil {
-		log.Printf("QueueWorker: Failed to save property with stations: %v", err)
+	// Save property with stations and images to database (transaction-based)
+	if err := w.saver.SavePropertyWithStationsAndImagesContext(ctx, property, stations, images); err != nil {
+		log.Printf("QueueWorker: Failed to save property with stations/images: %v", err)
 		// Treat as retryable error
 		w.handleScrapeError(item, fmt.Errorf("database save error: %w", err))
 		return
@@ -275,16 +994,23 @@ func (w *QueueWorker) handleScrapeSuccess(item *models.DetailScrapeQueue, proper
 	} else {
 		log.Printf("QueueWorker: [stations] property_id=%s stations_len=%d saved", property.ID, len(stations))
 	}
+	if len(images) == 0 {
+		log.Printf("QueueWorker: [images] property_id=%s images_len=0 skip_delete_preserve_existing", property.ID)
+	} else {
+		log.Printf("QueueWorker: [images] property_id=%s images_len=%d saved", property.ID, len(images))
+	}
 
 	// Create snapshot with change detection
 	if err := w.snapshot.CreateSnapshotWithChangeDetection(property); err != nil {
+		atomic.AddInt64(&w.snapshotFailures, 1)
 		log.Printf("QueueWorker: Warning: Failed to create snapshot: %v", err)
-		// Don't fail the whole operation for snapshot errors
+		// Don't fail the whole operation for snapshot errors; the gap report picks these up
 	}
 
 	// Mark queue item as done
 	item.Status = models.QueueStatusDone
 	item.LastError = ""
+	item.LastErrorCode = ""
 	completedAt := time.Now()
 	item.CompletedAt = &completedAt
 	item.NextRetryAt = nil
@@ -293,33 +1019,27 @@ func (w *QueueWorker) handleScrapeSuccess(item *models.DetailScrapeQueue, proper
 		log.Printf("QueueWorker: Failed to mark item as done: %v", err)
 	} else {
 		log.Printf("QueueWorker: ✅ Completed id=%d property_id=%s", item.ID, property.ID)
+		publishItemDone(item, property.ID)
 
-		// Track consecutive successes for preventive cooldown
-		w.consecutiveSuccess++
-
-		// Preventive cooldown after 3 consecutive successes (simulate human behavior)
-		if w.consecutiveSuccess >= 3 {
-			cooldownDuration := 5 * time.Minute
-			log.Printf("QueueWorker: Preventive cooldown after %d successes - pausing for %v", w.consecutiveSuccess, cooldownDuration)
-			time.Sleep(cooldownDuration)
-			w.consecutiveSuccess = 0 // Reset counter
-		}
+		// Preventive cooldown after N consecutive successes (simulate human behavior);
+		// the pause itself is enforced in processNextBatch
+		w.recordSuccess(item)
 	}
 }
 
-// healthCheck performs a lightweight request to check for WAF blocks
-func (w *QueueWorker) healthCheck() bool {
+// healthCheck performs a lightweight request to check for WAF blocks (Stop cancels it via ctx)
+func (w *QueueWorker) healthCheck(ctx context.Context) bool {
 	testURL := "https://realestate.yahoo.co.jp/rent/"
-	req, err := http.NewRequest("GET", testURL, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", testURL, nil)
 	if err != nil {
 		log.Printf("QueueWorker: Health check request creation failed: %v", err)
 		return false
 	}
 
-	// Apply browser-like headers
-	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/122.0.0.0 Safari/537.36")
-	req.Header.Set("Accept", "text/html,application/xhtml+xml,application/xml;q=0.9,image/avif,image/webp,*/*;q=0.8")
-	req.Header.Set("Accept-Language", "ja-JP,ja;q=0.9,en-US;q=0.8,en;q=0.7")
+	// Same browser header profile as the scraper's own requests; the transport negotiates
+	// gzip itself so the WAF page below can be read without the scraper's decoder
+	w.scraper.ApplyHeaders(req, "")
+	req.Header.Del("Accept-Encoding")
 
 	client := &http.Client{Timeout: 10 * time.Second}
 	resp, err := client.Do(req)
@@ -329,13 +1049,11 @@ func (w *QueueWorker) healthCheck() bool {
 	}
 	defer resp.Body.Close()
 
-	// Check for WAF block
-	if resp.StatusCode >= 500 {
-		body, _ := io.ReadAll(resp.Body)
-		if strings.Contains(string(body), "ご覧になろうとしているページは現在表示できません") {
-			log.Printf("QueueWorker: WAF block detected in health check (status: %d)", resp.StatusCode)
-			return false
-		}
+	// Check for WAF block (the block page is also served with 200)
+	body, _ := io.ReadAll(resp.Body)
+	if scraper.IsWAFBlockPage(string(body)) {
+		log.Printf("QueueWorker: WAF block detected in health check (status: %d)", resp.StatusCode)
+		return false
 	}
 
 	// 403 also could be WAF
@@ -356,6 +1074,7 @@ func (w *QueueWorker) GetQueueStats() map[string]interface{} {
 		Done          int64
 		Failed        int64
 		PermanentFail int64
+		Cancelled     int64
 	}
 
 	w.db.Model(&models.DetailScrapeQueue{}).Where("status = ?", models.QueueStatusPending).Count(&stats.Pending)
@@ -363,6 +1082,11 @@ func (w *QueueWorker) GetQueueStats() map[string]interface{} {
 	w.db.Model(&models.DetailScrapeQueue{}).Where("status = ?", models.QueueStatusDone).Count(&stats.Done)
 	w.db.Model(&models.DetailScrapeQueue{}).Where("status = ?", models.QueueStatusFailed).Count(&stats.Failed)
 	w.db.Model(&models.DetailScrapeQueue{}).Where("status = ?", models.QueueStatusPermanentFail).Count(&stats.PermanentFail)
+	w.db.Model(&models.DetailScrapeQueue{}).Where("status = ?", models.QueueStatusCancelled).Count(&stats.Cancelled)
+
+	// limiter_bound: a source has pending items while its detail limiter has no budget left (the
+	// limiter, not the worker, is the bottleneck)
+	bound := w.limiterBound()
 
 	return map[string]interface{}{
 		"pending":        stats.Pending,
@@ -370,6 +1094,32 @@ func (w *QueueWorker) GetQueueStats() map[string]interface{} {
 		"done":           stats.Done,
 		"failed":         stats.Failed,
 		"permanent_fail": stats.PermanentFail,
-		"is_running":     w.isRunning,
+		"cancelled":      stats.Cancelled,
+		"is_running":     w.IsRunning(),
+		"paused":         w.IsPaused(),
+		"pause":          w.Paused(),
+
+		"snapshot_failures":  atomic.LoadInt64(&w.snapshotFailures),
+		"not_modified":       atomic.LoadInt64(&w.notModified),
+		"delisted_by_head":   atomic.LoadInt64(&w.delisted),
+		"delisted_by_page":   atomic.LoadInt64(&w.endedPages),
+		"skipped_fresh":      atomic.LoadInt64(&w.freshSkipped),
+		"light_refreshed":    atomic.LoadInt64(&w.lightRefreshed),
+		"buildings_expanded": atomic.LoadInt64(&w.buildingsExpanded),
+		"recovered_stuck":    atomic.LoadInt64(&w.recoveredStuck),
+		"snapshots_skipped":  snapshot.SkippedUnchangedCount(),
+
+		"health_cooldown": w.HealthCooldown(),
+		"in_flight":       w.inFlightCount(),
+		"in_flight_items": w.inFlightItems(),
+		"busy_slots":      w.busyLanes(),
+		"max_concurrency": w.maxConcurrency,
+
+		"detail_limiter":        scraper.DetailLimiter.Status(),
+		"limiter_bound":         len(bound) > 0,
+		"limiter_bound_sources": bound,
+		"source_limiters":       w.sources.LimiterStatus(),
+		"adaptive_limiters":     w.adaptiveStatus(),
+		"source_breakers":       w.sources.BreakerStatus(),
 	}
 }
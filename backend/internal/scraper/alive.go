@@ -0,0 +1,118 @@
+package scraper
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"real-estate-portal/internal/ratelimit"
+	"time"
+)
+
+// AliveLimiter is the Yahoo budget for CheckAlive. HEAD requests are far cheaper than detail
+// pages, so it is more generous than DetailLimiter and never draws from the detail budget.
+var AliveLimiter = ratelimit.NewSourceDetailLimiter("alive", 120)
+
+// AliveChecker is a source that can tell whether a detail page is still listed without
+// scraping it (see Scraper.CheckAlive)
+type AliveChecker interface {
+	CheckAliveContext(ctx context.Context, detailURL string) (alive bool, status int, err error)
+}
+
+// ConfigureAliveLimit replaces every source's CheckAlive budget (0 keeps the default of 120 per
+// hour). Must be called at startup before checks begin.
+func ConfigureAliveLimit(perHour int) {
+	if perHour <= 0 {
+		return
+	}
+	AliveLimiter = ratelimit.NewSourceDetailLimiter("alive", perHour)
+	suumoLimits.alive = ratelimit.NewSourceDetailLimiter("alive:"+suumoSourceName, perHour)
+	log.Printf("Scraper: alive check limiter configured (%d per hour per source)", perHour)
+}
+
+// aliveLimiter is the CheckAlive budget for s's site
+func (s *Scraper) aliveLimiter() *ratelimit.DetailLimiter {
+	if s.limits != nil {
+		return s.limits.alive
+	}
+	return AliveLimiter
+}
+
+// CheckAlive reports whether detailURL is still listed (see CheckAliveContext)
+func (s *Scraper) CheckAlive(detailURL string) (alive bool, status int, err error) {
+	return s.CheckAliveContext(context.Background(), detailURL)
+}
+
+// CheckAliveContext asks the site whether detailURL is still listed with a HEAD request, or a
+// one-byte ranged GET when HEAD is refused (403/405/501). 404 and 410 mean delisted (alive=false,
+// no error); any 2xx means listed. Throttling, WAF and other statuses are errors, since they say
+// nothing about the listing. It waits on the source's alive limiter, not the detail budget.
+func (s *Scraper) CheckAliveContext(ctx context.Context, detailURL string) (alive bool, status int, err error) {
+	ctx, done := s.startStats(ctx)
+	defer done()
+	if err := s.checkRobots(ctx, detailURL); err != nil {
+		return false, 0, err
+	}
+	breaker := s.circuitBreaker
+	if !breaker.CanProceed() {
+		isOpen, failures, total := breaker.GetStatus()
+		return false, 0, fmt.Errorf("%w: suspected WAF block (%d/%d failures, open=%v)", ErrCircuitOpen, failures, total, isOpen)
+	}
+	waitStart := time.Now()
+	if err := s.aliveLimiter().AcquireContext(ctx, "alive"); err != nil {
+		return false, 0, fmt.Errorf("waiting for alive limiter: %w", err)
+	}
+	s.recordStats(ctx, func(st *ScrapeStats) { st.LimiterWait += time.Since(waitStart) })
+
+	status, err = s.aliveRequest(ctx, http.MethodHead, detailURL)
+	if err == nil && (status == http.StatusForbidden || status == http.StatusMethodNotAllowed || status == http.StatusNotImplemented) {
+		log.Printf("[CheckAlive] HEAD answered %d for %s, retrying as a ranged GET", status, detailURL)
+		status, err = s.aliveRequest(ctx, http.MethodGet, detailURL)
+	}
+	if err != nil {
+		return false, 0, err
+	}
+
+	switch {
+	case status == http.StatusNotFound || status == http.StatusGone:
+		breaker.RecordSuccess()
+		log.Printf("[CheckAlive] %s is delisted (status %d)", detailURL, status)
+		return false, status, nil
+	case status >= 200 && status < 300:
+		breaker.RecordSuccess()
+		return true, status, nil
+	case status == http.StatusTooManyRequests:
+		breaker.RecordFailure(status)
+		return false, status, fmt.Errorf("%w: alive check status code 429", ErrRateLimited)
+	case status >= 500 || status == http.StatusForbidden:
+		breaker.RecordFailure(status)
+	}
+	return false, status, fmt.Errorf("alive check failed: status code %d", status)
+}
+
+// aliveRequest sends one HEAD or ranged GET and returns the status (a WAF block page is ErrWAFBlocked)
+func (s *Scraper) aliveRequest(ctx context.Context, method, detailURL string) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, method, detailURL, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+	s.applyHeaders(req, "")
+	if method == http.MethodGet {
+		req.Header.Set("Range", "bytes=0-0")
+	}
+
+	resp, err := s.do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if method == http.MethodGet && isWAFBlock(resp) {
+		s.circuitBreaker.RecordBlock(resp.StatusCode)
+		return resp.StatusCode, fmt.Errorf("%w: immediate retreat required", ErrWAFBlocked)
+	}
+	// A server ignoring Range may still send the whole page; it is not needed
+	_, _ = io.CopyN(io.Discard, resp.Body, 64<<10)
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,75 @@
+package scraper
+
+import (
+	"real-estate-portal/internal/models"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// areaRowKeys are the detail table headers for the unit's own floor area. バルコニー面積 /
+// 敷地面積 / 延床面積 rows are never read.
+var areaRowKeys = map[string]bool{"専有面積": true, "使用部分面積": true, "面積": true}
+
+// areaWindow is how many runes after a 専有面積 label the value is looked for in
+const areaWindow = 30
+
+// extractAreaFromTable reads the 専有面積 row (th/td or dt/dd) of the detail table
+func extractAreaFromTable(doc *goquery.Document) (float64, string) {
+	area, unit := 0.0, ""
+	doc.Find("th, dt").EachWithBreak(func(_ int, header *goquery.Selection) bool {
+		if !areaRowKeys[strings.Join(strings.Fields(header.Text()), "")] {
+			return true
+		}
+		area, unit = models.ParseArea(header.NextFiltered("td, dd").Text())
+		return unit == ""
+	})
+	return area, unit
+}
+
+// extractArea finds the area right after 専有面積 (or a bare 面積 label) in the page text. The
+// window ends at the next 面積 label so a following バルコニー面積 is not read as the unit's area.
+func extractArea(text string) (float64, string) {
+	for _, label := range []string{"専有面積", "使用部分面積", "面積"} {
+		for rest := text; ; {
+			i := strings.Index(rest, label)
+			if i < 0 {
+				break
+			}
+			before := rest[:i]
+			rest = rest[i+len(label):]
+			// 面積 inside another label (バルコニー面積, 敷地面積, 専有面積 already tried)
+			if label == "面積" && endsWithWordRune(before) {
+				continue
+			}
+			window := rest
+			if j := strings.Index(window, "面積"); j >= 0 {
+				window = window[:j]
+			}
+			if runes := []rune(window); len(runes) > areaWindow {
+				window = string(runes[:areaWindow])
+			}
+			if area, unit := models.ParseArea(window); unit != "" {
+				return area, unit
+			}
+		}
+	}
+	return 0, ""
+}
+
+// endsWithWordRune reports whether s ends with a kanji / kana (the start of a compound label)
+func endsWithWordRune(s string) bool {
+	r, _ := utf8.DecodeLastRuneInString(s)
+	return unicode.In(r, unicode.Han, unicode.Katakana, unicode.Hiragana) || r == 'ー'
+}
+
+// setArea stores a parsed area and its original unit (tsubo / jo values are converted estimates)
+func setArea(property *models.Property, area float64, unit string) {
+	if unit == "" {
+		return
+	}
+	property.Area = &area
+	property.AreaUnit = unit
+}
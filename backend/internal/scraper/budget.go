@@ -0,0 +1,84 @@
+package scraper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// retryBudget bounds one doRequestWithRetry sequence: the list limiter wait, every attempt and
+// the backoff sleeps between them. A zero deadline means unbounded (ScraperConfig.MaxTotal = 0).
+type retryBudget struct {
+	total    time.Duration
+	deadline time.Time
+}
+
+// newRetryBudget starts the budget for one request sequence
+func (s *Scraper) newRetryBudget() retryBudget {
+	if s.maxTotal <= 0 {
+		return retryBudget{}
+	}
+	return retryBudget{total: s.maxTotal, deadline: time.Now().Add(s.maxTotal)}
+}
+
+// allows reports whether waiting d still ends before the deadline
+func (b retryBudget) allows(d time.Duration) bool {
+	return b.deadline.IsZero() || time.Now().Add(d).Before(b.deadline)
+}
+
+// context bounds ctx by the deadline (the cancel func must always be called)
+func (b retryBudget) context(ctx context.Context) (context.Context, context.CancelFunc) {
+	if b.deadline.IsZero() {
+		return context.WithCancel(ctx)
+	}
+	return context.WithDeadline(ctx, b.deadline)
+}
+
+// exhausted is the ErrTimeout returned instead of sleeping past the deadline
+func (b retryBudget) exhausted(what string) error {
+	return fmt.Errorf("%w: retry budget of %v used up %s", ErrTimeout, b.total, what)
+}
+
+// attempt sends one try of req, bounded by the per-attempt timeout or the budget's deadline,
+// whichever comes first. The bound covers reading the body too: the attempt's context is only
+// released when the caller closes resp.Body.
+func (s *Scraper) attempt(req *http.Request, budget retryBudget) (*http.Response, error) {
+	deadline := budget.deadline
+	if s.attemptTimeout > 0 {
+		if d := time.Now().Add(s.attemptTimeout); deadline.IsZero() || d.Before(deadline) {
+			deadline = d
+		}
+	}
+	if deadline.IsZero() {
+		return s.do(req)
+	}
+
+	limit := time.Until(deadline).Round(time.Millisecond)
+	ctx, cancel := context.WithDeadline(req.Context(), deadline)
+	resp, err := s.do(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		// A timed-out attempt is ErrTimeout unless the caller gave up or every proxy failed
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) && req.Context().Err() == nil && !errors.Is(err, ErrProxy) {
+			return nil, fmt.Errorf("%w: no response within %v: %v", ErrTimeout, limit, err)
+		}
+		return nil, err
+	}
+	resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// cancelOnClose releases an attempt's context once its body is closed
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (c *cancelOnClose) Close() error {
+	err := c.ReadCloser.Close()
+	c.cancel()
+	return err
+}
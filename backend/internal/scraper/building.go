@@ -0,0 +1,199 @@
+package scraper
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"real-estate-portal/internal/models"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// BuildingPageError is returned by ScrapeProperty / ScrapeDetail for a building page: one page
+// listing several rentable units. Nothing is saved for the building itself; Units are the
+// units read from its table (each with its own SourcePropertyID and detail URL).
+type BuildingPageError struct {
+	URL   string
+	Units []*models.Property
+}
+
+func (e *BuildingPageError) Error() string {
+	return fmt.Sprintf("building page with %d units: %s", len(e.Units), e.URL)
+}
+
+// Is makes errors.Is(err, ErrBuildingPage) match
+func (e *BuildingPageError) Is(target error) bool { return target == ErrBuildingPage }
+
+// ErrorCode reports the errtext code (see errtext.FromError)
+func (e *BuildingPageError) ErrorCode() string { return ErrBuildingPage.(*codedError).code }
+
+// UnitURLs returns the detail URLs of the units
+func (e *BuildingPageError) UnitURLs() []string {
+	urls := make([]string, 0, len(e.Units))
+	for _, unit := range e.Units {
+		urls = append(urls, unit.DetailURL)
+	}
+	return urls
+}
+
+// IsBuildingURL reports whether rawURL is a Yahoo building page (/rent/building/<id>/)
+func IsBuildingURL(rawURL string) bool {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+	return strings.Contains(parsed.Path, "/rent/building/")
+}
+
+// ScrapeBuilding scrapes a building page into one property per listed unit. An ordinary detail
+// page comes back as a single unit. Units only carry what the building page shows (rent, plan,
+// area, floor and the building's name, address, age and station); their detail pages have the rest.
+// NOTE: Rate limiting (DetailLimiter) should be applied by the caller, as for ScrapeProperty.
+func (s *Scraper) ScrapeBuilding(inputURL string) ([]*models.Property, error) {
+	return s.ScrapeBuildingContext(context.Background(), inputURL)
+}
+
+// ScrapeBuildingContext is ScrapeBuilding under ctx
+func (s *Scraper) ScrapeBuildingContext(ctx context.Context, inputURL string) ([]*models.Property, error) {
+	property, err := s.scrapeProperty(ctx, inputURL, "", Validators{})
+	var building *BuildingPageError
+	if errors.As(err, &building) {
+		return building.Units, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return []*models.Property{property}, nil
+}
+
+// buildingUnit is one row of a building page's unit table
+type buildingUnit struct {
+	detailURL string
+	row       *goquery.Selection
+}
+
+// isBuildingPage reports whether doc is a building page: a /rent/building/ URL, or a page without
+// a listing object of its own whose table rows link to two or more detail pages (recommendation
+// blocks on detail pages are lists, not table rows, and come with the page's own listing)
+func isBuildingPage(doc *goquery.Document, pageURL string) bool {
+	if IsBuildingURL(pageURL) {
+		return true
+	}
+	if contextPropertyObject(doc) != nil {
+		return false
+	}
+	return len(buildingUnitRows(doc, pageURL)) >= 2
+}
+
+// buildingUnitRows returns the table rows that link to a unit's detail page, one per unit
+func buildingUnitRows(doc *goquery.Document, pageURL string) []buildingUnit {
+	base, _ := url.Parse(pageURL)
+	var units []buildingUnit
+	seen := make(map[string]bool)
+	doc.Find("tr").Each(func(_ int, row *goquery.Selection) {
+		href, ok := row.Find("a[href*='/rent/detail/']").First().Attr("href")
+		if !ok {
+			return
+		}
+		link, err := url.Parse(strings.TrimSpace(href))
+		if err != nil {
+			return
+		}
+		if base != nil {
+			link = base.ResolveReference(link)
+		}
+		detailURL := normalizeURL(link.String())
+		if !strings.HasSuffix(detailURL, "/") {
+			detailURL += "/"
+		}
+		if seen[detailURL] {
+			return
+		}
+		seen[detailURL] = true
+		units = append(units, buildingUnit{detailURL: detailURL, row: row})
+	})
+	return units
+}
+
+// parseBuildingPage reads the units of a building page. Building-wide fields (name, address,
+// age, nearest station) are copied to every unit; the row gives rent, plan, area and floor.
+func parseBuildingPage(doc *goquery.Document, pageURL string) *BuildingPageError {
+	name := cleanTitle(strings.TrimSpace(doc.Find("h1").First().Text()))
+	if name == "" {
+		if title, ok := doc.Find("meta[property='og:title']").Attr("content"); ok {
+			name = cleanTitle(strings.TrimSpace(title))
+		}
+	}
+	address := extractAddress(doc)
+	age := extractBuildingAge(doc.Find("body").Text())
+	stations := extractStations(doc)
+
+	building := &BuildingPageError{URL: pageURL}
+	for _, unit := range buildingUnitRows(doc, pageURL) {
+		property := &models.Property{
+			Source:           "yahoo",
+			SourcePropertyID: yahooPropertyIDOrHash(unit.detailURL),
+			DetailURL:        unit.detailURL,
+			Title:            name,
+			BuildingName:     name,
+			Address:          address,
+			FetchedAt:        time.Now(),
+		}
+		property.ID = internalPropertyID(property.Source, property.SourcePropertyID)
+		if age > 0 {
+			property.BuildingAge = &age
+		}
+		applyStationCompatibility(property, stations)
+		parseBuildingUnitRow(unit.row, property)
+		if property.Title == "" {
+			property.Title = "No Title"
+		}
+		building.Units = append(building.Units, property)
+	}
+	log.Printf("[ScrapeProperty] %s is a building page (%q, %d units)", pageURL, name, len(building.Units))
+	return building
+}
+
+// parseBuildingUnitRow reads a unit row cell by cell: the first valid rent ("9.8万円 / 5,000円"
+// is the rent and its 管理費), the floor plan, the area and the floor ("3階")
+func parseBuildingUnitRow(row *goquery.Selection, property *models.Property) {
+	row.Find("td").Each(func(_ int, cell *goquery.Selection) {
+		text := strings.Join(strings.Fields(cell.Text()), " ")
+		compact := strings.ReplaceAll(text, " ", "")
+		if property.Rent == nil {
+			value := compact
+			if i := strings.IndexAny(value, "（(/／"); i > 0 {
+				value = value[:i]
+			}
+			if amount := models.ParseYenAmount(value); amount != nil && validRent(*amount) {
+				property.Rent = amount
+				return
+			}
+		}
+		if property.FloorPlan == "" {
+			property.FloorPlan = models.ParseFloorPlan(text)
+		}
+		if property.Area == nil {
+			area, unit := models.ParseArea(text)
+			setArea(property, area, unit)
+		}
+		if property.FloorLabel == "" && strings.Contains(compact, "階") {
+			if floor := extractFloor(compact); floor != 0 {
+				property.FloorLabel = compact
+				property.Floor = &floor
+			}
+		}
+	})
+}
+
+// internalPropertyID is the properties.id of a listing: the MD5 of "source:source_property_id"
+func internalPropertyID(source, sourcePropertyID string) string {
+	hash := md5.Sum([]byte(source + ":" + sourcePropertyID))
+	return hex.EncodeToString(hash[:])
+}
@@ -1,6 +1,7 @@
 package scraper
 
 import (
+	"fmt"
 	"log"
 	"sync"
 	"time"
@@ -17,10 +18,19 @@ type CircuitBreaker struct {
 	consecutiveFailures int  // NEW: Track consecutive failures for immediate detection
 	isOpen             bool
 	lastFailureTime    time.Time
+	notBefore          time.Time // Earliest half-open attempt asked for by a 429's Retry-After
+	tripped            bool      // Opened (or restored) and not recovered yet: the store holds a block
+	store              BreakerStore
 
 	mutex              sync.Mutex
 }
 
+// BreakerStore persists a breaker's block so a restart keeps honouring it
+type BreakerStore interface {
+	SaveBlock(reason string, until time.Time) // the breaker opened until then
+	ClearBlock()                              // a request succeeded after the block, or an admin reset
+}
+
 // NewCircuitBreaker creates a new circuit breaker
 func NewCircuitBreaker(failureThreshold int, resetTimeout time.Duration) *CircuitBreaker {
 	return &CircuitBreaker{
@@ -29,9 +39,42 @@ func NewCircuitBreaker(failureThreshold int, resetTimeout time.Duration) *Circui
 	}
 }
 
+// SetStore makes the breaker write its blocks to store (nil stops persisting)
+func (cb *CircuitBreaker) SetStore(store BreakerStore) {
+	cb.mutex.Lock()
+	defer cb.mutex.Unlock()
+	cb.store = store
+}
+
+// Restore opens the breaker until a block persisted by a previous process ends, so CanProceed
+// refuses requests until then (a past until is ignored)
+func (cb *CircuitBreaker) Restore(until time.Time) {
+	cb.mutex.Lock()
+	defer cb.mutex.Unlock()
+
+	if !time.Now().Before(until) {
+		return
+	}
+	cb.isOpen = true
+	cb.tripped = true
+	cb.lastFailureTime = until.Add(-cb.resetTimeout)
+	if until.After(cb.notBefore) {
+		cb.notBefore = until
+	}
+	log.Printf("⚠️  Circuit breaker restored open until %s (block persisted before restart)", until.Format(time.RFC3339))
+}
+
 // RecordSuccess records a successful request
 func (cb *CircuitBreaker) RecordSuccess() {
 	cb.mutex.Lock()
+	recovered := cb.tripped
+	cb.tripped = false
+	store := cb.store
+	defer func() {
+		if recovered && store != nil {
+			store.ClearBlock()
+		}
+	}()
 	defer cb.mutex.Unlock()
 
 	cb.successes++
@@ -39,6 +82,7 @@ func (cb *CircuitBreaker) RecordSuccess() {
 
 	// Reset consecutive failures on success
 	cb.consecutiveFailures = 0
+	cb.notBefore = time.Time{}
 
 	// Keep cumulative failures for rate calculation
 	// (don't reset cb.failures here - we need it for the 20-request window)
@@ -46,20 +90,46 @@ func (cb *CircuitBreaker) RecordSuccess() {
 
 // RecordFailure records a failed request (500, 503, etc.)
 func (cb *CircuitBreaker) RecordFailure(statusCode int) {
+	cb.recordFailure(statusCode, statusCode == 500 || statusCode == 429 || statusCode == 403)
+}
+
+// RecordBlock records a WAF block page. It counts as a critical failure whatever the status,
+// since the block page is also served with 200.
+func (cb *CircuitBreaker) RecordBlock(statusCode int) {
+	cb.recordFailure(statusCode, true)
+}
+
+// recordFailure counts a failure and persists the block when it opens the breaker
+func (cb *CircuitBreaker) recordFailure(statusCode int, critical bool) {
 	cb.mutex.Lock()
-	defer cb.mutex.Unlock()
+	wasOpen := cb.isOpen
+	reason := cb.countFailureLocked(statusCode, critical)
+	opened := cb.isOpen && !wasOpen
+	if opened {
+		cb.tripped = true
+	}
+	until, store := cb.retryAtLocked(), cb.store
+	cb.mutex.Unlock()
+
+	if opened && store != nil {
+		store.SaveBlock(reason, until)
+	}
+}
 
+// countFailureLocked counts a failure; two critical ones in a row open the breaker. It returns
+// why the breaker is open ("" if it is not). cb.mutex held.
+func (cb *CircuitBreaker) countFailureLocked(statusCode int, critical bool) string {
 	cb.failures++
 	cb.consecutiveFailures++
 	cb.totalRequests++
 	cb.lastFailureTime = time.Now()
 
 	// IMMEDIATE STOP: 2 consecutive critical errors = instant block detected
-	if cb.consecutiveFailures >= 2 && (statusCode == 500 || statusCode == 429 || statusCode == 403) {
+	if cb.consecutiveFailures >= 2 && critical {
 		cb.isOpen = true
 		log.Printf("🚨 CIRCUIT BREAKER OPEN: %d consecutive %d errors. WAF block detected!", cb.consecutiveFailures, statusCode)
 		log.Printf("⚠️  Scraping halted immediately. Will retry after %v", cb.resetTimeout)
-		return
+		return fmt.Sprintf("circuit breaker: %d consecutive %d errors", cb.consecutiveFailures, statusCode)
 	}
 
 	// GRADUAL DETECTION: Check failure rate after 20 requests (insurance)
@@ -71,10 +141,41 @@ func (cb *CircuitBreaker) RecordFailure(statusCode int) {
 			log.Printf("⚠️  CIRCUIT BREAKER OPEN: Failure rate %.1f%% (%d/%d failures). Suspected WAF block.",
 				failureRate*100, cb.failures, cb.totalRequests)
 			log.Printf("⚠️  Scraping halted. Will retry after %v", cb.resetTimeout)
+			return fmt.Sprintf("circuit breaker: failure rate %.1f%% (%d/%d failures)", failureRate*100, cb.failures, cb.totalRequests)
+		}
+	}
+	return ""
+}
+
+// RecordRetryAfter keeps the breaker from going half-open before a 429's Retry-After has passed
+// (the reset timeout alone may end sooner than the site asked). It only matters once the breaker
+// is open; a success clears it.
+func (cb *CircuitBreaker) RecordRetryAfter(wait time.Duration) {
+	cb.mutex.Lock()
+	if at := time.Now().Add(wait); at.After(cb.notBefore) {
+		cb.notBefore = at
+	}
+	held := cb.isOpen && cb.notBefore.After(cb.lastFailureTime.Add(cb.resetTimeout))
+	until, store := cb.notBefore, cb.store
+	cb.mutex.Unlock()
+
+	if held {
+		log.Printf("⚠️  Circuit breaker held open until %s (Retry-After %v)", until.Format(time.RFC3339), wait)
+		if store != nil {
+			store.SaveBlock(fmt.Sprintf("circuit breaker: Retry-After %v", wait), until)
 		}
 	}
 }
 
+// retryAtLocked is when an open breaker allows a half-open attempt (cb.mutex held)
+func (cb *CircuitBreaker) retryAtLocked() time.Time {
+	retryAt := cb.lastFailureTime.Add(cb.resetTimeout)
+	if cb.notBefore.After(retryAt) {
+		retryAt = cb.notBefore
+	}
+	return retryAt
+}
+
 // CanProceed checks if requests are allowed
 func (cb *CircuitBreaker) CanProceed() bool {
 	cb.mutex.Lock()
@@ -84,14 +185,15 @@ func (cb *CircuitBreaker) CanProceed() bool {
 		return true
 	}
 
-	// Check if reset timeout has passed
-	if time.Since(cb.lastFailureTime) > cb.resetTimeout {
+	// Check if reset timeout (and any Retry-After) has passed
+	if time.Now().After(cb.retryAtLocked()) {
 		log.Printf("Circuit breaker attempting half-open state after %v", cb.resetTimeout)
 		cb.isOpen = false
 		cb.failures = 0
 		cb.successes = 0
 		cb.totalRequests = 0
 		cb.consecutiveFailures = 0
+		cb.notBefore = time.Time{}
 		return true
 	}
 
@@ -104,3 +206,67 @@ func (cb *CircuitBreaker) GetStatus() (isOpen bool, failures int, total int) {
 	defer cb.mutex.Unlock()
 	return cb.isOpen, cb.failures, cb.totalRequests
 }
+
+// RetryAt returns when an open breaker will allow a half-open attempt (zero time if closed)
+func (cb *CircuitBreaker) RetryAt() time.Time {
+	cb.mutex.Lock()
+	defer cb.mutex.Unlock()
+
+	if !cb.isOpen {
+		return time.Time{}
+	}
+	return cb.retryAtLocked()
+}
+
+// Reset closes the breaker and clears its counts (admin reset after a block was lifted)
+func (cb *CircuitBreaker) Reset() {
+	cb.mutex.Lock()
+	recovered := cb.tripped
+	cb.tripped = false
+	store := cb.store
+	defer func() {
+		if recovered && store != nil {
+			store.ClearBlock()
+		}
+	}()
+	defer cb.mutex.Unlock()
+
+	if cb.isOpen {
+		log.Printf("Circuit breaker reset while open (%d/%d failures)", cb.failures, cb.totalRequests)
+	}
+	cb.isOpen = false
+	cb.failures = 0
+	cb.successes = 0
+	cb.totalRequests = 0
+	cb.consecutiveFailures = 0
+	cb.notBefore = time.Time{}
+}
+
+// State returns whether the breaker is open and when it will retry
+// Used by synchronous API handlers to fail fast instead of erroring URL-by-URL
+func (cb *CircuitBreaker) State() (isOpen bool, retryAt time.Time) {
+	retryAt = cb.RetryAt()
+	if retryAt.IsZero() || time.Now().After(retryAt) {
+		return false, time.Time{}
+	}
+	return true, retryAt
+}
+
+// BreakerStatus is a circuit breaker's state for status endpoints
+type BreakerStatus struct {
+	Open     bool       `json:"open"`
+	Failures int        `json:"failures"`
+	Total    int        `json:"total_requests"`
+	RetryAt  *time.Time `json:"retry_at,omitempty"`
+}
+
+// Status returns the breaker's state and counts
+func (cb *CircuitBreaker) Status() BreakerStatus {
+	_, failures, total := cb.GetStatus()
+	status := BreakerStatus{Failures: failures, Total: total}
+	if isOpen, retryAt := cb.State(); isOpen {
+		status.Open = true
+		status.RetryAt = &retryAt
+	}
+	return status
+}
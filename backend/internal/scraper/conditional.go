@@ -0,0 +1,79 @@
+package scraper
+
+import (
+	"context"
+	"net/http"
+	"real-estate-portal/internal/models"
+	"strings"
+
+	"github.com/chromedp/cdproto/network"
+)
+
+// Validators are the cache validators a detail page was served with. Sent back as
+// If-None-Match / If-Modified-Since, they let the site answer 304 for an unchanged page.
+type Validators struct {
+	ETag         string
+	LastModified string
+}
+
+// ValidatorsOf returns the validators stored on a previously scraped property
+func ValidatorsOf(p *models.Property) Validators {
+	return Validators{ETag: p.ETag, LastModified: p.LastModified}
+}
+
+// IsZero reports whether there is nothing to send (a plain, unconditional fetch)
+func (v Validators) IsZero() bool {
+	return v.ETag == "" && v.LastModified == ""
+}
+
+// headers returns the conditional request headers for v
+func (v Validators) headers() map[string]string {
+	h := make(map[string]string, 2)
+	if v.ETag != "" {
+		h["If-None-Match"] = v.ETag
+	}
+	if v.LastModified != "" {
+		h["If-Modified-Since"] = v.LastModified
+	}
+	return h
+}
+
+// validatorsFromHeader reads ETag / Last-Modified from an HTTP response
+func validatorsFromHeader(h http.Header) Validators {
+	return Validators{ETag: h.Get("ETag"), LastModified: h.Get("Last-Modified")}
+}
+
+// validatorsFromCDP reads ETag / Last-Modified from a Chrome response (header names may be lowercase)
+func validatorsFromCDP(h network.Headers) Validators {
+	var v Validators
+	for name, value := range h {
+		s, ok := value.(string)
+		if !ok {
+			continue
+		}
+		switch strings.ToLower(name) {
+		case "etag":
+			v.ETag = s
+		case "last-modified":
+			v.LastModified = s
+		}
+	}
+	return v
+}
+
+// fetchedPage is a fetched detail page; notModified means the site answered 304 and html is empty.
+// finalURL is where the page was served from after redirects ("" when unknown).
+type fetchedPage struct {
+	html        string
+	validators  Validators
+	notModified bool
+	finalURL    string
+}
+
+// ScrapePropertyIfModified re-scrapes a known detail page conditionally. With non-zero validators
+// an unchanged page returns ErrNotModified without being parsed (the request still counts against
+// DetailLimiter, which the caller acquires). Otherwise it behaves like ScrapePropertyContext; the
+// returned property carries the new validators either way.
+func (s *Scraper) ScrapePropertyIfModified(ctx context.Context, inputURL string, v Validators) (*models.Property, error) {
+	return s.scrapeProperty(ctx, inputURL, "", v)
+}
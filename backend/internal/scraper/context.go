@@ -0,0 +1,131 @@
+package scraper
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"maps"
+	"math"
+	"slices"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// contextPropertyPaths are where the listing object sits inside __SERVER_SIDE_CONTEXT__
+var contextPropertyPaths = []string{"page.Property", "page.property", "Property", "property"}
+
+// contextIntKeys / contextStringKeys / contextListKeys are the property object keys handed to
+// extractFromContextData, with the same value types the regex path (extractPropertyDataFromHTML) produces
+var (
+	contextIntKeys    = []string{"Price", "MinutesFromStation", "FloorNum", "YearsOld"}
+	contextStringKeys = []string{
+		"BuildingName", "AddressName", "StationName", "Direction", "StructureName", "RoomLayoutBreakdown",
+		"KindName", "FloorNameLabel", "ParkingAreaLabel", "ContractPeriod", "Insurance", "RoomLayoutImageUrl",
+	}
+	contextListKeys = []string{"Facilities", "Pickouts"} // kept as JSON array strings
+	// contextCoordinatePaths are latitude/longitude key pairs on (or under) the listing object;
+	// the first pair present is returned as "Latitude" / "Longitude" (float64)
+	contextCoordinatePaths = [][2]string{
+		{"Latitude", "Longitude"}, {"Lat", "Lng"}, {"Lat", "Lon"},
+		{"Map.Latitude", "Map.Longitude"}, {"Map.Lat", "Map.Lng"}, {"Location.Latitude", "Location.Longitude"},
+	}
+)
+
+// errNoContextProperty is returned when the blob parses but holds no listing object
+var errNoContextProperty = errors.New("property object not found in __SERVER_SIDE_CONTEXT__")
+
+// extractPropertyDataFromJSON unmarshals __SERVER_SIDE_CONTEXT__ and returns the listing object's
+// fields keyed like extractPropertyDataFromHTML. Unlike the regex path it only reads the listing
+// object itself, so prices or layouts from recommendation/ad blocks elsewhere in the blob are ignored.
+func extractPropertyDataFromJSON(doc *goquery.Document) (map[string]interface{}, error) {
+	contextJSON, err := extractServerSideContextJSON(doc)
+	if err != nil {
+		return nil, err
+	}
+	obj := findContextProperty(contextJSON)
+	if obj == nil {
+		return nil, errNoContextProperty
+	}
+
+	result := make(map[string]interface{})
+	for _, key := range contextIntKeys {
+		if v, ok := getInt(obj, key); ok {
+			result[key] = v
+		}
+	}
+	for _, key := range contextStringKeys {
+		if v, ok := getString(obj, key); ok && v != "" {
+			result[key] = v
+		}
+	}
+	// The 間取り図 may only be tagged inside the image lists
+	if _, ok := result["RoomLayoutImageUrl"]; !ok {
+		if layout := contextLayoutImage(obj); layout != "" {
+			result["RoomLayoutImageUrl"] = layout
+		}
+	}
+	for _, key := range contextListKeys {
+		if list, ok := obj[key].([]interface{}); ok {
+			if b, err := json.Marshal(list); err == nil {
+				result[key] = string(b)
+			}
+		}
+	}
+
+	// MonopolyArea is in units of 0.01 sqm; a fractional value is already in sqm
+	if area, ok := getFloat(obj, "MonopolyArea"); ok && area > 0 {
+		if area != math.Trunc(area) {
+			area *= 100
+		}
+		result["MonopolyArea"] = int(math.Round(area))
+	}
+
+	for _, pair := range contextCoordinatePaths {
+		lat, latOK := getFloat(obj, pair[0])
+		lng, lngOK := getFloat(obj, pair[1])
+		if latOK && lngOK {
+			result["Latitude"], result["Longitude"] = lat, lng
+			break
+		}
+	}
+
+	log.Printf("[extractPropertyDataFromJSON] Extracted %d fields from the structured context", len(result))
+	return result, nil
+}
+
+// findContextProperty returns the listing object: one of contextPropertyPaths, otherwise the first
+// nested object carrying both Price and RoomLayoutBreakdown (nil if there is none)
+func findContextProperty(contextJSON map[string]interface{}) map[string]interface{} {
+	for _, path := range contextPropertyPaths {
+		if v, ok := getNestedValue(contextJSON, path); ok {
+			if obj, ok := v.(map[string]interface{}); ok {
+				return obj
+			}
+		}
+	}
+
+	var walk func(v interface{}) map[string]interface{}
+	walk = func(v interface{}) map[string]interface{} {
+		switch t := v.(type) {
+		case map[string]interface{}:
+			_, hasPrice := t["Price"]
+			_, hasLayout := t["RoomLayoutBreakdown"]
+			if hasPrice && hasLayout {
+				return t
+			}
+			for _, key := range slices.Sorted(maps.Keys(t)) { // deterministic order
+				if obj := walk(t[key]); obj != nil {
+					return obj
+				}
+			}
+		case []interface{}:
+			for _, child := range t {
+				if obj := walk(child); obj != nil {
+					return obj
+				}
+			}
+		}
+		return nil
+	}
+	return walk(contextJSON)
+}
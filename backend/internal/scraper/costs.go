@@ -0,0 +1,70 @@
+package scraper
+
+import (
+	"real-estate-portal/internal/models"
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// costSplitPattern splits combined headers/values such as "敷金/保証金" → "1ヶ月/なし"
+var costSplitPattern = regexp.MustCompile(`\s*[/／]\s*`)
+
+// costDashValues are the dash variants Yahoo uses for "none" in the cost rows
+var costDashValues = map[string]bool{"-": true, "−": true, "ー": true, "―": true, "－": true, "—": true}
+
+// applyContractCosts fills the contract cost text fields (管理費・共益費 / 敷金 / 礼金 / 保証金 / 敷引)
+// from the detail table. Values are kept as scraped ("1ヶ月", "10,000円"), with whitespace
+// collapsed and dash-only cells stored as "なし"; numeric companions come from NormalizeFees.
+func applyContractCosts(doc *goquery.Document, property *models.Property) {
+	doc.Find("th, dt").Each(func(_ int, header *goquery.Selection) {
+		key := strings.Join(strings.Fields(header.Text()), "")
+		if key == "" || len(key) > 60 {
+			return
+		}
+		value := strings.Join(strings.Fields(header.NextFiltered("td, dd").Text()), " ")
+		if value == "" || len(value) > 200 {
+			return
+		}
+
+		// "敷金/保証金" with "1ヶ月/なし": pair each header part with its value part
+		keys := costSplitPattern.Split(key, -1)
+		values := costSplitPattern.Split(value, -1)
+		if len(keys) < 2 || len(keys) != len(values) {
+			keys, values = []string{key}, []string{value}
+		}
+		for i, k := range keys {
+			if field := contractCostField(property, k); field != nil && *field == "" {
+				*field = normalizeCostValue(values[i])
+			}
+		}
+	})
+}
+
+// contractCostField returns the Property field a cost header maps to, or nil.
+// 敷引 and 保証金 are checked before 敷金 since "敷引" / "保証金（敷金）" would otherwise match it.
+func contractCostField(property *models.Property, key string) *string {
+	switch {
+	case strings.Contains(key, "管理費") || strings.Contains(key, "共益費"):
+		return &property.ManagementFee
+	case strings.Contains(key, "敷引"):
+		return &property.SecurityDeposit
+	case strings.Contains(key, "保証金"):
+		return &property.GuarantorDeposit
+	case strings.Contains(key, "敷金"):
+		return &property.Deposit
+	case strings.Contains(key, "礼金"):
+		return &property.KeyMoney
+	}
+	return nil
+}
+
+// normalizeCostValue keeps the scraped text but stores dash-only cells as "なし"
+func normalizeCostValue(value string) string {
+	value = strings.TrimSpace(value)
+	if costDashValues[value] {
+		return "なし"
+	}
+	return value
+}
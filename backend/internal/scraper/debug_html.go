@@ -0,0 +1,139 @@
+package scraper
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Defaults for the debug HTML capture (ScraperConfig.DebugHTML*)
+const (
+	defaultDebugHTMLMaxBytes  = 2 << 20            // 2MiB per saved page
+	defaultDebugHTMLRetention = 7 * 24 * time.Hour // saved pages are pruned after a week
+	defaultDebugHTMLMaxFiles  = 200                // newest pages kept when the directory fills up
+)
+
+// debugFileIDPattern is what may go into a capture file name as-is; other IDs are hashed
+var debugFileIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,100}$`)
+
+// debugHTMLCapture saves the raw HTML of detail pages that yield no title or rent,
+// so what the site served (WAF interstitial, layout change) can be inspected later
+type debugHTMLCapture struct {
+	dir       string
+	maxBytes  int
+	retention time.Duration
+	maxFiles  int
+	mu        sync.Mutex
+}
+
+// newDebugHTMLCapture returns nil when dir is empty (capture off). Zero limits use the defaults.
+func newDebugHTMLCapture(dir string, maxBytes int, retention time.Duration, maxFiles int) *debugHTMLCapture {
+	if dir == "" {
+		return nil
+	}
+	if maxBytes <= 0 {
+		maxBytes = defaultDebugHTMLMaxBytes
+	}
+	if retention <= 0 {
+		retention = defaultDebugHTMLRetention
+	}
+	if maxFiles <= 0 {
+		maxFiles = defaultDebugHTMLMaxFiles
+	}
+	c := &debugHTMLCapture{dir: dir, maxBytes: maxBytes, retention: retention, maxFiles: maxFiles}
+	c.prune()
+	return c
+}
+
+// save writes html to <dir>/<propertyID>-<timestamp>.html (truncated to maxBytes), prunes old
+// captures and returns the path. Failures are logged only: capture must never fail a scrape.
+func (c *debugHTMLCapture) save(propertyID, html, reason string) string {
+	if c == nil {
+		return ""
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := os.MkdirAll(c.dir, 0o755); err != nil {
+		log.Printf("[DebugHTML] Cannot create %s: %v", c.dir, err)
+		return ""
+	}
+
+	if !debugFileIDPattern.MatchString(propertyID) {
+		hash := md5.Sum([]byte(propertyID))
+		propertyID = hex.EncodeToString(hash[:])
+	}
+	path := filepath.Join(c.dir, fmt.Sprintf("%s-%s.html", propertyID, time.Now().Format("20060102-150405.000")))
+
+	data := html
+	if len(data) > c.maxBytes {
+		data = data[:c.maxBytes] + fmt.Sprintf("\n<!-- truncated: %d of %d bytes kept -->\n", c.maxBytes, len(html))
+	}
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		log.Printf("[DebugHTML] Cannot write %s: %v", path, err)
+		return ""
+	}
+	log.Printf("[DebugHTML] Saved raw HTML (%s, %d bytes) to %s", reason, len(html), path)
+
+	c.pruneLocked()
+	return path
+}
+
+// prune removes captures older than the retention and the oldest ones beyond maxFiles
+func (c *debugHTMLCapture) prune() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.pruneLocked()
+}
+
+func (c *debugHTMLCapture) pruneLocked() {
+	entries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return // nothing captured yet
+	}
+
+	type capture struct {
+		path    string
+		modTime time.Time
+	}
+	var kept []capture
+	cutoff := time.Now().Add(-c.retention)
+	removed := 0
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".html") {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		path := filepath.Join(c.dir, entry.Name())
+		if info.ModTime().Before(cutoff) {
+			if os.Remove(path) == nil {
+				removed++
+			}
+			continue
+		}
+		kept = append(kept, capture{path, info.ModTime()})
+	}
+
+	if len(kept) > c.maxFiles {
+		slices.SortFunc(kept, func(a, b capture) int { return a.modTime.Compare(b.modTime) })
+		for _, old := range kept[:len(kept)-c.maxFiles] {
+			if os.Remove(old.path) == nil {
+				removed++
+			}
+		}
+	}
+	if removed > 0 {
+		log.Printf("[DebugHTML] Pruned %d old captures from %s", removed, c.dir)
+	}
+}
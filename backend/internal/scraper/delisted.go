@@ -0,0 +1,51 @@
+package scraper
+
+import (
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// delistedMarkers are the notices of Yahoo's ended-listing page (served with 200, not 404)
+var delistedMarkers = []string{
+	"掲載が終了しました",
+	"掲載を終了しました",
+	"掲載は終了しました",
+	"掲載終了しました",
+	"掲載期間が終了",
+}
+
+// isDelistedPage reports whether doc is the ended-listing page: a notice in the <title> or <h1>,
+// or in the body of a page that carries no listing (no __SERVER_SIDE_CONTEXT__, no 賃料 row), so
+// a recommendation block mentioning another ended listing does not count
+func isDelistedPage(doc *goquery.Document) bool {
+	heading := doc.Find("title").Text() + " " + doc.Find("h1").First().Text()
+	if containsAny(heading, delistedMarkers) {
+		return true
+	}
+	if !containsAny(doc.Find("body").Text(), delistedMarkers) {
+		return false
+	}
+	hasListing := false
+	doc.Find("script").EachWithBreak(func(_ int, script *goquery.Selection) bool {
+		hasListing = strings.Contains(script.Text(), "__SERVER_SIDE_CONTEXT__")
+		return !hasListing
+	})
+	doc.Find("th, dt").EachWithBreak(func(_ int, header *goquery.Selection) bool {
+		if strings.Join(strings.Fields(header.Text()), "") == "賃料" {
+			hasListing = true
+		}
+		return !hasListing
+	})
+	return !hasListing
+}
+
+// containsAny reports whether s contains one of substrs
+func containsAny(s string, substrs []string) bool {
+	for _, sub := range substrs {
+		if strings.Contains(s, sub) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,87 @@
+package scraper
+
+import (
+	"real-estate-portal/internal/models"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// maxNotesBytes bounds Notes (備考 / 初期費用 blocks); longer text is cut and ends in "…"
+const maxNotesBytes = 4096
+
+// noteRowLabels are the rows collected into Notes; 備考 itself goes in unlabeled, the others
+// under their heading (e.g. 【初期費用】)
+var noteRowLabels = map[string]string{
+	"備考":      "",
+	"特記事項":    "",
+	"初期費用":    "初期費用",
+	"その他初期費用": "初期費用",
+	"その他費用":   "その他費用",
+}
+
+// applyDetailRows fills the descriptive fields from the detail table rows (th/td or dt/dd):
+// 方位, 駐車場, 契約期間, 入居可能時期 and 条件等, plus FloorLabel from the 階数 / 所在階 / 階建
+// rows. Values already taken from __SERVER_SIDE_CONTEXT__ are kept; "-" cells are skipped.
+// 条件等 and the 備考 / 初期費用 blocks (Notes) keep their line breaks.
+func applyDetailRows(doc *goquery.Document, property *models.Property) {
+	set := func(field *string, value string, maxRunes int) {
+		if *field == "" {
+			*field = truncateRunes(value, maxRunes)
+		}
+	}
+	var notes []string
+	doc.Find("th, dt").Each(func(_ int, header *goquery.Selection) {
+		key := strings.Join(strings.Fields(header.Text()), "")
+		cell := header.NextFiltered("td, dd")
+		value := strings.Join(strings.Fields(cell.Text()), " ")
+		if value == "" || value == "-" || value == "－" || value == "―" {
+			return
+		}
+		if label, ok := noteRowLabels[key]; ok {
+			block := strings.Join(cellLines(cell), "\n")
+			if label != "" {
+				block = "【" + label + "】\n" + block
+			}
+			if !slices.Contains(notes, block) {
+				notes = append(notes, block)
+			}
+			return
+		}
+		switch key {
+		case "方位", "向き", "主要採光面":
+			set(&property.Direction, value, 50)
+		case "駐車場":
+			set(&property.Parking, value, 255)
+		case "契約期間":
+			set(&property.ContractPeriod, value, 50)
+		case "入居可能時期", "入居時期", "入居可能日", "入居":
+			set(&property.MoveInDate, value, 100)
+		case "条件等", "入居条件", "条件":
+			set(&property.Conditions, strings.Join(cellLines(cell), "\n"), 255)
+		}
+	})
+	if property.Notes == "" && len(notes) > 0 {
+		property.Notes = truncateText(strings.Join(notes, "\n\n"), maxNotesBytes)
+	}
+
+	// "地上3階建て/2階部分": NormalizeFloors later reads Floor / UnitFloor (2) from the full label
+	if property.FloorLabel == "" {
+		property.FloorLabel = extractFloorLabel(doc)
+	}
+}
+
+// truncateText cuts s to at most maxBytes on a rune boundary, ending it with "…" when cut
+func truncateText(s string, maxBytes int) string {
+	if len(s) <= maxBytes {
+		return s
+	}
+	const ellipsis = "…"
+	cut := maxBytes - len(ellipsis)
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return strings.TrimRight(s[:cut], " \n") + ellipsis
+}
@@ -0,0 +1,110 @@
+package scraper
+
+import (
+	"bufio"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+	"golang.org/x/net/html/charset"
+	"golang.org/x/text/encoding/japanese"
+	"golang.org/x/text/transform"
+)
+
+// acceptEncoding lists the encodings decodeBody can undo. Setting Accept-Encoding ourselves turns
+// off net/http's transparent gzip, so every body we read must go through decodeBody.
+const acceptEncoding = "gzip, deflate, br"
+
+// decodeBody returns resp.Body with the response's Content-Encoding removed. Encodings are undone
+// in reverse order of application; an unknown one is an ErrParse (the bytes would parse as garbage).
+// Closing the returned reader closes resp.Body.
+func decodeBody(resp *http.Response) (io.ReadCloser, error) {
+	var reader io.Reader = resp.Body
+	closers := []io.Closer{resp.Body}
+
+	encodings := strings.Split(resp.Header.Get("Content-Encoding"), ",")
+	for i := len(encodings) - 1; i >= 0; i-- {
+		switch enc := strings.ToLower(strings.TrimSpace(encodings[i])); enc {
+		case "", "identity":
+		case "gzip", "x-gzip":
+			gz, err := gzip.NewReader(reader)
+			if err != nil {
+				return nil, fmt.Errorf("%w: invalid gzip body: %w", ErrParse, err)
+			}
+			reader = gz
+			closers = append(closers, gz)
+		case "deflate":
+			fl, err := newDeflateReader(reader)
+			if err != nil {
+				return nil, fmt.Errorf("%w: invalid deflate body: %w", ErrParse, err)
+			}
+			reader = fl
+			closers = append(closers, fl)
+		case "br":
+			reader = brotli.NewReader(reader)
+		default:
+			return nil, fmt.Errorf("%w: unsupported Content-Encoding %q", ErrParse, enc)
+		}
+	}
+	return &decodedBody{Reader: reader, closers: closers}, nil
+}
+
+// newDeflateReader reads HTTP "deflate", which servers send either zlib-wrapped (RFC 9110)
+// or as a raw deflate stream; the zlib header is checked to tell them apart
+func newDeflateReader(r io.Reader) (io.ReadCloser, error) {
+	br := bufio.NewReader(r)
+	header, err := br.Peek(2)
+	if err != nil {
+		return nil, err
+	}
+	if header[0]&0x0f == 8 && (uint16(header[0])<<8|uint16(header[1]))%31 == 0 {
+		return zlib.NewReader(br)
+	}
+	return flate.NewReader(br), nil
+}
+
+// decodedBody closes the decompressors and then the underlying body
+type decodedBody struct {
+	io.Reader
+	closers []io.Closer
+}
+
+func (b *decodedBody) Close() error {
+	var first error
+	for i := len(b.closers) - 1; i >= 0; i-- {
+		if err := b.closers[i].Close(); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+// charsetSniffBytes is how much of the body is inspected for a BOM or <meta charset>
+const charsetSniffBytes = 1024
+
+// toUTF8 returns r transcoded to UTF-8 and the charset it was read as. The charset comes from the
+// Content-Type header, a BOM or <meta charset> near the top; an undeclared body that is not valid
+// UTF-8 is taken as Shift_JIS, the usual legacy encoding on Japanese sites. UTF-8 is passed through
+// untouched. Only raw HTTP bodies go through here: headless Chrome already returns UTF-8.
+func toUTF8(r io.Reader, contentType string) (io.Reader, string, error) {
+	br := bufio.NewReaderSize(r, charsetSniffBytes)
+	head, err := br.Peek(charsetSniffBytes)
+	if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		return nil, "", err
+	}
+
+	enc, name, certain := charset.DetermineEncoding(head, contentType)
+	if name == "utf-8" {
+		return br, name, nil
+	}
+	// windows-1252 without a declaration is x/net's last-resort guess, not something the page said
+	if !certain && name == "windows-1252" {
+		enc, name = japanese.ShiftJIS, "shift_jis"
+	}
+	return transform.NewReader(br, enc.NewDecoder()), name, nil
+}
@@ -0,0 +1,52 @@
+package scraper
+
+import (
+	"errors"
+	"real-estate-portal/internal/errtext"
+)
+
+// Sentinel errors returned (wrapped) by the request and parse paths. Callers decide retries
+// and status codes with errors.Is instead of matching message text; each also carries its
+// errtext code so stored last_error_code values stay the same.
+var (
+	ErrNotFound    error = &codedError{code: errtext.CodeNotFound, msg: "permanent_fail: not found"}
+	ErrWAFBlocked  error = &codedError{code: errtext.CodeWAF, msg: "WAF block detected"}
+	ErrCircuitOpen error = &codedError{code: errtext.CodeCircuitOpen, msg: "circuit breaker open"}
+	ErrRateLimited error = &codedError{code: errtext.CodeRateLimited, msg: "rate limited"}
+	ErrParse       error = &codedError{code: errtext.CodeParse, msg: "parse error"}
+
+	// ErrRobotsDisallowed is returned before any request when robots.txt forbids the URL
+	ErrRobotsDisallowed error = &codedError{code: errtext.CodeRobots, msg: "disallowed by robots.txt"}
+
+	// ErrProxy is returned when every configured proxy failed (connect error, CONNECT refused,
+	// 407); the site itself was never reached
+	ErrProxy error = &codedError{code: errtext.CodeProxy, msg: "proxy error"}
+
+	// ErrTimeout is returned when an attempt got no response within ScraperConfig.AttemptTimeout,
+	// or the retry budget (ScraperConfig.MaxTotal) ran out before the next attempt
+	ErrTimeout error = &codedError{code: errtext.CodeTimeout, msg: "timeout"}
+
+	// ErrDelisted is returned for Yahoo's 掲載終了 page, which is served with 200 instead of 404;
+	// nothing is parsed from it
+	ErrDelisted error = &codedError{code: errtext.CodeDelisted, msg: "listing ended"}
+
+	// ErrBuildingPage is matched by a *BuildingPageError: the URL is a building page listing
+	// several units, which are returned instead of one property
+	ErrBuildingPage error = &codedError{code: errtext.CodeBuilding, msg: "building page"}
+
+	// ErrUnknownSource is returned by Registry.ForURL for a host no PropertySource handles
+	ErrUnknownSource error = &codedError{code: errtext.CodeUnsupported, msg: "no scraper registered for this site"}
+)
+
+// codedError is a sentinel that also reports its errtext code (see errtext.FromError)
+type codedError struct {
+	code string
+	msg  string
+}
+
+func (e *codedError) Error() string     { return e.msg }
+func (e *codedError) ErrorCode() string { return e.code }
+
+// ErrNotModified is returned by ScrapePropertyIfModified when the site answers 304 Not Modified.
+// It is not a failure: the stored listing is still current.
+var ErrNotModified = errors.New("304 not modified")
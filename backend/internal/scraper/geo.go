@@ -0,0 +1,75 @@
+package scraper
+
+import (
+	"log"
+	"net/url"
+	"real-estate-portal/internal/models"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// mapLatLngParams are the query parameter pairs map embeds put coordinates in
+// (Yahoo!地図: lat/lon, Google Maps: lat/lng, some portals: ido/keido)
+var mapLatLngParams = [][2]string{{"lat", "lon"}, {"lat", "lng"}, {"ido", "keido"}}
+
+// mapPointParams hold "lat,lng" in a single parameter (Google Maps q= / ll= / center=)
+var mapPointParams = []string{"q", "ll", "center"}
+
+// applyMapCoordinates sets the property's coordinates from the first map iframe whose URL
+// carries an in-Japan latitude/longitude. Pages without a map leave them nil.
+func applyMapCoordinates(doc *goquery.Document, property *models.Property) {
+	doc.Find("iframe[src], iframe[data-src]").EachWithBreak(func(_ int, iframe *goquery.Selection) bool {
+		src, ok := iframe.Attr("data-src")
+		if !ok || src == "" {
+			src, _ = iframe.Attr("src")
+		}
+		lat, lng, ok := mapURLCoordinates(src)
+		if !ok || !property.SetCoordinates(lat, lng) {
+			return true
+		}
+		log.Printf("[applyMapCoordinates] id=%s Coordinates from map iframe: %f,%f", property.SourcePropertyID, lat, lng)
+		return false
+	})
+}
+
+// mapURLCoordinates reads a latitude/longitude pair from a map URL's query string
+func mapURLCoordinates(rawURL string) (lat, lng float64, ok bool) {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return 0, 0, false
+	}
+	query := u.Query()
+
+	for _, pair := range mapLatLngParams {
+		latValue, lngValue := query.Get(pair[0]), query.Get(pair[1])
+		if latValue == "" || lngValue == "" {
+			continue
+		}
+		if lat, lng, ok := parseLatLng(latValue, lngValue); ok {
+			return lat, lng, true
+		}
+	}
+	for _, param := range mapPointParams {
+		if latValue, lngValue, found := strings.Cut(query.Get(param), ","); found {
+			if lat, lng, ok := parseLatLng(latValue, lngValue); ok {
+				return lat, lng, true
+			}
+		}
+	}
+	return 0, 0, false
+}
+
+// parseLatLng parses a latitude and longitude given as decimal strings
+func parseLatLng(latValue, lngValue string) (float64, float64, bool) {
+	lat, err := strconv.ParseFloat(strings.TrimSpace(latValue), 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	lng, err := strconv.ParseFloat(strings.TrimSpace(lngValue), 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	return lat, lng, true
+}
@@ -0,0 +1,144 @@
+package scraper
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// imageVerifyCacheMax caps the cached image checks; the cache starts over when it is full
+const imageVerifyCacheMax = 10000
+
+// yahooImageHosts are the Yahoo domains (and their subdomains) whose image checks wait on the
+// Yahoo list limiter like page requests
+var yahooImageHosts = []string{"yahoo.co.jp", "yimg.jp"}
+
+// imageVerifyCache holds HEAD results per image URL for the process lifetime, so an image
+// shared by listings or re-scraped daily is checked once. Only answers with a status are
+// cached; network errors are checked again next time.
+var imageVerifyCache = struct {
+	sync.Mutex
+	ok map[string]bool
+}{ok: make(map[string]bool)}
+
+// ResetImageVerifyCache forgets every cached image check (the offline harness checks the same
+// fixture images in several scenarios)
+func ResetImageVerifyCache() {
+	imageVerifyCache.Lock()
+	defer imageVerifyCache.Unlock()
+	imageVerifyCache.ok = make(map[string]bool)
+}
+
+// cachedImageCheck returns the cached result for imageURL
+func cachedImageCheck(imageURL string) (ok, found bool) {
+	imageVerifyCache.Lock()
+	defer imageVerifyCache.Unlock()
+	ok, found = imageVerifyCache.ok[imageURL]
+	return ok, found
+}
+
+// storeImageCheck caches the result for imageURL
+func storeImageCheck(imageURL string, ok bool) {
+	imageVerifyCache.Lock()
+	defer imageVerifyCache.Unlock()
+	if len(imageVerifyCache.ok) >= imageVerifyCacheMax {
+		imageVerifyCache.ok = make(map[string]bool)
+	}
+	imageVerifyCache.ok[imageURL] = ok
+}
+
+// verifyImageURL reports whether imageURL is accessible (HEAD answers 200). With VerifyImages
+// off the image is accepted unchecked. Results are cached per URL, and checks against the site's
+// own hosts wait on its list limiter.
+func (s *Scraper) verifyImageURL(ctx context.Context, imageURL string) bool {
+	if !s.verifyImages {
+		s.recordStats(ctx, func(st *ScrapeStats) { st.ImageChecksSkipped++ })
+		return true
+	}
+	if ok, found := cachedImageCheck(imageURL); found {
+		s.recordStats(ctx, func(st *ScrapeStats) {
+			st.ImageChecksSkipped++
+			if !ok {
+				st.ImageChecksFailed++
+			}
+		})
+		return ok
+	}
+
+	ok, answered := s.checkImage(ctx, imageURL)
+	if answered {
+		storeImageCheck(imageURL, ok)
+	}
+	if !ok {
+		s.recordStats(ctx, func(st *ScrapeStats) { st.ImageChecksFailed++ })
+	}
+	return ok
+}
+
+// checkImage sends the HEAD request; answered is false when no response came back
+func (s *Scraper) checkImage(ctx context.Context, imageURL string) (ok, answered bool) {
+	if s.isSiteImageHost(imageURL) {
+		limiter := s.limiter
+		waitStart := time.Now()
+		limiter.Acquire()
+		defer limiter.Release()
+		s.recordStats(ctx, func(st *ScrapeStats) { st.LimiterWait += time.Since(waitStart) })
+	}
+
+	// Use a shorter timeout for image verification (ctx only carries the call's stats here:
+	// a check already started is not cut short)
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+	defer cancel()
+
+	// Create HEAD request to check without downloading the image
+	req, err := http.NewRequestWithContext(ctx, "HEAD", imageURL, nil)
+	if err != nil {
+		log.Printf("[verifyImageURL] Error creating request for %s: %v", imageURL, err)
+		return false, true
+	}
+
+	s.setRequestHeaders(req, requestImage, s.baseURL+"/")
+
+	// Same transport (and proxies) as the page requests
+	resp, err := s.do(req)
+	if err != nil {
+		log.Printf("[verifyImageURL] Error verifying image %s: %v", imageURL, err)
+		return false, false
+	}
+	defer resp.Body.Close()
+
+	// Accept 200 OK
+	if resp.StatusCode != 200 {
+		log.Printf("[verifyImageURL] Image verification failed for %s: status code %d", imageURL, resp.StatusCode)
+		return false, true
+	}
+
+	log.Printf("[verifyImageURL] Image verified successfully: %s", imageURL)
+	return true, true
+}
+
+// isSiteImageHost reports whether imageURL is served by s's site: the BaseURL host, or for
+// the Yahoo scraper any Yahoo domain
+func (s *Scraper) isSiteImageHost(imageURL string) bool {
+	u, err := url.Parse(imageURL)
+	if err != nil {
+		return false
+	}
+	host := u.Hostname()
+	if base, err := url.Parse(s.baseURL); err == nil && base.Hostname() == host {
+		return true
+	}
+	if s.limits != nil {
+		return false
+	}
+	for _, domain := range yahooImageHosts {
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,113 @@
+package scraper
+
+import (
+	"context"
+	"log"
+	"net/url"
+	"real-estate-portal/internal/models"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+var (
+	// layoutImageListKeys are the image arrays on the listing object; the 間取り図 is the entry
+	// whose caption / category says so
+	layoutImageListKeys    = []string{"ResizedExternalImageUrls", "ExternalImageUrls", "Images", "ImageList"}
+	layoutImageCaptionKeys = []string{"Category", "CategoryName", "Caption", "Comment", "Type", "Label"}
+	layoutImageURLKeys     = []string{"Url", "URL", "ImageUrl", "ExternalImageUrl"}
+)
+
+// isLayoutImageCaption reports whether an image caption / category marks the floor plan image
+func isLayoutImageCaption(caption string) bool {
+	lower := strings.ToLower(caption)
+	return strings.Contains(caption, "間取") || strings.Contains(lower, "madori") ||
+		strings.Contains(lower, "layout") || strings.Contains(lower, "floorplan")
+}
+
+// contextLayoutImage returns the 間取り図 entry of the listing object's image lists ("" if none is
+// tagged; an untagged photo is never taken as the layout)
+func contextLayoutImage(obj map[string]interface{}) string {
+	for _, listKey := range layoutImageListKeys {
+		list, _ := obj[listKey].([]interface{})
+		for _, item := range list {
+			entry, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			tagged := false
+			for _, key := range layoutImageCaptionKeys {
+				if caption, ok := getString(entry, key); ok && isLayoutImageCaption(caption) {
+					tagged = true
+					break
+				}
+			}
+			if !tagged {
+				continue
+			}
+			for _, key := range layoutImageURLKeys {
+				if u, ok := getString(entry, key); ok && u != "" {
+					return u
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// extractLayoutImage finds an <img> whose alt / title is 間取り図 (lazy-loaded data-src first) and
+// returns its URL resolved against the page URL
+func extractLayoutImage(doc *goquery.Document, pageURL string) string {
+	layout := ""
+	doc.Find("img").EachWithBreak(func(_ int, img *goquery.Selection) bool {
+		alt, _ := img.Attr("alt")
+		title, _ := img.Attr("title")
+		if !isLayoutImageCaption(alt) && !isLayoutImageCaption(title) {
+			return true
+		}
+		src, ok := img.Attr("data-src")
+		if !ok || strings.TrimSpace(src) == "" {
+			src, _ = img.Attr("src")
+		}
+		src = strings.TrimSpace(src)
+		if src == "" || strings.HasPrefix(src, "data:") {
+			return true
+		}
+		layout = resolveImageURL(pageURL, src)
+		return layout == ""
+	})
+	return layout
+}
+
+// resolveImageURL makes src absolute against pageURL ("" when either does not parse)
+func resolveImageURL(pageURL, src string) string {
+	base, err := url.Parse(pageURL)
+	if err != nil {
+		return ""
+	}
+	ref, err := url.Parse(src)
+	if err != nil {
+		return ""
+	}
+	return base.ResolveReference(ref).String()
+}
+
+// applyRoomLayoutImage sets RoomLayoutImageURL from the embedded JSON (already applied by
+// extractFromContextData), else from the page's 間取り図 <img>, which is checked with a HEAD request
+// since the markup often carries a "no image" placeholder. A layout equal to the main photo is
+// dropped rather than duplicating ImageURL.
+func (s *Scraper) applyRoomLayoutImage(ctx context.Context, doc *goquery.Document, pageURL string, property *models.Property) {
+	if property.RoomLayoutImageURL == "" {
+		if layout := extractLayoutImage(doc, pageURL); layout != "" {
+			if s.verifyImageURL(ctx, layout) {
+				property.RoomLayoutImageURL = layout
+			} else {
+				log.Printf("[applyRoomLayoutImage] id=%s Dropped unreachable layout image %s", property.SourcePropertyID, layout)
+			}
+		}
+	}
+	if property.RoomLayoutImageURL != "" && property.RoomLayoutImageURL == property.ImageURL {
+		log.Printf("[applyRoomLayoutImage] id=%s Layout image is the main photo, leaving it empty", property.SourcePropertyID)
+		property.RoomLayoutImageURL = ""
+	}
+}
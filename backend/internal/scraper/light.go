@@ -0,0 +1,141 @@
+package scraper
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"real-estate-portal/internal/models"
+	"real-estate-portal/internal/tracing"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// LightResult is what a light refresh reads from a detail page: enough to tell that the
+// listing is still up and whether its headline (title, photo, rent) changed
+type LightResult struct {
+	URL              string // normalized URL the page was served from (after redirects)
+	SourcePropertyID string // listing ID of URL; differs from the requested one after a relisting redirect
+	Title            string // og:title without the site suffix ("" when missing)
+	ImageURL         string // og:image ("" when missing)
+	Rent             *int   // nil when the page shows no plausible rent
+}
+
+// ScrapePropertyLight downloads a detail page and reads only og:title, og:image and the rent.
+// It skips the headless browser, the human-pace sleep and the full parse, so the listing can be
+// confirmed far more often than it is fully scraped. Errors are the same typed errors as
+// ScrapeProperty (ErrNotFound, ErrDelisted for the 掲載終了 page, ErrWAFBlocked, ...).
+// NOTE: Rate limiting (DetailLimiter) should be applied by the caller, as for ScrapeProperty.
+func (s *Scraper) ScrapePropertyLight(inputURL string) (*LightResult, error) {
+	return s.ScrapePropertyLightContext(context.Background(), inputURL)
+}
+
+// ScrapePropertyLightContext is ScrapePropertyLight under ctx (a Referer set with WithReferer is sent)
+func (s *Scraper) ScrapePropertyLightContext(ctx context.Context, inputURL string) (_ *LightResult, retErr error) {
+	ctx, span := tracing.Start(ctx, "scraper.ScrapePropertyLight", attribute.String("scrape.url", inputURL))
+	defer func() {
+		tracing.RecordError(span, retErr)
+		span.End()
+	}()
+	ctx, done := s.startStats(ctx)
+	defer done()
+
+	normalizedURL := normalizeURL(inputURL)
+	log.Printf("[ScrapePropertyLight] Refreshing %s", normalizedURL)
+
+	if err := s.checkRobots(ctx, normalizedURL); err != nil {
+		log.Printf("[ScrapePropertyLight] Skipping %s: %v", normalizedURL, err)
+		return nil, err
+	}
+	if err := s.warmUpSession(ctx); err != nil {
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("scrape canceled: %w", ctx.Err())
+		}
+		log.Printf("[ScrapePropertyLight] Warning: Failed to warm up session: %v", err)
+	}
+
+	page, err := s.fetchHTML(ctx, normalizedURL, refererFrom(ctx), Validators{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch URL: %w", err)
+	}
+
+	pageURL := normalizedURL
+	if page.finalURL != "" {
+		pageURL = normalizeURL(page.finalURL)
+	}
+
+	if err := acquireParse(ctx); err != nil {
+		return nil, err
+	}
+	defer ParseLimiter.Release()
+	doc, err := parseDocument(strings.NewReader(page.html))
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to parse HTML: %w", ErrParse, err)
+	}
+	if isDelistedPage(doc) {
+		log.Printf("[ScrapePropertyLight] %s is an ended-listing page (掲載終了)", pageURL)
+		return nil, fmt.Errorf("%w: %s", ErrDelisted, pageURL)
+	}
+
+	result := &LightResult{
+		URL:              pageURL,
+		SourcePropertyID: yahooPropertyIDOrHash(pageURL),
+	}
+	if title, ok := doc.Find("meta[property='og:title']").Attr("content"); ok {
+		result.Title = cleanTitle(strings.TrimSpace(title))
+	}
+	if image, ok := doc.Find("meta[property='og:image']").Attr("content"); ok {
+		result.ImageURL = strings.TrimSpace(image)
+	}
+	if rent := s.lightRent(doc); rent > 0 {
+		result.Rent = &rent
+	}
+
+	rentText := "none"
+	if result.Rent != nil {
+		rentText = strconv.Itoa(*result.Rent)
+	}
+	log.Printf("[ScrapePropertyLight] %s: title=%q rent=%s image=%t", pageURL, result.Title, rentText, result.ImageURL != "")
+	return result, nil
+}
+
+// lightRent reads the rent the way the full parse prefers it: a configured rent selector, the
+// embedded JSON's Price, the 賃料 row, then an amount next to 賃料 in the text. 0 = none found.
+func (s *Scraper) lightRent(doc *goquery.Document) int {
+	if text := s.selectorText(doc, SelectorRent); text != "" {
+		value := strings.ReplaceAll(text, " ", "")
+		if i := strings.IndexAny(value, "（(/／"); i > 0 {
+			value = value[:i]
+		}
+		if amount := models.ParseYenAmount(value); amount != nil && validRent(*amount) {
+			return *amount
+		}
+	}
+	// Only the listing object: recommendation blocks in the blob carry prices of their own
+	if obj := contextPropertyObject(doc); obj != nil {
+		if price, ok := getInt(obj, "Price"); ok && validRent(price) {
+			return price
+		}
+	}
+	if rent := extractRentFromTable(doc); rent > 0 {
+		return rent
+	}
+	return extractRent(doc.Find("body").Text())
+}
+
+// ScrapeLight implements LightSource
+func (s *Scraper) ScrapeLight(ctx context.Context, detailURL string) (*LightResult, error) {
+	return s.ScrapePropertyLightContext(ctx, detailURL)
+}
+
+// contextPropertyObject returns the listing object of __SERVER_SIDE_CONTEXT__ (nil when the page
+// has no blob, it is not valid JSON, or it holds no listing)
+func contextPropertyObject(doc *goquery.Document) map[string]interface{} {
+	contextJSON, err := extractServerSideContextJSON(doc)
+	if err != nil {
+		return nil
+	}
+	return findContextProperty(contextJSON)
+}
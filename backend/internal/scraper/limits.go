@@ -0,0 +1,117 @@
+package scraper
+
+import (
+	"log"
+	"net/url"
+	"real-estate-portal/internal/ratelimit"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// YahooHost is the host Yahoo's shared list limiter and circuit breaker are registered under
+const YahooHost = "realestate.yahoo.co.jp"
+
+// listPacing is the list limiter a source's shared limits start with
+type listPacing struct {
+	baseDelay time.Duration
+	jitter    time.Duration
+}
+
+// hostLimits are one host's shared list limiter and circuit breaker
+type hostLimits struct {
+	source  string
+	list    *ratelimit.YahooLimiter
+	breaker *CircuitBreaker
+}
+
+// sharedLimits is the default registry of list limiters and circuit breakers keyed by host.
+// Scrapers built without explicit ones (NewScraperWithConfig, NewSuumoSource) take their
+// host's entry, so every scraper of a site in the process paces against one limiter and
+// trips one breaker, while sites (and test servers) never share state.
+var sharedLimits = struct {
+	mu     sync.Mutex
+	byHost map[string]*hostLimits
+	pacing map[string]listPacing // per source, overridden by ConfigureSourceLimits
+}{
+	byHost: make(map[string]*hostLimits),
+	pacing: map[string]listPacing{
+		"yahoo":         {baseDelay: 8000 * time.Millisecond, jitter: 4000 * time.Millisecond}, // 8-12s between list pages
+		suumoSourceName: {baseDelay: 5 * time.Second, jitter: 3 * time.Second},                 // 5-8s between requests
+	},
+}
+
+// Circuit breaker defaults for every host: 8 failures out of 20 requests (or 2 consecutive
+// 403/429/500) open it, and it stays open for an hour before a half-open attempt
+const (
+	breakerFailureThreshold = 8
+	breakerResetTimeout     = 1 * time.Hour
+)
+
+// SharedLimits returns the process-wide list limiter and circuit breaker for host, creating
+// them on first use with source's list pacing ("yahoo" or "suumo"; others get Yahoo's)
+func SharedLimits(source, host string) (*ratelimit.YahooLimiter, *CircuitBreaker) {
+	host = strings.ToLower(host)
+
+	sharedLimits.mu.Lock()
+	defer sharedLimits.mu.Unlock()
+
+	limits, ok := sharedLimits.byHost[host]
+	if !ok {
+		pacing, known := sharedLimits.pacing[source]
+		if !known {
+			pacing = sharedLimits.pacing["yahoo"]
+		}
+		limits = &hostLimits{
+			source:  source,
+			list:    ratelimit.NewYahooLimiter(1, pacing.baseDelay, pacing.jitter),
+			breaker: NewCircuitBreaker(breakerFailureThreshold, breakerResetTimeout),
+		}
+		sharedLimits.byHost[host] = limits
+	}
+	return limits.list, limits.breaker
+}
+
+// SharedHosts returns the hosts with shared limits, sorted
+func SharedHosts() []string {
+	sharedLimits.mu.Lock()
+	defer sharedLimits.mu.Unlock()
+
+	hosts := make([]string, 0, len(sharedLimits.byHost))
+	for host := range sharedLimits.byHost {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+	return hosts
+}
+
+// configureListPacing sets source's list pacing for hosts registered from now on and for its
+// hosts already registered (their limiters are updated in place, so scrapers keep sharing them)
+func configureListPacing(source string, baseDelay, jitter time.Duration) {
+	sharedLimits.mu.Lock()
+	defer sharedLimits.mu.Unlock()
+
+	sharedLimits.pacing[source] = listPacing{baseDelay: baseDelay, jitter: jitter}
+	for _, limits := range sharedLimits.byHost {
+		if limits.source == source {
+			limits.list.SetDelay(baseDelay, jitter)
+		}
+	}
+}
+
+// baseHost is the host of a scraper's origin, the key of its shared limits
+func baseHost(baseURL string) string {
+	u, err := url.Parse(baseURL)
+	if err != nil || u.Host == "" {
+		log.Printf("Scraper: no host in base URL %q, sharing Yahoo's limits", baseURL)
+		return YahooHost
+	}
+	return u.Host
+}
+
+// ListLimiter returns the request pacing s waits on before each page request
+func (s *Scraper) ListLimiter() *ratelimit.YahooLimiter { return s.limiter }
+
+// CircuitBreaker returns the WAF circuit breaker s records failures in (for status and reset)
+func (s *Scraper) CircuitBreaker() *CircuitBreaker { return s.circuitBreaker }
@@ -0,0 +1,216 @@
+package scraper
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// MaxListPages caps how many list pages one ScrapeListPages call may follow
+// (Yahoo shows ~30 listings per page, so 20 pages covers ~600 listings)
+const MaxListPages = 20
+
+// ListPage is the result of a list scrape: the detail URLs plus what the site reports about the
+// whole search on its first page. TotalOnSite and Pages are -1 when the page does not show them
+// in a form we can parse; the URLs are returned either way.
+type ListPage struct {
+	URLs         []string
+	TotalOnSite  int // result count from the list header ("1,234件")
+	Pages        int // page count from the pager, else TotalOnSite / listings per page
+	PagesVisited int // list pages actually fetched
+
+	foundOn map[string]string // detail URL → list page it was first found on (crawls only)
+}
+
+// Referer returns the list page detailURL was found on, or fallback when it is not known
+// (a single-page scrape: the list URL itself)
+func (p *ListPage) Referer(detailURL, fallback string) string {
+	if pageURL, ok := p.foundOn[detailURL]; ok {
+		return pageURL
+	}
+	return fallback
+}
+
+// ScrapeListPages scrapes listURL and follows its "次へ" pagination links for up to maxPages
+// pages (clamped to 1..MaxListPages), returning the deduplicated detail URLs in page order
+// and how many pages were visited. Each page goes through the source's list limiter/retry path
+// as ScrapeListPage. The crawl stops early on the last page or when a page adds no new URLs.
+// If a later page fails, the URLs collected so far are returned together with the error.
+func (s *Scraper) ScrapeListPages(listURL string, maxPages int) ([]string, int, error) {
+	return s.ScrapeListPagesContext(context.Background(), listURL, maxPages)
+}
+
+// ScrapeListPagesContext is ScrapeListPages that stops following pages once ctx ends
+func (s *Scraper) ScrapeListPagesContext(ctx context.Context, listURL string, maxPages int) ([]string, int, error) {
+	page, err := s.ScrapeListPagesWithMetaContext(ctx, listURL, maxPages)
+	return page.URLs, page.PagesVisited, err
+}
+
+// ScrapeListPagesWithMetaContext is ScrapeListPagesContext that also returns the result and
+// page counts reported on the first page
+func (s *Scraper) ScrapeListPagesWithMetaContext(ctx context.Context, listURL string, maxPages int) (*ListPage, error) {
+	ctx, done := s.startStats(ctx)
+	defer done()
+	return crawlListPages(ctx, listURL, maxPages, s.scrapeListPage)
+}
+
+// listPageFunc scrapes one list page: its URLs and counts (PagesVisited 1) and the next
+// page's URL ("" on the last page)
+type listPageFunc func(ctx context.Context, pageURL string) (*ListPage, string, error)
+
+// crawlListPages follows a source's list pages with scrapePage (see ScrapeListPages). The
+// result is never nil; on error it holds what was collected before the failing page.
+func crawlListPages(ctx context.Context, listURL string, maxPages int, scrapePage listPageFunc) (*ListPage, error) {
+	if maxPages < 1 {
+		maxPages = 1
+	}
+	if maxPages > MaxListPages {
+		maxPages = MaxListPages
+	}
+
+	result := &ListPage{TotalOnSite: -1, Pages: -1, foundOn: make(map[string]string)}
+	seenURLs := make(map[string]bool)
+	visitedPages := make(map[string]bool)
+
+	for pageURL := listURL; pageURL != "" && result.PagesVisited < maxPages; {
+		visitedPages[pageURL] = true
+		page, nextURL, err := scrapePage(ctx, pageURL)
+		if err != nil {
+			log.Printf("[ScrapeListPages] Stopped at page %d (%s): %v", result.PagesVisited+1, pageURL, err)
+			return result, fmt.Errorf("list page %d: %w", result.PagesVisited+1, err)
+		}
+		result.PagesVisited++
+		if result.PagesVisited == 1 {
+			result.TotalOnSite, result.Pages = page.TotalOnSite, page.Pages
+		}
+
+		added := 0
+		for _, u := range page.URLs {
+			if !seenURLs[u] {
+				seenURLs[u] = true
+				result.URLs = append(result.URLs, u)
+				result.foundOn[u] = pageURL
+				added++
+			}
+		}
+		if added == 0 {
+			log.Printf("[ScrapeListPages] Page %d added no new URLs, stopping", result.PagesVisited)
+			break
+		}
+
+		// Guard against pagers that link back to a page already visited
+		if visitedPages[nextURL] {
+			nextURL = ""
+		}
+		pageURL = nextURL
+	}
+
+	log.Printf("[ScrapeListPages] Visited %d page(s) (max %d), found %d unique property URLs from %s (site reports %d results / %d pages)",
+		result.PagesVisited, maxPages, len(result.URLs), listURL, result.TotalOnSite, result.Pages)
+	return result, nil
+}
+
+var (
+	// "1,234件" as the whole text of a count element
+	resultCountPattern = regexp.MustCompile(`^([0-9][0-9,]*)件`)
+	// "検索結果 1,234件" / "該当物件数：1,234件" / "全1,234件" anywhere in the page text
+	resultCountTextPattern = regexp.MustCompile(`(?:検索結果|該当物件数?|該当件数|全)\s*[:：]?\s*([0-9][0-9,]*)\s*件`)
+)
+
+// parseResultCount returns the search's total result count shown on a list page, or -1.
+// Elements whose class names a count or hit total ("ListHeader__count", SUUMO's
+// "paginate_set-hit") are tried before a labelled "検索結果 N件" anywhere in the text, so
+// counts of unrelated blocks (新着 3件) are not picked up.
+func parseResultCount(doc *goquery.Document) int {
+	count := -1
+	doc.Find(`[class*="count"], [class*="Count"], [class*="hit"], [class*="Hit"]`).EachWithBreak(func(_ int, sel *goquery.Selection) bool {
+		text := strings.Join(strings.Fields(sel.Text()), "")
+		if m := resultCountPattern.FindStringSubmatch(text); m != nil {
+			count = parseCount(m[1])
+		}
+		return count < 0
+	})
+	if count >= 0 {
+		return count
+	}
+	if m := resultCountTextPattern.FindStringSubmatch(doc.Find("body").Text()); m != nil {
+		return parseCount(m[1])
+	}
+	return -1
+}
+
+func parseCount(digits string) int {
+	n, err := strconv.Atoi(strings.ReplaceAll(digits, ",", ""))
+	if err != nil {
+		return -1
+	}
+	return n
+}
+
+// parsePageCount returns the number of result pages: the highest page number in the pager,
+// else the total divided by the listings on this (first) page, else -1
+func parsePageCount(doc *goquery.Document, total, perPage int) int {
+	pages := -1
+	doc.Find(`[class*="ager"] a, [class*="ager"] span, [class*="aginat"] a, [class*="aginat"] span`).Each(func(_ int, sel *goquery.Selection) {
+		if n, err := strconv.Atoi(strings.TrimSpace(sel.Text())); err == nil && n > pages {
+			pages = n
+		}
+	})
+	if pages > 0 {
+		return pages
+	}
+	if total >= 0 && perPage > 0 {
+		return max(1, (total+perPage-1)/perPage)
+	}
+	return -1
+}
+
+// listPageMeta builds a one-page ListPage with the counts the page reports
+func listPageMeta(doc *goquery.Document, urls []string) *ListPage {
+	total := parseResultCount(doc)
+	return &ListPage{
+		URLs:         urls,
+		TotalOnSite:  total,
+		Pages:        parsePageCount(doc, total, len(urls)),
+		PagesVisited: 1,
+	}
+}
+
+// findNextPageURL returns the absolute URL of the list page's "次へ" link, or "" on the last page.
+// Links to another host are ignored so a crawl never leaves the site it started on.
+func findNextPageURL(doc *goquery.Document, pageURL string) string {
+	base, err := url.Parse(pageURL)
+	if err != nil {
+		return ""
+	}
+
+	var href string
+	if v, ok := doc.Find(`a[rel="next"], link[rel="next"]`).First().Attr("href"); ok {
+		href = v
+	} else {
+		doc.Find("a[href]").EachWithBreak(func(i int, sel *goquery.Selection) bool {
+			if strings.HasPrefix(strings.TrimSpace(sel.Text()), "次へ") {
+				href, _ = sel.Attr("href")
+				return false
+			}
+			return true
+		})
+	}
+
+	href = strings.TrimSpace(href)
+	if href == "" || strings.HasPrefix(href, "#") || strings.HasPrefix(href, "javascript:") {
+		return ""
+	}
+	next, err := base.Parse(href)
+	if err != nil || next.Host != base.Host {
+		return ""
+	}
+	next.Fragment = ""
+	return next.String()
+}
@@ -0,0 +1,101 @@
+package scraper
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"real-estate-portal/internal/ratelimit"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+)
+
+// Parse budget defaults. Yahoo detail pages are ~0.5MB / ~10k nodes; the guards only
+// catch pages that are clearly broken (error dumps, endless lists).
+const (
+	DefaultMaxConcurrentParses = 2
+	DefaultMaxDocumentBytes    = 8 << 20
+	DefaultMaxDocumentNodes    = 200000
+)
+
+var (
+	// ParseLimiter bounds how many goquery documents are parsed and held at once across
+	// all scraping paths (each document keeps the full DOM in memory until extraction ends)
+	ParseLimiter = ratelimit.NewConcurrencyLimiter(DefaultMaxConcurrentParses)
+
+	maxDocumentBytes int64 = DefaultMaxDocumentBytes
+	maxDocumentNodes       = DefaultMaxDocumentNodes
+)
+
+// DocumentTooLargeError is returned when a page exceeds the byte or node guard
+type DocumentTooLargeError struct {
+	Limit string // "bytes" or "nodes"
+	Size  int64  // observed size (at least Max+1; reading/counting stops there)
+	Max   int64
+}
+
+func (e *DocumentTooLargeError) Error() string {
+	return fmt.Sprintf("document too large: more than %d %s (limit %d)", e.Size-1, e.Limit, e.Max)
+}
+
+// ConfigureParseBudget replaces the parse concurrency and per-document guards.
+// Zero values keep the built-in defaults. Must be called at startup before scraping begins.
+func ConfigureParseBudget(maxConcurrent int, maxBytes int64, maxNodes int) {
+	if maxConcurrent > 0 {
+		ParseLimiter = ratelimit.NewConcurrencyLimiter(maxConcurrent)
+	}
+	if maxBytes > 0 {
+		maxDocumentBytes = maxBytes
+	}
+	if maxNodes > 0 {
+		maxDocumentNodes = maxNodes
+	}
+	log.Printf("Scraper: parse budget configured (max_concurrent=%d, max_bytes=%d, max_nodes=%d)",
+		ParseLimiter.Status().Max, maxDocumentBytes, maxDocumentNodes)
+}
+
+// parseDocument parses HTML from r, rejecting pages over the byte or node guard with
+// *DocumentTooLargeError. The caller must hold a ParseLimiter slot for as long as it uses the document.
+func parseDocument(r io.Reader) (*goquery.Document, error) {
+	data, err := io.ReadAll(io.LimitReader(r, maxDocumentBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > maxDocumentBytes {
+		return nil, &DocumentTooLargeError{Limit: "bytes", Size: int64(len(data)), Max: maxDocumentBytes}
+	}
+
+	root, err := html.Parse(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	if n := countNodes(root, maxDocumentNodes+1); n > maxDocumentNodes {
+		return nil, &DocumentTooLargeError{Limit: "nodes", Size: int64(n), Max: int64(maxDocumentNodes)}
+	}
+	return goquery.NewDocumentFromNode(root), nil
+}
+
+// countNodes counts nodes under root, stopping once stop is reached
+func countNodes(root *html.Node, stop int) int {
+	count := 0
+	stack := []*html.Node{root}
+	for len(stack) > 0 && count < stop {
+		n := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		count++
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			stack = append(stack, c)
+		}
+	}
+	return count
+}
+
+// acquireParse waits for a parse slot; pair with ParseLimiter.Release
+func acquireParse(ctx context.Context) error {
+	if err := ParseLimiter.Acquire(ctx); err != nil {
+		return fmt.Errorf("waiting for parse slot: %w", err)
+	}
+	return nil
+}
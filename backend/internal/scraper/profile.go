@@ -0,0 +1,185 @@
+package scraper
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync/atomic"
+)
+
+// defaultUserAgent is the built-in browser UA used when no source profile is configured
+const defaultUserAgent = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/122.0.0.0 Safari/537.36"
+
+// HeaderProfile holds per-source request fingerprint settings layered over the built-in browser headers
+type HeaderProfile struct {
+	UserAgents []string          // UA pool; one is picked per request
+	Headers    map[string]string // Extra or overriding headers
+}
+
+// debugLogging enables debug-level scraper logs (logging.level: debug)
+var debugLogging atomic.Bool
+
+// SetDebugLogging turns debug-level scraper logs (e.g. the User-Agent chosen per request) on or off
+func SetDebugLogging(enabled bool) {
+	debugLogging.Store(enabled)
+}
+
+// debugf logs only when debug logging is enabled
+func debugf(format string, args ...interface{}) {
+	if debugLogging.Load() {
+		log.Printf(format, args...)
+	}
+}
+
+// UserAgent picks the User-Agent for one request: the source profile pool, then the
+// configured pool (user_agents / user_agent), then the built-in default
+func (s *Scraper) UserAgent() string {
+	pool := s.userAgents
+	if s.profile != nil && len(s.profile.UserAgents) > 0 {
+		pool = s.profile.UserAgents
+	}
+	ua := defaultUserAgent
+	if len(pool) > 0 {
+		ua = pool[rand.Intn(len(pool))]
+	}
+	debugf("[UserAgent] Using %q (pool=%d)", ua, len(pool))
+	return ua
+}
+
+// requestKind selects the Accept and Sec-Fetch-* headers a browser would send for a request
+type requestKind int
+
+const (
+	requestDocument requestKind = iota // page navigation: list, detail, landing page, alive check
+	requestImage                       // image HEAD check (as an <img> load)
+	requestText                        // robots.txt
+)
+
+// chromeVersionPattern finds the major Chrome version in a User-Agent (Edge and Opera too)
+var chromeVersionPattern = regexp.MustCompile(`Chrome/([0-9]+)`)
+
+// applyHeaders sets the browser headers of a page navigation (see setRequestHeaders)
+func (s *Scraper) applyHeaders(req *http.Request, referer string) {
+	s.setRequestHeaders(req, requestDocument, referer)
+}
+
+// ApplyHeaders sets the same browser header profile on a request built outside the scraper
+// (the queue worker's WAF health check), so every request of a source looks alike
+func (s *Scraper) ApplyHeaders(req *http.Request, referer string) {
+	s.applyHeaders(req, referer)
+}
+
+// setRequestHeaders is the one place request headers are built: the headers Chrome sends for
+// kind, the chosen User-Agent with matching client hints, a Referer (with the Sec-Fetch-Site it
+// implies), and then the source profile headers (sources.<name>.headers), where an empty value
+// removes a header instead of setting it
+func (s *Scraper) setRequestHeaders(req *http.Request, kind requestKind, referer string) {
+	ua := s.UserAgent()
+	h := req.Header
+	h.Set("User-Agent", ua)
+	h.Set("Accept-Language", "ja-JP,ja;q=0.9,en-US;q=0.8,en;q=0.7")
+	h.Set("Accept-Encoding", acceptEncoding) // bodies are decoded by decodeBody
+	h.Set("Connection", "keep-alive")
+
+	switch kind {
+	case requestImage:
+		h.Set("Accept", "image/avif,image/webp,image/apng,image/svg+xml,image/*,*/*;q=0.8")
+		h.Set("Sec-Fetch-Mode", "no-cors")
+		h.Set("Sec-Fetch-Dest", "image")
+	case requestText:
+		h.Set("Accept", "text/plain,*/*;q=0.8")
+		h.Set("Sec-Fetch-Mode", "no-cors")
+		h.Set("Sec-Fetch-Dest", "empty")
+	default:
+		h.Set("Accept", "text/html,application/xhtml+xml,application/xml;q=0.9,image/avif,image/webp,image/apng,*/*;q=0.8,application/signed-exchange;v=b3;q=0.7")
+		h.Set("Upgrade-Insecure-Requests", "1")
+		h.Set("Sec-Fetch-Mode", "navigate")
+		h.Set("Sec-Fetch-User", "?1")
+		h.Set("Sec-Fetch-Dest", "document")
+	}
+
+	h.Set("Sec-Fetch-Site", "none")
+	if referer != "" {
+		h.Set("Referer", referer)
+		h.Set("Sec-Fetch-Site", fetchSite(req.URL, referer))
+	}
+
+	// Client hints only come from Chromium browsers, and must agree with the User-Agent
+	if m := chromeVersionPattern.FindStringSubmatch(ua); m != nil {
+		h.Set("sec-ch-ua", fmt.Sprintf(`"Chromium";v="%s", "Google Chrome";v="%s", "Not-A.Brand";v="99"`, m[1], m[1]))
+		mobile, platform := "?0", "Windows"
+		switch {
+		case strings.Contains(ua, "Android"):
+			mobile, platform = "?1", "Android"
+		case strings.Contains(ua, "Mac OS X"):
+			platform = "macOS"
+		case strings.Contains(ua, "Linux"):
+			platform = "Linux"
+		}
+		h.Set("sec-ch-ua-mobile", mobile)
+		h.Set("sec-ch-ua-platform", `"`+platform+`"`)
+	}
+
+	if s.profile == nil {
+		return
+	}
+	for k, v := range s.profile.Headers {
+		if v == "" {
+			h.Del(k)
+			continue
+		}
+		h.Set(k, v)
+	}
+}
+
+// fetchSite is the Sec-Fetch-Site of a request to target from a page at referer
+func fetchSite(target *url.URL, referer string) string {
+	ref, err := url.Parse(referer)
+	if err != nil || target == nil {
+		return "cross-site"
+	}
+	if ref.Scheme == target.Scheme && strings.EqualFold(ref.Host, target.Host) {
+		return "same-origin"
+	}
+	if siteOf(ref.Hostname()) == siteOf(target.Hostname()) {
+		return "same-site"
+	}
+	return "cross-site"
+}
+
+// siteOf approximates a host's registrable domain: the last two labels, or three under a
+// two-letter country code's second level (realestate.yahoo.co.jp → yahoo.co.jp)
+func siteOf(host string) string {
+	labels := strings.Split(strings.ToLower(host), ".")
+	n := 2
+	if len(labels) >= 3 && len(labels[len(labels)-1]) == 2 && len(labels[len(labels)-2]) <= 3 {
+		n = 3
+	}
+	if len(labels) <= n {
+		return strings.Join(labels, ".")
+	}
+	return strings.Join(labels[len(labels)-n:], ".")
+}
+
+// refererContextKey carries the page a detail URL was found on (see WithReferer)
+type refererContextKey struct{}
+
+// WithReferer returns ctx carrying the list page detailURL was discovered on; ScrapeDetail
+// sends it as the Referer, as a visitor clicking through from that list would
+func WithReferer(ctx context.Context, referer string) context.Context {
+	if referer == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, refererContextKey{}, referer)
+}
+
+// refererFrom is the Referer set by WithReferer ("" = none)
+func refererFrom(ctx context.Context) string {
+	referer, _ := ctx.Value(refererContextKey{}).(string)
+	return referer
+}
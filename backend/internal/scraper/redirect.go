@@ -0,0 +1,44 @@
+package scraper
+
+import (
+	"log"
+	"real-estate-portal/internal/models"
+)
+
+// PropertyRedirect is a detail page that redirected to another listing: Yahoo 301s the old URL of
+// a re-published listing to its new one. The caller moves the stored row (and its history) from
+// FromID to ToID instead of inserting a duplicate.
+type PropertyRedirect struct {
+	FromID  string // source_property_id of the requested URL
+	ToID    string // source_property_id the page was served under
+	FromURL string
+	ToURL   string
+}
+
+// GetLastRedirect returns the redirect seen by the last detail scrape, or nil (ScrapeDetail
+// returns each call's own in its result)
+func (s *Scraper) GetLastRedirect() *PropertyRedirect {
+	s.lastMu.Lock()
+	defer s.lastMu.Unlock()
+	return s.lastRedirect
+}
+
+// detectRedirect compares the requested URL with the one the page was served from. A redirect
+// within the same listing (trailing slash, tracking query) is not reported.
+func detectRedirect(requestedURL, pageURL string, property *models.Property) *PropertyRedirect {
+	if pageURL == requestedURL {
+		return nil
+	}
+	fromID := yahooPropertyIDOrHash(requestedURL)
+	if fromID == property.SourcePropertyID {
+		return nil
+	}
+	log.Printf("[ScrapeProperty] Warning: %s redirected to %s (property ID %s -> %s)",
+		requestedURL, property.DetailURL, fromID, property.SourcePropertyID)
+	return &PropertyRedirect{
+		FromID:  fromID,
+		ToID:    property.SourcePropertyID,
+		FromURL: requestedURL,
+		ToURL:   property.DetailURL,
+	}
+}
@@ -0,0 +1,62 @@
+package scraper
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultMaxRetryAfter is the longest Retry-After a request waits out itself (ScraperConfig.MaxRetryAfter)
+const defaultMaxRetryAfter = 30 * time.Minute
+
+// parseRetryAfter reads a Retry-After header: delay-seconds or an HTTP-date. ok is false when
+// the header is missing or unparsable; a date in the past is a zero wait.
+func parseRetryAfter(value string, now time.Time) (wait time.Duration, ok bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	at, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+	if wait = at.Sub(now); wait < 0 {
+		wait = 0
+	}
+	return wait, true
+}
+
+// RateLimitedError is an ErrRateLimited that carries the site's Retry-After: the request gave
+// up instead of waiting that long, and the caller should not come back sooner
+type RateLimitedError struct {
+	RetryAfter time.Duration
+	msg        string
+}
+
+func (e *RateLimitedError) Error() string {
+	return fmt.Sprintf("%s: %s (Retry-After %v)", ErrRateLimited.Error(), e.msg, e.RetryAfter)
+}
+
+// Is makes errors.Is(err, ErrRateLimited) match
+func (e *RateLimitedError) Is(target error) bool { return target == ErrRateLimited }
+
+// ErrorCode reports the errtext code (see errtext.FromError)
+func (e *RateLimitedError) ErrorCode() string { return ErrRateLimited.(*codedError).code }
+
+// RetryAfterOf returns the Retry-After carried by err (0 when it has none)
+func RetryAfterOf(err error) time.Duration {
+	var limited *RateLimitedError
+	if errors.As(err, &limited) {
+		return limited.RetryAfter
+	}
+	return 0
+}
@@ -0,0 +1,233 @@
+package scraper
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	robotsTTL      = 24 * time.Hour   // parsed robots.txt is reused this long per host
+	robotsErrorTTL = 10 * time.Minute // an unreachable robots.txt is retried sooner
+	robotsMaxBytes = 500 << 10        // RFC 9309: parse at least the first 500 KiB
+)
+
+// RobotsRule is one Allow/Disallow line of the "User-agent: *" group
+type RobotsRule struct {
+	Allow   bool           `json:"allow"`
+	Path    string         `json:"path"`
+	pattern *regexp.Regexp // Path with '*' and a trailing '$' expanded
+}
+
+// RobotsRules is the cached robots.txt state for one origin (scheme://host)
+type RobotsRules struct {
+	Origin    string       `json:"origin"`
+	Status    int          `json:"status"` // HTTP status of the robots.txt fetch (0 = request failed)
+	Rules     []RobotsRule `json:"rules"`
+	Error     string       `json:"error,omitempty"`
+	FetchedAt time.Time    `json:"fetched_at"`
+	ExpiresAt time.Time    `json:"expires_at"`
+}
+
+// unavailable reports whether robots.txt could not be read (network error or 5xx).
+// RFC 9309 treats that as "disallow everything" until it can be fetched again.
+func (r *RobotsRules) unavailable() bool {
+	return r.Status == 0 || r.Status >= 500
+}
+
+// match returns the rule deciding requestPath (longest match, Allow wins ties), nil if none matches
+func (r *RobotsRules) match(requestPath string) *RobotsRule {
+	var best *RobotsRule
+	for i := range r.Rules {
+		rule := &r.Rules[i]
+		if !rule.pattern.MatchString(requestPath) {
+			continue
+		}
+		if best == nil || len(rule.Path) > len(best.Path) || (len(rule.Path) == len(best.Path) && rule.Allow) {
+			best = rule
+		}
+	}
+	return best
+}
+
+var robotsCache = struct {
+	sync.Mutex
+	origins map[string]*RobotsRules
+}{origins: make(map[string]*RobotsRules)}
+
+// RobotsCacheSnapshot returns copies of the cached robots.txt rules, sorted by origin (admin API)
+func RobotsCacheSnapshot() []RobotsRules {
+	robotsCache.Lock()
+	defer robotsCache.Unlock()
+
+	snapshot := make([]RobotsRules, 0, len(robotsCache.origins))
+	for _, rules := range robotsCache.origins {
+		entry := *rules
+		entry.Rules = slices.Clone(rules.Rules)
+		snapshot = append(snapshot, entry)
+	}
+	slices.SortFunc(snapshot, func(a, b RobotsRules) int { return strings.Compare(a.Origin, b.Origin) })
+	return snapshot
+}
+
+// checkRobots returns ErrRobotsDisallowed (wrapped) when robots.txt forbids rawURL.
+// A robots.txt that can't be read yields a retryable error instead, so queue items
+// aren't marked permanent because of a transient outage.
+func (s *Scraper) checkRobots(ctx context.Context, rawURL string) error {
+	if !s.respectRobots {
+		return nil
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return nil // the request itself reports the bad URL
+	}
+	if u.Path == "/robots.txt" {
+		return nil
+	}
+
+	rules, err := s.robotsRules(ctx, u.Scheme+"://"+u.Host)
+	if err != nil {
+		return err
+	}
+	if rules.unavailable() {
+		return fmt.Errorf("robots.txt unavailable for %s (status %d): %s", rules.Origin, rules.Status, rules.Error)
+	}
+
+	requestPath := u.EscapedPath()
+	if requestPath == "" {
+		requestPath = "/"
+	}
+	if u.RawQuery != "" {
+		requestPath += "?" + u.RawQuery
+	}
+	if rule := rules.match(requestPath); rule != nil && !rule.Allow {
+		return fmt.Errorf("%w: %s (Disallow: %s)", ErrRobotsDisallowed, requestPath, rule.Path)
+	}
+	return nil
+}
+
+// robotsRules returns the cached rules for origin, fetching robots.txt when missing or expired.
+// The cache lock is held during the fetch so concurrent scrapes don't request it twice.
+func (s *Scraper) robotsRules(ctx context.Context, origin string) (*RobotsRules, error) {
+	robotsCache.Lock()
+	defer robotsCache.Unlock()
+
+	if rules, ok := robotsCache.origins[origin]; ok && time.Now().Before(rules.ExpiresAt) {
+		return rules, nil
+	}
+
+	rules, err := s.fetchRobots(ctx, origin)
+	if err != nil {
+		return nil, err
+	}
+	robotsCache.origins[origin] = rules
+	return rules, nil
+}
+
+// fetchRobots downloads and parses origin/robots.txt. Per RFC 9309 a 4xx means no
+// restrictions; network errors and 5xx are cached for robotsErrorTTL as unavailable.
+func (s *Scraper) fetchRobots(ctx context.Context, origin string) (*RobotsRules, error) {
+	robotsURL := origin + "/robots.txt"
+	now := time.Now()
+	rules := &RobotsRules{Origin: origin, FetchedAt: now, ExpiresAt: now.Add(robotsTTL)}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", robotsURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create robots.txt request: %w", err)
+	}
+	s.setRequestHeaders(req, requestText, "")
+
+	resp, err := s.do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("robots.txt fetch canceled: %w", ctx.Err())
+		}
+		log.Printf("[Robots] Failed to fetch %s: %v", robotsURL, err)
+		rules.Error = err.Error()
+		rules.ExpiresAt = now.Add(robotsErrorTTL)
+		return rules, nil
+	}
+	defer resp.Body.Close()
+
+	rules.Status = resp.StatusCode
+	switch {
+	case resp.StatusCode >= 500:
+		rules.Error = resp.Status
+		rules.ExpiresAt = now.Add(robotsErrorTTL)
+	case resp.StatusCode >= 400:
+		// No robots.txt: everything is allowed
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		body, err := decodeBody(resp)
+		if err != nil {
+			rules.Error = err.Error()
+			break
+		}
+		defer body.Close()
+		rules.Rules = parseRobots(io.LimitReader(body, robotsMaxBytes))
+	}
+	log.Printf("[Robots] Fetched %s: status %d, %d rules", robotsURL, rules.Status, len(rules.Rules))
+	return rules, nil
+}
+
+// parseRobots returns the Allow/Disallow rules of the "User-agent: *" groups.
+// Groups naming specific crawlers are skipped: the scraper sends browser User-Agents.
+func parseRobots(r io.Reader) []RobotsRule {
+	var rules []RobotsRule
+	inWildcard, lastWasAgent := false, false
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		key, value = strings.ToLower(strings.TrimSpace(key)), strings.TrimSpace(value)
+
+		switch key {
+		case "user-agent":
+			if !lastWasAgent { // a new group starts
+				inWildcard = false
+			}
+			if value == "*" {
+				inWildcard = true
+			}
+			lastWasAgent = true
+		case "allow", "disallow":
+			lastWasAgent = false
+			if !inWildcard || value == "" { // an empty Disallow allows everything
+				continue
+			}
+			rules = append(rules, RobotsRule{Allow: key == "allow", Path: value, pattern: robotsPattern(value)})
+		default:
+			lastWasAgent = false
+		}
+	}
+	return rules
+}
+
+// robotsPattern compiles a robots.txt path: '*' matches any run of characters,
+// a trailing '$' anchors the end, anything else is a prefix match
+func robotsPattern(path string) *regexp.Regexp {
+	anchored := strings.HasSuffix(path, "$")
+	path = strings.TrimSuffix(path, "$")
+
+	parts := strings.Split(path, "*")
+	for i, part := range parts {
+		parts[i] = regexp.QuoteMeta(part)
+	}
+	expr := "^" + strings.Join(parts, ".*")
+	if anchored {
+		expr += "$"
+	}
+	return regexp.MustCompile(expr)
+}
@@ -1,11 +1,11 @@
 package scraper
 
 import (
-	"compress/gzip"
 	"context"
 	"crypto/md5"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"html"
 	"io"
@@ -13,52 +13,59 @@ import (
 	"math"
 	"math/rand"
 	"net/http"
-	"net/http/cookiejar"
 	"net/url"
 	"real-estate-portal/internal/models"
 	"real-estate-portal/internal/ratelimit"
+	"real-estate-portal/internal/tracing"
 	"regexp"
-	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/PuerkitoBio/goquery"
+	"github.com/andybalholm/cascadia"
+	"github.com/chromedp/cdproto/network"
 	"github.com/chromedp/chromedp"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 var (
-	// Global rate limiter for Yahoo Real Estate list pages
-	// Used for list page scraping (search results)
-	yahooLimiter = ratelimit.NewYahooLimiter(
-		1,                     // maxInFlight: 1 concurrent request (avoid burst)
-		8000*time.Millisecond, // baseDelay: 8s base for list pages
-		4000*time.Millisecond, // jitter: 0-4s (total: 8-12s)
-	)
-
 	// DetailLimiter is exported for use in API handlers (single detail page scraping)
 	// Strictly limits detail pages to 8 per hour to avoid WAF detection
 	// NOTE: This should ONLY be used for single /api/scrape requests, NOT for batch/list operations
 	DetailLimiter = ratelimit.NewDetailLimiter(10) // 10 detail pages per hour max
-
-	// Global circuit breaker to detect WAF blocks
-	// Stricter early detection to avoid prolonged blocks
-	circuitBreaker = NewCircuitBreaker(
-		8,           // failureThreshold: 8 failures out of 20 requests (stricter)
-		1*time.Hour, // resetTimeout: wait 1 hour before retry
-	)
 )
 
 type Scraper struct {
 	client                *http.Client
 	maxRetries            int
 	retryDelay            time.Duration
+	attemptTimeout        time.Duration // Bound on one attempt incl. its body (0 = client timeout only)
+	maxTotal              time.Duration // Bound on a whole retry sequence (0 = unbounded)
+	maxRetryAfter         time.Duration // Longest 429 Retry-After waited out before giving up with ErrRateLimited
 	requestDelay          time.Duration
-	lastRequestTime       time.Time
-	lastHomepageVisit     time.Time
-	homepageVisitInterval time.Duration
+	paceMu                sync.Mutex // Guards lastRequestTime (pace runs from concurrent handlers)
+	lastRequestTime       time.Time  // Slot of the latest page request, possibly still in the future
+	sessionWarmup         bool       // Visit the landing page before detail scrapes (see warmUpSession)
+	lastMu                sync.Mutex        // Guards lastStations/lastImages/lastRedirect
 	lastStations          []StationAccess // Stores stations from the last scrape
 	lastImages            []string        // Stores image URLs from the last scrape
+	lastRedirect          *PropertyRedirect // Set when the last detail page redirected to another property ID
+	profile               *HeaderProfile  // Per-source header profile (nil = built-in headers)
+	userAgents            []string        // Configured UA pool used when the profile has none
+	baseURL               string          // Site origin used to build homepage/detail URLs
+	fixtureMode           bool            // Plain HTTP fetch, no human pacing (offline fixtures)
+	respectRobots         bool            // Check robots.txt before each list/detail request
+	verifyImages          bool            // HEAD-check og:image / layout images before keeping them
+	selectors             map[string]cascadia.Selector // Configured field selectors tried before the built-in extraction
+	debugHTML             *debugHTMLCapture // Saves pages that yield no title/rent (nil = off)
+	limiter               *ratelimit.YahooLimiter // List/request pacing (shared per host unless injected)
+	circuitBreaker        *CircuitBreaker         // WAF circuit breaker (shared per host unless injected)
+	limits                *sourceLimits     // Another site's detail/alive budgets (nil = the Yahoo ones)
+	proxies               *proxyPool        // Outbound proxy rotation (nil = direct)
+	statsMu               sync.Mutex        // Guards stats
+	stats                 *statsRecorder    // Requests and waits of the last scrape call started (see LastStats)
 }
 
 type ScraperConfig struct {
@@ -66,29 +73,110 @@ type ScraperConfig struct {
 	MaxRetries   int
 	RetryDelay   time.Duration
 	RequestDelay time.Duration
+	Profile      *HeaderProfile // Optional per-source header profile
+
+	// AttemptTimeout bounds each try of a page request, reading the body included (0 = Timeout).
+	// MaxTotal bounds the whole retry sequence (limiter wait, attempts, backoff); once the next
+	// backoff would overrun it the request fails with ErrTimeout instead of sleeping (0 = unbounded).
+	AttemptTimeout time.Duration
+	MaxTotal       time.Duration
+
+	// MaxRetryAfter is the longest Retry-After of a 429 a request sleeps through before its next
+	// attempt; a longer one fails the request with a *RateLimitedError (ErrRateLimited) carrying
+	// it, so the caller reschedules instead of blocking (0 = 30 minutes)
+	MaxRetryAfter time.Duration
+	UserAgents   []string       // UA pool (config user_agents / user_agent); the profile's pool wins
+
+	// RespectRobots refuses URLs disallowed by the host's robots.txt (ErrRobotsDisallowed).
+	// NewScraper and the API config default it to true.
+	RespectRobots bool
+
+	// VerifyImages HEAD-checks the og:image fallback and the 間取り図 <img> before keeping them
+	// (results cached per URL). Off: they are kept unchecked, saving a request per property.
+	VerifyImages bool
+
+	// Selectors maps detail fields (SelectorTitle, SelectorRent, ...) to CSS selectors tried
+	// before the built-in extraction; one that matches nothing falls back to it
+	Selectors map[string]string
+
+	// DebugHTMLDir saves the raw HTML of detail pages that fail to parse or yield no title/rent
+	// ("" = off). Files are capped at DebugHTMLMaxBytes and pruned after DebugHTMLRetention or
+	// beyond DebugHTMLMaxFiles (zero values: 2MiB, 7 days, 200 files).
+	DebugHTMLDir       string
+	DebugHTMLMaxBytes  int
+	DebugHTMLRetention time.Duration
+	DebugHTMLMaxFiles  int
+
+	// SessionWarmup visits the rent landing page (/rent/) before a detail scrape when the host's
+	// session was not warmed up in the last 30 minutes, so detail pages are requested with the
+	// cookies a visitor would have. NewScraper and the API config default it to true.
+	SessionWarmup bool
+
+	// CookieFile persists the cookie jar across restarts: restored on first use and rewritten
+	// when a response changes a cookie. Scrapers with the same file share one jar ("" = a
+	// process-wide jar kept in memory only).
+	CookieFile string
+
+	// BaseURL overrides the Yahoo origin (default: https://realestate.yahoo.co.jp).
+	// FixtureMode fetches detail pages with the plain HTTP client instead of headless Chrome
+	// and skips human-pace sleeps. Both are for the offline fixture harness.
+	BaseURL     string
+	FixtureMode bool
+
+	// Proxies routes every request (pages, robots.txt, image checks) through these proxy URLs,
+	// starting at the next one per request and moving on when a proxy fails (empty = direct)
+	Proxies []string
 }
 
+// defaultBaseURL is the Yahoo Real Estate origin
+const defaultBaseURL = "https://realestate.yahoo.co.jp"
+
 func NewScraper() *Scraper {
-	return NewScraperWithConfig(ScraperConfig{
-		Timeout:      30 * time.Second,  // 30s for normal page fetches
-		MaxRetries:   3,                  // Retry up to 3 times
-		RetryDelay:   2 * time.Second,   // Base delay for exponential backoff
-		RequestDelay: 2 * time.Second,   // Minimum 2s between requests (rate limiting)
-	})
+	return NewScraperWithConfig(DefaultScraperConfig())
 }
 
+// DefaultScraperConfig is the built-in configuration NewScraper uses
+func DefaultScraperConfig() ScraperConfig {
+	return ScraperConfig{
+		Timeout:       30 * time.Second, // 30s for normal page fetches
+		MaxRetries:    3,                // Retry up to 3 times
+		RetryDelay:    2 * time.Second,  // Base delay for exponential backoff
+		MaxTotal:      2 * time.Minute,  // Give up on a page after 2 minutes of retrying
+		RequestDelay:  2 * time.Second,  // Minimum 2s between requests (rate limiting)
+		RespectRobots: true,             // Refuse URLs disallowed by robots.txt
+		SessionWarmup: true,             // Collect cookies on the landing page before detail pages
+	}
+}
+
+// NewScraperWithConfig creates a Yahoo scraper that paces and trips the shared limiter and
+// circuit breaker of its origin's host (see SharedLimits)
 func NewScraperWithConfig(config ScraperConfig) *Scraper {
-	// Create cookie jar for session management
-	jar, err := cookiejar.New(nil)
-	if err != nil {
-		log.Printf("Warning: Failed to create cookie jar: %v", err)
-		jar = nil
+	limiter, breaker := SharedLimits("yahoo", baseHost(scraperBaseURL(config)))
+	return NewScraperWithLimiter(config, limiter, breaker)
+}
+
+// NewScraperWithLimiter creates a scraper that waits on limiter before each page request and
+// records failures in breaker, instead of its host's shared ones. Tests use it for isolated
+// instances; callers tuning a source pass their own.
+func NewScraperWithLimiter(config ScraperConfig, limiter *ratelimit.YahooLimiter, breaker *CircuitBreaker) *Scraper {
+	baseURL := scraperBaseURL(config)
+
+	proxies := newProxyPool(config.Proxies)
+
+	attemptTimeout := config.AttemptTimeout
+	if attemptTimeout <= 0 {
+		attemptTimeout = config.Timeout
+	}
+	maxRetryAfter := config.MaxRetryAfter
+	if maxRetryAfter <= 0 {
+		maxRetryAfter = defaultMaxRetryAfter
 	}
 
 	return &Scraper{
 		client: &http.Client{
-			Timeout: config.Timeout,
-			Jar:     jar,
+			Transport: newTransport(proxies),
+			Timeout:   config.Timeout,
+			Jar:       sharedSessionJar(config.CookieFile), // Session cookies shared across scrapers
 			CheckRedirect: func(req *http.Request, via []*http.Request) error {
 				// Follow redirects while maintaining cookies
 				return nil
@@ -96,107 +184,60 @@ func NewScraperWithConfig(config ScraperConfig) *Scraper {
 		},
 		maxRetries:            config.MaxRetries,
 		retryDelay:            config.RetryDelay,
+		attemptTimeout:        attemptTimeout,
+		maxTotal:              config.MaxTotal,
+		maxRetryAfter:         maxRetryAfter,
 		requestDelay:          config.RequestDelay,
-		homepageVisitInterval: 30 * time.Minute, // Visit homepage every 30 minutes to maintain session
+		sessionWarmup:         config.SessionWarmup,
+		profile:               config.Profile,
+		userAgents:            config.UserAgents,
+		baseURL:               baseURL,
+		fixtureMode:           config.FixtureMode,
+		respectRobots:         config.RespectRobots,
+		verifyImages:          config.VerifyImages,
+		selectors:             compileSelectors(config.Selectors),
+		debugHTML:             newDebugHTMLCapture(config.DebugHTMLDir, config.DebugHTMLMaxBytes, config.DebugHTMLRetention, config.DebugHTMLMaxFiles),
+		proxies:               proxies,
+		limiter:               limiter,
+		circuitBreaker:        breaker,
 	}
 }
 
-// rateLimit enforces minimum delay between requests
-func (s *Scraper) rateLimit() {
-	if s.requestDelay == 0 {
-		return
-	}
-
-	elapsed := time.Since(s.lastRequestTime)
-	if elapsed < s.requestDelay {
-		time.Sleep(s.requestDelay - elapsed)
+// scraperBaseURL is config's origin without a trailing slash (default: Yahoo)
+func scraperBaseURL(config ScraperConfig) string {
+	if baseURL := strings.TrimSuffix(config.BaseURL, "/"); baseURL != "" {
+		return baseURL
 	}
-	s.lastRequestTime = time.Now()
+	return defaultBaseURL
 }
 
-// visitHomepageIfNeeded visits the Yahoo Real Estate homepage to establish a session
-// This helps avoid bot detection by simulating a real user browsing flow
-func (s *Scraper) visitHomepageIfNeeded() error {
-	// Check if we need to visit homepage
-	if time.Since(s.lastHomepageVisit) < s.homepageVisitInterval {
-		return nil // Recent visit, no need to visit again
+// pace enforces RequestDelay between this instance's page requests, on top of the source's
+// shared list limiter. Each caller reserves the next free slot under the mutex and sleeps
+// outside it, so concurrent calls line up one delay apart instead of waking together.
+func (s *Scraper) pace(ctx context.Context) error {
+	if s.requestDelay <= 0 {
+		return nil
 	}
 
-	log.Printf("[Homepage] Visiting Yahoo Real Estate homepage to establish session")
-
-	req, err := http.NewRequest("GET", "https://realestate.yahoo.co.jp/", nil)
-	if err != nil {
-		return err
+	s.paceMu.Lock()
+	slot := s.lastRequestTime.Add(s.requestDelay)
+	if now := time.Now(); slot.Before(now) {
+		slot = now
 	}
+	s.lastRequestTime = slot
+	s.paceMu.Unlock()
 
-	applyBrowserHeaders(req, "")
-
-	resp, err := s.client.Do(req)
-	if err != nil {
-		log.Printf("[Homepage] Error visiting homepage: %v", err)
-		return err
+	wait := time.Until(slot)
+	if wait <= 0 {
+		return nil
 	}
-	defer resp.Body.Close()
-
-	s.lastHomepageVisit = time.Now()
-	log.Printf("[Homepage] Successfully visited homepage (Status: %d), cookies saved", resp.StatusCode)
-
-	// Small delay after homepage visit to appear more natural
-	time.Sleep(time.Duration(2+rand.Intn(3)) * time.Second)
-
-	return nil
-}
-
-// applyBrowserHeaders sets browser-like headers to avoid bot detection
-func applyBrowserHeaders(req *http.Request, referer string) {
-	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/122.0.0.0 Safari/537.36")
-	req.Header.Set("Accept", "text/html,application/xhtml+xml,application/xml;q=0.9,image/avif,image/webp,image/apng,*/*;q=0.8,application/signed-exchange;v=b3;q=0.7")
-	req.Header.Set("Accept-Language", "ja-JP,ja;q=0.9,en-US;q=0.8,en;q=0.7")
-	req.Header.Set("Accept-Encoding", "gzip, deflate, br, zstd")
-	req.Header.Set("DNT", "1")
-	req.Header.Set("Connection", "keep-alive")
-	req.Header.Set("Upgrade-Insecure-Requests", "1")
-	req.Header.Set("Sec-Fetch-Site", "none")
-	req.Header.Set("Sec-Fetch-Mode", "navigate")
-	req.Header.Set("Sec-Fetch-User", "?1")
-	req.Header.Set("Sec-Fetch-Dest", "document")
-	req.Header.Set("sec-ch-ua", `"Not A(Brand";v="99", "Google Chrome";v="122", "Chromium";v="122"`)
-	req.Header.Set("sec-ch-ua-mobile", "?0")
-	req.Header.Set("sec-ch-ua-platform", `"Windows"`)
-
-	if referer != "" {
-		req.Header.Set("Referer", referer)
-		req.Header.Set("Sec-Fetch-Site", "same-origin")
-	}
-}
-
-// isWAFBlock checks if a response indicates a WAF block
-func isWAFBlock(resp *http.Response) bool {
-	if resp.StatusCode != 500 {
-		return false
-	}
-
-	// Read body to check for WAF indicators
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return false
-	}
-
-	// Replace body so it can be read again if needed
-	resp.Body = io.NopCloser(strings.NewReader(string(body)))
-
-	bodyStr := string(body)
-	// Check for Yahoo WAF block message
-	if strings.Contains(bodyStr, "ご覧になろうとしているページは現在表示できません") {
-		log.Printf("[WAF] Detected Yahoo WAF block page")
-		return true
-	}
-
-	return false
+	s.recordStats(ctx, func(st *ScrapeStats) { st.PacingWait += wait })
+	return ratelimit.SleepContext(ctx, wait)
 }
 
 // sleepHumanDetailPace simulates human browsing behavior with natural delays
-func sleepHumanDetailPace() {
+// (returns ctx.Err() if ctx ends during the pause)
+func sleepHumanDetailPace(ctx context.Context) error {
 	// 80% normal browsing (45-120 seconds)
 	// 20% deep reading (180-420 seconds = 3-7 minutes)
 	p := rand.Float64()
@@ -211,7 +252,7 @@ func sleepHumanDetailPace() {
 	}
 
 	log.Printf("[Human Pace] Sleeping for %v to simulate human browsing", duration)
-	time.Sleep(duration)
+	return ratelimit.SleepContext(ctx, duration)
 }
 
 // sleepHumanListPace simulates human browsing behavior for list pages
@@ -222,58 +263,143 @@ func sleepHumanListPace() {
 	time.Sleep(duration)
 }
 
-// doRequestWithRetry performs HTTP request with exponential backoff retry
-func (s *Scraper) doRequestWithRetry(req *http.Request) (*http.Response, error) {
+// doRequestWithRetry performs HTTP request with exponential backoff retry.
+// The limiter wait, backoff sleeps and the request itself all stop once req's context ends.
+// Each attempt is bounded by attemptTimeout and the whole sequence by maxTotal: when the next
+// backoff would overrun it, ErrTimeout is returned instead of sleeping.
+func (s *Scraper) doRequestWithRetry(req *http.Request) (_ *http.Response, retErr error) {
 	var resp *http.Response
 	var err error
 
-	// Check circuit breaker before proceeding
-	if !circuitBreaker.CanProceed() {
-		isOpen, failures, total := circuitBreaker.GetStatus()
-		return nil, fmt.Errorf("circuit breaker open: suspected WAF block (%d/%d failures, open=%v)", failures, total, isOpen)
+	ctx, span := tracing.Start(req.Context(), "scraper.doRequestWithRetry", attribute.String("http.url", req.URL.String()))
+	req = req.WithContext(ctx)
+	retries, statusCode := 0, 0
+	defer func() {
+		span.SetAttributes(attribute.Int("http.status_code", statusCode), attribute.Int("retry.count", retries))
+		tracing.RecordError(span, retErr)
+		span.End()
+	}()
+
+	// Check circuit breaker before proceeding (each source trips its own)
+	breaker, listLimiter := s.circuitBreaker, s.limiter
+	if !breaker.CanProceed() {
+		isOpen, failures, total := breaker.GetStatus()
+		return nil, fmt.Errorf("%w: suspected WAF block (%d/%d failures, open=%v)", ErrCircuitOpen, failures, total, isOpen)
+	}
+
+	// Acquire the source's rate limiter before starting (the wait counts against the budget)
+	budget := s.newRetryBudget()
+	waitStart := time.Now()
+	acquireCtx, cancelAcquire := budget.context(ctx)
+	err = listLimiter.AcquireContext(acquireCtx)
+	cancelAcquire()
+	if err != nil {
+		if ctx.Err() == nil {
+			return nil, budget.exhausted("waiting for the rate limiter")
+		}
+		return nil, fmt.Errorf("waiting for rate limiter: %w", err)
 	}
+	defer listLimiter.Release()
+	s.recordStats(ctx, func(st *ScrapeStats) { st.LimiterWait += time.Since(waitStart) })
 
-	// Acquire global rate limiter before starting
-	yahooLimiter.Acquire()
-	defer yahooLimiter.Release()
-
+	var retryAfter time.Duration // Retry-After of the last 429 (0 = none)
 	for attempt := 0; attempt <= s.maxRetries; attempt++ {
 		if attempt > 0 {
-			// Exponential backoff: delay * 2^(attempt-1), max 60s
+			// Exponential backoff: delay * 2^(attempt-1), max 60s; a 429's Retry-After if longer
 			backoff := time.Duration(math.Pow(2, float64(attempt-1))) * s.retryDelay
 			if backoff > 60*time.Second {
 				backoff = 60 * time.Second
 			}
-			log.Printf("Retry attempt %d/%d after %v (inFlight: %d)", attempt, s.maxRetries, backoff, yahooLimiter.GetInFlight())
-			time.Sleep(backoff)
+			if retryAfter > backoff {
+				backoff = retryAfter
+				if !budget.allows(backoff) {
+					return nil, &RateLimitedError{RetryAfter: retryAfter, msg: fmt.Sprintf("status code 429 after %d attempt(s); Retry-After outlasts the retry budget of %v", attempt, budget.total)}
+				}
+			}
+			if !budget.allows(backoff) {
+				return nil, budget.exhausted(fmt.Sprintf("after %d attempt(s) (last: %s); not sleeping %v for a retry", attempt, describeFailure(resp, err), backoff))
+			}
+			log.Printf("Retry attempt %d/%d after %v (inFlight: %d)", attempt, s.maxRetries, backoff, listLimiter.GetInFlight())
+			s.recordStats(ctx, func(st *ScrapeStats) {
+				st.Retries++
+				st.PacingWait += backoff
+			})
+			if err := ratelimit.SleepContext(ctx, backoff); err != nil {
+				return nil, fmt.Errorf("retry canceled: %w", err)
+			}
+			retryAfter = 0
 		}
 
-		resp, err = s.client.Do(req)
+		// The per-instance request delay also counts against the budget
+		paceCtx, cancelPace := budget.context(ctx)
+		err = s.pace(paceCtx)
+		cancelPace()
+		if err != nil {
+			if ctx.Err() == nil {
+				return nil, budget.exhausted("waiting for the request delay")
+			}
+			return nil, fmt.Errorf("request canceled: %w", err)
+		}
 
-		if err == nil && resp.StatusCode == 200 {
-			circuitBreaker.RecordSuccess()
+		resp, err = s.attempt(req, budget)
+		retries = attempt
+		if resp != nil {
+			statusCode = resp.StatusCode
+		}
+
+		// Cancellation is not a site failure: stop without touching the circuit breaker
+		if ctx.Err() != nil {
+			if resp != nil && resp.Body != nil {
+				resp.Body.Close()
+			}
+			return nil, fmt.Errorf("request canceled: %w", ctx.Err())
+		}
+
+		// The block page comes with 500 and sometimes with 200: immediate failure, no retry
+		if err == nil && isWAFBlock(resp) {
+			log.Printf("Request blocked (attempt %d): WAF block page with status %d", attempt+1, resp.StatusCode)
+			breaker.RecordBlock(resp.StatusCode)
+			if resp.Body != nil {
+				resp.Body.Close()
+			}
+			return nil, fmt.Errorf("%w: immediate retreat required (status %d)", ErrWAFBlocked, resp.StatusCode)
+		}
+
+		// 304 only comes back for conditional requests (ScrapePropertyIfModified)
+		if err == nil && (resp.StatusCode == 200 || resp.StatusCode == http.StatusNotModified) {
+			breaker.RecordSuccess()
 			return resp, nil
 		}
 
 		// Log error with status code breakdown
 		if err != nil {
 			log.Printf("Request failed (attempt %d): %v", attempt+1, err)
-			circuitBreaker.RecordFailure(0)
-		} else {
-			log.Printf("Request failed (attempt %d): status %d (inFlight: %d)", attempt+1, resp.StatusCode, yahooLimiter.GetInFlight())
-
-			// Check for WAF block - immediate failure, no retry
-			if isWAFBlock(resp) {
-				circuitBreaker.RecordFailure(resp.StatusCode)
-				if resp.Body != nil {
-					resp.Body.Close()
-				}
-				return nil, fmt.Errorf("WAF block detected: immediate retreat required")
+			// A dead proxy says nothing about the site's health
+			if !errors.Is(err, ErrProxy) {
+				breaker.RecordFailure(0)
 			}
+		} else {
+			log.Printf("Request failed (attempt %d): status %d (inFlight: %d)", attempt+1, resp.StatusCode, listLimiter.GetInFlight())
 
 			// Record failure for circuit breaker
 			if resp.StatusCode >= 500 || resp.StatusCode == 429 || resp.StatusCode == 403 {
-				circuitBreaker.RecordFailure(resp.StatusCode)
+				breaker.RecordFailure(resp.StatusCode)
+			}
+
+			// Honor a 429's Retry-After: the breaker stays open at least that long, the next
+			// attempt waits for it, and one past the ceiling gives up so the caller reschedules
+			if resp.StatusCode == 429 {
+				if wait, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+					retryAfter = wait
+					breaker.RecordRetryAfter(wait)
+					log.Printf("429 with Retry-After %v (ceiling %v)", wait, s.maxRetryAfter)
+					if wait > s.maxRetryAfter {
+						if resp.Body != nil {
+							resp.Body.Close()
+						}
+						return nil, &RateLimitedError{RetryAfter: wait, msg: fmt.Sprintf("status code 429; Retry-After exceeds the %v ceiling", s.maxRetryAfter)}
+					}
+				}
 			}
 
 			if resp.Body != nil {
@@ -286,8 +412,14 @@ func (s *Scraper) doRequestWithRetry(req *http.Request) (*http.Response, error)
 				if serverBackoff > 60*time.Second {
 					serverBackoff = 60 * time.Second
 				}
+				if !budget.allows(serverBackoff) {
+					return nil, budget.exhausted(fmt.Sprintf("after %d attempt(s) (last: status %d); not backing off %v", attempt+1, resp.StatusCode, serverBackoff))
+				}
 				log.Printf("Server error %d, backing off for %v", resp.StatusCode, serverBackoff)
-				time.Sleep(serverBackoff)
+				s.recordStats(ctx, func(st *ScrapeStats) { st.PacingWait += serverBackoff })
+				if err := ratelimit.SleepContext(ctx, serverBackoff); err != nil {
+					return nil, fmt.Errorf("retry canceled: %w", err)
+				}
 			}
 		}
 
@@ -304,50 +436,110 @@ func (s *Scraper) doRequestWithRetry(req *http.Request) (*http.Response, error)
 	if err != nil {
 		return nil, fmt.Errorf("request failed after %d retries: %w", s.maxRetries, err)
 	}
-	// Include status code in error for caller to distinguish 404 vs WAF
+	// Typed errors let callers tell a delisted page (404) and throttling (429) from other failures
 	if resp != nil && resp.StatusCode == 404 {
-		return nil, fmt.Errorf("permanent_fail: status code 404 (property not found or delisted)")
+		return nil, fmt.Errorf("%w: status code 404 (property not found or delisted)", ErrNotFound)
+	}
+	if resp != nil && resp.StatusCode == 429 {
+		if retryAfter > 0 {
+			return nil, &RateLimitedError{RetryAfter: retryAfter, msg: fmt.Sprintf("request failed after %d retries: status code 429", s.maxRetries)}
+		}
+		return nil, fmt.Errorf("%w: request failed after %d retries: status code 429", ErrRateLimited, s.maxRetries)
 	}
 	return nil, fmt.Errorf("request failed after %d retries: status code %d", s.maxRetries, resp.StatusCode)
 }
 
-// ScrapeListPage scrapes a list page and returns property URLs
+// describeFailure summarizes a failed attempt for the retry budget error
+func describeFailure(resp *http.Response, err error) string {
+	if err != nil {
+		return err.Error()
+	}
+	if resp != nil {
+		return fmt.Sprintf("status %d", resp.StatusCode)
+	}
+	return "no response"
+}
+
+// ScrapeListPage scrapes a list page and returns property URLs (first page only; see ScrapeListPages)
 func (s *Scraper) ScrapeListPage(listURL string) ([]string, error) {
+	return s.ScrapeListPageContext(context.Background(), listURL)
+}
+
+// ScrapeListPageContext is ScrapeListPage that stops waiting/retrying once ctx ends
+func (s *Scraper) ScrapeListPageContext(ctx context.Context, listURL string) ([]string, error) {
+	page, err := s.ScrapeListPageWithMetaContext(ctx, listURL)
+	if err != nil {
+		return nil, err
+	}
+	return page.URLs, nil
+}
+
+// ScrapeListPageWithMeta is ScrapeListPage that also returns the result count and page count
+// the list page reports (-1 each when they cannot be parsed)
+func (s *Scraper) ScrapeListPageWithMeta(listURL string) (*ListPage, error) {
+	return s.ScrapeListPageWithMetaContext(context.Background(), listURL)
+}
+
+// ScrapeListPageWithMetaContext is ScrapeListPageWithMeta that stops waiting/retrying once ctx ends
+func (s *Scraper) ScrapeListPageWithMetaContext(ctx context.Context, listURL string) (*ListPage, error) {
+	ctx, done := s.startStats(ctx)
+	defer done()
+	page, _, err := s.scrapeListPage(ctx, listURL)
+	return page, err
+}
+
+// scrapeListPage scrapes one list page and returns its property URLs with the reported counts,
+// and the next page's URL ("" on the last page)
+func (s *Scraper) scrapeListPage(ctx context.Context, listURL string) (*ListPage, string, error) {
 	log.Printf("[ScrapeListPage] Starting scrape of list page: %s", listURL)
 
-	req, err := http.NewRequest("GET", listURL, nil)
+	if err := s.checkRobots(ctx, listURL); err != nil {
+		log.Printf("[ScrapeListPage] Skipping %s: %v", listURL, err)
+		return nil, "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", listURL, nil)
 	if err != nil {
 		log.Printf("[ScrapeListPage] Error creating request for %s: %v", listURL, err)
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return nil, "", fmt.Errorf("failed to create request: %w", err)
 	}
 
 	// Apply browser-like headers (no referer for list page)
-	applyBrowserHeaders(req, "")
+	s.applyHeaders(req, "")
 
 	resp, err := s.doRequestWithRetry(req)
 	if err != nil {
 		log.Printf("[ScrapeListPage] Error fetching list page %s: %v", listURL, err)
-		return nil, fmt.Errorf("failed to fetch list page: %w", err)
+		return nil, "", fmt.Errorf("failed to fetch list page: %w", err)
 	}
 	defer resp.Body.Close()
 
-	// Handle gzip decompression if needed
-	var reader io.Reader = resp.Body
-	if resp.Header.Get("Content-Encoding") == "gzip" {
-		gzipReader, err := gzip.NewReader(resp.Body)
-		if err != nil {
-			log.Printf("[ScrapeListPage] Error creating gzip reader: %v", err)
-			return nil, fmt.Errorf("failed to create gzip reader: %w", err)
-		}
-		defer gzipReader.Close()
-		reader = gzipReader
+	// Undo gzip/deflate/br (we set Accept-Encoding, so net/http does not)
+	reader, err := decodeBody(resp)
+	if err != nil {
+		log.Printf("[ScrapeListPage] Error decoding body from %s: %v", listURL, err)
+		return nil, "", err
+	}
+	defer reader.Close()
+
+	// Shift_JIS and other legacy charsets become UTF-8 before goquery sees them
+	utf8Reader, pageCharset, err := toUTF8(reader, resp.Header.Get("Content-Type"))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read body: %w", err)
+	}
+	if pageCharset != "utf-8" {
+		log.Printf("[ScrapeListPage] Transcoding %s from %s", listURL, pageCharset)
 	}
 
-	// Parse HTML (goquery will read body completely, maintaining connection stability)
-	doc, err := goquery.NewDocumentFromReader(reader)
+	// Parse HTML (reads the body completely, maintaining connection stability)
+	if err := acquireParse(ctx); err != nil {
+		return nil, "", err
+	}
+	defer ParseLimiter.Release()
+	doc, err := parseDocument(utf8Reader)
 	if err != nil {
 		log.Printf("[ScrapeListPage] Error parsing HTML from %s: %v", listURL, err)
-		return nil, fmt.Errorf("failed to parse HTML: %w", err)
+		return nil, "", fmt.Errorf("%w: failed to parse HTML: %w", ErrParse, err)
 	}
 
 	var propertyURLs []string
@@ -359,8 +551,8 @@ func (s *Scraper) ScrapeListPage(listURL string) ([]string, error) {
 	//   - "_0000" prefix + 40-char ID (45 chars total)
 	//   - "0000" prefix + 40-char ID (44 chars total)
 	//   - 40-char ID (no prefix, 40 chars total)
-	doc.Find("input._propertyCheckbox").Each(func(i int, s *goquery.Selection) {
-		value, exists := s.Attr("value")
+	doc.Find("input._propertyCheckbox").Each(func(i int, sel *goquery.Selection) {
+		value, exists := sel.Attr("value")
 
 		if !exists {
 			return
@@ -380,7 +572,7 @@ func (s *Scraper) ScrapeListPage(listURL string) ([]string, error) {
 		}
 
 		// Build detail URL
-		propertyURL := "https://realestate.yahoo.co.jp/rent/detail/" + propertyID
+		propertyURL := s.baseURL + "/rent/detail/" + propertyID
 
 		// Normalize URL to avoid duplicates
 		normalizedURL := normalizeURL(propertyURL)
@@ -392,15 +584,22 @@ func (s *Scraper) ScrapeListPage(listURL string) ([]string, error) {
 		}
 	})
 
-	log.Printf("[ScrapeListPage] Found %d unique property URLs from %s", len(propertyURLs), listURL)
-	return propertyURLs, nil
+	page := listPageMeta(doc, propertyURLs)
+	log.Printf("[ScrapeListPage] Found %d unique property URLs from %s (site reports %d results / %d pages)",
+		len(propertyURLs), listURL, page.TotalOnSite, page.Pages)
+	return page, findNextPageURL(doc, listURL), nil
 }
 
 // fetchHTMLWithHeadlessBrowser uses Chrome headless browser to fetch HTML
 // This bypasses most anti-bot detection by executing JavaScript
-func (s *Scraper) fetchHTMLWithHeadlessBrowser(url string) (string, error) {
+func (s *Scraper) fetchHTMLWithHeadlessBrowser(ctx context.Context, url, referer string, v Validators) (fetchedPage, error) {
 	log.Printf("[HeadlessBrowser] Fetching %s with Chrome", url)
 
+	// Chrome doesn't go through doRequestWithRetry, so the request delay is applied here
+	if err := s.pace(ctx); err != nil {
+		return fetchedPage{}, fmt.Errorf("scrape canceled: %w", err)
+	}
+
 	// Chrome execution options for systemd compatibility
 	opts := append(chromedp.DefaultExecAllocatorOptions[:],
 		chromedp.ExecPath("/usr/bin/google-chrome"), // Use Google Chrome
@@ -410,36 +609,82 @@ func (s *Scraper) fetchHTMLWithHeadlessBrowser(url string) (string, error) {
 		chromedp.Flag("disable-dev-shm-usage", true), // Prevents /dev/shm issues
 		chromedp.Flag("disable-setuid-sandbox", true),
 		chromedp.Flag("disable-software-rasterizer", true),
-		chromedp.UserAgent("Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/122.0.0.0 Safari/537.36"),
+		chromedp.UserAgent(s.UserAgent()),
 	)
+	// Chrome takes no proxy credentials, so only the host is passed; rotation is per page load
+	if s.proxies != nil {
+		opts = append(opts, chromedp.ProxyServer(redactProxy(s.proxies.pick())))
+	}
 
-	// Create allocator context with Chrome options
-	allocCtx, allocCancel := chromedp.NewExecAllocator(context.Background(), opts...)
+	// Create allocator context with Chrome options (canceling ctx shuts Chrome down)
+	allocCtx, allocCancel := chromedp.NewExecAllocator(ctx, opts...)
 	defer allocCancel()
 
 	// Create browser context
-	ctx, cancel := chromedp.NewContext(allocCtx)
+	browserCtx, cancel := chromedp.NewContext(allocCtx)
 	defer cancel()
 
 	// Set a timeout for the entire operation (30 seconds)
-	ctx, cancel = context.WithTimeout(ctx, 30*time.Second)
+	browserCtx, cancel = context.WithTimeout(browserCtx, 30*time.Second)
 	defer cancel()
 
-	var htmlContent string
-	err := chromedp.Run(ctx,
-		// Navigate to the URL
-		chromedp.Navigate(url),
+	// Capture the main document's status and validators
+	var mu sync.Mutex
+	var docStatus int64
+	var docValidators Validators
+	chromedp.ListenTarget(browserCtx, func(ev interface{}) {
+		if e, ok := ev.(*network.EventResponseReceived); ok && e.Type == network.ResourceTypeDocument {
+			mu.Lock()
+			defer mu.Unlock()
+			if docStatus == 0 {
+				docStatus = e.Response.Status
+				docValidators = validatorsFromCDP(e.Response.Headers)
+			}
+		}
+	})
+
+	navigate := chromedp.Tasks{network.Enable(), s.setBrowserCookies(ctx, url)}
+	if !v.IsZero() || referer != "" {
+		headers := network.Headers{}
+		for k, val := range v.headers() {
+			headers[k] = val
+		}
+		if referer != "" {
+			headers["Referer"] = referer
+		}
+		navigate = append(navigate, network.SetExtraHTTPHeaders(headers))
+	}
+	navigate = append(navigate, chromedp.Navigate(url))
+	err := chromedp.Run(browserCtx, navigate)
+
+	mu.Lock()
+	status, validators := docStatus, docValidators
+	mu.Unlock()
+	s.recordResponse(ctx, int(status))
+	if status == http.StatusNotModified {
+		log.Printf("[HeadlessBrowser] 304 Not Modified for %s", url)
+		return fetchedPage{validators: v, notModified: true}, nil
+	}
+	if err != nil {
+		log.Printf("[HeadlessBrowser] ERROR fetching %s: %v", url, err)
+		return fetchedPage{}, fmt.Errorf("chromedp error: %w", err)
+	}
+
+	var htmlContent, finalURL string
+	err = chromedp.Run(browserCtx,
 		// Wait for the page to load (wait for body element)
 		chromedp.WaitVisible(`body`, chromedp.ByQuery),
 		// Wait a bit more for JavaScript to execute
 		chromedp.Sleep(3*time.Second),
 		// Get the rendered HTML
 		chromedp.OuterHTML(`html`, &htmlContent, chromedp.ByQuery),
+		// Where Chrome ended up after redirects
+		chromedp.Location(&finalURL),
 	)
 
 	if err != nil {
 		log.Printf("[HeadlessBrowser] ERROR fetching %s: %v", url, err)
-		return "", fmt.Errorf("chromedp error: %w", err)
+		return fetchedPage{}, fmt.Errorf("chromedp error: %w", err)
 	}
 
 	// Log HTML size and preview
@@ -448,47 +693,273 @@ func (s *Scraper) fetchHTMLWithHeadlessBrowser(url string) (string, error) {
 	if htmlSize < previewLen {
 		previewLen = htmlSize
 	}
+	// Chrome renders the block page like any other; it must not be parsed into a listing
+	if IsWAFBlockPage(htmlContent) {
+		log.Printf("[HeadlessBrowser] WAF block page for %s (status %d)", url, status)
+		s.circuitBreaker.RecordBlock(int(status))
+		return fetchedPage{}, fmt.Errorf("%w: immediate retreat required (status %d)", ErrWAFBlocked, status)
+	}
 	log.Printf("[HeadlessBrowser] Successfully fetched HTML (%d bytes)", htmlSize)
 	log.Printf("[HeadlessBrowser] HTML preview (first %d chars): %s", previewLen, htmlContent[:previewLen])
 
-	return htmlContent, nil
+	return fetchedPage{html: htmlContent, validators: validators, finalURL: finalURL}, nil
+}
+
+// fetchHTML fetches a page with the plain HTTP client (used in fixture mode)
+func (s *Scraper) fetchHTML(ctx context.Context, pageURL, referer string, v Validators) (fetchedPage, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", pageURL, nil)
+	if err != nil {
+		return fetchedPage{}, fmt.Errorf("failed to create request: %w", err)
+	}
+	s.applyHeaders(req, referer)
+	for k, val := range v.headers() {
+		req.Header.Set(k, val)
+	}
+
+	resp, err := s.doRequestWithRetry(req)
+	if err != nil {
+		return fetchedPage{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified {
+		return fetchedPage{validators: v, notModified: true}, nil
+	}
+
+	reader, err := decodeBody(resp)
+	if err != nil {
+		return fetchedPage{}, err
+	}
+	defer reader.Close()
+
+	utf8Reader, pageCharset, err := toUTF8(reader, resp.Header.Get("Content-Type"))
+	if err != nil {
+		return fetchedPage{}, fmt.Errorf("failed to read body: %w", err)
+	}
+	if pageCharset != "utf-8" {
+		log.Printf("[fetchHTML] Transcoding %s from %s", pageURL, pageCharset)
+	}
+
+	body, err := io.ReadAll(utf8Reader)
+	if err != nil {
+		return fetchedPage{}, fmt.Errorf("failed to read body: %w", err)
+	}
+	// The client follows redirects; resp.Request is the last request of the chain
+	return fetchedPage{html: string(body), validators: validatorsFromHeader(resp.Header), finalURL: resp.Request.URL.String()}, nil
 }
 
 // ScrapeProperty scrapes a property detail page
 func (s *Scraper) ScrapeProperty(inputURL string) (*models.Property, error) {
-	return s.ScrapePropertyWithReferer(inputURL, "")
+	return s.scrapeProperty(context.Background(), inputURL, "", Validators{})
+}
+
+// ScrapePropertyContext scrapes a property detail page, tracing under the span carried by ctx.
+// Canceling ctx aborts the human-pace sleep, limiter wait, retries and the browser fetch.
+func (s *Scraper) ScrapePropertyContext(ctx context.Context, inputURL string) (*models.Property, error) {
+	return s.scrapeProperty(ctx, inputURL, "", Validators{})
 }
 
 // ScrapePropertyWithReferer scrapes a property detail page with optional referer
 // NOTE: Rate limiting (DetailLimiter) should be applied by the caller, not here.
 // This function only applies human-like delay to avoid detection.
 func (s *Scraper) ScrapePropertyWithReferer(inputURL string, referer string) (*models.Property, error) {
+	return s.scrapeProperty(context.Background(), inputURL, referer, Validators{})
+}
+
+// detailPage is what one detail page yielded besides the property: its stations, its gallery
+// and the redirect it was served through (nil when none)
+type detailPage struct {
+	property *models.Property
+	stations []StationAccess
+	images   []string
+	redirect *PropertyRedirect
+}
+
+// keepLast stores page for GetLastStations / GetLastImages / GetLastRedirect (nil only clears
+// the redirect)
+func (s *Scraper) keepLast(page *detailPage) {
+	s.lastMu.Lock()
+	defer s.lastMu.Unlock()
+	if page == nil {
+		s.lastRedirect = nil
+		return
+	}
+	s.lastStations, s.lastImages, s.lastRedirect = page.stations, page.images, page.redirect
+}
+
+// scrapeProperty is scrapeDetailPage for the callers that read stations, images and the
+// redirect back through the GetLast* methods
+func (s *Scraper) scrapeProperty(ctx context.Context, inputURL string, referer string, v Validators) (*models.Property, error) {
+	detail, err := s.scrapeDetailPage(ctx, inputURL, referer, v)
+	s.keepLast(detail)
+	if err != nil {
+		return nil, err
+	}
+	return detail.property, nil
+}
+
+// scrapeDetailPage fetches and parses one detail page. Nothing is kept on s, so concurrent
+// calls never see each other's stations, images or redirect.
+func (s *Scraper) scrapeDetailPage(ctx context.Context, inputURL string, referer string, v Validators) (_ *detailPage, retErr error) {
+	ctx, span := tracing.Start(ctx, "scraper.ScrapeProperty", attribute.String("scrape.url", inputURL))
+	defer func() {
+		tracing.RecordError(span, retErr)
+		span.End()
+	}()
+	ctx, done := s.startStats(ctx)
+	defer done()
+	if referer == "" {
+		referer = refererFrom(ctx)
+	}
+
 	// Normalize URL (remove query strings, trailing slash)
 	normalizedURL := normalizeURL(inputURL)
 	log.Printf("[ScrapeProperty] Starting scrape of property: %s (normalized: %s, referer: %s)", inputURL, normalizedURL, referer)
 
-	// Visit homepage if needed to establish/maintain session
-	if err := s.visitHomepageIfNeeded(); err != nil {
-		log.Printf("[ScrapeProperty] Warning: Failed to visit homepage: %v", err)
+	// Checked before the homepage visit and human-pace sleep so a disallowed URL costs no requests
+	if err := s.checkRobots(ctx, normalizedURL); err != nil {
+		log.Printf("[ScrapeProperty] Skipping %s: %v", normalizedURL, err)
+		return nil, err
+	}
+
+	// Warm up the session (landing page cookies) if needed
+	if err := s.warmUpSession(ctx); err != nil {
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("scrape canceled: %w", ctx.Err())
+		}
+		log.Printf("[ScrapeProperty] Warning: Failed to warm up session: %v", err)
 		// Continue anyway, as this is not a critical error
 	}
 
 	// Sleep to simulate human browsing behavior (45-120s, sometimes 3-7 minutes)
 	// NOTE: DetailLimiter.Acquire() should be called by the caller before this function
-	sleepHumanDetailPace()
+	if !s.fixtureMode {
+		sleepStart := time.Now()
+		err := sleepHumanDetailPace(ctx)
+		s.recordStats(ctx, func(st *ScrapeStats) { st.PacingWait += time.Since(sleepStart) })
+		if err != nil {
+			return nil, fmt.Errorf("scrape canceled: %w", err)
+		}
+	}
 
-	// Fetch the page using headless browser
-	htmlContent, err := s.fetchHTMLWithHeadlessBrowser(normalizedURL)
+	// Fetch the page using headless browser (plain HTTP in fixture mode)
+	var page fetchedPage
+	var err error
+	if s.fixtureMode {
+		page, err = s.fetchHTML(ctx, normalizedURL, referer, v)
+	} else {
+		_, fetchSpan := tracing.Start(ctx, "scraper.fetchHTMLWithHeadlessBrowser")
+		page, err = s.fetchHTMLWithHeadlessBrowser(ctx, normalizedURL, referer, v)
+		tracing.RecordError(fetchSpan, err)
+		fetchSpan.End()
+	}
 	if err != nil {
 		log.Printf("[ScrapeProperty] Error fetching URL with headless browser %s: %v", normalizedURL, err)
 		return nil, fmt.Errorf("failed to fetch URL: %w", err)
 	}
+	if page.notModified {
+		span.SetAttributes(attribute.Bool("scrape.not_modified", true))
+		log.Printf("[ScrapeProperty] 304 Not Modified: %s (skipping parse)", normalizedURL)
+		return nil, ErrNotModified
+	}
+
+	// A redirected page is parsed under the URL it was served from
+	pageURL := normalizedURL
+	if page.finalURL != "" {
+		pageURL = normalizeURL(page.finalURL)
+	}
+	detail, err := s.parseDetailHTML(ctx, page.html, pageURL)
+	if err != nil {
+		return nil, err
+	}
+	detail.redirect = detectRedirect(normalizedURL, pageURL, detail.property)
+	if detail.redirect != nil {
+		span.SetAttributes(attribute.String("scrape.redirected_to", detail.redirect.ToID))
+	}
+	detail.property.ETag = page.validators.ETag
+	detail.property.LastModified = page.validators.LastModified
+	return detail, nil
+}
 
-	// Parse HTML
-	doc, err := goquery.NewDocumentFromReader(strings.NewReader(htmlContent))
+// ParsePropertyHTML extracts a property (plus stations and images, see GetLastStations/GetLastImages)
+// from a fetched detail page. pageURL is the normalized URL the HTML was fetched from.
+func (s *Scraper) ParsePropertyHTML(ctx context.Context, htmlContent string, pageURL string) (*models.Property, error) {
+	detail, err := s.parseDetailHTML(ctx, htmlContent, pageURL)
 	if err != nil {
-		log.Printf("[ScrapeProperty] Error parsing HTML from %s: %v", normalizedURL, err)
-		return nil, fmt.Errorf("failed to parse HTML: %w", err)
+		return nil, err
+	}
+	s.keepLast(detail)
+	return detail.property, nil
+}
+
+// parseDetailHTML is ParsePropertyHTML without keeping anything on s
+func (s *Scraper) parseDetailHTML(ctx context.Context, htmlContent string, pageURL string) (_ *detailPage, retErr error) {
+	_, span := tracing.Start(ctx, "scraper.ParsePropertyHTML", attribute.Int("html.bytes", len(htmlContent)))
+	defer func() {
+		tracing.RecordError(span, retErr)
+		span.End()
+	}()
+
+	// Parse HTML (the parse slot is held until extraction is done; the DOM lives that long)
+	if err := acquireParse(ctx); err != nil {
+		return nil, err
+	}
+	defer ParseLimiter.Release()
+	doc, err := parseDocument(strings.NewReader(htmlContent))
+	if err != nil {
+		log.Printf("[ScrapeProperty] Error parsing HTML from %s: %v", pageURL, err)
+		if id, idErr := extractYahooPropertyID(pageURL); idErr == nil {
+			s.debugHTML.save(id, htmlContent, "parse error")
+		} else {
+			s.debugHTML.save(pageURL, htmlContent, "parse error")
+		}
+		return nil, fmt.Errorf("%w: failed to parse HTML: %w", ErrParse, err)
+	}
+
+	detail, err := s.parseDetailDocument(ctx, doc, pageURL)
+	if err != nil {
+		return nil, err
+	}
+	if detail.property.Title == "No Title" || detail.property.Rent == nil {
+		s.debugHTML.save(detail.property.SourcePropertyID, htmlContent, "no title or rent")
+	}
+	return detail, nil
+}
+
+// ParsePropertyDocument extracts a property and its stations from a parsed detail page. Nothing is
+// fetched (image HEAD checks only run with VerifyImages), so saved pages can be re-parsed offline.
+// sourceURL is the normalized URL the page came from; a canonical link in the page takes precedence.
+// Stations and images are also kept for GetLastStations/GetLastImages.
+func (s *Scraper) ParsePropertyDocument(doc *goquery.Document, sourceURL string) (*models.Property, []models.PropertyStation, error) {
+	detail, err := s.parseDetailDocument(context.Background(), doc, sourceURL)
+	if err != nil {
+		return nil, nil, err
+	}
+	s.keepLast(detail)
+	return detail.property, convertStationsToModels(detail.property.ID, detail.stations), nil
+}
+
+// parseDetailDocument is ParsePropertyDocument without keeping anything on s. Image checks
+// count into the stats of the call ctx belongs to.
+func (s *Scraper) parseDetailDocument(ctx context.Context, doc *goquery.Document, sourceURL string) (*detailPage, error) {
+	if doc == nil {
+		return nil, fmt.Errorf("%w: no document for %s", ErrParse, sourceURL)
+	}
+	normalizedURL := sourceURL
+
+	// Yahoo answers an ended listing with 200 and a notice page: nothing to parse or save
+	if isDelistedPage(doc) {
+		log.Printf("[ScrapeProperty] %s is an ended-listing page (掲載終了)", sourceURL)
+		return nil, fmt.Errorf("%w: %s", ErrDelisted, sourceURL)
+	}
+
+	// A building page lists several units: they are returned instead of one mixed property
+	if isBuildingPage(doc, sourceURL) {
+		building := parseBuildingPage(doc, sourceURL)
+		if len(building.Units) == 0 {
+			return nil, fmt.Errorf("%w: building page %s lists no units", ErrParse, sourceURL)
+		}
+		return nil, building
 	}
 
 	// Check for canonical URL
@@ -497,19 +968,10 @@ func (s *Scraper) ScrapePropertyWithReferer(inputURL string, referer string) (*m
 		normalizedURL = normalizeURL(canonicalURL)
 	}
 
-	// Extract Yahoo property ID from URL
-	yahooPropertyID, err := extractYahooPropertyID(normalizedURL)
-	if err != nil {
-		log.Printf("[ScrapeProperty] Warning: Could not extract Yahoo property ID from %s: %v", normalizedURL, err)
-		// Fallback to URL hash for non-standard URLs
-		hash := md5.Sum([]byte(normalizedURL))
-		yahooPropertyID = hex.EncodeToString(hash[:])
-	}
-
 	// Extract metadata
 	property := &models.Property{
 		Source:           "yahoo",
-		SourcePropertyID: yahooPropertyID,
+		SourcePropertyID: yahooPropertyIDOrHash(normalizedURL),
 		DetailURL:        normalizedURL,
 		FetchedAt:        time.Now(),
 	}
@@ -527,7 +989,9 @@ func (s *Scraper) ScrapePropertyWithReferer(inputURL string, referer string) (*m
 	log.Printf("  - <title> tag: %q", titleTag)
 	log.Printf("  - <h1> tag: %q", h1Tag)
 
-	if ogExists && strings.TrimSpace(ogTitle) != "" {
+	if selectorTitle := s.selectorText(doc, SelectorTitle); selectorTitle != "" {
+		property.Title = selectorTitle
+	} else if ogExists && strings.TrimSpace(ogTitle) != "" {
 		property.Title = strings.TrimSpace(ogTitle)
 	} else if twitterExists && strings.TrimSpace(twitterTitle) != "" {
 		property.Title = strings.TrimSpace(twitterTitle)
@@ -561,20 +1025,26 @@ func (s *Scraper) ScrapePropertyWithReferer(inputURL string, referer string) (*m
 	pageHTML, _ := doc.Html()
 	allImageURLs := extractAllImageURLsFromJSON(pageHTML)
 
-	// Store all image URLs for later retrieval
-	s.lastImages = allImageURLs
+	// All image URLs go back with the property
+	images := allImageURLs
 
 	// Set the first image as the primary image for backward compatibility
-	if len(allImageURLs) > 0 {
+	if imageURL := s.selectorImage(doc, sourceURL); imageURL != "" && s.verifyImageURL(ctx, imageURL) {
+		property.ImageURL = imageURL
+		if len(images) == 0 {
+			images = []string{imageURL}
+		}
+		log.Printf("[ScrapeProperty] Using the configured image selector")
+	} else if len(allImageURLs) > 0 {
 		property.ImageURL = allImageURLs[0]
 		log.Printf("[ScrapeProperty] Set primary image from %d total images", len(allImageURLs))
 	} else {
 		// Fallback to og:image if no images found in JSON
 		if imageURL, exists := doc.Find("meta[property='og:image']").Attr("content"); exists {
 			imageURL = strings.TrimSpace(imageURL)
-			if s.verifyImageURL(imageURL) {
+			if s.verifyImageURL(ctx, imageURL) {
 				property.ImageURL = imageURL
-				s.lastImages = []string{imageURL}
+				images = []string{imageURL}
 				log.Printf("[ScrapeProperty] Using og:image as fallback")
 			}
 		}
@@ -583,20 +1053,51 @@ func (s *Scraper) ScrapePropertyWithReferer(inputURL string, referer string) (*m
 	// Extract additional details from the page
 	s.extractDetailFields(doc, property)
 
+	// Floor plan image (間取り図), never a copy of the main photo
+	s.applyRoomLayoutImage(ctx, doc, sourceURL, property)
+
+	// 方位 / 駐車場 / 契約期間 / 入居可能時期 / 条件等 / 階数 rows the embedded JSON did not provide
+	applyDetailRows(doc, property)
+
+	// Coordinates from the map iframe when the embedded JSON has none
+	if property.Latitude == nil {
+		applyMapCoordinates(doc, property)
+	}
+
+	// Unit floor / building floors from the floor label (Floor follows unit_floor)
+	property.NormalizeFloors()
+
+	// Campaign markers (フリーレント / 仲介手数料無料) from the title, notes and page body
+	property.ApplyCampaigns(models.ParseCampaigns(
+		property.Title, property.Notes, property.Conditions, doc.Find("body").Text(),
+	))
+
+	// Building type / structure from the detail table when the embedded JSON lacks them
+	applyBuildingRows(doc, property)
+
+	// Contract costs (管理費・共益費 / 敷金 / 礼金 / 保証金 / 敷引) and their yen amounts
+	applyContractCosts(doc, property)
+	property.NormalizeFees()
+
+	// Lease type (定期借家 / 普通借家) and entry conditions from the 契約期間 / 条件等 rows
+	property.ApplyLeaseTerms(models.ParseLeaseTerms(
+		append([]string{property.ContractPeriod, property.Conditions}, extractLeaseRows(doc)...)...,
+	))
+
 	// Extract stations (new: for property_stations table)
 	// Apply backward compatibility by copying sort_order=1 to legacy fields
-	stations := extractStations(doc)
+	stations := s.selectorStations(doc)
+	if stations == nil {
+		stations = extractStations(doc)
+	}
 	applyStationCompatibility(property, stations)
-	// Store stations in scraper for retrieval by API handler
-	s.lastStations = stations
-	// Note: The actual saving to property_stations table happens in the API handler
+	property.NormalizeWalkTimeBucket()
+	// Note: The actual saving to property_stations table happens in the API handler / worker
 	// via gormDB.SavePropertyWithStations()
 
 	// Generate internal ID from source + source_property_id
 	// This ensures consistent ID generation across the application
-	idSource := property.Source + ":" + property.SourcePropertyID
-	hash := md5.Sum([]byte(idSource))
-	property.ID = hex.EncodeToString(hash[:])
+	property.ID = internalPropertyID(property.Source, property.SourcePropertyID)
 
 	// Validate required fields
 	if property.Title == "" {
@@ -605,7 +1106,7 @@ func (s *Scraper) ScrapePropertyWithReferer(inputURL string, referer string) (*m
 	}
 
 	log.Printf("[ScrapeProperty] Successfully scraped property %s (ID: %s, Title: %s, Stations: %d)", normalizedURL, property.ID, property.Title, len(stations))
-	return property, nil
+	return &detailPage{property: property, stations: stations, images: images}, nil
 }
 
 // extractPropertyDataFromHTML extracts property data directly from __SERVER_SIDE_CONTEXT__ using regex
@@ -809,18 +1310,26 @@ func extractPropertyDataFromHTML(htmlString string) map[string]interface{} {
 		}
 	}
 
+	// Extract Latitude / Longitude (地図座標、文字列の場合もある)
+	latRe := regexp.MustCompile(`"(?:Latitude|Lat)"\s*:\s*"?(-?[0-9]+\.[0-9]+)`)
+	lngRe := regexp.MustCompile(`"(?:Longitude|Lng|Lon)"\s*:\s*"?(-?[0-9]+\.[0-9]+)`)
+	if latMatch, lngMatch := latRe.FindStringSubmatch(contextSection), lngRe.FindStringSubmatch(contextSection); latMatch != nil && lngMatch != nil {
+		lat, latErr := strconv.ParseFloat(latMatch[1], 64)
+		lng, lngErr := strconv.ParseFloat(lngMatch[1], 64)
+		if latErr == nil && lngErr == nil {
+			result["Latitude"], result["Longitude"] = lat, lng
+			log.Printf("[extractPropertyDataFromHTML] Found Latitude/Longitude: %f,%f", lat, lng)
+		}
+	}
+
 	log.Printf("[extractPropertyDataFromHTML] Extracted %d fields", len(result))
 	return result
 }
 
-// extractServerSideContextJSON extracts __SERVER_SIDE_CONTEXT__ JSON from HTML
-func extractServerSideContextJSON(htmlString string) (map[string]interface{}, error) {
+// extractServerSideContextJSON extracts __SERVER_SIDE_CONTEXT__ JSON from an already parsed page
+// (the caller holds the parse slot, so the document is not parsed a second time here)
+func extractServerSideContextJSON(doc *goquery.Document) (map[string]interface{}, error) {
 	// Find script tags containing __SERVER_SIDE_CONTEXT__
-	doc, err := goquery.NewDocumentFromReader(strings.NewReader(htmlString))
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse HTML: %w", err)
-	}
-
 	var scriptText string
 	doc.Find("script").EachWithBreak(func(_ int, s *goquery.Selection) bool {
 		text := s.Text()
@@ -934,12 +1443,16 @@ func extractServerSideContextJSON(htmlString string) (map[string]interface{}, er
 	// Unescape HTML entities
 	jsonStr = html.UnescapeString(jsonStr)
 
-	// Yahoo uses JavaScript object literal format (unquoted keys), not standard JSON
-	// Convert to standard JSON by adding quotes around keys
+	// Yahoo sometimes uses JavaScript object literal format (unquoted keys), not standard JSON.
+	// Only then convert by adding quotes around keys, since the rewrite can touch string values
+	var result map[string]interface{}
+	if err := json.Unmarshal([]byte(jsonStr), &result); err == nil {
+		log.Printf("[extractServerSideContextJSON] Successfully parsed JSON, keys: %d", len(result))
+		return result, nil
+	}
 	jsonStr = convertJSObjectToJSON(jsonStr)
 
 	// Parse JSON
-	var result map[string]interface{}
 	if err := json.Unmarshal([]byte(jsonStr), &result); err != nil {
 		// Log first 500 chars of JSON for debugging
 		preview := jsonStr
@@ -1024,54 +1537,32 @@ func getString(m map[string]interface{}, path string) (string, bool) {
 }
 
 // extractDetailFields extracts detailed property information from the DOM
-// First tries to extract from __SERVER_SIDE_CONTEXT__ using regex, then falls back to DOM scraping
+// First unmarshals the property object in __SERVER_SIDE_CONTEXT__; when the blob is missing or
+// does not parse, regexes over the blob and finally the page text are used instead
 func (s *Scraper) extractDetailFields(doc *goquery.Document, property *models.Property) {
-	// Try to extract from __SERVER_SIDE_CONTEXT__ using direct regex first (most reliable)
-	htmlString, _ := doc.Html()
-	contextData := extractPropertyDataFromHTML(htmlString)
+	// Configured selectors (scraper.selectors) win over both paths below whenever they match
+	defer s.applyFieldSelectors(doc, property)
+
+	// Structured path: the property object from the parsed __SERVER_SIDE_CONTEXT__ JSON
+	contextData, err := extractPropertyDataFromJSON(doc)
+	if err != nil {
+		// Regex path: pick individual keys out of the blob (tolerates a blob that is not valid JSON)
+		log.Printf("[extractDetailFields] id=%s Structured context unavailable (%v), falling back to regex extraction",
+			property.SourcePropertyID, err)
+		htmlString, _ := doc.Html()
+		contextData = extractPropertyDataFromHTML(htmlString)
+	}
 
 	if len(contextData) > 0 {
 		log.Printf("[extractDetailFields] Found __SERVER_SIDE_CONTEXT__ data, extracting %d fields", len(contextData))
 		s.extractFromContextData(contextData, property)
+		if property.Address == "" {
+			property.Address = extractAddress(doc)
+		}
 
 		// Also extract facilities from HTML labels (人気の特徴・設備 + category lines)
 		// This handles properties that use Japanese labels instead of internal codes
-		popularLabels := extractPopularFeatureLabels(doc)
-		categoryLabels := extractCategoryFacilityLabels(doc)
-		allLabels := append(popularLabels, categoryLabels...)
-
-		if len(allLabels) > 0 {
-			log.Printf("[extractDetailFields] id=%s Extracted %d facility labels from HTML", property.ID, len(allLabels))
-			labelKeys := normalizeFacilitiesFromLabels(allLabels)
-
-			// Combine code-based and label-based facilities (union operation)
-			var existingKeys []string
-			if property.Facilities != "" {
-				json.Unmarshal([]byte(property.Facilities), &existingKeys)
-			}
-
-			// Merge and deduplicate
-			allKeys := append(existingKeys, labelKeys...)
-			uniqueKeys := make(map[string]bool)
-			for _, key := range allKeys {
-				if key != "" {
-					uniqueKeys[key] = true
-				}
-			}
-
-			// Convert back to sorted array
-			finalKeys := make([]string, 0, len(uniqueKeys))
-			for key := range uniqueKeys {
-				finalKeys = append(finalKeys, key)
-			}
-			sort.Strings(finalKeys)
-
-			if len(finalKeys) > 0 {
-				result, _ := json.Marshal(finalKeys)
-				property.Facilities = string(result)
-				log.Printf("[extractDetailFields] id=%s Combined facilities: %s", property.ID, property.Facilities)
-			}
-		}
+		applyFacilityLabels(doc, property)
 
 		return
 	}
@@ -1081,28 +1572,33 @@ func (s *Scraper) extractDetailFields(doc *goquery.Document, property *models.Pr
 	// Fallback: Extract from the page text (best effort)
 	pageText := doc.Text()
 
-	// Extract rent (賃料)
-	if rent := extractRent(pageText); rent > 0 {
+	// Extract rent (賃料): the 賃料 row, else an amount next to the word 賃料
+	rent := extractRentFromTable(doc)
+	if rent == 0 {
+		rent = extractRent(pageText)
+	}
+	if rent > 0 {
 		property.Rent = &rent
 	}
 
-	// Extract floor plan (間取り)
-	property.FloorPlan = extractFloorPlan(pageText)
-
-	// Extract area (面積)
-	if area := extractArea(pageText); area > 0 {
-		property.Area = &area
+	// Extract floor plan (間取り): the 間取り row, else the plan right after the word 間取り
+	property.FloorPlan = extractFloorPlanFromTable(doc)
+	if property.FloorPlan == "" {
+		property.FloorPlan = extractFloorPlan(pageText)
 	}
 
-	// Extract walk time (徒歩)
-	if walkTime := extractWalkTime(pageText); walkTime > 0 {
-		property.WalkTime = &walkTime
+	// Extract area (専有面積): the 専有面積 row, else the value right after the label in the text
+	// (never バルコニー面積; 坪 / 帖 only values are converted to ㎡)
+	area, areaUnit := extractAreaFromTable(doc)
+	if areaUnit == "" {
+		area, areaUnit = extractArea(pageText)
 	}
+	setArea(property, area, areaUnit)
 
-	// Extract station (駅名)
-	property.Station = extractStation(pageText)
+	// Station / walk time come from the access block (extractStations), never from a
+	// page-wide "○○駅 徒歩N分" match that may mix two different stations
 
-	// Extract address (住所)
+	// Extract address (住所): the 所在地 row, else the first address in the page text
 	property.Address = extractAddress(doc)
 
 	// Extract building age (築年数)
@@ -1114,6 +1610,38 @@ func (s *Scraper) extractDetailFields(doc *goquery.Document, property *models.Pr
 	if floor := extractFloor(pageText); floor != 0 {
 		property.Floor = &floor
 	}
+
+	// Facilities / feature tags from the HTML labels
+	applyFacilityLabels(doc, property)
+}
+
+// applyFacilityLabels merges the page's facility labels (人気の特徴・設備 + category lines) into
+// Facilities as English keys, and stores the 人気の特徴・設備 tags themselves ("バス・トイレ別",
+// "オートロック") as Features. Pickout codes from __SERVER_SIDE_CONTEXT__ are kept only when the
+// page shows no tags. Both fields stay valid JSON arrays ("[]" when nothing was found).
+func applyFacilityLabels(doc *goquery.Document, property *models.Property) {
+	popularLabels := extractPopularFeatureLabels(doc)
+	categoryLabels := extractCategoryFacilityLabels(doc)
+	allLabels := append(popularLabels, categoryLabels...)
+
+	if len(allLabels) > 0 {
+		log.Printf("[extractDetailFields] id=%s Extracted %d facility labels from HTML", property.ID, len(allLabels))
+		labelKeys := normalizeFacilitiesFromLabels(allLabels)
+
+		// Combine code-based and label-based facilities (union; SetFacilities dedupes and sorts)
+		allKeys := append(property.GetFacilities(), labelKeys...)
+		if len(allKeys) > 0 {
+			property.SetFacilities(allKeys)
+			log.Printf("[extractDetailFields] id=%s Combined facilities: %s", property.ID, property.Facilities)
+		}
+	}
+
+	if len(popularLabels) > 0 {
+		property.SetFeatures(popularLabels)
+		log.Printf("[extractDetailFields] id=%s Features: %s", property.ID, property.Features)
+	}
+
+	property.NormalizeListFields()
 }
 
 // decodeUnicodeEscape decodes Unicode escape sequences like \u6771\u4EAC
@@ -1158,7 +1686,7 @@ func (s *Scraper) extractFromContextData(contextData map[string]interface{}, pro
 	// Extract area (MonopolyArea is in units of 0.01 sqm, need to divide by 100)
 	if monopolyArea, ok := contextData["MonopolyArea"].(int); ok && monopolyArea > 0 {
 		area := float64(monopolyArea) / 100.0
-		property.Area = &area
+		setArea(property, area, models.AreaUnitSquareMeters)
 		log.Printf("[extractFromContextData] id=%s Area: %.2f sqm", propertyID, area)
 	}
 
@@ -1265,6 +1793,17 @@ func (s *Scraper) extractFromContextData(contextData map[string]interface{}, pro
 		log.Printf("[extractFromContextData] id=%s RoomLayoutImageURL: %s", propertyID, property.RoomLayoutImageURL)
 	}
 
+	// Extract coordinates (dropped when outside Japan: swapped or placeholder values)
+	lat, latOK := contextData["Latitude"].(float64)
+	lng, lngOK := contextData["Longitude"].(float64)
+	if latOK && lngOK {
+		if property.SetCoordinates(lat, lng) {
+			log.Printf("[extractFromContextData] id=%s Coordinates: %f,%f", propertyID, lat, lng)
+		} else {
+			log.Printf("[extractFromContextData] id=%s Ignoring coordinates outside Japan: %f,%f", propertyID, lat, lng)
+		}
+	}
+
 	// Final summary
 	rentVal := "NULL"
 	if property.Rent != nil {
@@ -1278,124 +1817,108 @@ func (s *Scraper) extractFromContextData(contextData map[string]interface{}, pro
 		propertyID, property.Title, rentVal, property.Station, ageVal, property.Address)
 }
 
-// extractRent extracts rent amount from text
+var (
+	// rentManYenPattern / rentYenPattern find a rent amount within a short distance after the
+	// word 賃料, so 礼金・敷金 amounts or "おすすめ 5.5万円〜" banners elsewhere on the page are not taken
+	rentManYenPattern = regexp.MustCompile(`賃料[^0-9万円]{0,20}?([0-9]+(?:\.[0-9]+)?)万円`)
+	rentYenPattern    = regexp.MustCompile(`賃料[^0-9万円]{0,20}?([0-9][0-9,]*)円`)
+)
+
+// validRent reports whether a rent is plausible (10,000 - 10,000,000 yen)
+func validRent(rent int) bool {
+	return rent >= 10000 && rent <= 10000000
+}
+
+// extractRentFromTable reads the 賃料 row (th/td or dt/dd) of the detail table. Text after the
+// amount ("8.5万円（管理費 5,000円）", "8.5万円/5000円") is ignored. Returns 0 when there is no row.
+func extractRentFromTable(doc *goquery.Document) int {
+	rent := 0
+	doc.Find("th, dt").EachWithBreak(func(_ int, header *goquery.Selection) bool {
+		key := strings.Join(strings.Fields(header.Text()), "")
+		if !strings.HasPrefix(key, "賃料") {
+			return true
+		}
+		value := strings.Join(strings.Fields(header.NextFiltered("td, dd").Text()), "")
+		if i := strings.IndexAny(value, "（(/／"); i > 0 {
+			value = value[:i]
+		}
+		if amount := models.ParseYenAmount(value); amount != nil && validRent(*amount) {
+			rent = *amount
+			return false
+		}
+		return true
+	})
+	return rent
+}
+
+// extractRent extracts the rent from page text: the first "8.5万円" or "85,000円" that follows
+// the word 賃料 (used when the page has neither the structured JSON nor a 賃料 row)
 func extractRent(text string) int {
-	// Pattern: "8.5万円" or "85000円"
-	re := regexp.MustCompile(`([0-9]+\.?[0-9]*)万円`)
-	matches := re.FindStringSubmatch(text)
-	if len(matches) > 1 {
+	if matches := rentManYenPattern.FindStringSubmatch(text); len(matches) > 1 {
 		if val, err := strconv.ParseFloat(matches[1], 64); err == nil {
-			rent := int(val * 10000)
-			// Validate: rent should be reasonable (10,000 - 10,000,000 yen)
-			if rent >= 10000 && rent <= 10000000 {
+			if rent := int(val*10000 + 0.5); validRent(rent) {
 				return rent
 			}
 		}
 	}
 
-	// Pattern: direct yen amount
-	re = regexp.MustCompile(`賃料[：:]\s*([0-9,]+)円`)
-	matches = re.FindStringSubmatch(text)
-	if len(matches) > 1 {
-		cleaned := strings.ReplaceAll(matches[1], ",", "")
-		if val, err := strconv.Atoi(cleaned); err == nil {
-			// Validate: rent should be reasonable (10,000 - 10,000,000 yen)
-			if val >= 10000 && val <= 10000000 {
-				return val
-			}
+	if matches := rentYenPattern.FindStringSubmatch(text); len(matches) > 1 {
+		if val, err := strconv.Atoi(strings.ReplaceAll(matches[1], ",", "")); err == nil && validRent(val) {
+			return val
 		}
 	}
 
 	return 0
 }
 
-// extractFloorPlan extracts floor plan (1K, 1DK, etc.)
+// floorPlanWindow is how far after the word 間取り (in runes) extractFloorPlan looks for the plan
+const floorPlanWindow = 20
+
+// extractFloorPlanFromTable reads the 間取り row (th/td or dt/dd) of the detail table.
+// 間取り詳細 / 間取り図 rows are not the plan itself and are skipped.
+func extractFloorPlanFromTable(doc *goquery.Document) string {
+	plan := ""
+	doc.Find("th, dt").EachWithBreak(func(_ int, header *goquery.Selection) bool {
+		if strings.Join(strings.Fields(header.Text()), "") != "間取り" {
+			return true
+		}
+		plan = models.ParseFloorPlan(header.NextFiltered("td, dd").Text())
+		return plan == ""
+	})
+	return plan
+}
+
+// extractFloorPlan extracts the floor plan (1K, 2SLDK, ワンルーム → 1R …) that follows the word
+// 間取り in page text; plans elsewhere on the page (ads, other listings) are ignored
 func extractFloorPlan(text string) string {
-	// Pattern: "1K", "1DK", "1LDK", "2LDK", etc.
-	re := regexp.MustCompile(`([0-9]?[SLDK]+)\b`)
-	matches := re.FindStringSubmatch(text)
-	if len(matches) > 1 {
-		return matches[1]
+	for rest := text; ; {
+		i := strings.Index(rest, "間取り")
+		if i < 0 {
+			return ""
+		}
+		rest = rest[i+len("間取り"):]
+		window := []rune(rest)
+		if len(window) > floorPlanWindow {
+			window = window[:floorPlanWindow]
+		}
+		if plan := models.ParseFloorPlan(string(window)); plan != "" {
+			return plan
+		}
 	}
-	return ""
 }
 
-// normalizeFloorPlan normalizes Japanese floor plan names to standard codes
+// normalizeFloorPlan normalizes a 間取り value (JSON RoomLayoutBreakdown, SUUMO 間取り cell) to one
+// of models.FloorPlans. Values without a valid plan (a bare "K", "LDK") are dropped.
 func normalizeFloorPlan(floorPlan string) string {
+	floorPlan = strings.TrimSpace(floorPlan)
 	if floorPlan == "" {
 		return ""
 	}
-
-	// First check if it's already in standard format (1R, 1K, 1DK, etc.)
-	if matched, _ := regexp.MatchString(`^[0-9]?[SLDK]+$`, floorPlan); matched {
-		return floorPlan
-	}
-
-	// Normalize Japanese variations to standard codes
-	floorPlan = strings.TrimSpace(floorPlan)
-
-	// Extract base floor plan type
-	if strings.Contains(floorPlan, "ワンルーム") || strings.Contains(floorPlan, "1R") {
-		return "1R"
-	}
-	if strings.Contains(floorPlan, "1K") {
-		return "1K"
-	}
-	if strings.Contains(floorPlan, "1DK") {
-		return "1DK"
-	}
-	if strings.Contains(floorPlan, "1LDK") {
-		return "1LDK"
-	}
-	if strings.Contains(floorPlan, "1SDK") {
-		return "1SDK"
-	}
-	if strings.Contains(floorPlan, "1SLDK") {
-		return "1SLDK"
-	}
-	if strings.Contains(floorPlan, "2K") {
-		return "2K"
-	}
-	if strings.Contains(floorPlan, "2DK") {
-		return "2DK"
-	}
-	if strings.Contains(floorPlan, "2LDK") {
-		return "2LDK"
-	}
-	if strings.Contains(floorPlan, "2SDK") {
-		return "2SDK"
-	}
-	if strings.Contains(floorPlan, "2SLDK") {
-		return "2SLDK"
-	}
-	if strings.Contains(floorPlan, "3K") {
-		return "3K"
-	}
-	if strings.Contains(floorPlan, "3DK") {
-		return "3DK"
+	if plan := models.ParseFloorPlan(floorPlan); plan != "" {
+		return plan
 	}
-	if strings.Contains(floorPlan, "3LDK") {
-		return "3LDK"
-	}
-	if strings.Contains(floorPlan, "3SDK") {
-		return "3SDK"
-	}
-	if strings.Contains(floorPlan, "3SLDK") {
-		return "3SLDK"
-	}
-	if strings.Contains(floorPlan, "4K") {
-		return "4K"
-	}
-	if strings.Contains(floorPlan, "4DK") {
-		return "4DK"
-	}
-	if strings.Contains(floorPlan, "4LDK") {
-		return "4LDK"
-	}
-
-	// If no match, return as-is (but log it)
 	log.Printf("[normalizeFloorPlan] Unknown floor plan format: %s", floorPlan)
-	return floorPlan
+	return ""
 }
 
 // cleanTitle removes unwanted text from property titles
@@ -1443,48 +1966,48 @@ func normalizeBuildingType(buildingType, structure string) string {
 	if structure != "" {
 		if strings.Contains(structure, "鉄筋コンクリート") || strings.Contains(structure, "RC") ||
 		   strings.Contains(structure, "鉄骨鉄筋コンクリート") || strings.Contains(structure, "SRC") {
-			return "mansion"
+			return models.BuildingTypeMansion
 		}
 		if strings.Contains(structure, "木造") {
-			return "apartment"
+			return models.BuildingTypeApartment
 		}
 		// 軽量鉄骨 (light steel) is typically used for apartments
 		if strings.Contains(structure, "軽量鉄骨") {
-			return "apartment"
+			return models.BuildingTypeApartment
 		}
 		// 鉄骨造 (steel frame) without RC is typically apartment/light construction
 		if strings.Contains(structure, "鉄骨造") && !strings.Contains(structure, "鉄筋") {
-			return "apartment"
+			return models.BuildingTypeApartment
 		}
 	}
 
 	// Fallback: Use building type label if structure doesn't give clear answer
 	if buildingType != "" {
 		if strings.Contains(buildingType, "マンション") {
-			return "mansion"
+			return models.BuildingTypeMansion
 		}
 		if strings.Contains(buildingType, "アパート") {
-			return "apartment"
+			return models.BuildingTypeApartment
 		}
 		if strings.Contains(buildingType, "一戸建") || strings.Contains(buildingType, "戸建") {
-			return "house"
+			return models.BuildingTypeHouse
 		}
 		if strings.Contains(buildingType, "テラスハウス") {
-			return "terrace_house"
+			return models.BuildingTypeTerraceHouse
 		}
 		if strings.Contains(buildingType, "タウンハウス") {
-			return "town_house"
+			return models.BuildingTypeTownHouse
 		}
 		if strings.Contains(buildingType, "シェアハウス") {
-			return "share_house"
+			return models.BuildingTypeShareHouse
 		}
 	}
 
-	// If already in English, return lowercase
-	if buildingType != "" {
+	// Already a canonical English key ("Mansion" → "mansion"); anything else is kept verbatim
+	if models.IsBuildingType(strings.ToLower(buildingType)) {
 		return strings.ToLower(buildingType)
 	}
-	return ""
+	return buildingType
 }
 
 // normalizeFacilities converts Yahoo facility codes to English keys for filtering
@@ -1613,6 +2136,11 @@ func normalizeFacilitiesFromLabels(labels []string) []string {
 		"ペット可":   "pet_friendly",
 		"ペット相談":  "pet_negotiable",
 
+		// Entry conditions (also read from the 条件等 row by models.ParseLeaseTerms)
+		"事務所利用可":  "office_use_allowed",
+		"ルームシェア可": "room_share_allowed",
+		"楽器可":     "instruments_allowed",
+
 		// Payment
 		"カード決済可": "card_payment",
 
@@ -1759,62 +2287,21 @@ func extractCategoryFacilityLabels(doc *goquery.Document) []string {
 	return labels
 }
 
-// extractArea extracts area in square meters
-func extractArea(text string) float64 {
-	// Pattern: "25.5㎡" or "25.5m²"
-	re := regexp.MustCompile(`([0-9]+\.?[0-9]*)[㎡m²]`)
-	matches := re.FindStringSubmatch(text)
-	if len(matches) > 1 {
-		if val, err := strconv.ParseFloat(matches[1], 64); err == nil {
-			// Validate: area should be reasonable (5-500 sqm for residential)
-			if val >= 5.0 && val <= 500.0 {
-				return val
-			}
-		}
-	}
-	return 0
-}
-
-// extractWalkTime extracts walking time to station in minutes
-func extractWalkTime(text string) int {
-	// Pattern: "徒歩5分" or "歩5分"
-	re := regexp.MustCompile(`[徒歩]+([0-9]+)分`)
-	matches := re.FindStringSubmatch(text)
-	if len(matches) > 1 {
-		if val, err := strconv.Atoi(matches[1]); err == nil {
-			// Validate: walk time should be reasonable (1-60 minutes)
-			if val >= 1 && val <= 60 {
-				return val
-			}
-		}
-	}
-	return 0
-}
-
-// extractStation extracts station name
-func extractStation(text string) string {
-	// Pattern: "XX駅" before "徒歩"
-	re := regexp.MustCompile(`([^\s]+駅)\s*[徒歩]`)
-	matches := re.FindStringSubmatch(text)
-	if len(matches) > 1 {
-		return matches[1]
-	}
-	return ""
-}
-
 // StationAccess represents a single station access point
 type StationAccess struct {
 	StationName string
 	LineName    string
 	WalkMinutes int
 	SortOrder   int
+	ViaBus      bool // "バス10分 ○○停 徒歩2分": reached by bus, so WalkMinutes stays 0
+	BusMinutes  int  // the bus ride ("バス10分"), 0 when walked or unknown; not stored in property_stations
 }
 
-// extractStations extracts all station access points from the document
-// Returns array of StationAccess with sort_order 1, 2, 3...
+// extractStations extracts all station access points from the document: the summary access
+// list, else the 交通 row of the detail table. Ordered nearest first with sort_order 1, 2, 3...
+// (see orderStationsByDistance); nil when the page has no station block at all.
 func extractStations(doc *goquery.Document) []StationAccess {
 	var stations []StationAccess
-	sortOrder := 1
 
 	// Find all station access entries
 	doc.Find("li.DetailSummaryTable__access").Each(func(_ int, s *goquery.Selection) {
@@ -1848,19 +2335,9 @@ func extractStations(doc *goquery.Document) []StationAccess {
 		afterStation = strings.TrimSpace(afterStation)
 
 		lineName := ""
-		walkMinutes := 0
-
-		// Try to extract walk time: "徒歩(\d+)分"
-		walkRe := regexp.MustCompile(`徒歩\s*([0-9]+)\s*分`)
-		walkMatches := walkRe.FindStringSubmatch(afterStation)
-		if len(walkMatches) > 1 {
-			if val, err := strconv.Atoi(walkMatches[1]); err == nil {
-				// Validate: walk time should be reasonable (1-60 minutes)
-				if val >= 1 && val <= 120 {
-					walkMinutes = val
-				}
-			}
-		}
+
+		// Bus segments: the 徒歩 that follows is from the bus stop, not from the station
+		walkMinutes, busMinutes, viaBus := parseAccessMinutes(afterStation)
 
 		// Extract line name: everything before "徒歩" or "バス" etc.
 		// Split by common transportation keywords
@@ -1878,18 +2355,21 @@ func extractStations(doc *goquery.Document) []StationAccess {
 
 		// If walkMinutes is 0, it means this is non-walk access (bus, etc.)
 		// Still save it with walk_minutes = 0 and preserve line_name/station_name
+		// (the property_stations row has no bus column; walk_minutes = 0 is the flag)
 
 		stations = append(stations, StationAccess{
-			StationName: stationName,
-			LineName:    lineName,
+			StationName: models.NormalizeStationName(stationName),
+			LineName:    models.NormalizeLineName(lineName),
 			WalkMinutes: walkMinutes,
-			SortOrder:   sortOrder,
+			BusMinutes:  busMinutes,
+			ViaBus:      viaBus,
 		})
-
-		sortOrder++
 	})
 
-	return stations
+	if len(stations) == 0 {
+		stations = extractStationRows(doc)
+	}
+	return orderStationsByDistance(stations)
 }
 
 // convertStationsToModels converts StationAccess to PropertyStation models
@@ -1908,8 +2388,9 @@ func convertStationsToModels(propertyID string, stations []StationAccess) []mode
 	return result
 }
 
-// applyStationCompatibility copies the primary station (sort_order=1) to legacy fields
-// for backward compatibility with existing code
+// applyStationCompatibility copies the primary (nearest) station, sort_order=1, to the legacy
+// Station / WalkTime fields so both always describe the same station; when that station is
+// only reached by bus, the ride goes to BusMinutes instead of a walk time
 func applyStationCompatibility(prop *models.Property, stations []StationAccess) {
 	if len(stations) == 0 {
 		return
@@ -1921,66 +2402,68 @@ func applyStationCompatibility(prop *models.Property, stations []StationAccess)
 		prop.Station = s0.StationName
 	}
 
-	// Only update walk_time if it's a valid walk time (> 0)
+	// Reached by bus only: no walk time rather than another station's (or the JSON's) minutes
 	if s0.WalkMinutes > 0 {
-		prop.WalkTime = &s0.WalkMinutes
+		walk := s0.WalkMinutes
+		prop.WalkTime = &walk
+	} else {
+		prop.WalkTime = nil
+	}
+	prop.BusMinutes = nil
+	if s0.ViaBus && s0.BusMinutes > 0 {
+		bus := s0.BusMinutes
+		prop.BusMinutes = &bus
 	}
 }
 
 // GetLastStations returns the stations from the last scrape operation
 func (s *Scraper) GetLastStations() []StationAccess {
+	s.lastMu.Lock()
+	defer s.lastMu.Unlock()
 	return s.lastStations
 }
 
 // GetLastStationsAsModels returns the stations from the last scrape as PropertyStation models
 func (s *Scraper) GetLastStationsAsModels(propertyID string) []models.PropertyStation {
-	return convertStationsToModels(propertyID, s.lastStations)
+	return convertStationsToModels(propertyID, s.GetLastStations())
 }
 
 // GetLastImages returns the image URLs from the last scrape
 func (s *Scraper) GetLastImages() []string {
+	s.lastMu.Lock()
+	defer s.lastMu.Unlock()
 	return s.lastImages
 }
 
 // GetLastImagesAsModels returns the images from the last scrape as PropertyImage models
+// (deduplicated, capped at models.MaxPropertyImages, sort_order in gallery order)
 func (s *Scraper) GetLastImagesAsModels(propertyID string) []models.PropertyImage {
-	images := make([]models.PropertyImage, 0, len(s.lastImages))
-	for i, imageURL := range s.lastImages {
-		images = append(images, models.PropertyImage{
-			PropertyID: propertyID,
-			ImageURL:   imageURL,
-			SortOrder:  i,
-		})
-	}
-	return images
+	return models.NewPropertyImages(propertyID, s.GetLastImages())
 }
 
-// extractAddress extracts address from the document
+// addressRowKeys are the detail table headers whose cell holds the property's address
+var addressRowKeys = map[string]bool{"所在地": true, "住所": true}
+
+// extractAddress reads the 所在地 / 住所 row of the detail table, else the first address
+// (都道府県 + 市区郡町村) in the page text. Only the table row and one regex pass over the
+// text are looked at, not every element of the page.
 func extractAddress(doc *goquery.Document) string {
-	// Try to find address in common patterns
 	address := ""
-
-	// Look for address in text
-	doc.Find("*").Each(func(i int, s *goquery.Selection) {
-		text := s.Text()
-		if strings.Contains(text, "東京都") || strings.Contains(text, "大阪府") ||
-		   strings.Contains(text, "神奈川県") || strings.Contains(text, "千葉県") ||
-		   strings.Contains(text, "埼玉県") {
-			// Extract just the address part
-			re := regexp.MustCompile(`(東京都|大阪府|神奈川県|千葉県|埼玉県)[^\n]+`)
-			matches := re.FindStringSubmatch(text)
-			if len(matches) > 0 && len(address) == 0 {
-				address = strings.TrimSpace(matches[0])
-				// Safely truncate at rune boundary
-				runes := []rune(address)
-				if len(runes) > 50 {
-					address = string(runes[:50])
-				}
-			}
+	doc.Find("th, dt").EachWithBreak(func(_ int, header *goquery.Selection) bool {
+		if !addressRowKeys[strings.Join(strings.Fields(header.Text()), "")] {
+			return true
 		}
+		lines := cellLines(header.NextFiltered("td, dd"))
+		if len(lines) > 0 {
+			// Later lines are map links / notes ("地図を見る")
+			address = truncateRunes(lines[0], 100)
+		}
+		return address == ""
 	})
-
-	return address
+	if address != "" {
+		return address
+	}
+	return models.FindAddress(doc.Text())
 }
 
 // extractBuildingAge extracts building age in years
@@ -1999,20 +2482,87 @@ func extractBuildingAge(text string) int {
 	return 0
 }
 
-// extractFloor extracts floor number
+// extractFloor extracts the unit's floor number
+// "〜階建" (building floors) is skipped so "地上15階建て/7階部分" yields 7, not 15
 func extractFloor(text string) int {
-	// Pattern: "2階" or "2F"
-	re := regexp.MustCompile(`([0-9]+)[階F]`)
-	matches := re.FindStringSubmatch(text)
-	if len(matches) > 1 {
-		if val, err := strconv.Atoi(matches[1]); err == nil {
-			// Validate: floor should be reasonable (0-100)
-			if val >= 0 && val <= 100 {
-				return val
+	if info := models.ParseFloorLabel(text); info.UnitFloor != nil {
+		return *info.UnitFloor
+	}
+	return 0
+}
+
+// extractFloorLabel builds a floor label from detail table rows such as
+// 所在階 / 階建 / 階数 (e.g. "地上15階建て/7階部分")
+func extractFloorLabel(doc *goquery.Document) string {
+	var parts []string
+	doc.Find("th, dt").Each(func(_ int, header *goquery.Selection) {
+		key := strings.TrimSpace(header.Text())
+		if !strings.Contains(key, "階") || len(key) > 30 {
+			return
+		}
+		value := strings.TrimSpace(header.NextFiltered("td, dd").Text())
+		if value != "" && strings.Contains(value, "階") && len(value) < 100 {
+			parts = append(parts, strings.Join(strings.Fields(value), ""))
+		}
+	})
+	return strings.Join(parts, "/")
+}
+
+// extractLeaseRows returns the values of detail table rows describing the contract
+// (契約期間 / 契約形態 / 条件等 / 入居条件), e.g. "定期借家2年" or "事務所利用可"
+func extractLeaseRows(doc *goquery.Document) []string {
+	var values []string
+	doc.Find("th, dt").Each(func(_ int, header *goquery.Selection) {
+		key := strings.TrimSpace(header.Text())
+		if len(key) > 30 || !(strings.Contains(key, "契約") || strings.Contains(key, "条件")) {
+			return
+		}
+		value := strings.TrimSpace(header.NextFiltered("td, dd").Text())
+		if value != "" && len(value) < 500 {
+			values = append(values, strings.Join(strings.Fields(value), " "))
+		}
+	})
+	return values
+}
+
+// applyBuildingRows fills Structure and BuildingType from the detail table rows
+// (建物種別 / 種別 and 構造 / 建物構造) when __SERVER_SIDE_CONTEXT__ did not provide them.
+// Unrecognized 種別 values are stored verbatim (see normalizeBuildingType).
+func applyBuildingRows(doc *goquery.Document, property *models.Property) {
+	var kind string
+	doc.Find("th, dt").Each(func(_ int, header *goquery.Selection) {
+		key := strings.Join(strings.Fields(header.Text()), "")
+		if key == "" || len(key) > 30 {
+			return
+		}
+		value := strings.Join(strings.Fields(header.NextFiltered("td, dd").Text()), " ")
+		if value == "" {
+			return
+		}
+		switch {
+		case strings.Contains(key, "構造"):
+			if property.Structure == "" {
+				property.Structure = truncateRunes(value, 50)
+			}
+		case key == "種別" || strings.HasSuffix(key, "種別") || strings.HasSuffix(key, "種目"):
+			if kind == "" {
+				kind = value
 			}
 		}
+	})
+
+	if property.BuildingType == "" && (kind != "" || property.Structure != "") {
+		property.BuildingType = truncateRunes(normalizeBuildingType(kind, property.Structure), 50)
 	}
-	return 0
+}
+
+// truncateRunes cuts s to at most n runes (column limits are in characters)
+func truncateRunes(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) > n {
+		return string(runes[:n])
+	}
+	return s
 }
 
 // normalizeURL normalizes a URL by removing query strings and trailing slashes
@@ -2101,7 +2651,7 @@ func extractAllImageURLsFromJSON(html string) []string {
 			seenSignatures[signature] = true
 
 			// Limit to ~20 unique images (typical for Yahoo Real Estate properties)
-			if len(imageURLs) >= 20 {
+			if len(imageURLs) >= models.MaxPropertyImages {
 				break
 			}
 		}
@@ -2146,37 +2696,16 @@ func extractAllImageURLsFromJSON(html string) []string {
 	return imageURLs
 }
 
-// verifyImageURL checks if an image URL is accessible (returns HTTP 200)
-func (s *Scraper) verifyImageURL(imageURL string) bool {
-	// Create HEAD request to check without downloading the image
-	req, err := http.NewRequest("HEAD", imageURL, nil)
-	if err != nil {
-		log.Printf("[verifyImageURL] Error creating request for %s: %v", imageURL, err)
-		return false
-	}
-
-	req.Header.Set("User-Agent", "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36")
-
-	// Use a shorter timeout for image verification
-	client := &http.Client{
-		Timeout: 5 * time.Second,
-	}
-
-	resp, err := client.Do(req)
+// yahooPropertyIDOrHash is the source_property_id stored for detailURL: its Yahoo property ID,
+// or the URL's MD5 for non-standard URLs
+func yahooPropertyIDOrHash(detailURL string) string {
+	id, err := extractYahooPropertyID(detailURL)
 	if err != nil {
-		log.Printf("[verifyImageURL] Error verifying image %s: %v", imageURL, err)
-		return false
-	}
-	defer resp.Body.Close()
-
-	// Accept 200 OK
-	if resp.StatusCode != 200 {
-		log.Printf("[verifyImageURL] Image verification failed for %s: status code %d", imageURL, resp.StatusCode)
-		return false
+		log.Printf("[ScrapeProperty] Warning: Could not extract Yahoo property ID from %s: %v", detailURL, err)
+		hash := md5.Sum([]byte(detailURL))
+		return hex.EncodeToString(hash[:])
 	}
-
-	log.Printf("[verifyImageURL] Image verified successfully: %s", imageURL)
-	return true
+	return id
 }
 
 // extractYahooPropertyID extracts Yahoo property ID from URL
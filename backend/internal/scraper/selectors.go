@@ -0,0 +1,135 @@
+package scraper
+
+import (
+	"log"
+	"real-estate-portal/internal/models"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/andybalholm/cascadia"
+)
+
+// Field names of ScraperConfig.Selectors (scraper.selectors in the config)
+const (
+	SelectorTitle        = "title"
+	SelectorRent         = "rent"
+	SelectorFloorPlan    = "floor_plan"
+	SelectorArea         = "area"
+	SelectorAddress      = "address"
+	SelectorStationBlock = "station_block"
+	SelectorImage        = "image"
+)
+
+// compileSelectors compiles the configured field selectors. The config loader already rejects
+// invalid ones; any that still fail are logged and left to the built-in extraction.
+func compileSelectors(selectors map[string]string) map[string]cascadia.Selector {
+	if len(selectors) == 0 {
+		return nil
+	}
+	compiled := make(map[string]cascadia.Selector, len(selectors))
+	for field, selector := range selectors {
+		sel, err := cascadia.Compile(selector)
+		if err != nil {
+			log.Printf("Scraper: ignoring invalid %s selector %q: %v", field, selector, err)
+			continue
+		}
+		compiled[field] = sel
+	}
+	return compiled
+}
+
+// selectorMatch returns the elements the field's configured selector finds (nil when no
+// selector is configured or it matches nothing, so the caller uses its built-in extraction)
+func (s *Scraper) selectorMatch(doc *goquery.Document, field string) *goquery.Selection {
+	sel, ok := s.selectors[field]
+	if !ok {
+		return nil
+	}
+	match := doc.FindMatcher(sel)
+	if match.Length() == 0 {
+		log.Printf("[selectors] %s selector matched nothing, using built-in extraction", field)
+		return nil
+	}
+	return match
+}
+
+// selectorText is the whitespace-collapsed text of the field selector's first match ("" if none)
+func (s *Scraper) selectorText(doc *goquery.Document, field string) string {
+	match := s.selectorMatch(doc, field)
+	if match == nil {
+		return ""
+	}
+	return strings.Join(strings.Fields(match.First().Text()), " ")
+}
+
+// applyFieldSelectors overrides rent / floor_plan / area / address with what the configured
+// selectors find. A selector whose text does not parse leaves the built-in value in place.
+func (s *Scraper) applyFieldSelectors(doc *goquery.Document, property *models.Property) {
+	if len(s.selectors) == 0 {
+		return
+	}
+
+	if text := s.selectorText(doc, SelectorRent); text != "" {
+		value := strings.ReplaceAll(text, " ", "")
+		// "9.8万円（管理費 3,000円）": only the amount before the fee note
+		if i := strings.IndexAny(value, "（(/／"); i > 0 {
+			value = value[:i]
+		}
+		if amount := models.ParseYenAmount(value); amount != nil && validRent(*amount) {
+			property.Rent = amount
+		} else {
+			log.Printf("[selectors] id=%s rent selector text %q is not a rent, keeping built-in value", property.SourcePropertyID, text)
+		}
+	}
+
+	if text := s.selectorText(doc, SelectorFloorPlan); text != "" {
+		if plan := models.ParseFloorPlan(text); plan != "" {
+			property.FloorPlan = plan
+		}
+	}
+
+	if text := s.selectorText(doc, SelectorArea); text != "" {
+		if area, unit := models.ParseArea(text); unit != "" {
+			setArea(property, area, unit)
+		}
+	}
+
+	if match := s.selectorMatch(doc, SelectorAddress); match != nil {
+		if lines := cellLines(match.First()); len(lines) > 0 {
+			property.Address = truncateRunes(lines[0], 100)
+		}
+	}
+}
+
+// selectorStations parses the station_block selector's elements like 交通 rows
+// ("JR山手線/新宿駅 徒歩7分" per line); nil falls back to extractStations
+func (s *Scraper) selectorStations(doc *goquery.Document) []StationAccess {
+	match := s.selectorMatch(doc, SelectorStationBlock)
+	if match == nil {
+		return nil
+	}
+	var stations []StationAccess
+	match.Each(func(_ int, block *goquery.Selection) {
+		stations = append(stations, parseStationLines(cellLines(block))...)
+	})
+	if len(stations) == 0 {
+		log.Printf("[selectors] station_block selector found no station lines, using built-in extraction")
+		return nil
+	}
+	return orderStationsByDistance(stations)
+}
+
+// selectorImage is the image URL of the image selector's first match (data-src, src, content
+// or href, resolved against pageURL; "" if none)
+func (s *Scraper) selectorImage(doc *goquery.Document, pageURL string) string {
+	match := s.selectorMatch(doc, SelectorImage)
+	if match == nil {
+		return ""
+	}
+	for _, attr := range []string{"data-src", "src", "content", "href"} {
+		if src, ok := match.First().Attr(attr); ok && strings.TrimSpace(src) != "" && !strings.HasPrefix(src, "data:") {
+			return resolveImageURL(pageURL, strings.TrimSpace(src))
+		}
+	}
+	return ""
+}
@@ -0,0 +1,294 @@
+package scraper
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math/rand"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"os"
+	"path/filepath"
+	"real-estate-portal/internal/ratelimit"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/chromedp"
+)
+
+// yahooWarmupPath is the landing page visited to collect cookies before detail scrapes
+const yahooWarmupPath = "/rent/"
+
+// sessionWarmupInterval is how long a host's warm-up is trusted before it is repeated
+const sessionWarmupInterval = 30 * time.Minute
+
+// savedCookie is one cookie in the cookie file
+type savedCookie struct {
+	URL      string    `json:"url"` // request URL that set it (scheme://host/path)
+	Name     string    `json:"name"`
+	Value    string    `json:"value"`
+	Domain   string    `json:"domain,omitempty"`
+	Path     string    `json:"path,omitempty"`
+	Expires  time.Time `json:"expires,omitempty"` // zero = session cookie
+	Secure   bool      `json:"secure,omitempty"`
+	HttpOnly bool      `json:"http_only,omitempty"`
+}
+
+// sessionJar is an http.CookieJar that also remembers what it was given, so the cookies can be
+// written to a file and restored after a restart (cookiejar.Jar cannot list its contents)
+type sessionJar struct {
+	inner *cookiejar.Jar
+	path  string // "" = memory only
+
+	mu      sync.Mutex
+	cookies map[string]savedCookie // domain|path|name → cookie
+}
+
+// sessionJars are the process-wide jars keyed by cookie file ("" = the in-memory jar), so every
+// scraper carries the cookies the others collected, like one browser profile
+var sessionJars = struct {
+	mu     sync.Mutex
+	byPath map[string]*sessionJar
+}{byPath: make(map[string]*sessionJar)}
+
+// sharedSessionJar returns the jar for path, loading the file on first use
+func sharedSessionJar(path string) *sessionJar {
+	sessionJars.mu.Lock()
+	defer sessionJars.mu.Unlock()
+
+	if jar, ok := sessionJars.byPath[path]; ok {
+		return jar
+	}
+	inner, _ := cookiejar.New(nil) // never fails without options
+	jar := &sessionJar{inner: inner, path: path, cookies: make(map[string]savedCookie)}
+	if path != "" {
+		jar.load()
+	}
+	sessionJars.byPath[path] = jar
+	return jar
+}
+
+// SetCookies implements http.CookieJar and rewrites the cookie file when a cookie changed
+func (j *sessionJar) SetCookies(u *url.URL, cookies []*http.Cookie) {
+	j.inner.SetCookies(u, cookies)
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	now := time.Now()
+	changed := false
+	for _, c := range cookies {
+		domain := strings.TrimPrefix(strings.ToLower(c.Domain), ".")
+		if domain == "" {
+			domain = u.Hostname()
+		}
+		key := domain + "|" + c.Path + "|" + c.Name
+		if c.MaxAge < 0 || (!c.Expires.IsZero() && c.Expires.Before(now)) {
+			if _, ok := j.cookies[key]; ok {
+				delete(j.cookies, key)
+				changed = true
+			}
+			continue
+		}
+		saved := savedCookie{
+			URL:      u.Scheme + "://" + u.Host + u.Path,
+			Name:     c.Name,
+			Value:    c.Value,
+			Domain:   c.Domain,
+			Path:     c.Path,
+			Expires:  c.Expires,
+			Secure:   c.Secure,
+			HttpOnly: c.HttpOnly,
+		}
+		if c.MaxAge > 0 {
+			saved.Expires = now.Add(time.Duration(c.MaxAge) * time.Second)
+		}
+		if j.cookies[key] != saved {
+			j.cookies[key] = saved
+			changed = true
+		}
+	}
+	if changed && j.path != "" {
+		j.save()
+	}
+}
+
+// Cookies implements http.CookieJar
+func (j *sessionJar) Cookies(u *url.URL) []*http.Cookie {
+	return j.inner.Cookies(u)
+}
+
+// load restores the unexpired cookies of the cookie file (a missing file is an empty jar)
+func (j *sessionJar) load() {
+	data, err := os.ReadFile(j.path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Printf("[Session] Warning: failed to read cookie file %s: %v", j.path, err)
+		}
+		return
+	}
+	var saved []savedCookie
+	if err := json.Unmarshal(data, &saved); err != nil {
+		log.Printf("[Session] Warning: ignoring unreadable cookie file %s: %v", j.path, err)
+		return
+	}
+
+	now := time.Now()
+	restored := 0
+	for _, c := range saved {
+		u, err := url.Parse(c.URL)
+		if err != nil || u.Host == "" || (!c.Expires.IsZero() && c.Expires.Before(now)) {
+			continue
+		}
+		j.inner.SetCookies(u, []*http.Cookie{{
+			Name:     c.Name,
+			Value:    c.Value,
+			Domain:   c.Domain,
+			Path:     c.Path,
+			Expires:  c.Expires,
+			Secure:   c.Secure,
+			HttpOnly: c.HttpOnly,
+		}})
+		domain := strings.TrimPrefix(strings.ToLower(c.Domain), ".")
+		if domain == "" {
+			domain = u.Hostname()
+		}
+		j.cookies[domain+"|"+c.Path+"|"+c.Name] = c
+		restored++
+	}
+	log.Printf("[Session] Restored %d cookie(s) from %s", restored, j.path)
+}
+
+// save writes the cookies to the cookie file (via a temp file, so a crash never truncates it).
+// Called with j.mu held.
+func (j *sessionJar) save() {
+	saved := make([]savedCookie, 0, len(j.cookies))
+	for _, c := range j.cookies {
+		saved = append(saved, c)
+	}
+	data, err := json.MarshalIndent(saved, "", "  ")
+	if err == nil {
+		err = writeFileAtomic(j.path, data)
+	}
+	if err != nil {
+		log.Printf("[Session] Warning: failed to save cookie file %s: %v", j.path, err)
+	}
+}
+
+func writeFileAtomic(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// sessionWarmups are the last warm-up per host, shared like the jars
+var sessionWarmups = struct {
+	mu     sync.Mutex
+	byHost map[string]time.Time
+}{byHost: make(map[string]time.Time)}
+
+// claimWarmup reports whether host is due a warm-up and, if so, marks it done now so
+// concurrent scrapes do not all visit the landing page
+func claimWarmup(host string) bool {
+	sessionWarmups.mu.Lock()
+	defer sessionWarmups.mu.Unlock()
+	if time.Since(sessionWarmups.byHost[host]) < sessionWarmupInterval {
+		return false
+	}
+	sessionWarmups.byHost[host] = time.Now()
+	return true
+}
+
+// releaseWarmup forgets a failed warm-up so the next scrape tries again
+func releaseWarmup(host string) {
+	sessionWarmups.mu.Lock()
+	defer sessionWarmups.mu.Unlock()
+	delete(sessionWarmups.byHost, host)
+}
+
+// warmUpSession visits the Yahoo rent landing page once per session (every 30 minutes per
+// host) to collect cookies before a detail scrape, like a visitor arriving from the top page
+func (s *Scraper) warmUpSession(ctx context.Context) error {
+	if !s.sessionWarmup {
+		return nil
+	}
+	base, err := url.Parse(s.baseURL)
+	if err != nil || !claimWarmup(base.Host) {
+		return nil
+	}
+
+	log.Printf("[Session] Warming up session on %s%s", s.baseURL, yahooWarmupPath)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", s.baseURL+yahooWarmupPath, nil)
+	if err != nil {
+		releaseWarmup(base.Host)
+		return err
+	}
+
+	s.applyHeaders(req, "")
+
+	resp, err := s.do(req)
+	if err != nil {
+		releaseWarmup(base.Host)
+		log.Printf("[Session] Error visiting landing page: %v", err)
+		return err
+	}
+	resp.Body.Close()
+
+	log.Printf("[Session] Visited landing page (Status: %d), %d cookie(s) for %s", resp.StatusCode, len(s.client.Jar.Cookies(base)), base.Host)
+	if resp.StatusCode >= 400 {
+		// Not retried before the interval: repeating it per scrape would only add requests to a block
+		return fmt.Errorf("landing page returned status %d", resp.StatusCode)
+	}
+
+	if s.fixtureMode {
+		return nil
+	}
+	// Small delay after the landing page to appear more natural
+	return ratelimit.SleepContext(ctx, time.Duration(2+rand.Intn(3))*time.Second)
+}
+
+// recordCookies counts whether req will carry cookies from the jar and logs it per request
+func (s *Scraper) recordCookies(ctx context.Context, u *url.URL) {
+	n := len(s.client.Jar.Cookies(u))
+	if n > 0 {
+		s.recordStats(ctx, func(st *ScrapeStats) { st.WithCookies++ })
+	}
+	debugf("[Session] %s %s cookies=%d", u.Host, u.Path, n)
+}
+
+// setBrowserCookies copies the jar's cookies for pageURL into headless Chrome before it
+// navigates, so detail pages fetched by the browser carry the warmed-up session too (counted in
+// the stats of the call scrapeCtx belongs to)
+func (s *Scraper) setBrowserCookies(scrapeCtx context.Context, pageURL string) chromedp.Action {
+	return chromedp.ActionFunc(func(ctx context.Context) error {
+		u, err := url.Parse(pageURL)
+		if err != nil {
+			return nil
+		}
+		cookies := s.client.Jar.Cookies(u)
+		s.recordCookies(scrapeCtx, u)
+		for _, c := range cookies {
+			if err := network.SetCookie(c.Name, c.Value).WithURL(pageURL).Do(ctx); err != nil {
+				log.Printf("[Session] Warning: failed to pass cookie %s to Chrome: %v", c.Name, err)
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,257 @@
+package scraper
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"real-estate-portal/internal/models"
+	"real-estate-portal/internal/ratelimit"
+	"strings"
+	"time"
+)
+
+// PropertySource is one listing site: it turns list pages into detail URLs and detail pages
+// into properties. Sources are registered by host (see Registry) so the queue worker and the
+// API can dispatch a URL to the site it belongs to.
+type PropertySource interface {
+	// Name is the value stored in properties.source and detail_scrape_queue.source
+	Name() string
+	// Hosts are the URL hosts this source handles (e.g. "realestate.yahoo.co.jp")
+	Hosts() []string
+	// Limiter is the source's detail page budget; callers acquire it before ScrapeDetail.
+	// Each source has its own, so one site's cooldown never stalls another.
+	Limiter() *ratelimit.DetailLimiter
+	// SourcePropertyID extracts the site's listing ID from a detail URL
+	SourcePropertyID(detailURL string) (string, error)
+
+	// ScrapeList returns the detail URLs on one list page
+	ScrapeList(ctx context.Context, listURL string) ([]string, error)
+	// ScrapeDetail fetches and parses one detail page. Non-zero validators make the request
+	// conditional; ErrNotModified means the stored listing is still current. The result is
+	// non-nil even with an error, so the call's stats can still be logged.
+	ScrapeDetail(ctx context.Context, detailURL string, v Validators) (*DetailResult, error)
+}
+
+// DetailResult is one ScrapeDetail call's output: the property with its stations and gallery,
+// the redirect the page was served through (nil when none) and the call's requests and waits.
+// Each call gets its own, so concurrent scrapes on one source never read each other's.
+type DetailResult struct {
+	Property *models.Property // nil when the call failed
+	Stations []models.PropertyStation
+	Images   []models.PropertyImage
+	Redirect *PropertyRedirect
+	Stats    ScrapeStats
+}
+
+// PagedSource is a source whose list pages can be followed through their 次へ links. The
+// result also carries the result/page counts the first page reports (-1 when not parsed).
+type PagedSource interface {
+	ScrapeListPagesWithMetaContext(ctx context.Context, listURL string, maxPages int) (*ListPage, error)
+}
+
+// StatsSource is a source that reports the requests and waits of its last list call
+// (ScrapeDetail returns its own in the result)
+type StatsSource interface {
+	LastStats() ScrapeStats
+}
+
+// LightSource is a source that can refresh a listing from its meta tags and rent alone
+// (see ScrapePropertyLight); other sources get a full ScrapeDetail instead
+type LightSource interface {
+	ScrapeLight(ctx context.Context, detailURL string) (*LightResult, error)
+}
+
+// BreakerSource is a source that exposes its WAF circuit breaker (for status and reset)
+type BreakerSource interface {
+	CircuitBreaker() *CircuitBreaker
+}
+
+// sourceLimits are one site's detail budget and CheckAlive budget. Yahoo uses the
+// package-level DetailLimiter / AliveLimiter instead; list pacing and the circuit breaker
+// are shared per host (see SharedLimits).
+type sourceLimits struct {
+	detail *ratelimit.DetailLimiter
+	alive  *ratelimit.DetailLimiter
+}
+
+// Registry maps URL hosts to the PropertySource that scrapes them
+type Registry struct {
+	sources []PropertySource
+	byHost  map[string]PropertySource
+}
+
+// NewRegistry registers sources by their hosts. A host claimed twice goes to the first source.
+func NewRegistry(sources ...PropertySource) *Registry {
+	r := &Registry{byHost: make(map[string]PropertySource)}
+	for _, src := range sources {
+		r.sources = append(r.sources, src)
+		for _, host := range src.Hosts() {
+			host = strings.ToLower(host)
+			if _, taken := r.byHost[host]; !taken {
+				r.byHost[host] = src
+			}
+		}
+	}
+	return r
+}
+
+// ForURL returns the source registered for rawURL's host (host:port first, then the bare host).
+// An unregistered host is ErrUnknownSource.
+func (r *Registry) ForURL(rawURL string) (PropertySource, error) {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("%w: invalid URL %q", ErrUnknownSource, rawURL)
+	}
+	if src, ok := r.byHost[strings.ToLower(u.Host)]; ok {
+		return src, nil
+	}
+	if src, ok := r.byHost[strings.ToLower(u.Hostname())]; ok {
+		return src, nil
+	}
+	return nil, fmt.Errorf("%w: %s (supported: %s)", ErrUnknownSource, u.Hostname(), strings.Join(r.Hosts(), ", "))
+}
+
+// ByName returns the source with the given Name, or nil
+func (r *Registry) ByName(name string) PropertySource {
+	for _, src := range r.sources {
+		if src.Name() == name {
+			return src
+		}
+	}
+	return nil
+}
+
+// Sources returns the registered sources in registration order
+func (r *Registry) Sources() []PropertySource {
+	return r.sources
+}
+
+// Hosts returns every registered host
+func (r *Registry) Hosts() []string {
+	var hosts []string
+	for _, src := range r.sources {
+		hosts = append(hosts, src.Hosts()...)
+	}
+	return hosts
+}
+
+// CoolingDown returns the names of sources whose detail limiter is in a preventive cooldown
+func (r *Registry) CoolingDown() []string {
+	var names []string
+	for _, src := range r.sources {
+		if src.Limiter().CooldownRemaining() > 0 {
+			names = append(names, src.Name())
+		}
+	}
+	return names
+}
+
+// LimiterStatus returns each source's detail limiter status keyed by source name
+func (r *Registry) LimiterStatus() map[string]ratelimit.DetailLimiterStatus {
+	status := make(map[string]ratelimit.DetailLimiterStatus, len(r.sources))
+	for _, src := range r.sources {
+		status[src.Name()] = src.Limiter().Status()
+	}
+	return status
+}
+
+// BreakerStatus returns each source's circuit breaker state keyed by source name
+func (r *Registry) BreakerStatus() map[string]BreakerStatus {
+	status := make(map[string]BreakerStatus, len(r.sources))
+	for _, src := range r.sources {
+		if bs, ok := src.(BreakerSource); ok {
+			status[src.Name()] = bs.CircuitBreaker().Status()
+		}
+	}
+	return status
+}
+
+// Yahoo Real Estate as a PropertySource
+
+// Name implements PropertySource
+func (s *Scraper) Name() string { return "yahoo" }
+
+// Hosts implements PropertySource (the configured origin's host)
+func (s *Scraper) Hosts() []string {
+	u, err := url.Parse(s.baseURL)
+	if err != nil || u.Host == "" {
+		return nil
+	}
+	return []string{u.Host}
+}
+
+// Limiter implements PropertySource: the shared Yahoo DetailLimiter
+func (s *Scraper) Limiter() *ratelimit.DetailLimiter { return DetailLimiter }
+
+// SourcePropertyID implements PropertySource: the path segment after /detail/
+func (s *Scraper) SourcePropertyID(detailURL string) (string, error) {
+	parts := strings.Split(normalizeURL(detailURL), "/detail/")
+	if len(parts) != 2 {
+		return "", fmt.Errorf("could not extract property ID from %s", detailURL)
+	}
+	id := strings.TrimSuffix(strings.Split(parts[1], "?")[0], "/")
+	if id == "" {
+		return "", fmt.Errorf("could not extract property ID from %s", detailURL)
+	}
+	return id, nil
+}
+
+// ScrapeList implements PropertySource
+func (s *Scraper) ScrapeList(ctx context.Context, listURL string) ([]string, error) {
+	return s.ScrapeListPageContext(ctx, listURL)
+}
+
+// ScrapeDetail implements PropertySource. The call's stations, gallery, redirect and stats come
+// back in the result; nothing is left on s for another call to read.
+func (s *Scraper) ScrapeDetail(ctx context.Context, detailURL string, v Validators) (*DetailResult, error) {
+	ctx, done := s.startStats(ctx)
+	detail, err := s.scrapeDetailPage(ctx, detailURL, "", v)
+	done()
+	if err != nil {
+		return &DetailResult{Stats: s.statsOf(ctx)}, err
+	}
+	result := detail.result()
+	result.Stats = s.statsOf(ctx)
+	return result, nil
+}
+
+// result is the DetailResult of a parsed page (without stats)
+func (d *detailPage) result() *DetailResult {
+	return &DetailResult{
+		Property: d.property,
+		Stations: convertStationsToModels(d.property.ID, d.stations),
+		Images:   models.NewPropertyImages(d.property.ID, d.images),
+		Redirect: d.redirect,
+	}
+}
+
+// ConfigureSourceLimits replaces a source's limiters with configured values ("yahoo" or
+// "suumo"). Zero values keep the built-in defaults. Must be called at startup before scraping begins.
+func ConfigureSourceLimits(name string, baseDelay, jitter time.Duration, detailPerHour int) {
+	switch name {
+	case "yahoo":
+		if baseDelay > 0 {
+			configureListPacing(name, baseDelay, jitter)
+		}
+		if detailPerHour > 0 {
+			DetailLimiter = ratelimit.NewDetailLimiter(detailPerHour)
+		}
+	case suumoSourceName:
+		if baseDelay > 0 {
+			configureListPacing(name, baseDelay, jitter)
+		}
+		if detailPerHour > 0 {
+			suumoLimits.detail = ratelimit.NewSourceDetailLimiter(suumoSourceName, detailPerHour)
+		}
+	default:
+		log.Printf("Scraper: no limiters for unknown source %q", name)
+		return
+	}
+	if baseDelay > 0 {
+		log.Printf("Scraper: %s list limiter configured (base_delay=%v, jitter=%v)", name, baseDelay, jitter)
+	}
+	if detailPerHour > 0 {
+		log.Printf("Scraper: %s detail limiter configured (%d per hour)", name, detailPerHour)
+	}
+}
@@ -0,0 +1,121 @@
+package scraper
+
+import (
+	"cmp"
+	"real-estate-portal/internal/models"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// stationRowKeys are the detail table headers whose cell lists the access lines
+var stationRowKeys = map[string]bool{"交通": true, "アクセス": true, "最寄駅": true, "最寄り駅": true}
+
+var (
+	// stationLinePattern splits one access line into line / station / the rest:
+	// "JR山手線 新宿駅 徒歩7分", "ＪＲ山手線/新宿駅 歩7分", "東京メトロ丸ノ内線「西新宿」駅 徒歩4分"
+	stationLinePattern = regexp.MustCompile(`^(.*?)[\s/／]*「?([^\s/／「」]+?)」?駅(?:[\s/／]+(.*))?$`)
+	accessWalkPattern  = regexp.MustCompile(`(?:徒歩|歩)\s*([0-9]+)\s*分`)
+	accessBusPattern   = regexp.MustCompile(`バス\s*([0-9]+)\s*分`)
+)
+
+// parseAccessMinutes reads the minutes part of an access line ("徒歩7分", "バス10分 ○○停 歩2分").
+// After a bus ride the 徒歩 is from the bus stop, so it is not the walk to the station.
+func parseAccessMinutes(text string) (walk, bus int, viaBus bool) {
+	viaBus = strings.Contains(text, "バス")
+	if m := accessBusPattern.FindStringSubmatch(text); m != nil {
+		if v, err := strconv.Atoi(m[1]); err == nil && v >= 1 && v <= 120 {
+			bus = v
+		}
+	}
+	if m := accessWalkPattern.FindStringSubmatch(text); m != nil && !viaBus {
+		if v, err := strconv.Atoi(m[1]); err == nil && v >= 1 && v <= 120 {
+			walk = v
+		}
+	}
+	return walk, bus, viaBus
+}
+
+// extractStationRows reads the 交通 row (th/td or dt/dd) of the detail table, one station per
+// line (<br> or block element). Lines without a "○○駅" are skipped.
+func extractStationRows(doc *goquery.Document) []StationAccess {
+	var stations []StationAccess
+	doc.Find("th, dt").EachWithBreak(func(_ int, header *goquery.Selection) bool {
+		if !stationRowKeys[strings.Join(strings.Fields(header.Text()), "")] {
+			return true
+		}
+		stations = parseStationLines(cellLines(header.NextFiltered("td, dd")))
+		return len(stations) == 0
+	})
+	return stations
+}
+
+// parseStationLines parses "JR山手線/新宿駅 徒歩7分" access lines; lines without a "○○駅" are skipped
+func parseStationLines(lines []string) []StationAccess {
+	var stations []StationAccess
+	for _, line := range lines {
+		m := stationLinePattern.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		walk, bus, viaBus := parseAccessMinutes(m[3])
+		stations = append(stations, StationAccess{
+			StationName: models.NormalizeStationName(m[2]),
+			LineName:    models.NormalizeLineName(m[1]),
+			WalkMinutes: walk,
+			BusMinutes:  bus,
+			ViaBus:      viaBus,
+		})
+	}
+	return stations
+}
+
+// cellLines splits a table cell into its text lines at <br> and block elements
+func cellLines(cell *goquery.Selection) []string {
+	var lines []string
+	var current strings.Builder
+	flush := func() {
+		if line := strings.Join(strings.Fields(strings.ReplaceAll(current.String(), "　", " ")), " "); line != "" {
+			lines = append(lines, line)
+		}
+		current.Reset()
+	}
+	cell.Contents().Each(func(_ int, node *goquery.Selection) {
+		switch goquery.NodeName(node) {
+		case "br":
+			flush()
+		case "div", "p", "li":
+			flush()
+			current.WriteString(node.Text())
+			flush()
+		default:
+			current.WriteString(node.Text())
+		}
+	})
+	flush()
+	return lines
+}
+
+// orderStationsByDistance puts the nearest station on foot first (sort_order 1 is the primary
+// station the legacy Station / WalkTime fields come from); stations reached by bus or without
+// minutes follow in page order
+func orderStationsByDistance(stations []StationAccess) []StationAccess {
+	slices.SortStableFunc(stations, func(a, b StationAccess) int {
+		switch {
+		case a.WalkMinutes > 0 && b.WalkMinutes > 0:
+			return cmp.Compare(a.WalkMinutes, b.WalkMinutes)
+		case a.WalkMinutes > 0:
+			return -1
+		case b.WalkMinutes > 0:
+			return 1
+		}
+		return 0
+	})
+	for i := range stations {
+		stations[i].SortOrder = i + 1
+	}
+	return stations
+}
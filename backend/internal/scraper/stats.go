@@ -0,0 +1,186 @@
+package scraper
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ScrapeStats are the requests and waits behind one scrape call (ScrapeProperty, ScrapeListPage,
+// ScrapeListPages, CheckAlive), so slowness can be split into site latency and our own pacing.
+// They are reset at the start of each call; see Scraper.LastStats.
+type ScrapeStats struct {
+	Requests     int         // HTTP requests sent (page, landing page, robots.txt, image checks; one per proxy tried)
+	WithCookies  int         // requests that carried session cookies from the jar
+	Retries      int         // retry attempts after a failed response
+	StatusCounts map[int]int // response status → count (0 = no response: network / proxy error)
+	LimiterWait  time.Duration
+	PacingWait   time.Duration // human-pace sleeps and retry backoff
+	WallTime     time.Duration
+
+	ImageChecksSkipped int // image HEAD checks not sent (VerifyImages off, or cached)
+	ImageChecksFailed  int // images dropped as unreachable (cached results included)
+}
+
+// scrapeStatsJSON is the API form of ScrapeStats (durations in milliseconds)
+type scrapeStatsJSON struct {
+	Requests      int            `json:"requests"`
+	WithCookies   int            `json:"requests_with_cookies"`
+	Retries       int            `json:"retries"`
+	StatusCounts  map[string]int `json:"status_counts"`
+	LimiterWaitMs int64          `json:"limiter_wait_ms"`
+	PacingWaitMs  int64          `json:"pacing_wait_ms"`
+	WallTimeMs    int64          `json:"wall_time_ms"`
+
+	ImageChecksSkipped int `json:"image_checks_skipped"`
+	ImageChecksFailed  int `json:"image_checks_failed"`
+}
+
+// MarshalJSON renders durations as milliseconds and statuses as string keys
+func (st ScrapeStats) MarshalJSON() ([]byte, error) {
+	out := scrapeStatsJSON{
+		Requests:      st.Requests,
+		WithCookies:   st.WithCookies,
+		Retries:       st.Retries,
+		StatusCounts:  make(map[string]int, len(st.StatusCounts)),
+		LimiterWaitMs: st.LimiterWait.Milliseconds(),
+		PacingWaitMs:  st.PacingWait.Milliseconds(),
+		WallTimeMs:    st.WallTime.Milliseconds(),
+
+		ImageChecksSkipped: st.ImageChecksSkipped,
+		ImageChecksFailed:  st.ImageChecksFailed,
+	}
+	for status, n := range st.StatusCounts {
+		out.StatusCounts[fmt.Sprint(status)] = n
+	}
+	return json.Marshal(out)
+}
+
+// Add sums other's counts and waits into st (WallTime is left alone: for a batch it is the
+// elapsed time of the whole run, not the sum of its calls)
+func (st *ScrapeStats) Add(other ScrapeStats) {
+	st.Requests += other.Requests
+	st.WithCookies += other.WithCookies
+	st.Retries += other.Retries
+	if st.StatusCounts == nil {
+		st.StatusCounts = make(map[int]int)
+	}
+	for status, n := range other.StatusCounts {
+		st.StatusCounts[status] += n
+	}
+	st.LimiterWait += other.LimiterWait
+	st.PacingWait += other.PacingWait
+	st.ImageChecksSkipped += other.ImageChecksSkipped
+	st.ImageChecksFailed += other.ImageChecksFailed
+}
+
+// String is the one-line log form: "requests=2 retries=1 status=200:1,503:1 limiter_wait=8.2s ..."
+func (st ScrapeStats) String() string {
+	statuses := make([]int, 0, len(st.StatusCounts))
+	for status := range st.StatusCounts {
+		statuses = append(statuses, status)
+	}
+	sort.Ints(statuses)
+	parts := make([]string, len(statuses))
+	for i, status := range statuses {
+		parts[i] = fmt.Sprintf("%d:%d", status, st.StatusCounts[status])
+	}
+	return fmt.Sprintf("requests=%d with_cookies=%d retries=%d status=%s limiter_wait=%v pacing_wait=%v wall_time=%v image_checks_skipped=%d image_checks_failed=%d",
+		st.Requests, st.WithCookies, st.Retries, strings.Join(parts, ","), st.LimiterWait.Round(time.Millisecond),
+		st.PacingWait.Round(time.Millisecond), st.WallTime.Round(time.Millisecond), st.ImageChecksSkipped, st.ImageChecksFailed)
+}
+
+// statsRecorder accumulates one scrape call's ScrapeStats
+type statsRecorder struct {
+	mu      sync.Mutex
+	current ScrapeStats
+	started time.Time
+}
+
+// statsKey is the context key of the current call's statsRecorder
+type statsKey struct{}
+
+// startStats begins a scrape call's stats. The returned ctx carries them, so the requests and
+// waits made under it count for this call alone even while other calls run on the same Scraper;
+// a call made inside another (ScrapeDetail → ScrapeProperty) keeps counting into the outer one.
+// Call the returned func when the call ends.
+func (s *Scraper) startStats(ctx context.Context) (context.Context, func()) {
+	if _, ok := ctx.Value(statsKey{}).(*statsRecorder); ok {
+		return ctx, func() {}
+	}
+	rec := &statsRecorder{current: ScrapeStats{StatusCounts: make(map[int]int)}, started: time.Now()}
+	s.statsMu.Lock()
+	s.stats = rec
+	s.statsMu.Unlock()
+	return context.WithValue(ctx, statsKey{}, rec), func() {
+		rec.mu.Lock()
+		rec.current.WallTime = time.Since(rec.started)
+		rec.mu.Unlock()
+	}
+}
+
+// recorder returns the stats of the call ctx belongs to; work outside a call (e.g. the image
+// checks of ParsePropertyDocument) counts into the last call started on s
+func (s *Scraper) recorder(ctx context.Context) *statsRecorder {
+	if rec, ok := ctx.Value(statsKey{}).(*statsRecorder); ok {
+		return rec
+	}
+	s.statsMu.Lock()
+	defer s.statsMu.Unlock()
+	if s.stats == nil {
+		s.stats = &statsRecorder{started: time.Now()}
+	}
+	return s.stats
+}
+
+// recordStats applies fn to the stats of the call ctx belongs to
+func (s *Scraper) recordStats(ctx context.Context, fn func(st *ScrapeStats)) {
+	rec := s.recorder(ctx)
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	if rec.current.StatusCounts == nil {
+		rec.current.StatusCounts = make(map[int]int)
+	}
+	fn(&rec.current)
+}
+
+// recordResponse counts one request and its status (0 when no response came back)
+func (s *Scraper) recordResponse(ctx context.Context, status int) {
+	s.recordStats(ctx, func(st *ScrapeStats) {
+		st.Requests++
+		st.StatusCounts[status]++
+	})
+}
+
+// snapshot returns a copy of the recorded stats
+func (r *statsRecorder) snapshot() ScrapeStats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := r.current
+	out.StatusCounts = make(map[int]int, len(r.current.StatusCounts))
+	for status, n := range r.current.StatusCounts {
+		out.StatusCounts[status] = n
+	}
+	return out
+}
+
+// statsOf returns the stats of the call ctx belongs to (a copy)
+func (s *Scraper) statsOf(ctx context.Context) ScrapeStats {
+	return s.recorder(ctx).snapshot()
+}
+
+// LastStats returns the stats of the last scrape call started on s (a copy). With concurrent
+// calls that is whichever started last; ScrapeDetail returns each call's own in its result.
+func (s *Scraper) LastStats() ScrapeStats {
+	s.statsMu.Lock()
+	rec := s.stats
+	s.statsMu.Unlock()
+	if rec == nil {
+		return ScrapeStats{}
+	}
+	return rec.snapshot()
+}
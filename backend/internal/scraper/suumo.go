@@ -0,0 +1,450 @@
+package scraper
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"net/url"
+	"real-estate-portal/internal/models"
+	"real-estate-portal/internal/ratelimit"
+	"real-estate-portal/internal/tracing"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// suumoSourceName is SUUMO's properties.source value and sources.<name> config key
+const suumoSourceName = "suumo"
+
+// defaultSuumoBaseURL is the SUUMO origin
+const defaultSuumoBaseURL = "https://suumo.jp"
+
+// suumoLimits budget SUUMO separately from Yahoo, so a cooldown on one site never stalls the
+// other (overridden by a sources.suumo block, see ConfigureSourceLimits). List pacing and the
+// circuit breaker are SUUMO's host's shared ones.
+var suumoLimits = &sourceLimits{
+	detail: ratelimit.NewSourceDetailLimiter(suumoSourceName, 20),           // 20 detail pages per hour
+	alive:  ratelimit.NewSourceDetailLimiter("alive:"+suumoSourceName, 120), // CheckAlive HEADs
+}
+
+var (
+	// suumoDetailPattern matches detail paths: /chintai/jnc_000012345678/ (listing) or
+	// /chintai/bc_100012345678/ (building cassette)
+	suumoDetailPattern = regexp.MustCompile(`/chintai/((?:jnc|bc)_[0-9]+)(?:/|$)`)
+	// suumoAccessPattern splits a 駅徒歩 line: "ＪＲ山手線/渋谷駅 歩7分" or "東急バス/渋谷駅 バス10分 (バス停)大坂上 歩2分"
+	suumoAccessPattern = regexp.MustCompile(`^(.+?)[/／](.+?駅)\s*(.*)$`)
+	suumoFloorsPattern = regexp.MustCompile(`([0-9]+)階`)
+)
+
+// SuumoSource scrapes SUUMO (suumo.jp) 賃貸 list and detail pages. SUUMO serves plain HTML,
+// so pages are fetched with the HTTP client (no headless Chrome, homepage visit or human-pace
+// sleep); requests are paced by SUUMO's own limiters and circuit breaker instead.
+type SuumoSource struct {
+	fetcher *Scraper // HTTP client, headers, robots.txt, retries and debug capture
+	baseURL string
+}
+
+// NewSuumoSource creates the SUUMO source. config.BaseURL defaults to https://suumo.jp;
+// FixtureMode is ignored (pages are always fetched over plain HTTP).
+func NewSuumoSource(config ScraperConfig) *SuumoSource {
+	if config.BaseURL == "" {
+		config.BaseURL = defaultSuumoBaseURL
+	}
+	limiter, breaker := SharedLimits(suumoSourceName, baseHost(config.BaseURL))
+	fetcher := NewScraperWithLimiter(config, limiter, breaker)
+	fetcher.limits = suumoLimits
+	return &SuumoSource{fetcher: fetcher, baseURL: fetcher.baseURL}
+}
+
+// Name implements PropertySource
+func (ss *SuumoSource) Name() string { return suumoSourceName }
+
+// Hosts implements PropertySource
+func (ss *SuumoSource) Hosts() []string {
+	u, err := url.Parse(ss.baseURL)
+	if err != nil || u.Host == "" {
+		return nil
+	}
+	if u.Host == "suumo.jp" {
+		return []string{"suumo.jp", "www.suumo.jp"}
+	}
+	return []string{u.Host}
+}
+
+// Limiter implements PropertySource: SUUMO's own detail budget
+func (ss *SuumoSource) Limiter() *ratelimit.DetailLimiter { return suumoLimits.detail }
+
+// CircuitBreaker implements BreakerSource: the breaker of SUUMO's host
+func (ss *SuumoSource) CircuitBreaker() *CircuitBreaker { return ss.fetcher.circuitBreaker }
+
+// SourcePropertyID implements PropertySource: "jnc_000012345678" / "bc_100012345678"
+func (ss *SuumoSource) SourcePropertyID(detailURL string) (string, error) {
+	u, err := url.Parse(detailURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid SUUMO URL %q: %w", detailURL, err)
+	}
+	m := suumoDetailPattern.FindStringSubmatch(u.Path)
+	if m == nil {
+		return "", fmt.Errorf("not a SUUMO detail URL (expected /chintai/jnc_…/ or /chintai/bc_…/): %s", detailURL)
+	}
+	return m[1], nil
+}
+
+// detailURL is the canonical detail URL for a SUUMO listing ID (SUUMO redirects without the slash)
+func (ss *SuumoSource) detailURL(id string) string {
+	return ss.baseURL + "/chintai/" + id + "/"
+}
+
+// ScrapeList implements PropertySource (first page only; see ScrapeListPagesContext)
+func (ss *SuumoSource) ScrapeList(ctx context.Context, listURL string) ([]string, error) {
+	ctx, done := ss.fetcher.startStats(ctx)
+	defer done()
+	page, _, err := ss.scrapeListPage(ctx, listURL)
+	if err != nil {
+		return nil, err
+	}
+	return page.URLs, nil
+}
+
+// ScrapeListPagesContext follows the 次へ links like Scraper.ScrapeListPages
+func (ss *SuumoSource) ScrapeListPagesContext(ctx context.Context, listURL string, maxPages int) ([]string, int, error) {
+	page, err := ss.ScrapeListPagesWithMetaContext(ctx, listURL, maxPages)
+	return page.URLs, page.PagesVisited, err
+}
+
+// ScrapeListPagesWithMetaContext implements PagedSource
+func (ss *SuumoSource) ScrapeListPagesWithMetaContext(ctx context.Context, listURL string, maxPages int) (*ListPage, error) {
+	ctx, done := ss.fetcher.startStats(ctx)
+	defer done()
+	return crawlListPages(ctx, listURL, maxPages, ss.scrapeListPage)
+}
+
+// scrapeListPage returns the detail URLs on one SUUMO list page with the reported counts
+// ("paginate_set-hit" / pager), and the next page's URL
+func (ss *SuumoSource) scrapeListPage(ctx context.Context, listURL string) (*ListPage, string, error) {
+	log.Printf("[Suumo] Scraping list page: %s", listURL)
+
+	if err := ss.fetcher.checkRobots(ctx, listURL); err != nil {
+		log.Printf("[Suumo] Skipping %s: %v", listURL, err)
+		return nil, "", err
+	}
+
+	page, err := ss.fetcher.fetchHTML(ctx, listURL, "", Validators{})
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to fetch list page: %w", err)
+	}
+
+	if err := acquireParse(ctx); err != nil {
+		return nil, "", err
+	}
+	defer ParseLimiter.Release()
+	doc, err := parseDocument(strings.NewReader(page.html))
+	if err != nil {
+		return nil, "", fmt.Errorf("%w: failed to parse HTML: %w", ErrParse, err)
+	}
+
+	// Each cassette links its rooms to /chintai/jnc_…/ (query strings carry tracking only)
+	var propertyURLs []string
+	seen := make(map[string]bool)
+	doc.Find("a[href]").Each(func(_ int, a *goquery.Selection) {
+		href, _ := a.Attr("href")
+		m := suumoDetailPattern.FindStringSubmatch(strings.Split(href, "?")[0])
+		if m == nil || seen[m[1]] {
+			return
+		}
+		seen[m[1]] = true
+		propertyURLs = append(propertyURLs, ss.detailURL(m[1]))
+	})
+
+	log.Printf("[Suumo] Found %d unique property URLs from %s", len(propertyURLs), listURL)
+	return listPageMeta(doc, propertyURLs), findNextPageURL(doc, listURL), nil
+}
+
+// ScrapeDetail implements PropertySource (the gallery and stats come back in the result)
+func (ss *SuumoSource) ScrapeDetail(ctx context.Context, detailURL string, v Validators) (_ *DetailResult, retErr error) {
+	ctx, span := tracing.Start(ctx, "scraper.suumo.ScrapeDetail", attribute.String("scrape.url", detailURL))
+	defer func() {
+		tracing.RecordError(span, retErr)
+		span.End()
+	}()
+	ctx, done := ss.fetcher.startStats(ctx)
+	result, err := ss.scrapeDetail(ctx, detailURL, v)
+	done()
+	if result == nil {
+		result = &DetailResult{}
+	}
+	result.Stats = ss.fetcher.statsOf(ctx)
+	return result, err
+}
+
+// scrapeDetail fetches and parses one detail page under ScrapeDetail's stats
+func (ss *SuumoSource) scrapeDetail(ctx context.Context, detailURL string, v Validators) (*DetailResult, error) {
+	id, err := ss.SourcePropertyID(detailURL)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrNotFound, err)
+	}
+	pageURL := ss.detailURL(id)
+
+	if err := ss.fetcher.checkRobots(ctx, pageURL); err != nil {
+		log.Printf("[Suumo] Skipping %s: %v", pageURL, err)
+		return nil, err
+	}
+
+	page, err := ss.fetcher.fetchHTML(ctx, pageURL, refererFrom(ctx), v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch URL: %w", err)
+	}
+	if page.notModified {
+		log.Printf("[Suumo] 304 Not Modified: %s (skipping parse)", pageURL)
+		return nil, ErrNotModified
+	}
+
+	result, err := ss.ParseDetailHTML(ctx, page.html, pageURL)
+	if err != nil {
+		return nil, err
+	}
+	result.Property.ETag = page.validators.ETag
+	result.Property.LastModified = page.validators.LastModified
+	return result, nil
+}
+
+// LastStats implements StatsSource (the fetcher's stats for the last list call)
+func (ss *SuumoSource) LastStats() ScrapeStats {
+	return ss.fetcher.LastStats()
+}
+
+// CheckAliveContext implements AliveChecker (HEAD through the fetcher, on SUUMO's alive budget)
+func (ss *SuumoSource) CheckAliveContext(ctx context.Context, detailURL string) (bool, int, error) {
+	return ss.fetcher.CheckAliveContext(ctx, detailURL)
+}
+
+// ParseDetailHTML extracts a property with its stations and gallery from a SUUMO detail page
+// fetched from pageURL (the result carries no stats)
+func (ss *SuumoSource) ParseDetailHTML(ctx context.Context, htmlContent string, pageURL string) (*DetailResult, error) {
+	id, err := ss.SourcePropertyID(pageURL)
+	if err != nil {
+		hash := md5.Sum([]byte(pageURL))
+		id = hex.EncodeToString(hash[:])
+	}
+
+	if err := acquireParse(ctx); err != nil {
+		return nil, err
+	}
+	defer ParseLimiter.Release()
+	doc, err := parseDocument(strings.NewReader(htmlContent))
+	if err != nil {
+		ss.fetcher.debugHTML.save(id, htmlContent, "parse error")
+		return nil, fmt.Errorf("%w: failed to parse HTML: %w", ErrParse, err)
+	}
+
+	property := &models.Property{
+		Source:           suumoSourceName,
+		SourcePropertyID: id,
+		DetailURL:        pageURL,
+		FetchedAt:        time.Now(),
+	}
+
+	property.Title = suumoTitle(doc)
+
+	// 賃料 ("8.5万円") and the 管理費・敷金・礼金 spans next to it ("管理費・共益費: 5000円")
+	if rent := models.ParseYenAmount(doc.Find(".property_view_note-emphasis").First().Text()); rent != nil && *rent > 0 {
+		property.Rent = rent
+	}
+	doc.Find(".property_view_note-list span").Each(func(_ int, span *goquery.Selection) {
+		key, value, ok := strings.Cut(strings.Join(strings.Fields(span.Text()), " "), ":")
+		if !ok {
+			key, value, ok = strings.Cut(key, "：")
+		}
+		if !ok {
+			return
+		}
+		if field := contractCostField(property, strings.TrimSpace(key)); field != nil && *field == "" {
+			*field = normalizeCostValue(value)
+		}
+	})
+
+	// 物件概要 table rows (所在地 / 駅徒歩 / 間取り / 専有面積 / 築年数 / 階 / 階建 / 向き …)
+	var stations []StationAccess
+	var unitFloor, buildingFloors string
+	doc.Find("th").Each(func(_ int, th *goquery.Selection) {
+		key := strings.Join(strings.Fields(th.Text()), "")
+		td := th.NextFiltered("td")
+		value := strings.Join(strings.Fields(td.Text()), " ")
+		if key == "" || value == "" {
+			return
+		}
+		switch key {
+		case "所在地":
+			property.Address = truncateRunes(value, 100)
+		case "駅徒歩":
+			stations = parseSuumoAccess(td)
+		case "間取り":
+			property.FloorPlan = normalizeFloorPlan(value)
+		case "間取り詳細":
+			property.FloorPlanDetails = value
+		case "専有面積":
+			area, unit := models.ParseArea(value)
+			setArea(property, area, unit)
+		case "築年数":
+			age := extractBuildingAge(value)
+			if age > 0 || strings.Contains(value, "新築") {
+				property.BuildingAge = &age
+			}
+		case "階":
+			unitFloor = value
+		case "階建":
+			buildingFloors = value
+		case "向き":
+			property.Direction = value
+		case "入居":
+			property.MoveInDate = truncateRunes(value, 100)
+		case "条件":
+			property.Conditions = truncateRunes(strings.Join(cellLines(td), "\n"), 255)
+		case "契約期間":
+			property.ContractPeriod = truncateRunes(value, 50)
+		case "駐車場":
+			property.Parking = truncateRunes(value, 255)
+		case "損保":
+			property.Insurance = truncateRunes(value, 255)
+		case "備考":
+			property.Notes = truncateText(strings.Join(cellLines(td), "\n"), maxNotesBytes)
+		}
+	})
+	property.FloorLabel = suumoFloorLabel(unitFloor, buildingFloors)
+	property.NormalizeFloors()
+
+	// 建物種別 / 構造 and the contract costs that only appear in the table
+	applyBuildingRows(doc, property)
+	applyContractCosts(doc, property)
+	property.NormalizeFees()
+
+	// 部屋の特徴・設備: one "、"-separated list
+	var labels []string
+	for _, label := range strings.Split(doc.Find("#bkdt-option li").First().Text(), "、") {
+		if label = strings.TrimSpace(label); label != "" {
+			labels = append(labels, label)
+		}
+	}
+	if len(labels) > 0 {
+		property.SetFacilities(normalizeFacilitiesFromLabels(labels))
+		property.SetFeatures(labels)
+	}
+	property.NormalizeListFields()
+
+	property.ApplyCampaigns(models.ParseCampaigns(
+		property.Title, property.Notes, property.Conditions, doc.Find("body").Text(),
+	))
+	property.ApplyLeaseTerms(models.ParseLeaseTerms(
+		append([]string{property.ContractPeriod, property.Conditions}, extractLeaseRows(doc)...)...,
+	))
+
+	applyStationCompatibility(property, stations)
+	property.NormalizeWalkTimeBucket()
+
+	// 周辺地図 (map iframe) coordinates, when the page embeds one
+	applyMapCoordinates(doc, property)
+
+	images := suumoImages(doc)
+	if len(images) > 0 {
+		property.ImageURL = images[0]
+	}
+
+	idSource := property.Source + ":" + property.SourcePropertyID
+	hash := md5.Sum([]byte(idSource))
+	property.ID = hex.EncodeToString(hash[:])
+
+	if property.Title == "No Title" || property.Rent == nil {
+		ss.fetcher.debugHTML.save(property.SourcePropertyID, htmlContent, "no title or rent")
+	}
+
+	log.Printf("[Suumo] Parsed property %s (ID: %s, Title: %s, Stations: %d)", pageURL, property.ID, property.Title, len(stations))
+	return &DetailResult{
+		Property: property,
+		Stations: convertStationsToModels(property.ID, stations),
+		Images:   models.NewPropertyImages(property.ID, images),
+	}, nil
+}
+
+// suumoTitle is the page heading, falling back to og:title / <title> without the "｜SUUMO" suffix
+func suumoTitle(doc *goquery.Document) string {
+	title := strings.TrimSpace(doc.Find("h1.section_h1-header-title").First().Text())
+	if title == "" {
+		title, _ = doc.Find("meta[property='og:title']").Attr("content")
+	}
+	if title == "" {
+		title = doc.Find("title").Text()
+	}
+	title = strings.TrimPrefix(strings.TrimSpace(title), "【SUUMO】")
+	if i := strings.Index(title, "｜"); i > 0 {
+		title = title[:i]
+	}
+	title = strings.Join(strings.Fields(title), " ")
+	if title == "" {
+		return "No Title"
+	}
+	return title
+}
+
+// parseSuumoAccess reads the 駅徒歩 cell: one .property_view_table-read line per station, nearest first
+func parseSuumoAccess(td *goquery.Selection) []StationAccess {
+	var stations []StationAccess
+	td.Find(".property_view_table-read").Each(func(_ int, line *goquery.Selection) {
+		text := strings.Join(strings.Fields(strings.ReplaceAll(line.Text(), "　", " ")), " ")
+		m := suumoAccessPattern.FindStringSubmatch(text)
+		if m == nil {
+			return
+		}
+		// After a bus ride the 歩N分 is from the bus stop, not the station
+		walk, bus, viaBus := parseAccessMinutes(m[3])
+		stations = append(stations, StationAccess{
+			StationName: models.NormalizeStationName(strings.TrimSpace(m[2])),
+			LineName:    models.NormalizeLineName(strings.TrimSpace(m[1])),
+			WalkMinutes: walk,
+			BusMinutes:  bus,
+			ViaBus:      viaBus,
+		})
+	})
+	return orderStationsByDistance(stations)
+}
+
+// suumoFloorLabel combines the 階 ("3階") and 階建 ("10階/地下1階建") cells into the
+// "地上10階建/3階" form ParseFloorLabel reads; SUUMO lists above-ground floors first
+func suumoFloorLabel(unitFloor, buildingFloors string) string {
+	var parts []string
+	if m := suumoFloorsPattern.FindStringSubmatch(buildingFloors); m != nil && strings.Contains(buildingFloors, "建") {
+		parts = append(parts, "地上"+m[1]+"階建")
+	}
+	if unitFloor != "" {
+		parts = append(parts, strings.ReplaceAll(unitFloor, " ", ""))
+	}
+	return strings.Join(parts, "/")
+}
+
+// suumoImages returns the gallery image URLs (lazy-loaded data-src first), falling back to og:image
+func suumoImages(doc *goquery.Document) []string {
+	var images []string
+	seen := make(map[string]bool)
+	doc.Find("#js-view_gallery-list img, .property_view_object-img img").Each(func(_ int, img *goquery.Selection) {
+		src, ok := img.Attr("data-src")
+		if !ok || src == "" {
+			src, _ = img.Attr("src")
+		}
+		src = strings.TrimSpace(src)
+		if !strings.HasPrefix(src, "http") || seen[src] {
+			return
+		}
+		seen[src] = true
+		images = append(images, src)
+	})
+	if len(images) == 0 {
+		if og, ok := doc.Find("meta[property='og:image']").Attr("content"); ok && strings.HasPrefix(og, "http") {
+			images = append(images, strings.TrimSpace(og))
+		}
+	}
+	return images
+}
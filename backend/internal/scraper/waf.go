@@ -0,0 +1,62 @@
+package scraper
+
+import (
+	"bytes"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+)
+
+// wafBlockMarkers are texts only Yahoo's block interstitial contains. The page is usually served
+// with 500, but also with 200, so it is recognized by content and never by status alone.
+var wafBlockMarkers = []string{
+	"ご覧になろうとしているページは現在表示できません",
+}
+
+// IsWAFBlockPage reports whether an HTML body is the WAF block page. The queue worker's health
+// check uses it too, so a block served with 200 is not taken for a healthy site.
+func IsWAFBlockPage(body string) bool {
+	for _, marker := range wafBlockMarkers {
+		if strings.Contains(body, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// isWAFBlock checks if a response is the WAF block page, whatever its status. The body is read
+// (and decoded) and put back, so the caller can still read it when it is not a block.
+func isWAFBlock(resp *http.Response) bool {
+	if resp.Body == nil || resp.StatusCode == http.StatusNotModified {
+		return false
+	}
+
+	// Read body to check for WAF indicators (the block page may be compressed too)
+	decoded, err := decodeBody(resp)
+	if err != nil {
+		return false
+	}
+	body, err := io.ReadAll(decoded)
+	decoded.Close()
+
+	// Replace body (already decoded) so it can be read again if needed; a read that failed
+	// part-way fails again for the caller instead of passing off a truncated page
+	resp.Header.Del("Content-Encoding")
+	if err != nil {
+		resp.Body = io.NopCloser(io.MultiReader(bytes.NewReader(body), failingReader{err}))
+		return false
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	if IsWAFBlockPage(string(body)) {
+		log.Printf("[WAF] Detected Yahoo WAF block page (status %d)", resp.StatusCode)
+		return true
+	}
+	return false
+}
+
+// failingReader returns err on every read
+type failingReader struct{ err error }
+
+func (r failingReader) Read([]byte) (int, error) { return 0, r.err }
@@ -9,14 +9,52 @@ import (
 	"github.com/meilisearch/meilisearch-go"
 )
 
+// FilterParams are the /api/filter parameters. JSON names match the query parameters
+// so stored share links can hydrate the frontend filter UI directly.
 type FilterParams struct {
-	Query       string
-	MinRent     *int
-	MaxRent     *int
-	FloorPlans  []string
-	MaxWalkTime *int
-	SortBy      string
-	Limit       int64
+	Query             string   `json:"q,omitempty"`
+	MinRent           *int     `json:"min_rent,omitempty"`
+	MaxRent           *int     `json:"max_rent,omitempty"`
+	FloorPlans        []string `json:"floor_plan,omitempty"`
+	BuildingTypes     []string `json:"building_type,omitempty"` // mansion / apartment / ... (OR semantics)
+	MaxWalkTime       *int     `json:"max_walk_time,omitempty"`
+	Lines             []string `json:"lines,omitempty"`               // Reachable lines (OR semantics)
+	MinBuildingFloors *int     `json:"min_building_floors,omitempty"` // Building floors lower bound (タワー物件)
+
+	// Campaign filters (only applied when set to true)
+	FreeRent       bool `json:"free_rent,omitempty"`
+	NoBrokerageFee bool `json:"no_brokerage_fee,omitempty"`
+
+	// Lease type filter (nil = any, false = exclude 定期借家, true = 定期借家 only)
+	FixedTermLease *bool `json:"fixed_term_lease,omitempty"`
+
+	SortBy string `json:"sort_by,omitempty"`
+	Limit  int64  `json:"limit,omitempty"`
+}
+
+// HasFilters reports whether a query or any filter is set (otherwise callers list everything)
+func (p FilterParams) HasFilters() bool {
+	return p.Query != "" || p.MinRent != nil || p.MaxRent != nil ||
+		len(p.FloorPlans) > 0 || len(p.BuildingTypes) > 0 || p.MaxWalkTime != nil || len(p.Lines) > 0 ||
+		p.MinBuildingFloors != nil || p.FreeRent || p.NoBrokerageFee || p.FixedTermLease != nil
+}
+
+// Validate rejects sort keys, floor plans and building types outside the canonical lists (see /api/meta/options)
+func (p FilterParams) Validate() error {
+	if !IsFilterSortKey(p.SortBy) {
+		return fmt.Errorf("invalid sort_by: %s", p.SortBy)
+	}
+	for _, plan := range p.FloorPlans {
+		if !models.IsFloorPlan(plan) {
+			return fmt.Errorf("invalid floor_plan: %s", plan)
+		}
+	}
+	for _, buildingType := range p.BuildingTypes {
+		if !models.IsBuildingType(buildingType) {
+			return fmt.Errorf("invalid building_type: %s", buildingType)
+		}
+	}
+	return nil
 }
 
 // FilterSearch performs advanced search with filters
@@ -40,11 +78,55 @@ func (s *SearchClient) FilterSearch(params FilterParams) ([]models.Property, err
 		filters = append(filters, fmt.Sprintf("(%s)", strings.Join(planFilters, " OR ")))
 	}
 
+	// Building type filter
+	if len(params.BuildingTypes) > 0 {
+		typeFilters := make([]string, len(params.BuildingTypes))
+		for i, buildingType := range params.BuildingTypes {
+			typeFilters[i] = fmt.Sprintf("building_type = '%s'", buildingType)
+		}
+		filters = append(filters, fmt.Sprintf("(%s)", strings.Join(typeFilters, " OR ")))
+	}
+
+	// Line filter (any of the given lines)
+	if len(params.Lines) > 0 {
+		lineFilters := make([]string, 0, len(params.Lines))
+		seen := make(map[string]bool, len(params.Lines))
+		for _, line := range params.Lines {
+			line = models.NormalizeLineName(line)
+			if line == "" || seen[line] {
+				continue
+			}
+			seen[line] = true
+			lineFilters = append(lineFilters, fmt.Sprintf("lines = '%s'", strings.ReplaceAll(line, "'", "\\'")))
+		}
+		if len(lineFilters) > 0 {
+			filters = append(filters, fmt.Sprintf("(%s)", strings.Join(lineFilters, " OR ")))
+		}
+	}
+
 	// Walk time filter
 	if params.MaxWalkTime != nil {
 		filters = append(filters, fmt.Sprintf("walk_time <= %d", *params.MaxWalkTime))
 	}
 
+	// Building floors filter
+	if params.MinBuildingFloors != nil {
+		filters = append(filters, fmt.Sprintf("building_floors >= %d", *params.MinBuildingFloors))
+	}
+
+	// Campaign filters
+	if params.FreeRent {
+		filters = append(filters, "free_rent = true")
+	}
+	if params.NoBrokerageFee {
+		filters = append(filters, "no_brokerage_fee = true")
+	}
+
+	// Lease type filter
+	if params.FixedTermLease != nil {
+		filters = append(filters, fmt.Sprintf("is_fixed_term_lease = %t", *params.FixedTermLease))
+	}
+
 	// Combine filters
 	var filterStr string
 	if len(filters) > 0 {
@@ -54,7 +136,8 @@ func (s *SearchClient) FilterSearch(params FilterParams) ([]models.Property, err
 	// Determine sort order
 	var sort []string
 	if params.SortBy != "" {
-		sort = []string{params.SortBy}
+		// fetched_at is stored as a string; "recently updated" sorts on the numeric copy
+		sort = []string{strings.Replace(params.SortBy, "fetched_at:", "fetched_at_ts:", 1)}
 	}
 
 	// Default limit
@@ -0,0 +1,74 @@
+package search
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// captureSearch starts a Meilisearch stand-in that answers every search with no hits and
+// returns the filter of the last one
+func captureSearch(t *testing.T) (*SearchClient, func() interface{}) {
+	t.Helper()
+	var filter interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/indexes/properties/search" {
+			http.NotFound(w, r)
+			return
+		}
+		var req map[string]interface{}
+		body, _ := io.ReadAll(r.Body)
+		if err := json.Unmarshal(body, &req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		filter = req["filter"]
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, `{"hits":[],"query":"","processingTimeMs":0,"limit":20,"offset":0,"estimatedTotalHits":0}`)
+	}))
+	t.Cleanup(srv.Close)
+	return NewSearchClient(srv.URL, "test-key"), func() interface{} { return filter }
+}
+
+func TestFilterSearchLines(t *testing.T) {
+	maxWalk := 10
+	tests := []struct {
+		name   string
+		params FilterParams
+		want   interface{}
+	}{
+		{
+			name:   "one line",
+			params: FilterParams{Lines: []string{"東急東横線"}},
+			want:   "(lines = '東急東横線')",
+		},
+		{
+			name:   "mixed prefixes of one line collapse",
+			params: FilterParams{Lines: []string{"ＪＲ山手線", "JR 山手線", "JR山手線"}},
+			want:   "(lines = 'JR山手線')",
+		},
+		{
+			name:   "two lines are ORed and ANDed with walk time",
+			params: FilterParams{Lines: []string{"ＪＲ山手線", "東急東横線"}, MaxWalkTime: &maxWalk},
+			want:   "(lines = 'JR山手線' OR lines = '東急東横線') AND walk_time <= 10",
+		},
+		{
+			name:   "blank lines are ignored",
+			params: FilterParams{Lines: []string{" ", ""}, MaxWalkTime: &maxWalk},
+			want:   "walk_time <= 10",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, lastFilter := captureSearch(t)
+			if _, err := client.FilterSearch(tt.params); err != nil {
+				t.Fatalf("FilterSearch: %v", err)
+			}
+			if got := lastFilter(); got != tt.want {
+				t.Errorf("filter %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
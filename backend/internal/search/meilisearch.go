@@ -1,9 +1,12 @@
 package search
 
 import (
+	"context"
 	"real-estate-portal/internal/models"
+	"real-estate-portal/internal/tracing"
 
 	"github.com/meilisearch/meilisearch-go"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 type SearchClient struct {
@@ -42,6 +45,8 @@ func (s *SearchClient) InitIndex() error {
 		"station",
 		"address",
 		"floor_plan",
+		"conditions",
+		"notes",
 	})
 	if err != nil {
 		return err
@@ -53,10 +58,26 @@ func (s *SearchClient) InitIndex() error {
 		"rent",
 		"floor_plan",
 		"walk_time",
+		"walk_time_bucket",
+		"bus_minutes",
 		"area",
 		"building_age",
 		"floor",
+		"unit_floor",
+		"building_floors",
 		"station",
+		"lines",
+		"management_fee_yen",
+		"deposit_months",
+		"deposit_yen",
+		"key_money_months",
+		"key_money_yen",
+		"free_rent",
+		"free_rent_months",
+		"no_brokerage_fee",
+		"is_fixed_term_lease",
+		"building_type",
+		"_geo",
 	})
 	if err != nil {
 		return err
@@ -69,6 +90,8 @@ func (s *SearchClient) InitIndex() error {
 		"walk_time",
 		"building_age",
 		"created_at",
+		"fetched_at_ts",
+		"_geo",
 	})
 	if err != nil {
 		return err
@@ -79,7 +102,20 @@ func (s *SearchClient) InitIndex() error {
 
 // IndexProperty indexes a single property
 func (s *SearchClient) IndexProperty(property *models.Property) error {
-	_, err := s.client.Index(s.index).AddDocuments([]models.Property{*property})
+	return s.IndexPropertyContext(context.Background(), property)
+}
+
+// IndexPropertyContext is IndexProperty traced under the span carried by ctx
+func (s *SearchClient) IndexPropertyContext(ctx context.Context, property *models.Property) error {
+	_, span := tracing.Start(ctx, "search.IndexProperty", attribute.String("property.id", property.ID))
+	defer span.End()
+
+	doc := *property
+	doc.FetchedAtTS = doc.FetchedAt.Unix()
+	doc.Geo = doc.GeoPoint()
+	doc.NormalizeWalkTimeBucket()
+	_, err := s.client.Index(s.index).AddDocuments([]models.Property{doc})
+	tracing.RecordError(span, err)
 	return err
 }
 
@@ -88,7 +124,27 @@ func (s *SearchClient) IndexProperties(properties []models.Property) error {
 	if len(properties) == 0 {
 		return nil
 	}
-	_, err := s.client.Index(s.index).AddDocuments(properties)
+
+	// fetched_at is an RFC3339 string in the document; sort on a numeric copy instead.
+	// Coordinates go in _geo as well, for Meilisearch geo filters and sorting.
+	// walk_time_bucket is recomputed so the facet never disagrees with walk_time.
+	docs := make([]models.Property, len(properties))
+	for i := range properties {
+		docs[i] = properties[i]
+		docs[i].FetchedAtTS = properties[i].FetchedAt.Unix()
+		docs[i].Geo = properties[i].GeoPoint()
+		docs[i].NormalizeWalkTimeBucket()
+	}
+	_, err := s.client.Index(s.index).AddDocuments(docs)
+	return err
+}
+
+// DeleteProperties removes properties from the index by ID
+func (s *SearchClient) DeleteProperties(ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	_, err := s.client.Index(s.index).DeleteDocuments(ids)
 	return err
 }
 
@@ -200,6 +256,14 @@ func parsePropertyFromHit(hit interface{}) models.Property {
 		FloorPlan: getString(hitMap, "floor_plan"),
 		Status:    models.PropertyStatus(getString(hitMap, "status")),
 	}
+	property.RoomLayoutImageURL = getString(hitMap, "room_layout_image_url")
+	property.Direction = getString(hitMap, "direction")
+	property.FloorLabel = getString(hitMap, "floor_label")
+	property.Parking = getString(hitMap, "parking")
+	property.ContractPeriod = getString(hitMap, "contract_period")
+	property.MoveInDate = getString(hitMap, "move_in_date")
+	property.Conditions = getString(hitMap, "conditions")
+	property.Notes = getString(hitMap, "notes")
 
 	// Parse numeric fields
 	if rent, ok := hitMap["rent"].(float64); ok {
@@ -209,10 +273,16 @@ func parsePropertyFromHit(hit interface{}) models.Property {
 	if area, ok := hitMap["area"].(float64); ok {
 		property.Area = &area
 	}
+	property.AreaUnit = getString(hitMap, "area_unit")
 	if walkTime, ok := hitMap["walk_time"].(float64); ok {
 		walkTimeInt := int(walkTime)
 		property.WalkTime = &walkTimeInt
 	}
+	property.WalkTimeBucket = getString(hitMap, "walk_time_bucket")
+	if busMinutes, ok := hitMap["bus_minutes"].(float64); ok {
+		busMinutesInt := int(busMinutes)
+		property.BusMinutes = &busMinutesInt
+	}
 	if buildingAge, ok := hitMap["building_age"].(float64); ok {
 		buildingAgeInt := int(buildingAge)
 		property.BuildingAge = &buildingAgeInt
@@ -221,6 +291,11 @@ func parsePropertyFromHit(hit interface{}) models.Property {
 		floorInt := int(floor)
 		property.Floor = &floorInt
 	}
+	if lat, ok := hitMap["latitude"].(float64); ok {
+		if lng, ok := hitMap["longitude"].(float64); ok {
+			property.Latitude, property.Longitude = &lat, &lng
+		}
+	}
 
 	return property
 }
@@ -0,0 +1,38 @@
+package search
+
+import "slices"
+
+// SearchSortKeys are the advanced search ("sort" in POST /api/search/advanced) sort keys
+var SearchSortKeys = []string{
+	"newest", "recently_updated", "rent_asc", "rent_desc", "area_desc", "walk_time_asc", "building_age_asc",
+}
+
+// searchSorts maps each SearchSortKeys entry to its Meilisearch sort expression
+var searchSorts = map[string]string{
+	"newest":           "created_at:desc",
+	"recently_updated": "fetched_at_ts:desc",
+	"rent_asc":         "rent:asc",
+	"rent_desc":        "rent:desc",
+	"area_desc":        "area:desc",
+	"walk_time_asc":    "walk_time:asc",
+	"building_age_asc": "building_age:asc",
+}
+
+// FilterSortKeys are the /api/filter sort_by values (Meilisearch sort expressions;
+// fetched_at is rewritten to the numeric fetched_at_ts by FilterSearch)
+var FilterSortKeys = []string{
+	"rent:asc", "rent:desc", "area:asc", "area:desc", "walk_time:asc", "walk_time:desc",
+	"building_age:asc", "building_age:desc", "created_at:asc", "created_at:desc",
+	"fetched_at:asc", "fetched_at:desc",
+}
+
+// SearchSort returns the Meilisearch sort expression for an advanced search sort key
+func SearchSort(key string) (string, bool) {
+	sort, ok := searchSorts[key]
+	return sort, ok
+}
+
+// IsFilterSortKey reports whether sortBy is an accepted /api/filter sort_by value ("" = relevance)
+func IsFilterSortKey(sortBy string) bool {
+	return sortBy == "" || slices.Contains(FilterSortKeys, sortBy)
+}
@@ -0,0 +1,140 @@
+// Package share stores filtered searches under random tokens so a result set can be
+// sent as a link and re-executed later.
+package share
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"log"
+	"real-estate-portal/internal/maintenance"
+	"real-estate-portal/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// DefaultTTL is how long a share link stays valid when no expiry is requested
+const DefaultTTL = 30 * 24 * time.Hour
+
+// MaxTTL caps requested expiries
+const MaxTTL = 365 * 24 * time.Hour
+
+var (
+	// ErrNotFound is returned for unknown tokens
+	ErrNotFound = errors.New("share link not found")
+	// ErrExpired is returned for tokens past their expiry (until the purge removes them)
+	ErrExpired = errors.New("share link expired")
+)
+
+// Service creates, resolves and purges share links
+type Service struct {
+	db  *gorm.DB
+	now func() time.Time
+
+	stopChan chan struct{}
+	done     chan struct{}
+}
+
+// NewService creates a new share link service
+func NewService(db *gorm.DB) *Service {
+	return &Service{db: db, now: time.Now}
+}
+
+// Create stores params (serialized filter parameters) under a new token.
+// ttl <= 0 uses DefaultTTL; longer than MaxTTL is capped.
+func (s *Service) Create(params string, ttl time.Duration) (*models.SharedSearch, error) {
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+	if ttl > MaxTTL {
+		ttl = MaxTTL
+	}
+
+	token, err := newToken()
+	if err != nil {
+		return nil, err
+	}
+
+	shared := &models.SharedSearch{
+		Token:     token,
+		Params:    params,
+		ExpiresAt: s.now().Add(ttl),
+	}
+	if err := s.db.Create(shared).Error; err != nil {
+		return nil, err
+	}
+	return shared, nil
+}
+
+// Get returns the share link for token, or ErrNotFound / ErrExpired
+func (s *Service) Get(token string) (*models.SharedSearch, error) {
+	var shared models.SharedSearch
+	err := s.db.Where("token = ?", token).First(&shared).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if shared.IsExpired(s.now()) {
+		return nil, ErrExpired
+	}
+	return &shared, nil
+}
+
+// PurgeExpired deletes expired share links and returns how many were removed
+func (s *Service) PurgeExpired() (int64, error) {
+	result := s.db.Where("expires_at <= ?", s.now()).Delete(&models.SharedSearch{})
+	return result.RowsAffected, result.Error
+}
+
+// StartPurge runs PurgeExpired every interval (daily by default) until Stop
+func (s *Service) StartPurge(interval time.Duration) {
+	if interval <= 0 {
+		interval = 24 * time.Hour
+	}
+	s.stopChan = make(chan struct{})
+	s.done = make(chan struct{})
+
+	go func() {
+		defer close(s.done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if maintenance.ReadOnly() {
+					continue // expired links are already refused on read; purge next time
+				}
+				if n, err := s.PurgeExpired(); err != nil {
+					log.Printf("[Share] Purge failed: %v", err)
+				} else if n > 0 {
+					log.Printf("[Share] Purged %d expired share links", n)
+				}
+			case <-s.stopChan:
+				return
+			}
+		}
+	}()
+	log.Printf("[Share] Started (purge every %v)", interval)
+}
+
+// Stop stops the purge loop
+func (s *Service) Stop() {
+	if s.stopChan == nil {
+		return
+	}
+	close(s.stopChan)
+	<-s.done
+	s.stopChan = nil
+}
+
+// newToken returns 32 random hex characters
+func newToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,128 @@
+package snapshot
+
+import (
+	"errors"
+	"fmt"
+	"real-estate-portal/internal/models"
+	"time"
+)
+
+// DefaultDiffTolerance is how far from a requested date the nearest snapshot may be
+const DefaultDiffTolerance = 7 * 24 * time.Hour
+
+// Missing-snapshot errors for either end of a diff
+var (
+	ErrFromSnapshotMissing = errors.New("no snapshot near the from date")
+	ErrToSnapshotMissing   = errors.New("no snapshot near the to date")
+)
+
+// changeFields maps change types to the snapshot field they describe
+var changeFields = map[string]string{
+	models.ChangeTypeRent:        "rent",
+	models.ChangeTypeStatus:      "status",
+	models.ChangeTypeFloorPlan:   "floor_plan",
+	models.ChangeTypeArea:        "area",
+	models.ChangeTypeBuildingAge: "building_age",
+	models.ChangeTypeImage:       "image_url",
+	models.ChangeTypeCampaign:    "campaign",
+	models.ChangeTypeLeaseType:   "is_fixed_term_lease",
+	models.ChangeTypeAccess:      "access",
+}
+
+// FieldDiff is one changed field between two snapshots
+type FieldDiff struct {
+	Field      string   `json:"field"`
+	ChangeType string   `json:"change_type"`
+	OldValue   string   `json:"old_value"`
+	NewValue   string   `json:"new_value"`
+	Magnitude  *float64 `json:"magnitude,omitempty"` // new - old for numeric fields
+}
+
+// Diff is the field-by-field difference between the snapshots nearest to two dates
+type Diff struct {
+	PropertyID string                   `json:"property_id"`
+	From       *models.PropertySnapshot `json:"from"`
+	To         *models.PropertySnapshot `json:"to"`
+	Fields     []FieldDiff              `json:"fields"`
+	Events     []models.PropertyChange  `json:"events"` // change records detected between the requested dates
+}
+
+// DiffSnapshots compares two snapshots with the same rules as DetectChanges
+func DiffSnapshots(from, to *models.PropertySnapshot) []FieldDiff {
+	changes := CompareSnapshots(to.PropertyID, from, to, to.SnapshotAt)
+	fields := make([]FieldDiff, 0, len(changes))
+	for _, change := range changes {
+		fields = append(fields, FieldDiff{
+			Field:      changeFields[change.ChangeType],
+			ChangeType: change.ChangeType,
+			OldValue:   change.OldValue,
+			NewValue:   change.NewValue,
+			Magnitude:  change.ChangeMagnitude,
+		})
+	}
+	return fields
+}
+
+// NearestSnapshot returns the snapshot closest to at (the earlier one on a tie), or nil
+// when none lies within tolerance
+func (s *Service) NearestSnapshot(propertyID string, at time.Time, tolerance time.Duration) (*models.PropertySnapshot, error) {
+	var before, after []models.PropertySnapshot
+	if err := s.db.Where("property_id = ? AND snapshot_at <= ? AND snapshot_at >= ?", propertyID, at, at.Add(-tolerance)).
+		Order("snapshot_at DESC").Limit(1).Find(&before).Error; err != nil {
+		return nil, err
+	}
+	if err := s.db.Where("property_id = ? AND snapshot_at > ? AND snapshot_at <= ?", propertyID, at, at.Add(tolerance)).
+		Order("snapshot_at ASC").Limit(1).Find(&after).Error; err != nil {
+		return nil, err
+	}
+
+	switch {
+	case len(before) == 0 && len(after) == 0:
+		return nil, nil
+	case len(after) == 0:
+		return &before[0], nil
+	case len(before) == 0:
+		return &after[0], nil
+	case after[0].SnapshotAt.Sub(at) < at.Sub(before[0].SnapshotAt):
+		return &after[0], nil
+	default:
+		return &before[0], nil
+	}
+}
+
+// DiffBetween loads the snapshots nearest to from and to and diffs them, along with the
+// change records detected between the two dates (to is inclusive of the whole day)
+func (s *Service) DiffBetween(propertyID string, from, to time.Time, tolerance time.Duration) (*Diff, error) {
+	if tolerance <= 0 {
+		tolerance = DefaultDiffTolerance
+	}
+
+	fromSnap, err := s.NearestSnapshot(propertyID, from, tolerance)
+	if err != nil {
+		return nil, err
+	}
+	if fromSnap == nil {
+		return nil, fmt.Errorf("%w (%s ± %v)", ErrFromSnapshotMissing, from.Format("2006-01-02"), tolerance)
+	}
+	toSnap, err := s.NearestSnapshot(propertyID, to, tolerance)
+	if err != nil {
+		return nil, err
+	}
+	if toSnap == nil {
+		return nil, fmt.Errorf("%w (%s ± %v)", ErrToSnapshotMissing, to.Format("2006-01-02"), tolerance)
+	}
+
+	events := []models.PropertyChange{}
+	if err := s.db.Where("property_id = ? AND detected_at >= ? AND detected_at < ?", propertyID, from, to.AddDate(0, 0, 1)).
+		Order("detected_at ASC").Find(&events).Error; err != nil {
+		return nil, err
+	}
+
+	return &Diff{
+		PropertyID: propertyID,
+		From:       fromSnap,
+		To:         toSnap,
+		Fields:     DiffSnapshots(fromSnap, toSnap),
+		Events:     events,
+	}, nil
+}
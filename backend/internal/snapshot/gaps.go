@@ -0,0 +1,151 @@
+package snapshot
+
+import (
+	"fmt"
+	"real-estate-portal/internal/models"
+	"strings"
+	"time"
+)
+
+// SnapshotGap is a property whose queue item completed on a day that has no snapshot
+type SnapshotGap struct {
+	PropertyID       string    `json:"property_id"`
+	Source           string    `json:"source"`
+	SourcePropertyID string    `json:"source_property_id"`
+	QueueItemID      int64     `json:"queue_item_id"`
+	CompletedAt      time.Time `json:"completed_at"`
+	Day              string    `json:"day"` // snapshot date (YYYY-MM-DD)
+}
+
+// snapshotDay returns the snapshot date for t, matching CreateSnapshot's truncation
+func snapshotDay(t time.Time) time.Time {
+	return t.Truncate(24 * time.Hour)
+}
+
+// FindSnapshotGaps lists properties with done queue items in the last `days` days
+// but no snapshot for the completion day (CreateSnapshotWithChangeDetection failed silently)
+func (s *Service) FindSnapshotGaps(days int) ([]SnapshotGap, error) {
+	if days <= 0 {
+		days = 7
+	}
+	cutoff := snapshotDay(time.Now().AddDate(0, 0, -days))
+
+	// Done queue items joined to the property they produced
+	var rows []struct {
+		QueueItemID      int64
+		Source           string
+		SourcePropertyID string
+		CompletedAt      time.Time
+		PropertyID       string
+	}
+	err := s.db.Table("detail_scrape_queue AS q").
+		Select("q.id AS queue_item_id, q.source, q.source_property_id, q.completed_at, p.id AS property_id").
+		Joins("JOIN properties p ON p.source = q.source AND p.source_property_id = q.source_property_id").
+		Where("q.status = ? AND q.completed_at >= ?", models.QueueStatusDone, cutoff).
+		Order("q.completed_at DESC").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("load done queue items: %w", err)
+	}
+	if len(rows) == 0 {
+		return []SnapshotGap{}, nil
+	}
+
+	ids := make([]string, 0, len(rows))
+	for _, r := range rows {
+		ids = append(ids, r.PropertyID)
+	}
+
+	// Existing snapshot days for those properties in one query
+	var snapshots []models.PropertySnapshot
+	if err := s.db.Select("property_id", "snapshot_at").
+		Where("property_id IN ? AND snapshot_at >= ?", ids, cutoff).
+		Find(&snapshots).Error; err != nil {
+		return nil, fmt.Errorf("load snapshots: %w", err)
+	}
+	have := make(map[string]bool, len(snapshots))
+	for _, snap := range snapshots {
+		have[snap.PropertyID+"|"+snap.SnapshotAt.Format("2006-01-02")] = true
+	}
+
+	gaps := []SnapshotGap{}
+	seen := make(map[string]bool)
+	for _, r := range rows {
+		day := snapshotDay(r.CompletedAt).Format("2006-01-02")
+		key := r.PropertyID + "|" + day
+		if have[key] || seen[key] {
+			continue
+		}
+		seen[key] = true
+		// Interest-weighted mode skips unchanged snapshots on purpose; those are not gaps
+		if s.skipUnchangedSnapshot(r.PropertyID) {
+			continue
+		}
+		gaps = append(gaps, SnapshotGap{
+			PropertyID:       r.PropertyID,
+			Source:           r.Source,
+			SourcePropertyID: r.SourcePropertyID,
+			QueueItemID:      r.QueueItemID,
+			CompletedAt:      r.CompletedAt,
+			Day:              day,
+		})
+	}
+	return gaps, nil
+}
+
+// RepairSnapshotGaps regenerates the missing snapshots from the current property rows.
+// Change detection is not replayed; the snapshot is marked as repaired in its change note.
+func (s *Service) RepairSnapshotGaps(gaps []SnapshotGap) (int, error) {
+	repaired := 0
+	for _, gap := range gaps {
+		var property models.Property
+		if err := s.db.Where("id = ?", gap.PropertyID).First(&property).Error; err != nil {
+			return repaired, fmt.Errorf("load property %s: %w", gap.PropertyID, err)
+		}
+
+		day, err := time.Parse("2006-01-02", gap.Day)
+		if err != nil {
+			return repaired, err
+		}
+
+		snapshot := &models.PropertySnapshot{
+			PropertyID:  property.ID,
+			SnapshotAt:  day,
+			Rent:        property.Rent,
+			FloorPlan:   property.FloorPlan,
+			Area:        property.Area,
+			WalkTime:    property.WalkTime,
+			BusMinutes:  property.BusMinutes,
+			Station:     property.Station,
+			Address:     property.Address,
+			BuildingAge: property.BuildingAge,
+			Floor:       property.Floor,
+			ImageURL:    property.ImageURL,
+			Status:      string(property.Status),
+
+			Latitude:  property.Latitude,
+			Longitude: property.Longitude,
+
+			ManagementFeeYen:    property.ManagementFeeYen,
+			DepositYen:          property.DepositYen,
+			KeyMoneyYen:         property.KeyMoneyYen,
+			GuarantorDepositYen: property.GuarantorDepositYen,
+			SecurityDepositYen:  property.SecurityDepositYen,
+			FreeRent:            property.FreeRent,
+			FreeRentMonths:      property.FreeRentMonths,
+			NoBrokerageFee:      property.NoBrokerageFee,
+			IsFixedTermLease:    property.IsFixedTermLease,
+			MoveInDate:          property.MoveInDate,
+			ContractPeriod:      property.ContractPeriod,
+			Conditions:          property.Conditions,
+			Parking:             property.Parking,
+			ManualFields:        strings.Join(property.GetLockedFields(), ","),
+			ChangeNote:          "repaired from current property row (snapshot gap)",
+		}
+		if err := s.db.Create(snapshot).Error; err != nil {
+			return repaired, fmt.Errorf("create snapshot %s@%s: %w", gap.PropertyID, gap.Day, err)
+		}
+		repaired++
+	}
+	return repaired, nil
+}
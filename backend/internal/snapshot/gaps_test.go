@@ -0,0 +1,142 @@
+package snapshot_test
+
+import (
+	"fmt"
+	"real-estate-portal/internal/database"
+	"real-estate-portal/internal/models"
+	"real-estate-portal/internal/snapshot"
+	"real-estate-portal/internal/sqlitetest"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// gapListing saves a property and a queue item for it in the given status, completed at `completed`
+func gapListing(t *testing.T, db *gorm.DB, id, status string, completed time.Time) *models.Property {
+	t.Helper()
+	rent := 90000
+	p := &models.Property{ID: id, Source: "yahoo", SourcePropertyID: "src-" + id,
+		DetailURL: "https://realestate.example/rent/detail/" + id + "/", Title: "ギャップ " + id,
+		Rent: &rent, FloorPlan: "1LDK", Status: models.PropertyStatusActive}
+	if err := database.NewGormDBFromDB(db).SaveProperty(p); err != nil {
+		t.Fatalf("save %s: %v", id, err)
+	}
+	item := models.DetailScrapeQueue{Source: p.Source, SourcePropertyID: p.SourcePropertyID,
+		DetailURL: p.DetailURL, Status: status, CompletedAt: &completed}
+	if err := db.Create(&item).Error; err != nil {
+		t.Fatalf("queue item %s: %v", id, err)
+	}
+	return p
+}
+
+func gapIDs(gaps []snapshot.SnapshotGap) string {
+	ids := make([]string, len(gaps))
+	for i, g := range gaps {
+		ids[i] = g.PropertyID + "@" + g.Day
+	}
+	sort.Strings(ids)
+	return strings.Join(ids, ",")
+}
+
+// A done queue item without a same-day snapshot is reported, and repair fills it in
+func TestSnapshotGapsFoundAndRepaired(t *testing.T) {
+	db := sqlitetest.Open(t)
+	service := snapshot.NewService(db)
+
+	today := time.Now().Truncate(24 * time.Hour)
+	todayStr := today.Format("2006-01-02")
+	yesterday := today.AddDate(0, 0, -1)
+
+	// Done today, snapshot failed: the gap
+	missing := gapListing(t, db, "gap-missing", models.QueueStatusDone, today.Add(time.Minute))
+	// Done twice today, no snapshot: one gap, not two
+	twice := gapListing(t, db, "gap-twice", models.QueueStatusDone, today.Add(2*time.Minute))
+	if err := db.Create(&models.DetailScrapeQueue{Source: twice.Source, SourcePropertyID: twice.SourcePropertyID,
+		DetailURL: twice.DetailURL, Status: models.QueueStatusDone, CompletedAt: ptrTime(today.Add(3 * time.Minute))}).Error; err != nil {
+		t.Fatal(err)
+	}
+	// Done today with its snapshot: fine
+	ok := gapListing(t, db, "gap-ok", models.QueueStatusDone, today.Add(time.Minute))
+	if err := service.CreateSnapshot(ok); err != nil {
+		t.Fatalf("snapshot: %v", err)
+	}
+	// Done today, but only yesterday's snapshot exists: still a gap for today
+	stale := gapListing(t, db, "gap-stale", models.QueueStatusDone, today.Add(time.Minute))
+	if err := service.CreateSnapshot(stale); err != nil {
+		t.Fatalf("snapshot: %v", err)
+	}
+	if err := db.Model(&models.PropertySnapshot{}).Where("property_id = ?", stale.ID).
+		Update("snapshot_at", yesterday).Error; err != nil {
+		t.Fatal(err)
+	}
+	// Not done, or done outside the window: ignored
+	gapListing(t, db, "gap-pending", models.QueueStatusPending, today.Add(time.Minute))
+	gapListing(t, db, "gap-old", models.QueueStatusDone, today.AddDate(0, 0, -30))
+
+	gaps, err := service.FindSnapshotGaps(7)
+	if err != nil {
+		t.Fatalf("find: %v", err)
+	}
+	want := fmt.Sprintf("%s@%s,%s@%s,%s@%s", missing.ID, todayStr, stale.ID, todayStr, twice.ID, todayStr)
+	if got := gapIDs(gaps); got != want {
+		t.Fatalf("gaps = %s (want %s)", got, want)
+	}
+	for _, g := range gaps {
+		if g.Source != "yahoo" || g.SourcePropertyID != "src-"+g.PropertyID || g.QueueItemID == 0 {
+			t.Errorf("gap %+v: missing queue item details", g)
+		}
+	}
+
+	repaired, err := service.RepairSnapshotGaps(gaps)
+	if err != nil {
+		t.Fatalf("repair: %v", err)
+	}
+	if repaired != len(gaps) {
+		t.Errorf("repaired %d (want %d)", repaired, len(gaps))
+	}
+
+	var snap models.PropertySnapshot
+	if err := db.Where("property_id = ?", missing.ID).First(&snap).Error; err != nil {
+		t.Fatalf("repaired snapshot: %v", err)
+	}
+	if snap.SnapshotAt.Format("2006-01-02") != todayStr || snap.Rent == nil || *snap.Rent != 90000 || snap.ChangeNote == "" {
+		t.Errorf("repaired snapshot = day %s rent %v note %q", snap.SnapshotAt.Format("2006-01-02"), snap.Rent, snap.ChangeNote)
+	}
+
+	after, err := service.FindSnapshotGaps(7)
+	if err != nil {
+		t.Fatalf("find after repair: %v", err)
+	}
+	if len(after) != 0 {
+		t.Errorf("gaps after repair = %s (want none)", gapIDs(after))
+	}
+}
+
+// With interest-weighted snapshots, a skipped unchanged snapshot is not a gap
+func TestSnapshotGapsIgnoreInterestSkips(t *testing.T) {
+	snapshot.SetInterestPolicy(snapshot.InterestPolicy{Enabled: true})
+	t.Cleanup(func() { snapshot.SetInterestPolicy(snapshot.InterestPolicy{}) })
+
+	db := sqlitetest.Open(t)
+	service := snapshot.NewService(db)
+	today := time.Now().Truncate(24 * time.Hour)
+
+	idle := gapListing(t, db, "gap-idle", models.QueueStatusDone, today.Add(time.Minute))
+	watched := gapListing(t, db, "gap-watched", models.QueueStatusDone, today.Add(time.Minute))
+	if _, err := database.NewGormDBFromDB(db).AdjustFavoriteCount(watched.ID, 1); err != nil {
+		t.Fatalf("favorite: %v", err)
+	}
+
+	gaps, err := service.FindSnapshotGaps(7)
+	if err != nil {
+		t.Fatalf("find: %v", err)
+	}
+	if got, want := gapIDs(gaps), watched.ID+"@"+today.Format("2006-01-02"); got != want {
+		t.Errorf("gaps = %s (want %s; %s has no interest)", got, want, idle.ID)
+	}
+}
+
+func ptrTime(t time.Time) *time.Time { return &t }
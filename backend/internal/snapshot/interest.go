@@ -0,0 +1,104 @@
+package snapshot
+
+import (
+	"fmt"
+	"log"
+	"real-estate-portal/internal/models"
+	"sync/atomic"
+	"time"
+)
+
+// InterestPolicy controls interest-weighted snapshotting. When enabled, properties
+// nobody has viewed (MinViews within Window) or favorited only get a snapshot when
+// a change is detected; the no-change daily snapshot is skipped.
+type InterestPolicy struct {
+	Enabled  bool
+	Window   time.Duration // view window (default 30 days)
+	MinViews int           // views within Window needed to keep full history (default 1)
+}
+
+// Interest is what interest-weighted snapshotting knows about one property
+type Interest struct {
+	RecentViews int `json:"recent_views"`
+	Favorites   int `json:"favorites"`
+}
+
+// DefaultInterestPolicy is used for unset fields (mode itself stays off)
+var DefaultInterestPolicy = InterestPolicy{
+	Window:   30 * 24 * time.Hour,
+	MinViews: 1,
+}
+
+var interestPolicy = DefaultInterestPolicy
+
+// skippedUnchanged counts no-change snapshots skipped for low-interest properties
+var skippedUnchanged int64
+
+// SetInterestPolicy sets the process-wide policy (call once at startup)
+func SetInterestPolicy(p InterestPolicy) {
+	if p.Window <= 0 {
+		p.Window = DefaultInterestPolicy.Window
+	}
+	if p.MinViews <= 0 {
+		p.MinViews = DefaultInterestPolicy.MinViews
+	}
+	interestPolicy = p
+	if p.Enabled {
+		log.Printf("[Snapshot] Interest-weighted snapshotting enabled (window=%v, min_views=%d)", p.Window, p.MinViews)
+	}
+}
+
+// GetInterestPolicy returns the current policy
+func GetInterestPolicy() InterestPolicy {
+	return interestPolicy
+}
+
+// SkippedUnchangedCount returns how many no-change snapshots were skipped since startup
+func SkippedUnchangedCount() int64 {
+	return atomic.LoadInt64(&skippedUnchanged)
+}
+
+// KeepUnchanged reports whether a snapshot with no detected changes should still be written
+func (p InterestPolicy) KeepUnchanged(in Interest) bool {
+	if !p.Enabled {
+		return true
+	}
+	return in.Favorites > 0 || in.RecentViews >= p.MinViews
+}
+
+// GetInterest loads recent views and favorites for a property
+func (s *Service) GetInterest(propertyID string) (Interest, error) {
+	var in Interest
+	since := time.Now().Add(-interestPolicy.Window).Format("2006-01-02")
+
+	err := s.db.Model(&models.PropertyView{}).
+		Where("property_id = ? AND day >= ?", propertyID, since).
+		Select("COALESCE(SUM(views), 0)").
+		Scan(&in.RecentViews).Error
+	if err != nil {
+		return in, fmt.Errorf("load views: %w", err)
+	}
+
+	err = s.db.Model(&models.PropertyFavorite{}).
+		Where("property_id = ?", propertyID).
+		Select("COALESCE(MAX(`count`), 0)").
+		Scan(&in.Favorites).Error
+	if err != nil {
+		return in, fmt.Errorf("load favorites: %w", err)
+	}
+	return in, nil
+}
+
+// skipUnchangedSnapshot reports whether the no-change snapshot for a property can be skipped.
+// Lookup errors keep the snapshot (full history is the safe default).
+func (s *Service) skipUnchangedSnapshot(propertyID string) bool {
+	if !interestPolicy.Enabled {
+		return false
+	}
+	in, err := s.GetInterest(propertyID)
+	if err != nil {
+		log.Printf("Warning: Failed to load interest for property %s: %v", propertyID, err)
+		return false
+	}
+	return !interestPolicy.KeepUnchanged(in)
+}
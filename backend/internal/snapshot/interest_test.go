@@ -0,0 +1,113 @@
+package snapshot_test
+
+import (
+	"real-estate-portal/internal/database"
+	"real-estate-portal/internal/models"
+	"real-estate-portal/internal/snapshot"
+	"real-estate-portal/internal/sqlitetest"
+	"testing"
+	"time"
+)
+
+// With interest-weighted snapshotting on, an unchanged listing nobody views or favorites gets
+// no new daily snapshot; interest or a change keeps it
+func TestInterestWeightedSnapshots(t *testing.T) {
+	snapshot.SetInterestPolicy(snapshot.InterestPolicy{Enabled: true})
+	t.Cleanup(func() { snapshot.SetInterestPolicy(snapshot.InterestPolicy{}) })
+
+	tests := []struct {
+		name     string
+		interest func(t *testing.T, gdb *database.GormDB, id string)
+		rent     int // rent at today's scrape (yesterday's is 80000)
+		wantKept bool
+	}{
+		{"no views or favorites", nil, 80000, false},
+		{"favorited", func(t *testing.T, gdb *database.GormDB, id string) {
+			if _, err := gdb.AdjustFavoriteCount(id, 1); err != nil {
+				t.Fatalf("favorite: %v", err)
+			}
+		}, 80000, true},
+		{"favorite taken back", func(t *testing.T, gdb *database.GormDB, id string) {
+			gdb.AdjustFavoriteCount(id, 1)
+			if n, err := gdb.AdjustFavoriteCount(id, -1); err != nil || n != 0 {
+				t.Fatalf("unfavorite: %d, %v", n, err)
+			}
+		}, 80000, false},
+		{"viewed", func(t *testing.T, gdb *database.GormDB, id string) {
+			vc := database.NewViewCounter(gdb)
+			vc.Record(id)
+			if err := vc.Flush(); err != nil {
+				t.Fatalf("flush views: %v", err)
+			}
+		}, 80000, true},
+		{"rent changed", nil, 78000, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := sqlitetest.Open(t)
+			gdb := database.NewGormDBFromDB(db)
+			service := snapshot.NewService(db)
+
+			rent := 80000
+			p := &models.Property{ID: "interest-01", Source: "yahoo", SourcePropertyID: "interest01",
+				DetailURL: "https://realestate.example/rent/detail/interest01/", Title: "関心テスト",
+				Rent: &rent, FloorPlan: "1K", Status: models.PropertyStatusActive}
+			if err := gdb.SaveProperty(p); err != nil {
+				t.Fatalf("save: %v", err)
+			}
+			// Yesterday's snapshot of the same listing
+			if err := service.CreateSnapshot(p); err != nil {
+				t.Fatalf("first snapshot: %v", err)
+			}
+			yesterday := time.Now().Truncate(24*time.Hour).AddDate(0, 0, -1)
+			if err := db.Model(&models.PropertySnapshot{}).Where("property_id = ?", p.ID).
+				Update("snapshot_at", yesterday).Error; err != nil {
+				t.Fatalf("backdate snapshot: %v", err)
+			}
+
+			if tt.interest != nil {
+				tt.interest(t, gdb, p.ID)
+			}
+			skipped := snapshot.SkippedUnchangedCount()
+			today := tt.rent
+			p.Rent = &today
+			if err := service.CreateSnapshotWithChangeDetection(p); err != nil {
+				t.Fatalf("snapshot: %v", err)
+			}
+
+			var n int64
+			db.Model(&models.PropertySnapshot{}).Where("property_id = ?", p.ID).Count(&n)
+			if kept := n == 2; kept != tt.wantKept {
+				t.Errorf("%d snapshots, today's kept=%v (want %v)", n, kept, tt.wantKept)
+			}
+			wantSkipped := int64(1)
+			if tt.wantKept {
+				wantSkipped = 0
+			}
+			if counted := snapshot.SkippedUnchangedCount() - skipped; counted != wantSkipped {
+				t.Errorf("skipped counter moved by %d (want %d)", counted, wantSkipped)
+			}
+		})
+	}
+}
+
+func TestInterestPolicyKeepUnchanged(t *testing.T) {
+	policy := snapshot.InterestPolicy{Enabled: true, Window: 30 * 24 * time.Hour, MinViews: 3}
+	tests := []struct {
+		name     string
+		policy   snapshot.InterestPolicy
+		interest snapshot.Interest
+		want     bool
+	}{
+		{"zero views", policy, snapshot.Interest{}, false},
+		{"favorited", policy, snapshot.Interest{Favorites: 1}, true},
+		{"enough views", policy, snapshot.Interest{RecentViews: 3}, true},
+		{"too few views", policy, snapshot.Interest{RecentViews: 2}, false},
+		{"mode off keeps full history", snapshot.InterestPolicy{}, snapshot.Interest{}, true},
+	}
+	for _, tt := range tests {
+		if got := tt.policy.KeepUnchanged(tt.interest); got != tt.want {
+			t.Errorf("%s: keep=%v (want %v)", tt.name, got, tt.want)
+		}
+	}
+}
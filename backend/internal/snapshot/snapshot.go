@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"log"
 	"real-estate-portal/internal/models"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"gorm.io/gorm"
@@ -21,21 +23,7 @@ func NewService(db *gorm.DB) *Service {
 
 // CreateSnapshot creates a snapshot of a property
 func (s *Service) CreateSnapshot(property *models.Property) error {
-	snapshot := &models.PropertySnapshot{
-		PropertyID:  property.ID,
-		SnapshotAt:  time.Now().Truncate(24 * time.Hour), // Truncate to date only
-		Rent:        property.Rent,
-		FloorPlan:   property.FloorPlan,
-		Area:        property.Area,
-		WalkTime:    property.WalkTime,
-		Station:     property.Station,
-		Address:     property.Address,
-		BuildingAge: property.BuildingAge,
-		Floor:       property.Floor,
-		ImageURL:    property.ImageURL,
-		Status:      string(property.Status),
-		HasChanged:  false,
-	}
+	snapshot := snapshotOf(property)
 
 	// Check if snapshot already exists for today
 	var existing models.PropertySnapshot
@@ -75,112 +63,256 @@ func (s *Service) DetectChanges(property *models.Property) ([]models.PropertyCha
 		return nil, result.Error
 	}
 
-	// Compare and detect changes
+	return CompareSnapshots(property.ID, &lastSnapshot, snapshotOf(property), time.Now()), nil
+}
+
+// detectPartialChanges compares a light refresh's partial snapshot with the most recent earlier
+// one. Without an earlier snapshot nothing is reported: a light refresh never announces a new property.
+func (s *Service) detectPartialChanges(cur *models.PropertySnapshot) ([]models.PropertyChange, error) {
+	var lastSnapshot []models.PropertySnapshot
+	err := s.db.Where("property_id = ? AND snapshot_at < ?", cur.PropertyID, cur.SnapshotAt).
+		Order("snapshot_at DESC").
+		Limit(1).
+		Find(&lastSnapshot).Error
+	if err != nil || len(lastSnapshot) == 0 {
+		return nil, err
+	}
+	return CompareSnapshots(cur.PropertyID, &lastSnapshot[0], cur, time.Now()), nil
+}
+
+// snapshotOf captures the compared state of a property as an (unsaved) snapshot
+func snapshotOf(property *models.Property) *models.PropertySnapshot {
+	return &models.PropertySnapshot{
+		PropertyID:  property.ID,
+		SnapshotAt:  time.Now().Truncate(24 * time.Hour), // Truncate to date only
+		Rent:        property.Rent,
+		FloorPlan:   property.FloorPlan,
+		Area:        property.Area,
+		WalkTime:    property.WalkTime,
+		BusMinutes:  property.BusMinutes,
+		Station:     property.Station,
+		Address:     property.Address,
+		BuildingAge: property.BuildingAge,
+		Floor:       property.Floor,
+		ImageURL:    property.ImageURL,
+		Status:      string(property.Status),
+
+		Latitude:  property.Latitude,
+		Longitude: property.Longitude,
+
+		ManagementFeeYen:    property.ManagementFeeYen,
+		DepositYen:          property.DepositYen,
+		KeyMoneyYen:         property.KeyMoneyYen,
+		GuarantorDepositYen: property.GuarantorDepositYen,
+		SecurityDepositYen:  property.SecurityDepositYen,
+		FreeRent:            property.FreeRent,
+		FreeRentMonths:      property.FreeRentMonths,
+		NoBrokerageFee:      property.NoBrokerageFee,
+		IsFixedTermLease:    property.IsFixedTermLease,
+		MoveInDate:          property.MoveInDate,
+		ContractPeriod:      property.ContractPeriod,
+		Conditions:          property.Conditions,
+		Parking:             property.Parking,
+		ManualFields:        strings.Join(property.GetLockedFields(), ","),
+	}
+}
+
+// CompareSnapshots returns the changes from old to cur. This is the single comparison
+// used by change detection (DetectChanges) and the snapshot diff API. A partial cur (light
+// refresh) is only compared on what it observed: rent and status.
+func CompareSnapshots(propertyID string, old, cur *models.PropertySnapshot, detectedAt time.Time) []models.PropertyChange {
 	changes := []models.PropertyChange{}
+	full := !cur.Partial
 
 	// Rent change
-	if !intPtrEqual(property.Rent, lastSnapshot.Rent) {
+	if !intPtrEqual(cur.Rent, old.Rent) {
 		oldVal := "nil"
 		newVal := "nil"
 		var magnitude float64
 
-		if lastSnapshot.Rent != nil {
-			oldVal = fmt.Sprintf("%d", *lastSnapshot.Rent)
+		if old.Rent != nil {
+			oldVal = fmt.Sprintf("%d", *old.Rent)
 		}
-		if property.Rent != nil {
-			newVal = fmt.Sprintf("%d", *property.Rent)
+		if cur.Rent != nil {
+			newVal = fmt.Sprintf("%d", *cur.Rent)
 		}
 
-		if lastSnapshot.Rent != nil && property.Rent != nil {
-			magnitude = float64(*property.Rent - *lastSnapshot.Rent)
+		if old.Rent != nil && cur.Rent != nil {
+			magnitude = float64(*cur.Rent - *old.Rent)
 		}
 
 		changes = append(changes, models.PropertyChange{
-			PropertyID:      property.ID,
+			PropertyID:      propertyID,
 			ChangeType:      models.ChangeTypeRent,
 			OldValue:        oldVal,
 			NewValue:        newVal,
 			ChangeMagnitude: &magnitude,
-			DetectedAt:      time.Now(),
+			DetectedAt:      detectedAt,
 		})
 	}
 
 	// Status change
-	if string(property.Status) != lastSnapshot.Status {
+	if cur.Status != old.Status {
 		changes = append(changes, models.PropertyChange{
-			PropertyID: property.ID,
+			PropertyID: propertyID,
 			ChangeType: models.ChangeTypeStatus,
-			OldValue:   lastSnapshot.Status,
-			NewValue:   string(property.Status),
-			DetectedAt: time.Now(),
+			OldValue:   old.Status,
+			NewValue:   cur.Status,
+			DetectedAt: detectedAt,
 		})
 	}
 
 	// Floor plan change
-	if property.FloorPlan != lastSnapshot.FloorPlan {
+	if full && cur.FloorPlan != old.FloorPlan {
 		changes = append(changes, models.PropertyChange{
-			PropertyID: property.ID,
+			PropertyID: propertyID,
 			ChangeType: models.ChangeTypeFloorPlan,
-			OldValue:   lastSnapshot.FloorPlan,
-			NewValue:   property.FloorPlan,
-			DetectedAt: time.Now(),
+			OldValue:   old.FloorPlan,
+			NewValue:   cur.FloorPlan,
+			DetectedAt: detectedAt,
 		})
 	}
 
 	// Area change
-	if !float64PtrEqual(property.Area, lastSnapshot.Area) {
+	if full && !float64PtrEqual(cur.Area, old.Area) {
 		oldVal := "nil"
 		newVal := "nil"
 
-		if lastSnapshot.Area != nil {
-			oldVal = fmt.Sprintf("%.2f", *lastSnapshot.Area)
+		if old.Area != nil {
+			oldVal = fmt.Sprintf("%.2f", *old.Area)
 		}
-		if property.Area != nil {
-			newVal = fmt.Sprintf("%.2f", *property.Area)
+		if cur.Area != nil {
+			newVal = fmt.Sprintf("%.2f", *cur.Area)
 		}
 
-		changes = append(changes, models.PropertyChange{
-			PropertyID: property.ID,
+		change := models.PropertyChange{
+			PropertyID: propertyID,
 			ChangeType: models.ChangeTypeArea,
 			OldValue:   oldVal,
 			NewValue:   newVal,
-			DetectedAt: time.Now(),
-		})
+			DetectedAt: detectedAt,
+		}
+		if old.Area != nil && cur.Area != nil {
+			magnitude := *cur.Area - *old.Area
+			change.ChangeMagnitude = &magnitude
+		}
+		changes = append(changes, change)
 	}
 
 	// Building age change
-	if !intPtrEqual(property.BuildingAge, lastSnapshot.BuildingAge) {
+	if full && !intPtrEqual(cur.BuildingAge, old.BuildingAge) {
 		oldVal := "nil"
 		newVal := "nil"
 
-		if lastSnapshot.BuildingAge != nil {
-			oldVal = fmt.Sprintf("%d", *lastSnapshot.BuildingAge)
+		if old.BuildingAge != nil {
+			oldVal = fmt.Sprintf("%d", *old.BuildingAge)
 		}
-		if property.BuildingAge != nil {
-			newVal = fmt.Sprintf("%d", *property.BuildingAge)
+		if cur.BuildingAge != nil {
+			newVal = fmt.Sprintf("%d", *cur.BuildingAge)
 		}
 
-		changes = append(changes, models.PropertyChange{
-			PropertyID: property.ID,
+		change := models.PropertyChange{
+			PropertyID: propertyID,
 			ChangeType: models.ChangeTypeBuildingAge,
 			OldValue:   oldVal,
 			NewValue:   newVal,
-			DetectedAt: time.Now(),
-		})
+			DetectedAt: detectedAt,
+		}
+		if old.BuildingAge != nil && cur.BuildingAge != nil {
+			magnitude := float64(*cur.BuildingAge - *old.BuildingAge)
+			change.ChangeMagnitude = &magnitude
+		}
+		changes = append(changes, change)
 	}
 
 	// Image change
-	if property.ImageURL != lastSnapshot.ImageURL {
+	if full && cur.ImageURL != old.ImageURL {
 		changes = append(changes, models.PropertyChange{
-			PropertyID: property.ID,
+			PropertyID: propertyID,
 			ChangeType: models.ChangeTypeImage,
-			OldValue:   lastSnapshot.ImageURL,
-			NewValue:   property.ImageURL,
-			DetectedAt: time.Now(),
+			OldValue:   old.ImageURL,
+			NewValue:   cur.ImageURL,
+			DetectedAt: detectedAt,
 		})
 	}
 
-	return changes, nil
+	// Campaign start/end (free rent, brokerage fee waiver)
+	if full && (cur.FreeRent != old.FreeRent ||
+		!intPtrEqual(cur.FreeRentMonths, old.FreeRentMonths) ||
+		cur.NoBrokerageFee != old.NoBrokerageFee) {
+		changes = append(changes, models.PropertyChange{
+			PropertyID: propertyID,
+			ChangeType: models.ChangeTypeCampaign,
+			OldValue:   campaignSummary(old.FreeRent, old.FreeRentMonths, old.NoBrokerageFee),
+			NewValue:   campaignSummary(cur.FreeRent, cur.FreeRentMonths, cur.NoBrokerageFee),
+			DetectedAt: detectedAt,
+		})
+	}
+
+	// Lease type (普通借家 <-> 定期借家)
+	if full && cur.IsFixedTermLease != old.IsFixedTermLease {
+		changes = append(changes, models.PropertyChange{
+			PropertyID: propertyID,
+			ChangeType: models.ChangeTypeLeaseType,
+			OldValue:   leaseTypeSummary(old.IsFixedTermLease),
+			NewValue:   leaseTypeSummary(cur.IsFixedTermLease),
+			DetectedAt: detectedAt,
+		})
+	}
+
+	// Access to the nearest station (徒歩 or バス minutes); snapshots taken before bus_minutes
+	// existed have neither for bus-only listings, so an unknown old access is not a change.
+	// walk_time_bucket is derived from walk_time and never gets a change row of its own.
+	if full && (old.WalkTime != nil || old.BusMinutes != nil) &&
+		(!intPtrEqual(cur.WalkTime, old.WalkTime) || !intPtrEqual(cur.BusMinutes, old.BusMinutes)) {
+		changes = append(changes, models.PropertyChange{
+			PropertyID: propertyID,
+			ChangeType: models.ChangeTypeAccess,
+			OldValue:   accessSummary(old.WalkTime, old.BusMinutes),
+			NewValue:   accessSummary(cur.WalkTime, cur.BusMinutes),
+			DetectedAt: detectedAt,
+		})
+	}
+
+	return changes
+}
+
+// accessSummary renders the nearest-station access for change records (e.g. "walk=7m", "bus=10m")
+func accessSummary(walkTime, busMinutes *int) string {
+	switch {
+	case walkTime != nil:
+		return fmt.Sprintf("walk=%dm", *walkTime)
+	case busMinutes != nil:
+		return fmt.Sprintf("bus=%dm", *busMinutes)
+	}
+	return "none"
+}
+
+// leaseTypeSummary renders the lease type for change records
+func leaseTypeSummary(fixedTerm bool) string {
+	if fixedTerm {
+		return "fixed_term"
+	}
+	return "standard"
+}
+
+// campaignSummary renders campaign flags for change records (e.g. "free_rent=1m,no_brokerage_fee")
+func campaignSummary(freeRent bool, freeRentMonths *int, noBrokerageFee bool) string {
+	parts := []string{}
+	if freeRent {
+		if freeRentMonths != nil {
+			parts = append(parts, fmt.Sprintf("free_rent=%dm", *freeRentMonths))
+		} else {
+			parts = append(parts, "free_rent")
+		}
+	}
+	if noBrokerageFee {
+		parts = append(parts, "no_brokerage_fee")
+	}
+	if len(parts) == 0 {
+		return "none"
+	}
+	return strings.Join(parts, ",")
 }
 
 // SaveChanges saves detected changes to the database
@@ -199,29 +331,41 @@ func (s *Service) SaveChanges(changes []models.PropertyChange, snapshotID uint)
 
 // CreateSnapshotWithChangeDetection creates a snapshot and detects changes
 func (s *Service) CreateSnapshotWithChangeDetection(property *models.Property) error {
+	return s.createSnapshotWithChangeDetection(property, false)
+}
+
+// CreatePartialSnapshotWithChangeDetection records a light refresh: property is the stored
+// listing with the refreshed rent applied. The snapshot is marked partial so only the rent is
+// compared (the carried-over fields are never reported as changed or removed), and it never
+// replaces the day's full snapshot, whose rent is updated instead.
+func (s *Service) CreatePartialSnapshotWithChangeDetection(property *models.Property) error {
+	return s.createSnapshotWithChangeDetection(property, true)
+}
+
+func (s *Service) createSnapshotWithChangeDetection(property *models.Property, partial bool) error {
+	snapshot := snapshotOf(property)
+	snapshot.Partial = partial
+
 	// Detect changes first
-	changes, err := s.DetectChanges(property)
+	var changes []models.PropertyChange
+	var err error
+	if partial {
+		changes, err = s.detectPartialChanges(snapshot)
+	} else {
+		changes, err = s.DetectChanges(property)
+	}
 	if err != nil {
 		log.Printf("Warning: Failed to detect changes for property %s: %v", property.ID, err)
 	}
 
-	// Create snapshot
-	snapshot := &models.PropertySnapshot{
-		PropertyID:  property.ID,
-		SnapshotAt:  time.Now().Truncate(24 * time.Hour),
-		Rent:        property.Rent,
-		FloorPlan:   property.FloorPlan,
-		Area:        property.Area,
-		WalkTime:    property.WalkTime,
-		Station:     property.Station,
-		Address:     property.Address,
-		BuildingAge: property.BuildingAge,
-		Floor:       property.Floor,
-		ImageURL:    property.ImageURL,
-		Status:      string(property.Status),
-		HasChanged:  len(changes) > 0,
+	// Interest-weighted mode: listings nobody views or favorites only keep change snapshots
+	if err == nil && len(changes) == 0 && s.skipUnchangedSnapshot(property.ID) {
+		atomic.AddInt64(&skippedUnchanged, 1)
+		return nil
 	}
 
+	snapshot.HasChanged = len(changes) > 0
+
 	if len(changes) > 0 {
 		changeNotes := []string{}
 		for _, change := range changes {
@@ -241,6 +385,17 @@ func (s *Service) CreateSnapshotWithChangeDetection(property *models.Property) e
 		}
 	} else if result.Error != nil {
 		return result.Error
+	} else if partial && !existing.Partial {
+		// Keep today's full snapshot; only what the light refresh observed is updated
+		existing.Rent = snapshot.Rent
+		if snapshot.HasChanged {
+			existing.HasChanged = true
+			existing.ChangeNote = snapshot.ChangeNote
+		}
+		snapshot = &existing
+		if err := s.db.Save(snapshot).Error; err != nil {
+			return err
+		}
 	} else {
 		// Update existing snapshot
 		snapshot.ID = existing.ID
@@ -279,9 +434,18 @@ func (s *Service) GetPropertyHistory(propertyID string, limit int) ([]models.Pro
 
 // GetRecentChanges retrieves recent property changes
 func (s *Service) GetRecentChanges(limit int) ([]models.PropertyChange, error) {
+	return s.GetRecentChangesByType(limit, nil)
+}
+
+// GetRecentChangesByType retrieves recent changes, optionally limited to the given change types
+func (s *Service) GetRecentChangesByType(limit int, changeTypes []string) ([]models.PropertyChange, error) {
 	var changes []models.PropertyChange
 	query := s.db.Order("detected_at DESC")
 
+	if len(changeTypes) > 0 {
+		query = query.Where("change_type IN ?", changeTypes)
+	}
+
 	if limit > 0 {
 		query = query.Limit(limit)
 	}
@@ -0,0 +1,81 @@
+package snapshot_test
+
+import (
+	"real-estate-portal/internal/database"
+	"real-estate-portal/internal/models"
+	"real-estate-portal/internal/snapshot"
+	"real-estate-portal/internal/sqlitetest"
+	"testing"
+	"time"
+)
+
+// Switching 普通借家 -> 定期借家 is reported as lease_type_changed
+func TestCompareSnapshotsLeaseType(t *testing.T) {
+	tests := []struct {
+		name     string
+		old, cur bool
+		want     []string // old/new value pairs
+	}{
+		{"standard to fixed term", false, true, []string{"standard", "fixed_term"}},
+		{"fixed term to standard", true, false, []string{"fixed_term", "standard"}},
+		{"unchanged", true, true, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			old := &models.PropertySnapshot{Status: "active", IsFixedTermLease: tt.old}
+			cur := &models.PropertySnapshot{Status: "active", IsFixedTermLease: tt.cur}
+			changes := snapshot.CompareSnapshots("lease", old, cur, time.Now())
+			if tt.want == nil {
+				if len(changes) != 0 {
+					t.Errorf("changes = %+v (want none)", changes)
+				}
+				return
+			}
+			if len(changes) != 1 || changes[0].ChangeType != models.ChangeTypeLeaseType ||
+				changes[0].OldValue != tt.want[0] || changes[0].NewValue != tt.want[1] {
+				t.Errorf("changes = %+v (want one %s %s -> %s)", changes, models.ChangeTypeLeaseType, tt.want[0], tt.want[1])
+			}
+		})
+	}
+}
+
+// The fee amounts computed by NormalizeFees are carried into the snapshot row
+func TestCreateSnapshotFees(t *testing.T) {
+	db := sqlitetest.Open(t)
+	rent := 98000
+	p := &models.Property{ID: "fees-01", Source: "yahoo", SourcePropertyID: "fees01",
+		DetailURL: "https://realestate.example/rent/detail/fees01/", Title: "費用テスト", Rent: &rent,
+		ManagementFee: "なし", Deposit: "10,000円", KeyMoney: "1.5ヶ月", GuarantorDeposit: "なし", SecurityDeposit: "1ヶ月"}
+	if err := database.NewGormDBFromDB(db).SaveProperty(p); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	if err := snapshot.NewService(db).CreateSnapshot(p); err != nil {
+		t.Fatalf("snapshot: %v", err)
+	}
+
+	var snap models.PropertySnapshot
+	if err := db.Where("property_id = ?", p.ID).First(&snap).Error; err != nil {
+		t.Fatalf("read snapshot: %v", err)
+	}
+	for _, f := range []struct {
+		name      string
+		got, want *int
+	}{
+		{"management_fee_yen", snap.ManagementFeeYen, p.ManagementFeeYen},
+		{"deposit_yen", snap.DepositYen, p.DepositYen},
+		{"key_money_yen", snap.KeyMoneyYen, p.KeyMoneyYen},
+		{"guarantor_deposit_yen", snap.GuarantorDepositYen, p.GuarantorDepositYen},
+		{"security_deposit_yen", snap.SecurityDepositYen, p.SecurityDepositYen},
+	} {
+		if f.want == nil {
+			t.Errorf("%s: property has no amount", f.name)
+			continue
+		}
+		if f.got == nil || *f.got != *f.want {
+			t.Errorf("%s: snapshot %v, property %d", f.name, f.got, *f.want)
+		}
+	}
+	if *p.KeyMoneyYen != 147000 || *p.DepositYen != 10000 {
+		t.Errorf("key_money_yen=%d deposit_yen=%d (want 147000/10000)", *p.KeyMoneyYen, *p.DepositYen)
+	}
+}
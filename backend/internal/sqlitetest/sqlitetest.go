@@ -0,0 +1,79 @@
+// Package sqlitetest opens throwaway SQLite databases with the app's schema for go tests.
+// Only _test.go files import it, so the SQLite driver never ships in the binaries.
+package sqlitetest
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"path/filepath"
+	"real-estate-portal/internal/database"
+	"real-estate-portal/internal/models"
+	"strings"
+	"sync"
+	"testing"
+
+	gosqlite "github.com/glebarez/go-sqlite"
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+var registerFuncs sync.Once
+
+// Open returns a GORM handle on a fresh SQLite file in the test's temp dir with the schema
+// migrated by InitSchema (plus property_images, which comes from migrations/006). CONCAT and
+// GREATEST, which the schema (active_key) and the queue use as in MySQL, are registered as
+// SQL functions.
+func Open(tb testing.TB) *gorm.DB {
+	tb.Helper()
+	return OpenFile(tb, filepath.Join(tb.TempDir(), "test.db"))
+}
+
+// OpenFile is Open on the database file at path; opening the same path again stands in for
+// a restart of the process (the rows are kept)
+func OpenFile(tb testing.TB, path string) *gorm.DB {
+	tb.Helper()
+	registerFuncs.Do(func() {
+		gosqlite.MustRegisterDeterministicScalarFunction("concat", -1, func(_ *gosqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+			var b strings.Builder
+			for _, arg := range args {
+				if arg == nil {
+					return nil, nil
+				}
+				fmt.Fprint(&b, arg)
+			}
+			return b.String(), nil
+		})
+		gosqlite.MustRegisterDeterministicScalarFunction("greatest", -1, func(_ *gosqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+			var best int64
+			for i, arg := range args {
+				n, ok := arg.(int64)
+				if !ok {
+					return nil, fmt.Errorf("greatest: %T argument", arg)
+				}
+				if i == 0 || n > best {
+					best = n
+				}
+			}
+			return best, nil
+		})
+	})
+
+	dsn := path + "?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)"
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		tb.Fatalf("open sqlite: %v", err)
+	}
+	tb.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	if err := database.NewGormDBFromDB(db).InitSchema(); err != nil {
+		tb.Fatalf("migrate: %v", err)
+	}
+	if err := db.AutoMigrate(&models.PropertyImage{}); err != nil {
+		tb.Fatalf("migrate property_images: %v", err)
+	}
+	return db
+}
@@ -0,0 +1,111 @@
+// Package tracing wires optional OpenTelemetry tracing for the scrape pipeline.
+//
+// When no OTLP endpoint is configured the global no-op tracer provider stays in
+// place, so Start returns non-recording spans and costs next to nothing.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName identifies spans created by this application
+const instrumentationName = "real-estate-portal"
+
+// DefaultServiceName is used when otel.service_name is not set
+const DefaultServiceName = "shiboroom-backend"
+
+// Init installs an OTLP/HTTP exporter for the given endpoint (e.g. http://localhost:4318).
+// With an empty endpoint tracing stays disabled. The returned function flushes and
+// shuts down the exporter; it is always safe to call.
+func Init(endpoint, serviceName string) (func(context.Context) error, error) {
+	noop := func(context.Context) error { return nil }
+	if endpoint == "" {
+		return noop, nil
+	}
+	if serviceName == "" {
+		serviceName = DefaultServiceName
+	}
+
+	var opt otlptracehttp.Option
+	if strings.Contains(endpoint, "://") {
+		opt = otlptracehttp.WithEndpointURL(endpoint)
+	} else {
+		opt = otlptracehttp.WithEndpoint(endpoint)
+	}
+	exporter, err := otlptracehttp.New(context.Background(), opt)
+	if err != nil {
+		return noop, fmt.Errorf("failed to create OTLP exporter: %w", err)
+	}
+
+	res := resource.NewSchemaless(semconv.ServiceName(serviceName))
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	log.Printf("[Tracing] OpenTelemetry enabled (endpoint=%s, service=%s)", endpoint, serviceName)
+	return provider.Shutdown, nil
+}
+
+// Start starts a child span of whatever span ctx carries
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return otel.Tracer(instrumentationName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// RecordError marks the span as failed. A nil error is ignored.
+func RecordError(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
+
+// Middleware creates the root span for each HTTP request and stores it in the
+// request context, so handlers pass c.Request.Context() down the pipeline.
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := otel.GetTextMapPropagator().Extract(c.Request.Context(), propagation.HeaderCarrier(c.Request.Header))
+
+		route := c.FullPath()
+		if route == "" {
+			route = c.Request.URL.Path
+		}
+		ctx, span := otel.Tracer(instrumentationName).Start(ctx, c.Request.Method+" "+route,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				attribute.String("http.method", c.Request.Method),
+				attribute.String("http.route", route),
+			),
+		)
+		defer span.End()
+
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+
+		status := c.Writer.Status()
+		span.SetAttributes(attribute.Int("http.status_code", status))
+		if status >= 500 {
+			span.SetStatus(codes.Error, fmt.Sprintf("HTTP %d", status))
+		}
+	}
+}
@@ -0,0 +1,10 @@
+-- Migration: Add fingerprint and relisted_from for relist detection
+-- Purpose: Link units that were delisted and relisted under a new Yahoo ID
+-- fingerprint = md5(image path | area | floor_plan | address), computed at save time
+
+ALTER TABLE properties
+ADD COLUMN IF NOT EXISTS fingerprint VARCHAR(32) DEFAULT NULL,
+ADD COLUMN IF NOT EXISTS relisted_from VARCHAR(32) DEFAULT NULL;
+
+ALTER TABLE properties ADD INDEX IF NOT EXISTS idx_fingerprint (fingerprint);
+ALTER TABLE properties ADD INDEX IF NOT EXISTS idx_relisted_from (relisted_from);
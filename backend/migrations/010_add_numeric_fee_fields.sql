@@ -0,0 +1,15 @@
+-- Migration: Add numeric companions for fee text fields
+-- Purpose: management_fee / deposit / key_money are raw Japanese text ("5,000円", "1ヶ月", "敷1礼1").
+-- These columns hold normalized values computed at save time (NULL when unparsable).
+
+ALTER TABLE properties
+ADD COLUMN IF NOT EXISTS management_fee_yen INT DEFAULT NULL,
+ADD COLUMN IF NOT EXISTS deposit_months DECIMAL(4,2) DEFAULT NULL,
+ADD COLUMN IF NOT EXISTS deposit_yen INT DEFAULT NULL,
+ADD COLUMN IF NOT EXISTS key_money_months DECIMAL(4,2) DEFAULT NULL,
+ADD COLUMN IF NOT EXISTS key_money_yen INT DEFAULT NULL;
+
+ALTER TABLE property_snapshots
+ADD COLUMN IF NOT EXISTS management_fee_yen INT DEFAULT NULL,
+ADD COLUMN IF NOT EXISTS deposit_yen INT DEFAULT NULL,
+ADD COLUMN IF NOT EXISTS key_money_yen INT DEFAULT NULL;
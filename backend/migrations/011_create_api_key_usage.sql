@@ -0,0 +1,13 @@
+-- Migration: Create api_key_usage table
+-- Purpose: Per-API-key daily counters for scrape/enqueue quotas (survive restarts)
+
+CREATE TABLE IF NOT EXISTS api_key_usage (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    key_name VARCHAR(100) NOT NULL,
+    day VARCHAR(10) NOT NULL,
+    scrape_count INT NOT NULL DEFAULT 0,
+    enqueue_count INT NOT NULL DEFAULT 0,
+    updated_at DATETIME NOT NULL,
+
+    UNIQUE KEY idx_key_day (key_name, day)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
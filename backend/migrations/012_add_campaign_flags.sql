@@ -0,0 +1,14 @@
+-- Migration: Add campaign flags (フリーレント・仲介手数料無料)
+-- Purpose: Campaigns heavily affect effective cost; stored as filterable columns and tracked in snapshots.
+
+ALTER TABLE properties
+ADD COLUMN IF NOT EXISTS free_rent BOOLEAN NOT NULL DEFAULT FALSE,
+ADD COLUMN IF NOT EXISTS free_rent_months INT DEFAULT NULL,
+ADD COLUMN IF NOT EXISTS no_brokerage_fee BOOLEAN NOT NULL DEFAULT FALSE,
+ADD INDEX IF NOT EXISTS idx_free_rent (free_rent),
+ADD INDEX IF NOT EXISTS idx_no_brokerage_fee (no_brokerage_fee);
+
+ALTER TABLE property_snapshots
+ADD COLUMN IF NOT EXISTS free_rent BOOLEAN DEFAULT FALSE,
+ADD COLUMN IF NOT EXISTS free_rent_months INT DEFAULT NULL,
+ADD COLUMN IF NOT EXISTS no_brokerage_fee BOOLEAN DEFAULT FALSE;
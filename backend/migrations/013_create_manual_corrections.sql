@@ -0,0 +1,24 @@
+-- Migration: Manual corrections with audit trail
+-- Purpose: Admins can pin corrected values that survive re-scrapes.
+-- properties.locked_fields holds the JSON array of locked field names; manual_corrections is the audit log.
+
+ALTER TABLE properties
+ADD COLUMN IF NOT EXISTS locked_fields VARCHAR(500) DEFAULT NULL;
+
+ALTER TABLE property_snapshots
+ADD COLUMN IF NOT EXISTS manual_fields VARCHAR(500) DEFAULT NULL;
+
+CREATE TABLE IF NOT EXISTS manual_corrections (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    property_id VARCHAR(32) NOT NULL,
+    field VARCHAR(50) NOT NULL,
+    action VARCHAR(20) NOT NULL,
+    old_value TEXT,
+    new_value TEXT,
+    author VARCHAR(100),
+    reason TEXT,
+    created_at DATETIME NOT NULL,
+
+    INDEX idx_manual_corrections_property (property_id),
+    INDEX idx_manual_corrections_created (created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,20 @@
+-- Migration: Create property_views and property_favorites tables
+-- Purpose: Interest signals for interest-weighted snapshotting
+--          (listings nobody views or favorites only get snapshots when something changes)
+
+CREATE TABLE IF NOT EXISTS property_views (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    property_id VARCHAR(32) NOT NULL,
+    day VARCHAR(10) NOT NULL,
+    views INT NOT NULL DEFAULT 0,
+    updated_at DATETIME NOT NULL,
+
+    UNIQUE KEY idx_property_view_day (property_id, day),
+    INDEX idx_property_views_day (day)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS property_favorites (
+    property_id VARCHAR(32) PRIMARY KEY,
+    count INT NOT NULL DEFAULT 0,
+    updated_at DATETIME NOT NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
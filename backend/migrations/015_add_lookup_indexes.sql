@@ -0,0 +1,4 @@
+-- Migration: Indexes for GET /api/admin/lookup
+-- Purpose: URL lookups match detail_url by prefix; delete_logs.detail_url had no index
+
+CREATE INDEX IF NOT EXISTS idx_delete_logs_detail_url ON delete_logs(detail_url(255));
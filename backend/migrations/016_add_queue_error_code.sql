@@ -0,0 +1,11 @@
+-- Migration: Add last_error_code to detail_scrape_queue
+-- Purpose: last_error is now sanitized and length-bounded; the short code allows grouping failures
+-- without parsing free text. Existing oversized messages are trimmed to the same bound.
+
+ALTER TABLE detail_scrape_queue
+ADD COLUMN IF NOT EXISTS last_error_code VARCHAR(32) DEFAULT NULL AFTER last_error,
+ADD INDEX IF NOT EXISTS idx_last_error_code (last_error_code);
+
+UPDATE detail_scrape_queue
+SET last_error = CONCAT(LEFT(last_error, 500), '…(truncated)')
+WHERE CHAR_LENGTH(last_error) > 500;
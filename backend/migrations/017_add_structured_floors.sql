@@ -0,0 +1,31 @@
+-- Migration: Structured floors parsed from floor_label
+-- Purpose: floor was taken by a loose regex that often picked the building's total floors
+-- ("地上15階建て/7階部分" -> 15). unit_floor / building_floors are parsed from floor_label on save;
+-- floor stays as an alias of unit_floor. building_floors is filterable (タワー物件).
+
+ALTER TABLE properties
+ADD COLUMN IF NOT EXISTS unit_floor INT DEFAULT NULL AFTER floor,
+ADD COLUMN IF NOT EXISTS building_floors INT DEFAULT NULL AFTER unit_floor,
+ADD INDEX IF NOT EXISTS idx_properties_building_floors (building_floors);
+
+-- Backfill the common "〜階建 / 〜階部分" forms; the rest is filled on the next re-scrape
+UPDATE properties
+SET building_floors = CAST(REGEXP_SUBSTR(REGEXP_SUBSTR(floor_label, '[0-9]+階(地下[0-9]+階)?建'), '[0-9]+') AS UNSIGNED)
+WHERE building_floors IS NULL AND floor_label REGEXP '[0-9]+階(地下[0-9]+階)?建';
+
+UPDATE properties
+SET unit_floor = CASE
+    WHEN floor_label REGEXP '地下[0-9]+階部分' THEN -CAST(REGEXP_SUBSTR(REGEXP_SUBSTR(floor_label, '地下[0-9]+階部分'), '[0-9]+') AS SIGNED)
+    ELSE CAST(REGEXP_SUBSTR(REGEXP_SUBSTR(floor_label, '[0-9]+階部分'), '[0-9]+') AS SIGNED)
+END
+WHERE unit_floor IS NULL AND floor_label REGEXP '[0-9]+階部分';
+
+-- floor follows unit_floor unless it was manually corrected
+UPDATE properties
+SET floor = unit_floor
+WHERE unit_floor IS NOT NULL
+  AND (locked_fields IS NULL OR locked_fields NOT LIKE '%"floor"%');
+
+UPDATE properties
+SET unit_floor = floor
+WHERE unit_floor IS NULL AND floor IS NOT NULL;
@@ -0,0 +1,11 @@
+-- Migration: Create shared_searches table
+-- Purpose: Time-limited share links for filtered result sets (POST /api/share, GET /api/share/:token)
+
+CREATE TABLE IF NOT EXISTS shared_searches (
+    token VARCHAR(32) PRIMARY KEY,
+    params TEXT NOT NULL,
+    expires_at DATETIME NOT NULL,
+    created_at DATETIME NOT NULL,
+
+    INDEX idx_shared_searches_expires_at (expires_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,10 @@
+-- Migration: Create maintenance_state table
+-- Purpose: Persist the read-only maintenance flag (POST /api/admin/maintenance/read-only) across restarts
+-- Single row (id = 1)
+
+CREATE TABLE IF NOT EXISTS maintenance_state (
+    id INT PRIMARY KEY,
+    read_only BOOLEAN NOT NULL DEFAULT FALSE,
+    message VARCHAR(500),
+    updated_at DATETIME NOT NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
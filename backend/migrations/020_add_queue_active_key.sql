@@ -0,0 +1,32 @@
+-- Migration: Add active_key to detail_scrape_queue
+-- Purpose: At most one pending/processing row per listing (source, source_property_id).
+-- Enqueue paths upsert against it and raise the priority instead of inserting a duplicate.
+-- Run in read-only maintenance mode (POST /api/admin/maintenance/read-only) so the worker is paused.
+
+-- 1. Collapse existing duplicates: keep the processing row if any (else the oldest),
+--    carrying over the highest priority, and delete the other active rows
+CREATE TEMPORARY TABLE queue_active_dups AS
+SELECT source,
+       source_property_id,
+       COALESCE(MIN(CASE WHEN status = 'processing' THEN id END), MIN(id)) AS keep_id,
+       MAX(priority) AS max_priority
+FROM detail_scrape_queue
+WHERE status IN ('pending', 'processing')
+GROUP BY source, source_property_id
+HAVING COUNT(*) > 1;
+
+UPDATE detail_scrape_queue q
+JOIN queue_active_dups d ON q.id = d.keep_id
+SET q.priority = d.max_priority;
+
+DELETE q FROM detail_scrape_queue q
+JOIN queue_active_dups d ON q.source = d.source AND q.source_property_id = d.source_property_id
+WHERE q.status IN ('pending', 'processing') AND q.id <> d.keep_id;
+
+DROP TEMPORARY TABLE queue_active_dups;
+
+-- 2. Generated key + unique index (NULLs for done/failed rows don't collide)
+ALTER TABLE detail_scrape_queue
+    ADD COLUMN active_key VARCHAR(306)
+        GENERATED ALWAYS AS (CASE WHEN status IN ('pending', 'processing') THEN CONCAT(source, ':', source_property_id) END) STORED,
+    ADD UNIQUE INDEX uniq_queue_active_key (active_key);
@@ -0,0 +1,24 @@
+-- Migration: Fixed-term lease flag (定期借家) parsed from contract_period / conditions
+-- Purpose: Fixed-term leases are a dealbreaker for many users; stored as a filterable column
+-- and tracked in snapshots. 事務所利用可 / ルームシェア可 / 楽器可 go into facilities.
+
+ALTER TABLE properties
+ADD COLUMN IF NOT EXISTS is_fixed_term_lease BOOLEAN NOT NULL DEFAULT FALSE AFTER no_brokerage_fee,
+ADD COLUMN IF NOT EXISTS lease_term VARCHAR(50) DEFAULT NULL AFTER is_fixed_term_lease,
+ADD INDEX IF NOT EXISTS idx_properties_is_fixed_term_lease (is_fixed_term_lease);
+
+ALTER TABLE property_snapshots
+ADD COLUMN IF NOT EXISTS is_fixed_term_lease BOOLEAN DEFAULT FALSE;
+
+-- Backfill from the stored text; lease_term and condition flags are filled on the next re-scrape
+UPDATE properties
+SET is_fixed_term_lease = TRUE
+WHERE contract_period LIKE '%定期借家%' OR contract_period LIKE '%定借%'
+   OR conditions LIKE '%定期借家%' OR conditions LIKE '%定借%';
+
+-- Carry the backfilled flag into existing snapshots so the next detection run does not
+-- report every fixed-term listing as lease_type_changed
+UPDATE property_snapshots s
+JOIN properties p ON p.id = s.property_id
+SET s.is_fixed_term_lease = p.is_fixed_term_lease
+WHERE p.is_fixed_term_lease = TRUE;
@@ -0,0 +1,15 @@
+-- Migration: Numeric companions for guarantor deposit (保証金) and security deposit (敷引)
+-- Purpose: The scraper now fills management_fee / deposit / key_money / guarantor_deposit /
+-- security_deposit from the detail table. These columns hold the yen amounts computed at save
+-- time (months are converted with the rent; NULL when unparsable), alongside those from 010.
+
+ALTER TABLE properties
+ADD COLUMN IF NOT EXISTS guarantor_deposit_yen INT DEFAULT NULL AFTER key_money_yen,
+ADD COLUMN IF NOT EXISTS security_deposit_yen INT DEFAULT NULL AFTER guarantor_deposit_yen;
+
+ALTER TABLE property_snapshots
+ADD COLUMN IF NOT EXISTS guarantor_deposit_yen INT DEFAULT NULL,
+ADD COLUMN IF NOT EXISTS security_deposit_yen INT DEFAULT NULL;
+
+-- No backfill: the text columns were never populated before this change, so existing rows
+-- pick the values up on their next re-scrape.
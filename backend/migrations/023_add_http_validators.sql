@@ -0,0 +1,10 @@
+-- Migration: HTTP cache validators for conditional detail re-scrapes
+-- Purpose: The ETag / Last-Modified a detail page was served with are sent back as
+-- If-None-Match / If-Modified-Since on the next re-scrape. A 304 lets the queue worker
+-- bump last_seen_at and take a snapshot without fetching and parsing the full page.
+
+ALTER TABLE properties
+ADD COLUMN IF NOT EXISTS etag VARCHAR(255) DEFAULT NULL AFTER locked_fields,
+ADD COLUMN IF NOT EXISTS last_modified VARCHAR(64) DEFAULT NULL AFTER etag;
+
+-- No backfill: validators are recorded on each property's next full detail fetch.
@@ -0,0 +1,14 @@
+-- Migration: Latitude / longitude for properties and snapshots
+-- Purpose: Coordinates from the detail page (embedded JSON, falling back to the map iframe)
+-- for map display and Meilisearch _geo search. Values outside Japan's bounding box are
+-- dropped before saving, so NULL means "unknown".
+
+ALTER TABLE properties
+ADD COLUMN IF NOT EXISTS latitude DECIMAL(9,6) DEFAULT NULL AFTER lease_term,
+ADD COLUMN IF NOT EXISTS longitude DECIMAL(9,6) DEFAULT NULL AFTER latitude;
+
+ALTER TABLE property_snapshots
+ADD COLUMN IF NOT EXISTS latitude DECIMAL(9,6) DEFAULT NULL AFTER status,
+ADD COLUMN IF NOT EXISTS longitude DECIMAL(9,6) DEFAULT NULL AFTER latitude;
+
+-- No backfill: coordinates are recorded on each property's next full detail fetch.
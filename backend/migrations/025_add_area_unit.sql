@@ -0,0 +1,9 @@
+-- Migration: Original unit of properties.area
+-- Purpose: Listings that only state the area in 坪 or 帖/畳 are stored converted to ㎡
+-- (×3.30578 / ×1.62), so the value is an estimate. area_unit records where it came from:
+-- 'sqm' (as listed), 'tsubo' or 'jo' (converted). NULL is a row saved before this column.
+
+ALTER TABLE properties
+ADD COLUMN IF NOT EXISTS area_unit VARCHAR(8) DEFAULT NULL AFTER area;
+
+-- No backfill: the unit is recorded on each property's next full detail fetch.
@@ -0,0 +1,11 @@
+-- Migration: Listing terms on property snapshots
+-- Purpose: 入居可能時期 / 契約期間 / 条件等 / 駐車場 are now read from the detail table and
+-- change between relistings of the same unit, so snapshots keep them for the history view.
+
+ALTER TABLE property_snapshots
+ADD COLUMN IF NOT EXISTS move_in_date VARCHAR(100) DEFAULT NULL AFTER is_fixed_term_lease,
+ADD COLUMN IF NOT EXISTS contract_period VARCHAR(50) DEFAULT NULL AFTER move_in_date,
+ADD COLUMN IF NOT EXISTS conditions VARCHAR(255) DEFAULT NULL AFTER contract_period,
+ADD COLUMN IF NOT EXISTS parking VARCHAR(255) DEFAULT NULL AFTER conditions;
+
+-- No backfill: existing snapshots keep NULL; new snapshots copy the property row.
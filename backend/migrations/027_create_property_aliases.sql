@@ -0,0 +1,16 @@
+-- Migration: Create property_aliases table
+-- Purpose: When a detail URL redirects to a new listing ID (re-published listings), the existing
+-- row is moved to the new ID/URL and the old source_property_id is kept here, so the old ID
+-- still resolves to the property instead of a duplicate row being inserted.
+
+CREATE TABLE IF NOT EXISTS property_aliases (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    source VARCHAR(20) NOT NULL,
+    source_property_id VARCHAR(100) NOT NULL,
+    detail_url VARCHAR(500) NOT NULL,
+    property_id VARCHAR(32) NOT NULL,
+    created_at DATETIME NOT NULL,
+
+    UNIQUE INDEX idx_property_alias_source (source, source_property_id),
+    INDEX idx_property_aliases_property_id (property_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,12 @@
+-- Migration: Bus ride minutes for bus-access listings
+-- Purpose: "バス10分 ○○停 歩2分" is not a walk to the station, so walk_time stays NULL for
+-- listings whose nearest station is only reached by bus and the ride is kept in bus_minutes.
+-- Snapshots keep it too so access changes can be detected.
+
+ALTER TABLE properties
+ADD COLUMN IF NOT EXISTS bus_minutes INT DEFAULT NULL AFTER walk_time;
+
+ALTER TABLE property_snapshots
+ADD COLUMN IF NOT EXISTS bus_minutes INT DEFAULT NULL AFTER walk_time;
+
+-- No backfill: bus_minutes is filled on the next detail scrape.
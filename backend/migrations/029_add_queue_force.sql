@@ -0,0 +1,7 @@
+-- Migration: Force flag on queue rows
+-- Purpose: the queue worker skips items whose listing was fetched less than
+-- scraper.min_refetch_interval_minutes ago (done with last_error_code = 'skipped_fresh');
+-- force = 1 on a row scrapes it anyway.
+
+ALTER TABLE detail_scrape_queue
+ADD COLUMN IF NOT EXISTS force BOOLEAN NOT NULL DEFAULT FALSE AFTER next_retry_at;
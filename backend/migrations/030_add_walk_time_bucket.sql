@@ -0,0 +1,18 @@
+-- Migration: Walk-time bucket derived from walk_time
+-- Purpose: facet / stats counts of listings within 1-5, 6-10, 11-15 and 16+ minutes of the
+-- nearest station. walk_time_bucket is recomputed from walk_time on every save (NULL walk_time
+-- = 'unknown'); snapshots do not store it since it carries no information of its own.
+
+ALTER TABLE properties
+ADD COLUMN IF NOT EXISTS walk_time_bucket VARCHAR(10) NOT NULL DEFAULT 'unknown' AFTER bus_minutes,
+ADD INDEX IF NOT EXISTS idx_properties_walk_time_bucket (walk_time_bucket);
+
+-- Backfill (same boundaries as models.WalkTimeBucket)
+UPDATE properties
+SET walk_time_bucket = CASE
+    WHEN walk_time IS NULL OR walk_time < 0 THEN 'unknown'
+    WHEN walk_time <= 5 THEN '1-5'
+    WHEN walk_time <= 10 THEN '6-10'
+    WHEN walk_time <= 15 THEN '11-15'
+    ELSE '16+'
+END;
@@ -0,0 +1,7 @@
+-- Migration: Referer of queued detail URLs
+-- Purpose: detail pages discovered on a list page are requested with that list page as the
+-- Referer (as a visitor clicking through would). NULL/empty = no Referer (scheduled refreshes,
+-- manual enqueues and rows queued before this column existed).
+
+ALTER TABLE detail_scrape_queue
+ADD COLUMN IF NOT EXISTS referer_url TEXT NULL AFTER detail_url;
@@ -0,0 +1,12 @@
+-- Migration: Light refresh
+-- Purpose: scraper.light_refresh enqueues known listings with light = 1 on its own, more
+-- frequent cadence; the worker then reads only og:title / og:image and the rent. Snapshots
+-- taken from such a refresh have partial = 1 and are only compared on rent and status.
+
+ALTER TABLE detail_scrape_queue
+ADD COLUMN IF NOT EXISTS light BOOLEAN NOT NULL DEFAULT FALSE AFTER force;
+
+ALTER TABLE property_snapshots
+ADD COLUMN IF NOT EXISTS partial BOOLEAN NOT NULL DEFAULT FALSE AFTER manual_fields;
+
+-- No backfill: existing rows are full scrapes.
@@ -0,0 +1,18 @@
+-- Migration: Create scheduler_runs table
+-- Purpose: The daily run crawls scheduler.list_urls to discover new listings before refreshing
+-- known ones. Each run is recorded with how many URLs every list page added, so
+-- GET /api/admin/scraping/status can show the last run.
+
+CREATE TABLE IF NOT EXISTS scheduler_runs (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    `trigger` VARCHAR(20) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    started_at DATETIME NOT NULL,
+    finished_at DATETIME NULL,
+    discovered INT NOT NULL DEFAULT 0,
+    enqueued INT NOT NULL DEFAULT 0,
+    list_urls TEXT,
+    error VARCHAR(500),
+
+    INDEX idx_scheduler_runs_started_at (started_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
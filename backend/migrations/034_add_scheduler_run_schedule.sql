@@ -0,0 +1,6 @@
+-- Migration: Schedule name on scheduler runs
+-- Purpose: scheduler.schedules runs several cron entries with their own list URLs and enqueue
+-- limits; each run records which schedule fired it (empty for daily_run_time and manual runs).
+
+ALTER TABLE scheduler_runs
+ADD COLUMN IF NOT EXISTS schedule VARCHAR(100) NULL AFTER `trigger`;
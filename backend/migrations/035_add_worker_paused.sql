@@ -0,0 +1,8 @@
+-- Migration: Queue worker pause flag
+-- Purpose: POST /api/admin/worker/pause stops the queue worker from claiming items while the
+-- API keeps serving; the flag lives on the scraping_state row so a restart comes back paused.
+
+ALTER TABLE scraping_state
+ADD COLUMN IF NOT EXISTS worker_paused BOOLEAN NOT NULL DEFAULT FALSE AFTER success_count,
+ADD COLUMN IF NOT EXISTS worker_paused_reason VARCHAR(500) NULL AFTER worker_paused,
+ADD COLUMN IF NOT EXISTS worker_paused_at DATETIME NULL AFTER worker_paused_reason;
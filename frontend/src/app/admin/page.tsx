@@ -80,7 +80,8 @@ export default function AdminPage() {
 
     try {
       const response = await fetch(`${API_URL}/api/scheduler/run`, {
-        method: 'POST'
+        method: 'POST',
+        headers: { 'X-Requested-With': 'XMLHttpRequest' }
       })
 
       if (response.ok) {
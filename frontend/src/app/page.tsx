@@ -14,6 +14,7 @@ interface Property {
   floor_plan?: string
   area?: number
   walk_time?: number
+  bus_minutes?: number
   station?: string
   address?: string
   building_age?: number
@@ -1771,6 +1772,9 @@ function PropertyCard({
             {property.station && property.walk_time && (
               <><strong>{property.station}</strong> 徒歩{property.walk_time}分</>
             )}
+            {property.station && !property.walk_time && property.bus_minutes && (
+              <><strong>{property.station}</strong> バス{property.bus_minutes}分</>
+            )}
             {property.address && <> ／ {property.address}</>}
           </div>
         )}
@@ -16,6 +16,7 @@ interface Property {
   floor_plan_details?: string
   area?: number
   walk_time?: number
+  bus_minutes?: number
   station?: string
   address?: string
   building_age?: number
@@ -74,6 +75,7 @@ interface PropertySnapshot {
   floor_plan?: string
   area?: number
   walk_time?: number
+  bus_minutes?: number
   station?: string
   address?: string
   building_age?: number
@@ -642,6 +644,11 @@ export default function PropertyDetailPage() {
                       <dt>最寄駅</dt>
                       <dd>{property.station} 徒歩{property.walk_time}分</dd>
                     </>
+                  ) : property.station && property.bus_minutes ? (
+                    <>
+                      <dt>最寄駅</dt>
+                      <dd>{property.station} バス{property.bus_minutes}分</dd>
+                    </>
                   ) : null}
                   {property.address && (
                     <>
@@ -724,7 +731,7 @@ export default function PropertyDetailPage() {
                   {property.conditions && (
                     <>
                       <dt>入居条件</dt>
-                      <dd>{property.conditions}</dd>
+                      <dd style={{ whiteSpace: 'pre-line' }}>{property.conditions}</dd>
                     </>
                   )}
                 </dl>
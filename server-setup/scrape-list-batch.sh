@@ -85,7 +85,7 @@ for ((page=1; page<=MAX_PAGES; page++)); do
     fi
 
     # 取得した物件URL数を表示
-    PROPERTY_COUNT=$(echo "$BODY" | python3 -c "import sys, json; data=json.load(sys.stdin); print(len(data.get('property_urls', [])))" 2>/dev/null || echo "0")
+    PROPERTY_COUNT=$(echo "$BODY" | python3 -c "import sys, json; data=json.load(sys.stdin); print(len(data.get('results', [])))" 2>/dev/null || echo "0")
     echo "✅ $PROPERTY_COUNT 件の物件URLを取得"
 
     if [ "$PROPERTY_COUNT" = "0" ]; then
@@ -95,7 +95,7 @@ for ((page=1; page<=MAX_PAGES; page++)); do
 
     # 各物件をスクレイピング
     echo "[2/2] 各物件の詳細をスクレイピング..."
-    PROPERTY_URLS=$(echo "$BODY" | python3 -c "import sys, json; data=json.load(sys.stdin); print('\n'.join(data.get('property_urls', [])))" 2>/dev/null)
+    PROPERTY_URLS=$(echo "$BODY" | python3 -c "import sys, json; data=json.load(sys.stdin); print('\n'.join(r['url'] for r in data.get('results', [])))" 2>/dev/null)
 
     PAGE_SCRAPED=0
     PAGE_FAILED=0